@@ -5,7 +5,8 @@ import (
 )
 
 type BlockHeader struct {
-	Number    *big.Int `json:"number"`
-	Hash      string   `json:"hash"`
-	Timestamp uint64   `json:"timestamp"`
+	Number     *big.Int `json:"number"`
+	Hash       string   `json:"hash"`
+	ParentHash string   `json:"parentHash,omitempty"` // optional, lets log-driven trackers detect reorgs without removed logs
+	Timestamp  uint64   `json:"timestamp"`
 }
@@ -0,0 +1,224 @@
+package gasmodel
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+var ErrUnknownPoolType = errors.New("unknown pool type")
+
+// Sample is a recorded swap.
+type Sample struct {
+	TxHash string `json:"txHash,omitempty"`
+	// Pool is the state of the pool right before the swap.
+	Pool     entity.Pool `json:"pool"`
+	TokenIn  string      `json:"tokenIn"`
+	AmountIn *big.Int    `json:"amountIn"`
+	TokenOut string      `json:"tokenOut"`
+	// GasUsed is the gas used by the swap: the gasUsed of the receipt of a transaction doing only this swap, net of
+	// the intrinsic gas and of the overhead of the router it went through.
+	GasUsed int64 `json:"gasUsed"`
+}
+
+// Fit is the model fitted to the samples of a pool type.
+type Fit struct {
+	Model Model
+	// Samples is the number of samples the model is fitted to, Skipped the number of samples the simulator failed on.
+	Samples int
+	Skipped int
+	// RMSE is the root mean square error of the model on the samples, StaticRMSE the one of the simulator's own
+	// estimates.
+	RMSE       float64
+	StaticRMSE float64
+}
+
+// ReadSamples parses JSONL samples. Empty lines are skipped.
+func ReadSamples(r io.Reader) ([]Sample, error) {
+	var samples []Sample
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 1<<20), 1<<28)
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		var sample Sample
+		if err := json.Unmarshal(data, &sample); err != nil {
+			return nil, errors.WithMessagef(err, "line %d", line)
+		}
+		samples = append(samples, sample)
+	}
+	return samples, scanner.Err()
+}
+
+// observation is a sample run through its simulator.
+type observation struct {
+	features  Features
+	gasUsed   float64
+	staticGas float64
+}
+
+// Calibrate runs each sample through the simulator of its pool type and fits a Model per pool type to the gas used.
+// Features come from the SwapInfo of the results when it implements IGasFeatures. Only the pool types registered with
+// RegisterPoolType are fitted, as the others never read their model; their samples are ignored. Samples the simulator
+// fails on are skipped.
+func Calibrate(ctx context.Context, samples []Sample, chainID valueobject.ChainID) (map[string]Fit, error) {
+	observations := make(map[string][]observation)
+	skipped := make(map[string]int)
+	for _, sample := range samples {
+		poolType := sample.Pool.Type
+		factory := pool.Factory(poolType)
+		if factory == nil {
+			return nil, errors.WithMessagef(ErrUnknownPoolType, "%s (tx %s)", poolType, sample.TxHash)
+		}
+		if !IsRegistered(poolType) {
+			continue
+		}
+		poolSim, err := factory(pool.FactoryParams{EntityPool: sample.Pool, ChainID: chainID})
+		if err != nil {
+			skipped[poolType]++
+			continue
+		}
+		res, err := pool.CalcAmountOut(ctx, poolSim, pool.TokenAmount{Token: sample.TokenIn, Amount: sample.AmountIn},
+			sample.TokenOut, nil)
+		if err != nil || !res.IsValid() {
+			skipped[poolType]++
+			continue
+		}
+		var features Features
+		if f, ok := res.SwapInfo.(IGasFeatures); ok {
+			features = f.GasFeatures()
+			features.Hook = strings.ToLower(features.Hook)
+		}
+		observations[poolType] = append(observations[poolType], observation{
+			features:  features,
+			gasUsed:   float64(sample.GasUsed),
+			staticGas: float64(res.Gas),
+		})
+	}
+
+	fits := make(map[string]Fit, len(observations))
+	for poolType, obs := range observations {
+		model := fitModel(obs)
+		var se, staticSe float64
+		for _, o := range obs {
+			se += math.Pow(float64(model.Gas(o.features))-o.gasUsed, 2)
+			staticSe += math.Pow(o.staticGas-o.gasUsed, 2)
+		}
+		fits[poolType] = Fit{
+			Model:      model,
+			Samples:    len(obs),
+			Skipped:    skipped[poolType],
+			RMSE:       math.Sqrt(se / float64(len(obs))),
+			StaticRMSE: math.Sqrt(staticSe / float64(len(obs))),
+		}
+	}
+	return fits, nil
+}
+
+// Models returns the models of fits, to be written by WriteModels.
+func Models(fits map[string]Fit) map[string]Model {
+	models := make(map[string]Model, len(fits))
+	for poolType, fit := range fits {
+		models[poolType] = fit.Model
+	}
+	return models
+}
+
+// fitModel fits gasUsed = Base + PerTickCrossed*ticksCrossed + PerHook[hook] by least squares. Coefficients the
+// observations cannot tell apart from the others (e.g. PerTickCrossed if no swap crosses a tick, or the hook of a pool
+// type whose swaps all call it, which is folded into Base) are set to 0.
+func fitModel(obs []observation) Model {
+	var hooks []string
+	for _, o := range obs {
+		if o.features.Hook != "" && !slices.Contains(hooks, o.features.Hook) {
+			hooks = append(hooks, o.features.Hook)
+		}
+	}
+	slices.Sort(hooks)
+
+	// columns: 1, ticksCrossed, then a 0/1 column per hook
+	n := 2 + len(hooks)
+	row := make([]float64, n)
+	ata := make([][]float64, n)
+	for i := range ata {
+		ata[i] = make([]float64, n+1) // augmented with aty
+	}
+	for _, o := range obs {
+		clear(row)
+		row[0], row[1] = 1, float64(o.features.TicksCrossed)
+		if idx := slices.Index(hooks, o.features.Hook); idx >= 0 {
+			row[2+idx] = 1
+		}
+		for i := range n {
+			for j := range n {
+				ata[i][j] += row[i] * row[j]
+			}
+			ata[i][n] += row[i] * o.gasUsed
+		}
+	}
+
+	coefs := solveLeastSquares(ata)
+	model := Model{Base: int64(math.Round(coefs[0])), PerTickCrossed: int64(math.Round(coefs[1]))}
+	for i, hook := range hooks {
+		if gas := int64(math.Round(coefs[2+i])); gas != 0 {
+			if model.PerHook == nil {
+				model.PerHook = make(map[string]int64, len(hooks))
+			}
+			model.PerHook[hook] = gas
+		}
+	}
+	return model
+}
+
+// solveLeastSquares solves the augmented normal equations m by Gauss-Jordan elimination. The coefficient of a column
+// linearly dependent on the previous ones is set to 0.
+func solveLeastSquares(m [][]float64) []float64 {
+	n := len(m)
+	coefs := make([]float64, n)
+	scale := make([]float64, n)
+	for i := range n {
+		scale[i] = m[i][i]
+	}
+	pivotCol := make([]int, 0, n)
+	for col, r := 0, 0; col < n && r < n; col++ {
+		best := r
+		for i := r + 1; i < n; i++ {
+			if math.Abs(m[i][col]) > math.Abs(m[best][col]) {
+				best = i
+			}
+		}
+		if math.Abs(m[best][col]) <= 1e-9*scale[col] {
+			continue
+		}
+		m[r], m[best] = m[best], m[r]
+		for i := range n {
+			if i == r || m[i][col] == 0 {
+				continue
+			}
+			factor := m[i][col] / m[r][col]
+			for j := col; j <= n; j++ {
+				m[i][j] -= factor * m[r][j]
+			}
+		}
+		pivotCol = append(pivotCol, col)
+		r++
+	}
+	for r, col := range pivotCol {
+		coefs[col] = m[r][n] / m[r][col]
+	}
+	return coefs
+}
@@ -0,0 +1,125 @@
+package gasmodel_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	. "github.com/KyberNetwork/kyberswap-dex-lib/pkg/gasmodel"
+	uniswapv2 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v2"
+	uniswapv3 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v3"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+const (
+	testToken0 = "0x0000000000000000000000000000000000000001"
+	testToken1 = "0x0000000000000000000000000000000000000002"
+)
+
+// newTestV3Pool returns a uniswap v3 pool at tick 0 with 10 nested positions of 1e18 liquidity on [-60k, 60k], so that
+// swaps cross more ticks as they grow.
+func newTestV3Pool() entity.Pool {
+	const tick = `{"index":%d,"liquidityGross":"1000000000000000000","liquidityNet":"%s1000000000000000000"}`
+	var ticks []string
+	for k := 10; k >= 1; k-- {
+		ticks = append(ticks, fmt.Sprintf(tick, -60*k, ""))
+	}
+	for k := 1; k <= 10; k++ {
+		ticks = append(ticks, fmt.Sprintf(tick, 60*k, "-"))
+	}
+	return entity.Pool{
+		Address:  "0x0000000000000000000000000000000000000003",
+		Exchange: "uniswapv3",
+		Type:     uniswapv3.DexTypeUniswapV3,
+		SwapFee:  3000,
+		Reserves: []string{"1000000000000000000000", "1000000000000000000000"},
+		Tokens: []*entity.PoolToken{
+			{Address: testToken0, Swappable: true},
+			{Address: testToken1, Swappable: true},
+		},
+		Extra: `{"liquidity":"10000000000000000000","sqrtPriceX96":"79228162514264337593543950336",` +
+			`"tickSpacing":60,"tick":0,"ticks":[` + strings.Join(ticks, ",") + `]}`,
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	want := Model{Base: 90000, PerTickCrossed: 25000}
+	p := newTestV3Pool()
+	poolSim, err := uniswapv3.NewPoolSimulator(p, valueobject.ChainIDEthereum)
+	require.NoError(t, err)
+
+	var jsonl bytes.Buffer
+	crossed := map[int]bool{}
+	for _, amount := range []int64{1e15, 1e16, 3e16, 6e16, 1e17, 1.4e17} {
+		for _, tokenIn := range []string{testToken0, testToken1} {
+			tokenOut := testToken1
+			if tokenIn == testToken1 {
+				tokenOut = testToken0
+			}
+			res, err := pool.CalcAmountOut(ctx, poolSim, pool.TokenAmount{Token: tokenIn, Amount: big.NewInt(amount)},
+				tokenOut, nil)
+			require.NoError(t, err)
+			features := res.SwapInfo.(IGasFeatures).GasFeatures()
+			crossed[features.TicksCrossed] = true
+			data, err := json.Marshal(Sample{
+				Pool:     p,
+				TokenIn:  tokenIn,
+				AmountIn: big.NewInt(amount),
+				TokenOut: tokenOut,
+				GasUsed:  want.Gas(features),
+			})
+			require.NoError(t, err)
+			jsonl.Write(append(data, '\n'))
+		}
+	}
+	require.Greater(t, len(crossed), 2)
+
+	samples, err := ReadSamples(&jsonl)
+	require.NoError(t, err)
+	// a sample the simulator fails on
+	samples = append(samples, Sample{Pool: p, TokenIn: testToken0, AmountIn: big.NewInt(1), TokenOut: testToken0})
+
+	fits, err := Calibrate(ctx, samples, valueobject.ChainIDEthereum)
+	require.NoError(t, err)
+	require.Contains(t, fits, uniswapv3.DexTypeUniswapV3)
+	fit := fits[uniswapv3.DexTypeUniswapV3]
+	assert.Equal(t, want, fit.Model)
+	assert.Equal(t, len(samples)-1, fit.Samples)
+	assert.Equal(t, 1, fit.Skipped)
+	assert.Zero(t, fit.RMSE)
+	assert.Positive(t, fit.StaticRMSE)
+	assert.Equal(t, map[string]Model{uniswapv3.DexTypeUniswapV3: want}, Models(fits))
+
+	// uniswap v2 simulators do not read a gas model, so their samples are not fitted
+	v2Pool := entity.Pool{
+		Address:  "0x0000000000000000000000000000000000000004",
+		Exchange: "uniswap",
+		Type:     uniswapv2.DexType,
+		Reserves: []string{"1000000000000000000000", "1000000000000000000000"},
+		Tokens: []*entity.PoolToken{
+			{Address: testToken0, Swappable: true},
+			{Address: testToken1, Swappable: true},
+		},
+		Extra: `{"fee":3,"feePrecision":1000}`,
+	}
+	fits, err = Calibrate(ctx, append(samples, Sample{Pool: v2Pool, TokenIn: testToken0, AmountIn: big.NewInt(1e15),
+		TokenOut: testToken1, GasUsed: 60000}), valueobject.ChainIDEthereum)
+	require.NoError(t, err)
+	assert.NotContains(t, fits, uniswapv2.DexType)
+	assert.Contains(t, fits, uniswapv3.DexTypeUniswapV3)
+
+	p.Type = "unknown"
+	_, err = Calibrate(ctx, []Sample{{Pool: p}}, valueobject.ChainIDEthereum)
+	assert.ErrorIs(t, err, ErrUnknownPoolType)
+}
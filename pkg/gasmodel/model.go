@@ -0,0 +1,131 @@
+// Package gasmodel calibrates the gas estimates of pool simulators against the gas actually used by recorded swaps.
+//
+// A Model prices a swap from the features it reports through its SwapInfo: a base cost, a cost per initialized tick
+// crossed and a cost per hook. Calibrate fits one Model per pool type from recorded swaps, and WriteModels emits the
+// fitted models as the models.json data file embedded in this package, which simulators read at init through Lookup.
+// It ships empty until it is generated from recorded swaps, so simulators keep their default gas meanwhile.
+// Only the pool types registered with RegisterPoolType read their model, so only those are calibrated; the others
+// keep their default gas.
+package gasmodel
+
+import (
+	_ "embed"
+	"io"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+)
+
+var ErrDuplicateHook = errors.New("duplicate hook")
+
+// Features are the characteristics of a swap its gas cost depends on.
+type Features struct {
+	// TicksCrossed is the number of initialized ticks the swap crosses.
+	TicksCrossed int
+	// Hook is the lowercase address of the hook the swap calls, if any.
+	Hook string
+}
+
+// IGasFeatures is implemented by the SwapInfo of simulators whose swaps do not all cost the same gas.
+type IGasFeatures interface {
+	GasFeatures() Features
+}
+
+// Model is a linear gas model: a swap costs Base, plus PerTickCrossed per initialized tick crossed, plus PerHook of the
+// hook it calls.
+type Model struct {
+	Base           int64            `json:"base"`
+	PerTickCrossed int64            `json:"perTickCrossed,omitempty"`
+	PerHook        map[string]int64 `json:"perHook,omitempty"`
+}
+
+// Gas returns the gas cost of a swap with features f.
+func (m Model) Gas(f Features) int64 {
+	return m.Base + m.PerTickCrossed*int64(f.TicksCrossed) + m.PerHook[f.Hook]
+}
+
+// HookGas returns the calibrated gas cost of calling hook.
+func (m Model) HookGas(hook string) (int64, bool) {
+	gas, ok := m.PerHook[strings.ToLower(hook)]
+	return gas, ok
+}
+
+//go:embed models.json
+var modelsJSON []byte
+
+// models are the calibrated models by pool type.
+var models = mustParseModels(modelsJSON)
+
+// poolTypes are the pool types whose simulators read their model. don't modify
+var poolTypes = make(map[string]struct{}, 8)
+
+// RegisterPoolType registers a pool type whose simulators read its model through Lookup, for Calibrate to fit.
+func RegisterPoolType(poolType string) bool {
+	poolTypes[poolType] = struct{}{}
+	return true
+}
+
+// IsRegistered returns whether the simulators of poolType read its model.
+func IsRegistered(poolType string) bool {
+	_, ok := poolTypes[poolType]
+	return ok
+}
+
+// Lookup returns the calibrated model of poolType.
+func Lookup(poolType string) (Model, bool) {
+	m, ok := models[poolType]
+	return m, ok
+}
+
+// ParseModels parses models by pool type, as written by WriteModels. Hook addresses are lowercased, and a model with
+// two of them differing only in case is rejected.
+func ParseModels(data []byte) (map[string]Model, error) {
+	var m map[string]Model
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	for poolType, model := range m {
+		perHook, dup := lowerKeys(model.PerHook)
+		if dup != "" {
+			return nil, errors.WithMessagef(ErrDuplicateHook, "%s of pool type %s", dup, poolType)
+		}
+		model.PerHook = perHook
+		m[poolType] = model
+	}
+	return m, nil
+}
+
+// WriteModels writes models by pool type as indented JSON, for ParseModels to read back.
+func WriteModels(w io.Writer, models map[string]Model) error {
+	data, err := json.MarshalIndent(models, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+func mustParseModels(data []byte) map[string]Model {
+	m, err := ParseModels(data)
+	if err != nil {
+		panic("invalid gasmodel/models.json: " + err.Error())
+	}
+	return m
+}
+
+// lowerKeys returns m with lowercase keys, or the first key two keys of m collide into.
+func lowerKeys(m map[string]int64) (map[string]int64, string) {
+	if m == nil {
+		return nil, ""
+	}
+	lowered := make(map[string]int64, len(m))
+	for k, v := range m {
+		lower := strings.ToLower(k)
+		if _, ok := lowered[lower]; ok {
+			return nil, lower
+		}
+		lowered[lower] = v
+	}
+	return lowered, ""
+}
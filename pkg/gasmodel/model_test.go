@@ -0,0 +1,64 @@
+package gasmodel
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testHookA = "0x00000000000000000000000000000000000000a0"
+	testHookB = "0x00000000000000000000000000000000000000b0"
+)
+
+func TestFitModel(t *testing.T) {
+	t.Parallel()
+	want := Model{Base: 120000, PerTickCrossed: 18000, PerHook: map[string]int64{testHookA: 30000, testHookB: 75000}}
+	var obs []observation
+	for ticks := range 5 {
+		for _, hook := range []string{"", testHookA, testHookB} {
+			features := Features{TicksCrossed: ticks, Hook: hook}
+			for _, noise := range []float64{-50, 50} {
+				obs = append(obs, observation{features: features, gasUsed: float64(want.Gas(features)) + noise})
+			}
+		}
+	}
+	assert.Equal(t, want, fitModel(obs))
+
+	// without tick crossings nor hook-less swaps, PerTickCrossed and the hook cost cannot be told apart from Base
+	obs = obs[:0]
+	for _, gasUsed := range []float64{99000, 101000} {
+		obs = append(obs, observation{features: Features{Hook: testHookA}, gasUsed: gasUsed})
+	}
+	assert.Equal(t, Model{Base: 100000}, fitModel(obs))
+}
+
+func TestModels(t *testing.T) {
+	t.Parallel()
+	m := Model{Base: 100, PerTickCrossed: 10, PerHook: map[string]int64{testHookA: 5}}
+	assert.Equal(t, int64(100), m.Gas(Features{}))
+	assert.Equal(t, int64(125), m.Gas(Features{TicksCrossed: 2, Hook: testHookA}))
+	gas, ok := m.HookGas("0x00000000000000000000000000000000000000A0")
+	assert.True(t, ok)
+	assert.Equal(t, int64(5), gas)
+
+	models := map[string]Model{"a": m, "b": {Base: 1}}
+	var buf bytes.Buffer
+	require.NoError(t, WriteModels(&buf, models))
+	parsed, err := ParseModels(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, models, parsed)
+
+	parsed, err = ParseModels([]byte(`{"a":{"base":1,"perHook":{"0x00000000000000000000000000000000000000A0":2}}}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{testHookA: 2}, parsed["a"].PerHook)
+	_, err = ParseModels([]byte(`{"a":{"base":1,"perHook":{"0x00000000000000000000000000000000000000A0":2,` +
+		`"0x00000000000000000000000000000000000000a0":3}}}`))
+	assert.ErrorIs(t, err, ErrDuplicateHook)
+
+	// the embedded models parse, and none is calibrated yet
+	_, ok = Lookup("uniswapv3")
+	assert.False(t, ok)
+}
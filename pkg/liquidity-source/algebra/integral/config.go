@@ -1,6 +1,10 @@
 package integral
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/reorg"
+)
 
 type Config struct {
 	DexID              string
@@ -12,4 +16,6 @@ type Config struct {
 	TickLensAddress   string
 
 	UseBasePluginV2 bool `json:"useBasePluginV2"`
+
+	Reorg reorg.Config `json:"reorg"`
 }
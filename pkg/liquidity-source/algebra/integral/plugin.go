@@ -456,8 +456,11 @@ func (p *PoolSimulator) calculateFeeFactors(currentTick, lastTick int32, priceCh
 	factor.SetUint64(uint64(priceChangeFactor))
 	feeFactorImpact := priceChangeRatio.Mul(priceChangeRatio, &factor).Div(priceChangeRatio, FACTOR_DENOMINATOR)
 
-	feeFactors := p.slidingFee
-	newZeroToOneFeeFactor := feeFactors.ZeroToOneFeeFactor.Sub(feeFactors.ZeroToOneFeeFactor, feeFactorImpact)
+	// copy the factors as clones of the simulator share them
+	feeFactors := *p.slidingFee
+	feeFactors.ZeroToOneFeeFactor = p.slidingFee.ZeroToOneFeeFactor.Clone()
+	feeFactors.OneToZeroFeeFactor = p.slidingFee.OneToZeroFeeFactor.Clone()
+	newZeroToOneFeeFactor := new(uint256.Int).Sub(feeFactors.ZeroToOneFeeFactor, feeFactorImpact)
 	if 0 < newZeroToOneFeeFactor.Sign() && newZeroToOneFeeFactor.Cmp(DOUBLE_FEE_MULTIPLIER) < 0 {
 		feeFactors.ZeroToOneFeeFactor = newZeroToOneFeeFactor
 		feeFactors.OneToZeroFeeFactor.Add(feeFactors.OneToZeroFeeFactor, feeFactorImpact)
@@ -468,7 +471,7 @@ func (p *PoolSimulator) calculateFeeFactors(currentTick, lastTick int32, priceCh
 		feeFactors.ZeroToOneFeeFactor.Set(DOUBLE_FEE_MULTIPLIER)
 		feeFactors.OneToZeroFeeFactor.Clear()
 	}
-	return feeFactors, nil
+	return &feeFactors, nil
 }
 
 func getInputTokenDelta01(to, from, liquidity *uint256.Int) (*uint256.Int, error) {
@@ -0,0 +1,39 @@
+package integral
+
+import (
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpXg4(t *testing.T) {
+	t.Parallel()
+	closestValue := CLOSEST_VALUE_0.Clone()
+
+	// x/g >= 0.5 scales the closest value by e^0.5, which must not write into the shared constant
+	first := expXg4(uint256.NewInt(60), 100)
+	second := expXg4(uint256.NewInt(60), 100)
+	assert.Equal(t, first, second)
+	assert.Equal(t, closestValue, CLOSEST_VALUE_0)
+}
+
+func TestPoolSimulator_calculateFeeFactors(t *testing.T) {
+	t.Parallel()
+	p := &PoolSimulator{slidingFee: &SlidingFeeConfig{
+		ZeroToOneFeeFactor: FEE_FACTOR_MULTIPLIER.Clone(),
+		OneToZeroFeeFactor: FEE_FACTOR_MULTIPLIER.Clone(),
+		PriceChangeFactor:  1000,
+		BaseFee:            500,
+	}}
+
+	feeFactors, err := p.calculateFeeFactors(100, 0, p.slidingFee.PriceChangeFactor)
+	require.NoError(t, err)
+	assert.True(t, feeFactors.ZeroToOneFeeFactor.Lt(FEE_FACTOR_MULTIPLIER))
+	assert.True(t, feeFactors.OneToZeroFeeFactor.Gt(FEE_FACTOR_MULTIPLIER))
+
+	// the simulator's factors are shared with its clones and only replaced once a swap is applied
+	assert.Equal(t, FEE_FACTOR_MULTIPLIER, p.slidingFee.ZeroToOneFeeFactor)
+	assert.Equal(t, FEE_FACTOR_MULTIPLIER, p.slidingFee.OneToZeroFeeFactor)
+}
@@ -204,6 +204,38 @@ func (p *PoolSimulator) swap(tokenIn, tokenOut string, amtRequired *uint256.Int)
 	}, nil
 }
 
+// GetSpotPrice returns the price at the current sqrt price, net of the fee the plugin would charge for the swap.
+func (p *PoolSimulator) GetSpotPrice(tokenIn, tokenOut string) (float64, error) {
+	if !p.globalState.Unlocked {
+		return 0, ErrPoolLocked
+	}
+	tokenInIndex, tokenOutIndex := p.GetTokenIndex(tokenIn), p.GetTokenIndex(tokenOut)
+	if tokenInIndex < 0 || tokenOutIndex < 0 {
+		return 0, ErrInvalidToken
+	} else if p.liquidity.IsZero() {
+		return 0, pool.ErrSpotPriceUnsupported
+	}
+
+	zeroForOne := tokenInIndex == 0
+	cloned := p.CloneState().(*PoolSimulator) // the plugin updates the fee state before a swap
+	overrideFee, pluginFee, err := lo.Ternary(cloned.useBasePluginV2 && cloned.slidingFee.FeeType,
+		cloned.beforeSwapV2, cloned.beforeSwapV1)(zeroForOne)
+	if err != nil {
+		return 0, err
+	}
+	fee := lo.Ternary(overrideFee != 0, overrideFee, uint32(cloned.globalState.LastFee)) + pluginFee
+	if fee >= 1e6 {
+		return 0, ErrIncorrectPluginFee
+	}
+
+	sqrtPrice := math.Ldexp(p.globalState.Price.Float64(), -96)
+	price := sqrtPrice * sqrtPrice
+	if !zeroForOne {
+		price = 1 / price
+	}
+	return price * (1 - float64(fee)/1e6), nil
+}
+
 func (p *PoolSimulator) CloneState() pool.IPoolSimulator {
 	cloned := *p
 	cloned.liquidity = p.liquidity.Clone()
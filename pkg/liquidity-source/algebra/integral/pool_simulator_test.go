@@ -518,3 +518,8 @@ func TestPoolSimulator_CalcAmountIn(t *testing.T) {
 	t.Parallel()
 	testutil.TestCalcAmountIn(t, ps)
 }
+
+func TestPoolSimulator_GetSpotPrice(t *testing.T) {
+	t.Parallel()
+	testutil.TestSpotPrice(t, lo.Must(NewPoolSimulator(thenaEp)), 1)
+}
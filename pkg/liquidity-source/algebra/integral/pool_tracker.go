@@ -24,6 +24,7 @@ import (
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/algebra"
 	tickspkg "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v3/ticks"
 	sourcePool "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/reorg"
 	pooltrack "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/tracker"
 	abipkg "github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/abi"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/eth"
@@ -38,6 +39,7 @@ type PoolTracker struct {
 	algebra.PoolTracker[Timepoint, TimepointRPC]
 	config        *Config
 	graphqlClient *graphqlpkg.Client
+	reorgGuard    *reorg.Guard
 }
 
 func NewPoolTracker(
@@ -49,6 +51,7 @@ func NewPoolTracker(
 		PoolTracker:   algebra.PoolTracker[Timepoint, TimepointRPC]{EthrpcClient: ethrpcClient},
 		config:        cfg,
 		graphqlClient: graphqlClient,
+		reorgGuard:    reorg.NewGuard(cfg.Reorg),
 	}
 }
 
@@ -478,6 +481,10 @@ func (d *PoolTracker) getPoolTicks(ctx context.Context, poolAddress string) ([]T
 }
 
 func (t *PoolTracker) GetNewPoolState(ctx context.Context, p entity.Pool, param sourcePool.GetNewPoolStateParams) (entity.Pool, error) {
+	return t.reorgGuard.GetNewPoolState(ctx, p, param, t.applyLogs, t.BootstrapPoolState)
+}
+
+func (t *PoolTracker) applyLogs(ctx context.Context, p entity.Pool, param sourcePool.GetNewPoolStateParams) (entity.Pool, error) {
 	return t.getNewPoolState(ctx, p, param.Logs, param.BlockHeaders, nil)
 }
 
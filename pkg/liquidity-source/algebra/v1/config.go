@@ -1,6 +1,10 @@
 package algebrav1
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/reorg"
+)
 
 type Config struct {
 	DexID              string
@@ -12,4 +16,6 @@ type Config struct {
 
 	AlwaysUseTickLens bool
 	TickLensAddress   string
+
+	Reorg reorg.Config `json:"reorg"`
 }
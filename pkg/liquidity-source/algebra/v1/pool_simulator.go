@@ -2,6 +2,7 @@ package algebrav1
 
 import (
 	"fmt"
+	"math"
 	"math/big"
 	"strings"
 
@@ -163,6 +164,23 @@ func (p *PoolSimulator) CalcAmountOut(param pool.CalcAmountOutParams) (*pool.Cal
 	}, nil
 }
 
+// GetSpotPrice returns the price at the current sqrt price, net of the directional fee.
+func (p *PoolSimulator) GetSpotPrice(tokenIn, tokenOut string) (float64, error) {
+	tokenInIndex, tokenOutIndex := p.GetTokenIndex(tokenIn), p.GetTokenIndex(tokenOut)
+	if tokenInIndex < 0 || tokenOutIndex < 0 || tokenInIndex == tokenOutIndex {
+		return 0, ErrInvalidToken
+	} else if p.liquidity.IsZero() {
+		return 0, pool.ErrSpotPriceUnsupported
+	}
+
+	sqrtPrice := math.Ldexp(p.globalState.Price.Float64(), -96)
+	price, fee := sqrtPrice*sqrtPrice, p.globalState.FeeZto // token1 per token0
+	if tokenInIndex == 1 {
+		price, fee = 1/price, p.globalState.FeeOtz
+	}
+	return price * (1 - float64(fee)/1e6), nil
+}
+
 func (p *PoolSimulator) CloneState() pool.IPoolSimulator {
 	cloned := *p
 	cloned.liquidity = p.liquidity.Clone()
@@ -58,6 +58,18 @@ func TestPoolSimulator_CalcAmountOut(t *testing.T) {
 	}
 }
 
+func TestPoolSimulator_GetSpotPrice(t *testing.T) {
+	t.Parallel()
+	p, err := NewPoolSimulator(entity.Pool{
+		Reserves: entity.PoolReserves{"21265875874493991905878", "10344609910613908943698"},
+		Tokens:   []*entity.PoolToken{{Address: "A"}, {Address: "B"}},
+		Extra:    `{"liquidity":299344339249801237803452,"globalState":{"price":50556054571765543459252266509,"tick":-8986,"feeZto":7550,"feeOtz":7550,"timepoint_index":4,"community_fee_token0":0,"community_fee_token1":0,"unlocked":true},"ticks":[{"Index":-23040,"LiquidityGross":18101291400643986804037,"LiquidityNet":18101291400643986804037},{"Index":-9495,"LiquidityGross":281243047849157250999415,"LiquidityNet":281243047849157250999415},{"Index":-8940,"LiquidityGross":281243047849157250999415,"LiquidityNet":-281243047849157250999415},{"Index":16080,"LiquidityGross":18101291400643986804037,"LiquidityNet":-18101291400643986804037}],"tickSpacing":5}`,
+	})
+	require.NoError(t, err)
+
+	testutil.TestSpotPrice(t, p, 1)
+}
+
 func TestPoolSimulator_UpdateBalance(t *testing.T) {
 	t.Parallel()
 	_ = logger.SetLogLevel("debug")
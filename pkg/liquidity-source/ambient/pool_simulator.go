@@ -1,6 +1,7 @@
 package ambient
 
 import (
+	"math"
 	"math/big"
 	"slices"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"github.com/samber/lo"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v3/ticks"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
@@ -158,6 +160,36 @@ func (p *PoolSimulator) matchDirection(tokenIn, tokenOut string) (inBaseQty, ok
 	return iIn == 0, true
 }
 
+// GetTickDepth returns the liquidity of each range between the tracked active ticks, where crossing a tick upward adds
+// its bid liquidity and removes its ask liquidity. Ranges include the ambient liquidity, which also spans the prices
+// beyond the outermost active ticks.
+func (p *PoolSimulator) GetTickDepth() []pool.TickDepth {
+	levels := make(map[int32]BookLevel, len(p.state.Levels))
+	for _, level := range p.state.Levels {
+		levels[level.Tick] = level.Level
+	}
+	activeTicks := slices.Sorted(slices.Values(p.state.ActiveTicks))
+	bookTicks := make([]ticks.Tick, 0, len(activeTicks))
+	for _, tick := range activeTicks {
+		level := levels[tick]
+		var bidLiq, askLiq uint256.Int
+		LotsToLiquidity(&bidLiq, &level.BidLots)
+		LotsToLiquidity(&askLiq, &level.AskLots)
+		gross := new(big.Int).Add(bidLiq.ToBig(), askLiq.ToBig())
+		bookTicks = append(bookTicks, ticks.Tick{
+			TickIdx:        int(tick),
+			LiquidityGross: gross,
+			LiquidityNet:   new(big.Int).Sub(bidLiq.ToBig(), askLiq.ToBig()),
+		})
+	}
+
+	curve := &p.state.Curve
+	var liquidity uint256.Int
+	ActiveLiquidity(&liquidity, curve)
+	sqrtPrice := math.Ldexp(curve.PriceRoot.Float64(), -64)
+	return ticks.Depth(sqrtPrice, int(GetTickAtSqrtRatio(curve.PriceRoot)), liquidity.ToBig(), bookTicks)
+}
+
 func (p *PoolSimulator) CloneState() pool.IPoolSimulator {
 	cloned := *p
 	cloned.Info.Reserves = slices.Clone(p.Info.Reserves)
@@ -1,9 +1,11 @@
 package ambient
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/goccy/go-json"
+	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/require"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
@@ -123,3 +125,37 @@ func TestCalcAmountOut_WETH_PEPE_ConcurrentSafe(t *testing.T) {
 	})
 	require.NoError(t, err)
 }
+
+func TestGetTickDepth_WETH_PEPE(t *testing.T) {
+	t.Parallel()
+
+	var ep entity.Pool
+	require.NoError(t, json.Unmarshal([]byte(pepePepePoolJSON), &ep))
+	sim, err := NewPoolSimulator(ep)
+	require.NoError(t, err)
+
+	var ambientLiq uint256.Int
+	ActiveLiquidity(&ambientLiq, &sim.state.Curve)
+	tickCurrent := int(GetTickAtSqrtRatio(sim.state.Curve.PriceRoot))
+
+	depth := sim.GetTickDepth()
+	require.Len(t, depth, len(sim.state.ActiveTicks)-1)
+	for i, d := range depth {
+		require.Equal(t, int(sim.state.ActiveTicks[i]), d.TickLower)
+		require.Equal(t, int(sim.state.ActiveTicks[i+1]), d.TickUpper)
+		// ConcLiq is 0: the concentrated positions are all out of range
+		require.GreaterOrEqual(t, d.Liquidity.Cmp(ambientLiq.ToBig()), 0)
+		if d.TickUpper <= tickCurrent {
+			require.Zero(t, d.Amount0)
+			require.Positive(t, d.Amount1)
+		} else if d.TickLower > tickCurrent {
+			require.Positive(t, d.Amount0)
+			require.Zero(t, d.Amount1)
+		}
+	}
+
+	// the [-196944, -194736] position adds its liquidity on top of the ambient one
+	var posLiq uint256.Int
+	LotsToLiquidity(&posLiq, uint256.NewInt(325710782961597566))
+	require.Equal(t, new(big.Int).Add(ambientLiq.ToBig(), posLiq.ToBig()), depth[len(depth)-1].Liquidity)
+}
@@ -10,12 +10,14 @@ import (
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/math"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/shared"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/vault"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 )
 
 type PoolSimulator struct {
 	pool.Pool
+	basePools map[string]shared.IBasePool
 
 	paused                 bool
 	canNotUpdateTokenRates bool
@@ -26,13 +28,15 @@ type PoolSimulator struct {
 	vault       string
 	poolID      string
 	poolTypeVer int
+
+	batchSwapEnabled bool
 }
 
 var _ shared.IBasePool = (*PoolSimulator)(nil)
 
-var _ = pool.RegisterFactory0(DexType, NewPoolSimulator)
+var _ = pool.RegisterFactoryMeta(DexType, NewPoolSimulator)
 
-func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
+func NewPoolSimulator(entityPool entity.Pool, basePoolMap map[string]pool.IPoolSimulator) (*PoolSimulator, error) {
 	var (
 		extra       Extra
 		staticExtra StaticExtra
@@ -49,6 +53,15 @@ func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
 		return nil, err
 	}
 
+	var basePools = make(map[string]shared.IBasePool, len(staticExtra.BasePools))
+	if basePoolMap != nil {
+		for basePool := range staticExtra.BasePools {
+			if p, ok := basePoolMap[basePool].(shared.IBasePool); ok {
+				basePools[basePool] = p
+			}
+		}
+	}
+
 	for idx := 0; idx < len(entityPool.Tokens); idx++ {
 		tokens[idx] = entityPool.Tokens[idx].Address
 		reserves[idx] = bignumber.NewBig10(entityPool.Reserves[idx])
@@ -93,6 +106,7 @@ func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
 
 	return &PoolSimulator{
 		Pool:                   pool,
+		basePools:              basePools,
 		paused:                 extra.Paused,
 		canNotUpdateTokenRates: extra.CanNotUpdateTokenRates,
 		regularSimulator:       &regularSimulator,
@@ -100,6 +114,7 @@ func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
 		vault:                  staticExtra.Vault,
 		poolID:                 staticExtra.PoolID,
 		poolTypeVer:            staticExtra.PoolTypeVer,
+		batchSwapEnabled:       staticExtra.BatchSwapEnabled,
 	}, nil
 }
 
@@ -164,15 +179,19 @@ func (s *PoolSimulator) CalcAmountOut(params pool.CalcAmountOutParams) (*pool.Ca
 	tokenAmountIn := params.TokenAmountIn
 	tokenOut := params.TokenOut
 
+	amountIn, overflow := uint256.FromBig(tokenAmountIn.Amount)
+	if overflow {
+		return nil, ErrOverflow
+	}
+
 	indexIn := s.GetTokenIndex(tokenAmountIn.Token)
 	indexOut := s.GetTokenIndex(tokenOut)
 	if indexIn < 0 || indexOut < 0 {
-		return nil, ErrUnknownToken
-	}
+		if !s.batchSwapEnabled || len(s.basePools) == 0 {
+			return nil, ErrUnknownToken
+		}
 
-	amountIn, overflow := uint256.FromBig(tokenAmountIn.Amount)
-	if overflow {
-		return nil, ErrOverflow
+		return s.swapBatch(tokenAmountIn.Token, tokenOut, amountIn)
 	}
 
 	balances := make([]*uint256.Int, len(s.Info.Reserves))
@@ -210,6 +229,30 @@ func (s *PoolSimulator) CalcAmountOut(params pool.CalcAmountOutParams) (*pool.Ca
 	}, nil
 }
 
+// swapBatch swaps through the base pools, which hold the tokens this pool only has the BPT of.
+func (s *PoolSimulator) swapBatch(tokenIn, tokenOut string, amountIn *uint256.Int) (*pool.CalcAmountOutResult, error) {
+	amountOut, hops, err := vault.BatchSwap(s, s.basePools, tokenIn, tokenOut, amountIn)
+	if err != nil {
+		return nil, err
+	}
+
+	estimatedGas := int64(0)
+	for _, hop := range hops {
+		if hop.JoinExitIndex != nil {
+			estimatedGas += shared.JoinExitGasUsage
+		} else {
+			estimatedGas += DefaultGas.Swap
+		}
+	}
+
+	return &pool.CalcAmountOutResult{
+		TokenAmountOut: &pool.TokenAmount{Token: tokenOut, Amount: amountOut.ToBig()},
+		Fee:            &pool.TokenAmount{Token: tokenOut, Amount: bignumber.ZeroBI},
+		Gas:            estimatedGas,
+		SwapInfo:       shared.SwapInfo{Hops: hops},
+	}, nil
+}
+
 func (s *PoolSimulator) CalcAmountIn(params pool.CalcAmountInParams) (*pool.CalcAmountInResult, error) {
 	if s.paused {
 		return nil, ErrPoolPaused
@@ -283,6 +326,11 @@ func (s *PoolSimulator) GetApprovalAddress(_, _ string) string {
 }
 
 func (s *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	if swapInfo, ok := params.SwapInfo.(shared.SwapInfo); ok && len(swapInfo.Hops) > 0 {
+		vault.UpdateBalance(s.basePools, swapInfo.Hops, s.updateBalance)
+		return
+	}
+
 	if params.TokenAmountIn.Token == s.Info.Address || params.TokenAmountOut.Token == s.Info.Address {
 		s.bptSimulator.updateBalance(params)
 		return
@@ -291,6 +339,38 @@ func (s *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
 	s.regularSimulator.updateBalance(params)
 }
 
+func (s *PoolSimulator) updateBalance(tokenIn, tokenOut string, amountIn, amountOut *big.Int) {
+	s.UpdateBalance(pool.UpdateBalanceParams{
+		TokenAmountIn:  pool.TokenAmount{Token: tokenIn, Amount: amountIn},
+		TokenAmountOut: pool.TokenAmount{Token: tokenOut, Amount: amountOut},
+	})
+}
+
+func (s *PoolSimulator) GetTokens() []string {
+	return vault.GetTokens(s.Info.Tokens, s.basePools)
+}
+
+func (s *PoolSimulator) CanSwapFrom(address string) []string { return s.CanSwapTo(address) }
+
+func (s *PoolSimulator) CanSwapTo(address string) []string {
+	return vault.CanSwapTo(s.Info.Tokens, s.basePools, address)
+}
+
+func (s *PoolSimulator) GetBasePools() []pool.IPoolSimulator {
+	var result = make([]pool.IPoolSimulator, 0, len(s.basePools))
+	for _, basePool := range s.basePools {
+		result = append(result, basePool)
+	}
+
+	return result
+}
+
+func (s *PoolSimulator) SetBasePool(basePool pool.IPoolSimulator) {
+	if basePool, ok := basePool.(shared.IBasePool); ok {
+		s.basePools[basePool.GetAddress()] = basePool
+	}
+}
+
 // https://etherscan.io/address/0x2ba7aa2213fa2c909cd9e46fed5a0059542b36b0#code#F22#L696
 /**
  * @dev Reverses the `scalingFactor` applied to `amount`, resulting in a smaller or equal value depending on
@@ -0,0 +1,97 @@
+package composablestable
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/linear"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/shared"
+	poolpkg "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+// a boosted pool: composable stable over two linear pools, one wrapping a 6-decimals token and one an 18-decimals one,
+// both with a BPT rate of 1
+const (
+	usdc      = "0x1111111111111111111111111111111111111111"
+	bbUsdc    = "0x3333333333333333333333333333333333333333"
+	dai       = "0x4444444444444444444444444444444444444444"
+	bbDai     = "0x6666666666666666666666666666666666666666"
+	bbUsd     = "0x9999999999999999999999999999999999999999"
+	bbUsdcStr = `{"address":"0x3333333333333333333333333333333333333333","exchange":"balancer-v2-linear","type":"balancer-v2-linear","reserves":["2000000000000","3000000000000","5192296853384827628530496329220095"],"tokens":[{"address":"0x1111111111111111111111111111111111111111","swappable":true},{"address":"0x2222222222222222222222222222222222222222","swappable":true},{"address":"0x3333333333333333333333333333333333333333","swappable":true}],"extra":"{\"swapFeePercentage\":\"100000000000000\",\"scalingFactors\":[\"1000000000000000000000000000000\",\"1050000000000000000000000000000\",\"1000000000000000000\"],\"lowerTarget\":\"1000000000000000000000000\",\"upperTarget\":\"4000000000000000000000000\",\"totalSupply\":\"5192296858534827628530496329220095\",\"paused\":false}","staticExtra":"{\"poolId\":\"0x3333333333333333333333333333333333333333000000000000000000000001\",\"poolType\":\"AaveLinear\",\"poolTypeVersion\":1,\"vault\":\"0xba12222222228d8ba445958a75a0704d566bf2c8\",\"mainIndex\":0,\"wrappedIndex\":1,\"bptIndex\":2}"}`
+	bbDaiStr  = `{"address":"0x6666666666666666666666666666666666666666","exchange":"balancer-v2-linear","type":"balancer-v2-linear","reserves":["2000000000000000000000000","3000000000000000000000000","5192296853474827628530496329220095"],"tokens":[{"address":"0x4444444444444444444444444444444444444444","swappable":true},{"address":"0x5555555555555555555555555555555555555555","swappable":true},{"address":"0x6666666666666666666666666666666666666666","swappable":true}],"extra":"{\"swapFeePercentage\":\"100000000000000\",\"scalingFactors\":[\"1000000000000000000\",\"1020000000000000000\",\"1000000000000000000\"],\"lowerTarget\":\"1000000000000000000000000\",\"upperTarget\":\"4000000000000000000000000\",\"totalSupply\":\"5192296858534827628530496329220095\",\"paused\":false}","staticExtra":"{\"poolId\":\"0x6666666666666666666666666666666666666666000000000000000000000002\",\"poolType\":\"AaveLinear\",\"poolTypeVersion\":1,\"vault\":\"0xba12222222228d8ba445958a75a0704d566bf2c8\",\"mainIndex\":0,\"wrappedIndex\":1,\"bptIndex\":2}"}`
+	bbUsdStr  = `{"address":"0x9999999999999999999999999999999999999999","exchange":"balancer-v2-composable-stable","type":"balancer-v2-composable-stable","reserves":["5100000000000000000000000","5060000000000000000000000","5192296848374827628530496329220095"],"tokens":[{"address":"0x3333333333333333333333333333333333333333","swappable":true},{"address":"0x6666666666666666666666666666666666666666","swappable":true},{"address":"0x9999999999999999999999999999999999999999","swappable":true}],"extra":"{\"canNotUpdateTokenRates\":false,\"scalingFactors\":[\"1000000000000000000\",\"1000000000000000000\",\"1000000000000000000\"],\"bptTotalSupply\":\"5192296858534827628530496329220095\",\"amp\":\"1000000\",\"lastJoinExit\":{\"lastJoinExitAmplification\":\"1000000\",\"lastPostJoinExitInvariant\":\"10160000000000000000000000\"},\"rateProviders\":[\"0x0000000000000000000000000000000000000000\",\"0x0000000000000000000000000000000000000000\",\"0x0000000000000000000000000000000000000000\"],\"tokenRateCaches\":[{\"rate\":null,\"oldRate\":null,\"duration\":null,\"expires\":null},{\"rate\":null,\"oldRate\":null,\"duration\":null,\"expires\":null},{\"rate\":null,\"oldRate\":null,\"duration\":null,\"expires\":null}],\"swapFeePercentage\":\"100000000000000\",\"protocolFeePercentageCache\":{\"0\":\"0\",\"2\":\"0\"},\"isTokenExemptFromYieldProtocolFee\":[false,false,false],\"isExemptFromYieldProtocolFee\":false,\"inRecoveryMode\":false,\"paused\":false}","staticExtra":"{\"poolId\":\"0x9999999999999999999999999999999999999999000000000000000000000003\",\"poolType\":\"ComposableStable\",\"poolTypeVer\":3,\"bptIndex\":2,\"scalingFactors\":[\"1000000000000000000\",\"1000000000000000000\",\"1000000000000000000\"],\"vault\":\"0xba12222222228d8ba445958a75a0704d566bf2c8\",\"batchSwapEnabled\":true,\"basePools\":{\"0x3333333333333333333333333333333333333333\":[\"0x1111111111111111111111111111111111111111\",\"0x2222222222222222222222222222222222222222\",\"0x3333333333333333333333333333333333333333\"],\"0x6666666666666666666666666666666666666666\":[\"0x4444444444444444444444444444444444444444\",\"0x5555555555555555555555555555555555555555\",\"0x6666666666666666666666666666666666666666\"]}}"}`
+)
+
+func newBoostedPool(t *testing.T) (*PoolSimulator, *linear.PoolSimulator, *linear.PoolSimulator) {
+	basePools := make(map[string]poolpkg.IPoolSimulator)
+	var linearPools []*linear.PoolSimulator
+	for _, poolStr := range []string{bbUsdcStr, bbDaiStr} {
+		var entityPool entity.Pool
+		require.NoError(t, json.Unmarshal([]byte(poolStr), &entityPool))
+		linearPool, err := linear.NewPoolSimulator(entityPool)
+		require.NoError(t, err)
+		basePools[entityPool.Address] = linearPool
+		linearPools = append(linearPools, linearPool)
+	}
+
+	var entityPool entity.Pool
+	require.NoError(t, json.Unmarshal([]byte(bbUsdStr), &entityPool))
+	s, err := NewPoolSimulator(entityPool, basePools)
+	require.NoError(t, err)
+
+	return s, linearPools[0], linearPools[1]
+}
+
+func TestBoostedPoolSwap(t *testing.T) {
+	t.Parallel()
+	s, bbUsdcPool, bbDaiPool := newBoostedPool(t)
+
+	assert.Contains(t, s.CanSwapTo(usdc), dai)
+	assert.Contains(t, s.GetTokens(), usdc)
+
+	amountIn := big.NewInt(1000_000000)
+	result, err := s.CalcAmountOut(poolpkg.CalcAmountOutParams{
+		TokenAmountIn: poolpkg.TokenAmount{Token: usdc, Amount: amountIn},
+		TokenOut:      dai,
+	})
+	require.NoError(t, err)
+
+	// 1000 USDC for a little under 1000 DAI, routed main -> BPT -> BPT -> main
+	amountOut := result.TokenAmountOut.Amount
+	assert.True(t, amountOut.Cmp(bigFromString("999000000000000000000")) > 0, amountOut.String())
+	assert.True(t, amountOut.Cmp(bigFromString("1000000000000000000000")) < 0, amountOut.String())
+
+	swapInfo, ok := result.SwapInfo.(shared.SwapInfo)
+	require.True(t, ok)
+	require.Len(t, swapInfo.Hops, 3)
+	assert.Equal(t, []string{bbUsdc, bbUsd, bbDai},
+		[]string{swapInfo.Hops[0].Pool, swapInfo.Hops[1].Pool, swapInfo.Hops[2].Pool})
+	assert.Equal(t, []string{usdc, bbUsdc, bbDai},
+		[]string{swapInfo.Hops[0].TokenIn, swapInfo.Hops[1].TokenIn, swapInfo.Hops[2].TokenIn})
+	for _, hop := range swapInfo.Hops {
+		// linear pools hold their own BPT, so they are entered and left through swaps
+		assert.Nil(t, hop.JoinExitIndex)
+	}
+
+	s.UpdateBalance(poolpkg.UpdateBalanceParams{
+		TokenAmountIn:  poolpkg.TokenAmount{Token: usdc, Amount: amountIn},
+		TokenAmountOut: *result.TokenAmountOut,
+		SwapInfo:       result.SwapInfo,
+	})
+
+	assert.Equal(t, "2001000000000", bbUsdcPool.GetReserves()[0].String())
+	assert.Equal(t, new(big.Int).Sub(bigFromString("2000000000000000000000000"), amountOut).String(),
+		bbDaiPool.GetReserves()[0].String())
+	assert.Equal(t, new(big.Int).Add(bigFromString("5100000000000000000000000"), swapInfo.Hops[1].AmountIn.ToBig()).String(),
+		s.GetReserves()[0].String())
+}
+
+func bigFromString(s string) *big.Int {
+	v, _ := new(big.Int).SetString(s, 10)
+	return v
+}
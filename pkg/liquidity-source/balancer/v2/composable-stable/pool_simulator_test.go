@@ -1027,7 +1027,7 @@ func TestPoolSimulator_CalcAmountIn(t *testing.T) {
 			err := json.Unmarshal([]byte(tt.fields.poolStr), &pool)
 			assert.Nil(t, err)
 
-			simulator, err := NewPoolSimulator(pool)
+			simulator, err := NewPoolSimulator(pool, nil)
 			assert.Nil(t, err)
 
 			got, err := testutil.MustConcurrentSafe(t, func() (*poolpkg.CalcAmountInResult, error) {
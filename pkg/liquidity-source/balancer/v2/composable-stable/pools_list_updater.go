@@ -160,6 +160,7 @@ func (u *PoolsListUpdater) initPool(
 		poolTokens     = make([]*entity.PoolToken, len(subgraphPool.PoolTokens))
 		reserves       = make([]string, len(subgraphPool.PoolTokens))
 		scalingFactors = make([]*uint256.Int, len(subgraphPool.PoolTokens))
+		basePools      = make(map[string][]string)
 	)
 
 	for j, token := range subgraphPool.PoolTokens {
@@ -172,6 +173,15 @@ func (u *PoolsListUpdater) initPool(
 			big256.TenPow(18-uint8(token.Decimals)),
 			big256.BONE,
 		)
+
+		if token.NestedPool.Address != "" {
+			var underlyingTokens = make([]string, 0, len(token.NestedPool.Tokens))
+
+			for _, baseToken := range token.NestedPool.Tokens {
+				underlyingTokens = append(underlyingTokens, strings.ToLower(baseToken.Address))
+			}
+			basePools[strings.ToLower(token.NestedPool.Address)] = underlyingTokens
+		}
 	}
 
 	staticExtra := StaticExtra{
@@ -181,6 +191,9 @@ func (u *PoolsListUpdater) initPool(
 		BptIndex:       int(bptIndex.Int64()),
 		ScalingFactors: scalingFactors,
 		Vault:          vault,
+
+		BatchSwapEnabled: u.config.BatchSwapEnabled,
+		BasePools:        basePools,
 	}
 	staticExtraBytes, err := json.Marshal(staticExtra)
 	if err != nil {
@@ -58,6 +58,9 @@ type StaticExtra struct {
 	BptIndex       int            `json:"bptIndex"`
 	ScalingFactors []*uint256.Int `json:"scalingFactors"`
 	Vault          string         `json:"vault"`
+
+	BatchSwapEnabled bool                `json:"batchSwapEnabled,omitempty"`
+	BasePools        map[string][]string `json:"basePools,omitempty"`
 }
 
 type AmplificationParameterResp struct {
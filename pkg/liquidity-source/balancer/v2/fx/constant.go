@@ -0,0 +1,18 @@
+package fx
+
+const (
+	DexType = "balancer-v2-fx"
+
+	poolTypeFX = "FX"
+
+	poolMethodGetVault       = "getVault"
+	poolMethodAssimilator    = "assimilator"
+	poolMethodViewParameters = "viewParameters"
+	poolMethodPaused         = "paused"
+
+	assimilatorMethodGetRate = "getRate"
+)
+
+var (
+	defaultGas = Gas{Swap: 230000}
+)
@@ -0,0 +1,9 @@
+package fx
+
+import _ "embed"
+
+//go:embed abis/FXPool.json
+var poolJson []byte
+
+//go:embed abis/Assimilator.json
+var assimilatorJson []byte
@@ -0,0 +1,12 @@
+package fx
+
+import "errors"
+
+var (
+	ErrInvalidToken    = errors.New("invalid token")
+	ErrInvalidAmountIn = errors.New("invalid amount in")
+	ErrInvalidExtra    = errors.New("invalid extra")
+	ErrInvalidRate     = errors.New("invalid oracle rate")
+	ErrPoolPaused      = errors.New("pool is paused")
+	ErrReserveNotFound = errors.New("reserve not found")
+)
@@ -0,0 +1,152 @@
+package fx
+
+import (
+	"math/big"
+
+	"github.com/goccy/go-json"
+	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/shared"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/stabull"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// PoolSimulator simulates a Xave FX pool: a two-token Balancer v2 pool priced by the DFX curve around the oracle
+// rates of its assimilators, the same curve Stabull runs outside the vault.
+type PoolSimulator struct {
+	pool.Pool
+
+	paused      bool
+	curve       stabull.CurveParams
+	oracleRates [2]*uint256.Int
+	decs        [2]*uint256.Int
+
+	vault  string
+	poolID string
+}
+
+var _ = pool.RegisterFactory0(DexType, NewPoolSimulator)
+
+func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
+	var (
+		extra       Extra
+		staticExtra StaticExtra
+	)
+
+	if err := json.Unmarshal([]byte(entityPool.Extra), &extra); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(entityPool.StaticExtra), &staticExtra); err != nil {
+		return nil, err
+	}
+
+	if len(entityPool.Tokens) != 2 || len(entityPool.Reserves) != 2 || extra.Alpha == nil || extra.Beta == nil ||
+		extra.Delta == nil || extra.Epsilon == nil || extra.Lambda == nil {
+		return nil, ErrInvalidExtra
+	}
+
+	poolInfo := pool.PoolInfo{
+		Address:  entityPool.Address,
+		Exchange: entityPool.Exchange,
+		Type:     entityPool.Type,
+		Tokens:   []string{entityPool.Tokens[0].Address, entityPool.Tokens[1].Address},
+		Reserves: []*big.Int{
+			bignumber.NewBig10(entityPool.Reserves[0]),
+			bignumber.NewBig10(entityPool.Reserves[1]),
+		},
+		BlockNumber: entityPool.BlockNumber,
+	}
+
+	return &PoolSimulator{
+		Pool:        pool.Pool{Info: poolInfo},
+		paused:      extra.Paused,
+		curve:       extra.CurveParams,
+		oracleRates: extra.OracleRates,
+		decs: [2]*uint256.Int{
+			big256.TenPow(entityPool.Tokens[0].Decimals),
+			big256.TenPow(entityPool.Tokens[1].Decimals),
+		},
+		vault:  staticExtra.Vault,
+		poolID: staticExtra.PoolID,
+	}, nil
+}
+
+func (s *PoolSimulator) CalcAmountOut(params pool.CalcAmountOutParams) (*pool.CalcAmountOutResult, error) {
+	if s.paused {
+		return nil, ErrPoolPaused
+	}
+
+	tokenAmountIn, tokenOut := params.TokenAmountIn, params.TokenOut
+	indexIn, indexOut := s.GetTokenIndex(tokenAmountIn.Token), s.GetTokenIndex(tokenOut)
+	if indexIn < 0 || indexOut < 0 || indexIn == indexOut {
+		return nil, ErrInvalidToken
+	}
+
+	amountIn, overflow := uint256.FromBig(tokenAmountIn.Amount)
+	if overflow || amountIn.Sign() <= 0 {
+		return nil, ErrInvalidAmountIn
+	}
+
+	rateIn, rateOut := s.oracleRates[indexIn], s.oracleRates[indexOut]
+	if rateIn == nil || rateIn.Sign() <= 0 || rateOut == nil || rateOut.Sign() <= 0 {
+		return nil, ErrInvalidRate
+	}
+
+	reserveIn, overflow := uint256.FromBig(s.Info.Reserves[indexIn])
+	if overflow {
+		return nil, ErrInvalidExtra
+	}
+	reserveOut, overflow := uint256.FromBig(s.Info.Reserves[indexOut])
+	if overflow {
+		return nil, ErrInvalidExtra
+	}
+
+	amountOut, err := stabull.ViewOriginSwap(&s.curve, amountIn, reserveIn, reserveOut, s.decs[indexIn],
+		s.decs[indexOut], rateIn, rateOut)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pool.CalcAmountOutResult{
+		TokenAmountOut: &pool.TokenAmount{Token: tokenOut, Amount: amountOut.ToBig()},
+		Fee:            &pool.TokenAmount{Token: tokenAmountIn.Token, Amount: bignumber.ZeroBI},
+		Gas:            defaultGas.Swap,
+		SwapInfo:       shared.SwapInfo{},
+	}, nil
+}
+
+func (s *PoolSimulator) CloneState() pool.IPoolSimulator {
+	cloned := *s
+	cloned.Info.Reserves = []*big.Int{s.Info.Reserves[0], s.Info.Reserves[1]}
+	return &cloned
+}
+
+func (s *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	for idx, token := range s.Info.Tokens {
+		if token == params.TokenAmountIn.Token {
+			s.Info.Reserves[idx] = new(big.Int).Add(s.Info.Reserves[idx], params.TokenAmountIn.Amount)
+		}
+
+		if token == params.TokenAmountOut.Token {
+			s.Info.Reserves[idx] = new(big.Int).Sub(s.Info.Reserves[idx], params.TokenAmountOut.Amount)
+		}
+	}
+}
+
+func (s *PoolSimulator) GetMetaInfo(tokenIn, tokenOut string) any {
+	return PoolMetaInfo{
+		Vault:           s.vault,
+		PoolID:          s.poolID,
+		TokenOutIndex:   s.GetTokenIndex(tokenOut),
+		BlockNumber:     s.Info.BlockNumber,
+		ApprovalAddress: s.GetApprovalAddress(tokenIn, tokenOut),
+	}
+}
+
+func (s *PoolSimulator) GetApprovalAddress(_, _ string) string {
+	return s.vault
+}
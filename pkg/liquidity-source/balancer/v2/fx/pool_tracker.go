@@ -0,0 +1,231 @@
+package fx
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/goccy/go-json"
+	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/shared"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/stabull"
+	poolpkg "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	pooltrack "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/tracker"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
+)
+
+type PoolTracker struct {
+	config       *shared.Config
+	ethrpcClient *ethrpc.Client
+}
+
+var _ = pooltrack.RegisterFactoryCE(DexType, NewPoolTracker)
+
+func NewPoolTracker(
+	config *shared.Config,
+	ethrpcClient *ethrpc.Client,
+) (*PoolTracker, error) {
+	return &PoolTracker{
+		config:       config,
+		ethrpcClient: ethrpcClient,
+	}, nil
+}
+
+func (t *PoolTracker) GetNewPoolState(
+	ctx context.Context,
+	p entity.Pool,
+	params poolpkg.GetNewPoolStateParams,
+) (entity.Pool, error) {
+	return t.getNewPoolState(ctx, p, params, nil)
+}
+
+func (t *PoolTracker) GetNewPoolStateWithOverrides(
+	ctx context.Context,
+	p entity.Pool,
+	params poolpkg.GetNewPoolStateWithOverridesParams,
+) (entity.Pool, error) {
+	return t.getNewPoolState(ctx, p, poolpkg.GetNewPoolStateParams{Logs: params.Logs}, params.Overrides)
+}
+
+func (t *PoolTracker) getNewPoolState(
+	ctx context.Context,
+	p entity.Pool,
+	_ poolpkg.GetNewPoolStateParams,
+	overrides map[common.Address]gethclient.OverrideAccount,
+) (entity.Pool, error) {
+	logger.WithFields(logger.Fields{
+		"dexId":       t.config.DexID,
+		"dexType":     DexType,
+		"poolAddress": p.Address,
+	}).Info("Start updating state ...")
+
+	defer func() {
+		logger.WithFields(logger.Fields{
+			"dexId":       t.config.DexID,
+			"dexType":     DexType,
+			"poolAddress": p.Address,
+		}).Info("Finish updating state.")
+	}()
+
+	var staticExtra StaticExtra
+	if err := json.Unmarshal([]byte(p.StaticExtra), &staticExtra); err != nil {
+		logger.WithFields(logger.Fields{
+			"dexId":       t.config.DexID,
+			"dexType":     DexType,
+			"poolAddress": p.Address,
+		}).Error(err.Error())
+
+		return p, err
+	}
+
+	// call RPC
+	rpcRes, err := t.queryRPC(ctx, p.Address, staticExtra, overrides)
+	if err != nil {
+		return p, err
+	}
+
+	// update pool
+
+	extra := Extra{
+		CurveParams: stabull.CurveParams{
+			Alpha:   toABDK(rpcRes.Parameters.Alpha),
+			Beta:    toABDK(rpcRes.Parameters.Beta),
+			Delta:   toABDK(rpcRes.Parameters.Delta),
+			Epsilon: toABDK(rpcRes.Parameters.Epsilon),
+			Lambda:  toABDK(rpcRes.Parameters.Lambda),
+		},
+		OracleRates: [2]*uint256.Int{
+			uint256.MustFromBig(rpcRes.OracleRates[0]),
+			uint256.MustFromBig(rpcRes.OracleRates[1]),
+		},
+		Paused: rpcRes.Paused,
+	}
+	extraBytes, err := json.Marshal(extra)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"dexId":       t.config.DexID,
+			"dexType":     DexType,
+			"poolAddress": p.Address,
+		}).Error(err.Error())
+
+		return p, err
+	}
+
+	reserves, err := t.initReserves(p, rpcRes.PoolTokens)
+	if err != nil {
+		return p, err
+	}
+
+	p.BlockNumber = rpcRes.BlockNumber
+	p.Extra = string(extraBytes)
+	p.SwapFee, _ = new(big.Float).Quo(new(big.Float).SetInt(rpcRes.Parameters.Epsilon), big.NewFloat(1e18)).Float64()
+	p.Timestamp = time.Now().Unix()
+	p.Reserves = reserves
+
+	return p, nil
+}
+
+func (t *PoolTracker) initReserves(
+	p entity.Pool,
+	poolTokens PoolTokens,
+) ([]string, error) {
+	reserveByToken := make(map[string]*big.Int)
+	for idx, token := range poolTokens.Tokens {
+		addr := hexutil.Encode(token[:])
+		reserveByToken[addr] = poolTokens.Balances[idx]
+	}
+
+	reserves := make([]string, len(p.Tokens))
+	for idx, token := range p.Tokens {
+		r, ok := reserveByToken[token.Address]
+		if !ok {
+			logger.WithFields(logger.Fields{
+				"dexId":       t.config.DexID,
+				"dexType":     DexType,
+				"poolAddress": p.Address,
+			}).Error("can not get reserve")
+
+			return nil, ErrReserveNotFound
+		}
+
+		reserves[idx] = r.String()
+	}
+
+	return reserves, nil
+}
+
+func (t *PoolTracker) queryRPC(
+	ctx context.Context,
+	poolAddress string,
+	staticExtra StaticExtra,
+	overrides map[common.Address]gethclient.OverrideAccount,
+) (*rpcRes, error) {
+	var (
+		poolTokens  PoolTokens
+		parameters  Parameters
+		oracleRates [2]*big.Int
+		paused      bool
+	)
+
+	req := t.ethrpcClient.R().SetContext(ctx).SetOverrides(overrides)
+
+	req.AddCall(&ethrpc.Call{
+		ABI:    shared.VaultABI,
+		Target: staticExtra.Vault,
+		Method: shared.VaultMethodGetPoolTokens,
+		Params: []any{common.HexToHash(staticExtra.PoolID)},
+	}, []any{&poolTokens})
+
+	req.AddCall(&ethrpc.Call{
+		ABI:    poolABI,
+		Target: poolAddress,
+		Method: poolMethodViewParameters,
+	}, []any{&parameters})
+
+	req.AddCall(&ethrpc.Call{
+		ABI:    poolABI,
+		Target: poolAddress,
+		Method: poolMethodPaused,
+	}, []any{&paused})
+
+	for idx, assimilator := range staticExtra.Assimilators {
+		req.AddCall(&ethrpc.Call{
+			ABI:    assimilatorABI,
+			Target: assimilator,
+			Method: assimilatorMethodGetRate,
+		}, []any{&oracleRates[idx]})
+	}
+
+	res, err := req.Aggregate()
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"dexId":       t.config.DexID,
+			"dexType":     DexType,
+			"poolAddress": poolAddress,
+		}).Error(err.Error())
+
+		return nil, err
+	}
+
+	return &rpcRes{
+		PoolTokens:  poolTokens,
+		Parameters:  parameters,
+		OracleRates: oracleRates,
+		Paused:      paused,
+		BlockNumber: res.BlockNumber.Uint64(),
+	}, nil
+}
+
+// toABDK converts an 18-decimals value as returned by viewParameters() into the 64.64 fixed point the curve uses.
+func toABDK(value *big.Int) *uint256.Int {
+	v := uint256.MustFromBig(value)
+	v.MulDivOverflow(v, big256.U2Pow64, big256.BONE)
+	return v
+}
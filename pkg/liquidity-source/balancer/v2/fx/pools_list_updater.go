@@ -0,0 +1,174 @@
+package fx
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/goccy/go-json"
+	"github.com/samber/lo"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/shared"
+	poollist "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/list"
+	graphqlpkg "github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/graphql"
+)
+
+type PoolsListUpdater struct {
+	config        *shared.Config
+	ethrpcClient  *ethrpc.Client
+	sharedUpdater *shared.PoolsListUpdater
+}
+
+// poolInfo is what the pool lists of the subgraph do not carry: the vault and the assimilator of each token.
+type poolInfo struct {
+	Vault        common.Address
+	Assimilators [2]common.Address
+}
+
+var _ = poollist.RegisterFactoryCEG(DexType, NewPoolsListUpdater)
+
+func NewPoolsListUpdater(
+	config *shared.Config,
+	ethrpcClient *ethrpc.Client,
+	graphqlClient *graphqlpkg.Client,
+) *PoolsListUpdater {
+	config.SubgraphPoolTypes = []string{poolTypeFX}
+
+	sharedUpdater := shared.NewPoolsListUpdater(config, graphqlClient)
+
+	return &PoolsListUpdater{
+		config:        config,
+		ethrpcClient:  ethrpcClient,
+		sharedUpdater: sharedUpdater,
+	}
+}
+
+func (u *PoolsListUpdater) GetNewPools(ctx context.Context, metadataBytes []byte) ([]entity.Pool, []byte, error) {
+	logger.WithFields(logger.Fields{
+		"dexId":   u.config.DexID,
+		"dexType": DexType,
+	}).Infof("Start updating pools list ...")
+	defer func() {
+		logger.WithFields(logger.Fields{
+			"dexId":   u.config.DexID,
+			"dexType": DexType,
+		}).Infof("Finish updating pools list.")
+	}()
+
+	subgraphPools, newMetadataBytes, err := u.sharedUpdater.GetNewPools(ctx, metadataBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// FX pools always hold exactly two tokens, a foreign stablecoin and USDC
+	subgraphPools = lo.Filter(subgraphPools, func(p *shared.SubgraphPool, _ int) bool {
+		return len(p.PoolTokens) == 2
+	})
+
+	infos, err := u.getPoolInfos(ctx, subgraphPools)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pools, err := u.initPools(subgraphPools, infos)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"dexId":   u.config.DexID,
+			"dexType": DexType,
+		}).Error(err.Error())
+
+		return nil, nil, err
+	}
+
+	return pools, newMetadataBytes, nil
+}
+
+func (u *PoolsListUpdater) getPoolInfos(ctx context.Context, subgraphPools []*shared.SubgraphPool) ([]poolInfo, error) {
+	infos := make([]poolInfo, len(subgraphPools))
+
+	req := u.ethrpcClient.R().SetContext(ctx)
+	for idx, subgraphPool := range subgraphPools {
+		req.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: subgraphPool.Address,
+			Method: poolMethodGetVault,
+		}, []any{&infos[idx].Vault})
+		for j, token := range subgraphPool.PoolTokens {
+			req.AddCall(&ethrpc.Call{
+				ABI:    poolABI,
+				Target: subgraphPool.Address,
+				Method: poolMethodAssimilator,
+				Params: []any{common.HexToAddress(token.Address)},
+			}, []any{&infos[idx].Assimilators[j]})
+		}
+	}
+	if _, err := req.Aggregate(); err != nil {
+		logger.WithFields(logger.Fields{
+			"dexId":   u.config.DexID,
+			"dexType": DexType,
+		}).Error(err.Error())
+		return nil, err
+	}
+
+	return infos, nil
+}
+
+func (u *PoolsListUpdater) initPools(subgraphPools []*shared.SubgraphPool, infos []poolInfo) ([]entity.Pool, error) {
+	pools := make([]entity.Pool, 0, len(subgraphPools))
+	for idx := range subgraphPools {
+		pool, err := u.initPool(subgraphPools[idx], infos[idx])
+		if err != nil {
+			return nil, err
+		}
+
+		pools = append(pools, pool)
+	}
+
+	return pools, nil
+}
+
+func (u *PoolsListUpdater) initPool(subgraphPool *shared.SubgraphPool, info poolInfo) (entity.Pool, error) {
+	var (
+		poolTokens = make([]*entity.PoolToken, len(subgraphPool.PoolTokens))
+		reserves   = make([]string, len(subgraphPool.PoolTokens))
+	)
+
+	for j, token := range subgraphPool.PoolTokens {
+		poolTokens[j] = &entity.PoolToken{
+			Address:   strings.ToLower(token.Address),
+			Decimals:  uint8(token.Decimals),
+			Swappable: true,
+		}
+		reserves[j] = "0"
+	}
+
+	staticExtra := StaticExtra{
+		PoolID:      subgraphPool.ID,
+		PoolType:    subgraphPool.Type,
+		PoolTypeVer: subgraphPool.Version,
+		Vault:       hexutil.Encode(info.Vault[:]),
+		Assimilators: [2]string{
+			hexutil.Encode(info.Assimilators[0][:]),
+			hexutil.Encode(info.Assimilators[1][:]),
+		},
+	}
+	staticExtraBytes, err := json.Marshal(staticExtra)
+	if err != nil {
+		return entity.Pool{}, err
+	}
+
+	return entity.Pool{
+		Address:     strings.ToLower(subgraphPool.Address),
+		Exchange:    u.config.DexID,
+		Type:        DexType,
+		Timestamp:   time.Now().Unix(),
+		Tokens:      poolTokens,
+		Reserves:    reserves,
+		StaticExtra: string(staticExtraBytes),
+	}, nil
+}
@@ -0,0 +1,60 @@
+package fx
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/stabull"
+)
+
+type Gas struct {
+	Swap int64
+}
+
+type Extra struct {
+	// CurveParams are the viewParameters() greeks converted from 1e18 into 64.64 fixed point.
+	stabull.CurveParams `json:"curveParams"`
+	// OracleRates are the assimilator rates (8 decimals) of each token against the numeraire.
+	OracleRates [2]*uint256.Int `json:"oracleRates"`
+	Paused      bool            `json:"paused"`
+}
+
+type StaticExtra struct {
+	PoolID       string    `json:"poolId"`
+	PoolType     string    `json:"poolType"`
+	PoolTypeVer  int       `json:"poolTypeVersion"`
+	Vault        string    `json:"vault"`
+	Assimilators [2]string `json:"assimilators"`
+}
+
+type PoolTokens struct {
+	Tokens          []common.Address
+	Balances        []*big.Int
+	LastChangeBlock *big.Int
+}
+
+type Parameters struct {
+	Alpha   *big.Int
+	Beta    *big.Int
+	Delta   *big.Int
+	Epsilon *big.Int
+	Lambda  *big.Int
+}
+
+type PoolMetaInfo struct {
+	Vault           string `json:"vault"`
+	PoolID          string `json:"poolId"`
+	TokenOutIndex   int    `json:"tokenOutIndex"`
+	BlockNumber     uint64 `json:"blockNumber"`
+	ApprovalAddress string `json:"approvalAddress"`
+}
+
+type rpcRes struct {
+	PoolTokens  PoolTokens
+	Parameters  Parameters
+	OracleRates [2]*big.Int
+	Paused      bool
+	BlockNumber uint64
+}
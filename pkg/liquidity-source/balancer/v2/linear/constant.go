@@ -0,0 +1,25 @@
+package linear
+
+const (
+	DexType = "balancer-v2-linear"
+
+	poolTypeLegacyAaveLinear    = "AaveLinear"
+	poolTypeLegacyERC4626Linear = "ERC4626Linear"
+	poolTypeLegacyEulerLinear   = "EulerLinear"
+
+	poolTypeLinear = "LINEAR"
+
+	poolMethodGetSwapFeePercentage = "getSwapFeePercentage"
+	poolMethodGetPausedState       = "getPausedState"
+	poolMethodGetVault             = "getVault"
+	poolMethodGetScalingFactors    = "getScalingFactors"
+	poolMethodGetTargets           = "getTargets"
+	poolMethodGetMainIndex         = "getMainIndex"
+	poolMethodGetWrappedIndex      = "getWrappedIndex"
+	poolMethodGetBptIndex          = "getBptIndex"
+	poolMethodTotalSupply          = "totalSupply"
+)
+
+var (
+	defaultGas = Gas{Swap: 88000}
+)
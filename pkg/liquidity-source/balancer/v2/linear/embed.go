@@ -0,0 +1,6 @@
+package linear
+
+import _ "embed"
+
+//go:embed abis/LinearPool.json
+var poolJson []byte
@@ -0,0 +1,13 @@
+package linear
+
+import "errors"
+
+var (
+	ErrInvalidToken     = errors.New("INVALID_TOKEN")
+	ErrInvalidExtra     = errors.New("invalid extra")
+	ErrInvalidReserve   = errors.New("invalid reserve")
+	ErrInvalidAmountIn  = errors.New("invalid amount in")
+	ErrInvalidAmountOut = errors.New("invalid amount out")
+	ErrPoolPaused       = errors.New("pool is paused")
+	ErrReserveNotFound  = errors.New("reserve not found")
+)
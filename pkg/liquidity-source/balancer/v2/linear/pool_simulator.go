@@ -0,0 +1,335 @@
+package linear
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/goccy/go-json"
+	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/math"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/shared"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+var (
+	ErrJoinExitNotSupported = errors.New("linear pools are only entered and left through swaps")
+)
+
+// PoolSimulator simulates a Balancer v2 linear pool, which holds a main token, a wrapped (yield-bearing) version of it
+// and its own pre-minted BPT. Main and wrapped tokens trade 1:1 at the wrapped rate while the main balance stays
+// within [lowerTarget, upperTarget]; beyond that a fee pushes the balance back towards the targets.
+type PoolSimulator struct {
+	pool.Pool
+
+	paused bool
+
+	swapFeePercentage *uint256.Int
+	scalingFactors    []*uint256.Int
+	lowerTarget       *uint256.Int
+	upperTarget       *uint256.Int
+	totalSupply       *uint256.Int
+
+	mainIndex    int
+	wrappedIndex int
+	bptIndex     int
+
+	vault  string
+	poolID string
+}
+
+var _ shared.IBasePool = (*PoolSimulator)(nil)
+
+var _ = pool.RegisterFactory0(DexType, NewPoolSimulator)
+
+func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
+	var (
+		extra       Extra
+		staticExtra StaticExtra
+
+		tokens   = make([]string, len(entityPool.Tokens))
+		reserves = make([]*big.Int, len(entityPool.Tokens))
+	)
+
+	if err := json.Unmarshal([]byte(entityPool.Extra), &extra); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(entityPool.StaticExtra), &staticExtra); err != nil {
+		return nil, err
+	}
+
+	if len(extra.ScalingFactors) != len(entityPool.Tokens) || extra.TotalSupply == nil ||
+		extra.LowerTarget == nil || extra.UpperTarget == nil || extra.SwapFeePercentage == nil {
+		return nil, ErrInvalidExtra
+	}
+
+	for idx := range entityPool.Tokens {
+		tokens[idx] = entityPool.Tokens[idx].Address
+		reserves[idx] = bignumber.NewBig10(entityPool.Reserves[idx])
+	}
+
+	poolInfo := pool.PoolInfo{
+		Address:     entityPool.Address,
+		Exchange:    entityPool.Exchange,
+		Type:        entityPool.Type,
+		Tokens:      tokens,
+		Reserves:    reserves,
+		BlockNumber: entityPool.BlockNumber,
+	}
+
+	return &PoolSimulator{
+		Pool:              pool.Pool{Info: poolInfo},
+		paused:            extra.Paused,
+		swapFeePercentage: extra.SwapFeePercentage,
+		scalingFactors:    extra.ScalingFactors,
+		lowerTarget:       extra.LowerTarget,
+		upperTarget:       extra.UpperTarget,
+		totalSupply:       extra.TotalSupply,
+		mainIndex:         staticExtra.MainIndex,
+		wrappedIndex:      staticExtra.WrappedIndex,
+		bptIndex:          staticExtra.BptIndex,
+		vault:             staticExtra.Vault,
+		poolID:            staticExtra.PoolID,
+	}, nil
+}
+
+func (s *PoolSimulator) GetPoolId() string {
+	return s.poolID
+}
+
+func (s *PoolSimulator) OnJoin(_ string, _ *uint256.Int) (*uint256.Int, error) {
+	return nil, ErrJoinExitNotSupported
+}
+
+func (s *PoolSimulator) OnExit(_ string, _ *uint256.Int) (*uint256.Int, error) {
+	return nil, ErrJoinExitNotSupported
+}
+
+func (s *PoolSimulator) OnSwap(tokenIn, tokenOut string, amountIn *uint256.Int) (*uint256.Int, error) {
+	if s.paused {
+		return nil, ErrPoolPaused
+	}
+
+	indexIn, indexOut := s.GetTokenIndex(tokenIn), s.GetTokenIndex(tokenOut)
+	if indexIn < 0 || indexOut < 0 || indexIn == indexOut {
+		return nil, ErrInvalidToken
+	}
+
+	return s.swapGivenIn(indexIn, indexOut, amountIn)
+}
+
+func (s *PoolSimulator) CalcAmountOut(params pool.CalcAmountOutParams) (*pool.CalcAmountOutResult, error) {
+	tokenAmountIn, tokenOut := params.TokenAmountIn, params.TokenOut
+	amountIn, overflow := uint256.FromBig(tokenAmountIn.Amount)
+	if overflow {
+		return nil, ErrInvalidAmountIn
+	}
+
+	amountOut, err := s.OnSwap(tokenAmountIn.Token, tokenOut, amountIn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pool.CalcAmountOutResult{
+		TokenAmountOut: &pool.TokenAmount{Token: tokenOut, Amount: amountOut.ToBig()},
+		Fee:            &pool.TokenAmount{Token: tokenOut, Amount: bignumber.ZeroBI},
+		Gas:            defaultGas.Swap,
+		SwapInfo:       shared.SwapInfo{},
+	}, nil
+}
+
+func (s *PoolSimulator) CalcAmountIn(params pool.CalcAmountInParams) (*pool.CalcAmountInResult, error) {
+	if s.paused {
+		return nil, ErrPoolPaused
+	}
+
+	tokenAmountOut, tokenIn := params.TokenAmountOut, params.TokenIn
+	amountOut, overflow := uint256.FromBig(tokenAmountOut.Amount)
+	if overflow {
+		return nil, ErrInvalidAmountOut
+	}
+
+	indexIn, indexOut := s.GetTokenIndex(tokenIn), s.GetTokenIndex(tokenAmountOut.Token)
+	if indexIn < 0 || indexOut < 0 || indexIn == indexOut {
+		return nil, ErrInvalidToken
+	}
+
+	amountIn, err := s.swapGivenOut(indexIn, indexOut, amountOut)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pool.CalcAmountInResult{
+		TokenAmountIn: &pool.TokenAmount{Token: tokenIn, Amount: amountIn.ToBig()},
+		Fee:           &pool.TokenAmount{Token: tokenIn, Amount: bignumber.ZeroBI},
+		Gas:           defaultGas.Swap,
+		SwapInfo:      shared.SwapInfo{},
+	}, nil
+}
+
+// swapGivenIn mirrors LinearPool._swapGivenBptIn/_swapGivenMainIn/_swapGivenWrappedIn on upscaled amounts.
+func (s *PoolSimulator) swapGivenIn(indexIn, indexOut int, amountIn *uint256.Int) (*uint256.Int, error) {
+	balances, err := s.upscaledBalances()
+	if err != nil {
+		return nil, err
+	}
+
+	amount, err := _upscale(amountIn, s.scalingFactors[indexIn])
+	if err != nil {
+		return nil, err
+	}
+
+	virtualSupply, err := s.virtualSupply(balances)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mainBalance, wrappedBalance = balances[s.mainIndex], balances[s.wrappedIndex]
+		params                      = s.params()
+		amountOut                   *uint256.Int
+	)
+
+	switch {
+	case indexIn == s.bptIndex && indexOut == s.mainIndex:
+		amountOut, err = math.LinearMath.CalcMainOutPerBptIn(amount, mainBalance, wrappedBalance, virtualSupply, params)
+	case indexIn == s.bptIndex && indexOut == s.wrappedIndex:
+		amountOut, err = math.LinearMath.CalcWrappedOutPerBptIn(amount, mainBalance, wrappedBalance, virtualSupply,
+			params)
+	case indexIn == s.mainIndex && indexOut == s.wrappedIndex:
+		amountOut, err = math.LinearMath.CalcWrappedOutPerMainIn(amount, mainBalance, params)
+	case indexIn == s.mainIndex && indexOut == s.bptIndex:
+		amountOut, err = math.LinearMath.CalcBptOutPerMainIn(amount, mainBalance, wrappedBalance, virtualSupply, params)
+	case indexIn == s.wrappedIndex && indexOut == s.mainIndex:
+		amountOut, err = math.LinearMath.CalcMainOutPerWrappedIn(amount, mainBalance, params)
+	case indexIn == s.wrappedIndex && indexOut == s.bptIndex:
+		amountOut, err = math.LinearMath.CalcBptOutPerWrappedIn(amount, mainBalance, wrappedBalance, virtualSupply,
+			params)
+	default:
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return _downscaleDown(amountOut, s.scalingFactors[indexOut])
+}
+
+// swapGivenOut mirrors LinearPool._swapGivenBptOut/_swapGivenMainOut/_swapGivenWrappedOut on upscaled amounts.
+func (s *PoolSimulator) swapGivenOut(indexIn, indexOut int, amountOut *uint256.Int) (*uint256.Int, error) {
+	balances, err := s.upscaledBalances()
+	if err != nil {
+		return nil, err
+	}
+
+	amount, err := _upscale(amountOut, s.scalingFactors[indexOut])
+	if err != nil {
+		return nil, err
+	}
+
+	virtualSupply, err := s.virtualSupply(balances)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mainBalance, wrappedBalance = balances[s.mainIndex], balances[s.wrappedIndex]
+		params                      = s.params()
+		amountIn                    *uint256.Int
+	)
+
+	switch {
+	case indexOut == s.bptIndex && indexIn == s.mainIndex:
+		amountIn, err = math.LinearMath.CalcMainInPerBptOut(amount, mainBalance, wrappedBalance, virtualSupply, params)
+	case indexOut == s.bptIndex && indexIn == s.wrappedIndex:
+		amountIn, err = math.LinearMath.CalcWrappedInPerBptOut(amount, mainBalance, wrappedBalance, virtualSupply,
+			params)
+	case indexOut == s.mainIndex && indexIn == s.wrappedIndex:
+		amountIn, err = math.LinearMath.CalcWrappedInPerMainOut(amount, mainBalance, params)
+	case indexOut == s.mainIndex && indexIn == s.bptIndex:
+		amountIn, err = math.LinearMath.CalcBptInPerMainOut(amount, mainBalance, wrappedBalance, virtualSupply, params)
+	case indexOut == s.wrappedIndex && indexIn == s.mainIndex:
+		amountIn, err = math.LinearMath.CalcMainInPerWrappedOut(amount, mainBalance, params)
+	case indexOut == s.wrappedIndex && indexIn == s.bptIndex:
+		amountIn, err = math.LinearMath.CalcBptInPerWrappedOut(amount, mainBalance, wrappedBalance, virtualSupply,
+			params)
+	default:
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return _downscaleUp(amountIn, s.scalingFactors[indexIn])
+}
+
+func (s *PoolSimulator) params() *math.LinearParams {
+	return &math.LinearParams{
+		Fee:         s.swapFeePercentage,
+		LowerTarget: s.lowerTarget,
+		UpperTarget: s.upperTarget,
+	}
+}
+
+// virtualSupply is the BPT in circulation: all of it is pre-minted and whatever the vault still holds is unissued.
+func (s *PoolSimulator) virtualSupply(balances []*uint256.Int) (*uint256.Int, error) {
+	return math.FixedPoint.Sub(s.totalSupply, balances[s.bptIndex])
+}
+
+func (s *PoolSimulator) upscaledBalances() ([]*uint256.Int, error) {
+	balances := make([]*uint256.Int, len(s.Info.Reserves))
+	for i, reserve := range s.Info.Reserves {
+		balance, overflow := uint256.FromBig(reserve)
+		if overflow {
+			return nil, ErrInvalidReserve
+		}
+
+		var err error
+		if balances[i], err = _upscale(balance, s.scalingFactors[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return balances, nil
+}
+
+func (s *PoolSimulator) GetMetaInfo(tokenIn, tokenOut string) any {
+	return PoolMetaInfo{
+		Vault:           s.vault,
+		PoolID:          s.poolID,
+		TokenOutIndex:   s.GetTokenIndex(tokenOut),
+		BlockNumber:     s.Info.BlockNumber,
+		ApprovalAddress: s.GetApprovalAddress(tokenIn, tokenOut),
+	}
+}
+
+func (s *PoolSimulator) GetApprovalAddress(_, _ string) string {
+	return s.vault
+}
+
+func (s *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	for idx, token := range s.Info.Tokens {
+		if token == params.TokenAmountIn.Token {
+			s.Info.Reserves[idx] = new(big.Int).Add(s.Info.Reserves[idx], params.TokenAmountIn.Amount)
+		}
+
+		if token == params.TokenAmountOut.Token {
+			s.Info.Reserves[idx] = new(big.Int).Sub(s.Info.Reserves[idx], params.TokenAmountOut.Amount)
+		}
+	}
+}
+
+func _upscale(amount *uint256.Int, scalingFactor *uint256.Int) (*uint256.Int, error) {
+	return math.FixedPoint.MulDown(amount, scalingFactor)
+}
+
+func _downscaleDown(amount *uint256.Int, scalingFactor *uint256.Int) (*uint256.Int, error) {
+	return math.FixedPoint.DivDown(amount, scalingFactor)
+}
+
+func _downscaleUp(amount *uint256.Int, scalingFactor *uint256.Int) (*uint256.Int, error) {
+	return math.FixedPoint.DivUp(amount, scalingFactor)
+}
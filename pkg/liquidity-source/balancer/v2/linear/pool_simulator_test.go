@@ -0,0 +1,140 @@
+package linear
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/testutil"
+)
+
+const (
+	mainToken    = "0x1111111111111111111111111111111111111111"
+	wrappedToken = "0x2222222222222222222222222222222222222222"
+	bptToken     = "0x3333333333333333333333333333333333333333"
+
+	// 2M USDC-like main, 3M wrapped at a 1.05 rate, 5.1M BPT in circulation, targets at 1M and 4M, 0.01% fee
+	linearPoolStr = `{"address":"0x3333333333333333333333333333333333333333","exchange":"balancer-v2-linear","type":"balancer-v2-linear","reserves":["2000000000000","3000000000000","5192296853434827628530496329220095"],"tokens":[{"address":"0x1111111111111111111111111111111111111111","swappable":true},{"address":"0x2222222222222222222222222222222222222222","swappable":true},{"address":"0x3333333333333333333333333333333333333333","swappable":true}],"extra":"{\"swapFeePercentage\":\"100000000000000\",\"scalingFactors\":[\"1000000000000000000000000000000\",\"1050000000000000000000000000000\",\"1000000000000000000\"],\"lowerTarget\":\"1000000000000000000000000\",\"upperTarget\":\"4000000000000000000000000\",\"totalSupply\":\"5192296858534827628530496329220095\",\"paused\":false}","staticExtra":"{\"poolId\":\"0x3333333333333333333333333333333333333333000000000000000000000001\",\"poolType\":\"AaveLinear\",\"poolTypeVersion\":1,\"vault\":\"0xba12222222228d8ba445958a75a0704d566bf2c8\",\"mainIndex\":0,\"wrappedIndex\":1,\"bptIndex\":2}"}`
+)
+
+func newTestPool(t *testing.T) *PoolSimulator {
+	var entityPool entity.Pool
+	require.NoError(t, json.Unmarshal([]byte(linearPoolStr), &entityPool))
+
+	s, err := NewPoolSimulator(entityPool)
+	require.NoError(t, err)
+
+	return s
+}
+
+func TestCalcAmountOut(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		tokenIn   string
+		amountIn  string
+		tokenOut  string
+		amountOut string
+		err       error
+	}{
+		{
+			name:      "main to wrapped within targets swaps at the wrapped rate",
+			tokenIn:   mainToken,
+			amountIn:  "1000000000",
+			tokenOut:  wrappedToken,
+			amountOut: "952380952",
+		},
+		{
+			name:      "wrapped to main below the lower target is charged the fee",
+			tokenIn:   wrappedToken,
+			amountIn:  "1500000000000",
+			tokenOut:  mainToken,
+			amountOut: "1574942505749",
+		},
+		{
+			name:      "main to BPT",
+			tokenIn:   mainToken,
+			amountIn:  "1000000000",
+			tokenOut:  bptToken,
+			amountOut: "990291262135922330097",
+		},
+		{
+			name:      "BPT to wrapped",
+			tokenIn:   bptToken,
+			amountIn:  "1000000000000000000000",
+			tokenOut:  wrappedToken,
+			amountOut: "961718020",
+		},
+		{
+			name:     "unknown token",
+			tokenIn:  "0x4444444444444444444444444444444444444444",
+			amountIn: "1000000000",
+			tokenOut: mainToken,
+			err:      ErrInvalidToken,
+		},
+	}
+
+	s := newTestPool(t)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := testutil.MustConcurrentSafe(t, func() (*pool.CalcAmountOutResult, error) {
+				return s.CalcAmountOut(pool.CalcAmountOutParams{
+					TokenAmountIn: pool.TokenAmount{Token: tc.tokenIn, Amount: bignumber.NewBig10(tc.amountIn)},
+					TokenOut:      tc.tokenOut,
+				})
+			})
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.amountOut, result.TokenAmountOut.Amount.String())
+		})
+	}
+}
+
+func TestCalcAmountIn(t *testing.T) {
+	t.Parallel()
+	s := newTestPool(t)
+
+	result, err := testutil.MustConcurrentSafe(t, func() (*pool.CalcAmountInResult, error) {
+		return s.CalcAmountIn(pool.CalcAmountInParams{
+			TokenAmountOut: pool.TokenAmount{Token: mainToken, Amount: big.NewInt(1000000000)},
+			TokenIn:        wrappedToken,
+		})
+	})
+	require.NoError(t, err)
+	// rounded up against the 952380952 the same main amount buys
+	assert.Equal(t, "952380953", result.TokenAmountIn.Amount.String())
+}
+
+func TestUpdateBalance(t *testing.T) {
+	t.Parallel()
+	s := newTestPool(t)
+
+	amountIn := big.NewInt(1000000000)
+	result, err := s.CalcAmountOut(pool.CalcAmountOutParams{
+		TokenAmountIn: pool.TokenAmount{Token: mainToken, Amount: amountIn},
+		TokenOut:      bptToken,
+	})
+	require.NoError(t, err)
+
+	s.UpdateBalance(pool.UpdateBalanceParams{
+		TokenAmountIn:  pool.TokenAmount{Token: mainToken, Amount: amountIn},
+		TokenAmountOut: *result.TokenAmountOut,
+		SwapInfo:       result.SwapInfo,
+	})
+
+	assert.Equal(t, "2001000000000", s.Info.Reserves[0].String())
+	balances, err := s.upscaledBalances()
+	require.NoError(t, err)
+	virtualSupply, err := s.virtualSupply(balances)
+	require.NoError(t, err)
+	assert.Equal(t, "5100990291262135922330097", virtualSupply.String())
+}
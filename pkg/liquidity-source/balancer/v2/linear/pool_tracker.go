@@ -0,0 +1,246 @@
+package linear
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/goccy/go-json"
+	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/shared"
+	poolpkg "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	pooltrack "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/tracker"
+)
+
+type PoolTracker struct {
+	config       *shared.Config
+	ethrpcClient *ethrpc.Client
+}
+
+var _ = pooltrack.RegisterFactoryCE(DexType, NewPoolTracker)
+
+func NewPoolTracker(
+	config *shared.Config,
+	ethrpcClient *ethrpc.Client,
+) (*PoolTracker, error) {
+	return &PoolTracker{
+		config:       config,
+		ethrpcClient: ethrpcClient,
+	}, nil
+}
+
+func (t *PoolTracker) GetNewPoolState(
+	ctx context.Context,
+	p entity.Pool,
+	params poolpkg.GetNewPoolStateParams,
+) (entity.Pool, error) {
+	return t.getNewPoolState(ctx, p, params, nil)
+}
+
+func (t *PoolTracker) GetNewPoolStateWithOverrides(
+	ctx context.Context,
+	p entity.Pool,
+	params poolpkg.GetNewPoolStateWithOverridesParams,
+) (entity.Pool, error) {
+	return t.getNewPoolState(ctx, p, poolpkg.GetNewPoolStateParams{Logs: params.Logs}, params.Overrides)
+}
+
+func (t *PoolTracker) getNewPoolState(
+	ctx context.Context,
+	p entity.Pool,
+	_ poolpkg.GetNewPoolStateParams,
+	overrides map[common.Address]gethclient.OverrideAccount,
+) (entity.Pool, error) {
+	logger.WithFields(logger.Fields{
+		"dexId":       t.config.DexID,
+		"dexType":     DexType,
+		"poolAddress": p.Address,
+	}).Info("Start updating state ...")
+
+	defer func() {
+		logger.WithFields(logger.Fields{
+			"dexId":       t.config.DexID,
+			"dexType":     DexType,
+			"poolAddress": p.Address,
+		}).Info("Finish updating state.")
+	}()
+
+	var staticExtra StaticExtra
+	if err := json.Unmarshal([]byte(p.StaticExtra), &staticExtra); err != nil {
+		logger.WithFields(logger.Fields{
+			"dexId":       t.config.DexID,
+			"dexType":     DexType,
+			"poolAddress": p.Address,
+		}).Error(err.Error())
+
+		return p, err
+	}
+
+	// call RPC
+	rpcRes, err := t.queryRPC(ctx, p.Address, staticExtra.PoolID, staticExtra.Vault, overrides)
+	if err != nil {
+		return p, err
+	}
+
+	scalingFactors := make([]*uint256.Int, len(rpcRes.ScalingFactors))
+	for idx, factor := range rpcRes.ScalingFactors {
+		scalingFactors[idx], _ = uint256.FromBig(factor)
+	}
+
+	var (
+		swapFeePercentage, _ = uint256.FromBig(rpcRes.SwapFeePercentage)
+		lowerTarget, _       = uint256.FromBig(rpcRes.Targets.LowerTarget)
+		upperTarget, _       = uint256.FromBig(rpcRes.Targets.UpperTarget)
+		totalSupply, _       = uint256.FromBig(rpcRes.TotalSupply)
+	)
+
+	// update pool
+
+	extra := Extra{
+		SwapFeePercentage: swapFeePercentage,
+		ScalingFactors:    scalingFactors,
+		LowerTarget:       lowerTarget,
+		UpperTarget:       upperTarget,
+		TotalSupply:       totalSupply,
+		Paused:            !isNotPaused(rpcRes.PausedState),
+	}
+	extraBytes, err := json.Marshal(extra)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"dexId":       t.config.DexID,
+			"dexType":     DexType,
+			"poolAddress": p.Address,
+		}).Error(err.Error())
+
+		return p, err
+	}
+
+	reserves, err := t.initReserves(p, rpcRes.PoolTokens)
+	if err != nil {
+		return p, err
+	}
+
+	p.BlockNumber = rpcRes.BlockNumber
+	p.Extra = string(extraBytes)
+	p.Timestamp = time.Now().Unix()
+	p.Reserves = reserves
+
+	return p, nil
+}
+
+func (t *PoolTracker) initReserves(
+	p entity.Pool,
+	poolTokens PoolTokens,
+) ([]string, error) {
+	reserveByToken := make(map[string]*big.Int)
+	for idx, token := range poolTokens.Tokens {
+		addr := hexutil.Encode(token[:])
+		reserveByToken[addr] = poolTokens.Balances[idx]
+	}
+
+	reserves := make([]string, len(p.Tokens))
+	for idx, token := range p.Tokens {
+		r, ok := reserveByToken[token.Address]
+		if !ok {
+			logger.WithFields(logger.Fields{
+				"dexId":       t.config.DexID,
+				"dexType":     DexType,
+				"poolAddress": p.Address,
+			}).Error("can not get reserve")
+
+			return nil, ErrReserveNotFound
+		}
+
+		reserves[idx] = r.String()
+	}
+
+	return reserves, nil
+}
+
+func (t *PoolTracker) queryRPC(
+	ctx context.Context,
+	poolAddress string,
+	poolID string,
+	vault string,
+	overrides map[common.Address]gethclient.OverrideAccount,
+) (*rpcRes, error) {
+	var (
+		poolTokens                     PoolTokens
+		swapFeePercentage, totalSupply *big.Int
+		scalingFactors                 []*big.Int
+		targets                        Targets
+		pausedState                    PausedState
+	)
+
+	req := t.ethrpcClient.R().SetContext(ctx).SetOverrides(overrides)
+
+	req.AddCall(&ethrpc.Call{
+		ABI:    shared.VaultABI,
+		Target: vault,
+		Method: shared.VaultMethodGetPoolTokens,
+		Params: []any{common.HexToHash(poolID)},
+	}, []any{&poolTokens})
+
+	req.AddCall(&ethrpc.Call{
+		ABI:    poolABI,
+		Target: poolAddress,
+		Method: poolMethodGetSwapFeePercentage,
+	}, []any{&swapFeePercentage})
+
+	// the wrapped token's scaling factor carries its current rate
+	req.AddCall(&ethrpc.Call{
+		ABI:    poolABI,
+		Target: poolAddress,
+		Method: poolMethodGetScalingFactors,
+	}, []any{&scalingFactors})
+
+	req.AddCall(&ethrpc.Call{
+		ABI:    poolABI,
+		Target: poolAddress,
+		Method: poolMethodGetTargets,
+	}, []any{&targets})
+
+	req.AddCall(&ethrpc.Call{
+		ABI:    poolABI,
+		Target: poolAddress,
+		Method: poolMethodTotalSupply,
+	}, []any{&totalSupply})
+
+	req.AddCall(&ethrpc.Call{
+		ABI:    poolABI,
+		Target: poolAddress,
+		Method: poolMethodGetPausedState,
+	}, []any{&pausedState})
+
+	res, err := req.Aggregate()
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"dexId":       t.config.DexID,
+			"dexType":     DexType,
+			"poolAddress": poolAddress,
+		}).Error(err.Error())
+
+		return nil, err
+	}
+
+	return &rpcRes{
+		PoolTokens:        poolTokens,
+		SwapFeePercentage: swapFeePercentage,
+		ScalingFactors:    scalingFactors,
+		Targets:           targets,
+		TotalSupply:       totalSupply,
+		PausedState:       pausedState,
+		BlockNumber:       res.BlockNumber.Uint64(),
+	}, nil
+}
+
+func isNotPaused(pausedState PausedState) bool {
+	return time.Now().Unix() > pausedState.BufferPeriodEndTime.Int64() || !pausedState.Paused
+}
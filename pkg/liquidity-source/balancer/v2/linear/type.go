@@ -0,0 +1,66 @@
+package linear
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+)
+
+type Gas struct {
+	Swap int64
+}
+
+type Extra struct {
+	SwapFeePercentage *uint256.Int   `json:"swapFeePercentage"`
+	ScalingFactors    []*uint256.Int `json:"scalingFactors"`
+	LowerTarget       *uint256.Int   `json:"lowerTarget"`
+	UpperTarget       *uint256.Int   `json:"upperTarget"`
+	TotalSupply       *uint256.Int   `json:"totalSupply"`
+	Paused            bool           `json:"paused"`
+}
+
+type StaticExtra struct {
+	PoolID       string `json:"poolId"`
+	PoolType     string `json:"poolType"`
+	PoolTypeVer  int    `json:"poolTypeVersion"`
+	Vault        string `json:"vault"`
+	MainIndex    int    `json:"mainIndex"`
+	WrappedIndex int    `json:"wrappedIndex"`
+	BptIndex     int    `json:"bptIndex"`
+}
+
+type PoolTokens struct {
+	Tokens          []common.Address
+	Balances        []*big.Int
+	LastChangeBlock *big.Int
+}
+
+type PausedState struct {
+	Paused              bool
+	PauseWindowEndTime  *big.Int
+	BufferPeriodEndTime *big.Int
+}
+
+type Targets struct {
+	LowerTarget *big.Int
+	UpperTarget *big.Int
+}
+
+type PoolMetaInfo struct {
+	Vault           string `json:"vault"`
+	PoolID          string `json:"poolId"`
+	TokenOutIndex   int    `json:"tokenOutIndex"`
+	BlockNumber     uint64 `json:"blockNumber"`
+	ApprovalAddress string `json:"approvalAddress"`
+}
+
+type rpcRes struct {
+	PoolTokens        PoolTokens
+	SwapFeePercentage *big.Int
+	ScalingFactors    []*big.Int
+	Targets           Targets
+	TotalSupply       *big.Int
+	PausedState       PausedState
+	BlockNumber       uint64
+}
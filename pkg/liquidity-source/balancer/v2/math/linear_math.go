@@ -0,0 +1,467 @@
+package math
+
+import (
+	"github.com/holiman/uint256"
+)
+
+var LinearMath *linearMath
+
+// linearMath ports LinearMath.sol of the Balancer v2 linear pools, whose invariant is the nominal main balance plus
+// the wrapped balance.
+type linearMath struct{}
+
+// LinearParams are the swap fee and the main balance targets of a linear pool. Swaps moving the main balance outside
+// [LowerTarget, UpperTarget] are charged the fee on the part beyond the target.
+type LinearParams struct {
+	Fee         *uint256.Int
+	LowerTarget *uint256.Int
+	UpperTarget *uint256.Int
+}
+
+func init() {
+	LinearMath = &linearMath{}
+}
+
+func (l *linearMath) CalcBptOutPerMainIn(
+	mainIn, mainBalance, wrappedBalance, bptSupply *uint256.Int,
+	params *LinearParams,
+) (*uint256.Int, error) {
+	// Amount out, so we round down overall.
+
+	if bptSupply.IsZero() {
+		// BPT typically grows in the same ratio the invariant does. The first time liquidity is added however, the
+		// BPT supply is initialized to equal the invariant (which in this case is just the nominal main balance as
+		// there is no wrapped balance).
+		return l.toNominal(mainIn, params)
+	}
+
+	previousNominalMain, err := l.toNominal(mainBalance, params)
+	if err != nil {
+		return nil, err
+	}
+	newMainBalance, err := FixedPoint.Add(mainBalance, mainIn)
+	if err != nil {
+		return nil, err
+	}
+	afterNominalMain, err := l.toNominal(newMainBalance, params)
+	if err != nil {
+		return nil, err
+	}
+	deltaNominalMain, err := FixedPoint.Sub(afterNominalMain, previousNominalMain)
+	if err != nil {
+		return nil, err
+	}
+	invariant, err := l.calcInvariant(previousNominalMain, wrappedBalance)
+	if err != nil {
+		return nil, err
+	}
+	bptOut, err := Math.Mul(bptSupply, deltaNominalMain)
+	if err != nil {
+		return nil, err
+	}
+
+	return Math.DivDown(bptOut, invariant)
+}
+
+func (l *linearMath) CalcBptInPerMainOut(
+	mainOut, mainBalance, wrappedBalance, bptSupply *uint256.Int,
+	params *LinearParams,
+) (*uint256.Int, error) {
+	// Amount in, so we round up overall.
+
+	previousNominalMain, err := l.toNominal(mainBalance, params)
+	if err != nil {
+		return nil, err
+	}
+	newMainBalance, err := FixedPoint.Sub(mainBalance, mainOut)
+	if err != nil {
+		return nil, err
+	}
+	afterNominalMain, err := l.toNominal(newMainBalance, params)
+	if err != nil {
+		return nil, err
+	}
+	deltaNominalMain, err := FixedPoint.Sub(previousNominalMain, afterNominalMain)
+	if err != nil {
+		return nil, err
+	}
+	invariant, err := l.calcInvariant(previousNominalMain, wrappedBalance)
+	if err != nil {
+		return nil, err
+	}
+	bptIn, err := Math.Mul(bptSupply, deltaNominalMain)
+	if err != nil {
+		return nil, err
+	}
+
+	return Math.DivUp(bptIn, invariant)
+}
+
+func (l *linearMath) CalcWrappedOutPerMainIn(
+	mainIn, mainBalance *uint256.Int,
+	params *LinearParams,
+) (*uint256.Int, error) {
+	// Amount out, so we round down overall.
+
+	previousNominalMain, err := l.toNominal(mainBalance, params)
+	if err != nil {
+		return nil, err
+	}
+	newMainBalance, err := FixedPoint.Add(mainBalance, mainIn)
+	if err != nil {
+		return nil, err
+	}
+	afterNominalMain, err := l.toNominal(newMainBalance, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return FixedPoint.Sub(afterNominalMain, previousNominalMain)
+}
+
+func (l *linearMath) CalcWrappedInPerMainOut(
+	mainOut, mainBalance *uint256.Int,
+	params *LinearParams,
+) (*uint256.Int, error) {
+	// Amount in, so we round up overall.
+
+	previousNominalMain, err := l.toNominal(mainBalance, params)
+	if err != nil {
+		return nil, err
+	}
+	newMainBalance, err := FixedPoint.Sub(mainBalance, mainOut)
+	if err != nil {
+		return nil, err
+	}
+	afterNominalMain, err := l.toNominal(newMainBalance, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return FixedPoint.Sub(previousNominalMain, afterNominalMain)
+}
+
+func (l *linearMath) CalcMainInPerBptOut(
+	bptOut, mainBalance, wrappedBalance, bptSupply *uint256.Int,
+	params *LinearParams,
+) (*uint256.Int, error) {
+	// Amount in, so we round up overall.
+
+	if bptSupply.IsZero() {
+		// BPT typically grows in the same ratio the invariant does. The first time liquidity is added however, the
+		// BPT supply is initialized to equal the invariant (which in this case is just the nominal main balance as
+		// there is no wrapped balance).
+		return l.fromNominal(bptOut, params)
+	}
+
+	previousNominalMain, err := l.toNominal(mainBalance, params)
+	if err != nil {
+		return nil, err
+	}
+	invariant, err := l.calcInvariant(previousNominalMain, wrappedBalance)
+	if err != nil {
+		return nil, err
+	}
+	deltaNominalMain, err := Math.Mul(invariant, bptOut)
+	if err != nil {
+		return nil, err
+	}
+	if deltaNominalMain, err = Math.DivUp(deltaNominalMain, bptSupply); err != nil {
+		return nil, err
+	}
+	afterNominalMain, err := FixedPoint.Add(previousNominalMain, deltaNominalMain)
+	if err != nil {
+		return nil, err
+	}
+	newMainBalance, err := l.fromNominal(afterNominalMain, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return FixedPoint.Sub(newMainBalance, mainBalance)
+}
+
+func (l *linearMath) CalcMainOutPerBptIn(
+	bptIn, mainBalance, wrappedBalance, bptSupply *uint256.Int,
+	params *LinearParams,
+) (*uint256.Int, error) {
+	// Amount out, so we round down overall.
+
+	previousNominalMain, err := l.toNominal(mainBalance, params)
+	if err != nil {
+		return nil, err
+	}
+	invariant, err := l.calcInvariant(previousNominalMain, wrappedBalance)
+	if err != nil {
+		return nil, err
+	}
+	deltaNominalMain, err := Math.Mul(invariant, bptIn)
+	if err != nil {
+		return nil, err
+	}
+	if deltaNominalMain, err = Math.DivDown(deltaNominalMain, bptSupply); err != nil {
+		return nil, err
+	}
+	afterNominalMain, err := FixedPoint.Sub(previousNominalMain, deltaNominalMain)
+	if err != nil {
+		return nil, err
+	}
+	newMainBalance, err := l.fromNominal(afterNominalMain, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return FixedPoint.Sub(mainBalance, newMainBalance)
+}
+
+func (l *linearMath) CalcMainOutPerWrappedIn(
+	wrappedIn, mainBalance *uint256.Int,
+	params *LinearParams,
+) (*uint256.Int, error) {
+	// Amount out, so we round down overall.
+
+	previousNominalMain, err := l.toNominal(mainBalance, params)
+	if err != nil {
+		return nil, err
+	}
+	afterNominalMain, err := FixedPoint.Sub(previousNominalMain, wrappedIn)
+	if err != nil {
+		return nil, err
+	}
+	newMainBalance, err := l.fromNominal(afterNominalMain, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return FixedPoint.Sub(mainBalance, newMainBalance)
+}
+
+func (l *linearMath) CalcMainInPerWrappedOut(
+	wrappedOut, mainBalance *uint256.Int,
+	params *LinearParams,
+) (*uint256.Int, error) {
+	// Amount in, so we round up overall.
+
+	previousNominalMain, err := l.toNominal(mainBalance, params)
+	if err != nil {
+		return nil, err
+	}
+	afterNominalMain, err := FixedPoint.Add(previousNominalMain, wrappedOut)
+	if err != nil {
+		return nil, err
+	}
+	newMainBalance, err := l.fromNominal(afterNominalMain, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return FixedPoint.Sub(newMainBalance, mainBalance)
+}
+
+func (l *linearMath) CalcBptOutPerWrappedIn(
+	wrappedIn, mainBalance, wrappedBalance, bptSupply *uint256.Int,
+	params *LinearParams,
+) (*uint256.Int, error) {
+	// Amount out, so we round down overall.
+
+	if bptSupply.IsZero() {
+		// Return nominal DAI
+		return wrappedIn, nil
+	}
+
+	nominalMain, err := l.toNominal(mainBalance, params)
+	if err != nil {
+		return nil, err
+	}
+	previousInvariant, err := l.calcInvariant(nominalMain, wrappedBalance)
+	if err != nil {
+		return nil, err
+	}
+	newWrappedBalance, err := FixedPoint.Add(wrappedBalance, wrappedIn)
+	if err != nil {
+		return nil, err
+	}
+	newInvariant, err := l.calcInvariant(nominalMain, newWrappedBalance)
+	if err != nil {
+		return nil, err
+	}
+	newBptBalance, err := Math.Mul(bptSupply, newInvariant)
+	if err != nil {
+		return nil, err
+	}
+	if newBptBalance, err = Math.DivDown(newBptBalance, previousInvariant); err != nil {
+		return nil, err
+	}
+
+	return FixedPoint.Sub(newBptBalance, bptSupply)
+}
+
+func (l *linearMath) CalcBptInPerWrappedOut(
+	wrappedOut, mainBalance, wrappedBalance, bptSupply *uint256.Int,
+	params *LinearParams,
+) (*uint256.Int, error) {
+	// Amount in, so we round up overall.
+
+	nominalMain, err := l.toNominal(mainBalance, params)
+	if err != nil {
+		return nil, err
+	}
+	previousInvariant, err := l.calcInvariant(nominalMain, wrappedBalance)
+	if err != nil {
+		return nil, err
+	}
+	newWrappedBalance, err := FixedPoint.Sub(wrappedBalance, wrappedOut)
+	if err != nil {
+		return nil, err
+	}
+	newInvariant, err := l.calcInvariant(nominalMain, newWrappedBalance)
+	if err != nil {
+		return nil, err
+	}
+	newBptBalance, err := Math.Mul(bptSupply, newInvariant)
+	if err != nil {
+		return nil, err
+	}
+	if newBptBalance, err = Math.DivDown(newBptBalance, previousInvariant); err != nil {
+		return nil, err
+	}
+
+	return FixedPoint.Sub(bptSupply, newBptBalance)
+}
+
+func (l *linearMath) CalcWrappedInPerBptOut(
+	bptOut, mainBalance, wrappedBalance, bptSupply *uint256.Int,
+	params *LinearParams,
+) (*uint256.Int, error) {
+	// Amount in, so we round up overall.
+
+	if bptSupply.IsZero() {
+		// Return nominal DAI
+		return bptOut, nil
+	}
+
+	nominalMain, err := l.toNominal(mainBalance, params)
+	if err != nil {
+		return nil, err
+	}
+	previousInvariant, err := l.calcInvariant(nominalMain, wrappedBalance)
+	if err != nil {
+		return nil, err
+	}
+	newBptBalance, err := FixedPoint.Add(bptSupply, bptOut)
+	if err != nil {
+		return nil, err
+	}
+	newWrappedBalance, err := l.calcWrappedBalance(newBptBalance, previousInvariant, bptSupply, nominalMain)
+	if err != nil {
+		return nil, err
+	}
+
+	return FixedPoint.Sub(newWrappedBalance, wrappedBalance)
+}
+
+func (l *linearMath) CalcWrappedOutPerBptIn(
+	bptIn, mainBalance, wrappedBalance, bptSupply *uint256.Int,
+	params *LinearParams,
+) (*uint256.Int, error) {
+	// Amount out, so we round down overall.
+
+	nominalMain, err := l.toNominal(mainBalance, params)
+	if err != nil {
+		return nil, err
+	}
+	previousInvariant, err := l.calcInvariant(nominalMain, wrappedBalance)
+	if err != nil {
+		return nil, err
+	}
+	newBptBalance, err := FixedPoint.Sub(bptSupply, bptIn)
+	if err != nil {
+		return nil, err
+	}
+	newWrappedBalance, err := l.calcWrappedBalance(newBptBalance, previousInvariant, bptSupply, nominalMain)
+	if err != nil {
+		return nil, err
+	}
+
+	return FixedPoint.Sub(wrappedBalance, newWrappedBalance)
+}
+
+// calcWrappedBalance returns divUp(newBptBalance * previousInvariant, bptSupply) - nominalMain.
+func (l *linearMath) calcWrappedBalance(
+	newBptBalance, previousInvariant, bptSupply, nominalMain *uint256.Int,
+) (*uint256.Int, error) {
+	newInvariant, err := Math.Mul(newBptBalance, previousInvariant)
+	if err != nil {
+		return nil, err
+	}
+	if newInvariant, err = Math.DivUp(newInvariant, bptSupply); err != nil {
+		return nil, err
+	}
+
+	return FixedPoint.Sub(newInvariant, nominalMain)
+}
+
+func (l *linearMath) calcInvariant(nominalMainBalance, wrappedBalance *uint256.Int) (*uint256.Int, error) {
+	return FixedPoint.Add(nominalMainBalance, wrappedBalance)
+}
+
+func (l *linearMath) toNominal(real *uint256.Int, params *LinearParams) (*uint256.Int, error) {
+	// Fees are always rounded down: either direction would work but we need to be consistent, and rounding down
+	// uses less gas.
+
+	if real.Lt(params.LowerTarget) {
+		fees, err := FixedPoint.MulDown(new(uint256.Int).Sub(params.LowerTarget, real), params.Fee)
+		if err != nil {
+			return nil, err
+		}
+
+		return FixedPoint.Sub(real, fees)
+	} else if !real.Gt(params.UpperTarget) {
+		return real, nil
+	}
+
+	fees, err := FixedPoint.MulDown(new(uint256.Int).Sub(real, params.UpperTarget), params.Fee)
+	if err != nil {
+		return nil, err
+	}
+
+	return FixedPoint.Sub(real, fees)
+}
+
+func (l *linearMath) fromNominal(nominal *uint256.Int, params *LinearParams) (*uint256.Int, error) {
+	// Since real = nominal + fees, rounding down fees is equivalent to rounding down real.
+
+	if nominal.Lt(params.LowerTarget) {
+		fees, err := FixedPoint.MulDown(params.Fee, params.LowerTarget)
+		if err != nil {
+			return nil, err
+		}
+		real, err := FixedPoint.Add(nominal, fees)
+		if err != nil {
+			return nil, err
+		}
+		onePlusFee, err := FixedPoint.Add(FixedPoint.ONE, params.Fee)
+		if err != nil {
+			return nil, err
+		}
+
+		return FixedPoint.DivDown(real, onePlusFee)
+	} else if !nominal.Gt(params.UpperTarget) {
+		return nominal, nil
+	}
+
+	fees, err := FixedPoint.MulDown(params.Fee, params.UpperTarget)
+	if err != nil {
+		return nil, err
+	}
+	real, err := FixedPoint.Sub(nominal, fees)
+	if err != nil {
+		return nil, err
+	}
+	oneMinusFee, err := FixedPoint.Sub(FixedPoint.ONE, params.Fee)
+	if err != nil {
+		return nil, err
+	}
+
+	return FixedPoint.DivDown(real, oneMinusFee)
+}
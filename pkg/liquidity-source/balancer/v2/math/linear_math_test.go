@@ -0,0 +1,67 @@
+package math
+
+import (
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinearMathNominal(t *testing.T) {
+	t.Parallel()
+	// 1% fee, targets at 1M and 4M
+	params := &LinearParams{
+		Fee:         uint256.MustFromDecimal("10000000000000000"),
+		LowerTarget: uint256.MustFromDecimal("1000000000000000000000000"),
+		UpperTarget: uint256.MustFromDecimal("4000000000000000000000000"),
+	}
+
+	tests := []struct {
+		name    string
+		real    string
+		nominal string
+	}{
+		{"below lower target", "500000000000000000000000", "495000000000000000000000"},
+		{"within targets", "2000000000000000000000000", "2000000000000000000000000"},
+		{"above upper target", "5000000000000000000000000", "4990000000000000000000000"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nominal, err := LinearMath.toNominal(uint256.MustFromDecimal(tc.real), params)
+			require.NoError(t, err)
+			assert.Equal(t, tc.nominal, nominal.Dec())
+
+			real, err := LinearMath.fromNominal(nominal, params)
+			require.NoError(t, err)
+			assert.Equal(t, tc.real, real.Dec())
+		})
+	}
+}
+
+func TestLinearMathMainWrapped(t *testing.T) {
+	t.Parallel()
+	params := &LinearParams{
+		Fee:         uint256.MustFromDecimal("10000000000000000"),
+		LowerTarget: uint256.MustFromDecimal("1000000000000000000000000"),
+		UpperTarget: uint256.MustFromDecimal("4000000000000000000000000"),
+	}
+	mainBalance := uint256.MustFromDecimal("2000000000000000000000000")
+
+	// within the targets main and wrapped trade 1:1 both ways
+	amount := uint256.MustFromDecimal("1000000000000000000000")
+	wrappedOut, err := LinearMath.CalcWrappedOutPerMainIn(amount, mainBalance, params)
+	require.NoError(t, err)
+	assert.Equal(t, amount.Dec(), wrappedOut.Dec())
+
+	mainOut, err := LinearMath.CalcMainOutPerWrappedIn(amount, mainBalance, params)
+	require.NoError(t, err)
+	assert.Equal(t, amount.Dec(), mainOut.Dec())
+
+	// draining main below the lower target costs the fee on the part below it
+	mainOut, err = LinearMath.CalcMainOutPerWrappedIn(uint256.MustFromDecimal("1500000000000000000000000"),
+		mainBalance, params)
+	require.NoError(t, err)
+	assert.Equal(t, "1495049504950495049504951", mainOut.Dec())
+}
@@ -69,6 +69,24 @@ func (l *stableMath) CalcOutGivenIn(
 	return amountOut, nil
 }
 
+// CalcSpotPrice returns the marginal amount of balances[indexOut] out per unit of balances[indexIn] in, before fees,
+// from the partial derivatives of the invariant A·n^n·Σx + D = A·D·n^n + D^(n+1) / (n^n·Πx).
+func (l *stableMath) CalcSpotPrice(
+	invariant *uint256.Int,
+	amp *uint256.Int,
+	balances []*uint256.Int,
+	indexIn int,
+	indexOut int,
+) float64 {
+	d, n := invariant.Float64(), float64(len(balances))
+	ampTimesTotal := amp.Float64() * n / _AMP_PRECISION.Float64()
+	dr := d // D^(n+1) / (n^n·Πx), accumulated factor by factor to stay within float64 range
+	for _, balance := range balances {
+		dr *= d / (n * balance.Float64())
+	}
+	return (ampTimesTotal + dr/balances[indexIn].Float64()) / (ampTimesTotal + dr/balances[indexOut].Float64())
+}
+
 // MetaStable: https://etherscan.io/address/0x063c624672e390363b25f0c6c68ad9067c34595b#code#F30#L152
 //
 // Stable Version 1: https://etherscan.io/address/0x06df3b2bbb68adc8b0e302443692037ed9f91b42#code#F8#L152
@@ -204,6 +204,17 @@ func (l *weightedMath) CalcInGivenOutV1(
 	return FixedPoint.MulUp(balanceIn, ratio)
 }
 
+// CalcSpotPrice returns the marginal amount of tokenOut per unit of tokenIn, before fees:
+// (balanceOut / weightOut) / (balanceIn / weightIn).
+func (l *weightedMath) CalcSpotPrice(
+	balanceIn *uint256.Int,
+	weightIn *uint256.Int,
+	balanceOut *uint256.Int,
+	weightOut *uint256.Int,
+) float64 {
+	return balanceOut.Float64() * weightIn.Float64() / (balanceIn.Float64() * weightOut.Float64())
+}
+
 func (l *weightedMath) CalculateInvariantV1(normalizedWeights, balances []*uint256.Int) (*uint256.Int, error) {
 	invariant := new(uint256.Int).Set(FixedPoint.ONE)
 
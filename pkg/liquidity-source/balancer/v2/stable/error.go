@@ -1,9 +1,13 @@
 package stable
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/vault"
+)
 
 var (
-	ErrTokenNotRegistered = errors.New("TOKEN_NOT_REGISTERED")
+	ErrTokenNotRegistered = vault.ErrTokenNotRegistered
 	ErrInvalidReserve     = errors.New("invalid reserve")
 	ErrInvalidAmountIn    = errors.New("invalid amount in")
 	ErrInvalidAmountOut   = errors.New("invalid amount out")
@@ -7,6 +7,7 @@ import (
 	"github.com/goccy/go-json"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/math"
@@ -423,3 +424,14 @@ func TestCanSwapTo(t *testing.T) {
 		})
 	}
 }
+
+func TestPoolSimulator_GetSpotPrice(t *testing.T) {
+	t.Parallel()
+	poolStr := `{"address":"0x851523a36690bf267bbfec389c823072d82921a9","exchange":"balancer-v2-stable","type":"balancer-v2-stable","timestamp":1703667290,"reserves":["1152882153159026494","873225053252443292"],"tokens":[{"address":"0x7f39c581f595b53c5cb19bd0b3f8da6c935e2ca0","swappable":true},{"address":"0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2","swappable":true}],"extra":"{\"amp\":\"0xf4240\",\"swapFeePercentage\":\"0x16bcc41e90000\",\"scalingFactors\":[\"0xFFB10F9BCF7D41A\",\"0xde0b6b3a7640000\"],\"paused\":false}","staticExtra":"{\"poolId\":\"0x851523a36690bf267bbfec389c823072d82921a90002000000000000000001ed\",\"poolType\":\"MetaStable\",\"poolTypeVersion\":1,\"poolSpecialization\":2,\"vault\":\"0xba12222222228d8ba445958a75a0704d566bf2c8\"}"}`
+	var poolEnt entity.Pool
+	require.NoError(t, json.Unmarshal([]byte(poolStr), &poolEnt))
+	p, err := NewPoolSimulator(poolEnt, nil)
+	require.NoError(t, err)
+
+	testutil.TestSpotPrice(t, p, 1)
+}
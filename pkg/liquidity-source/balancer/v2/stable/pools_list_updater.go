@@ -3,6 +3,7 @@ package stable
 import (
 	"context"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/KyberNetwork/ethrpc"
@@ -135,7 +136,7 @@ func (u *PoolsListUpdater) initPool(subgraphPool *shared.SubgraphPool, vault str
 
 	for j, token := range subgraphPool.PoolTokens {
 		poolTokens[j] = &entity.PoolToken{
-			Address:   token.Address,
+			Address:   strings.ToLower(token.Address),
 			Swappable: token.IsAllowed,
 		}
 		reserves[j] = "0"
@@ -148,9 +149,9 @@ func (u *PoolsListUpdater) initPool(subgraphPool *shared.SubgraphPool, vault str
 			var underlyingTokens = make([]string, 0, len(token.NestedPool.Tokens))
 
 			for _, baseToken := range token.NestedPool.Tokens {
-				underlyingTokens = append(underlyingTokens, baseToken.Address)
+				underlyingTokens = append(underlyingTokens, strings.ToLower(baseToken.Address))
 			}
-			basePools[token.NestedPool.Address] = underlyingTokens
+			basePools[strings.ToLower(token.NestedPool.Address)] = underlyingTokens
 		}
 	}
 
@@ -180,7 +181,7 @@ func (u *PoolsListUpdater) initPool(subgraphPool *shared.SubgraphPool, vault str
 	}
 
 	return entity.Pool{
-		Address:     subgraphPool.Address,
+		Address:     strings.ToLower(subgraphPool.Address),
 		Exchange:    u.config.DexID,
 		Type:        DexType,
 		Timestamp:   time.Now().Unix(),
@@ -0,0 +1,255 @@
+package vault
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/holiman/uint256"
+	"github.com/samber/lo"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/shared"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+var (
+	ErrTokenNotRegistered         = errors.New("TOKEN_NOT_REGISTERED")
+	ErrSameBasePoolSwapNotAllowed = errors.New("swapping between tokens in the same base pool is not allowed")
+)
+
+// BatchSwap simulates a vault batch swap from tokenIn to tokenOut through p. A token that isn't one of p's own is
+// routed through the base pool holding it: its BPT is entered before, or left after, the swap in p.
+func BatchSwap(
+	p shared.IBasePool,
+	basePools map[string]shared.IBasePool,
+	tokenIn, tokenOut string,
+	amountIn *uint256.Int,
+) (*uint256.Int, []shared.Hop, error) {
+	var (
+		basePoolIn, basePoolOut shared.IBasePool
+		bptTokenIn, bptTokenOut = tokenIn, tokenOut
+		err                     error
+	)
+
+	if p.GetTokenIndex(tokenIn) < 0 {
+		if basePoolIn, err = GetBasePool(basePools, tokenIn); err != nil {
+			return nil, nil, err
+		}
+		bptTokenIn = basePoolIn.GetAddress()
+	}
+
+	if p.GetTokenIndex(tokenOut) < 0 {
+		if basePoolOut, err = GetBasePool(basePools, tokenOut); err != nil {
+			return nil, nil, err
+		}
+		bptTokenOut = basePoolOut.GetAddress()
+	}
+
+	if bptTokenIn == bptTokenOut {
+		return nil, nil, ErrSameBasePoolSwapNotAllowed
+	}
+
+	var (
+		hops   = make([]shared.Hop, 0, 3)
+		amount = amountIn
+	)
+
+	if basePoolIn != nil {
+		hop, err := joinBasePool(basePoolIn, tokenIn, amount, len(hops))
+		if err != nil {
+			return nil, nil, err
+		}
+		hops = append(hops, hop)
+		amount = hop.AmountOut
+	}
+
+	amountOut, err := p.OnSwap(bptTokenIn, bptTokenOut, amount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hops = append(hops, shared.Hop{
+		PoolId:    p.GetPoolId(),
+		Pool:      p.GetAddress(),
+		TokenIn:   bptTokenIn,
+		TokenOut:  bptTokenOut,
+		AmountIn:  amount,
+		AmountOut: amountOut,
+	})
+
+	if basePoolOut != nil {
+		hop, err := exitBasePool(basePoolOut, tokenOut, amountOut, len(hops))
+		if err != nil {
+			return nil, nil, err
+		}
+		hops = append(hops, hop)
+		amountOut = hop.AmountOut
+	}
+
+	return amountOut, hops, nil
+}
+
+// joinBasePool swaps tokenIn for the BPT of basePool. Pools holding their own BPT (composable stable, linear) mint it
+// through a regular swap; the others are joined.
+func joinBasePool(basePool shared.IBasePool, tokenIn string, amountIn *uint256.Int, hopIndex int) (shared.Hop, error) {
+	var (
+		bptToken             = basePool.GetAddress()
+		joinIndex, bptAmount *uint256.Int
+		err                  error
+	)
+
+	if isPhantomBpt(basePool) {
+		bptAmount, err = basePool.OnSwap(tokenIn, bptToken, amountIn)
+	} else {
+		bptAmount, err = basePool.OnJoin(tokenIn, amountIn)
+		joinIndex = shared.PackJoinExitIndex(shared.PoolJoin, hopIndex)
+	}
+	if err != nil {
+		return shared.Hop{}, err
+	}
+
+	return shared.Hop{
+		PoolId:        basePool.GetPoolId(),
+		Pool:          bptToken,
+		TokenIn:       tokenIn,
+		TokenOut:      bptToken,
+		AmountIn:      amountIn,
+		AmountOut:     bptAmount,
+		JoinExitIndex: joinIndex,
+	}, nil
+}
+
+// exitBasePool swaps the BPT of basePool for tokenOut, the reverse of joinBasePool.
+func exitBasePool(basePool shared.IBasePool, tokenOut string, bptAmount *uint256.Int, hopIndex int) (shared.Hop, error) {
+	var (
+		bptToken             = basePool.GetAddress()
+		exitIndex, amountOut *uint256.Int
+		err                  error
+	)
+
+	if isPhantomBpt(basePool) {
+		amountOut, err = basePool.OnSwap(bptToken, tokenOut, bptAmount)
+	} else {
+		amountOut, err = basePool.OnExit(tokenOut, bptAmount)
+		exitIndex = shared.PackJoinExitIndex(shared.PoolExit, hopIndex)
+	}
+	if err != nil {
+		return shared.Hop{}, err
+	}
+
+	return shared.Hop{
+		PoolId:        basePool.GetPoolId(),
+		Pool:          bptToken,
+		TokenIn:       bptToken,
+		TokenOut:      tokenOut,
+		AmountIn:      bptAmount,
+		AmountOut:     amountOut,
+		JoinExitIndex: exitIndex,
+	}, nil
+}
+
+// isPhantomBpt reports whether basePool registers its own BPT as one of its tokens.
+func isPhantomBpt(basePool shared.IBasePool) bool {
+	return basePool.GetTokenIndex(basePool.GetAddress()) >= 0
+}
+
+// GetBasePool returns the base pool holding token.
+func GetBasePool(basePools map[string]shared.IBasePool, token string) (shared.IBasePool, error) {
+	for _, basePool := range basePools {
+		if basePool.GetTokenIndex(token) >= 0 {
+			return basePool, nil
+		}
+	}
+
+	return nil, ErrTokenNotRegistered
+}
+
+// UpdateBalance replays the hops of a batch swap, on the base pools they went through and through updateSelf for the
+// others.
+func UpdateBalance(
+	basePools map[string]shared.IBasePool,
+	hops []shared.Hop,
+	updateSelf func(tokenIn, tokenOut string, amountIn, amountOut *big.Int),
+) {
+	for _, hop := range hops {
+		amountIn := hop.AmountIn.ToBig()
+		amountOut := hop.AmountOut.ToBig()
+
+		if basePool, ok := basePools[hop.Pool]; ok {
+			basePool.UpdateBalance(pool.UpdateBalanceParams{
+				TokenAmountIn:  pool.TokenAmount{Token: hop.TokenIn, Amount: amountIn},
+				TokenAmountOut: pool.TokenAmount{Token: hop.TokenOut, Amount: amountOut},
+				SwapInfo:       shared.SwapInfo{},
+			})
+		} else {
+			updateSelf(hop.TokenIn, hop.TokenOut, amountIn, amountOut)
+		}
+	}
+}
+
+// GetTokens returns the tokens of a pool and of its base pools.
+func GetTokens(tokens []string, basePools map[string]shared.IBasePool) []string {
+	tokenSet := make(map[string]struct{})
+
+	for _, basePool := range basePools {
+		for _, token := range basePool.GetTokens() {
+			tokenSet[token] = struct{}{}
+		}
+	}
+
+	for _, token := range tokens {
+		tokenSet[token] = struct{}{}
+	}
+
+	return lo.Keys(tokenSet)
+}
+
+// CanSwapTo returns the tokens reachable from address through a pool with the given tokens and base pools.
+func CanSwapTo(tokens []string, basePools map[string]shared.IBasePool, address string) []string {
+	result := make(map[string]struct{})
+
+	if !lo.Contains(tokens, address) {
+		found := false // Flag to check if any base pool contains the token
+
+		for _, basePool := range basePools {
+			if basePool.GetTokenIndex(address) >= 0 {
+				found = true
+
+				for _, token := range basePool.CanSwapTo(address) {
+					result[token] = struct{}{}
+				}
+			} else {
+				for _, underlyingToken := range basePool.GetTokens() {
+					if underlyingToken != address {
+						result[underlyingToken] = struct{}{}
+					}
+				}
+			}
+		}
+
+		if !found {
+			return []string{}
+		}
+
+		// Add tokens from main pool
+		for _, poolToken := range tokens {
+			result[poolToken] = struct{}{}
+		}
+	} else {
+		// Add tokens from main pool except itself
+		for _, poolToken := range tokens {
+			if poolToken != address {
+				result[poolToken] = struct{}{}
+			}
+		}
+
+		for _, basePool := range basePools {
+			for _, underlyingToken := range basePool.GetTokens() {
+				if underlyingToken != address {
+					result[underlyingToken] = struct{}{}
+				}
+			}
+		}
+	}
+
+	return lo.Keys(result)
+}
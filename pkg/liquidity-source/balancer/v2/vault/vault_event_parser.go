@@ -10,6 +10,7 @@ import (
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/shared"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/events"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/poolfactory"
 )
 
@@ -32,14 +33,7 @@ func NewPoolFactory(config *Config) *EventParser {
 // Ported from the Balancer V3 vault event parser, adapted for V2.
 // https://github.com/KyberNetwork/kyberswap-dex-lib/blob/0b94bbfeae66b43cb64a52308bf52520145ab79c/pkg/liquidity-source/balancer/v3/vault/vault_event_parser.go#L31-L67
 func (p *EventParser) Decode(ctx context.Context, logs []types.Log) (map[string][]types.Log, error) {
-	addressLogs := make(map[string][]types.Log)
-	for _, log := range logs {
-		addresses, _ := p.DecodePoolAddressesFromFactoryLog(ctx, log)
-		for _, address := range addresses {
-			addressLogs[address] = append(addressLogs[address], log)
-		}
-	}
-	return addressLogs, nil
+	return events.Decode(ctx, logs, p.DecodePoolAddressesFromFactoryLog)
 }
 
 // DecodePoolAddressesFromFactoryLog extracts the affected pool address from a V2 Vault log.
@@ -52,7 +46,7 @@ func (p *EventParser) Decode(ctx context.Context, logs []types.Log) (map[string]
 // Ported/mirrored from V3 DecodePoolAddressesFromFactoryLog:
 // https://github.com/KyberNetwork/kyberswap-dex-lib/blob/0b94bbfeae66b43cb64a52308bf52520145ab79c/pkg/liquidity-source/balancer/v3/vault/vault_event_parser.go#L42-L67
 func (p *EventParser) DecodePoolAddressesFromFactoryLog(_ context.Context, log types.Log) ([]string, error) {
-	if log.Address != common.HexToAddress(p.config.Vault) {
+	if len(log.Topics) == 0 || log.Address != common.HexToAddress(p.config.Vault) {
 		return nil, nil
 	}
 	switch log.Topics[0] {
@@ -7,19 +7,18 @@ import (
 	"github.com/KyberNetwork/blockchain-toolkit/number"
 	"github.com/goccy/go-json"
 	"github.com/holiman/uint256"
-	"github.com/samber/lo"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
-	composablestable "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/composable-stable"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/math"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/shared"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/vault"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 )
 
 var (
-	ErrSameBasePoolSwapNotAllowed = errors.New("swapping between tokens in the same base pool is not allowed")
-	ErrTokenNotRegistered         = errors.New("TOKEN_NOT_REGISTERED")
+	ErrSameBasePoolSwapNotAllowed = vault.ErrSameBasePoolSwapNotAllowed
+	ErrTokenNotRegistered         = vault.ErrTokenNotRegistered
 	ErrInvalidReserve             = errors.New("invalid reserve")
 	ErrInvalidAmountIn            = errors.New("invalid amount in")
 	ErrPoolPaused                 = errors.New("pool is paused")
@@ -325,192 +324,12 @@ func (s *PoolSimulator) swapDirect(tokenIn, tokenOut string, amountIn *uint256.I
 	return s.buildSwapResult(tokenOut, amountOut, nil), nil
 }
 
-func (s *PoolSimulator) swapFromBase2Main(tokenIn, tokenOut string, amountIn *uint256.Int) (*pool.CalcAmountOutResult, error) {
-	basePool, err := s.getBasePool(tokenIn)
+func (s *PoolSimulator) swapBatch(tokenIn, tokenOut string, amountIn *uint256.Int) (*pool.CalcAmountOutResult, error) {
+	amountOut, hops, err := vault.BatchSwap(s, s.basePools, tokenIn, tokenOut, amountIn)
 	if err != nil {
 		return nil, err
 	}
 
-	bptToken := basePool.GetAddress()
-
-	var (
-		hops                 = make([]shared.Hop, 0, 2)
-		joinIndex, bptAmount *uint256.Int
-	)
-
-	switch basePool.GetType() {
-	case composablestable.DexType:
-		bptAmount, err = basePool.OnSwap(tokenIn, bptToken, amountIn)
-	default:
-		bptAmount, err = basePool.OnJoin(tokenIn, amountIn)
-		joinIndex = shared.PackJoinExitIndex(shared.PoolJoin, len(hops))
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	hops = append(hops, shared.Hop{
-		PoolId:        basePool.GetPoolId(),
-		Pool:          basePool.GetAddress(),
-		TokenIn:       tokenIn,
-		TokenOut:      bptToken,
-		AmountIn:      amountIn,
-		AmountOut:     bptAmount,
-		JoinExitIndex: joinIndex,
-	})
-
-	amountOut, err := s.OnSwap(bptToken, tokenOut, bptAmount)
-	if err != nil {
-		return nil, err
-	}
-
-	hops = append(hops, shared.Hop{
-		PoolId:    s.GetPoolId(),
-		Pool:      s.GetAddress(),
-		TokenIn:   bptToken,
-		TokenOut:  tokenOut,
-		AmountIn:  bptAmount,
-		AmountOut: amountOut,
-	})
-
-	return s.buildSwapResult(tokenOut, amountOut, hops), nil
-}
-
-func (s *PoolSimulator) swapFromMain2Base(tokenIn, tokenOut string, amountIn *uint256.Int) (*pool.CalcAmountOutResult, error) {
-	basePool, err := s.getBasePool(tokenOut)
-	if err != nil {
-		return nil, err
-	}
-
-	bptToken := basePool.GetAddress()
-
-	var hops = make([]shared.Hop, 0, 2)
-
-	bptAmount, err := s.OnSwap(tokenIn, bptToken, amountIn)
-	if err != nil {
-		return nil, err
-	}
-
-	hops = append(hops, shared.Hop{
-		PoolId:    s.poolID,
-		Pool:      s.GetAddress(),
-		TokenIn:   tokenIn,
-		TokenOut:  bptToken,
-		AmountIn:  amountIn,
-		AmountOut: bptAmount,
-	})
-
-	var (
-		exitIndex, amountOut *uint256.Int
-	)
-
-	switch basePool.GetType() {
-	case composablestable.DexType:
-		amountOut, err = basePool.OnSwap(bptToken, tokenOut, bptAmount)
-	default:
-		amountOut, err = basePool.OnExit(tokenOut, bptAmount)
-		exitIndex = shared.PackJoinExitIndex(shared.PoolExit, len(hops))
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	hops = append(hops, shared.Hop{
-		PoolId:        basePool.GetPoolId(),
-		Pool:          basePool.GetAddress(),
-		TokenIn:       bptToken,
-		TokenOut:      tokenOut,
-		AmountIn:      bptAmount,
-		AmountOut:     amountOut,
-		JoinExitIndex: exitIndex,
-	})
-
-	return s.buildSwapResult(tokenOut, amountOut, hops), nil
-}
-
-func (s *PoolSimulator) swapBetweenBasePools(tokenIn, tokenOut string, amountIn *uint256.Int) (*pool.CalcAmountOutResult, error) {
-	basePoolIn, err := s.getBasePool(tokenIn)
-	if err != nil {
-		return nil, err
-	}
-
-	basePoolOut, err := s.getBasePool(tokenOut)
-	if err != nil {
-		return nil, err
-	}
-
-	bptTokenIn := basePoolIn.GetAddress()
-	bptTokenOut := basePoolOut.GetAddress()
-
-	if bptTokenIn == bptTokenOut {
-		return nil, ErrSameBasePoolSwapNotAllowed
-	}
-
-	var (
-		hops                   = make([]shared.Hop, 0, 3)
-		joinIndex, bptAmountIn *uint256.Int
-	)
-
-	switch basePoolIn.GetType() {
-	case composablestable.DexType:
-		bptAmountIn, err = basePoolIn.OnSwap(tokenIn, bptTokenIn, amountIn)
-	default:
-		bptAmountIn, err = basePoolIn.OnJoin(tokenIn, amountIn)
-		joinIndex = shared.PackJoinExitIndex(shared.PoolJoin, len(hops))
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	hops = append(hops, shared.Hop{
-		PoolId:        basePoolIn.GetPoolId(),
-		Pool:          basePoolIn.GetAddress(),
-		TokenIn:       tokenIn,
-		TokenOut:      bptTokenIn,
-		AmountIn:      amountIn,
-		AmountOut:     bptAmountIn,
-		JoinExitIndex: joinIndex,
-	})
-
-	bptAmountOut, err := s.OnSwap(bptTokenIn, bptTokenOut, bptAmountIn)
-	if err != nil {
-		return nil, err
-	}
-
-	hops = append(hops, shared.Hop{
-		PoolId:    s.poolID,
-		Pool:      s.GetAddress(),
-		TokenIn:   bptTokenIn,
-		TokenOut:  bptTokenOut,
-		AmountIn:  bptAmountIn,
-		AmountOut: bptAmountOut,
-	})
-
-	var (
-		exitIndex, amountOut *uint256.Int
-	)
-
-	switch basePoolOut.GetType() {
-	case composablestable.DexType:
-		amountOut, err = basePoolOut.OnSwap(bptTokenOut, tokenOut, bptAmountOut)
-	default:
-		amountOut, err = basePoolOut.OnExit(tokenOut, bptAmountOut)
-		exitIndex = shared.PackJoinExitIndex(shared.PoolExit, len(hops))
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	hops = append(hops, shared.Hop{
-		PoolId:        basePoolOut.GetPoolId(),
-		Pool:          basePoolOut.GetAddress(),
-		TokenIn:       bptTokenOut,
-		TokenOut:      tokenOut,
-		AmountIn:      bptAmountOut,
-		AmountOut:     amountOut,
-		JoinExitIndex: exitIndex,
-	})
-
 	return s.buildSwapResult(tokenOut, amountOut, hops), nil
 }
 
@@ -542,15 +361,31 @@ func (s *PoolSimulator) buildSwapResult(tokenOut string, amountOut *uint256.Int,
 	}
 }
 
-func (s *PoolSimulator) getBasePool(token string) (shared.IBasePool, error) {
-	for _, basePool := range s.basePools {
-		index := basePool.GetTokenIndex(token)
-		if index >= 0 {
-			return basePool, nil
-		}
+// GetSpotPrice returns the marginal price of the weighted invariant at the current balances, net of the swap fee. The
+// scaling factors cancel out. Swaps through base pools are not priced analytically.
+func (s *PoolSimulator) GetSpotPrice(tokenIn, tokenOut string) (float64, error) {
+	if s.paused {
+		return 0, ErrPoolPaused
+	}
+
+	indexIn, indexOut := s.GetTokenIndex(tokenIn), s.GetTokenIndex(tokenOut)
+	if indexIn < 0 || indexOut < 0 {
+		return 0, pool.ErrSpotPriceUnsupported
 	}
 
-	return nil, ErrTokenNotRegistered
+	balanceIn, overflow := uint256.FromBig(s.Info.Reserves[indexIn])
+	if overflow {
+		return 0, ErrInvalidReserve
+	}
+
+	balanceOut, overflow := uint256.FromBig(s.Info.Reserves[indexOut])
+	if overflow {
+		return 0, ErrInvalidReserve
+	}
+
+	price := math.WeightedMath.CalcSpotPrice(balanceIn, s.normalizedWeights[indexIn], balanceOut,
+		s.normalizedWeights[indexOut])
+	return price * (1 - s.swapFeePercentage.Float64()/1e18), nil
 }
 
 // https://etherscan.io/address/0x065f5b35d4077334379847fe26f58b1029e51161#code#F7#L32
@@ -575,15 +410,7 @@ func (s *PoolSimulator) CalcAmountOut(params pool.CalcAmountOutParams) (*pool.Ca
 		return s.swapDirect(tokenAmountIn.Token, tokenOut, amountIn)
 	}
 
-	if indexIn < 0 && indexOut >= 0 {
-		return s.swapFromBase2Main(tokenAmountIn.Token, tokenOut, amountIn)
-	}
-
-	if indexIn >= 0 {
-		return s.swapFromMain2Base(tokenAmountIn.Token, tokenOut, amountIn)
-	}
-
-	return s.swapBetweenBasePools(tokenAmountIn.Token, tokenOut, amountIn)
+	return s.swapBatch(tokenAmountIn.Token, tokenOut, amountIn)
 }
 
 func (s *PoolSimulator) CalcAmountIn(params pool.CalcAmountInParams) (*pool.CalcAmountInResult, error) {
@@ -784,25 +611,7 @@ func (s *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
 		return
 	}
 
-	for _, hop := range swapInfo.Hops {
-		amountIn := hop.AmountIn.ToBig()
-		amountOut := hop.AmountOut.ToBig()
-
-		if basePool, ok := s.basePools[hop.Pool]; ok {
-			basePool.UpdateBalance(pool.UpdateBalanceParams{
-				TokenAmountIn: pool.TokenAmount{
-					Token:  hop.TokenIn,
-					Amount: amountIn,
-				},
-				TokenAmountOut: pool.TokenAmount{
-					Token:  hop.TokenOut,
-					Amount: amountOut,
-				},
-			})
-		} else {
-			s.updateBalance(hop.TokenIn, hop.TokenOut, amountIn, amountOut)
-		}
-	}
+	vault.UpdateBalance(s.basePools, swapInfo.Hops, s.updateBalance)
 }
 
 func (s *PoolSimulator) updateBalance(tokenIn, tokenOut string, amountIn, amountOut *big.Int) {
@@ -847,71 +656,11 @@ func (s *PoolSimulator) CanSwapFrom(address string) []string {
 }
 
 func (s *PoolSimulator) CanSwapTo(address string) []string {
-	result := make(map[string]struct{})
-	var tokenIndex = s.GetTokenIndex(address)
-
-	if tokenIndex < 0 {
-		found := false // Flag to check if any base pool contains the token
-
-		for _, basePool := range s.basePools {
-			if basePool.GetTokenIndex(address) >= 0 {
-				found = true
-
-				for _, token := range basePool.CanSwapTo(address) {
-					result[token] = struct{}{}
-				}
-			} else {
-				for _, underlyingToken := range basePool.GetTokens() {
-					if underlyingToken != address {
-						result[underlyingToken] = struct{}{}
-					}
-				}
-			}
-		}
-
-		if !found {
-			return []string{}
-		}
-
-		// Add tokens from main pool
-		for _, poolToken := range s.Info.Tokens {
-			result[poolToken] = struct{}{}
-		}
-	} else {
-
-		// Add tokens from main pool except itself
-		for _, poolToken := range s.Info.Tokens {
-			if poolToken != address {
-				result[poolToken] = struct{}{}
-			}
-		}
-
-		for _, basePool := range s.basePools {
-			for _, underlyingToken := range basePool.GetTokens() {
-				if underlyingToken != address {
-					result[underlyingToken] = struct{}{}
-				}
-			}
-		}
-	}
-
-	return lo.Keys(result)
+	return vault.CanSwapTo(s.Info.Tokens, s.basePools, address)
 }
 
 func (s *PoolSimulator) GetTokens() []string {
-	tokenSet := make(map[string]struct{})
-
-	for _, basePool := range s.basePools {
-		for _, token := range basePool.GetTokens() {
-			tokenSet[token] = struct{}{}
-		}
-	}
-
-	for _, token := range s.GetInfo().Tokens {
-		tokenSet[token] = struct{}{}
-	}
-
-	return lo.Keys(tokenSet)
+	return vault.GetTokens(s.Info.Tokens, s.basePools)
 }
 
 func (s *PoolSimulator) GetBasePools() []pool.IPoolSimulator {
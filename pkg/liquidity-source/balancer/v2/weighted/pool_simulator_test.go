@@ -7,9 +7,12 @@ import (
 	"github.com/goccy/go-json"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/shared"
 	poolpkg "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/testutil"
 )
 
@@ -767,3 +770,60 @@ func TestPoolSimulator_CalcAmountIn(t *testing.T) {
 		})
 	}
 }
+
+func TestPoolSimulator_GetSpotPrice(t *testing.T) {
+	t.Parallel()
+	poolStr := `{"address":"0x5c6ee304399dbdb9c8ef030ab642b10820db8f56","exchange":"balancer-v2-weighted","type":"balancer-v2-weighted","timestamp":1702542461,"reserves":["31686717298564222587034828","14236767788701850247952"],"tokens":[{"address":"0xba100000625a3754423978a60c9317c58a424e3d","swappable":true},{"address":"0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2","swappable":true}],"extra":"{\"swapFeePercentage\":\"0x2386f26fc10000\",\"paused\":false}","staticExtra":"{\"poolId\":\"0x5c6ee304399dbdb9c8ef030ab642b10820db8f56000200000000000000000014\",\"poolType\":\"Weighted\",\"poolTypeVer\":1,\"scalingFactors\":[\"0x1\",\"0x1\"],\"normalizedWeights\":[\"0xb1a2bc2ec500000\",\"0x2c68af0bb140000\"],\"vault\":\"0xba12222222228d8ba445958a75a0704d566bf2c8\"}"}`
+	var poolEnt entity.Pool
+	require.NoError(t, json.Unmarshal([]byte(poolStr), &poolEnt))
+	p, err := NewPoolSimulator(poolEnt, nil)
+	require.NoError(t, err)
+
+	testutil.TestSpotPrice(t, p, 1)
+}
+
+func TestPoolSimulator_UpdateBalance_Nested(t *testing.T) {
+	t.Parallel()
+	// a weighted pool of another weighted pool's BPT and DAI; USDC is swapped in by joining the base pool
+	const (
+		usdc        = "0x1111111111111111111111111111111111111111"
+		basePool    = "0x3333333333333333333333333333333333333333"
+		dai         = "0x4444444444444444444444444444444444444444"
+		basePoolStr = `{"address":"0x3333333333333333333333333333333333333333","exchange":"balancer-v2-weighted","type":"balancer-v2-weighted","reserves":["1000000000000000000000000","1000000000000000000000000"],"tokens":[{"address":"0x1111111111111111111111111111111111111111","swappable":true},{"address":"0x2222222222222222222222222222222222222222","swappable":true}],"extra":"{\"swapFeePercentage\":\"1000000000000000\",\"protocolSwapFeePercentage\":\"0\",\"totalSupply\":\"2000000000000000000000000\",\"paused\":false}","staticExtra":"{\"poolId\":\"0x3333333333333333333333333333333333333333000000000000000000000001\",\"poolType\":\"Weighted\",\"poolTypeVer\":1,\"scalingFactors\":[\"1\",\"1\"],\"normalizedWeights\":[\"500000000000000000\",\"500000000000000000\"],\"vault\":\"0xba12222222228d8ba445958a75a0704d566bf2c8\"}"}`
+		poolStr     = `{"address":"0x7777777777777777777777777777777777777777","exchange":"balancer-v2-weighted","type":"balancer-v2-weighted","reserves":["1000000000000000000000000","1000000000000000000000000"],"tokens":[{"address":"0x3333333333333333333333333333333333333333","swappable":true},{"address":"0x4444444444444444444444444444444444444444","swappable":true}],"extra":"{\"swapFeePercentage\":\"1000000000000000\",\"protocolSwapFeePercentage\":\"0\",\"totalSupply\":\"2000000000000000000000000\",\"paused\":false}","staticExtra":"{\"poolId\":\"0x7777777777777777777777777777777777777777000000000000000000000002\",\"poolType\":\"Weighted\",\"poolTypeVer\":1,\"scalingFactors\":[\"1\",\"1\"],\"normalizedWeights\":[\"500000000000000000\",\"500000000000000000\"],\"vault\":\"0xba12222222228d8ba445958a75a0704d566bf2c8\",\"batchSwapEnabled\":true,\"basePools\":{\"0x3333333333333333333333333333333333333333\":[\"0x1111111111111111111111111111111111111111\",\"0x2222222222222222222222222222222222222222\"]}}"}`
+	)
+
+	var baseEntity, entityPool entity.Pool
+	require.NoError(t, json.Unmarshal([]byte(basePoolStr), &baseEntity))
+	require.NoError(t, json.Unmarshal([]byte(poolStr), &entityPool))
+	base, err := NewPoolSimulator(baseEntity, nil)
+	require.NoError(t, err)
+	s, err := NewPoolSimulator(entityPool, map[string]poolpkg.IPoolSimulator{basePool: base})
+	require.NoError(t, err)
+
+	amountIn := bignumber.NewBig10("1000000000000000000000")
+	result, err := s.CalcAmountOut(poolpkg.CalcAmountOutParams{
+		TokenAmountIn: poolpkg.TokenAmount{Token: usdc, Amount: amountIn},
+		TokenOut:      dai,
+	})
+	require.NoError(t, err)
+	swapInfo, ok := result.SwapInfo.(shared.SwapInfo)
+	require.True(t, ok)
+	require.Len(t, swapInfo.Hops, 2)
+	assert.Equal(t, []string{basePool, entityPool.Address}, []string{swapInfo.Hops[0].Pool, swapInfo.Hops[1].Pool})
+	bptAmount := swapInfo.Hops[0].AmountOut.ToBig()
+
+	s.UpdateBalance(poolpkg.UpdateBalanceParams{
+		TokenAmountIn:  poolpkg.TokenAmount{Token: usdc, Amount: amountIn},
+		TokenAmountOut: *result.TokenAmountOut,
+		SwapInfo:       result.SwapInfo,
+	})
+
+	// the base pool got the USDC of the join, the pool the BPT it minted
+	assert.Equal(t, "1001000000000000000000000", base.GetReserves()[0].String())
+	assert.Equal(t, "1000000000000000000000000", base.GetReserves()[1].String())
+	assert.Equal(t, new(big.Int).Add(bignumber.NewBig10("1000000000000000000000000"), bptAmount).String(),
+		s.GetReserves()[0].String())
+	assert.Equal(t, new(big.Int).Sub(bignumber.NewBig10("1000000000000000000000000"), result.TokenAmountOut.Amount).String(),
+		s.GetReserves()[1].String())
+}
@@ -161,9 +161,9 @@ func (u *PoolsListUpdater) initPool(subgraphPool *shared.SubgraphPool, vault str
 			var underlyingTokens = make([]string, 0, len(token.NestedPool.Tokens))
 
 			for _, baseToken := range token.NestedPool.Tokens {
-				underlyingTokens = append(underlyingTokens, baseToken.Address)
+				underlyingTokens = append(underlyingTokens, strings.ToLower(baseToken.Address))
 			}
-			basePools[token.NestedPool.Address] = underlyingTokens
+			basePools[strings.ToLower(token.NestedPool.Address)] = underlyingTokens
 		}
 	}
 
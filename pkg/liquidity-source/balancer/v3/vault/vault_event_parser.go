@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/events"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/poolfactory"
 )
 
@@ -29,18 +30,11 @@ func NewPoolFactory(config *Config) *EventParser {
 }
 
 func (p *EventParser) Decode(ctx context.Context, logs []types.Log) (map[string][]types.Log, error) {
-	addressLogs := make(map[string][]types.Log)
-	for _, log := range logs {
-		addresses, _ := p.DecodePoolAddressesFromFactoryLog(ctx, log)
-		for _, address := range addresses {
-			addressLogs[address] = append(addressLogs[address], log)
-		}
-	}
-	return addressLogs, nil
+	return events.Decode(ctx, logs, p.DecodePoolAddressesFromFactoryLog)
 }
 
 func (p *EventParser) DecodePoolAddressesFromFactoryLog(_ context.Context, log types.Log) ([]string, error) {
-	if log.Address != common.HexToAddress(p.config.Vault) {
+	if len(log.Topics) == 0 || log.Address != common.HexToAddress(p.config.Vault) {
 		return nil, nil
 	}
 	switch log.Topics[0] {
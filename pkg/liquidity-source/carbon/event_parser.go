@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/events"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/poolfactory"
 )
 
@@ -27,22 +28,7 @@ func NewPoolFactory(config *Config, ethrpcClient *ethrpc.Client) *EventParser {
 }
 
 func (ep *EventParser) Decode(ctx context.Context, logs []types.Log) (map[string][]types.Log, error) {
-	addressLogs := make(map[string][]types.Log)
-
-	for _, log := range logs {
-		poolAddresses, err := ep.DecodePoolAddressesFromFactoryLog(ctx, log)
-		if err != nil {
-			return nil, err
-		}
-
-		for _, poolAddress := range poolAddresses {
-			if poolAddress != "" {
-				addressLogs[poolAddress] = append(addressLogs[poolAddress], log)
-			}
-		}
-	}
-
-	return addressLogs, nil
+	return events.Decode(ctx, logs, ep.DecodePoolAddressesFromFactoryLog)
 }
 
 func (ep *EventParser) DecodePoolAddressesFromFactoryLog(ctx context.Context, log types.Log) ([]string, error) {
@@ -297,7 +297,8 @@ func (t *PoolTracker) fetchStrategiesByIDs(
 		endIndex := min(offset+maxStrategiesPerBatch, len(ids))
 		chunk := ids[offset:endIndex]
 
-		raws := make([]StrategyByPairResp, len(chunk))
+		// strategy returns a single tuple, which abi unpacks into the first field of a struct
+		raws := make([]struct{ Strategy StrategyByPairResp }, len(chunk))
 		req := t.ethrpcClient.R().SetContext(ctx).SetBlockNumber(blockNumber)
 		for i, id := range chunk {
 			req.AddCall(&ethrpc.Call{
@@ -312,7 +313,7 @@ func (t *PoolTracker) fetchStrategiesByIDs(
 		}
 
 		for _, raw := range raws {
-			strategies = append(strategies, mapRawStrategy(raw, token0))
+			strategies = append(strategies, mapRawStrategy(raw.Strategy, token0))
 		}
 	}
 
@@ -3,6 +3,8 @@ package clipper
 import (
 	"context"
 	"math/big"
+	"strconv"
+	"time"
 
 	"github.com/goccy/go-json"
 	"github.com/pkg/errors"
@@ -59,6 +61,10 @@ func (h *RFQHandler) RFQ(ctx context.Context, params pool.RFQParams) (*pool.RFQR
 	}
 
 	newAmountOut, _ := new(big.Int).SetString(result.OutputAmount, 10)
+	var expiresAt time.Time
+	if goodUntil, err := strconv.ParseInt(result.GoodUntil, 10, 64); err == nil {
+		expiresAt = time.Unix(goodUntil, 0)
+	}
 
 	return &pool.RFQResult{
 		NewAmountOut: newAmountOut,
@@ -68,6 +74,7 @@ func (h *RFQHandler) RFQ(ctx context.Context, params pool.RFQParams) (*pool.RFQR
 			S:         result.Signature.S,
 			GoodUntil: result.GoodUntil,
 		},
+		ExpiresAt: expiresAt,
 	}, nil
 }
 
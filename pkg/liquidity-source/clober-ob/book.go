@@ -0,0 +1,82 @@
+package cloberob
+
+import (
+	"github.com/KyberNetwork/int256"
+	"github.com/holiman/uint256"
+
+	cloberlib "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/clober-ob/libraries"
+	u256 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/clob"
+)
+
+// tickOrders are the ticks of a book as clob.Orders, each taken the way BookManager.take does: by whole units of the
+// quote, at the tick price rounded in favor of the book, with the taker fee charged per tick.
+type tickOrders struct {
+	depths      []Liquidity
+	takerPolicy cloberlib.FeePolicy
+	unitSize    *uint256.Int
+	// fee is the taker fee charged over the walk, in the quote if the policy uses it, in the base otherwise.
+	fee uint256.Int
+}
+
+func (o *tickOrders) Len() int {
+	return len(o.depths)
+}
+
+func (o *tickOrders) FillIn(i int, amountIn *uint256.Int) (clob.Level, bool, error) {
+	tick := o.depths[i].Tick
+	if tick < int24Min {
+		return clob.Level{}, true, nil
+	}
+	if err := cloberlib.ValidateTick(tick); err != nil {
+		return clob.Level{}, false, err
+	}
+
+	maxBase := amountIn
+	if !o.takerPolicy.UsesQuote() {
+		maxBase = o.takerPolicy.CalculateOriginalAmount(amountIn, false)
+	}
+	maxQuote, err := cloberlib.BaseToQuote(tick, maxBase, false)
+	if err != nil {
+		return clob.Level{}, false, err
+	}
+	units := maxQuote.Div(maxQuote, o.unitSize)
+	if units.IsZero() {
+		return clob.Level{}, true, nil
+	}
+	if depth := uint256.NewInt(o.depths[i].Depth); depth.Lt(units) {
+		units = depth
+	}
+
+	quoteAmount := new(uint256.Int).Mul(units, o.unitSize)
+	baseAmount, err := cloberlib.QuoteToBase(tick, quoteAmount, true)
+	if err != nil {
+		return clob.Level{}, false, err
+	}
+
+	var signed int256.Int
+	if o.takerPolicy.UsesQuote() {
+		fee := o.takerPolicy.CalculateFee(quoteAmount, false)
+		signed.SetFromBig(quoteAmount.ToBig())
+		quoteAmount.SetFromBig(signed.Sub(&signed, fee).ToBig())
+		o.addFee(fee)
+	} else {
+		fee := o.takerPolicy.CalculateFee(baseAmount, false)
+		signed.SetFromBig(baseAmount.ToBig())
+		baseAmount.SetFromBig(signed.Add(&signed, fee).ToBig())
+		o.addFee(fee)
+	}
+	if baseAmount.IsZero() {
+		return clob.Level{}, true, nil
+	}
+
+	return clob.Level{In: baseAmount, Out: quoteAmount, Lots: units}, false, nil
+}
+
+// addFee accounts for the taker fee of a tick, a rebate if negative, by its absolute value.
+func (o *tickOrders) addFee(fee *int256.Int) {
+	if fee.Sign() < 0 {
+		fee = new(int256.Int).Neg(fee)
+	}
+	o.fee.Add(&o.fee, u256.FromBig(fee.ToBig()))
+}
@@ -3,6 +3,7 @@ package cloberob
 import (
 	"github.com/ethereum/go-ethereum/common"
 
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/reorg"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
 )
 
@@ -14,4 +15,5 @@ type Config struct {
 	AllowSubgraphError bool                `json:"allowSubgraphError"`
 	BookManager        common.Address      `json:"bookManager"`
 	BookViewer         common.Address      `json:"bookViewer"`
+	Reorg              reorg.Config        `json:"reorg"`
 }
@@ -1,20 +1,20 @@
 package cloberob
 
 import (
+	"errors"
 	"math/big"
 	"slices"
 	"strings"
 
-	"github.com/KyberNetwork/int256"
 	"github.com/goccy/go-json"
 	"github.com/holiman/uint256"
 	"github.com/samber/lo"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
-	cloberlib "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/clober-ob/libraries"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
 	u256 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/clob"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
 )
 
@@ -64,154 +64,43 @@ func (s *PoolSimulator) CalcAmountOut(params pool.CalcAmountOutParams) (*pool.Ca
 		return nil, ErrInvalidToken
 	}
 
-	takenQuoteAmount, spentBaseAmount, fee, tickIdx, err := getExpectedOutput(u256.U0, amountIn, s.Depths, s.TakerPolicy, s.unitSize)
-	if err != nil {
+	orders := s.orders()
+	fill, err := clob.FillOrdersExactIn(orders, amountIn)
+	if errors.Is(err, clob.ErrEmptyBook) {
+		return nil, ErrNoLiquidity
+	} else if err != nil {
 		return nil, err
 	}
 
 	return &pool.CalcAmountOutResult{
 		TokenAmountOut: &pool.TokenAmount{
 			Token:  s.Info.Tokens[indexOut],
-			Amount: takenQuoteAmount.ToBig(),
+			Amount: fill.AmountOut.ToBig(),
 		},
 		Fee: &pool.TokenAmount{
 			Token:  lo.Ternary(s.TakerPolicy.UsesQuote(), tokenOut, tokenIn),
-			Amount: fee.ToBig(),
+			Amount: orders.fee.ToBig(),
 		},
-		Gas: defaultBaseGas + defaultTakeGas*(int64(tickIdx)+1),
+		Gas: defaultBaseGas + defaultTakeGas*int64(fill.Walked),
 		SwapInfo: SwapInfo{
-			SpentBaseAmount: spentBaseAmount,
+			SpentBaseAmount: fill.AmountIn,
 			LimitPrice:      u256.U0,
 		},
 	}, nil
 }
 
-func getExpectedOutput(
-	limitPrice, pBaseAmount *uint256.Int,
-	depths []Liquidity, takerPolicy cloberlib.FeePolicy, unitSize *uint256.Int) (
-	*uint256.Int, *uint256.Int, *uint256.Int, int, error) {
-	var takenQuoteAmount, spentBaseAmount, feeAmount uint256.Int
-
-	if len(depths) == 0 {
-		return nil, nil, nil, 0, ErrNoLiquidity
-	}
-
-	tempU, tempI := new(uint256.Int), new(int256.Int)
-	tick, tickIndex := depths[0].Tick, 0
-
-	for !spentBaseAmount.Gt(pBaseAmount) && tick >= int24Min {
-		tickToPrice, err := cloberlib.ToPrice(tick)
-		if err != nil {
-			return nil, nil, nil, 0, err
-		}
-
-		if limitPrice.Gt(tickToPrice) {
-			break
-		}
-
-		var maxAmount uint256.Int
-		if takerPolicy.UsesQuote() {
-			maxAmount.Sub(pBaseAmount, &spentBaseAmount)
-		} else {
-			maxAmount.Set(takerPolicy.CalculateOriginalAmount(tempU.Sub(pBaseAmount, &spentBaseAmount), false))
-		}
-
-		tempU, err = cloberlib.BaseToQuote(tick, &maxAmount, false)
-		if err != nil {
-			return nil, nil, nil, 0, err
-		}
-		maxAmount.Div(tempU, unitSize)
-
-		if maxAmount.IsZero() {
-			break
-		}
-
-		currentDepth := new(uint256.Int).SetUint64(depths[tickIndex].Depth)
-		quoteAmount := new(uint256.Int)
-		if currentDepth.Gt(&maxAmount) {
-			quoteAmount.Mul(&maxAmount, unitSize)
-		} else {
-			quoteAmount.Mul(currentDepth, unitSize)
-		}
-
-		baseAmount, err := cloberlib.QuoteToBase(tick, quoteAmount, true)
-		if err != nil {
-			return nil, nil, nil, 0, err
-		}
-
-		if takerPolicy.UsesQuote() {
-			tempI.SetFromBig(quoteAmount.ToBig())
-			fee := takerPolicy.CalculateFee(quoteAmount, false)
-			tempI.Sub(tempI, fee)
-			quoteAmount.SetFromBig(tempI.ToBig())
-
-			if fee.Sign() > 0 {
-				feeAmount.Add(&feeAmount, u256.FromBig(fee.ToBig()))
-			} else {
-				feeAmount.Add(&feeAmount, u256.FromBig(fee.Neg(fee).ToBig()))
-			}
-		} else {
-			tempI.SetFromBig(baseAmount.ToBig())
-			fee := takerPolicy.CalculateFee(baseAmount, false)
-			tempI.Add(tempI, fee)
-			baseAmount.SetFromBig(tempI.ToBig())
-
-			if fee.Sign() > 0 {
-				feeAmount.Add(&feeAmount, u256.FromBig(fee.ToBig()))
-			} else {
-				feeAmount.Add(&feeAmount, u256.FromBig(fee.Neg(fee).ToBig()))
-			}
-		}
-
-		if baseAmount.IsZero() {
-			break
-		}
-
-		takenQuoteAmount.Add(&takenQuoteAmount, quoteAmount)
-		spentBaseAmount.Add(&spentBaseAmount, baseAmount)
-
-		if tickIndex+1 >= len(depths) {
-			break
-		}
-
-		tickIndex++
-		tick = depths[tickIndex].Tick
-	}
-
-	return &takenQuoteAmount, &spentBaseAmount, &feeAmount, tickIndex, nil
+func (s *PoolSimulator) orders() *tickOrders {
+	return &tickOrders{depths: s.Depths, takerPolicy: s.TakerPolicy, unitSize: s.unitSize}
 }
 
+// UpdateBalance takes the units filled by the swap off the depths, walking them again with its amount in.
 func (s *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
-	swapInfo, ok := params.SwapInfo.(SwapInfo)
-	if !ok {
+	fill, err := clob.FillOrdersExactIn(s.orders(), uint256.MustFromBig(params.TokenAmountIn.Amount))
+	if err != nil {
 		return
 	}
-
-	spentBaseAmount := swapInfo.SpentBaseAmount.Clone()
-	for i := 0; i < len(s.Depths) && spentBaseAmount.Sign() > 0; i++ {
-		depth := &s.Depths[i]
-		tick := depth.Tick
-
-		quoteAmount, err := cloberlib.BaseToQuote(tick, spentBaseAmount, false)
-		if err != nil {
-			return
-		}
-
-		units := new(uint256.Int).Div(quoteAmount, s.unitSize)
-
-		currentDepth := uint256.NewInt(depth.Depth)
-		if !currentDepth.Gt(units) {
-			depth.Depth = 0
-			takenAmount := new(uint256.Int).Mul(currentDepth, s.unitSize)
-			spentAmount, err := cloberlib.QuoteToBase(tick, takenAmount, true)
-			if err != nil {
-				return
-			}
-			spentBaseAmount.Sub(spentBaseAmount, spentAmount)
-		} else {
-			depth.Depth -= units.Uint64()
-			spentBaseAmount.Clear()
-		}
+	for _, part := range fill.Parts {
+		s.Depths[part.Index].Depth -= part.Lots.Uint64()
 	}
 
 	highestIdx := 0
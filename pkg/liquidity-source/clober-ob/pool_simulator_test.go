@@ -0,0 +1,207 @@
+package cloberob
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/KyberNetwork/int256"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	cloberlib "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/clober-ob/libraries"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	u256 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
+)
+
+func takerPolicy(usesQuote bool, rate uint32) cloberlib.FeePolicy {
+	policy := cloberlib.FeePolicy(rate + 500000)
+	if usesQuote {
+		policy |= 1 << 23
+	}
+	return policy
+}
+
+func testPool(t *testing.T, policy cloberlib.FeePolicy, depths []Liquidity) *PoolSimulator {
+	extra := fmt.Sprintf(`{"depths":[%s]}`, joinDepths(depths))
+	staticExtra := fmt.Sprintf(`{"unitSize":1000000000000,"takerPolicy":%d}`, policy)
+	sim, err := NewPoolSimulator(entity.Pool{
+		Address:     "0xbook",
+		Exchange:    DexType,
+		Type:        DexType,
+		Tokens:      []*entity.PoolToken{{Address: "base", Decimals: 18}, {Address: "quote", Decimals: 18}},
+		Reserves:    []string{"0", "0"},
+		Extra:       extra,
+		StaticExtra: staticExtra,
+	})
+	require.NoError(t, err)
+	return sim
+}
+
+func joinDepths(depths []Liquidity) string {
+	var s string
+	for i, depth := range depths {
+		if i > 0 {
+			s += ","
+		}
+		s += fmt.Sprintf(`{"tick":%d,"depth":%d}`, depth.Tick, depth.Depth)
+	}
+	return s
+}
+
+func randomDepths(rng *rand.Rand) []Liquidity {
+	depths := make([]Liquidity, 1+rng.Intn(20))
+	tick := cloberlib.Tick(rng.Intn(2000) - 1000)
+	for i := range depths {
+		depths[i] = Liquidity{Tick: tick, Depth: 1 + uint64(rng.Int63n(1e6))}
+		tick -= 1 + cloberlib.Tick(rng.Intn(50))
+	}
+	return depths
+}
+
+// The walk over pkg/util/clob must take the book exactly as the loop it replaced did.
+func TestDifferentialGetExpectedOutput(t *testing.T) {
+	t.Parallel()
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		policy := takerPolicy(rng.Intn(2) == 0, uint32(rng.Intn(3000)))
+		depths := randomDepths(rng)
+		sim := testPool(t, policy, depths)
+		amountIn := new(big.Int).Rand(rng, big.NewInt(1e18))
+		amountIn.Mul(amountIn, big.NewInt(1+rng.Int63n(1e6)))
+
+		t.Run(fmt.Sprintf("book %d", i), func(t *testing.T) {
+			expectedOut, expectedIn, expectedFee, _, err := legacyGetExpectedOutput(u256.U0,
+				uint256.MustFromBig(amountIn), depths, policy, uint256.NewInt(1e12))
+			require.NoError(t, err)
+
+			res, err := sim.CalcAmountOut(pool.CalcAmountOutParams{
+				TokenAmountIn: pool.TokenAmount{Token: "base", Amount: amountIn},
+				TokenOut:      "quote",
+			})
+			require.NoError(t, err)
+			assert.Equal(t, expectedOut.Dec(), res.TokenAmountOut.Amount.String())
+			assert.Equal(t, expectedIn.Dec(), res.SwapInfo.(SwapInfo).SpentBaseAmount.Dec())
+			assert.Equal(t, expectedFee.Dec(), res.Fee.Amount.String())
+		})
+	}
+}
+
+func TestPoolSimulator_UpdateBalance(t *testing.T) {
+	t.Parallel()
+	sim := testPool(t, takerPolicy(true, 100), []Liquidity{{Tick: 10, Depth: 5}, {Tick: 0, Depth: 5}, {Tick: -10, Depth: 5}})
+	params := pool.CalcAmountOutParams{
+		TokenAmountIn: pool.TokenAmount{Token: "base", Amount: big.NewInt(7e12)},
+		TokenOut:      "quote",
+	}
+	res, err := sim.CalcAmountOut(params)
+	require.NoError(t, err)
+	assert.Equal(t, defaultBaseGas+3*defaultTakeGas, res.Gas, "the dust left walks the third tick")
+
+	cloned := sim.CloneState().(*PoolSimulator)
+	cloned.UpdateBalance(pool.UpdateBalanceParams{TokenAmountIn: params.TokenAmountIn, SwapInfo: res.SwapInfo})
+	assert.Equal(t, []Liquidity{{Tick: 0, Depth: 3}, {Tick: -10, Depth: 5}}, cloned.Depths)
+	assert.Len(t, sim.Depths, 3, "the original book is left untouched")
+
+	_, err = testPool(t, takerPolicy(true, 100), nil).CalcAmountOut(params)
+	assert.ErrorIs(t, err, ErrNoLiquidity)
+}
+
+// legacyGetExpectedOutput is the tick loop the simulator used before walking the book with pkg/util/clob.
+func legacyGetExpectedOutput(
+	limitPrice, pBaseAmount *uint256.Int,
+	depths []Liquidity, takerPolicy cloberlib.FeePolicy, unitSize *uint256.Int) (
+	*uint256.Int, *uint256.Int, *uint256.Int, int, error) {
+	var takenQuoteAmount, spentBaseAmount, feeAmount uint256.Int
+
+	if len(depths) == 0 {
+		return nil, nil, nil, 0, ErrNoLiquidity
+	}
+
+	tempU, tempI := new(uint256.Int), new(int256.Int)
+	tick, tickIndex := depths[0].Tick, 0
+
+	for !spentBaseAmount.Gt(pBaseAmount) && tick >= int24Min {
+		tickToPrice, err := cloberlib.ToPrice(tick)
+		if err != nil {
+			return nil, nil, nil, 0, err
+		}
+
+		if limitPrice.Gt(tickToPrice) {
+			break
+		}
+
+		var maxAmount uint256.Int
+		if takerPolicy.UsesQuote() {
+			maxAmount.Sub(pBaseAmount, &spentBaseAmount)
+		} else {
+			maxAmount.Set(takerPolicy.CalculateOriginalAmount(tempU.Sub(pBaseAmount, &spentBaseAmount), false))
+		}
+
+		tempU, err = cloberlib.BaseToQuote(tick, &maxAmount, false)
+		if err != nil {
+			return nil, nil, nil, 0, err
+		}
+		maxAmount.Div(tempU, unitSize)
+
+		if maxAmount.IsZero() {
+			break
+		}
+
+		currentDepth := new(uint256.Int).SetUint64(depths[tickIndex].Depth)
+		quoteAmount := new(uint256.Int)
+		if currentDepth.Gt(&maxAmount) {
+			quoteAmount.Mul(&maxAmount, unitSize)
+		} else {
+			quoteAmount.Mul(currentDepth, unitSize)
+		}
+
+		baseAmount, err := cloberlib.QuoteToBase(tick, quoteAmount, true)
+		if err != nil {
+			return nil, nil, nil, 0, err
+		}
+
+		if takerPolicy.UsesQuote() {
+			tempI.SetFromBig(quoteAmount.ToBig())
+			fee := takerPolicy.CalculateFee(quoteAmount, false)
+			tempI.Sub(tempI, fee)
+			quoteAmount.SetFromBig(tempI.ToBig())
+
+			if fee.Sign() > 0 {
+				feeAmount.Add(&feeAmount, u256.FromBig(fee.ToBig()))
+			} else {
+				feeAmount.Add(&feeAmount, u256.FromBig(fee.Neg(fee).ToBig()))
+			}
+		} else {
+			tempI.SetFromBig(baseAmount.ToBig())
+			fee := takerPolicy.CalculateFee(baseAmount, false)
+			tempI.Add(tempI, fee)
+			baseAmount.SetFromBig(tempI.ToBig())
+
+			if fee.Sign() > 0 {
+				feeAmount.Add(&feeAmount, u256.FromBig(fee.ToBig()))
+			} else {
+				feeAmount.Add(&feeAmount, u256.FromBig(fee.Neg(fee).ToBig()))
+			}
+		}
+
+		if baseAmount.IsZero() {
+			break
+		}
+
+		takenQuoteAmount.Add(&takenQuoteAmount, quoteAmount)
+		spentBaseAmount.Add(&spentBaseAmount, baseAmount)
+
+		if tickIndex+1 >= len(depths) {
+			break
+		}
+
+		tickIndex++
+		tick = depths[tickIndex].Tick
+	}
+
+	return &takenQuoteAmount, &spentBaseAmount, &feeAmount, tickIndex, nil
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
 	cloberlib "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/clober-ob/libraries"
 	poolpkg "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/reorg"
 	pooltrack "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/tracker"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/eth"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/graphql"
@@ -27,6 +28,7 @@ type PoolTracker struct {
 	config        *Config
 	ethrpcClient  *ethrpc.Client
 	graphqlClient *graphql.Client
+	reorgGuard    *reorg.Guard
 }
 
 var _ = pooltrack.RegisterFactoryCEG(DexType, NewPoolTracker)
@@ -36,6 +38,7 @@ func NewPoolTracker(config *Config, ethrpcClient *ethrpc.Client, graphqlClient *
 		config:        config,
 		ethrpcClient:  ethrpcClient,
 		graphqlClient: graphqlClient,
+		reorgGuard:    reorg.NewGuard(config.Reorg),
 	}, nil
 }
 
@@ -93,6 +96,10 @@ func (t *PoolTracker) BootstrapPoolState(
 }
 
 func (t *PoolTracker) GetNewPoolState(ctx context.Context, p entity.Pool, param poolpkg.GetNewPoolStateParams) (entity.Pool, error) {
+	return t.reorgGuard.GetNewPoolState(ctx, p, param, t.applyLogs, t.BootstrapPoolState)
+}
+
+func (t *PoolTracker) applyLogs(ctx context.Context, p entity.Pool, param poolpkg.GetNewPoolStateParams) (entity.Pool, error) {
 	l := logger.WithFields(logger.Fields{
 		"pool":  p.Address,
 		"dexId": t.config.DexId,
@@ -0,0 +1,50 @@
+package cryptoswap
+
+import (
+	"errors"
+
+	"github.com/KyberNetwork/blockchain-toolkit/i256"
+	"github.com/holiman/uint256"
+)
+
+const (
+	// MaxLoopLimit is the iteration cap of every Newton loop, same as `range(255)` in the contracts.
+	MaxLoopLimit = 255
+)
+
+var (
+	U_1e3  = uint256.NewInt(1e3)
+	U_1260 = uint256.NewInt(1260)
+	U_1e6  = uint256.NewInt(1e6)
+	U_1e12 = uint256.NewInt(1e12)
+	U_1e14 = uint256.NewInt(1e14)
+	U_1e16 = uint256.NewInt(1e16)
+	U_1e18 = uint256.NewInt(1e18)
+	U_2e18 = uint256.NewInt(2e18)
+	U_1e20 = uint256.MustFromDecimal("100000000000000000000")
+	U_1e36 = uint256.MustFromDecimal("1000000000000000000000000000000000000")
+
+	AMultiplier = uint256.NewInt(10000)
+
+	// MinFrac and MaxFrac bound x[i] * 10**18 / D once newton_D or newton_y has converged.
+	MinFrac = U_1e16
+	MaxFrac = U_1e20
+
+	CbrtConst1 = uint256.MustFromDecimal("115792089237316195423570985008687907853269000000000000000000")
+	CbrtConst2 = uint256.MustFromDecimal("115792089237316195423570985008687907853269")
+
+	wadExpMin   = i256.MustFromDecimal("-42139678854452767551")
+	wadExpMax   = i256.MustFromDecimal("135305999368893231589")
+	wadExpScale = uint256.MustFromDecimal("3822833074963236453042738258902158003155416615667")
+)
+
+var (
+	ErrZero             = errors.New("zero")
+	ErrUnsafeD          = errors.New("unsafe values D")
+	ErrUnsafeXi         = errors.New("unsafe values x[i]")
+	ErrUnsafeY          = errors.New("unsafe value for y")
+	ErrDDoesNotConverge = errors.New("d does not converge")
+	ErrYDoesNotConverge = errors.New("y does not converge")
+	ErrDidNotConverge   = errors.New("did not converge")
+	ErrWadExpOverflow   = errors.New("wad_exp overflow")
+)
@@ -0,0 +1,556 @@
+// Package cryptoswap is the CryptoSwap invariant math shared by every Curve crypto pool simulator: the legacy v1
+// two/tricrypto pools as well as tricrypto-ng and twocrypto-ng.
+// Input validation that differs between contract versions (A, gamma, x[0] and D bounds) is left to the callers,
+// while the Newton iterations themselves live here. Functions suffixed with 2 are the forms collapsed for 2 coins
+// used by twocrypto-ng, which round differently from the generic N-coin forms.
+package cryptoswap
+
+import (
+	"github.com/KyberNetwork/blockchain-toolkit/i256"
+	"github.com/KyberNetwork/blockchain-toolkit/number"
+	"github.com/KyberNetwork/int256"
+	"github.com/holiman/uint256"
+)
+
+// Sort sorts x in descending order in place.
+func Sort(x []uint256.Int) {
+	for i := 1; i < len(x); i++ {
+		for j := i; j > 0 && x[j-1].Lt(&x[j]); j-- {
+			x[j-1], x[j] = x[j], x[j-1]
+		}
+	}
+}
+
+// GeometricMean calculates the geometric mean of 2 or 3 numbers into result, as in the ng contracts.
+func GeometricMean(x []uint256.Int, result *uint256.Int) {
+	if len(x) == 2 {
+		result.Sqrt(result.Mul(&x[0], &x[1]))
+		return
+	}
+
+	prod := number.Div(number.SafeMul(number.Div(number.SafeMul(&x[0], &x[1]), U_1e18), &x[2]), U_1e18)
+	if prod.IsZero() {
+		result.Clear()
+		return
+	}
+	Cbrt(prod, result)
+}
+
+// IterativeGeometricMean calculates the geometric mean of x, sorted in descending order, into D with Newton's
+// method as the v1 contracts do.
+// https://github.com/curvefi/curve-crypto-contract/blob/d7d04cd/contracts/tricrypto/CurveCryptoMath3.vy#L60
+func IterativeGeometricMean(x []uint256.Int, D *uint256.Int) error {
+	var nCoins, nCoinsSub1, DPrev, tmp uint256.Int
+	nCoins.SetUint64(uint64(len(x)))
+	nCoinsSub1.SetUint64(uint64(len(x) - 1))
+	D.Set(&x[0])
+	for range MaxLoopLimit {
+		DPrev.Set(D)
+		tmp.Set(U_1e18)
+		for i := range x {
+			tmp.Div(number.SafeMul(&tmp, &x[i]), D)
+		}
+		// D = D * ((N_COINS - 1) * 10**18 + tmp) / (N_COINS * 10**18)
+		D.Div(
+			number.SafeMul(D, number.SafeAdd(number.Mul(&nCoinsSub1, U_1e18), &tmp)),
+			number.Mul(&nCoins, U_1e18),
+		)
+		tmp.Sub(D, &DPrev)
+		if D.Lt(&DPrev) {
+			tmp.Sub(&DPrev, D)
+		}
+		if tmp.CmpUint64(1) <= 0 || number.SafeMul(&tmp, U_1e18).Lt(D) {
+			return nil
+		}
+	}
+	return ErrDidNotConverge
+}
+
+// SqrtInt calculates sqrt(x * 10**18) into result with the Babylonian method of the v1 contracts.
+func SqrtInt(x *uint256.Int, result *uint256.Int) error {
+	if x.IsZero() {
+		result.Clear()
+		return nil
+	}
+	var z, xe18 uint256.Int
+	z.Div(number.SafeAdd(x, U_1e18), number.Number_2)
+	xe18.Set(number.SafeMul(x, U_1e18))
+	result.Set(x)
+	for range 256 {
+		if z.Eq(result) {
+			return nil
+		}
+		result.Set(&z)
+		z.Div(number.SafeAdd(number.Div(&xe18, &z), &z), number.Number_2)
+	}
+	return ErrDidNotConverge
+}
+
+// HalfPow calculates 0.5 ** (power / 10**18) into result, stopping once the series term is below precision.
+// https://github.com/curvefi/curve-crypto-contract/blob/d7d04cd/contracts/tricrypto/CurveCryptoSwap.vy#L230
+func HalfPow(power, precision *uint256.Int, result *uint256.Int) error {
+	var intpow, otherpow uint256.Int
+	intpow.Div(power, U_1e18)
+	otherpow.Sub(power, number.Mul(&intpow, U_1e18))
+	if intpow.CmpUint64(59) > 0 {
+		result.Clear()
+		return nil
+	}
+	result.Rsh(U_1e18, uint(intpow.Uint64()))
+	if otherpow.IsZero() {
+		return nil
+	}
+
+	var term, S, K, c uint256.Int
+	x := uint256.NewInt(5e17)
+	term.Set(U_1e18)
+	S.Set(U_1e18)
+	neg := false
+	for i := uint64(1); i < 256; i++ {
+		K.Mul(K.SetUint64(i), U_1e18)
+		c.Sub(&K, U_1e18)
+		if otherpow.Gt(&c) {
+			c.Sub(&otherpow, &c)
+			neg = !neg
+		} else {
+			c.Sub(&c, &otherpow)
+		}
+		term.Div(number.SafeMul(&term, number.Div(number.SafeMul(&c, x), U_1e18)), &K)
+		if neg {
+			number.SafeSubZ(&S, &term, &S)
+		} else {
+			number.SafeAddZ(&S, &term, &S)
+		}
+		if term.Lt(precision) {
+			result.Div(number.SafeMul(result, &S), U_1e18)
+			return nil
+		}
+	}
+	return ErrDidNotConverge
+}
+
+// NewtonD finds the invariant D of x, sorted in descending order, via Newton's method from the initial guess
+// already in D. ANN is A * N**N scaled by AMultiplier. x[i] * 10**18 / D is left to the caller to validate.
+// https://github.com/curvefi/tricrypto-ng/blob/d6df8a9/contracts/main/CurveCryptoMathOptimized3.vy#L319
+func NewtonD(ANN, gamma *uint256.Int, x []uint256.Int, D *uint256.Int) error {
+	var nCoins uint256.Int
+	nCoins.SetUint64(uint64(len(x)))
+	return newtonD(ANN, gamma, x, &nCoins, D, func(K0 *uint256.Int) {
+		// K0 = 10**18 * x[0] * N_COINS / D * x[1] * N_COINS / D * ...
+		K0.Set(U_1e18)
+		for i := range x {
+			K0.Div(number.Mul(number.Mul(K0, &x[i]), &nCoins), D)
+		}
+	})
+}
+
+// NewtonD2 is NewtonD with K0 collapsed for 2 coins, as in twocrypto-ng.
+// https://github.com/curvefi/twocrypto-ng/blob/d21b270/contracts/main/CurveCryptoMathOptimized2.vy#L376
+func NewtonD2(ANN, gamma *uint256.Int, x []uint256.Int, D *uint256.Int) error {
+	var nCoins, scale uint256.Int
+	nCoins.SetUint64(2)
+	scale.Mul(U_1e18, number.Number_4)
+	return newtonD(ANN, gamma, x, &nCoins, D, func(K0 *uint256.Int) {
+		// K0 = (10**18 * N_COINS**2) * x[0] / D * x[1] / D
+		K0.Div(number.Mul(K0.Div(number.Mul(&scale, &x[0]), D), &x[1]), D)
+	})
+}
+
+func newtonD(ANN, gamma *uint256.Int, x []uint256.Int, nCoins, D *uint256.Int, k0 func(K0 *uint256.Int)) error {
+	var S uint256.Int
+	for i := range x {
+		S.Add(&S, &x[i])
+	}
+
+	var g1k0Base, DPrev, K0, g1k0, diff uint256.Int
+	g1k0Base.Add(gamma, U_1e18)
+	for range MaxLoopLimit {
+		if D.IsZero() {
+			return ErrUnsafeD
+		}
+		DPrev.Set(D)
+
+		k0(&K0)
+		if K0.IsZero() {
+			return ErrZero
+		}
+
+		// if _g1k0 > K0: _g1k0 = _g1k0 - K0 + 1 else: _g1k0 = K0 - _g1k0 + 1
+		if g1k0Base.Gt(&K0) {
+			g1k0.AddUint64(g1k0.Sub(&g1k0Base, &K0), 1)
+		} else {
+			g1k0.AddUint64(g1k0.Sub(&K0, &g1k0Base), 1)
+		}
+
+		// mul1 = 10**18 * D / gamma * _g1k0 / gamma * _g1k0 * A_MULTIPLIER / ANN
+		mul1 := number.Div(
+			number.Mul(
+				number.Mul(
+					number.Div(number.Mul(number.Div(number.Mul(U_1e18, D), gamma), &g1k0), gamma),
+					&g1k0,
+				),
+				AMultiplier,
+			),
+			ANN,
+		)
+
+		// mul2 = (2 * 10**18) * N_COINS * K0 / _g1k0
+		mul2 := number.Div(number.Mul(number.SafeMul(U_2e18, nCoins), &K0), &g1k0)
+
+		// neg_fprime = (S + S * mul2 / 10**18) + mul1 * N_COINS / K0 - mul2 * D / 10**18
+		negFprime := number.Sub(
+			number.Add(
+				number.Add(&S, number.Div(number.Mul(&S, mul2), U_1e18)),
+				number.Div(number.Mul(mul1, nCoins), &K0),
+			),
+			number.Div(number.Mul(mul2, D), U_1e18),
+		)
+		if negFprime.IsZero() {
+			return ErrZero
+		}
+
+		// D_plus = D * (neg_fprime + S) / neg_fprime
+		DPlus := number.Div(number.SafeMul(D, number.Add(negFprime, &S)), negFprime)
+		// D_minus = D * D / neg_fprime
+		DMinus := number.Div(number.SafeMul(D, D), negFprime)
+		// D_minus +-= D * (mul1 / neg_fprime) / 10**18 * |10**18 - K0| / K0
+		if U_1e18.Gt(&K0) {
+			DMinus = number.SafeAdd(DMinus, number.Div(
+				number.Mul(number.Div(number.SafeMul(D, number.Div(mul1, negFprime)), U_1e18), number.Sub(U_1e18, &K0)),
+				&K0,
+			))
+		} else {
+			DMinus = number.SafeSub(DMinus, number.Div(
+				number.Mul(number.Div(number.SafeMul(D, number.Div(mul1, negFprime)), U_1e18), number.Sub(&K0, U_1e18)),
+				&K0,
+			))
+		}
+		if DPlus.Gt(DMinus) {
+			D.Sub(DPlus, DMinus)
+		} else {
+			D.Div(D.Sub(DMinus, DPlus), number.Number_2)
+		}
+
+		if D.Gt(&DPrev) {
+			diff.Sub(D, &DPrev)
+		} else {
+			diff.Sub(&DPrev, D)
+		}
+		// diff * 10**14 < max(10**16, D)
+		limit := U_1e16
+		if D.Gt(U_1e16) {
+			limit = D
+		}
+		if number.Mul(&diff, U_1e14).Lt(limit) {
+			return nil
+		}
+	}
+	return ErrDDoesNotConverge
+}
+
+// NewtonY calculates x[i] into y given the other balances of x and the invariant D, for any number of coins.
+// https://github.com/curvefi/tricrypto-ng/blob/d6df8a9/contracts/main/CurveCryptoMathOptimized3.vy#L253
+func NewtonY(ann, gamma *uint256.Int, x []uint256.Int, D *uint256.Int, i int, y *uint256.Int) error {
+	numTokens := len(x)
+	var nCoins, K0i, Si uint256.Int
+	nCoins.SetUint64(uint64(numTokens))
+	K0i.Set(U_1e18)
+
+	var xSorted [8]uint256.Int
+	copy(xSorted[:numTokens], x)
+	xSorted[i].Clear()
+	Sort(xSorted[:numTokens])
+
+	// convergence_limit = max(max(x_sorted[0] / 10**14, D / 10**14), 100)
+	convergenceLimit := maxConvergenceLimit(&xSorted[0], D)
+
+	y.Div(D, &nCoins)
+	for j := 2; j < numTokens+1; j++ {
+		_x := &xSorted[numTokens-j]
+		if _x.IsZero() {
+			return ErrZero
+		}
+		y.Div(number.SafeMul(y, D), number.SafeMul(_x, &nCoins))
+		Si.Add(&Si, _x)
+	}
+	for j := range numTokens - 1 {
+		K0i.Div(number.SafeMul(number.SafeMul(&K0i, &xSorted[j]), &nCoins), D)
+	}
+
+	if err := newtonY(ann, gamma, D, &nCoins, &K0i, &Si, convergenceLimit, y); err != nil {
+		return err
+	}
+	frac := number.Div(number.SafeMul(y, U_1e18), D)
+	if frac.Lt(MinFrac) || frac.Gt(MaxFrac) {
+		return ErrUnsafeY
+	}
+	return nil
+}
+
+// NewtonY2 is NewtonY collapsed for 2 coins, as twocrypto-ng's _newton_y. limMul bounds K0_i and the caller is
+// expected to check the resulting y against it.
+// https://github.com/curvefi/twocrypto-ng/blob/d21b270/contracts/main/CurveCryptoMathOptimized2.vy#L143
+func NewtonY2(ann, gamma *uint256.Int, x []uint256.Int, D *uint256.Int, i int, limMul *uint256.Int,
+	y *uint256.Int) error {
+	var nCoins, nCoins2 uint256.Int
+	nCoins.SetUint64(2)
+	nCoins2.SetUint64(4)
+
+	xj := &x[1-i]
+	y.Div(number.Mul(D, D), number.Mul(xj, &nCoins2))
+	K0i := number.Div(number.Mul(number.Mul(U_1e18, &nCoins), xj), D)
+	// assert (K0_i >= unsafe_div(10**36, lim_mul)) and (K0_i <= lim_mul)  # dev: unsafe values x[i]
+	if K0i.Lt(number.Div(U_1e36, limMul)) || K0i.Gt(limMul) {
+		return ErrUnsafeXi
+	}
+
+	return newtonY(ann, gamma, D, &nCoins, K0i, xj, maxConvergenceLimit(xj, D), y)
+}
+
+func maxConvergenceLimit(x0, D *uint256.Int) *uint256.Int {
+	convergenceLimit := number.Div(x0, U_1e14)
+	if temp := number.Div(D, U_1e14); temp.Gt(convergenceLimit) {
+		convergenceLimit = temp
+	}
+	if convergenceLimit.CmpUint64(100) < 0 {
+		convergenceLimit.SetUint64(100)
+	}
+	return convergenceLimit
+}
+
+// newtonY runs the newton_y iteration from the initial guess in y, given K0_i and S_i of the other coins.
+func newtonY(ann, gamma, D, nCoins, K0i, Si, convergenceLimit, y *uint256.Int) error {
+	var yPrev, K0, S, g1k0, mul1, yfprime, diff uint256.Int
+	De18 := number.SafeMul(D, U_1e18)
+
+	for range MaxLoopLimit {
+		yPrev.Set(y)
+		K0.Div(number.SafeMul(number.SafeMul(K0i, y), nCoins), D)
+		S.Add(Si, y)
+
+		g1k0.Add(gamma, U_1e18)
+		if g1k0.Gt(&K0) {
+			number.SafeAddZ(number.SafeSub(&g1k0, &K0), number.Number_1, &g1k0)
+		} else {
+			number.SafeAddZ(number.SafeSub(&K0, &g1k0), number.Number_1, &g1k0)
+		}
+
+		// mul1 = 10**18 * D / gamma * _g1k0 / gamma * _g1k0 * A_MULTIPLIER / ANN
+		mul1.Div(
+			number.SafeMul(
+				number.Div(number.SafeMul(number.Div(De18, gamma), &g1k0), gamma),
+				number.SafeMul(&g1k0, AMultiplier),
+			),
+			ann,
+		)
+
+		// mul2 = 10**18 + (2 * 10**18) * K0 / _g1k0
+		mul2 := number.SafeAdd(U_1e18, number.Div(number.SafeMul(U_2e18, &K0), &g1k0))
+
+		// yfprime = 10**18 * y + S * mul2 + mul1
+		number.SafeAddZ(number.SafeAdd(number.SafeMul(U_1e18, y), number.SafeMul(&S, mul2)), &mul1, &yfprime)
+		dyfprime := number.SafeMul(D, mul2)
+		if yfprime.Lt(dyfprime) {
+			y.Div(&yPrev, number.Number_2)
+			continue
+		}
+		yfprime.Sub(&yfprime, dyfprime)
+
+		if y.IsZero() {
+			return ErrZero
+		}
+		fprime := number.Div(&yfprime, y)
+		if fprime.IsZero() {
+			return ErrZero
+		}
+
+		yMinus := number.Div(&mul1, fprime)
+		yPlus := number.SafeAdd(
+			number.Div(number.SafeAdd(&yfprime, De18), fprime),
+			number.Div(number.SafeMul(yMinus, U_1e18), &K0),
+		)
+		number.SafeAddZ(yMinus, number.Div(number.SafeMul(U_1e18, &S), fprime), yMinus)
+		if yPlus.Lt(yMinus) {
+			y.Div(&yPrev, number.Number_2)
+		} else {
+			y.Sub(yPlus, yMinus)
+		}
+
+		if y.Gt(&yPrev) {
+			diff.Sub(y, &yPrev)
+		} else {
+			diff.Sub(&yPrev, y)
+		}
+		// diff < max(convergence_limit, y / 10**14)
+		limit := number.Div(y, U_1e14)
+		if convergenceLimit.Gt(limit) {
+			limit = convergenceLimit
+		}
+		if diff.Lt(limit) {
+			return nil
+		}
+	}
+	return ErrYDoesNotConverge
+}
+
+// ReductionCoefficient calculates into K the fee reduction coefficient of x for any number of coins:
+// feeGamma / (feeGamma + (1 - K)), where K = prod(x) / (sum(x) / N)**N.
+// https://github.com/curvefi/tricrypto-ng/blob/d6df8a9/contracts/main/CurveCryptoMathOptimized3.vy#L124
+func ReductionCoefficient(x []uint256.Int, feeGamma *uint256.Int, K *uint256.Int) error {
+	var S, nCoins uint256.Int
+	nCoins.SetUint64(uint64(len(x)))
+	for i := range x {
+		number.SafeAddZ(&S, &x[i], &S)
+	}
+	if S.IsZero() {
+		return ErrZero
+	}
+
+	K.Set(U_1e18)
+	for i := range x {
+		K.Div(number.SafeMul(number.SafeMul(K, &nCoins), &x[i]), &S)
+	}
+	if !feeGamma.IsZero() {
+		K.Div(number.SafeMul(feeGamma, U_1e18), number.SafeSub(number.SafeAdd(feeGamma, U_1e18), K))
+	}
+	return nil
+}
+
+// ReductionCoefficient2 is ReductionCoefficient collapsed for 2 coins, as in twocrypto-ng.
+// https://github.com/curvefi/twocrypto-ng/blob/d21b270/contracts/main/CurveTwocryptoOptimized.vy#L1365
+func ReductionCoefficient2(x []uint256.Int, feeGamma *uint256.Int, K *uint256.Int) error {
+	var S uint256.Int
+	number.SafeAddZ(&x[0], &x[1], &S)
+	if S.IsZero() {
+		return ErrZero
+	}
+
+	// K = 10**18 * N_COINS**2 * x[0] / S * x[1] / S
+	K.Mul(U_1e18, number.Number_4)
+	K.Div(number.SafeMul(K, &x[0]), &S)
+	K.Div(number.SafeMul(K, &x[1]), &S)
+
+	K.Div(number.SafeMul(feeGamma, U_1e18), number.SafeSub(number.SafeAdd(feeGamma, U_1e18), K))
+	return nil
+}
+
+// Cbrt calculates the cube root of x scaled by 10**18 (cbrt(x * 10**36)) into result.
+// https://github.com/curvefi/tricrypto-ng/blob/d6df8a9/contracts/main/CurveCryptoMathOptimized3.vy#L889
+func Cbrt(x *uint256.Int, result *uint256.Int) {
+	var xx *uint256.Int
+	if x.Cmp(CbrtConst1) >= 0 {
+		xx = x
+	} else if x.Cmp(CbrtConst2) >= 0 {
+		xx = number.Mul(x, U_1e18)
+	} else {
+		xx = number.Mul(x, U_1e36)
+	}
+
+	// # initial_guess = 2 ** pow * 1260 ** remainder // 1000 ** remainder, with pow = log2(x) // 3
+	var log2x, remainder, pow, num, den uint256.Int
+	Log2(xx, false, &log2x)
+	remainder.Mod(&log2x, number.Number_3)
+	pow.Div(&log2x, number.Number_3)
+	num.Exp(U_1260, &remainder)
+	den.Exp(U_1e3, &remainder)
+	result.Div(num.Mul(pow.Exp(number.Number_2, &pow), &num), &den)
+
+	// # 7 newton raphson iterations are just about sufficient with good initial values
+	for range 7 {
+		result.Div(number.Add(number.Mul(number.Number_2, result), number.Div(xx, number.Mul(result, result))),
+			number.Number_3)
+	}
+
+	if x.Cmp(CbrtConst1) >= 0 {
+		result.Mul(result, U_1e12)
+	} else if x.Cmp(CbrtConst2) >= 0 {
+		result.Mul(result, U_1e6)
+	}
+}
+
+// Log2 calculates the log in base 2 of x into result, rounding up if roundup is set. It returns 0 if given 0.
+// This implementation is derived from Snekmate (https://github.com/pcaversaccio/snekmate).
+func Log2(x *uint256.Int, roundup bool, result *uint256.Int) {
+	result.SetUint64(uint64(x.BitLen()))
+	if result.IsZero() {
+		return
+	}
+	result.SubUint64(result, 1)
+	var pow uint256.Int
+	if roundup && pow.Lsh(number.Number_1, uint(result.Uint64())).Lt(x) {
+		result.AddUint64(result, 1)
+	}
+}
+
+// WadExp calculates the natural exponential function of a signed integer with a precision of 1e18.
+// This implementation is derived from Snekmate (https://github.com/pcaversaccio/snekmate).
+func WadExp(x *int256.Int) (*uint256.Int, error) {
+	// # If the result is `< 0.5`, we return zero. This happens when we have the following:
+	// # "x <= floor(log(0.5e18) * 1e18) ~ -42e18".
+	if x.Cmp(wadExpMin) <= 0 {
+		return new(uint256.Int), nil
+	}
+
+	// # When the result is "> (2 ** 255 - 1) / 1e18" we cannot represent it as a signed integer.
+	// # This happens when "x >= floor(log((2 ** 255 - 1) / 1e18) * 1e18) ~ 135".
+	if x.Cmp(wadExpMax) >= 0 {
+		return nil, ErrWadExpOverflow
+	}
+
+	// # `x` is now in the range "(-42, 136) * 1e18". Convert to "(-42, 136) * 2 ** 96" for higher
+	// # intermediate precision and a binary base. This base conversion is a multiplication with
+	// # "1e18 / 2 ** 96 = 5 ** 18 / 2 ** 78".
+	value := i256.Div(i256.Lsh(x, 78), i256.MustFromDecimal("3814697265625"))
+
+	// # Reduce the range of `x` to "(-½ ln 2, ½ ln 2) * 2 ** 96" by factoring out powers of two
+	// # so that "exp(x) = exp(x') * 2 ** k", where `k` is a signer integer. Solving this gives
+	// # "k = round(x / log(2))" and "x' = x - k * log(2)". Thus, `k` is in the range "[-61, 195]".
+	k := i256.Rsh(
+		i256.Add(
+			i256.Div(i256.Lsh(value, 96), i256.MustFromDecimal("54916777467707473351141471128")),
+			i256.MustFromDecimal("39614081257132168796771975168")),
+		96)
+	value = i256.Sub(value, i256.Mul(k, i256.MustFromDecimal("54916777467707473351141471128")))
+
+	// # Evaluate using a "(6, 7)"-term rational approximation. Since `p` is monic,
+	// # we will multiply by a scaling factor later.
+	y := i256.Add(
+		i256.Rsh(i256.Mul(i256.Add(value, i256.MustFromDecimal("1346386616545796478920950773328")), value), 96),
+		i256.MustFromDecimal("57155421227552351082224309758442"))
+	p := i256.Add(
+		i256.Mul(
+			i256.Add(
+				i256.Rsh(
+					i256.Mul(i256.Sub(i256.Add(y, value), i256.MustFromDecimal("94201549194550492254356042504812")), y),
+					96),
+				i256.MustFromDecimal("28719021644029726153956944680412240")),
+			value),
+		i256.Lsh(i256.MustFromDecimal("4385272521454847904659076985693276"), 96),
+	)
+
+	// # We leave `p` in the "2 ** 192" base so that we do not have to scale it up
+	// # again for the division.
+	q := i256.Add(
+		i256.Rsh(i256.Mul(i256.Sub(value, i256.MustFromDecimal("2855989394907223263936484059900")), value), 96),
+		i256.MustFromDecimal("50020603652535783019961831881945"))
+	q = i256.Sub(i256.Rsh(i256.Mul(q, value), 96), i256.MustFromDecimal("533845033583426703283633433725380"))
+	q = i256.Add(i256.Rsh(i256.Mul(q, value), 96), i256.MustFromDecimal("3604857256930695427073651918091429"))
+	q = i256.Sub(i256.Rsh(i256.Mul(q, value), 96), i256.MustFromDecimal("14423608567350463180887372962807573"))
+	q = i256.Add(i256.Rsh(i256.Mul(q, value), 96), i256.MustFromDecimal("26449188498355588339934803723976023"))
+
+	// # The polynomial `q` has no zeros in the range because all its roots are complex.
+	// # No scaling is required, as `p` is already "2 ** 96" too large. Also,
+	// # `r` is in the range "(0.09, 0.25) * 2**96" after the division.
+	r := i256.Div(p, q)
+
+	// # To finalise the calculation, we have to multiply `r` by:
+	// #   - the scale factor "s = ~6.031367120",
+	// #   - the factor "2 ** k" from the range reduction, and
+	// #   - the factor "1e18 / 2 ** 96" for the base conversion.
+	// # We do this all at once, with an intermediate result in "2**213" base,
+	// # so that the final right shift always gives a positive value.
+	tmp := number.Mul(i256.UnsafeToUInt256(r), wadExpScale)
+	var z uint256.Int
+	z.Rsh(tmp, uint(195-k.Int64()))
+	return &z, nil
+}
@@ -0,0 +1,26 @@
+package stableswap
+
+import (
+	"errors"
+
+	"github.com/holiman/uint256"
+)
+
+const (
+	// MaxLoopLimit is the iteration cap of every Newton loop, same as `range(255)` in the contracts.
+	MaxLoopLimit = 255
+)
+
+var (
+	FeeDenominator = uint256.MustFromDecimal("10000000000")
+)
+
+var (
+	ErrZero                   = errors.New("zero")
+	ErrOverflow               = errors.New("uint256 overflow")
+	ErrDDoesNotConverge       = errors.New("d does not converge")
+	ErrAmountOutNotConverge   = errors.New("approximation did not converge")
+	ErrDenominatorZero        = errors.New("denominator should not be 0")
+	ErrTokenFromEqualsTokenTo = errors.New("can't compare token to itself")
+	ErrTokenIndexesOutOfRange = errors.New("token index out of range")
+)
@@ -0,0 +1,255 @@
+// Package stableswap is the StableSwap invariant math shared by every Curve stable pool simulator: the legacy
+// base/plain-oracle/aave/compound/meta pools as well as plain, stable-ng and stable-meta-ng.
+// Results are written into caller-provided pointers to keep the hot path allocation free.
+package stableswap
+
+import (
+	"github.com/KyberNetwork/blockchain-toolkit/number"
+	"github.com/holiman/uint256"
+)
+
+// DPFormula selects how get_D accumulates D_P, which differs between pool templates.
+type DPFormula uint8
+
+const (
+	// DPPerCoin is `D_P = D_P * D / (x * N_COINS)` for each coin (base, plain and meta templates).
+	DPPerCoin DPFormula = iota
+	// DPPerCoinPlusOne is `D_P = D_P * D / (x * N_COINS + 1)`, used by some old pools to avoid division by zero.
+	DPPerCoinPlusOne
+	// DPPowN is `D_P = D_P * D / x` for each coin then `D_P /= N_COINS ** N_COINS` (stableswap-ng).
+	DPPowN
+)
+
+// A returns the amplification coefficient, linearly ramped from initialA to futureA between initialATime and
+// futureATime. The returned value must not be modified as it may be futureA itself.
+func A(initialA, futureA *uint256.Int, initialATime, futureATime, now int64) *uint256.Int {
+	if futureATime <= now {
+		return futureA
+	}
+	var a, elapsed, duration uint256.Int
+	elapsed.SetUint64(uint64(now - initialATime))
+	duration.SetUint64(uint64(futureATime - initialATime))
+	if futureA.Cmp(initialA) > 0 {
+		a.Div(a.Mul(a.Sub(futureA, initialA), &elapsed), &duration)
+		return a.Add(initialA, &a)
+	}
+	a.Div(a.Mul(a.Sub(initialA, futureA), &elapsed), &duration)
+	return a.Sub(initialA, &a)
+}
+
+// GetD calculates the invariant D of the normalized balances xp into D.
+// https://github.com/curvefi/curve-contract/blob/d4e8589/contracts/pool-templates/base/SwapTemplateBase.vy#L217
+func GetD(xp []uint256.Int, amp, aPrecision *uint256.Int, formula DPFormula, D *uint256.Int) error {
+	var S uint256.Int
+	for i := range xp {
+		if xp[i].IsZero() && formula != DPPerCoinPlusOne {
+			// this would divide by zero below
+			return ErrZero
+		}
+		if _, overflow := S.AddOverflow(&S, &xp[i]); overflow {
+			return ErrDDoesNotConverge
+		}
+	}
+	if S.IsZero() {
+		D.Clear()
+		return nil
+	}
+
+	var nCoins, nCoinsPlus1, nCoinsPowN, Ann, AnnS, AnnSubAPrec uint256.Int
+	nCoins.SetUint64(uint64(len(xp)))
+	nCoinsPlus1.SetUint64(uint64(len(xp) + 1))
+	nCoinsPowN.Exp(&nCoins, &nCoins)
+	Ann.Mul(amp, &nCoins)
+	// Ann * S / A_PRECISION
+	if _, overflow := AnnS.MulOverflow(&Ann, &S); overflow {
+		return ErrDDoesNotConverge
+	}
+	AnnS.Div(&AnnS, aPrecision)
+	// Ann - A_PRECISION
+	AnnSubAPrec.Sub(&Ann, aPrecision)
+
+	var DP, Dprev, num, den, tmp uint256.Int
+	var overflow, o bool
+	D.Set(&S)
+	for range MaxLoopLimit {
+		DP.Set(D)
+		for j := range xp {
+			// on-chain reverts if D_P * D overflows uint256
+			if _, overflow = DP.MulOverflow(&DP, D); overflow {
+				return ErrDDoesNotConverge
+			}
+			switch formula {
+			case DPPerCoin:
+				DP.Div(&DP, tmp.Mul(&xp[j], &nCoins))
+			case DPPerCoinPlusOne:
+				DP.Div(&DP, tmp.AddUint64(tmp.Mul(&xp[j], &nCoins), 1))
+			default:
+				DP.Div(&DP, &xp[j])
+			}
+		}
+		if formula == DPPowN {
+			DP.Div(&DP, &nCoinsPowN)
+		}
+		Dprev.Set(D)
+
+		// D = (Ann * S / A_PRECISION + D_P * N_COINS) * D / ((Ann - A_PRECISION) * D / A_PRECISION + (N_COINS + 1) * D_P)
+		_, overflow = num.MulOverflow(&DP, &nCoins)
+		_, o = num.AddOverflow(&AnnS, &num)
+		overflow = overflow || o
+		_, o = num.MulOverflow(&num, D)
+		overflow = overflow || o
+		_, o = den.MulOverflow(&AnnSubAPrec, D)
+		overflow = overflow || o
+		den.Div(&den, aPrecision)
+		_, o = tmp.MulOverflow(&DP, &nCoinsPlus1)
+		overflow = overflow || o
+		_, o = den.AddOverflow(&den, &tmp)
+		if overflow || o {
+			return ErrDDoesNotConverge
+		}
+		D.Div(&num, &den)
+
+		if number.WithinDelta(D, &Dprev, 1) {
+			return nil
+		}
+	}
+	return ErrDDoesNotConverge
+}
+
+// GetY calculates x[j] into y if one makes x[i] = x, given the invariant D of xp.
+// https://github.com/curvefi/curve-contract/blob/d4e8589/contracts/pool-templates/base/SwapTemplateBase.vy#L388
+func GetY(i, j int, x *uint256.Int, xp []uint256.Int, amp, aPrecision, D *uint256.Int, y *uint256.Int) error {
+	if i == j {
+		return ErrTokenFromEqualsTokenTo
+	}
+	if i < 0 || j < 0 || i >= len(xp) || j >= len(xp) {
+		return ErrTokenIndexesOutOfRange
+	}
+
+	var c, S uint256.Int
+	c.Set(D)
+	for k := range xp {
+		var _x *uint256.Int
+		if k == i {
+			_x = x
+		} else if k != j {
+			_x = &xp[k]
+		} else {
+			continue
+		}
+		if err := accumulateCS(_x, D, len(xp), &c, &S); err != nil {
+			return err
+		}
+	}
+	return solveY(amp, aPrecision, D, len(xp), &c, &S, y)
+}
+
+// GetYD calculates x[i] into y if one reduces the invariant of xp to D.
+// https://github.com/curvefi/curve-contract/blob/d4e8589/contracts/pool-templates/base/SwapTemplateBase.vy#L598
+func GetYD(amp, aPrecision *uint256.Int, i int, xp []uint256.Int, D *uint256.Int, y *uint256.Int) error {
+	if i < 0 || i >= len(xp) {
+		return ErrTokenIndexesOutOfRange
+	}
+
+	var c, S uint256.Int
+	c.Set(D)
+	for k := range xp {
+		if k == i {
+			continue
+		}
+		if err := accumulateCS(&xp[k], D, len(xp), &c, &S); err != nil {
+			return err
+		}
+	}
+	return solveY(amp, aPrecision, D, len(xp), &c, &S, y)
+}
+
+// accumulateCS applies `S += x` and `c = c * D / (x * N_COINS)` for one of the known coins.
+func accumulateCS(x, D *uint256.Int, numTokens int, c, S *uint256.Int) error {
+	if x.IsZero() {
+		return ErrZero
+	}
+	var den uint256.Int
+	_, overflow := S.AddOverflow(S, x)
+	_, o := c.MulOverflow(c, D)
+	if overflow || o {
+		return ErrOverflow
+	}
+	c.Div(c, den.Mul(x, den.SetUint64(uint64(numTokens))))
+	return nil
+}
+
+// solveY finishes c and b from the accumulated c and S then runs `y = (y*y + c) / (2 * y + b - D)` from y = D.
+func solveY(amp, aPrecision, D *uint256.Int, numTokens int, c, S, y *uint256.Int) error {
+	var nCoins, Ann, b, tmp uint256.Int
+	nCoins.SetUint64(uint64(numTokens))
+	Ann.Mul(amp, &nCoins)
+	if Ann.IsZero() {
+		return ErrZero
+	}
+
+	// c = c * D * A_PRECISION / (Ann * N_COINS)
+	_, overflow := c.MulOverflow(c, D)
+	_, o := c.MulOverflow(c, aPrecision)
+	overflow = overflow || o
+	c.Div(c, tmp.Mul(&Ann, &nCoins))
+	// b = S + D * A_PRECISION / Ann
+	_, o = b.MulOverflow(D, aPrecision)
+	overflow = overflow || o
+	_, o = b.AddOverflow(S, b.Div(&b, &Ann))
+	if overflow || o {
+		return ErrOverflow
+	}
+
+	var yPrev, num, den uint256.Int
+	y.Set(D)
+	for range MaxLoopLimit {
+		yPrev.Set(y)
+
+		_, overflow = den.AddOverflow(y, y)
+		_, o = den.AddOverflow(&den, &b)
+		overflow = overflow || o
+		_, o = num.MulOverflow(y, y)
+		overflow = overflow || o
+		_, o = num.AddOverflow(&num, c)
+		if overflow || o {
+			return ErrOverflow
+		}
+		if den.Cmp(D) <= 0 {
+			return ErrDenominatorZero
+		}
+		y.Div(&num, den.Sub(&den, D))
+
+		if number.WithinDelta(y, &yPrev, 1) {
+			return nil
+		}
+	}
+	return ErrAmountOutNotConverge
+}
+
+// DynamicFee returns into fee the stableswap-ng style fee scaled by how imbalanced xpi and xpj are. The base fee is
+// used as is when offpegFeeMultiplier is unset or not above FeeDenominator.
+// https://github.com/curvefi/stableswap-ng/blob/12a0c7d/contracts/main/CurveStableSwapNG.vy#L1046
+func DynamicFee(xpi, xpj, baseFee, offpegFeeMultiplier, fee *uint256.Int) {
+	if offpegFeeMultiplier == nil || offpegFeeMultiplier.Cmp(FeeDenominator) <= 0 {
+		fee.Set(baseFee)
+		return
+	}
+
+	// xps2: uint256 = (xpi + xpj) ** 2
+	sum := number.SafeAdd(xpi, xpj)
+	xps2 := number.SafeMul(sum, sum)
+	fee.Div(
+		number.Mul(offpegFeeMultiplier, baseFee),
+		number.Add(
+			number.Div(
+				number.SafeMul(
+					number.SafeMul(number.Sub(offpegFeeMultiplier, FeeDenominator), number.Number_4),
+					number.SafeMul(xpi, xpj),
+				),
+				xps2,
+			),
+			FeeDenominator,
+		),
+	)
+}
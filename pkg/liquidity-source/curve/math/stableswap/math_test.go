@@ -0,0 +1,120 @@
+package stableswap
+
+import (
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expected values below come from a line-by-line integer port of the Vyper templates linked from each function
+
+var (
+	testAmp        = uint256.NewInt(2000 * 100)
+	testAPrecision = uint256.NewInt(100)
+)
+
+func testXp() []uint256.Int {
+	return []uint256.Int{
+		*uint256.MustFromDecimal("1000000000000000000000000"),
+		*uint256.MustFromDecimal("1200000000000000000000000"),
+		*uint256.MustFromDecimal("800000000000000000000000"),
+	}
+}
+
+func TestA(t *testing.T) {
+	t.Parallel()
+	initialA, futureA := uint256.NewInt(100), uint256.NewInt(300)
+	assert.Equal(t, uint256.NewInt(100), A(initialA, futureA, 1000, 2000, 1000))
+	assert.Equal(t, uint256.NewInt(200), A(initialA, futureA, 1000, 2000, 1500))
+	assert.Equal(t, uint256.NewInt(300), A(initialA, futureA, 1000, 2000, 2000))
+	assert.Equal(t, uint256.NewInt(300), A(initialA, futureA, 1000, 2000, 3000))
+	// ramping down
+	assert.Equal(t, uint256.NewInt(250), A(futureA, initialA, 1000, 2000, 1250))
+	// the ramp must not write through to its inputs
+	assert.Equal(t, uint256.NewInt(100), initialA)
+	assert.Equal(t, uint256.NewInt(300), futureA)
+}
+
+func TestGetD(t *testing.T) {
+	t.Parallel()
+	for _, formula := range []DPFormula{DPPerCoin, DPPerCoinPlusOne, DPPowN} {
+		var D uint256.Int
+		require.NoError(t, GetD(testXp(), testAmp, testAPrecision, formula, &D))
+		assert.Equal(t, "2999979177656086521002857", D.Dec())
+	}
+
+	t.Run("balanced", func(t *testing.T) {
+		t.Parallel()
+		var D uint256.Int
+		xp := []uint256.Int{*uint256.NewInt(1e18), *uint256.NewInt(1e18)}
+		require.NoError(t, GetD(xp, testAmp, testAPrecision, DPPerCoin, &D))
+		assert.Equal(t, uint256.NewInt(2e18), &D)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		D := uint256.NewInt(1)
+		xp := []uint256.Int{{}, {}}
+		require.NoError(t, GetD(xp, testAmp, testAPrecision, DPPerCoinPlusOne, D))
+		assert.True(t, D.IsZero())
+	})
+
+	t.Run("zero balance", func(t *testing.T) {
+		t.Parallel()
+		var D uint256.Int
+		xp := []uint256.Int{*uint256.NewInt(1e18), {}}
+		assert.ErrorIs(t, GetD(xp, testAmp, testAPrecision, DPPerCoin, &D), ErrZero)
+	})
+}
+
+func TestGetY(t *testing.T) {
+	t.Parallel()
+	xp := testXp()
+	var D, x, y uint256.Int
+	require.NoError(t, GetD(xp, testAmp, testAPrecision, DPPerCoin, &D))
+	x.Add(&xp[0], uint256.MustFromDecimal("1000000000000000000000"))
+	require.NoError(t, GetY(0, 1, &x, xp, testAmp, testAPrecision, &D, &y))
+	assert.Equal(t, "1198999913682678752885393", y.Dec())
+
+	assert.ErrorIs(t, GetY(1, 1, &x, xp, testAmp, testAPrecision, &D, &y), ErrTokenFromEqualsTokenTo)
+	assert.ErrorIs(t, GetY(0, 3, &x, xp, testAmp, testAPrecision, &D, &y), ErrTokenIndexesOutOfRange)
+}
+
+func TestGetYD(t *testing.T) {
+	t.Parallel()
+	xp := testXp()
+	var D, y uint256.Int
+	require.NoError(t, GetD(xp, testAmp, testAPrecision, DPPerCoin, &D))
+
+	// an unchanged D gives back the balance itself, up to the 1 wei convergence threshold
+	require.NoError(t, GetYD(testAmp, testAPrecision, 2, xp, &D, &y))
+	assert.InDelta(t, xp[2].Float64(), y.Float64(), 1)
+
+	D.Div(D.Mul(&D, uint256.NewInt(99)), uint256.NewInt(100))
+	require.NoError(t, GetYD(testAmp, testAPrecision, 2, xp, &D, &y))
+	assert.Equal(t, "770004175101332725223200", y.Dec())
+
+	assert.ErrorIs(t, GetYD(testAmp, testAPrecision, 3, xp, &D, &y), ErrTokenIndexesOutOfRange)
+}
+
+func TestDynamicFee(t *testing.T) {
+	t.Parallel()
+	xp := testXp()
+	baseFee, multiplier := uint256.NewInt(4000000), uint256.NewInt(20000000000)
+	var fee uint256.Int
+
+	DynamicFee(&xp[0], &xp[1], baseFee, multiplier, &fee)
+	assert.Equal(t, uint256.NewInt(4016597), &fee)
+
+	// balanced coins pay the base fee
+	DynamicFee(&xp[0], &xp[0], baseFee, multiplier, &fee)
+	assert.Equal(t, baseFee, &fee)
+
+	// unset or non-amplifying multipliers fall back to the base fee
+	DynamicFee(&xp[0], &xp[1], baseFee, nil, &fee)
+	assert.Equal(t, baseFee, &fee)
+	DynamicFee(&xp[0], &xp[1], baseFee, FeeDenominator, &fee)
+	assert.Equal(t, baseFee, &fee)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/KyberNetwork/blockchain-toolkit/number"
 	"github.com/holiman/uint256"
 
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/math/stableswap"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/shared"
 )
 
@@ -15,37 +16,8 @@ import (
 // also, some functions are modified to pass in the result pointer instead of allocating and returning result
 
 func (t *PoolSimulator) _A() *uint256.Int {
-	var t1 = t.extra.FutureATime
-	var a1 = t.extra.FutureA
-	var now = time.Now().Unix()
-	if t1 > now {
-		var t0 = t.extra.InitialATime
-		var a0 = t.extra.InitialA
-		if a1.Cmp(a0) > 0 {
-			return number.Add(
-				a0,
-				number.Div(
-					number.Mul(
-						number.Sub(a1, a0),
-						number.SetUint64(uint64(now-t0)),
-					),
-					number.SetUint64(uint64(t1-t0)),
-				),
-			)
-		} else {
-			return number.Sub(
-				a0,
-				number.Div(
-					number.Mul(
-						number.Sub(a0, a1),
-						number.SetUint64(uint64(now-t0)),
-					),
-					number.SetUint64(uint64(t1-t0)),
-				),
-			)
-		}
-	}
-	return a1
+	return stableswap.A(t.extra.InitialA, t.extra.FutureA, t.extra.InitialATime, t.extra.FutureATime,
+		time.Now().Unix())
 }
 
 func xpMem(
@@ -73,71 +45,10 @@ func xpMem_inplace(
 // D invariant calculation in non-overflowing integer operations iteratively
 // - `D`: output
 func (t *PoolSimulator) getD(xp []uint256.Int, a *uint256.Int, D *uint256.Int) error {
-	var S uint256.Int
-	S.Clear()
-	for i := range xp {
-		if xp[i].IsZero() {
-			// this will cause div by zero down below
-			return ErrZero
-		}
-		S.Add(&S, &xp[i])
-	}
-	if S.IsZero() {
-		D.Clear()
-		return nil
-	}
-
-	var D_P, Ann, Ann_mul_S_div_APrec, Ann_sub_APrec, Dprev uint256.Int
-
-	// D: uint256 = S
-	D.Set(&S)
-
-	// Ann: uint256 = amp * N_COINS
-	Ann.Mul(a, &t.numTokensU256)
-
-	// pre-calculate some values to be used in the loop
-	// Ann * S / A_PRECISION
-	Ann_mul_S_div_APrec.Div(number.Mul(&Ann, &S), t.staticExtra.APrecision)
-	// Ann - A_PRECISION
-	Ann_sub_APrec.Sub(&Ann, t.staticExtra.APrecision)
-
-	numTokensPlus1 := uint256.NewInt(uint64(t.numTokens + 1))
-
-	for range MaxLoopLimit {
-		// D_P: uint256 = D
-		D_P.Set(D)
-
-		for j := range xp {
-			// D_P = D_P * D / (_x * N_COINS)
-			// some pools (very few) will divide by `(_x * N_COINS +1)` instead to avoid div by zero (https://github.com/curvefi/curve-contract/blob/d4e8589/contracts/pools/aave/StableSwapAave.vy#L299)
-			// but we can't apply that to other pools because it will lead to incorrect result (return high amount while the pool cannot be used anymore)
-			// so here we'll use the original formula and do the zero check at the beginning
-			D_P.Div(
-				number.SafeMul(&D_P, D),
-				number.SafeMul(&xp[j], &t.numTokensU256),
-			)
-		}
-		// Dprev = D
-		Dprev.Set(D)
-
-		// D = (Ann * S / A_PRECISION + D_P * N_COINS) * D / ((Ann - A_PRECISION) * D / A_PRECISION + (N_COINS + 1) * D_P)
-		D.Div(
-			number.SafeMul(
-				number.SafeAdd(&Ann_mul_S_div_APrec, number.SafeMul(&D_P, &t.numTokensU256)),
-				D,
-			),
-			number.SafeAdd(
-				number.Div(number.SafeMul(&Ann_sub_APrec, D), t.staticExtra.APrecision),
-				number.SafeMul(&D_P, numTokensPlus1),
-			),
-		)
-
-		// calc abs(D - Dprev) and compare against 1
-		if withinDelta(D, &Dprev, 1) {
-			return nil
-		}
-	}
-	return ErrDDoesNotConverge
+	// some pools (very few) divide D_P by `(_x * N_COINS + 1)` instead to avoid div by zero (https://github.com/curvefi/curve-contract/blob/d4e8589/contracts/pools/aave/StableSwapAave.vy#L299)
+	// but we can't apply that to other pools because it will lead to incorrect result (return high amount while the pool cannot be used anymore)
+	// so here we use the original formula, which rejects zero balances
+	return stableswap.GetD(xp, a, t.staticExtra.APrecision, stableswap.DPPerCoin, D)
 }
 
 func (t *PoolSimulator) get_D_mem(rates []uint256.Int, balances []uint256.Int, amp *uint256.Int, D *uint256.Int) error {
@@ -156,13 +67,6 @@ func (t *PoolSimulator) getY(
 	dCached *uint256.Int,
 	y *uint256.Int,
 ) error {
-	if tokenIndexFrom == tokenIndexTo {
-		return ErrTokenFromEqualsTokenTo
-	}
-	if tokenIndexFrom >= t.numTokens && tokenIndexTo >= t.numTokens {
-		return ErrTokenIndexesOutOfRange
-	}
-
 	var a = t._A()
 	if a == nil {
 		return ErrInvalidAValue
@@ -171,80 +75,13 @@ func (t *PoolSimulator) getY(
 	var d uint256.Int
 	if dCached != nil {
 		d.Set(dCached)
-	} else {
+	} else if tokenIndexFrom != tokenIndexTo {
 		err := t.getD(xp, a, &d)
 		if err != nil {
 			return err
 		}
 	}
-	var c = number.Set(&d)
-	var nA = number.SafeMul(a, &t.numTokensU256)
-	var _x, s uint256.Int
-	s.Clear()
-	for i := 0; i < t.numTokens; i++ {
-		if i == tokenIndexFrom {
-			_x.Set(x)
-		} else if i != tokenIndexTo {
-			_x.Set(&xp[i])
-		} else {
-			continue
-		}
-		if _x.IsZero() {
-			return ErrZero
-		}
-		s.Add(&s, &_x)
-		c.Div(
-			number.SafeMul(c, &d),
-			number.SafeMul(&_x, &t.numTokensU256),
-		)
-	}
-	if nA.IsZero() {
-		return ErrZero
-	}
-	c.Div(
-		number.SafeMul(number.SafeMul(c, &d), t.staticExtra.APrecision),
-		number.SafeMul(nA, &t.numTokensU256),
-	)
-	var b = number.SafeAdd(
-		&s,
-		number.Div(number.SafeMul(&d, t.staticExtra.APrecision), nA),
-	)
-
-	var yPrev uint256.Int
-	y.Set(&d)
-	for range MaxLoopLimit {
-		// y_prev = y
-		yPrev.Set(y)
-
-		// y = (y*y + c) / (2 * y + b - D)
-		y.Div(
-			number.SafeAdd(number.SafeMul(y, y), c),
-			number.SafeSub(
-				number.SafeAdd(
-					number.SafeAdd(y, y), // 2 * y
-					b),
-				&d),
-		)
-
-		// calc abs(y - y_prev) and compare against 1
-		if withinDelta(y, &yPrev, 1) {
-			return nil
-		}
-	}
-	return ErrAmountOutNotConverge
-}
-
-func withinDelta(x, y *uint256.Int, delta uint64) bool {
-	var diff uint256.Int
-	if x.Cmp(y) > 0 {
-		diff.Sub(x, y)
-	} else {
-		diff.Sub(y, x)
-	}
-	if diff.CmpUint64(delta) <= 0 {
-		return true
-	}
-	return false
+	return stableswap.GetY(tokenIndexFrom, tokenIndexTo, x, xp, a, t.staticExtra.APrecision, &d, y)
 }
 
 // need to keep big.Int for interface method, will be removed later
@@ -402,56 +239,7 @@ func (t *PoolSimulator) getYD(
 	//output
 	y *uint256.Int,
 ) error {
-	var numTokens = len(xp)
-	if tokenIndex >= numTokens {
-		return ErrTokenNotFound
-	}
-	var c, s uint256.Int
-	c.Set(d)
-	s.Clear()
-	var nA = number.Mul(a, &t.numTokensU256)
-	for i := range numTokens {
-		if i != tokenIndex {
-			s.Add(&s, &xp[i])
-			c.Div(
-				number.Mul(&c, d),
-				number.Mul(&xp[i], &t.numTokensU256),
-			)
-		}
-	}
-	if nA.IsZero() {
-		return ErrZero
-	}
-	c.Div(
-		number.Mul(number.Mul(&c, d), t.staticExtra.APrecision),
-		number.Mul(nA, &t.numTokensU256),
-	)
-	var b = number.Add(
-		&s,
-		number.Div(number.Mul(d, t.staticExtra.APrecision), nA),
-	)
-	var yPrev uint256.Int
-	y.Set(d)
-	for range MaxLoopLimit {
-		yPrev.Set(y)
-		y.Div(
-			number.Add(
-				number.Mul(y, y),
-				&c,
-			),
-			number.Sub(
-				number.Add(
-					number.Add(y, y),
-					b,
-				),
-				d,
-			),
-		)
-		if withinDelta(y, &yPrev, 1) {
-			return nil
-		}
-	}
-	return ErrAmountOutNotConverge
+	return stableswap.GetYD(a, t.staticExtra.APrecision, tokenIndex, xp, d, y)
 }
 
 // need to keep big.Int for interface method, will be removed later
@@ -160,6 +160,29 @@ func (t *PoolSimulator) CloneState() pool.IPoolSimulator {
 	return &cloned
 }
 
+// GetSpotPrice returns the marginal price of the StableSwap invariant at the current balances, net of the swap fee.
+func (t *PoolSimulator) GetSpotPrice(tokenIn, tokenOut string) (float64, error) {
+	var tokenIndexFrom = t.Info.GetTokenIndex(tokenIn)
+	var tokenIndexTo = t.Info.GetTokenIndex(tokenOut)
+	if tokenIndexFrom < 0 || tokenIndexTo < 0 {
+		return 0, fmt.Errorf("tokenIndexFrom %v or tokenIndexTo %v is not correct", tokenIndexFrom, tokenIndexTo)
+	} else if tokenIndexFrom == tokenIndexTo {
+		return 0, ErrTokenFromEqualsTokenTo
+	}
+
+	var xp = xpMem(t.extra.RateMultipliers, t.reserves)
+	var amp = t._A()
+	var D uint256.Int
+	if err := t.getD(xp, amp, &D); err != nil {
+		return 0, err
+	}
+
+	ann := amp.Float64() * float64(t.numTokens) / t.staticExtra.APrecision.Float64()
+	price := shared.StableSwapSpotPrice(xp, &D, ann, tokenIndexFrom, tokenIndexTo)
+	return price * t.extra.RateMultipliers[tokenIndexFrom].Float64() / t.extra.RateMultipliers[tokenIndexTo].Float64() *
+		(1 - t.extra.SwapFee.Float64()/FeeDenominator.Float64()), nil
+}
+
 func (t *PoolSimulator) CalcAmountOut(param pool.CalcAmountOutParams) (*pool.CalcAmountOutResult, error) {
 	tokenAmountIn := param.TokenAmountIn
 	tokenOut := param.TokenOut
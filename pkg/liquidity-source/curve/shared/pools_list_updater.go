@@ -16,6 +16,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/samber/lo"
 
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/transfertax"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
 )
 
@@ -35,6 +36,10 @@ type (
 		DataSources []CurveDataSource   `mapstructure:"data_sources" json:"data_sources,omitempty"`
 
 		FetchPoolsMinDuration durationjson.Duration `mapstructure:"fetch_pools_min_duration" json:"fetch_pools_min_duration"`
+
+		// TransferTax enables the detection of the transfer taxes of the pool tokens, applied by the simulators. The
+		// chain defaults to ChainID.
+		TransferTax *transfertax.Config `mapstructure:"transfer_tax" json:"transfer_tax,omitempty"`
 	}
 
 	HTTPConfig struct {
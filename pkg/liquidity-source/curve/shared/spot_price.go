@@ -0,0 +1,14 @@
+package shared
+
+import "github.com/holiman/uint256"
+
+// StableSwapSpotPrice returns the marginal amount of xp[j] out per unit of xp[i] in on the StableSwap invariant
+// Ann·Σx + D = Ann·D + D^(n+1) / (n^n·Πx), before fees. ann is A·n in real terms, i.e. divided by A_PRECISION.
+func StableSwapSpotPrice(xp []uint256.Int, d *uint256.Int, ann float64, i, j int) float64 {
+	D, n := d.Float64(), float64(len(xp))
+	dr := D // D^(n+1) / (n^n·Πx), accumulated factor by factor to stay within float64 range
+	for k := range xp {
+		dr *= D / (n * xp[k].Float64())
+	}
+	return (ann + dr/xp[i].Float64()) / (ann + dr/xp[j].Float64())
+}
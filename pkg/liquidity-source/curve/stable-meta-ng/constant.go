@@ -4,6 +4,8 @@ import (
 	"errors"
 
 	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/math/stableswap"
 )
 
 const (
@@ -41,12 +43,12 @@ var (
 	ErrInvalidStoredRates           = errors.New("invalid stored rates")
 	ErrInvalidNumToken              = errors.New("invalid number of token")
 	ErrInvalidAValue                = errors.New("invalid A value")
-	ErrZero                         = errors.New("zero")
+	ErrZero                         = stableswap.ErrZero
 	ErrBalancesMustMatchMultipliers = errors.New("balances must match multipliers")
-	ErrDDoesNotConverge             = errors.New("d does not converge")
-	ErrTokenFromEqualsTokenTo       = errors.New("can't compare token to itself")
-	ErrTokenIndexesOutOfRange       = errors.New("token index out of range")
-	ErrAmountOutNotConverge         = errors.New("approximation did not converge")
+	ErrDDoesNotConverge             = stableswap.ErrDDoesNotConverge
+	ErrTokenFromEqualsTokenTo       = stableswap.ErrTokenFromEqualsTokenTo
+	ErrTokenIndexesOutOfRange       = stableswap.ErrTokenIndexesOutOfRange
+	ErrAmountOutNotConverge         = stableswap.ErrAmountOutNotConverge
 
 	ErrTokenToUnderlyingNotSupported = errors.New("not support exchange from base pool token to its underlying")
 	ErrAllBasePoolTokens             = errors.New("base pool swap should be done at base pool")
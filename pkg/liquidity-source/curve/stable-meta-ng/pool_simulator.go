@@ -232,6 +232,11 @@ func (t *PoolSimulator) CalcAmountIn(param pool.CalcAmountInParams) (*pool.CalcA
 		fmt.Errorf("tokenIndexFrom %v or tokenIndexTo %v is not correct", tokenIndexFrom, tokenIndexTo)
 }
 
+// GetSpotPrice defers to the numeric estimate, as swaps may go through the base pool.
+func (t *PoolSimulator) GetSpotPrice(_, _ string) (float64, error) {
+	return 0, pool.ErrSpotPriceUnsupported
+}
+
 func (t *PoolSimulator) CloneState() pool.IPoolSimulator {
 	cloned := *t
 	cloned.PoolSimulator = *t.PoolSimulator.CloneState().(*stableng.PoolSimulator)
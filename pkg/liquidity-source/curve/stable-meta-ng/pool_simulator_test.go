@@ -13,6 +13,7 @@ import (
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/plain"
 	stableng "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/stable-ng"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/transfertax"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/testutil"
 )
@@ -538,3 +539,63 @@ func TestUpdateBalance(t *testing.T) {
 		})
 	}
 }
+
+func TestNewPoolSimulator_TaxedBasePool(t *testing.T) {
+	t.Parallel()
+	const pyusd, usdc = "0x6c3ea9036406852006290770bedfcaba0e23a0e8", "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	basePoolEntity := entity.Pool{
+		Address:  "0x383e6b4437b59fff47b619cba855ca29342a8559",
+		Exchange: stableng.DexType,
+		Type:     stableng.DexType,
+		Reserves: []string{"6596844237987", "8488370101464", "15010621768138115163097113"},
+		Tokens: []*entity.PoolToken{
+			{Address: pyusd, Decimals: 6, Swappable: true},
+			{Address: usdc, Decimals: 6, Swappable: true},
+		},
+		Extra:       "{\"InitialA\":\"100000\",\"FutureA\":\"500000\",\"InitialATime\":1743933983,\"FutureATime\":1745126502,\"SwapFee\":\"1000000\",\"AdminFee\":\"5000000000\",\"OffpegFeeMultiplier\":\"50000000000\",\"RateMultipliers\":[\"1000000000000000000000000000000\",\"1000000000000000000000000000000\"]}",
+		StaticExtra: "{\"APrecision\":\"100\",\"IsNativeCoins\":[false,false]}",
+	}
+	taxedBasePoolEntity := basePoolEntity
+	require.NoError(t, transfertax.SetPoolTaxes(&taxedBasePoolEntity, transfertax.Taxes{pyusd: {SellBps: 100}}))
+	poolEntity := entity.Pool{
+		Address:  "0x9e10f9fb6f0d32b350cee2618662243d4f24c64a",
+		Exchange: DexType,
+		Type:     DexType,
+		Reserves: []string{"84401399278587251786", "107280554742478708149", "190577437534465844859"},
+		Tokens: []*entity.PoolToken{
+			{Address: "0x4591dbff62656e7859afe5e45f6f47d3669fbb28", Decimals: 18, Swappable: true},
+			{Address: basePoolEntity.Address, Decimals: 18, Swappable: true},
+		},
+		Extra:       "{\"InitialA\":\"15000\",\"FutureA\":\"15000\",\"InitialATime\":0,\"FutureATime\":0,\"SwapFee\":\"4000000\",\"AdminFee\":\"5000000000\",\"OffpegFeeMultiplier\":\"20000000000\",\"RateMultipliers\":[\"1000000000000000000\",\"1004967714250081535\"]}",
+		StaticExtra: "{\"APrecision\":\"100\",\"IsNativeCoins\":[false,false],\"BasePool\":\"0x383e6b4437b59fff47b619cba855ca29342a8559\"}",
+	}
+
+	// the base pool built by the factory with the taxes persisted by the tracker is still a curve base pool
+	basePoolSim, err := pool.Factory(stableng.DexType)(pool.FactoryParams{EntityPool: taxedBasePoolEntity})
+	require.NoError(t, err)
+	p, err := pool.Factory(DexType)(pool.FactoryParams{
+		EntityPool:  poolEntity,
+		BasePoolMap: map[string]pool.IPoolSimulator{basePoolEntity.Address: basePoolSim},
+	})
+	require.NoError(t, err)
+	res, err := p.CalcAmountOut(pool.CalcAmountOutParams{
+		TokenAmountIn: pool.TokenAmount{Token: pyusd, Amount: bignumber.NewBig("1000000000")},
+		TokenOut:      poolEntity.Tokens[0].Address,
+	})
+	require.NoError(t, err)
+	assert.Positive(t, res.TokenAmountOut.Amount.Sign())
+
+	// swaps through the base pool itself are quoted net of the taxes
+	untaxedBasePoolSim, err := stableng.NewPoolSimulator(basePoolEntity)
+	require.NoError(t, err)
+	params := pool.CalcAmountOutParams{
+		TokenAmountIn: pool.TokenAmount{Token: pyusd, Amount: bignumber.NewBig("1000000000")},
+		TokenOut:      usdc,
+	}
+	taxedRes, err := basePoolSim.CalcAmountOut(params)
+	require.NoError(t, err)
+	params.TokenAmountIn.Amount = bignumber.NewBig("990000000")
+	untaxedRes, err := untaxedBasePoolSim.CalcAmountOut(params)
+	require.NoError(t, err)
+	assert.Equal(t, untaxedRes.TokenAmountOut.Amount, taxedRes.TokenAmountOut.Amount)
+}
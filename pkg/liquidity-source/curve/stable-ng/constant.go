@@ -4,6 +4,8 @@ import (
 	"errors"
 
 	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/math/stableswap"
 )
 
 const (
@@ -21,8 +23,6 @@ const (
 	PoolMethodGetBalances  = "get_balances"
 	PoolMethodStoredRates  = "stored_rates"
 	PoolMethodOffpegFeeMul = "offpeg_fee_multiplier"
-
-	MaxLoopLimit = 256
 )
 
 var (
@@ -36,11 +36,11 @@ var (
 	ErrInvalidStoredRates     = errors.New("invalid stored rates")
 	ErrInvalidNumToken        = errors.New("invalid number of token")
 	ErrInvalidAValue          = errors.New("invalid A value")
-	ErrZero                   = errors.New("zero")
-	ErrDDoesNotConverge       = errors.New("d does not converge")
-	ErrTokenFromEqualsTokenTo = errors.New("can't compare token to itself")
-	ErrTokenIndexesOutOfRange = errors.New("token index out of range")
-	ErrAmountOutNotConverge   = errors.New("approximation did not converge")
+	ErrZero                   = stableswap.ErrZero
+	ErrDDoesNotConverge       = stableswap.ErrDDoesNotConverge
+	ErrTokenFromEqualsTokenTo = stableswap.ErrTokenFromEqualsTokenTo
+	ErrTokenIndexesOutOfRange = stableswap.ErrTokenIndexesOutOfRange
+	ErrAmountOutNotConverge   = stableswap.ErrAmountOutNotConverge
 	ErrExecutionReverted      = errors.New("execution reverted")
 	ErrPoolDrained            = errors.New("pool drained: y == 0 from get_y")
 )
@@ -8,6 +8,7 @@ import (
 	"github.com/holiman/uint256"
 	"github.com/pkg/errors"
 
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/math/stableswap"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/shared"
 )
 
@@ -34,111 +35,18 @@ func xpMem_inplace(
 }
 
 func (t *PoolSimulator) _A() *uint256.Int {
-	var t1 = t.Extra.FutureATime
-	var a1 = t.Extra.FutureA
-	var now = time.Now().Unix()
-	if t1 > now {
-		var t0 = t.Extra.InitialATime
-		var a0 = t.Extra.InitialA
-		if a1.Cmp(a0) > 0 {
-			return number.Add(
-				a0,
-				number.Div(
-					number.Mul(
-						number.Sub(a1, a0),
-						number.SetUint64(uint64(now-t0)),
-					),
-					number.SetUint64(uint64(t1-t0)),
-				),
-			)
-		} else {
-			return number.Sub(
-				a0,
-				number.Div(
-					number.Mul(
-						number.Sub(a0, a1),
-						number.SetUint64(uint64(now-t0)),
-					),
-					number.SetUint64(uint64(t1-t0)),
-				),
-			)
-		}
-	}
-	return a1
+	return stableswap.A(t.Extra.InitialA, t.Extra.FutureA, t.Extra.InitialATime, t.Extra.FutureATime,
+		time.Now().Unix())
 }
 
 // D invariant calculation in non-overflowing integer operations iteratively
 // - `D`: output
 func (t *PoolSimulator) getD(xp []uint256.Int, amp *uint256.Int, D *uint256.Int) error {
-	var S uint256.Int
-
-	for i := range xp {
-		if xp[i].IsZero() {
-			return ErrZero
-		}
-		S.Add(&S, &xp[i])
-	}
-
-	if S.IsZero() {
-		D.Clear()
-		return nil
-	}
-
-	D.Set(&S)
-
-	var Ann uint256.Int
-	Ann.Mul(amp, &t.NumTokensU256)
-
-	var temp1, temp2, temp3, D_P, Dprev, nCoinsN uint256.Int
-
-	// N_COINS ^ N_COINS, matches Vyper's pow_mod256(N_COINS, N_COINS)
-	nCoinsN.Exp(&t.NumTokensU256, &t.NumTokensU256)
-
-	var nCoinsPlus1 uint256.Int
-	nCoinsPlus1.AddUint64(&t.NumTokensU256, 1)
-
-	for range 255 {
-		Dprev.Set(D)
-
-		// Match Vyper exactly:
-		//   for x in _xp: D_P = D_P * D / x
-		//   D_P /= N_COINS ** N_COINS
-		// If D_P * D overflows uint256, pool state is degenerate (on-chain also reverts).
-		D_P.Set(D)
-		for j := range xp {
-			if _, overflow := D_P.MulOverflow(&D_P, D); overflow {
-				return ErrDDoesNotConverge
-			}
-			D_P.Div(&D_P, &xp[j])
-		}
-		D_P.Div(&D_P, &nCoinsN)
-
-		// (Ann * S / A_PRECISION + D_P * N_COINS) * D
-		temp1.Mul(&Ann, &S)
-		temp1.Div(&temp1, t.StaticExtra.APrecision)
-
-		temp2.Mul(&D_P, &t.NumTokensU256)
-
-		temp1.Add(&temp1, &temp2)
-		temp1.Mul(&temp1, D)
-
-		// (Ann - A_PRECISION) * D / A_PRECISION + (N_COINS + 1) * D_P
-		temp2.Sub(&Ann, t.StaticExtra.APrecision)
-		temp2.Mul(&temp2, D)
-		temp2.Div(&temp2, t.StaticExtra.APrecision)
-
-		// (N_COINS + 1) * D_P
-		temp3.Mul(&nCoinsPlus1, &D_P)
-		temp2.Add(&temp2, &temp3)
-
-		D.Div(&temp1, &temp2)
-
-		if number.WithinDelta(D, &Dprev, 1) {
-			return nil
-		}
-	}
-
-	return ErrDDoesNotConverge
+	// Match Vyper exactly:
+	//   for x in _xp: D_P = D_P * D / x
+	//   D_P /= N_COINS ** N_COINS
+	// If D_P * D overflows uint256, pool state is degenerate (on-chain also reverts).
+	return stableswap.GetD(xp, amp, t.StaticExtra.APrecision, stableswap.DPPowN, D)
 }
 
 // Calculate x[j] if one makes x[i] = x
@@ -152,13 +60,6 @@ func (t *PoolSimulator) GetY(
 	dCached *uint256.Int,
 	y *uint256.Int,
 ) error {
-	if tokenIndexFrom == tokenIndexTo {
-		return ErrTokenFromEqualsTokenTo
-	}
-	if tokenIndexFrom >= t.NumTokens && tokenIndexTo >= t.NumTokens {
-		return ErrTokenIndexesOutOfRange
-	}
-
 	var a = t._A()
 	if a == nil {
 		return ErrInvalidAValue
@@ -167,67 +68,13 @@ func (t *PoolSimulator) GetY(
 	var d uint256.Int
 	if dCached != nil {
 		d.Set(dCached)
-	} else {
+	} else if tokenIndexFrom != tokenIndexTo {
 		err := t.getD(xp, a, &d)
 		if err != nil {
 			return err
 		}
 	}
-	var c = number.Set(&d)
-	var Ann = number.Mul(a, &t.NumTokensU256)
-	var _x, s uint256.Int
-	s.Clear()
-	for i := 0; i < t.NumTokens; i++ {
-		if i == tokenIndexFrom {
-			_x.Set(x)
-		} else if i != tokenIndexTo {
-			_x.Set(&xp[i])
-		} else {
-			continue
-		}
-		if _x.IsZero() {
-			return ErrZero
-		}
-		s.Add(&s, &_x)
-		c.Div(
-			number.SafeMul(c, &d),
-			number.SafeMul(&_x, &t.NumTokensU256),
-		)
-	}
-	if Ann.IsZero() {
-		return ErrZero
-	}
-	c.Div(
-		number.SafeMul(number.SafeMul(c, &d), t.StaticExtra.APrecision),
-		number.SafeMul(Ann, &t.NumTokensU256),
-	)
-	var b = number.SafeAdd(
-		&s,
-		number.Div(number.SafeMul(&d, t.StaticExtra.APrecision), Ann),
-	)
-
-	var yPrev uint256.Int
-	y.Set(&d)
-	for range MaxLoopLimit {
-		// y_prev = y
-		yPrev.Set(y)
-
-		// y = (y*y + c) / (2 * y + b - D)
-		y.Div(
-			number.SafeAdd(number.SafeMul(y, y), c),
-			number.SafeSub(
-				number.SafeAdd(
-					number.SafeAdd(y, y), // 2 * y
-					b),
-				&d),
-		)
-
-		// calc abs(y - y_prev) and compare against 1
-		if number.WithinDelta(y, &yPrev, 1) {
-			return nil
-		}
-	}
-	return ErrAmountOutNotConverge
+	return stableswap.GetY(tokenIndexFrom, tokenIndexTo, x, xp, a, t.StaticExtra.APrecision, &d, y)
 }
 
 // Calculate the current output dy given input dx
@@ -362,7 +209,10 @@ func (t *PoolSimulator) GetDx(
 	// x: uint256 = self.get_y(j, i, y, xp, amp, D, N_COINS)
 	var x uint256.Int
 	err = t.GetY(j, i, &y, xp, dCached, &x)
-	if err != nil {
+	if errors.Is(err, stableswap.ErrOverflow) {
+		// checked arithmetic overflow reverts on-chain, same as the panics recovered above
+		return errors.Wrapf(ErrExecutionReverted, "%v", err)
+	} else if err != nil {
 		return err
 	}
 
@@ -373,31 +223,7 @@ func (t *PoolSimulator) GetDx(
 }
 
 func (t *PoolSimulator) DynamicFee(xpi *uint256.Int, xpj *uint256.Int, swapFee *uint256.Int, feeOutput *uint256.Int) {
-	_offpeg_fee_multiplier := t.Extra.OffpegFeeMultiplier
-	if _offpeg_fee_multiplier == nil || _offpeg_fee_multiplier.Cmp(FeeDenominator) <= 0 {
-		feeOutput.Set(swapFee)
-		return
-	}
-
-	// xps2: uint256 = (xpi + xpj) ** 2
-	sum := number.SafeAdd(xpi, xpj)
-	prod := number.SafeMul(xpi, xpj)
-	xps2 := number.SafeMul(sum, sum)
-	feeOutput.Div(
-		number.Mul(_offpeg_fee_multiplier, swapFee),
-		number.Add(
-			number.Div(
-				number.SafeMul(
-					number.SafeMul(
-						number.Sub(_offpeg_fee_multiplier, FeeDenominator),
-						number.Number_4,
-					),
-					prod,
-				), xps2,
-			),
-			FeeDenominator,
-		),
-	)
+	stableswap.DynamicFee(xpi, xpj, swapFee, t.Extra.OffpegFeeMultiplier, feeOutput)
 }
 
 // Calculate addition or reduction in token supply from a deposit or withdrawal
@@ -579,56 +405,7 @@ func (t *PoolSimulator) getYD(
 	// output
 	y *uint256.Int,
 ) error {
-	if tokenIndex >= t.NumTokens {
-		return ErrTokenIndexesOutOfRange
-	}
-	var c, s uint256.Int
-	c.Set(d)
-	s.Clear()
-	var nA = number.Mul(a, &t.NumTokensU256)
-	for i := 0; i < t.NumTokens; i++ {
-		if i != tokenIndex {
-			number.SafeAddZ(&s, &xp[i], &s)
-			c.Div(
-				number.SafeMul(&c, d),
-				number.SafeMul(&xp[i], &t.NumTokensU256),
-			)
-		}
-	}
-	if nA.IsZero() {
-		return ErrZero
-	}
-	c.Div(
-		number.SafeMul(number.SafeMul(&c, d), t.StaticExtra.APrecision),
-		number.SafeMul(nA, &t.NumTokensU256),
-	)
-	var b = number.SafeAdd(
-		&s,
-		number.Div(number.SafeMul(d, t.StaticExtra.APrecision), nA),
-	)
-	var yPrev uint256.Int
-	y.Set(d)
-	for range MaxLoopLimit {
-		yPrev.Set(y)
-		// y = (y*y + c) / (2 * y + b - D)
-		y.Div(
-			number.SafeAdd(
-				number.SafeMul(y, y),
-				&c,
-			),
-			number.SafeSub(
-				number.SafeAdd(
-					number.SafeAdd(y, y),
-					b,
-				),
-				d,
-			),
-		)
-		if number.WithinDelta(y, &yPrev, 1) {
-			return nil
-		}
-	}
-	return ErrAmountOutNotConverge
+	return stableswap.GetYD(a, t.StaticExtra.APrecision, tokenIndex, xp, d, y)
 }
 
 func (t *PoolSimulator) ApplyRemoveLiquidityOneCoinU256(i int, tokenAmount, dy, dyFee *uint256.Int) error {
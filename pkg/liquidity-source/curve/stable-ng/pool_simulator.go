@@ -105,6 +105,10 @@ func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
 }
 
 func (t *PoolSimulator) CalcAmountOut(param pool.CalcAmountOutParams) (*pool.CalcAmountOutResult, error) {
+	return t.Extra.TransferTaxes.CalcAmountOut(param, t.calcAmountOut)
+}
+
+func (t *PoolSimulator) calcAmountOut(param pool.CalcAmountOutParams) (*pool.CalcAmountOutResult, error) {
 	tokenAmountIn := param.TokenAmountIn
 	tokenOut := param.TokenOut
 	var tokenIndexFrom = t.Info.GetTokenIndex(tokenAmountIn.Token)
@@ -144,6 +148,10 @@ func (t *PoolSimulator) CalcAmountOut(param pool.CalcAmountOutParams) (*pool.Cal
 }
 
 func (t *PoolSimulator) CalcAmountIn(param pool.CalcAmountInParams) (*pool.CalcAmountInResult, error) {
+	return t.Extra.TransferTaxes.CalcAmountIn(param, t.calcAmountIn)
+}
+
+func (t *PoolSimulator) calcAmountIn(param pool.CalcAmountInParams) (*pool.CalcAmountInResult, error) {
 	tokenAmountOut := param.TokenAmountOut
 	tokenIn := param.TokenIn
 	var tokenIndexFrom = t.Info.GetTokenIndex(tokenIn)
@@ -183,6 +191,31 @@ func (t *PoolSimulator) CalcAmountIn(param pool.CalcAmountInParams) (*pool.CalcA
 	return &pool.CalcAmountInResult{}, fmt.Errorf("tokenIndexFrom %v or TokenOutIndex %v is not correct", tokenIndexFrom, tokenIndexTo)
 }
 
+// GetSpotPrice returns the marginal price of the StableSwap invariant at the current balances, net of the dynamic fee
+// and transfer taxes.
+func (t *PoolSimulator) GetSpotPrice(tokenIn, tokenOut string) (float64, error) {
+	var tokenIndexFrom = t.Info.GetTokenIndex(tokenIn)
+	var tokenIndexTo = t.Info.GetTokenIndex(tokenOut)
+	if tokenIndexFrom < 0 || tokenIndexTo < 0 {
+		return 0, fmt.Errorf("tokenIndexFrom %v or TokenOutIndex %v is not correct", tokenIndexFrom, tokenIndexTo)
+	} else if tokenIndexFrom == tokenIndexTo {
+		return 0, ErrTokenFromEqualsTokenTo
+	}
+
+	var xp = XpMem(t.Extra.RateMultipliers, t.Reserves)
+	var amp = t._A()
+	var D, dynamicFee uint256.Int
+	if err := t.getD(xp, amp, &D); err != nil {
+		return 0, err
+	}
+	t.DynamicFee(&xp[tokenIndexFrom], &xp[tokenIndexTo], t.Extra.SwapFee, &dynamicFee)
+
+	ann := amp.Float64() * float64(t.NumTokens) / t.StaticExtra.APrecision.Float64()
+	price := shared.StableSwapSpotPrice(xp, &D, ann, tokenIndexFrom, tokenIndexTo)
+	return price * t.Extra.RateMultipliers[tokenIndexFrom].Float64() / t.Extra.RateMultipliers[tokenIndexTo].Float64() *
+		(1 - dynamicFee.Float64()/FeeDenominator.Float64()) * t.Extra.TransferTaxes.NetShare(tokenIn, tokenOut), nil
+}
+
 func (t *PoolSimulator) CloneState() pool.IPoolSimulator {
 	cloned := *t
 	cloned.Info.Reserves = slices.Clone(t.Info.Reserves)
@@ -191,6 +224,7 @@ func (t *PoolSimulator) CloneState() pool.IPoolSimulator {
 }
 
 func (t *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	params = t.Extra.TransferTaxes.PoolAmounts(params)
 	input, output := params.TokenAmountIn, params.TokenAmountOut
 	var inputAmount = input.Amount
 	outputAmount := new(big.Int).Add(output.Amount, params.Fee.Amount)
@@ -15,15 +15,16 @@ import (
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/testutil"
 )
 
+var calcAmountOutPools = []string{
+	// https://arbiscan.io/address/0xdc40d14accd5629bbfa65d057f175871628d13c7#readContract
+	`{"address":"0xdc40d14accd5629bbfa65d057f175871628d13c7","exchange":"curve-stable-ng","type":"curve-stable-ng","timestamp":1709285278,"reserves":["50980","75958","100000000000000"],"tokens":[{"address":"0xfd086bc7cd5c481dcc9c85ebe478a1c0b69fcbb9","symbol":"USDT","decimals":6,"swappable":true},{"address":"0xff970a61a04b1ca14834a43f5de4533ebddb5cc8","symbol":"USDC.e","decimals":6,"swappable":true}],"extra":"{\"InitialA\":\"20000\",\"FutureA\":\"20000\",\"InitialATime\":0,\"FutureATime\":0,\"SwapFee\":\"4000000\",\"AdminFee\":\"5000000000\",\"OffpegFeeMultiplier\":\"20000000000\",\"RateMultipliers\":[\"1000000000000000000000000000000\",\"1000000000000000000000000000000\"]}","staticExtra":"{\"APrecision\":\"100\"}","blockNumber":185969597}`,
+
+	// https://arbiscan.io/address/0x3adf984c937fa6846e5a24e0a68521bdaf767ce1#readContract
+	`{"address":"0x3adf984c937fa6846e5a24e0a68521bdaf767ce1","exchange":"curve-stable-ng","type":"curve-stable-ng","timestamp":1709287180,"reserves":["8994725349517509957774712","1568153728639","10550045569550900254909685"],"tokens":[{"address":"0x498bf2b1e120fed3ad3d42ea2165e9b73f99c1e5","symbol":"crvUSD","decimals":18,"swappable":true},{"address":"0xff970a61a04b1ca14834a43f5de4533ebddb5cc8","symbol":"USDC.e","decimals":6,"swappable":true}],"extra":"{\"InitialA\":\"100000\",\"FutureA\":\"100000\",\"InitialATime\":0,\"FutureATime\":0,\"SwapFee\":\"1000000\",\"AdminFee\":\"5000000000\",\"OffpegFeeMultiplier\":\"50000000000\",\"RateMultipliers\":[\"1000000000000000000\",\"1000000000000000000000000000000\"]}","staticExtra":"{\"APrecision\":\"100\"}","blockNumber":185977087}`,
+}
+
 func TestCalcAmountOut(t *testing.T) {
 	t.Parallel()
-	pools := []string{
-		// https://arbiscan.io/address/0xdc40d14accd5629bbfa65d057f175871628d13c7#readContract
-		`{"address":"0xdc40d14accd5629bbfa65d057f175871628d13c7","exchange":"curve-stable-ng","type":"curve-stable-ng","timestamp":1709285278,"reserves":["50980","75958","100000000000000"],"tokens":[{"address":"0xfd086bc7cd5c481dcc9c85ebe478a1c0b69fcbb9","symbol":"USDT","decimals":6,"swappable":true},{"address":"0xff970a61a04b1ca14834a43f5de4533ebddb5cc8","symbol":"USDC.e","decimals":6,"swappable":true}],"extra":"{\"InitialA\":\"20000\",\"FutureA\":\"20000\",\"InitialATime\":0,\"FutureATime\":0,\"SwapFee\":\"4000000\",\"AdminFee\":\"5000000000\",\"OffpegFeeMultiplier\":\"20000000000\",\"RateMultipliers\":[\"1000000000000000000000000000000\",\"1000000000000000000000000000000\"]}","staticExtra":"{\"APrecision\":\"100\"}","blockNumber":185969597}`,
-
-		// https://arbiscan.io/address/0x3adf984c937fa6846e5a24e0a68521bdaf767ce1#readContract
-		`{"address":"0x3adf984c937fa6846e5a24e0a68521bdaf767ce1","exchange":"curve-stable-ng","type":"curve-stable-ng","timestamp":1709287180,"reserves":["8994725349517509957774712","1568153728639","10550045569550900254909685"],"tokens":[{"address":"0x498bf2b1e120fed3ad3d42ea2165e9b73f99c1e5","symbol":"crvUSD","decimals":18,"swappable":true},{"address":"0xff970a61a04b1ca14834a43f5de4533ebddb5cc8","symbol":"USDC.e","decimals":6,"swappable":true}],"extra":"{\"InitialA\":\"100000\",\"FutureA\":\"100000\",\"InitialATime\":0,\"FutureATime\":0,\"SwapFee\":\"1000000\",\"AdminFee\":\"5000000000\",\"OffpegFeeMultiplier\":\"50000000000\",\"RateMultipliers\":[\"1000000000000000000\",\"1000000000000000000000000000000\"]}","staticExtra":"{\"APrecision\":\"100\"}","blockNumber":185977087}`,
-	}
 
 	testcases := []struct {
 		poolIdx           int
@@ -48,7 +49,7 @@ func TestCalcAmountOut(t *testing.T) {
 		{1, "0xff970a61a04b1ca14834a43f5de4533ebddb5cc8", 5000, "0x498bf2b1e120fed3ad3d42ea2165e9b73f99c1e5", 5026591932391690},
 	}
 
-	sims := lo.Map(pools, func(poolRedis string, _ int) *PoolSimulator {
+	sims := lo.Map(calcAmountOutPools, func(poolRedis string, _ int) *PoolSimulator {
 		var poolEntity entity.Pool
 		err := json.Unmarshal([]byte(poolRedis), &poolEntity)
 		require.Nil(t, err)
@@ -75,6 +76,16 @@ func TestCalcAmountOut(t *testing.T) {
 	}
 }
 
+func TestGetSpotPrice(t *testing.T) {
+	t.Parallel()
+	var poolEntity entity.Pool
+	require.NoError(t, json.Unmarshal([]byte(calcAmountOutPools[1]), &poolEntity))
+	p, err := NewPoolSimulator(poolEntity)
+	require.NoError(t, err)
+
+	testutil.TestSpotPrice(t, p, 1)
+}
+
 // TestCalcAmountOutDegeneratePool verifies that a pool whose on-chain get_D oscillates
 // (never satisfies |D - Dprev| <= 1 in 255 iterations) is correctly rejected by CalcAmountOut.
 //
@@ -17,12 +17,14 @@ import (
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/shared"
 	poolpkg "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
 	pooltrack "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/tracker"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/transfertax"
 )
 
 type PoolTracker struct {
 	config       *shared.Config
 	ethrpcClient *ethrpc.Client
 	logger       logger.Logger
+	transferTax  *transfertax.Detector
 }
 
 var _ = pooltrack.RegisterBackupFactoryCE(DexType, NewPoolTracker)
@@ -40,6 +42,7 @@ func NewPoolTracker(
 		config:       config,
 		ethrpcClient: ethrpcClient,
 		logger:       lg,
+		transferTax:  transfertax.NewConfiguredDetector(config.TransferTax, config.ChainID, ethrpcClient),
 	}, nil
 }
 
@@ -171,7 +174,7 @@ func (t *PoolTracker) getNewPoolState(
 	p.Timestamp = time.Now().Unix()
 	p.Reserves = reserves
 
-	return p, nil
+	return t.transferTax.Track(ctx, p)
 }
 
 func updateRateMultipliers(lg logger.Logger, extra *Extra, numTokens int, customRates []*big.Int) error {
@@ -1,6 +1,10 @@
 package stableng
 
-import "github.com/holiman/uint256"
+import (
+	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/transfertax"
+)
 
 type (
 	StaticExtra struct {
@@ -19,5 +23,8 @@ type (
 		OffpegFeeMultiplier *uint256.Int
 
 		RateMultipliers []uint256.Int `json:",omitempty"`
+
+		// TransferTaxes are the taxes of the tokens the tracker detected, applied to swaps through the pool itself.
+		TransferTaxes transfertax.Taxes `json:"transferTaxes,omitempty"`
 	}
 )
@@ -5,6 +5,8 @@ import (
 
 	"github.com/KyberNetwork/blockchain-toolkit/number"
 	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/math/cryptoswap"
 )
 
 // from contracts/main/CurveTricryptoOptimizedWETH.vy
@@ -12,7 +14,7 @@ import (
 
 func (t *PoolSimulator) FeeCalc(xp []uint256.Int, fee *uint256.Int) error {
 	var f uint256.Int
-	var err = reductionCoefficient(xp, t.Extra.FeeGamma, &f)
+	var err = cryptoswap.ReductionCoefficient(xp, t.Extra.FeeGamma, &f)
 	if err != nil {
 		return err
 	}
@@ -7,6 +7,8 @@ import (
 	"github.com/KyberNetwork/int256"
 	mapset "github.com/deckarep/golang-set/v2"
 	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/math/cryptoswap"
 )
 
 const (
@@ -50,18 +52,14 @@ const (
 	PoolMethodLastPrices         = poolMethodLastPrices
 	PoolMethodMath               = poolMethodMath
 
-	MaxLoopLimit = 256
-	NumTokens    = 2
+	NumTokens = 2
 )
 
 var (
 	PriceMask = uint256.MustFromHex("0xffffffffffffffffffffffffffffffff")
 
 	U_1e3  = uint256.MustFromDecimal("1000")
-	U_1260 = uint256.MustFromDecimal("1260")
-	U_1e6  = uint256.MustFromDecimal("1000000")
 	U_1e10 = uint256.MustFromDecimal("10000000000")
-	U_1e12 = uint256.MustFromDecimal("1000000000000")
 	U_1e14 = uint256.MustFromDecimal("100000000000000")
 	U_1e16 = uint256.MustFromDecimal("10000000000000000")
 	U_1e18 = uint256.MustFromDecimal("1000000000000000000")
@@ -116,9 +114,6 @@ var (
 	MinX0 = uint256.MustFromDecimal("1000000000")
 	MaxX1 = uint256.MustFromDecimal("1000000000000000000000000000000000")
 
-	CbrtConst1 = uint256.MustFromDecimal("115792089237316195423570985008687907853269000000000000000000")
-	CbrtConst2 = uint256.MustFromDecimal("115792089237316195423570985008687907853269")
-
 	NumTokensU256 = uint256.NewInt(NumTokens)
 
 	SupportedImplementation = mapset.NewSet("twocrypto-optimized")
@@ -129,17 +124,17 @@ var (
 var (
 	ErrInvalidReserve      = errors.New("invalid reserve")
 	ErrInvalidNumToken     = errors.New("invalid number of token")
-	ErrZero                = errors.New("zero")
+	ErrZero                = cryptoswap.ErrZero
 	ErrLoss                = errors.New("loss")
-	ErrDDoesNotConverge    = errors.New("d does not converge")
-	ErrYDoesNotConverge    = errors.New("y does not converge")
-	ErrWadExpOverflow      = errors.New("wad_exp overflow")
-	ErrUnsafeY             = errors.New("unsafe value for y")
+	ErrDDoesNotConverge    = cryptoswap.ErrDDoesNotConverge
+	ErrYDoesNotConverge    = cryptoswap.ErrYDoesNotConverge
+	ErrWadExpOverflow      = cryptoswap.ErrWadExpOverflow
+	ErrUnsafeY             = cryptoswap.ErrUnsafeY
 	ErrUnsafeA             = errors.New("unsafe values A")
 	ErrUnsafeGamma         = errors.New("unsafe values gamma")
-	ErrUnsafeD             = errors.New("unsafe values D")
+	ErrUnsafeD             = cryptoswap.ErrUnsafeD
 	ErrUnsafeX0            = errors.New("unsafe values x[0]")
-	ErrUnsafeXi            = errors.New("unsafe values x[i]")
+	ErrUnsafeXi            = cryptoswap.ErrUnsafeXi
 	ErrCoinIndexOutOfRange = errors.New("coin index out of range")
 	ErrExchange0Coins      = errors.New("do not exchange 0 coins")
 )
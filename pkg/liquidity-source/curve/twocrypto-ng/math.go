@@ -1,6 +1,7 @@
 package twocryptong
 
 import (
+	"errors"
 	"time"
 
 	"github.com/KyberNetwork/blockchain-toolkit/i256"
@@ -9,6 +10,9 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/holiman/uint256"
 	"github.com/samber/lo"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/math/cryptoswap"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/math/stableswap"
 )
 
 var ( // FXSwap custom MATH library for their twocrypto-ng pools
@@ -54,66 +58,12 @@ func _get_y_custom(
 		return ErrCoinIndexOutOfRange
 	}
 
-	var S_, _x, y_prev, c, Ann uint256.Int
-	c.Set(D)
-	Ann.Mul(_amp, NumTokensU256) // Ann = _amp * N_COINS
-
-	// Calculate S_ and c
-	for _i := range NumTokens {
-		if _i != i {
-			_x.Set(&xp[_i])
-			S_.Add(&S_, &_x)
-			// c = c * D // (_x * N_COINS)
-			c.Mul(&c, D)
-			c.Div(&c, number.Mul(&_x, NumTokensU256))
-		}
-	}
-
-	// c = c * D * A_MULTIPLIER // (Ann * N_COINS)
-	c.Mul(&c, D)
-	c.Mul(&c, AMultiplier)
-	c.Div(&c, number.Mul(&Ann, NumTokensU256))
-
-	// b = S_ + D * A_MULTIPLIER // Ann
-	var b uint256.Int
-	b.Mul(D, AMultiplier)
-	b.Div(&b, &Ann)
-	b.Add(&S_, &b)
-
-	// y = D
-	y.Set(D)
-
-	// Newton's method: y = (y*y + c) // (2 * y + b - D)
-	for range 255 {
-		y_prev.Set(y)
-
-		// y = (y*y + c) // (2 * y + b - D)
-		var numerator, denominator uint256.Int
-		numerator.Mul(y, y)           // y*y
-		numerator.Add(&numerator, &c) // y*y + c
-
-		denominator.Mul(number.Number_2, y) // 2*y
-		denominator.Add(&denominator, &b)   // 2*y + b
-		if _, underflow := denominator.SubOverflow(&denominator, D); underflow || denominator.IsZero() {
-			return ErrZero
-		}
-
-		y.Div(&numerator, &denominator)
-
-		// Check convergence: |y - y_prev| <= 1
-		var diff uint256.Int
-		if y.Gt(&y_prev) {
-			diff.Sub(y, &y_prev)
-		} else {
-			diff.Sub(&y_prev, y)
-		}
-
-		if diff.CmpUint64(1) <= 0 {
-			return nil
-		}
+	// the custom math is a stableswap get_y_D with A_MULTIPLIER as A_PRECISION
+	err := stableswap.GetYD(_amp, AMultiplier, i, xp, D, y)
+	if errors.Is(err, stableswap.ErrDenominatorZero) || errors.Is(err, stableswap.ErrZero) {
+		return ErrZero
 	}
-
-	return ErrYDoesNotConverge
+	return err
 }
 
 // https://github.com/curvefi/twocrypto-ng/blob/d21b270/contracts/main/CurveCryptoMathOptimized2.vy#L229
@@ -336,27 +286,13 @@ func _get_y(
 	return nil
 }
 
-// only sort slice of 2 elements (update the input array directly)
-func sort(x []uint256.Int) {
-	if x[0].Cmp(&x[1]) < 0 {
-		tmp := number.Set(&x[0])
-		x[0].Set(&x[1])
-		x[1].Set(tmp)
-	}
-}
-
 // calculates a geometric mean for two numbers.
 func geometric_mean(_x []uint256.Int) *uint256.Int {
 	var result uint256.Int
-	_geometric_mean(_x, &result)
+	cryptoswap.GeometricMean(_x, &result)
 	return &result
 }
 
-// calculates a geometric mean for two numbers into provided result.
-func _geometric_mean(_x []uint256.Int, result *uint256.Int) {
-	result.Sqrt(result.Mul(&_x[0], &_x[1]))
-}
-
 func newton_D(
 	ANN, gamma, K0_prev *uint256.Int,
 	x_unsorted []uint256.Int,
@@ -397,7 +333,7 @@ func _newton_D(
 	for i := range x_unsorted {
 		x[i].Set(&x_unsorted[i])
 	}
-	sort(x[:])
+	cryptoswap.Sort(x[:])
 
 	// assert x[0] > 10**9 - 1 and x[0] < 10**15 * 10**18 + 1  # dev: unsafe values x[0]
 	if x[0].Cmp(MinX0) < 0 || x[0].Cmp(MaxX1) > 0 {
@@ -427,123 +363,19 @@ func _newton_D(
 		}
 	}
 
-	var __g1k0 = number.Add(gamma, U_1e18)
-	var D_prev, K0, diff uint256.Int
-	for range 255 {
-		if D.Sign() <= 0 {
-			return nil, ErrUnsafeD
-		}
-		D_prev.Set(&D)
-
-		// # collapsed for 2 coins
-		// K0: uint256 = unsafe_div(unsafe_div((10**18 * N_COINS**2) * x[0], D) * x[1], D)
-		K0.Div(number.Mul(number.Div(
-			number.Mul(number.Mul(U_1e18, number.Mul(NumTokensU256, NumTokensU256)), &x[0]), &D), &x[1]), &D)
-
-		// if _g1k0 > K0:  #       The following operations can safely be unsafe.
-		// 		_g1k0 = unsafe_add(unsafe_sub(_g1k0, K0), 1)
-		// else:
-		// 		_g1k0 = unsafe_add(unsafe_sub(K0, _g1k0), 1)
-
-		var _g1k0 uint256.Int
-		_g1k0.Set(__g1k0)
-		if _g1k0.Cmp(&K0) > 0 {
-			_g1k0.AddUint64(number.Sub(&_g1k0, &K0), 1)
-		} else {
-			_g1k0.AddUint64(number.Sub(&K0, &_g1k0), 1)
-		}
-
-		// # D / (A * N**N) * _g1k0**2 / gamma**2
-		// mul1: uint256 = unsafe_div(unsafe_div(unsafe_div(10**18 * D, gamma) * _g1k0, gamma) * _g1k0 * A_MULTIPLIER, ANN)
-		var mul1 = number.Div(
-			number.Mul(
-				number.Mul(
-					number.Div(
-						number.Mul(
-							number.Div(number.Mul(U_1e18, &D), gamma), &_g1k0,
-						),
-						gamma,
-					),
-					&_g1k0,
-				),
-				AMultiplier,
-			),
-			ANN,
-		)
-
-		// # 2*N*K0 / _g1k0
-		// mul2: uint256 = unsafe_div(((2 * 10**18) * N_COINS) * K0, _g1k0)
-		var mul2 = number.Div(
-			number.Mul(number.SafeMul(U_2e18, NumTokensU256), &K0), &_g1k0,
-		)
-
-		// # calculate neg_fprime. here K0 > 0 is being validated (safediv).
-		// neg_fprime: uint256 = (S + unsafe_div(S * mul2, 10**18)) + mul1 * N_COINS / K0 - unsafe_div(mul2 * D, 10**18)
-		var neg_fprime = number.Sub(
-			number.Add(
-				number.Add(S, number.Div(number.Mul(S, mul2), U_1e18)),
-				number.SafeDiv(number.Mul(mul1, NumTokensU256), &K0),
-			),
-			number.Div(number.Mul(mul2, &D), U_1e18),
-		)
-
-		// # D -= f / fprime; neg_fprime safediv being validated
-		// D_plus: uint256 = D * (neg_fprime + S) / neg_fprime
-		var D_plus = number.SafeDiv(number.SafeMul(&D, number.Add(neg_fprime, S)), neg_fprime)
-
-		// D_minus: uint256 = unsafe_div(D * D,  neg_fprime)
-		var D_minus = number.Div(number.SafeMul(&D, &D), neg_fprime)
-
-		if U_1e18.Cmp(&K0) > 0 {
-			// D_minus += unsafe_div(unsafe_div(D * unsafe_div(mul1, neg_fprime), 10**18) * unsafe_sub(10**18, K0), K0)
-			D_minus = number.SafeAdd(D_minus,
-				number.Div(
-					number.Mul(number.Div(number.SafeMul(&D, number.Div(mul1, neg_fprime)), U_1e18),
-						number.Sub(U_1e18, &K0)),
-					&K0,
-				),
-			)
-		} else {
-			// D_minus -= unsafe_div(unsafe_div(D * unsafe_div(mul1, neg_fprime), 10**18) * unsafe_sub(K0, 10**18), K0)
-			D_minus = number.SafeSub(D_minus,
-				number.Div(
-					number.Mul(number.Div(number.SafeMul(&D, number.Div(mul1, neg_fprime)), U_1e18),
-						number.Sub(&K0, U_1e18)),
-					&K0,
-				),
-			)
-		}
-		if D_plus.Cmp(D_minus) > 0 {
-			// D = unsafe_sub(D_plus, D_minus)  # <--------- Safe since we check.
-			D.Set(number.Sub(D_plus, D_minus))
-		} else {
-			// D = unsafe_div(unsafe_sub(D_minus, D_plus), 2)
-			D.Div(number.Sub(D_minus, D_plus), number.Number_2)
-		}
-
-		if D.Cmp(&D_prev) > 0 {
-			diff.Sub(&D, &D_prev)
-		} else {
-			diff.Sub(&D_prev, &D)
-		}
+	if err := cryptoswap.NewtonD2(ANN, gamma, x[:], &D); err != nil {
+		return nil, err
+	}
 
-		temp := U_1e16
-		if D.Cmp(U_1e16) > 0 {
-			temp = &D
-		}
-		if number.Mul(&diff, U_1e14).Cmp(temp) < 0 {
-			// # Test that we are safe with the next get_y
-			for i := range x {
-				var frac = number.Div(number.Mul(&x[i], U_1e18), &D)
-				if frac.Cmp(number.Div(MinFrac, NumTokensU256)) < 0 || frac.Cmp(number.Div(MaxFrac,
-					NumTokensU256)) > 0 {
-					return nil, ErrUnsafeXi
-				}
-			}
-			return &D, nil
+	// # Test that we are safe with the next get_y
+	for i := range x {
+		var frac = number.Div(number.Mul(&x[i], U_1e18), &D)
+		if frac.Cmp(number.Div(MinFrac, NumTokensU256)) < 0 || frac.Cmp(number.Div(MaxFrac,
+			NumTokensU256)) > 0 {
+			return nil, ErrUnsafeXi
 		}
 	}
-	return nil, ErrDDoesNotConverge
+	return &D, nil
 }
 
 // https://etherscan.io/address/0x79839c2D74531A8222C0F555865aAc1834e82e51#code#F1#L67
@@ -551,206 +383,26 @@ func _newton_D_custom(
 	_amp *uint256.Int,
 	_xp []uint256.Int,
 ) (*uint256.Int, error) {
-	var S uint256.Int
-	for _, x := range _xp {
-		S.Add(&S, &x)
-	}
-	if S.IsZero() {
-		var z uint256.Int
-		return &z, nil // return 0
-	}
-
-	var D, Ann uint256.Int
-	D.Set(&S)
-	Ann.Mul(_amp, NumTokensU256) // Ann = _amp * N_COINS
-
-	var D_P, Dprev, nCoinsPow uint256.Int
-	nCoinsPow.Set(NumTokensU256) // N_COINS^N_COINS
-	nCoinsPow.Mul(&nCoinsPow, NumTokensU256)
-
-	for range 255 {
-		D_P.Set(&D)
-		for _, x := range _xp {
-			D_P.Mul(&D_P, &D)
-			D_P.Div(&D_P, &x)
-		}
-		D_P.Div(&D_P, &nCoinsPow) // D_P //= N_COINS**N_COINS
-		Dprev.Set(&D)
-
-		// (Ann * S / A_MULTIPLIER + D_P * N_COINS) * D / ((Ann - A_MULTIPLIER) * D / A_MULTIPLIER + (N_COINS + 1) * D_P)
-		var numerator, denominator, temp uint256.Int
-
-		// numerator = (Ann * S / A_MULTIPLIER + D_P * N_COINS) * D
-		numerator.Mul(&Ann, &S)
-		numerator.Div(&numerator, AMultiplier)
-		temp.Mul(&D_P, NumTokensU256)
-		numerator.Add(&numerator, &temp)
-		numerator.Mul(&numerator, &D)
-
-		// denominator = (Ann - A_MULTIPLIER) * D / A_MULTIPLIER + (N_COINS + 1) * D_P
-		denominator.Sub(&Ann, AMultiplier)
-		denominator.Mul(&denominator, &D)
-		denominator.Div(&denominator, AMultiplier)
-		temp.AddUint64(NumTokensU256, 1) // N_COINS + 1
-		temp.Mul(&temp, &D_P)
-		denominator.Add(&denominator, &temp)
-
-		D.Div(&numerator, &denominator)
-
-		if D.Cmp(&Dprev) > 0 {
-			if number.Sub(&D, &Dprev).CmpUint64(1) <= 0 {
-				return &D, nil
-			}
-		} else {
-			if number.Sub(&Dprev, &D).CmpUint64(1) <= 0 {
-				return &D, nil
-			}
-		}
+	// the custom math is a stableswap get_D with A_MULTIPLIER as A_PRECISION
+	var D uint256.Int
+	if err := stableswap.GetD(_xp, _amp, AMultiplier, stableswap.DPPowN, &D); err != nil {
+		return nil, err
 	}
-
-	return nil, ErrDDoesNotConverge
+	return &D, nil
 }
 
 func cbrt(x *uint256.Int) *uint256.Int {
 	var res uint256.Int
-	_cbrt(x, &res)
+	cryptoswap.Cbrt(x, &res)
 	return &res
 }
 
-func _cbrt(x *uint256.Int, a *uint256.Int) {
-	var xx *uint256.Int
-	if x.Cmp(CbrtConst1) >= 0 {
-		xx = x
-	} else if x.Cmp(CbrtConst2) >= 0 {
-		xx = number.Mul(x, U_1e18)
-	} else {
-		xx = number.Mul(x, U_1e36)
-	}
-
-	// log2x: int256 = convert(self._snekmate_log_2(xx, False), int256)
-	_log2x := i256.SafeToInt256(snekmate_log_2(xx, false))
-	log2x := i256.SafeConvertToUInt256(_log2x)
-
-	// # When we divide log2x by 3, the remainder is (log2x % 3).
-	// # So if we just multiply 2**(log2x/3) and discard the remainder to calculate our
-	// # guess, the newton method will need more iterations to converge to a solution,
-	// # since it is missing that precision. It's a few more calculations now to do less
-	// # calculations later:
-	// # pow = log2(x) // 3
-	// # remainder = log2(x) % 3
-	// # initial_guess = 2 ** pow * cbrt(2) ** remainder
-	// # substituting -> 2 = 1.26 ≈ 1260 / 1000, we get:
-	// #
-	// # initial_guess = 2 ** pow * 1260 ** remainder // 1000 ** remainder
-
-	var remainder uint256.Int
-	remainder.Mod(log2x, number.Number_3)
-	a.Div(
-		number.Mul(
-			pow_mod256(number.Number_2, number.Div(log2x, number.Number_3)), // # <- pow
-			pow_mod256(U_1260, &remainder),
-		),
-		pow_mod256(U_1e3, &remainder),
-	)
-
-	// # Because we chose good initial values for cube roots, 7 newton raphson iterations
-	// # are just about sufficient. 6 iterations would result in non-convergences, and 8
-	// # would be one too many iterations. Without initial values, the iteration count
-	// # can go up to 20 or greater. The iterations are unrolled. This reduces gas costs
-	// # but takes up more bytecode:
-	a.Div(number.Add(number.Mul(number.Number_2, a), number.Div(xx, number.Mul(a, a))), number.Number_3)
-	a.Div(number.Add(number.Mul(number.Number_2, a), number.Div(xx, number.Mul(a, a))), number.Number_3)
-	a.Div(number.Add(number.Mul(number.Number_2, a), number.Div(xx, number.Mul(a, a))), number.Number_3)
-	a.Div(number.Add(number.Mul(number.Number_2, a), number.Div(xx, number.Mul(a, a))), number.Number_3)
-	a.Div(number.Add(number.Mul(number.Number_2, a), number.Div(xx, number.Mul(a, a))), number.Number_3)
-	a.Div(number.Add(number.Mul(number.Number_2, a), number.Div(xx, number.Mul(a, a))), number.Number_3)
-	a.Div(number.Add(number.Mul(number.Number_2, a), number.Div(xx, number.Mul(a, a))), number.Number_3)
-
-	if x.Cmp(CbrtConst1) >= 0 {
-		a.Mul(a, U_1e12)
-	} else if x.Cmp(CbrtConst2) >= 0 {
-		a.Mul(a, U_1e6)
-	}
-}
-
 func pow_mod256(x, y *uint256.Int) *uint256.Int {
 	var z uint256.Int
 	z.Exp(x, y)
 	return &z
 }
 
-func snekmate_log_2(x *uint256.Int, roundup bool) *uint256.Int {
-	var result uint256.Int
-	_snekmate_log_2(x, roundup, &result)
-	return &result
-}
-
-func _snekmate_log_2(x *uint256.Int, roundup bool, result *uint256.Int) {
-	/*
-	   @notice An `internal` helper function that returns the log in base 2
-	        of `x`, following the selected rounding direction.
-	   @dev This implementation is derived from Snekmate, which is authored
-	        by pcaversaccio (Snekmate), distributed under the AGPL-3.0 license.
-	        https://github.com/pcaversaccio/snekmate
-	   @dev Note that it returns 0 if given 0. The implementation is
-	        inspired by OpenZeppelin's implementation here:
-	        https://github.com/OpenZeppelin/openzeppelin-contracts/blob/master/contracts/utils/math/Math.sol.
-	   @param x The 32-byte variable.
-	   @param roundup The Boolean variable that specifies whether
-	          to round up or not. The default `False` is round down.
-	   @return uint256 The 32-byte calculation result.
-	*/
-	value := number.Set(x)
-	result.Clear()
-	var tmp, const1 uint256.Int
-
-	// # The following lines cannot overflow because we have the well-known
-	// # decay behaviour of `log_2(max_value(uint256)) < max_value(uint256)`.
-	tmp.Rsh(x, 128)
-	if !tmp.IsZero() {
-		value.Rsh(x, 128)
-		result.SetUint64(128)
-	}
-	tmp.Rsh(value, 64)
-	if !tmp.IsZero() {
-		value.Rsh(value, 64)
-		result.AddUint64(result, 64)
-	}
-	tmp.Rsh(value, 32)
-	if !tmp.IsZero() {
-		value.Rsh(value, 32)
-		result.AddUint64(result, 32)
-	}
-	tmp.Rsh(value, 16)
-	if !tmp.IsZero() {
-		value.Rsh(value, 16)
-		result.AddUint64(result, 16)
-	}
-	tmp.Rsh(value, 8)
-	if !tmp.IsZero() {
-		value.Rsh(value, 8)
-		result.AddUint64(result, 8)
-	}
-	tmp.Rsh(value, 4)
-	if !tmp.IsZero() {
-		value.Rsh(value, 4)
-		result.AddUint64(result, 4)
-	}
-	tmp.Rsh(value, 2)
-	if !tmp.IsZero() {
-		value.Rsh(value, 2)
-		result.AddUint64(result, 2)
-	}
-	tmp.Rsh(value, 1)
-	if !tmp.IsZero() {
-		result.AddUint64(result, 1)
-	}
-	const1.Lsh(number.Number_1, uint(result.Uint64()))
-	if roundup && const1.Cmp(x) < 0 {
-		result.AddUint64(result, 1)
-	}
-}
-
 // Calculate x[i] given A, gamma, xp and D using newton's method with safety checks.
 // https://github.com/curvefi/twocrypto-ng/blob/d21b270/contracts/main/CurveCryptoMathOptimized2.vy#L210
 func newton_y( // nolint:unused
@@ -804,123 +456,7 @@ func _newton_y(
 	// output
 	y *uint256.Int,
 ) error {
-	x_j := &x[1-i]
-	y.Div(number.Mul(D, D), number.Mul(x_j, number.Mul(NumTokensU256, NumTokensU256)))
-	K0i := number.Div(number.Mul(number.Mul(U_1e18, NumTokensU256), x_j), D)
-
-	// assert (K0_i >= unsafe_div(10**36, lim_mul)) and (K0_i <= lim_mul)  # dev: unsafe values x[i]
-	if K0i.Cmp(number.Div(U_1e36, lim_mul)) < 0 || K0i.Cmp(lim_mul) > 0 {
-		return ErrUnsafeXi
-	}
-
-	// convergence_limit: uint256 = max(max(x_j / 10**14, D / 10**14), 100)
-	var convergenceLimit = number.Div(x_j, U_1e14)
-	var temp = number.Div(D, U_1e14)
-	if temp.Cmp(convergenceLimit) > 0 {
-		convergenceLimit = temp
-	}
-	if convergenceLimit.CmpUint64(100) < 0 {
-		convergenceLimit.SetUint64(100)
-	}
-
-	var yPrev, K0, S, _g1k0, mul1, yfprime uint256.Int
-	De18 := number.SafeMul(D, U_1e18)
-
-	for range 255 {
-		yPrev.Set(y)
-		K0.Div(number.SafeMul(number.SafeMul(K0i, y), NumTokensU256), D)
-		S.Add(x_j, y)
-
-		_g1k0.Add(gamma, U_1e18)
-		if _g1k0.Cmp(&K0) > 0 {
-			number.SafeAddZ(number.SafeSub(&_g1k0, &K0), number.Number_1, &_g1k0)
-		} else {
-			number.SafeAddZ(number.SafeSub(&K0, &_g1k0), number.Number_1, &_g1k0)
-		}
-
-		// mul1 = 10**18 * D / gamma * _g1k0 / gamma * _g1k0 * A_MULTIPLIER / ANN
-		mul1.Div(
-			number.SafeMul(
-				number.Div(
-					number.SafeMul(
-						number.Div(De18, gamma),
-						&_g1k0,
-					), gamma,
-				),
-				number.SafeMul(&_g1k0, AMultiplier),
-			), ann)
-
-		// mul2 = 10**18 + (2 * 10**18) * K0 / _g1k0
-		var mul2 = number.SafeAdd(
-			U_1e18,
-			number.Div(number.SafeMul(U_2e18, &K0), &_g1k0),
-		)
-
-		// yfprime = 10**18 * y + S * mul2 + mul1
-		number.SafeAddZ(
-			number.SafeAdd(number.SafeMul(U_1e18, y), number.SafeMul(&S, mul2)),
-			&mul1, &yfprime)
-		var _dyfprime = number.SafeMul(D, mul2)
-		if yfprime.Cmp(_dyfprime) < 0 {
-			y.Div(&yPrev, number.Number_2)
-			continue
-		} else {
-			number.SafeSubZ(&yfprime, _dyfprime, &yfprime)
-		}
-
-		if y.IsZero() {
-			return ErrZero
-		}
-
-		var fprime = number.Div(&yfprime, y)
-
-		if fprime.IsZero() {
-			return ErrZero
-		}
-
-		var yMinus = number.Div(&mul1, fprime)
-		var yPlus = number.SafeAdd(number.Div(
-			number.SafeAdd(&yfprime, De18),
-			fprime),
-			number.Div(number.SafeMul(yMinus, U_1e18), &K0))
-		number.SafeAddZ(yMinus, number.Div(number.SafeMul(U_1e18, &S), fprime), yMinus)
-		if yPlus.Cmp(yMinus) < 0 {
-			y.Div(&yPrev, number.Number_2)
-		} else {
-			number.SafeSubZ(yPlus, yMinus, y)
-		}
-		var diff uint256.Int
-		if y.Cmp(&yPrev) > 0 {
-			diff.Sub(y, &yPrev)
-		} else {
-			diff.Sub(&yPrev, y)
-		}
-		var t = number.Div(y, U_1e14)
-		if convergenceLimit.Cmp(t) > 0 {
-			t = convergenceLimit
-		}
-		if diff.Cmp(t) < 0 {
-			return nil
-		}
-	}
-	return ErrYDoesNotConverge
-}
-
-func reductionCoefficient(x []uint256.Int, feeGamma *uint256.Int, K *uint256.Int) error {
-	var S uint256.Int
-	number.SafeAddZ(&x[0], &x[1], &S)
-	if S.IsZero() {
-		return ErrZero
-	}
-
-	K.Mul(U_1e18, number.Mul(NumTokensU256, NumTokensU256))
-	K.Div(number.SafeMul(K, &x[0]), &S)
-	K.Div(number.SafeMul(K, &x[1]), &S)
-
-	K.Div(
-		number.SafeMul(feeGamma, U_1e18),
-		number.SafeSub(number.SafeAdd(feeGamma, U_1e18), K))
-	return nil
+	return cryptoswap.NewtonY2(ann, gamma, x, D, i, lim_mul, y)
 }
 
 func (t *PoolSimulator) _A_gamma() (*uint256.Int, *uint256.Int) {
@@ -958,107 +494,6 @@ func (t *PoolSimulator) _A_gamma_inplace(A, gamma *uint256.Int) {
 	}
 }
 
-func wad_exp(x *int256.Int) (*uint256.Int, error) {
-	/*
-	   @dev Calculates the natural exponential function of a signed integer with
-	        a precision of 1e18.
-	   @notice Note that this function consumes about 810 gas units. The implementation
-	           is inspired by Remco Bloemen's implementation under the MIT license here:
-	           https://xn--2-umb.com/22/exp-ln.
-	   @dev This implementation is derived from Snekmate, which is authored
-	        by pcaversaccio (Snekmate), distributed under the AGPL-3.0 license.
-	        https://github.com/pcaversaccio/snekmate
-	   @param x The 32-byte variable.
-	   @return int256 The 32-byte calculation result.
-	*/
-
-	// # If the result is `< 0.5`, we return zero. This happens when we have the following:
-	// # "x <= floor(log(0.5e18) * 1e18) ~ -42e18".
-	if x.Cmp(i256.MustFromDecimal("-42139678854452767551")) <= 0 {
-		var z uint256.Int
-		return &z, nil
-	}
-
-	// # When the result is "> (2 ** 255 - 1) / 1e18" we cannot represent it as a signed integer.
-	// # This happens when "x >= floor(log((2 ** 255 - 1) / 1e18) * 1e18) ~ 135".
-	if x.Cmp(i256.MustFromDecimal("135305999368893231589")) >= 0 {
-		return nil, ErrWadExpOverflow
-	}
-
-	// # `x` is now in the range "(-42, 136) * 1e18". Convert to "(-42, 136) * 2 ** 96" for higher
-	// # intermediate precision and a binary base. This base conversion is a multiplication with
-	// # "1e18 / 2 ** 96 = 5 ** 18 / 2 ** 78".
-	value := i256.Div(i256.Lsh(x, 78), i256.MustFromDecimal("3814697265625"))
-
-	// # Reduce the range of `x` to "(-½ ln 2, ½ ln 2) * 2 ** 96" by factoring out powers of two
-	// # so that "exp(x) = exp(x') * 2 ** k", where `k` is a signer integer. Solving this gives
-	// # "k = round(x / log(2))" and "x' = x - k * log(2)". Thus, `k` is in the range "[-61, 195]".
-	k := i256.Rsh(
-		i256.Add(
-			i256.Div(
-				i256.Lsh(value, 96),
-				i256.MustFromDecimal("54916777467707473351141471128")),
-			i256.MustFromDecimal("39614081257132168796771975168")),
-		96)
-	value = i256.Sub(value, i256.Mul(k, i256.MustFromDecimal("54916777467707473351141471128")))
-
-	// # Evaluate using a "(6, 7)"-term rational approximation. Since `p` is monic,
-	// # we will multiply by a scaling factor later.
-	y := i256.Add(
-		i256.Rsh(
-			i256.Mul(i256.Add(value, i256.MustFromDecimal("1346386616545796478920950773328")), value),
-			96),
-		i256.MustFromDecimal("57155421227552351082224309758442"))
-	p := i256.Add(
-		i256.Mul(
-			i256.Add(
-				i256.Rsh(
-					i256.Mul(
-						i256.Sub(i256.Add(y, value),
-							i256.MustFromDecimal("94201549194550492254356042504812")),
-						y),
-					96),
-				i256.MustFromDecimal("28719021644029726153956944680412240")),
-			value),
-		i256.Lsh(i256.MustFromDecimal("4385272521454847904659076985693276"), 96),
-	)
-
-	// # We leave `p` in the "2 ** 192" base so that we do not have to scale it up
-	// # again for the division.
-	q := i256.Add(
-		i256.Rsh(
-			i256.Mul(i256.Sub(value, i256.MustFromDecimal("2855989394907223263936484059900")), value),
-			96),
-		i256.MustFromDecimal("50020603652535783019961831881945"))
-	q = i256.Sub(i256.Rsh(i256.Mul(q, value), 96), i256.MustFromDecimal("533845033583426703283633433725380"))
-	q = i256.Add(i256.Rsh(i256.Mul(q, value), 96), i256.MustFromDecimal("3604857256930695427073651918091429"))
-	q = i256.Sub(i256.Rsh(i256.Mul(q, value), 96), i256.MustFromDecimal("14423608567350463180887372962807573"))
-	q = i256.Add(i256.Rsh(i256.Mul(q, value), 96), i256.MustFromDecimal("26449188498355588339934803723976023"))
-
-	// # The polynomial `q` has no zeros in the range because all its roots are complex.
-	// # No scaling is required, as `p` is already "2 ** 96" too large. Also,
-	// # `r` is in the range "(0.09, 0.25) * 2**96" after the division.
-	r := i256.Div(p, q)
-
-	// # To finalise the calculation, we have to multiply `r` by:
-	// #   - the scale factor "s = ~6.031367120",
-	// #   - the factor "2 ** k" from the range reduction, and
-	// #   - the factor "1e18 / 2 ** 96" for the base conversion.
-	// # We do this all at once, with an intermediate result in "2**213" base,
-	// # so that the final right shift always gives a positive value.
-
-	// # Note that to circumvent Vyper's safecast feature for the potentially
-	// # negative parameter value `r`, we first convert `r` to `bytes32` and
-	// # subsequently to `uint256`. Remember that the EVM default behaviour is
-	// # to use two's complement representation to handle signed integers.
-	tmp := number.Mul(i256.UnsafeToUInt256(r),
-		uint256.MustFromDecimal("3822833074963236453042738258902158003155416615667"))
-	n := 195 - k.Int64()
-	var z uint256.Int
-	z.Rsh(tmp, uint(n))
-	return &z, nil
-}
-
 func get_p(
 	useCustomMath bool,
 	_xp [NumTokens]uint256.Int,
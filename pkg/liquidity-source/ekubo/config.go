@@ -3,6 +3,7 @@ package ekubo
 import (
 	"github.com/ethereum/go-ethereum/common"
 
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/reorg"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
 )
 
@@ -16,6 +17,7 @@ type Config struct {
 	BasicDataFetcher string              `json:"basicDataFetcher"`
 	TwammDataFetcher string              `json:"twammDataFetcher"`
 	Router           string              `json:"router"`
+	Reorg            reorg.Config        `json:"reorg"`
 
 	supportedExtensions map[common.Address]ExtensionType
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/ekubo/abis"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/ekubo/pools"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/events"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/poolfactory"
 )
 
@@ -36,18 +37,7 @@ func NewPoolFactory(config *Config) *EventParser {
 }
 
 func (e *EventParser) Decode(ctx context.Context, logs []types.Log) (map[string][]types.Log, error) {
-	addressLogs := make(map[string][]types.Log)
-	for _, log := range logs {
-		poolAddresses, err := e.DecodePoolAddressesFromFactoryLog(ctx, log)
-		if err != nil {
-			return nil, err
-		}
-
-		for _, poolAddress := range poolAddresses {
-			addressLogs[poolAddress] = append(addressLogs[poolAddress], log)
-		}
-	}
-	return addressLogs, nil
+	return events.Decode(ctx, logs, e.DecodePoolAddressesFromFactoryLog)
 }
 
 func (e *EventParser) DecodePoolAddressesFromFactoryLog(_ context.Context, log types.Log) ([]string, error) {
@@ -12,6 +12,7 @@ import (
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/ekubo/math"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/ekubo/pools"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/ekubo/quoting"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
@@ -142,6 +143,37 @@ func (p *PoolSimulator) quoteWithZeroChecksAndBaseGasCost(amountBig *big.Int, is
 	return quote, nil
 }
 
+// GetSpotPrice returns the price at the current sqrt ratio, net of the pool fee. Only pools without a swap-dependent
+// extension are priced analytically.
+func (p *PoolSimulator) GetSpotPrice(tokenIn, tokenOut string) (float64, error) {
+	tokenInIndex, tokenOutIndex := p.GetTokenIndex(tokenIn), p.GetTokenIndex(tokenOut)
+	if tokenInIndex < 0 || tokenOutIndex < 0 || tokenInIndex == tokenOutIndex {
+		return 0, pool.ErrTokenNotAvailable
+	}
+
+	var sqrtRatio, liquidity *uint256.Int
+	switch ekuboPool := p.EkuboPool.(type) {
+	case *pools.BasePool:
+		sqrtRatio, liquidity = ekuboPool.SqrtRatio, ekuboPool.Liquidity
+	case *pools.FullRangePool:
+		sqrtRatio, liquidity = ekuboPool.SqrtRatio, ekuboPool.Liquidity
+	case *pools.OraclePool:
+		sqrtRatio, liquidity = ekuboPool.SqrtRatio, ekuboPool.Liquidity
+	default:
+		return 0, pool.ErrSpotPriceUnsupported
+	}
+	if liquidity == nil || liquidity.IsZero() {
+		return 0, pool.ErrSpotPriceUnsupported
+	}
+
+	sqrtPrice := sqrtRatio.Float64() / 0x1p128
+	price := sqrtPrice * sqrtPrice
+	if tokenInIndex == 1 {
+		price = 1 / price
+	}
+	return price * (1 - float64(p.GetKey().Config.Fee)/0x1p64), nil
+}
+
 func (p *PoolSimulator) CloneState() pool.IPoolSimulator {
 	cloned := *p
 	cloned.EkuboPool = p.EkuboPool.CloneState().(EkuboPool)
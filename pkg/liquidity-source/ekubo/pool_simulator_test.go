@@ -484,6 +484,16 @@ func (ts *PoolSimulatorTestSuite) TestCalcAmountIn() {
 	}
 }
 
+func (ts *PoolSimulatorTestSuite) TestGetSpotPrice() {
+	ts.T().Parallel()
+
+	for p, sim := range ts.sims {
+		ts.T().Run(p, func(t *testing.T) {
+			testutil.TestSpotPrice(t, sim, 1)
+		})
+	}
+}
+
 func TestPoolSimulatorTestSuite(t *testing.T) {
 	t.Parallel()
 	suite.Run(t, new(PoolSimulatorTestSuite))
@@ -18,6 +18,7 @@ import (
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/ekubo/abis"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/ekubo/pools"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/reorg"
 	pooltrack "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/tracker"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/eth"
 )
@@ -26,6 +27,7 @@ type PoolTracker struct {
 	config       *Config
 	ethrpcClient *ethrpc.Client
 	dataFetcher  *dataFetchers
+	reorgGuard   *reorg.Guard
 }
 
 var _ = pooltrack.RegisterFactoryCE0(DexType, NewPoolTracker)
@@ -35,6 +37,7 @@ func NewPoolTracker(config *Config, ethrpcClient *ethrpc.Client) *PoolTracker {
 		config:       config,
 		ethrpcClient: ethrpcClient,
 		dataFetcher:  NewDataFetchers(ethrpcClient, config),
+		reorgGuard:   reorg.NewGuard(config.Reorg),
 	}
 }
 
@@ -106,6 +109,15 @@ func (d *PoolTracker) GetNewPoolState(
 	ctx context.Context,
 	p entity.Pool,
 	params pool.GetNewPoolStateParams,
+) (entity.Pool, error) {
+	// without logs, getNewPoolState refreshes the whole state through RPC, which also serves deep reorgs
+	return d.reorgGuard.GetNewPoolState(ctx, p, params, d.applyLogsOrRefresh, nil)
+}
+
+func (d *PoolTracker) applyLogsOrRefresh(
+	ctx context.Context,
+	p entity.Pool,
+	params pool.GetNewPoolStateParams,
 ) (entity.Pool, error) {
 	return d.getNewPoolState(ctx, p, params, nil)
 }
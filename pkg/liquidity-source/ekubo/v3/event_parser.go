@@ -15,6 +15,7 @@ import (
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/ekubo/v3/abis"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/ekubo/v3/pools"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/events"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/poolfactory"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
 )
@@ -34,18 +35,7 @@ func NewPoolFactory(config *Config, ethrpcClient *ethrpc.Client) *EventParser {
 }
 
 func (e *EventParser) Decode(ctx context.Context, logs []types.Log) (map[string][]types.Log, error) {
-	addressLogs := make(map[string][]types.Log)
-	for _, log := range logs {
-		poolAddresses, err := e.DecodePoolAddressesFromFactoryLog(ctx, log)
-		if err != nil {
-			return nil, err
-		}
-
-		for _, poolAddress := range poolAddresses {
-			addressLogs[poolAddress] = append(addressLogs[poolAddress], log)
-		}
-	}
-	return addressLogs, nil
+	return events.Decode(ctx, logs, e.DecodePoolAddressesFromFactoryLog)
 }
 
 func (e *EventParser) DecodePoolAddressesFromFactoryLog(_ context.Context, log types.Log) ([]string, error) {
@@ -0,0 +1,150 @@
+package evmquoter
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/evmstate"
+)
+
+// callArgs are the values of the placeholders of a call.
+type callArgs struct {
+	pool      common.Address
+	swapper   common.Address
+	tokenIn   common.Address
+	tokenOut  common.Address
+	amountIn  *big.Int
+	amountOut *big.Int
+}
+
+// validate checks that the method has as many arguments as its signature, all of them static and valid.
+func (m *Method) validate() error {
+	open, closing := strings.IndexByte(m.Signature, '('), strings.LastIndexByte(m.Signature, ')')
+	if open <= 0 || closing != len(m.Signature)-1 {
+		return fmt.Errorf("%w: malformed signature %q", ErrInvalidMethod, m.Signature)
+	}
+	var params []string
+	if open+1 < closing {
+		params = strings.Split(m.Signature[open+1:closing], ",")
+	}
+	if len(params) != len(m.Args) {
+		return fmt.Errorf("%w: %q takes %d arguments, got %d", ErrInvalidMethod, m.Signature, len(params),
+			len(m.Args))
+	}
+	for _, param := range params {
+		if param == "string" || param == "bytes" || strings.ContainsAny(param, "[(") {
+			return fmt.Errorf("%w: %q has dynamic argument %s", ErrInvalidMethod, m.Signature, param)
+		}
+	}
+	if m.Output < 0 {
+		return fmt.Errorf("%w: negative output index", ErrInvalidMethod)
+	}
+	var args callArgs
+	_, err := m.pack(&args)
+	return err
+}
+
+// pack returns the input of a call of the method.
+func (m *Method) pack(args *callArgs) ([]byte, error) {
+	input := make([]byte, 4, 4+wordSize*len(m.Args))
+	copy(input, crypto.Keccak256([]byte(m.Signature)))
+	for _, arg := range m.Args {
+		word, err := args.word(arg)
+		if err != nil {
+			return nil, err
+		}
+		input = append(input, word[:]...)
+	}
+	return input, nil
+}
+
+// output returns the return word of the amount out.
+func (m *Method) output(ret []byte) (*big.Int, error) {
+	if len(ret) < (m.Output+1)*wordSize {
+		return nil, ErrInvalidReturn
+	}
+	return new(big.Int).SetBytes(ret[m.Output*wordSize : (m.Output+1)*wordSize]), nil
+}
+
+func (a *callArgs) word(arg string) (common.Hash, error) {
+	switch arg {
+	case ArgPool:
+		return common.BytesToHash(a.pool[:]), nil
+	case ArgSwapper:
+		return common.BytesToHash(a.swapper[:]), nil
+	case ArgTokenIn:
+		return common.BytesToHash(a.tokenIn[:]), nil
+	case ArgTokenOut:
+		return common.BytesToHash(a.tokenOut[:]), nil
+	case ArgAmountIn:
+		return amountWord(a.amountIn)
+	case ArgAmountOut:
+		return amountWord(a.amountOut)
+	case "true":
+		return common.Hash{31: 1}, nil
+	case "false":
+		return common.Hash{}, nil
+	}
+	if len(arg) == 2+2*common.AddressLength && common.IsHexAddress(arg) {
+		return common.BytesToHash(common.HexToAddress(arg).Bytes()), nil
+	}
+	value, ok := new(big.Int).SetString(arg, 0)
+	if !ok || value.BitLen() > 256 {
+		return common.Hash{}, fmt.Errorf("%w: invalid argument %q", ErrInvalidMethod, arg)
+	}
+	return common.Hash(math.U256Bytes(value)), nil // two's complement if negative
+}
+
+func amountWord(amount *big.Int) (common.Hash, error) {
+	if amount == nil {
+		return common.Hash{}, nil
+	} else if amount.Sign() < 0 || amount.BitLen() > 256 {
+		return common.Hash{}, fmt.Errorf("%w: amount %s out of range", ErrInvalidMethod, amount)
+	}
+	return common.BigToHash(amount), nil
+}
+
+// quoteMsg returns the quote call of args.
+func (e *StaticExtra) quoteMsg(args *callArgs) (*evmstate.Msg, error) {
+	input, err := e.Quote.pack(args)
+	if err != nil {
+		return nil, err
+	}
+	return &evmstate.Msg{ChainID: e.ChainID, From: e.Swapper, To: common.HexToAddress(e.Quote.Target), Input: input},
+		nil
+}
+
+// swapMsg returns the swap call of args, funding the swapper with amountIn of tokenIn approved to the swap target.
+func (e *StaticExtra) swapMsg(args *callArgs, balanceSlot, allowanceSlot common.Hash) (*evmstate.Msg, error) {
+	if balanceSlot == (common.Hash{}) || allowanceSlot == (common.Hash{}) {
+		return nil, ErrSlotNotFound
+	}
+	input, err := e.Swap.pack(args)
+	if err != nil {
+		return nil, err
+	}
+	return &evmstate.Msg{
+		ChainID: e.ChainID,
+		From:    e.Swapper,
+		To:      common.HexToAddress(e.Swap.Target),
+		Input:   input,
+		Storage: map[common.Address]map[common.Hash]common.Hash{args.tokenIn: {
+			balanceSlot:   common.BigToHash(args.amountIn),
+			allowanceSlot: common.BigToHash(maxUint256),
+		}},
+	}, nil
+}
+
+// tokenMsg returns the call of an ERC20 view of token, taking addresses.
+func (e *StaticExtra) tokenMsg(token common.Address, signature string, addrs ...common.Address) *evmstate.Msg {
+	input := crypto.Keccak256([]byte(signature))[:4]
+	for _, addr := range addrs {
+		input = append(input, common.BytesToHash(addr[:]).Bytes()...)
+	}
+	return &evmstate.Msg{ChainID: e.ChainID, From: e.Swapper, To: token, Input: input}
+}
@@ -0,0 +1,51 @@
+package evmquoter
+
+import "github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+
+type Config struct {
+	DexID   string              `json:"dexID"`
+	ChainID valueobject.ChainID `json:"chainId"`
+	Pools   []PoolConfig        `json:"pools"`
+}
+
+// PoolConfig describes a pool quoted by calling its contracts. For example, a pool with a router quoting
+// quote(tokenIn, tokenOut, amountIn):
+//
+//	{
+//	  "address": "0x...",
+//	  "tokens": ["0x...", "0x..."],
+//	  "quote": {"target": "0x<router>", "signature": "quote(address,address,uint256)",
+//	    "args": ["$tokenIn", "$tokenOut", "$amountIn"]},
+//	  "swap": {"target": "0x<router>", "signature": "swap(address,address,uint256,uint256,address)",
+//	    "args": ["$tokenIn", "$tokenOut", "$amountIn", "$amountOut", "$swapper"]}
+//	}
+type PoolConfig struct {
+	Address string   `json:"address"`
+	Tokens  []string `json:"tokens"`
+
+	// Quote returns the amount out of swapping amountIn of tokenIn.
+	Quote Method `json:"quote"`
+	// Swap, if set, swaps from the swapper with the pool so that the simulator can apply its state changes, instead
+	// of only moving the reserves. The swapper is funded with amountIn of tokenIn and approves the target beforehand.
+	Swap *Method `json:"swap,omitempty"`
+
+	// ReserveHolder holds the token reserves of the pool, the pool itself if empty.
+	ReserveHolder string `json:"reserveHolder,omitempty"`
+	// Swapper is the account quotes and swaps are made from, for pools whitelisting their callers.
+	Swapper string `json:"swapper,omitempty"`
+	// Gas is the gas cost of a swap, the gas used by the quote if 0.
+	Gas int64 `json:"gas,omitempty"`
+}
+
+// Method is a contract call whose arguments are all static, i.e. one word each.
+type Method struct {
+	// Target is the contract called, the pool itself if empty.
+	Target string `json:"target,omitempty"`
+	// Signature is the canonical function signature, e.g. "quote(address,address,uint256)".
+	Signature string `json:"signature"`
+	// Args are the arguments of the call, each either a placeholder such as ArgAmountIn, or a literal address, decimal
+	// or 0x-prefixed integer, or boolean.
+	Args []string `json:"args"`
+	// Output is the index of the return word holding the amount out.
+	Output int `json:"output,omitempty"`
+}
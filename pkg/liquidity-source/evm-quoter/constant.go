@@ -0,0 +1,50 @@
+package evmquoter
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+const (
+	DexType = valueobject.ExchangeEVMQuoter
+
+	methodBalanceOf = "balanceOf(address)"
+	methodAllowance = "allowance(address,address)"
+
+	// wordSize is the size of an ABI-encoded static argument or return value.
+	wordSize = 32
+)
+
+// Placeholders of Method.Args, filled in per call. Any other argument is a literal.
+const (
+	ArgTokenIn   = "$tokenIn"
+	ArgTokenOut  = "$tokenOut"
+	ArgAmountIn  = "$amountIn"
+	ArgAmountOut = "$amountOut"
+	ArgPool      = "$pool"
+	ArgSwapper   = "$swapper"
+)
+
+var (
+	// defaultSwapper is the account quotes and swaps are simulated from, unless the pool configures one.
+	defaultSwapper = common.HexToAddress("0x00000000000000000000000000000000000e7e11")
+
+	// probeDivisors size the quotes and swaps the tracker probes the pool with, as fractions of its reserves.
+	probeDivisors = []int64{10_000, 100, 5}
+
+	maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+)
+
+var (
+	ErrInvalidToken    = errors.New("evm-quoter: invalid token")
+	ErrZeroAmount      = errors.New("evm-quoter: zero amount")
+	ErrNoState         = errors.New("evm-quoter: pool state not tracked")
+	ErrInvalidMethod   = errors.New("evm-quoter: invalid method")
+	ErrInvalidReturn   = errors.New("evm-quoter: return value too short")
+	ErrSlotNotFound    = errors.New("evm-quoter: token storage slot not found")
+	ErrInsufficientOut = errors.New("evm-quoter: amount out exceeds reserve")
+)
@@ -0,0 +1,103 @@
+package evmquoter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+	"github.com/samber/lo"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	poollist "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/list"
+)
+
+type PoolsListUpdater struct {
+	config         *Config
+	ethrpcClient   *ethrpc.Client
+	hasInitialized bool
+}
+
+var _ = poollist.RegisterFactoryCE(DexType, NewPoolsListUpdater)
+
+func NewPoolsListUpdater(cfg *Config, ethrpcClient *ethrpc.Client) *PoolsListUpdater {
+	return &PoolsListUpdater{config: cfg, ethrpcClient: ethrpcClient}
+}
+
+// GetNewPools returns the pools of the config, once. Reserves are left at "0" for the tracker to fill in along with
+// the state.
+func (u *PoolsListUpdater) GetNewPools(_ context.Context, _ []byte) ([]entity.Pool, []byte, error) {
+	if u.hasInitialized {
+		return nil, nil, nil
+	}
+
+	pools := make([]entity.Pool, 0, len(u.config.Pools))
+	for i := range u.config.Pools {
+		p, err := u.newPool(&u.config.Pools[i])
+		if err != nil {
+			logger.WithFields(logger.Fields{"dexId": u.config.DexID, "pool": u.config.Pools[i].Address}).
+				Errorf("invalid pool config: %v", err)
+			return nil, nil, err
+		}
+		pools = append(pools, p)
+	}
+	u.hasInitialized = true
+
+	return pools, nil, nil
+}
+
+func (u *PoolsListUpdater) newPool(cfg *PoolConfig) (entity.Pool, error) {
+	if !common.IsHexAddress(cfg.Address) {
+		return entity.Pool{}, fmt.Errorf("invalid pool address %q", cfg.Address)
+	} else if len(cfg.Tokens) < 2 {
+		return entity.Pool{}, fmt.Errorf("pool %s has %d tokens", cfg.Address, len(cfg.Tokens))
+	}
+	poolAddress := strings.ToLower(cfg.Address)
+
+	staticExtra := StaticExtra{
+		ChainID:       u.config.ChainID,
+		Quote:         resolveMethod(cfg.Quote, poolAddress),
+		ReserveHolder: common.HexToAddress(lo.CoalesceOrEmpty(cfg.ReserveHolder, poolAddress)),
+		Swapper:       defaultSwapper,
+		Gas:           cfg.Gas,
+	}
+	if cfg.Swapper != "" {
+		staticExtra.Swapper = common.HexToAddress(cfg.Swapper)
+	}
+	if err := staticExtra.Quote.validate(); err != nil {
+		return entity.Pool{}, err
+	}
+	if cfg.Swap != nil {
+		swap := resolveMethod(*cfg.Swap, poolAddress)
+		if err := swap.validate(); err != nil {
+			return entity.Pool{}, err
+		}
+		staticExtra.Swap = &swap
+	}
+	staticExtraBytes, err := json.Marshal(staticExtra)
+	if err != nil {
+		return entity.Pool{}, err
+	}
+
+	return entity.Pool{
+		Address:   poolAddress,
+		Exchange:  u.config.DexID,
+		Type:      DexType,
+		Timestamp: time.Now().Unix(),
+		Reserves:  lo.Map(cfg.Tokens, func(string, int) string { return "0" }),
+		Tokens: lo.Map(cfg.Tokens, func(token string, _ int) *entity.PoolToken {
+			return &entity.PoolToken{Address: strings.ToLower(token), Swappable: true}
+		}),
+		StaticExtra: string(staticExtraBytes),
+	}, nil
+}
+
+// resolveMethod returns method targeting the pool if it targets nothing else.
+func resolveMethod(method Method, poolAddress string) Method {
+	method.Target = strings.ToLower(lo.CoalesceOrEmpty(method.Target, poolAddress))
+	return method
+}
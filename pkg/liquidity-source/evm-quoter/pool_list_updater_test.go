@@ -0,0 +1,74 @@
+package evmquoter
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+func TestPoolsListUpdater_GetNewPools(t *testing.T) {
+	t.Parallel()
+	u := NewPoolsListUpdater(newTestConfig(), nil)
+	pools, _, err := u.GetNewPools(context.Background(), nil)
+	require.NoError(t, err)
+	require.Len(t, pools, 1)
+
+	p := pools[0]
+	assert.Equal(t, testPool.Hex(), common.HexToAddress(p.Address).Hex())
+	assert.Equal(t, DexType, p.Type)
+	assert.Equal(t, entity.PoolReserves{"0", "0"}, p.Reserves)
+	var staticExtra StaticExtra
+	require.NoError(t, json.Unmarshal([]byte(p.StaticExtra), &staticExtra))
+	assert.Equal(t, p.Address, staticExtra.Quote.Target)
+	assert.Equal(t, p.Address, staticExtra.Swap.Target)
+	assert.Equal(t, testPool, staticExtra.ReserveHolder)
+	assert.Equal(t, defaultSwapper, staticExtra.Swapper)
+	assert.Equal(t, valueobject.ChainIDEthereum, staticExtra.ChainID)
+
+	pools, _, err = u.GetNewPools(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, pools)
+
+	cfg := newTestConfig()
+	cfg.Pools[0].Quote.Args = cfg.Pools[0].Quote.Args[1:]
+	_, _, err = NewPoolsListUpdater(cfg, nil).GetNewPools(context.Background(), nil)
+	assert.ErrorIs(t, err, ErrInvalidMethod)
+}
+
+func TestMethod_pack(t *testing.T) {
+	t.Parallel()
+	method := Method{
+		Signature: "quote(address,uint256,int24,bool,address,uint256)",
+		Args:      []string{ArgTokenIn, ArgAmountIn, "-1", "true", "0x00000000000000000000000000000000000000fF", "0x10"},
+	}
+	require.NoError(t, method.validate())
+	input, err := method.pack(&callArgs{tokenIn: testTokens[0], amountIn: big.NewInt(1000)})
+	require.NoError(t, err)
+	assert.Equal(t, common.FromHex("0x"+
+		"49ee4d73"+
+		"00000000000000000000000000000000000000000000000000000000000000b0"+
+		"00000000000000000000000000000000000000000000000000000000000003e8"+
+		"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"+
+		"0000000000000000000000000000000000000000000000000000000000000001"+
+		"00000000000000000000000000000000000000000000000000000000000000ff"+
+		"0000000000000000000000000000000000000000000000000000000000000010"), input)
+
+	for _, method := range []Method{
+		{Signature: "quote(uint256)", Args: []string{"x"}},
+		{Signature: "quote(bytes)", Args: []string{"0x"}},
+		{Signature: "quote(uint256[])", Args: []string{"1"}},
+		{Signature: "quote", Args: nil},
+		{Signature: "quote()", Args: nil, Output: -1},
+	} {
+		assert.ErrorIs(t, method.validate(), ErrInvalidMethod, method.Signature)
+	}
+	assert.NoError(t, (&Method{Signature: "quote()"}).validate())
+}
@@ -0,0 +1,163 @@
+package evmquoter
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+	"github.com/samber/lo"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/evmstate"
+)
+
+// PoolSimulator quotes by calling the quote method of the pool in the embedded EVM, against the state the tracker
+// collected. With a swap method, UpdateBalance runs the swap too and applies what it writes, so that the next quote
+// sees the pool as the swap left it.
+type PoolSimulator struct {
+	pool.Pool
+
+	staticExtra StaticExtra
+	extra       Extra
+}
+
+var _ = pool.RegisterFactory0(DexType, NewPoolSimulator)
+
+func NewPoolSimulator(ep entity.Pool) (*PoolSimulator, error) {
+	var staticExtra StaticExtra
+	if err := json.Unmarshal([]byte(ep.StaticExtra), &staticExtra); err != nil {
+		return nil, err
+	}
+	var extra Extra
+	if err := json.Unmarshal([]byte(ep.Extra), &extra); err != nil {
+		return nil, err
+	} else if extra.State == nil {
+		return nil, ErrNoState
+	}
+
+	return &PoolSimulator{
+		Pool: pool.Pool{Info: pool.PoolInfo{
+			Address:     ep.Address,
+			Exchange:    ep.Exchange,
+			Type:        ep.Type,
+			Tokens:      lo.Map(ep.Tokens, func(token *entity.PoolToken, _ int) string { return token.Address }),
+			Reserves:    lo.Map(ep.Reserves, func(reserve string, _ int) *big.Int { return bignumber.NewBig(reserve) }),
+			BlockNumber: ep.BlockNumber,
+		}},
+		staticExtra: staticExtra,
+		extra:       extra,
+	}, nil
+}
+
+func (s *PoolSimulator) CalcAmountOut(params pool.CalcAmountOutParams) (*pool.CalcAmountOutResult, error) {
+	indexIn, indexOut := s.GetTokenIndex(params.TokenAmountIn.Token), s.GetTokenIndex(params.TokenOut)
+	if indexIn < 0 || indexOut < 0 || indexIn == indexOut {
+		return nil, ErrInvalidToken
+	}
+	amountIn := params.TokenAmountIn.Amount
+	if amountIn == nil || amountIn.Sign() <= 0 {
+		return nil, ErrZeroAmount
+	}
+
+	args := s.callArgs(indexIn, indexOut, amountIn)
+	msg, err := s.staticExtra.quoteMsg(args)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.call(msg)
+	if err != nil {
+		return nil, err
+	}
+	amountOut, err := s.staticExtra.Quote.output(res.Ret)
+	if err != nil {
+		return nil, err
+	} else if amountOut.Sign() <= 0 {
+		return nil, ErrZeroAmount
+	} else if amountOut.Cmp(s.Info.Reserves[indexOut]) > 0 {
+		return nil, ErrInsufficientOut
+	}
+
+	return &pool.CalcAmountOutResult{
+		TokenAmountOut: &pool.TokenAmount{Token: params.TokenOut, Amount: amountOut},
+		Fee:            &pool.TokenAmount{Token: params.TokenAmountIn.Token, Amount: bignumber.ZeroBI},
+		Gas:            lo.CoalesceOrEmpty(s.staticExtra.Gas, res.GasUsed),
+	}, nil
+}
+
+// UpdateBalance moves the reserves by the swap and, with a swap method, applies the state changes of running it.
+// A swap failing to run, e.g. on state the tracker did not collect, only moves the reserves.
+func (s *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	indexIn, indexOut := s.GetTokenIndex(params.TokenAmountIn.Token), s.GetTokenIndex(params.TokenAmountOut.Token)
+	if indexIn < 0 || indexOut < 0 || indexIn == indexOut {
+		return
+	}
+	s.Info.Reserves[indexIn] = new(big.Int).Add(s.Info.Reserves[indexIn], params.TokenAmountIn.Amount)
+	s.Info.Reserves[indexOut] = new(big.Int).Sub(s.Info.Reserves[indexOut], params.TokenAmountOut.Amount)
+	if s.Info.Reserves[indexOut].Sign() < 0 {
+		s.Info.Reserves[indexOut].SetUint64(0)
+	}
+
+	if s.staticExtra.Swap == nil {
+		return
+	}
+	if err := s.swap(indexIn, indexOut, params.TokenAmountIn.Amount, params.TokenAmountOut.Amount); err != nil {
+		logger.WithFields(logger.Fields{"pool": s.Info.Address, "exchange": s.Info.Exchange}).
+			Warnf("failed to simulate swap, only reserves updated: %v", err)
+	}
+}
+
+func (s *PoolSimulator) swap(indexIn, indexOut int, amountIn, amountOut *big.Int) error {
+	if indexIn >= len(s.extra.BalanceSlots) || indexIn >= len(s.extra.AllowanceSlots) {
+		return ErrSlotNotFound
+	}
+	args := s.callArgs(indexIn, indexOut, amountIn)
+	args.amountOut = amountOut
+	msg, err := s.staticExtra.swapMsg(args, s.extra.BalanceSlots[indexIn], s.extra.AllowanceSlots[indexIn])
+	if err != nil {
+		return err
+	}
+	res, err := s.call(msg)
+	if err != nil {
+		return err
+	}
+	s.extra.State.Apply(res.Diff)
+	return nil
+}
+
+// CloneState clones the reserves and the state, which Apply copies on write.
+func (s *PoolSimulator) CloneState() pool.IPoolSimulator {
+	cloned := *s
+	cloned.Info.Reserves = lo.Map(s.Info.Reserves, func(reserve *big.Int, _ int) *big.Int {
+		return new(big.Int).Set(reserve)
+	})
+	cloned.extra.State = s.extra.State.Clone()
+	return &cloned
+}
+
+func (s *PoolSimulator) GetMetaInfo(_, _ string) any {
+	return MetaInfo{BlockNumber: s.Info.BlockNumber}
+}
+
+func (s *PoolSimulator) callArgs(indexIn, indexOut int, amountIn *big.Int) *callArgs {
+	return &callArgs{
+		pool:     common.HexToAddress(s.Info.Address),
+		swapper:  s.staticExtra.Swapper,
+		tokenIn:  common.HexToAddress(s.Info.Tokens[indexIn]),
+		tokenOut: common.HexToAddress(s.Info.Tokens[indexOut]),
+		amountIn: amountIn,
+	}
+}
+
+func (s *PoolSimulator) call(msg *evmstate.Msg) (*evmstate.Result, error) {
+	res, missing, err := s.extra.State.Call(msg)
+	if err != nil {
+		return nil, err
+	} else if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", evmstate.ErrStateNotCached, missing)
+	}
+	return res, nil
+}
@@ -0,0 +1,88 @@
+package evmquoter
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/evmstate"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/testutil"
+)
+
+func newTestPoolSimulator(t *testing.T) *PoolSimulator {
+	t.Helper()
+	p, _ := newTestPool(t)
+	sim, err := NewPoolSimulator(p)
+	require.NoError(t, err)
+	return sim
+}
+
+func calcAmountOut(sim pool.IPoolSimulator, tokenIn, tokenOut common.Address, amountIn int64) (*pool.CalcAmountOutResult,
+	error) {
+	return sim.CalcAmountOut(pool.CalcAmountOutParams{
+		TokenAmountIn: pool.TokenAmount{Token: hexAddress(tokenIn), Amount: big.NewInt(amountIn)},
+		TokenOut:      hexAddress(tokenOut),
+	})
+}
+
+func hexAddress(addr common.Address) string {
+	return "0x" + common.Bytes2Hex(addr[:])
+}
+
+func TestPoolSimulator_CalcAmountOut(t *testing.T) {
+	t.Parallel()
+	sim := newTestPoolSimulator(t)
+
+	res, err := calcAmountOut(sim, testTokens[0], testTokens[1], 1000)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1994), res.TokenAmountOut.Amount)
+	assert.Equal(t, int64(100000), res.Gas)
+	res, err = calcAmountOut(sim, testTokens[1], testTokens[0], 1000)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(498), res.TokenAmountOut.Amount)
+
+	_, err = calcAmountOut(sim, testTokens[0], testTokens[1], 2e6)
+	assert.ErrorIs(t, err, ErrInsufficientOut)
+	_, err = calcAmountOut(sim, testTokens[0], testTokens[1], 0)
+	assert.ErrorIs(t, err, ErrZeroAmount)
+	_, err = calcAmountOut(sim, testTokens[0], testTokens[0], 1000)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestPoolSimulator_UpdateBalance(t *testing.T) {
+	t.Parallel()
+	sim := newTestPoolSimulator(t)
+	params := pool.CalcAmountOutParams{
+		TokenAmountIn: pool.TokenAmount{Token: hexAddress(testTokens[0]), Amount: big.NewInt(1000)},
+		TokenOut:      hexAddress(testTokens[1]),
+	}
+	testutil.TestCloneState(t, sim, params, nil)
+
+	res, err := sim.CalcAmountOut(params)
+	require.NoError(t, err)
+	sim.UpdateBalance(pool.UpdateBalanceParams{TokenAmountIn: params.TokenAmountIn, TokenAmountOut: *res.TokenAmountOut})
+	assert.Equal(t, []*big.Int{big.NewInt(1e6 + 1000), big.NewInt(2e6 - 1994)}, sim.GetReserves())
+
+	// the swap decrements the rate, and moves the balances of the pool
+	res, err = sim.CalcAmountOut(params)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1993), res.TokenAmountOut.Amount)
+	state := sim.extra.State
+	assert.Equal(t, common.BigToHash(big.NewInt(1e6+1000)),
+		state.Accounts[testTokens[0]].Storage[common.BytesToHash(testPool[:])])
+	assert.Equal(t, common.BigToHash(big.NewInt(2e6-1994)),
+		state.Accounts[testTokens[1]].Storage[common.BytesToHash(testPool[:])])
+}
+
+func TestPoolSimulator_MissingState(t *testing.T) {
+	t.Parallel()
+	sim := newTestPoolSimulator(t)
+	sim.extra.State = sim.extra.State.Clone()
+	sim.extra.State.Accounts[testPool] = &evmstate.Account{Nonce: 1, Code: testPoolCode}
+	_, err := calcAmountOut(sim, testTokens[0], testTokens[1], 1000)
+	assert.ErrorIs(t, err, evmstate.ErrStateNotCached)
+}
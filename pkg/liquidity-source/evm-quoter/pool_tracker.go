@@ -0,0 +1,194 @@
+package evmquoter
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/goccy/go-json"
+	"github.com/samber/lo"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	pooltrack "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/tracker"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/evmstate"
+)
+
+type PoolTracker struct {
+	config       *Config
+	ethrpcClient *ethrpc.Client
+}
+
+var _ = pooltrack.RegisterFactoryCE(DexType, NewPoolTracker)
+
+func NewPoolTracker(cfg *Config, ethrpcClient *ethrpc.Client) (*PoolTracker, error) {
+	return &PoolTracker{config: cfg, ethrpcClient: ethrpcClient}, nil
+}
+
+func (t *PoolTracker) GetNewPoolState(
+	ctx context.Context,
+	p entity.Pool,
+	_ pool.GetNewPoolStateParams,
+) (entity.Pool, error) {
+	return t.getNewPoolState(ctx, p, nil)
+}
+
+func (t *PoolTracker) GetNewPoolStateWithOverrides(
+	ctx context.Context,
+	p entity.Pool,
+	params pool.GetNewPoolStateWithOverridesParams,
+) (entity.Pool, error) {
+	return t.getNewPoolState(ctx, p, params.Overrides)
+}
+
+// getNewPoolState collects the state the calls of the pool read: the reserves, quotes of a few sizes in both
+// directions and, if configured, the matching swaps. The calls run in the embedded EVM, and the accounts and storage
+// slots they find missing are fetched round after round until they run through.
+func (t *PoolTracker) getNewPoolState(
+	ctx context.Context,
+	p entity.Pool,
+	overrides map[common.Address]gethclient.OverrideAccount,
+) (entity.Pool, error) {
+	startTime := time.Now()
+	var staticExtra StaticExtra
+	if err := json.Unmarshal([]byte(p.StaticExtra), &staticExtra); err != nil {
+		return p, err
+	}
+
+	prober := &prober{
+		staticExtra: &staticExtra,
+		pool:        common.HexToAddress(p.Address),
+		tokens: lo.Map(p.Tokens, func(token *entity.PoolToken, _ int) common.Address {
+			return common.HexToAddress(token.Address)
+		}),
+		reserves: lo.Map(p.Tokens, func(*entity.PoolToken, int) *big.Int { return new(big.Int) }),
+	}
+	if staticExtra.Swap != nil {
+		prober.balanceSlots = make([]common.Hash, len(p.Tokens))
+		prober.allowanceSlots = make([]common.Hash, len(p.Tokens))
+	}
+	seed := evmstate.Keys{}
+	seed.Add(prober.pool)
+	seed.Add(common.HexToAddress(staticExtra.Quote.Target))
+	for _, token := range prober.tokens {
+		seed.Add(token)
+	}
+	state, err := evmstate.Collect(ctx, t.ethrpcClient, nil, overrides, seed, prober.probe)
+	if err != nil {
+		logger.WithFields(logger.Fields{"dexId": t.config.DexID, "pool": p.Address}).
+			Errorf("failed to collect pool state: %v", err)
+		return p, err
+	}
+
+	extraBytes, err := json.Marshal(Extra{
+		State:          state,
+		BalanceSlots:   prober.balanceSlots,
+		AllowanceSlots: prober.allowanceSlots,
+	})
+	if err != nil {
+		return p, err
+	}
+	p.Extra = string(extraBytes)
+	p.Reserves = lo.Map(prober.reserves, func(reserve *big.Int, _ int) string { return reserve.String() })
+	p.BlockNumber = state.BlockNumber
+	p.Timestamp = time.Now().Unix()
+
+	logger.WithFields(logger.Fields{"dexId": t.config.DexID, "pool": p.Address, "accounts": len(state.Accounts),
+		"duration": time.Since(startTime).Milliseconds()}).Info("finished getting new pool state")
+	return p, nil
+}
+
+// prober runs the calls of a pool against the state being collected, recording what they return along the way.
+type prober struct {
+	staticExtra *StaticExtra
+	pool        common.Address
+	tokens      []common.Address
+
+	reserves       []*big.Int
+	balanceSlots   []common.Hash
+	allowanceSlots []common.Hash
+}
+
+// probe runs the calls of the pool and returns the state they found missing.
+func (p *prober) probe(state *evmstate.State) evmstate.Keys {
+	missing := evmstate.Keys{}
+	call := func(msg *evmstate.Msg) *evmstate.Result {
+		res, callMissing, err := state.Call(msg)
+		missing.Merge(callMissing)
+		if err != nil || len(callMissing) > 0 {
+			return nil
+		}
+		return res
+	}
+
+	swapper, swapTarget := p.staticExtra.Swapper, common.Address{}
+	if p.staticExtra.Swap != nil {
+		swapTarget = common.HexToAddress(p.staticExtra.Swap.Target)
+	}
+	for i, token := range p.tokens {
+		if res := call(p.staticExtra.tokenMsg(token, methodBalanceOf, p.staticExtra.ReserveHolder)); res != nil {
+			p.reserves[i] = new(big.Int).SetBytes(res.Ret[:min(len(res.Ret), wordSize)])
+		}
+		if p.staticExtra.Swap == nil {
+			continue
+		}
+		msg := p.staticExtra.tokenMsg(token, methodBalanceOf, swapper)
+		msg.TraceReads = true
+		if res := call(msg); res != nil {
+			p.balanceSlots[i] = lastRead(res, token)
+		}
+		msg = p.staticExtra.tokenMsg(token, methodAllowance, swapper, swapTarget)
+		msg.TraceReads = true
+		if res := call(msg); res != nil {
+			p.allowanceSlots[i] = lastRead(res, token)
+		}
+	}
+	if len(missing) > 0 {
+		return missing // the probes below are sized by the reserves
+	}
+
+	for _, divisor := range probeDivisors {
+		for i, tokenIn := range p.tokens {
+			amountIn := new(big.Int).Div(p.reserves[i], big.NewInt(divisor))
+			if amountIn.Sign() == 0 {
+				amountIn = bignumber.One
+			}
+			for j, tokenOut := range p.tokens {
+				if i == j {
+					continue
+				}
+				// a probe may revert, as the pool may well reject some swaps
+				args := &callArgs{pool: p.pool, swapper: swapper, tokenIn: tokenIn, tokenOut: tokenOut,
+					amountIn: amountIn}
+				if msg, err := p.staticExtra.quoteMsg(args); err == nil {
+					if res := call(msg); res != nil {
+						args.amountOut, _ = p.staticExtra.Quote.output(res.Ret)
+					}
+				}
+				if p.staticExtra.Swap == nil {
+					continue
+				}
+				if msg, err := p.staticExtra.swapMsg(args, p.balanceSlots[i], p.allowanceSlots[i]); err == nil {
+					call(msg)
+				}
+			}
+		}
+	}
+	return missing
+}
+
+// lastRead returns the last storage slot of token a call read, which for the balanceOf and allowance views of an
+// ERC20 is the slot of the balance or allowance.
+func lastRead(res *evmstate.Result, token common.Address) common.Hash {
+	for i := len(res.Reads) - 1; i >= 0; i-- {
+		if res.Reads[i].Address == token {
+			return res.Reads[i].Slot
+		}
+	}
+	return common.Hash{}
+}
@@ -0,0 +1,128 @@
+package evmquoter
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/evmstate"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/testutil"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+var (
+	testPool   = common.HexToAddress("0x00000000000000000000000000000000000000a0")
+	testTokens = []common.Address{
+		common.HexToAddress("0x00000000000000000000000000000000000000b0"),
+		common.HexToAddress("0x00000000000000000000000000000000000000b1"),
+	}
+
+	// testTokenCode is an ERC20 keeping the balance of an account in the slot of its address, and its allowance, to
+	// any spender, in the slot of its address plus 1<<160.
+	testTokenCode = common.FromHex("0x60003560e01c806370a0823114610037578063dd62ed3e14610044578063a9059cbb146100685780" +
+		"6323b872dd1461007a5760006000fd5b6004355460005260206000f35b60043574010000000000000000000000000000000000000000" +
+		"175460005260206000f35b602435600435336100cf9291906100b8565b600435740100000000000000000000000000000000000000001780" +
+		"546044358181116100da57900390556044356024356004356100cf9291906100b8565b80548381106100da578390039055805482019055" +
+		"50565b600160005260206000f35b60006000fd")
+
+	// testPoolCode quotes amountIn * sload(0) / 1000 from the lower token address to the higher one, and
+	// amountIn * 1000 / sload(0) the other way, both less a 0.3% fee:
+	//
+	//	function quote(address tokenIn, address tokenOut, uint256 amountIn) returns (uint256)
+	//	function swap(address tokenIn, address tokenOut, uint256 amountIn, uint256, address to) returns (uint256)
+	//
+	// swap transfers amountIn from the caller and the amount out to `to`, then decrements slot 0.
+	testPoolCode = common.FromHex("0x60003560e01c8063b646638414610021578063d5bcb9b5146100325760006000fd5b6100296100" +
+		"d4565b60005260206000f35b7f23b872dd00000000000000000000000000000000000000000000000000000000608052336084523060a4" +
+		"5260443560c452602060006064608060006004355af115610100576100806100d4565b7fa9059cbb000000000000000000000000000000" +
+		"000000000000000000000000006080526084356084528060a452602060006044608060006024355af11561010057600160005403600055" +
+		"60005260206000f35b602435600435106100ed576000546103e5604435020490565b620f42406103e560005402604435020490565b6000" +
+		"6000fd")
+)
+
+func newTestConfig() *Config {
+	return &Config{
+		DexID:   "evm-quoter-test",
+		ChainID: valueobject.ChainIDEthereum,
+		Pools: []PoolConfig{{
+			Address: testPool.Hex(),
+			Tokens:  []string{testTokens[0].Hex(), testTokens[1].Hex()},
+			Quote: Method{
+				Signature: "quote(address,address,uint256)",
+				Args:      []string{ArgTokenIn, ArgTokenOut, ArgAmountIn},
+			},
+			Swap: &Method{
+				Signature: "swap(address,address,uint256,uint256,address)",
+				Args:      []string{ArgTokenIn, ArgTokenOut, ArgAmountIn, ArgAmountOut, ArgSwapper},
+			},
+			Gas: 100000,
+		}},
+	}
+}
+
+// newTestChainState returns the chain the test pool lives on, with a rate of 2 and reserves of 1e6 and 2e6.
+func newTestChainState() *evmstate.State {
+	return &evmstate.State{
+		BlockNumber: 100,
+		Time:        1700000000,
+		Accounts: map[common.Address]*evmstate.Account{
+			testPool: {Nonce: 1, Code: testPoolCode, Storage: map[common.Hash]common.Hash{
+				{}: common.BigToHash(big.NewInt(2000)),
+			}},
+			testTokens[0]: {Nonce: 1, Code: testTokenCode, Storage: map[common.Hash]common.Hash{
+				common.BytesToHash(testPool[:]): common.BigToHash(big.NewInt(1e6)),
+			}},
+			testTokens[1]: {Nonce: 1, Code: testTokenCode, Storage: map[common.Hash]common.Hash{
+				common.BytesToHash(testPool[:]): common.BigToHash(big.NewInt(2e6)),
+			}},
+		},
+	}
+}
+
+// newTestPool lists and tracks the test pool.
+func newTestPool(t *testing.T) (entity.Pool, *testutil.EVMStateNode) {
+	t.Helper()
+	cfg := newTestConfig()
+	pools, _, err := NewPoolsListUpdater(cfg, nil).GetNewPools(context.Background(), nil)
+	require.NoError(t, err)
+	require.Len(t, pools, 1)
+
+	node, client := testutil.NewEVMStateNode(t, newTestChainState())
+	tracker, err := NewPoolTracker(cfg, client)
+	require.NoError(t, err)
+	p, err := tracker.GetNewPoolState(context.Background(), pools[0], pool.GetNewPoolStateParams{})
+	require.NoError(t, err)
+	return p, node
+}
+
+func TestPoolTracker_GetNewPoolState(t *testing.T) {
+	t.Parallel()
+	p, node := newTestPool(t)
+	assert.Equal(t, entity.PoolReserves{"1000000", "2000000"}, p.Reserves)
+	assert.Equal(t, uint64(100), p.BlockNumber)
+	assert.Equal(t, 1, node.Calls["eth_getBlockByNumber"])
+
+	var extra Extra
+	require.NoError(t, json.Unmarshal([]byte(p.Extra), &extra))
+	balanceSlot := common.BytesToHash(defaultSwapper[:])
+	allowanceSlot := common.BigToHash(new(big.Int).Add(defaultSwapper.Big(), new(big.Int).Lsh(big.NewInt(1), 160)))
+	assert.Equal(t, []common.Hash{balanceSlot, balanceSlot}, extra.BalanceSlots)
+	assert.Equal(t, []common.Hash{allowanceSlot, allowanceSlot}, extra.AllowanceSlots)
+
+	// only the state the calls read is collected
+	assert.Len(t, extra.State.Accounts, 3)
+	for _, token := range testTokens {
+		assert.Equal(t, map[common.Hash]common.Hash{
+			common.BytesToHash(testPool[:]): node.State.Accounts[token].Storage[common.BytesToHash(testPool[:])],
+			balanceSlot:                     {},
+			allowanceSlot:                   {},
+		}, extra.State.Accounts[token].Storage)
+	}
+}
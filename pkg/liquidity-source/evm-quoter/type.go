@@ -0,0 +1,32 @@
+package evmquoter
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/evmstate"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+// StaticExtra is the call configuration of a pool, with every contract address resolved.
+type StaticExtra struct {
+	ChainID       valueobject.ChainID `json:"chainId"`
+	Quote         Method              `json:"quote"`
+	Swap          *Method             `json:"swap,omitempty"`
+	ReserveHolder common.Address      `json:"reserveHolder"`
+	Swapper       common.Address      `json:"swapper"`
+	Gas           int64               `json:"gas,omitempty"`
+}
+
+// Extra is the state the calls of the pool read.
+type Extra struct {
+	State *evmstate.State `json:"state"`
+	// BalanceSlots and AllowanceSlots are, per token, the storage slots of the balance of the swapper and of its
+	// allowance to the swap target, for the simulator to fund swaps by overriding them. Only tracked with a swap; the
+	// zero hash if not found, as mapping slots are hashes.
+	BalanceSlots   []common.Hash `json:"balanceSlots,omitempty"`
+	AllowanceSlots []common.Hash `json:"allowanceSlots,omitempty"`
+}
+
+type MetaInfo struct {
+	BlockNumber uint64 `json:"blockNumber"`
+}
@@ -1,6 +1,9 @@
 package dexv2
 
-import "github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+import (
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/reorg"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
 
 type Config struct {
 	DexID       string
@@ -9,4 +12,5 @@ type Config struct {
 	Resolver    string              `json:"resolver"`
 	Liquidity   string              `json:"liquidity"`
 	SubgraphAPI string              `json:"subgraphAPI"`
+	Reorg       reorg.Config        `json:"reorg"`
 }
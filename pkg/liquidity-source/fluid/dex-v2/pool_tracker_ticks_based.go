@@ -107,6 +107,14 @@ func (t *PoolTracker) GetNewPoolState(
 	ctx context.Context,
 	p entity.Pool,
 	param poolpkg.GetNewPoolStateParams,
+) (entity.Pool, error) {
+	return t.reorgGuard.GetNewPoolState(ctx, p, param, t.applyLogs, t.BootstrapPoolState)
+}
+
+func (t *PoolTracker) applyLogs(
+	ctx context.Context,
+	p entity.Pool,
+	param poolpkg.GetNewPoolStateParams,
 ) (entity.Pool, error) {
 	if len(param.Logs) == 0 {
 		return p, nil
@@ -0,0 +1,19 @@
+package gmxv2
+
+import (
+	"github.com/KyberNetwork/blockchain-toolkit/time/durationjson"
+)
+
+type Config struct {
+	DexID      string     `json:"dexID"`
+	DataStore  string     `json:"dataStore"`
+	Reader     string     `json:"reader"`
+	HTTPConfig HTTPConfig `json:"httpConfig"`
+}
+
+// HTTPConfig configures the GMX API serving the oracle prices of the tokens, e.g. https://arbitrum-api.gmxinfra.io.
+type HTTPConfig struct {
+	BaseURL    string                `json:"baseURL"`
+	Timeout    durationjson.Duration `json:"timeout"`
+	RetryCount int                   `json:"retryCount"`
+}
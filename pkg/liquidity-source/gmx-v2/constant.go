@@ -0,0 +1,37 @@
+package gmxv2
+
+import (
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+const (
+	DexType = "gmx-v2"
+
+	dataStoreMethodGetBool    = "getBool"
+	dataStoreMethodGetBytes32 = "getBytes32"
+	dataStoreMethodGetUint    = "getUint"
+
+	readerMethodGetMarkets = "getMarkets"
+
+	tickersEndpoint = "/prices/tickers"
+
+	// marketsPageSize is the number of markets read by each getMarkets call.
+	marketsPageSize = 100
+	// marketsPerRequest is the number of markets whose DataStore values are read by each multicall.
+	marketsPerRequest = 20
+
+	defaultGas = 300000
+)
+
+var (
+	// floatPrecision is the precision of GMX factors and USD values.
+	floatPrecision = bignumber.TenPowInt(30)
+	// weiPrecision is the precision of PRBMath UD60x18 numbers.
+	weiPrecision = bignumber.TenPowInt(18)
+	// floatToWeiDivisor converts floatPrecision numbers to weiPrecision ones.
+	floatToWeiDivisor = bignumber.TenPowInt(12)
+
+	halfWeiPrecision = new(big.Int).Rsh(weiPrecision, 1)
+)
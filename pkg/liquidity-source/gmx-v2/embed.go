@@ -0,0 +1,9 @@
+package gmxv2
+
+import _ "embed"
+
+//go:embed abis/DataStore.json
+var dataStoreABIJson []byte
+
+//go:embed abis/Reader.json
+var readerABIJson []byte
@@ -0,0 +1,19 @@
+package gmxv2
+
+import "errors"
+
+var (
+	ErrInvalidAmountIn                = errors.New("gmx-v2: invalid amount in")
+	ErrInvalidTokenIn                 = errors.New("gmx-v2: invalid token in")
+	ErrNoSwapPath                     = errors.New("gmx-v2: no swap path")
+	ErrMarketDisabled                 = errors.New("gmx-v2: market disabled")
+	ErrMissingPrice                   = errors.New("gmx-v2: missing oracle price")
+	ErrUsdDeltaExceedsPoolValue       = errors.New("gmx-v2: usd delta exceeds pool value")
+	ErrSwapPriceImpactExceedsAmountIn = errors.New("gmx-v2: swap price impact exceeds amount in")
+	ErrInsufficientPoolAmount         = errors.New("gmx-v2: insufficient pool amount")
+	ErrMaxPoolAmountExceeded          = errors.New("gmx-v2: max pool amount exceeded")
+	ErrInsufficientReserve            = errors.New("gmx-v2: insufficient reserve")
+	ErrInvalidAmountOut               = errors.New("gmx-v2: invalid amount out")
+	ErrLogInputTooSmall               = errors.New("gmx-v2: log input too small")
+	ErrExp2InputTooBig                = errors.New("gmx-v2: exp2 input too big")
+)
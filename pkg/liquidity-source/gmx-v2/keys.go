@@ -0,0 +1,111 @@
+package gmxv2
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DataStore keys, as defined in https://github.com/gmx-io/gmx-synthetics/blob/main/contracts/data/Keys.sol
+var (
+	keySwapFeeReceiverFactor    = baseKey("SWAP_FEE_RECEIVER_FACTOR")
+	keyIsMarketDisabled         = baseKey("IS_MARKET_DISABLED")
+	keyPoolAmount               = baseKey("POOL_AMOUNT")
+	keyMaxPoolAmount            = baseKey("MAX_POOL_AMOUNT")
+	keySwapImpactPoolAmount     = baseKey("SWAP_IMPACT_POOL_AMOUNT")
+	keySwapImpactFactor         = baseKey("SWAP_IMPACT_FACTOR")
+	keySwapImpactExponentFactor = baseKey("SWAP_IMPACT_EXPONENT_FACTOR")
+	keySwapFeeFactor            = baseKey("SWAP_FEE_FACTOR")
+	keyPositionFeeFactor        = baseKey("POSITION_FEE_FACTOR")
+	keyReserveFactor            = baseKey("RESERVE_FACTOR")
+	keyOpenInterest             = baseKey("OPEN_INTEREST")
+	keyOpenInterestInTokens     = baseKey("OPEN_INTEREST_IN_TOKENS")
+	keyVirtualMarketID          = baseKey("VIRTUAL_MARKET_ID")
+	keyVirtualInventoryForSwaps = baseKey("VIRTUAL_INVENTORY_FOR_SWAPS")
+)
+
+// baseKey returns keccak256(abi.encode(name)).
+func baseKey(name string) common.Hash {
+	paddedLen := (len(name) + 31) / 32 * 32
+	data := make([]byte, 64+paddedLen)
+	data[31] = 0x20
+	binary.BigEndian.PutUint64(data[56:64], uint64(len(name)))
+	copy(data[64:], name)
+	return crypto.Keccak256Hash(data)
+}
+
+// derivedKey returns keccak256(abi.encode(base, args...)) for address, bool and bytes32 args.
+func derivedKey(base common.Hash, args ...any) common.Hash {
+	data := make([]byte, 0, 32*(len(args)+1))
+	data = append(data, base.Bytes()...)
+	for _, arg := range args {
+		var word common.Hash
+		switch v := arg.(type) {
+		case common.Address:
+			word = common.BytesToHash(v.Bytes())
+		case bool:
+			if v {
+				word[31] = 1
+			}
+		case common.Hash:
+			word = v
+		default:
+			panic("gmx-v2: unsupported key argument")
+		}
+		data = append(data, word.Bytes()...)
+	}
+	return crypto.Keccak256Hash(data)
+}
+
+func isMarketDisabledKey(market common.Address) common.Hash {
+	return derivedKey(keyIsMarketDisabled, market)
+}
+
+func poolAmountKey(market, token common.Address) common.Hash {
+	return derivedKey(keyPoolAmount, market, token)
+}
+
+func maxPoolAmountKey(market, token common.Address) common.Hash {
+	return derivedKey(keyMaxPoolAmount, market, token)
+}
+
+func swapImpactPoolAmountKey(market, token common.Address) common.Hash {
+	return derivedKey(keySwapImpactPoolAmount, market, token)
+}
+
+func swapImpactFactorKey(market common.Address, isPositive bool) common.Hash {
+	return derivedKey(keySwapImpactFactor, market, isPositive)
+}
+
+func swapImpactExponentFactorKey(market common.Address) common.Hash {
+	return derivedKey(keySwapImpactExponentFactor, market)
+}
+
+func swapFeeFactorKey(market common.Address, forPositiveImpact bool) common.Hash {
+	return derivedKey(keySwapFeeFactor, market, forPositiveImpact)
+}
+
+func positionFeeFactorKey(market common.Address, forPositiveImpact bool) common.Hash {
+	return derivedKey(keyPositionFeeFactor, market, forPositiveImpact)
+}
+
+func reserveFactorKey(market common.Address, isLong bool) common.Hash {
+	return derivedKey(keyReserveFactor, market, isLong)
+}
+
+func openInterestKey(market, collateralToken common.Address, isLong bool) common.Hash {
+	return derivedKey(keyOpenInterest, market, collateralToken, isLong)
+}
+
+func openInterestInTokensKey(market, collateralToken common.Address, isLong bool) common.Hash {
+	return derivedKey(keyOpenInterestInTokens, market, collateralToken, isLong)
+}
+
+func virtualMarketIDKey(market common.Address) common.Hash {
+	return derivedKey(keyVirtualMarketID, market)
+}
+
+func virtualInventoryForSwapsKey(virtualMarketID common.Hash, isLongToken bool) common.Hash {
+	return derivedKey(keyVirtualInventoryForSwaps, virtualMarketID, isLongToken)
+}
@@ -0,0 +1,47 @@
+package gmxv2
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func abiEncodeKey(t *testing.T, types []string, values ...any) common.Hash {
+	args := make(abi.Arguments, len(types))
+	for i, typ := range types {
+		abiType, err := abi.NewType(typ, "", nil)
+		require.NoError(t, err)
+		args[i] = abi.Argument{Type: abiType}
+	}
+	data, err := args.Pack(values...)
+	require.NoError(t, err)
+	return crypto.Keccak256Hash(data)
+}
+
+func TestKeys(t *testing.T) {
+	t.Parallel()
+
+	market := common.HexToAddress("0x70d95587d40A2caf56bd97485aB3Eec10Bee6336")
+	token := common.HexToAddress("0x82aF49447D8a07e3bd95BD0d56f35241523fBab1")
+	virtualMarketID := common.HexToHash("0x4fd8ed60ba3b0b7b1e2b1ec0c2ac5b4f5e5bb1c5d2d78c0d64a0bd6ee9b9fa1c")
+
+	for _, name := range []string{"POOL_AMOUNT", "SWAP_FEE_RECEIVER_FACTOR", "VIRTUAL_INVENTORY_FOR_SWAPS",
+		"A_NAME_LONGER_THAN_THIRTY_TWO_BYTES_IN_TOTAL"} {
+		assert.Equal(t, abiEncodeKey(t, []string{"string"}, name), baseKey(name), name)
+	}
+
+	assert.Equal(t, abiEncodeKey(t, []string{"bytes32", "address", "address"}, keyPoolAmount, market, token),
+		poolAmountKey(market, token))
+	assert.Equal(t, abiEncodeKey(t, []string{"bytes32", "address", "bool"}, keySwapImpactFactor, market, true),
+		swapImpactFactorKey(market, true))
+	assert.Equal(t, abiEncodeKey(t, []string{"bytes32", "address", "bool"}, keySwapFeeFactor, market, false),
+		swapFeeFactorKey(market, false))
+	assert.Equal(t, abiEncodeKey(t, []string{"bytes32", "address", "address", "bool"}, keyOpenInterest, market,
+		token, true), openInterestKey(market, token, true))
+	assert.Equal(t, abiEncodeKey(t, []string{"bytes32", "bytes32", "bool"}, keyVirtualInventoryForSwaps,
+		virtualMarketID, false), virtualInventoryForSwapsKey(virtualMarketID, false))
+}
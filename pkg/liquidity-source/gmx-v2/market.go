@@ -0,0 +1,342 @@
+package gmxv2
+
+import (
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// swapResult is the outcome of a swap through a market, with the state changes it makes.
+type swapResult struct {
+	marketIndex int
+	tokenIn     string
+	tokenOut    string
+	amountOut   *big.Int
+	feeAmount   *big.Int
+
+	// poolAmountIn is added to the pool amount of tokenIn, poolAmountOut removed from that of tokenOut.
+	poolAmountIn  *big.Int
+	poolAmountOut *big.Int
+	// swapImpactPoolDeltaIn and swapImpactPoolDeltaOut are applied to the swap impact pools of tokenIn and tokenOut.
+	swapImpactPoolDeltaIn  *big.Int
+	swapImpactPoolDeltaOut *big.Int
+}
+
+func (m *Market) side(isLong bool) *MarketSide {
+	if isLong {
+		return &m.Long
+	}
+	return &m.Short
+}
+
+// hasToken returns whether token is the long or short token of the market and which one.
+func (m *Market) hasToken(token string) (isLong bool, ok bool) {
+	switch token {
+	case m.LongToken:
+		return true, true
+	case m.ShortToken:
+		return false, true
+	}
+	return false, false
+}
+
+func (m *Market) oppositeToken(isLong bool) string {
+	if isLong {
+		return m.ShortToken
+	}
+	return m.LongToken
+}
+
+// swap simulates SwapUtils._swap of amountIn of tokenIn through the market, with the virtual inventory vi of the
+// market if it has one.
+// https://github.com/gmx-io/gmx-synthetics/blob/main/contracts/swap/SwapUtils.sol
+func (m *Market) swap(tokenIn string, amountIn *big.Int, prices map[string]*Price, vi *VirtualInventory,
+	swapFeeReceiverFactor *big.Int) (*swapResult, error) {
+	if m.IsDisabled {
+		return nil, ErrMarketDisabled
+	}
+
+	isLongIn, ok := m.hasToken(tokenIn)
+	if !ok {
+		return nil, ErrInvalidTokenIn
+	}
+	tokenOut := m.oppositeToken(isLongIn)
+	sideIn, sideOut := m.side(isLongIn), m.side(!isLongIn)
+
+	priceIn, priceOut := prices[tokenIn], prices[tokenOut]
+	if priceIn == nil || priceOut == nil {
+		return nil, ErrMissingPrice
+	}
+
+	usdDelta := new(big.Int).Mul(amountIn, priceIn.mid())
+	priceImpactUsd, err := m.priceImpactUsd(isLongIn, priceIn.mid(), priceOut.mid(), usdDelta, vi)
+	if err != nil {
+		return nil, err
+	}
+	isPositiveImpact := priceImpactUsd.Sign() > 0
+
+	// SwapPricingUtils.getSwapFees
+	feeFactor := m.SwapFeeFactorForNegativeImpact
+	if isPositiveImpact {
+		feeFactor = m.SwapFeeFactorForPositiveImpact
+	}
+	feeAmount := applyFactor(amountIn, feeFactor)
+	feeAmountForPool := new(big.Int).Sub(feeAmount, applyFactor(feeAmount, swapFeeReceiverFactor))
+	amountAfterFees := new(big.Int).Sub(amountIn, feeAmount)
+
+	result := &swapResult{
+		tokenIn:                tokenIn,
+		tokenOut:               tokenOut,
+		feeAmount:              feeAmount,
+		swapImpactPoolDeltaIn:  new(big.Int),
+		swapImpactPoolDeltaOut: new(big.Int),
+	}
+
+	var amountOut *big.Int
+	if isPositiveImpact {
+		impactAmountOut, cappedDiffUsd := swapImpactAmountWithCap(priceImpactUsd, priceOut, sideOut)
+		result.swapImpactPoolDeltaOut.Neg(impactAmountOut)
+
+		// the positive impact not covered by the swap impact pool of tokenOut is paid by the one of tokenIn
+		if cappedDiffUsd.Sign() != 0 {
+			impactAmountIn, _ := swapImpactAmountWithCap(cappedDiffUsd, priceIn, sideIn)
+			result.swapImpactPoolDeltaIn.Neg(impactAmountIn)
+			amountAfterFees.Add(amountAfterFees, impactAmountIn)
+		}
+
+		amountOut = new(big.Int).Mul(amountAfterFees, priceIn.Min)
+		amountOut.Quo(amountOut, priceOut.Max)
+		result.poolAmountOut = new(big.Int).Set(amountOut)
+		amountOut.Add(amountOut, impactAmountOut)
+	} else {
+		impactAmountIn, _ := swapImpactAmountWithCap(priceImpactUsd, priceIn, sideIn)
+		result.swapImpactPoolDeltaIn.Neg(impactAmountIn)
+		if amountAfterFees.Cmp(result.swapImpactPoolDeltaIn) <= 0 {
+			return nil, ErrSwapPriceImpactExceedsAmountIn
+		}
+		amountAfterFees.Add(amountAfterFees, impactAmountIn)
+
+		amountOut = new(big.Int).Mul(amountAfterFees, priceIn.Min)
+		amountOut.Quo(amountOut, priceOut.Max)
+		result.poolAmountOut = new(big.Int).Set(amountOut)
+	}
+
+	if amountOut.Sign() <= 0 {
+		return nil, ErrInvalidAmountOut
+	}
+	result.amountOut = amountOut
+	result.poolAmountIn = amountAfterFees.Add(amountAfterFees, feeAmountForPool)
+
+	if err = m.validateSwap(isLongIn, result, prices); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// validateSwap checks the pool amounts and the reserve of the market after the swap.
+func (m *Market) validateSwap(isLongIn bool, result *swapResult, prices map[string]*Price) error {
+	sideIn, sideOut := m.side(isLongIn), m.side(!isLongIn)
+
+	if result.poolAmountOut.Cmp(sideOut.PoolAmount) > 0 {
+		return ErrInsufficientPoolAmount
+	}
+
+	// MarketUtils.validatePoolAmount
+	if new(big.Int).Add(sideIn.PoolAmount, result.poolAmountIn).Cmp(sideIn.MaxPoolAmount) > 0 {
+		return ErrMaxPoolAmountExceeded
+	}
+
+	// MarketUtils.validateReserve of the side of tokenOut
+	isLongOut := !isLongIn
+	poolUsd := new(big.Int).Sub(sideOut.PoolAmount, result.poolAmountOut)
+	poolUsd.Mul(poolUsd, prices[result.tokenOut].Min)
+	maxReservedUsd := applyFactor(poolUsd, sideOut.ReserveFactor)
+
+	// MarketUtils.getReservedUsd: longs reserve their open interest in tokens at the index token price
+	var reservedUsd *big.Int
+	switch {
+	case !isLongOut:
+		reservedUsd = sideOut.OpenInterest
+	case sideOut.OpenInterestInTokens.Sign() == 0:
+		reservedUsd = bignumber.ZeroBI
+	default:
+		indexTokenPrice := prices[m.IndexToken]
+		if indexTokenPrice == nil {
+			return ErrMissingPrice
+		}
+		reservedUsd = new(big.Int).Mul(sideOut.OpenInterestInTokens, indexTokenPrice.Max)
+	}
+	if reservedUsd.Cmp(maxReservedUsd) > 0 {
+		return ErrInsufficientReserve
+	}
+
+	return nil
+}
+
+// apply applies the state changes of a swap through the market, and returns the next virtual inventory of the market.
+func (m *Market) apply(result *swapResult, vi *VirtualInventory) *VirtualInventory {
+	isLongIn, _ := m.hasToken(result.tokenIn)
+	sideIn, sideOut := m.side(isLongIn), m.side(!isLongIn)
+
+	sideIn.PoolAmount = new(big.Int).Add(sideIn.PoolAmount, result.poolAmountIn)
+	sideOut.PoolAmount = new(big.Int).Sub(sideOut.PoolAmount, result.poolAmountOut)
+	sideIn.SwapImpactPoolAmount = applyBoundedDelta(sideIn.SwapImpactPoolAmount, result.swapImpactPoolDeltaIn)
+	sideOut.SwapImpactPoolAmount = applyBoundedDelta(sideOut.SwapImpactPoolAmount, result.swapImpactPoolDeltaOut)
+
+	return m.nextVirtualInventory(vi, result)
+}
+
+// nextVirtualInventory returns the virtual inventory for swaps after a swap through the market, as applied by
+// MarketUtils.applyDeltaToVirtualInventoryForSwaps along with the pool amounts.
+func (m *Market) nextVirtualInventory(vi *VirtualInventory, result *swapResult) *VirtualInventory {
+	if vi == nil {
+		return nil
+	}
+
+	isLongIn, _ := m.hasToken(result.tokenIn)
+	negPoolAmountOut := new(big.Int).Neg(result.poolAmountOut)
+	if isLongIn {
+		return &VirtualInventory{
+			Long:  applyBoundedDelta(vi.Long, result.poolAmountIn),
+			Short: applyBoundedDelta(vi.Short, negPoolAmountOut),
+		}
+	}
+	return &VirtualInventory{
+		Long:  applyBoundedDelta(vi.Long, negPoolAmountOut),
+		Short: applyBoundedDelta(vi.Short, result.poolAmountIn),
+	}
+}
+
+// priceImpactUsd returns the price impact of a swap of usdDelta from the token of isLongIn to the opposite token, as
+// SwapPricingUtils.getPriceImpactUsd: the worst of the impacts on the pool amounts and on the virtual inventory.
+func (m *Market) priceImpactUsd(isLongIn bool, priceIn, priceOut, usdDelta *big.Int,
+	vi *VirtualInventory) (*big.Int, error) {
+	poolAmountIn, poolAmountOut := m.side(isLongIn).PoolAmount, m.side(!isLongIn).PoolAmount
+	priceImpactUsd, err := m.priceImpactUsdForPoolAmounts(poolAmountIn, poolAmountOut, priceIn, priceOut, usdDelta)
+	if err != nil || vi == nil {
+		return priceImpactUsd, err
+	}
+
+	virtualPoolAmountIn, virtualPoolAmountOut := vi.Short, vi.Long
+	if isLongIn {
+		virtualPoolAmountIn, virtualPoolAmountOut = vi.Long, vi.Short
+	}
+	priceImpactUsdForVirtualInventory, err := m.priceImpactUsdForPoolAmounts(virtualPoolAmountIn,
+		virtualPoolAmountOut, priceIn, priceOut, usdDelta)
+	if err != nil {
+		return nil, err
+	}
+
+	if priceImpactUsdForVirtualInventory.Cmp(priceImpactUsd) < 0 {
+		return priceImpactUsdForVirtualInventory, nil
+	}
+	return priceImpactUsd, nil
+}
+
+// priceImpactUsdForPoolAmounts returns the price impact of moving usdDelta from the pool of tokenOut to the one of
+// tokenIn, as SwapPricingUtils._getPriceImpactUsd.
+func (m *Market) priceImpactUsdForPoolAmounts(poolAmountIn, poolAmountOut, priceIn, priceOut,
+	usdDelta *big.Int) (*big.Int, error) {
+	poolUsdIn := new(big.Int).Mul(poolAmountIn, priceIn)
+	poolUsdOut := new(big.Int).Mul(poolAmountOut, priceOut)
+	if usdDelta.Cmp(poolUsdOut) > 0 {
+		return nil, ErrUsdDeltaExceedsPoolValue
+	}
+
+	nextPoolUsdIn := new(big.Int).Add(poolUsdIn, usdDelta)
+	nextPoolUsdOut := new(big.Int).Sub(poolUsdOut, usdDelta)
+
+	initialDiffUsd := diff(poolUsdIn, poolUsdOut)
+	nextDiffUsd := diff(nextPoolUsdIn, nextPoolUsdOut)
+
+	positiveImpactFactor, negativeImpactFactor := m.adjustedSwapImpactFactors()
+
+	// PricingUtils.getPriceImpactUsdForSameSideRebalance
+	isSameSideRebalance := (poolUsdIn.Cmp(poolUsdOut) <= 0) == (nextPoolUsdIn.Cmp(nextPoolUsdOut) <= 0)
+	if isSameSideRebalance {
+		hasPositiveImpact := nextDiffUsd.Cmp(initialDiffUsd) < 0
+		impactFactor := negativeImpactFactor
+		if hasPositiveImpact {
+			impactFactor = positiveImpactFactor
+		}
+
+		initialImpactUsd, err := m.applyImpactFactor(initialDiffUsd, impactFactor)
+		if err != nil {
+			return nil, err
+		}
+		nextImpactUsd, err := m.applyImpactFactor(nextDiffUsd, impactFactor)
+		if err != nil {
+			return nil, err
+		}
+
+		deltaDiffUsd := diff(initialImpactUsd, nextImpactUsd)
+		if !hasPositiveImpact {
+			deltaDiffUsd.Neg(deltaDiffUsd)
+		}
+		return deltaDiffUsd, nil
+	}
+
+	// PricingUtils.getPriceImpactUsdForCrossoverRebalance
+	positiveImpactUsd, err := m.applyImpactFactor(initialDiffUsd, positiveImpactFactor)
+	if err != nil {
+		return nil, err
+	}
+	negativeImpactUsd, err := m.applyImpactFactor(nextDiffUsd, negativeImpactFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).Sub(positiveImpactUsd, negativeImpactUsd), nil
+}
+
+// adjustedSwapImpactFactors caps the positive impact factor by the negative one, as
+// MarketUtils.getAdjustedSwapImpactFactors.
+func (m *Market) adjustedSwapImpactFactors() (positiveImpactFactor, negativeImpactFactor *big.Int) {
+	positiveImpactFactor, negativeImpactFactor = m.PositiveSwapImpactFactor, m.NegativeSwapImpactFactor
+	if positiveImpactFactor.Cmp(negativeImpactFactor) > 0 {
+		positiveImpactFactor = negativeImpactFactor
+	}
+	return positiveImpactFactor, negativeImpactFactor
+}
+
+// applyImpactFactor returns impactFactor * diffUsd ^ swapImpactExponentFactor, as PricingUtils.applyImpactFactor.
+func (m *Market) applyImpactFactor(diffUsd, impactFactor *big.Int) (*big.Int, error) {
+	exponentValue, err := applyExponentFactor(diffUsd, m.SwapImpactExponentFactor)
+	if err != nil {
+		return nil, err
+	}
+	return applyFactor(exponentValue, impactFactor), nil
+}
+
+// swapImpactAmountWithCap converts priceImpactUsd to an amount of the token of side, as
+// MarketUtils.getSwapImpactAmountWithCap: positive impacts are rounded down and capped by the swap impact pool, with
+// the USD over the cap returned as cappedDiffUsd; negative ones are rounded up.
+func swapImpactAmountWithCap(priceImpactUsd *big.Int, price *Price, side *MarketSide) (impactAmount,
+	cappedDiffUsd *big.Int) {
+	if priceImpactUsd.Sign() <= 0 {
+		return roundUpMagnitudeDivision(priceImpactUsd, price.Min), new(big.Int)
+	}
+
+	impactAmount = new(big.Int).Quo(priceImpactUsd, price.Max)
+	cappedDiffUsd = new(big.Int)
+	if impactAmount.Cmp(side.SwapImpactPoolAmount) > 0 {
+		cappedDiffUsd.Sub(impactAmount, side.SwapImpactPoolAmount).Mul(cappedDiffUsd, price.Max)
+		impactAmount.Set(side.SwapImpactPoolAmount)
+	}
+	return impactAmount, cappedDiffUsd
+}
+
+// applyBoundedDelta returns value + delta floored at zero, as DataStore.applyBoundedDeltaToUint.
+func applyBoundedDelta(value, delta *big.Int) *big.Int {
+	result := new(big.Int).Add(value, delta)
+	if result.Sign() < 0 {
+		return new(big.Int)
+	}
+	return result
+}
+
+func (p *Price) mid() *big.Int {
+	result := new(big.Int).Add(p.Min, p.Max)
+	return result.Rsh(result, 1)
+}
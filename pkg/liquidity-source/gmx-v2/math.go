@@ -0,0 +1,156 @@
+package gmxv2
+
+import (
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// exp2Factors are root(2, 2^-i) for i in [1, 64], in the 64.64-bit fixed-point format of PRBMath.exp2.
+var exp2Factors = func() []*big.Int {
+	hexes := []string{
+		"16A09E667F3BCC909", "1306FE0A31B7152DF", "1172B83C7D517ADCE", "10B5586CF9890F62A",
+		"1059B0D31585743AE", "102C9A3E778060EE7", "10163DA9FB33356D8", "100B1AFA5ABCBED61",
+		"10058C86DA1C09EA2", "1002C605E2E8CEC50", "100162F3904051FA1", "1000B175EFFDC76BA",
+		"100058BA01FB9F96D", "10002C5CC37DA9492", "1000162E525EE0547", "10000B17255775C04",
+		"1000058B91B5BC9AE", "100002C5C89D5EC6D", "10000162E43F4F831", "100000B1721BCFC9A",
+		"10000058B90CF1E6E", "1000002C5C863B73F", "100000162E430E5A2", "1000000B172183551",
+		"100000058B90C0B49", "10000002C5C8601CC", "1000000162E42FFF0", "10000000B17217FBB",
+		"1000000058B90BFCE", "100000002C5C85FE3", "10000000162E42FF1", "100000000B17217F8",
+		"10000000058B90BFC", "1000000002C5C85FE", "100000000162E42FF", "1000000000B17217F",
+		"100000000058B90C0", "10000000002C5C860", "1000000000162E430", "10000000000B17218",
+		"1000000000058B90C", "100000000002C5C86", "10000000000162E43", "100000000000B1721",
+		"10000000000058B91", "1000000000002C5C8", "100000000000162E4", "1000000000000B172",
+		"100000000000058B9", "10000000000002C5D", "1000000000000162E", "10000000000000B17",
+		"1000000000000058C", "100000000000002C6", "10000000000000163", "100000000000000B1",
+		"10000000000000059", "1000000000000002C", "10000000000000016", "1000000000000000B",
+		"10000000000000006", "10000000000000003", "10000000000000001", "10000000000000001",
+	}
+	factors := make([]*big.Int, len(hexes))
+	for i, h := range hexes {
+		factors[i], _ = new(big.Int).SetString(h, 16)
+	}
+	return factors
+}()
+
+var (
+	exp2Start    = new(big.Int).Lsh(bignumber.One, 191)
+	exp2MaxInput = new(big.Int).Mul(big.NewInt(192), weiPrecision)
+)
+
+// applyFactor returns value * factor / floatPrecision, as Precision.applyFactor.
+func applyFactor(value, factor *big.Int) *big.Int {
+	result := new(big.Int).Mul(value, factor)
+	return result.Quo(result, floatPrecision)
+}
+
+// applyExponentFactor returns floatValue ^ exponentFactor, as Precision.applyExponentFactor.
+func applyExponentFactor(floatValue, exponentFactor *big.Int) (*big.Int, error) {
+	if floatValue.Cmp(floatPrecision) < 0 {
+		return new(big.Int), nil
+	}
+
+	if exponentFactor.Cmp(floatPrecision) == 0 {
+		return floatValue, nil
+	}
+
+	weiValue, err := pow(
+		new(big.Int).Quo(floatValue, floatToWeiDivisor),
+		new(big.Int).Quo(exponentFactor, floatToWeiDivisor),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return weiValue.Mul(weiValue, floatToWeiDivisor), nil
+}
+
+// pow returns x ^ y of UD60x18 numbers, as PRBMathUD60x18.pow.
+func pow(x, y *big.Int) (*big.Int, error) {
+	if x.Sign() == 0 {
+		if y.Sign() == 0 {
+			return new(big.Int).Set(weiPrecision), nil
+		}
+		return new(big.Int), nil
+	}
+
+	logX, err := log2(x)
+	if err != nil {
+		return nil, err
+	}
+
+	return exp2(mulFixedPoint(logX, y))
+}
+
+// log2 returns the binary logarithm of an UD60x18 number, as PRBMathUD60x18.log2.
+func log2(x *big.Int) (*big.Int, error) {
+	if x.Cmp(weiPrecision) < 0 {
+		return nil, ErrLogInputTooSmall
+	}
+
+	n := new(big.Int).Quo(x, weiPrecision).BitLen() - 1
+	result := new(big.Int).Mul(big.NewInt(int64(n)), weiPrecision)
+
+	y := new(big.Int).Rsh(x, uint(n))
+	if y.Cmp(weiPrecision) == 0 {
+		return result, nil
+	}
+
+	doubleUnit := new(big.Int).Lsh(weiPrecision, 1)
+	for delta := new(big.Int).Set(halfWeiPrecision); delta.Sign() > 0; delta.Rsh(delta, 1) {
+		y.Mul(y, y).Quo(y, weiPrecision)
+		if y.Cmp(doubleUnit) >= 0 {
+			result.Add(result, delta)
+			y.Rsh(y, 1)
+		}
+	}
+
+	return result, nil
+}
+
+// exp2 returns the binary exponent of an UD60x18 number, as PRBMathUD60x18.exp2.
+func exp2(x *big.Int) (*big.Int, error) {
+	if x.Cmp(exp2MaxInput) >= 0 {
+		return nil, ErrExp2InputTooBig
+	}
+
+	x192x64 := new(big.Int).Lsh(x, 64)
+	x192x64.Quo(x192x64, weiPrecision)
+
+	result := new(big.Int).Set(exp2Start)
+	for i, factor := range exp2Factors {
+		if x192x64.Bit(63-i) == 1 {
+			result.Mul(result, factor).Rsh(result, 64)
+		}
+	}
+
+	result.Mul(result, weiPrecision)
+	return result.Rsh(result, uint(191-new(big.Int).Rsh(x192x64, 64).Uint64())), nil
+}
+
+// mulFixedPoint returns x * y of UD60x18 numbers rounded half up, as PRBMath.mulDivFixedPoint.
+func mulFixedPoint(x, y *big.Int) *big.Int {
+	result, remainder := new(big.Int).QuoRem(new(big.Int).Mul(x, y), weiPrecision, new(big.Int))
+	if remainder.Cmp(halfWeiPrecision) >= 0 {
+		result.Add(result, bignumber.One)
+	}
+	return result
+}
+
+// diff returns |a - b|.
+func diff(a, b *big.Int) *big.Int {
+	result := new(big.Int).Sub(a, b)
+	return result.Abs(result)
+}
+
+// roundUpMagnitudeDivision returns a / b with the magnitude of negative a rounded up, as
+// Calc.roundUpMagnitudeDivision.
+func roundUpMagnitudeDivision(a, b *big.Int) *big.Int {
+	if a.Sign() < 0 {
+		result := new(big.Int).Sub(a, b)
+		result.Add(result, bignumber.One)
+		return result.Quo(result, b)
+	}
+
+	return new(big.Int).Quo(a, b)
+}
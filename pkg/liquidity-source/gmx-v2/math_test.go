@@ -0,0 +1,81 @@
+package gmxv2
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+func ud60x18(f float64) *big.Int {
+	result, _ := new(big.Float).Mul(big.NewFloat(f), new(big.Float).SetInt(weiPrecision)).Int(nil)
+	return result
+}
+
+func assertRelativelyEqual(t *testing.T, expected, actual *big.Int, tolerance float64) {
+	t.Helper()
+	delta, _ := new(big.Float).Quo(new(big.Float).SetInt(diff(expected, actual)),
+		new(big.Float).SetInt(expected)).Float64()
+	assert.LessOrEqualf(t, delta, tolerance, "expected %s, got %s", expected, actual)
+}
+
+func TestPow(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		x, y, expected float64
+	}{
+		{2, 2, 4},
+		{4, 0.5, 2},
+		{10, 1.5, 31.622776601683793},
+		{1e6, 2, 1e12},
+		{123456.789, 1.75, 813113571.8883508},
+		{1, 3, 1},
+	}
+	for _, tc := range testCases {
+		result, err := pow(ud60x18(tc.x), ud60x18(tc.y))
+		require.NoError(t, err)
+		assertRelativelyEqual(t, ud60x18(tc.expected), result, 1e-9)
+	}
+
+	result, err := pow(new(big.Int), new(big.Int))
+	require.NoError(t, err)
+	assert.Equal(t, weiPrecision, result)
+
+	_, err = log2(ud60x18(0.5))
+	assert.ErrorIs(t, err, ErrLogInputTooSmall)
+	_, err = exp2(ud60x18(192))
+	assert.ErrorIs(t, err, ErrExp2InputTooBig)
+}
+
+func TestApplyExponentFactor(t *testing.T) {
+	t.Parallel()
+
+	usd := func(f float64) *big.Int {
+		return new(big.Int).Mul(ud60x18(f), floatToWeiDivisor)
+	}
+
+	result, err := applyExponentFactor(usd(0.5), usd(2))
+	require.NoError(t, err)
+	assert.Zero(t, result.Sign())
+
+	result, err = applyExponentFactor(usd(1000), floatPrecision)
+	require.NoError(t, err)
+	assert.Equal(t, usd(1000), result)
+
+	result, err = applyExponentFactor(usd(1000), usd(2))
+	require.NoError(t, err)
+	assertRelativelyEqual(t, usd(1e6), result, 1e-9)
+}
+
+func TestRoundUpMagnitudeDivision(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, big.NewInt(3), roundUpMagnitudeDivision(big.NewInt(7), big.NewInt(2)))
+	assert.Equal(t, big.NewInt(-4), roundUpMagnitudeDivision(big.NewInt(-7), big.NewInt(2)))
+	assert.Equal(t, big.NewInt(-3), roundUpMagnitudeDivision(big.NewInt(-6), big.NewInt(2)))
+	assert.Equal(t, bignumber.ZeroBI, roundUpMagnitudeDivision(bignumber.ZeroBI, big.NewInt(2)))
+}
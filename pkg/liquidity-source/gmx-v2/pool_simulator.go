@@ -0,0 +1,239 @@
+package gmxv2
+
+import (
+	"maps"
+	"math/big"
+	"slices"
+
+	"github.com/goccy/go-json"
+	"github.com/samber/lo"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// PoolSimulator simulates swaps through the GM markets of a GMX v2 deployment, along swap paths of one or two markets.
+type PoolSimulator struct {
+	pool.Pool
+
+	markets               []*Market
+	prices                map[string]*Price
+	virtualInventories    map[string]*VirtualInventory
+	swapFeeReceiverFactor *big.Int
+
+	// marketsByPair lists the indexes of the markets swapping a token in to a token out.
+	marketsByPair map[string]map[string][]int
+	// neighbors lists the tokens a token is swapped to through a single market.
+	neighbors map[string][]string
+	// reachable lists the tokens a token is swapped to through a swap path.
+	reachable map[string][]string
+}
+
+var _ = pool.RegisterFactory0(DexType, NewPoolSimulator)
+
+func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
+	var extra Extra
+	if err := json.Unmarshal([]byte(entityPool.Extra), &extra); err != nil {
+		return nil, err
+	}
+
+	tokens := lo.Map(entityPool.Tokens, func(token *entity.PoolToken, _ int) string { return token.Address })
+	p := &PoolSimulator{
+		Pool: pool.Pool{Info: pool.PoolInfo{
+			Address:  entityPool.Address,
+			Exchange: entityPool.Exchange,
+			Type:     entityPool.Type,
+			Tokens:   tokens,
+			Reserves: lo.Map(entityPool.Reserves, func(reserve string, _ int) *big.Int {
+				return bignumber.NewBig(reserve)
+			}),
+			BlockNumber: entityPool.BlockNumber,
+		}},
+		markets:               extra.Markets,
+		prices:                extra.Prices,
+		virtualInventories:    extra.VirtualInventories,
+		swapFeeReceiverFactor: extra.SwapFeeReceiverFactor,
+	}
+	p.indexMarkets()
+
+	return p, nil
+}
+
+// indexMarkets indexes the enabled markets between tokens of the pool with oracle prices.
+func (p *PoolSimulator) indexMarkets() {
+	p.marketsByPair = make(map[string]map[string][]int)
+	p.neighbors = make(map[string][]string)
+	p.reachable = make(map[string][]string)
+
+	isPoolToken := lo.SliceToMap(p.Info.Tokens, func(token string) (string, bool) { return token, true })
+	addPair := func(tokenIn, tokenOut string, marketIndex int) {
+		if p.marketsByPair[tokenIn] == nil {
+			p.marketsByPair[tokenIn] = make(map[string][]int)
+		}
+		if len(p.marketsByPair[tokenIn][tokenOut]) == 0 {
+			p.neighbors[tokenIn] = append(p.neighbors[tokenIn], tokenOut)
+		}
+		p.marketsByPair[tokenIn][tokenOut] = append(p.marketsByPair[tokenIn][tokenOut], marketIndex)
+	}
+
+	for i, m := range p.markets {
+		if m.IsDisabled || m.LongToken == m.ShortToken || !isPoolToken[m.LongToken] || !isPoolToken[m.ShortToken] ||
+			p.prices[m.LongToken] == nil || p.prices[m.ShortToken] == nil {
+			continue
+		}
+		addPair(m.LongToken, m.ShortToken, i)
+		addPair(m.ShortToken, m.LongToken, i)
+	}
+
+	for token, neighbors := range p.neighbors {
+		slices.Sort(neighbors)
+		reachable := map[string]struct{}{}
+		for _, neighbor := range neighbors {
+			reachable[neighbor] = struct{}{}
+			for _, next := range p.neighbors[neighbor] {
+				reachable[next] = struct{}{}
+			}
+		}
+		delete(reachable, token)
+		p.reachable[token] = slices.Sorted(maps.Keys(reachable))
+	}
+}
+
+func (p *PoolSimulator) CalcAmountOut(params pool.CalcAmountOutParams) (*pool.CalcAmountOutResult, error) {
+	tokenIn, tokenOut, amountIn := params.TokenAmountIn.Token, params.TokenOut, params.TokenAmountIn.Amount
+	if amountIn == nil || amountIn.Sign() <= 0 {
+		return nil, ErrInvalidAmountIn
+	}
+
+	hops, err := p.bestSwapPath(tokenIn, tokenOut, amountIn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pool.CalcAmountOutResult{
+		TokenAmountOut: &pool.TokenAmount{Token: tokenOut, Amount: hops[len(hops)-1].amountOut},
+		Fee:            &pool.TokenAmount{Token: tokenIn, Amount: hops[0].feeAmount},
+		Gas:            defaultGas * int64(len(hops)),
+		SwapInfo: SwapInfo{
+			SwapPath: lo.Map(hops, func(hop *swapResult, _ int) string { return p.markets[hop.marketIndex].MarketToken }),
+			hops:     hops,
+		},
+	}, nil
+}
+
+// bestSwapPath returns the swaps along the swap path with the most amount out: through the best of the markets of
+// tokenIn and tokenOut, or through the best market to each intermediate token and then the best market from it.
+func (p *PoolSimulator) bestSwapPath(tokenIn, tokenOut string, amountIn *big.Int) ([]*swapResult, error) {
+	var best []*swapResult
+	err := ErrNoSwapPath
+	consider := func(hops []*swapResult, hopErr error) {
+		if hopErr != nil {
+			err = hopErr
+			return
+		}
+		if best == nil || hops[len(hops)-1].amountOut.Cmp(best[len(best)-1].amountOut) > 0 {
+			best = hops
+		}
+	}
+
+	if hop, hopErr := p.bestSwap(tokenIn, tokenOut, amountIn, p.virtualInventories, -1); hop != nil || hopErr != nil {
+		consider([]*swapResult{hop}, hopErr)
+	}
+
+	for _, tokenMid := range p.neighbors[tokenIn] {
+		if tokenMid == tokenOut || len(p.marketsByPair[tokenMid][tokenOut]) == 0 {
+			continue
+		}
+
+		first, hopErr := p.bestSwap(tokenIn, tokenMid, amountIn, p.virtualInventories, -1)
+		if hopErr != nil {
+			consider(nil, hopErr)
+			continue
+		}
+
+		// the second swap sees the virtual inventory left by the first one
+		virtualInventories := p.virtualInventories
+		if m := p.markets[first.marketIndex]; m.VirtualMarketID != "" {
+			virtualInventories = maps.Clone(p.virtualInventories)
+			virtualInventories[m.VirtualMarketID] = m.nextVirtualInventory(p.virtualInventories[m.VirtualMarketID],
+				first)
+		}
+		second, hopErr := p.bestSwap(tokenMid, tokenOut, first.amountOut, virtualInventories, first.marketIndex)
+		if second != nil || hopErr != nil {
+			consider([]*swapResult{first, second}, hopErr)
+		}
+	}
+
+	if best == nil {
+		return nil, err
+	}
+	return best, nil
+}
+
+// bestSwap returns the swap with the most amount out through the markets of tokenIn and tokenOut other than
+// excludedMarketIndex, or nil if there is no such market.
+func (p *PoolSimulator) bestSwap(tokenIn, tokenOut string, amountIn *big.Int,
+	virtualInventories map[string]*VirtualInventory, excludedMarketIndex int) (*swapResult, error) {
+	var best *swapResult
+	var err error
+	for _, i := range p.marketsByPair[tokenIn][tokenOut] {
+		if i == excludedMarketIndex {
+			continue
+		}
+
+		m := p.markets[i]
+		result, swapErr := m.swap(tokenIn, amountIn, p.prices, virtualInventories[m.VirtualMarketID],
+			p.swapFeeReceiverFactor)
+		if swapErr != nil {
+			err = swapErr
+			continue
+		}
+		result.marketIndex = i
+
+		if best == nil || result.amountOut.Cmp(best.amountOut) > 0 {
+			best = result
+		}
+	}
+
+	if best == nil {
+		return nil, err
+	}
+	return best, nil
+}
+
+func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	swapInfo, ok := params.SwapInfo.(SwapInfo)
+	if !ok {
+		return
+	}
+
+	for _, hop := range swapInfo.hops {
+		m := p.markets[hop.marketIndex]
+		if next := m.apply(hop, p.virtualInventories[m.VirtualMarketID]); next != nil {
+			p.virtualInventories[m.VirtualMarketID] = next
+		}
+	}
+}
+
+func (p *PoolSimulator) CloneState() pool.IPoolSimulator {
+	cloned := *p
+	cloned.markets = lo.Map(p.markets, func(m *Market, _ int) *Market {
+		clonedMarket := *m
+		return &clonedMarket
+	})
+	cloned.virtualInventories = maps.Clone(p.virtualInventories)
+	return &cloned
+}
+
+func (p *PoolSimulator) CanSwapTo(address string) []string {
+	return p.reachable[address]
+}
+
+func (p *PoolSimulator) CanSwapFrom(address string) []string {
+	return p.reachable[address]
+}
+
+func (p *PoolSimulator) GetMetaInfo(_, _ string) any {
+	return pool.MetaInfo{BlockNumber: p.Info.BlockNumber}
+}
@@ -0,0 +1,311 @@
+package gmxv2
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+const (
+	weth = "0x82af49447d8a07e3bd95bd0d56f35241523fbab1"
+	usdc = "0xaf88d065e77c8cc2239327c5edb3a432268e5831"
+	wbtc = "0x2f2a2543b76a4166549f7aab2e75bef0aefc5b0f"
+
+	ethMarket = "0x70d95587d40a2caf56bd97485ab3eec10bee6336"
+	btcMarket = "0x47c031236e19d024b42f8ae6780e44a573170703"
+)
+
+func amount(f float64, decimals int) *big.Int {
+	result, _ := new(big.Float).Mul(big.NewFloat(f), new(big.Float).SetInt(bignumber.TenPowInt(decimals))).Int(nil)
+	return result
+}
+
+// usdPrice returns the price of one unit of a token of decimals at f USD.
+func usdPrice(f float64, decimals int) *Price {
+	price := amount(f, 30-decimals)
+	return &Price{Min: price, Max: new(big.Int).Set(price)}
+}
+
+func testMarket(marketToken, longToken string, longDecimals int, longAmount, shortAmount float64) *Market {
+	side := func(poolAmount *big.Int, impactPoolAmount *big.Int) MarketSide {
+		return MarketSide{
+			PoolAmount:           poolAmount,
+			MaxPoolAmount:        amount(1, 40),
+			SwapImpactPoolAmount: impactPoolAmount,
+			ReserveFactor:        floatPrecision,
+			OpenInterest:         new(big.Int),
+			OpenInterestInTokens: new(big.Int),
+		}
+	}
+
+	return &Market{
+		MarketToken:                        marketToken,
+		IndexToken:                         longToken,
+		LongToken:                          longToken,
+		ShortToken:                         usdc,
+		Long:                               side(amount(longAmount, longDecimals), amount(longAmount/100, longDecimals)),
+		Short:                              side(amount(shortAmount, 6), amount(shortAmount/100, 6)),
+		PositiveSwapImpactFactor:           amount(5, 21),
+		NegativeSwapImpactFactor:           amount(1, 22),
+		SwapImpactExponentFactor:           amount(2, 30),
+		SwapFeeFactorForPositiveImpact:     amount(5, 26),
+		SwapFeeFactorForNegativeImpact:     amount(7, 26),
+		PositionFeeFactorForPositiveImpact: amount(4, 26),
+		PositionFeeFactorForNegativeImpact: amount(6, 26),
+	}
+}
+
+func testExtra() *Extra {
+	return &Extra{
+		Markets: []*Market{
+			testMarket(ethMarket, weth, 18, 1000, 2_000_000),
+			testMarket(btcMarket, wbtc, 8, 100, 6_000_000),
+		},
+		Prices: map[string]*Price{
+			weth: usdPrice(2000, 18),
+			usdc: usdPrice(1, 6),
+			wbtc: usdPrice(60000, 8),
+		},
+		SwapFeeReceiverFactor: amount(37, 28),
+	}
+}
+
+func newTestPoolSimulator(t *testing.T, extra *Extra) *PoolSimulator {
+	t.Helper()
+	extraBytes, err := json.Marshal(extra)
+	require.NoError(t, err)
+
+	p, err := NewPoolSimulator(entity.Pool{
+		Address:  "0xfd70de6b91282d8017aa4e741e9ae325cab992d8",
+		Exchange: DexType,
+		Type:     DexType,
+		Reserves: entity.PoolReserves{"0", "0", "0"},
+		Tokens: []*entity.PoolToken{
+			{Address: weth, Swappable: true},
+			{Address: usdc, Swappable: true},
+			{Address: wbtc, Swappable: true},
+		},
+		Extra: string(extraBytes),
+	})
+	require.NoError(t, err)
+	return p
+}
+
+func calcAmountOut(p pool.IPoolSimulator, tokenIn string, amountIn *big.Int,
+	tokenOut string) (*pool.CalcAmountOutResult, error) {
+	return p.CalcAmountOut(pool.CalcAmountOutParams{
+		TokenAmountIn: pool.TokenAmount{Token: tokenIn, Amount: amountIn},
+		TokenOut:      tokenOut,
+	})
+}
+
+func TestPoolSimulator_CalcAmountOut(t *testing.T) {
+	t.Parallel()
+
+	t.Run("direct swap with negative impact", func(t *testing.T) {
+		t.Parallel()
+		p := newTestPoolSimulator(t, testExtra())
+
+		result, err := calcAmountOut(p, weth, amount(1, 18), usdc)
+		require.NoError(t, err)
+
+		// fee 0.0007 WETH, impact -(1e-8 * 4000^2) = -0.16 USD = -0.00008 WETH
+		assert.InDelta(t, 1998.44e6, float64(result.TokenAmountOut.Amount.Int64()), 10)
+		assert.Equal(t, amount(7, 14), result.Fee.Amount)
+		assert.Equal(t, weth, result.Fee.Token)
+		assert.Equal(t, int64(defaultGas), result.Gas)
+		assert.Equal(t, []string{ethMarket}, result.SwapInfo.(SwapInfo).SwapPath)
+	})
+
+	t.Run("two-market swap path", func(t *testing.T) {
+		t.Parallel()
+		p := newTestPoolSimulator(t, testExtra())
+
+		result, err := calcAmountOut(p, weth, amount(1, 18), wbtc)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{ethMarket, btcMarket}, result.SwapInfo.(SwapInfo).SwapPath)
+		assert.Equal(t, int64(2*defaultGas), result.Gas)
+		assert.Less(t, result.TokenAmountOut.Amount.Int64(), int64(2000*100_000_000/60000))
+		assert.Greater(t, result.TokenAmountOut.Amount.Int64(), int64(1996*100_000_000/60000))
+	})
+
+	t.Run("positive impact", func(t *testing.T) {
+		t.Parallel()
+		extra := testExtra()
+		extra.Markets[0].Short.PoolAmount = amount(1_000_000, 6)
+		p := newTestPoolSimulator(t, extra)
+
+		result, err := calcAmountOut(p, usdc, amount(1000, 6), weth)
+		require.NoError(t, err)
+
+		// 999.5 USDC after the 0.05% fee for positive impact, plus the impact paid by the swap impact pool
+		assert.Equal(t, amount(0.5, 6), result.Fee.Amount)
+		assert.Greater(t, result.TokenAmountOut.Amount.Cmp(amount(49975, 13)), 0)
+	})
+
+	t.Run("positive impact capped by empty swap impact pools", func(t *testing.T) {
+		t.Parallel()
+		extra := testExtra()
+		extra.Markets[0].Short.PoolAmount = amount(1_000_000, 6)
+		extra.Markets[0].Long.SwapImpactPoolAmount = new(big.Int)
+		extra.Markets[0].Short.SwapImpactPoolAmount = new(big.Int)
+		p := newTestPoolSimulator(t, extra)
+
+		result, err := calcAmountOut(p, usdc, amount(1000, 6), weth)
+		require.NoError(t, err)
+		assert.Equal(t, amount(49975, 13), result.TokenAmountOut.Amount)
+	})
+
+	t.Run("virtual inventory", func(t *testing.T) {
+		t.Parallel()
+		withoutVI, err := calcAmountOut(newTestPoolSimulator(t, testExtra()), weth, amount(10, 18), usdc)
+		require.NoError(t, err)
+
+		extra := testExtra()
+		extra.Markets[0].VirtualMarketID = "0x01"
+		extra.VirtualInventories = map[string]*VirtualInventory{
+			"0x01": {Long: amount(1000, 18), Short: amount(500_000, 6)},
+		}
+		withVI, err := calcAmountOut(newTestPoolSimulator(t, extra), weth, amount(10, 18), usdc)
+		require.NoError(t, err)
+
+		assert.Less(t, withVI.TokenAmountOut.Amount.Cmp(withoutVI.TokenAmountOut.Amount), 0)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			name     string
+			mutate   func(extra *Extra)
+			tokenIn  string
+			amountIn *big.Int
+			tokenOut string
+			err      error
+		}{
+			{
+				name:     "zero amount in",
+				amountIn: new(big.Int),
+				err:      ErrInvalidAmountIn,
+			},
+			{
+				name:    "unknown token",
+				tokenIn: "0x0000000000000000000000000000000000000001",
+				err:     ErrNoSwapPath,
+			},
+			{
+				name:   "disabled market",
+				mutate: func(extra *Extra) { extra.Markets[0].IsDisabled = true },
+				err:    ErrNoSwapPath,
+			},
+			{
+				name:   "missing price",
+				mutate: func(extra *Extra) { delete(extra.Prices, weth) },
+				err:    ErrNoSwapPath,
+			},
+			{
+				name:     "usd delta exceeds pool value",
+				amountIn: amount(2000, 18),
+				err:      ErrUsdDeltaExceedsPoolValue,
+			},
+			{
+				name:   "max pool amount exceeded",
+				mutate: func(extra *Extra) { extra.Markets[0].Long.MaxPoolAmount = amount(1000, 18) },
+				err:    ErrMaxPoolAmountExceeded,
+			},
+			{
+				name:   "insufficient reserve",
+				mutate: func(extra *Extra) { extra.Markets[0].Short.OpenInterest = amount(1_999_000, 30) },
+				err:    ErrInsufficientReserve,
+			},
+			{
+				name: "long reserve at the index token price",
+				mutate: func(extra *Extra) {
+					extra.Markets[0].Long.OpenInterestInTokens = amount(999.9, 18)
+				},
+				tokenIn:  usdc,
+				amountIn: amount(1000, 6),
+				tokenOut: weth,
+				err:      ErrInsufficientReserve,
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				extra := testExtra()
+				if tc.mutate != nil {
+					tc.mutate(extra)
+				}
+				tokenIn, amountIn, tokenOut := weth, amount(1, 18), usdc
+				if tc.tokenIn != "" {
+					tokenIn = tc.tokenIn
+				}
+				if tc.amountIn != nil {
+					amountIn = tc.amountIn
+				}
+				if tc.tokenOut != "" {
+					tokenOut = tc.tokenOut
+				}
+
+				_, err := calcAmountOut(newTestPoolSimulator(t, extra), tokenIn, amountIn, tokenOut)
+				assert.ErrorIs(t, err, tc.err)
+			})
+		}
+	})
+}
+
+func TestPoolSimulator_UpdateBalance(t *testing.T) {
+	t.Parallel()
+
+	extra := testExtra()
+	extra.Markets[0].VirtualMarketID = "0x01"
+	extra.VirtualInventories = map[string]*VirtualInventory{
+		"0x01": {Long: amount(1000, 18), Short: amount(2_000_000, 6)},
+	}
+	p := newTestPoolSimulator(t, extra)
+	amountIn := amount(10, 18)
+
+	before, err := calcAmountOut(p, weth, amountIn, usdc)
+	require.NoError(t, err)
+
+	cloned := p.CloneState()
+	result, err := calcAmountOut(cloned, weth, amountIn, usdc)
+	require.NoError(t, err)
+	cloned.UpdateBalance(pool.UpdateBalanceParams{
+		TokenAmountIn:  pool.TokenAmount{Token: weth, Amount: amountIn},
+		TokenAmountOut: *result.TokenAmountOut,
+		SwapInfo:       result.SwapInfo,
+	})
+
+	clonedMarket := cloned.(*PoolSimulator).markets[0]
+	assert.Greater(t, clonedMarket.Long.PoolAmount.Cmp(p.markets[0].Long.PoolAmount), 0)
+	assert.Equal(t, new(big.Int).Sub(p.markets[0].Short.PoolAmount, result.TokenAmountOut.Amount),
+		clonedMarket.Short.PoolAmount)
+	assert.Greater(t, cloned.(*PoolSimulator).virtualInventories["0x01"].Long.Cmp(p.virtualInventories["0x01"].Long), 0)
+
+	after, err := calcAmountOut(cloned, weth, amountIn, usdc)
+	require.NoError(t, err)
+	assert.Less(t, after.TokenAmountOut.Amount.Cmp(before.TokenAmountOut.Amount), 0)
+
+	unchanged, err := calcAmountOut(p, weth, amountIn, usdc)
+	require.NoError(t, err)
+	assert.Equal(t, before.TokenAmountOut.Amount, unchanged.TokenAmountOut.Amount)
+}
+
+func TestPoolSimulator_CanSwapTo(t *testing.T) {
+	t.Parallel()
+
+	p := newTestPoolSimulator(t, testExtra())
+	assert.ElementsMatch(t, []string{usdc, wbtc}, p.CanSwapTo(weth))
+	assert.ElementsMatch(t, []string{weth, wbtc}, p.CanSwapFrom(usdc))
+	assert.ElementsMatch(t, []string{usdc, weth}, p.CanSwapTo(wbtc))
+}
@@ -0,0 +1,276 @@
+package gmxv2
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/go-resty/resty/v2"
+	"github.com/goccy/go-json"
+	"github.com/samber/lo"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	pooltrack "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/tracker"
+)
+
+type PoolTracker struct {
+	config       *Config
+	ethrpcClient *ethrpc.Client
+	httpClient   *resty.Client
+}
+
+var _ = pooltrack.RegisterFactoryCE0(DexType, NewPoolTracker)
+
+func NewPoolTracker(config *Config, ethrpcClient *ethrpc.Client) *PoolTracker {
+	httpClient := resty.NewWithClient(lo.ToPtr(lo.FromPtr(http.DefaultClient))).
+		SetBaseURL(config.HTTPConfig.BaseURL).
+		SetTimeout(config.HTTPConfig.Timeout.Duration).
+		SetRetryCount(config.HTTPConfig.RetryCount)
+
+	return &PoolTracker{
+		config:       config,
+		ethrpcClient: ethrpcClient,
+		httpClient:   httpClient,
+	}
+}
+
+func (t *PoolTracker) GetNewPoolState(
+	ctx context.Context,
+	p entity.Pool,
+	_ pool.GetNewPoolStateParams,
+) (entity.Pool, error) {
+	lg := logger.WithFields(logger.Fields{"poolAddress": p.Address, "dexID": t.config.DexID})
+	lg.Info("start getting new state of pool")
+	defer lg.Info("finished getting new state of pool")
+
+	marketProps, err := getMarkets(ctx, t.ethrpcClient, t.config)
+	if err != nil {
+		lg.WithFields(logger.Fields{"error": err}).Error("failed to get markets")
+		return p, err
+	}
+
+	prices, err := t.getPrices(ctx, marketProps)
+	if err != nil {
+		lg.WithFields(logger.Fields{"error": err}).Error("failed to get prices")
+		return p, err
+	}
+
+	extra, blockNumber, err := t.getMarketsState(ctx, marketProps)
+	if err != nil {
+		lg.WithFields(logger.Fields{"error": err}).Error("failed to get markets state")
+		return p, err
+	}
+	extra.Prices = prices
+
+	if blockNumber != nil && p.BlockNumber > blockNumber.Uint64() {
+		return p, nil
+	}
+
+	extraBytes, err := json.Marshal(extra)
+	if err != nil {
+		return p, err
+	}
+
+	poolAmounts := map[string]*big.Int{}
+	addPoolAmount := func(token string, amount *big.Int) {
+		if poolAmounts[token] == nil {
+			poolAmounts[token] = new(big.Int)
+		}
+		poolAmounts[token].Add(poolAmounts[token], amount)
+	}
+	for _, m := range extra.Markets {
+		addPoolAmount(m.LongToken, m.Long.PoolAmount)
+		addPoolAmount(m.ShortToken, m.Short.PoolAmount)
+	}
+
+	p.Extra = string(extraBytes)
+	p.Reserves = lo.Map(p.Tokens, func(token *entity.PoolToken, _ int) string {
+		if amount := poolAmounts[token.Address]; amount != nil {
+			return amount.String()
+		}
+		return "0"
+	})
+	p.Timestamp = time.Now().Unix()
+	if blockNumber != nil {
+		p.BlockNumber = blockNumber.Uint64()
+	}
+
+	return p, nil
+}
+
+// getPrices returns the oracle prices of the long, short and index tokens of markets from the tickers API.
+func (t *PoolTracker) getPrices(ctx context.Context, markets []MarketProps) (map[string]*Price, error) {
+	var tickers []Ticker
+	resp, err := t.httpClient.R().SetContext(ctx).SetResult(&tickers).Get(tickersEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call tickers API: %w", err)
+	}
+
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("tickers API returned status %v", resp.Status())
+	}
+
+	isMarketToken := map[string]bool{}
+	for _, market := range markets {
+		for _, token := range []common.Address{market.IndexToken, market.LongToken, market.ShortToken} {
+			isMarketToken[hexutil.Encode(token[:])] = true
+		}
+	}
+
+	prices := make(map[string]*Price, len(isMarketToken))
+	for _, ticker := range tickers {
+		token := strings.ToLower(ticker.TokenAddress)
+		if !isMarketToken[token] {
+			continue
+		}
+
+		minPrice, ok := new(big.Int).SetString(ticker.MinPrice, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid min price %q of %s", ticker.MinPrice, token)
+		}
+		maxPrice, ok := new(big.Int).SetString(ticker.MaxPrice, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid max price %q of %s", ticker.MaxPrice, token)
+		}
+		prices[token] = &Price{Min: minPrice, Max: maxPrice}
+	}
+
+	return prices, nil
+}
+
+// marketState holds the DataStore values of a market read before being folded into a Market.
+type marketState struct {
+	isDisabled      bool
+	virtualMarketID common.Hash
+	// openInterest and openInterestInTokens are indexed by [isLong][isLongCollateral].
+	openInterest         [2][2]*big.Int
+	openInterestInTokens [2][2]*big.Int
+}
+
+// getMarketsState reads the DataStore values of markets at a single block, in batches of marketsPerRequest markets.
+func (t *PoolTracker) getMarketsState(ctx context.Context, marketProps []MarketProps) (*Extra, *big.Int, error) {
+	dataStore := t.config.DataStore
+	getUint := func(key common.Hash) *ethrpc.Call {
+		return &ethrpc.Call{ABI: dataStoreABI, Target: dataStore, Method: dataStoreMethodGetUint, Params: []any{key}}
+	}
+
+	extra := &Extra{Markets: make([]*Market, len(marketProps))}
+	states := make([]marketState, len(marketProps))
+	var blockNumber *big.Int
+	for start := 0; start < len(marketProps); start += marketsPerRequest {
+		end := min(start+marketsPerRequest, len(marketProps))
+
+		req := t.ethrpcClient.NewRequest().SetContext(ctx).SetBlockNumber(blockNumber)
+		if start == 0 {
+			req.AddCall(getUint(keySwapFeeReceiverFactor), []any{&extra.SwapFeeReceiverFactor})
+		}
+
+		for i := start; i < end; i++ {
+			props, state := marketProps[i], &states[i]
+			market := props.MarketToken
+			m := &Market{
+				MarketToken: hexutil.Encode(props.MarketToken[:]),
+				IndexToken:  hexutil.Encode(props.IndexToken[:]),
+				LongToken:   hexutil.Encode(props.LongToken[:]),
+				ShortToken:  hexutil.Encode(props.ShortToken[:]),
+			}
+			extra.Markets[i] = m
+
+			req.AddCall(&ethrpc.Call{ABI: dataStoreABI, Target: dataStore, Method: dataStoreMethodGetBool,
+				Params: []any{isMarketDisabledKey(market)}}, []any{&state.isDisabled}).
+				AddCall(&ethrpc.Call{ABI: dataStoreABI, Target: dataStore, Method: dataStoreMethodGetBytes32,
+					Params: []any{virtualMarketIDKey(market)}}, []any{&state.virtualMarketID}).
+				AddCall(getUint(swapImpactFactorKey(market, true)), []any{&m.PositiveSwapImpactFactor}).
+				AddCall(getUint(swapImpactFactorKey(market, false)), []any{&m.NegativeSwapImpactFactor}).
+				AddCall(getUint(swapImpactExponentFactorKey(market)), []any{&m.SwapImpactExponentFactor}).
+				AddCall(getUint(swapFeeFactorKey(market, true)), []any{&m.SwapFeeFactorForPositiveImpact}).
+				AddCall(getUint(swapFeeFactorKey(market, false)), []any{&m.SwapFeeFactorForNegativeImpact}).
+				AddCall(getUint(positionFeeFactorKey(market, true)), []any{&m.PositionFeeFactorForPositiveImpact}).
+				AddCall(getUint(positionFeeFactorKey(market, false)), []any{&m.PositionFeeFactorForNegativeImpact})
+
+			for isLong, side := range map[bool]*MarketSide{true: &m.Long, false: &m.Short} {
+				token := lo.Ternary(isLong, props.LongToken, props.ShortToken)
+				req.AddCall(getUint(poolAmountKey(market, token)), []any{&side.PoolAmount}).
+					AddCall(getUint(maxPoolAmountKey(market, token)), []any{&side.MaxPoolAmount}).
+					AddCall(getUint(swapImpactPoolAmountKey(market, token)), []any{&side.SwapImpactPoolAmount}).
+					AddCall(getUint(reserveFactorKey(market, isLong)), []any{&side.ReserveFactor})
+
+				for j, collateralToken := range []common.Address{props.ShortToken, props.LongToken} {
+					req.AddCall(getUint(openInterestKey(market, collateralToken, isLong)),
+						[]any{&state.openInterest[lo.Ternary(isLong, 1, 0)][j]}).
+						AddCall(getUint(openInterestInTokensKey(market, collateralToken, isLong)),
+							[]any{&state.openInterestInTokens[lo.Ternary(isLong, 1, 0)][j]})
+				}
+			}
+		}
+
+		resp, err := req.Aggregate()
+		if err != nil {
+			return nil, nil, err
+		}
+		if blockNumber == nil {
+			blockNumber = resp.BlockNumber
+		}
+	}
+
+	var virtualMarketIDs []common.Hash
+	for i, m := range extra.Markets {
+		state := &states[i]
+		m.IsDisabled = state.isDisabled
+		if state.virtualMarketID != (common.Hash{}) {
+			m.VirtualMarketID = state.virtualMarketID.Hex()
+			virtualMarketIDs = append(virtualMarketIDs, state.virtualMarketID)
+		}
+		m.Long.OpenInterest = new(big.Int).Add(state.openInterest[1][0], state.openInterest[1][1])
+		m.Long.OpenInterestInTokens = new(big.Int).Add(state.openInterestInTokens[1][0],
+			state.openInterestInTokens[1][1])
+		m.Short.OpenInterest = new(big.Int).Add(state.openInterest[0][0], state.openInterest[0][1])
+		m.Short.OpenInterestInTokens = new(big.Int).Add(state.openInterestInTokens[0][0],
+			state.openInterestInTokens[0][1])
+	}
+
+	virtualInventories, err := t.getVirtualInventories(ctx, lo.Uniq(virtualMarketIDs), blockNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+	extra.VirtualInventories = virtualInventories
+
+	return extra, blockNumber, nil
+}
+
+// getVirtualInventories returns the virtual inventories for swaps of virtualMarketIDs, keyed by their hex ids.
+func (t *PoolTracker) getVirtualInventories(ctx context.Context, virtualMarketIDs []common.Hash,
+	blockNumber *big.Int) (map[string]*VirtualInventory, error) {
+	if len(virtualMarketIDs) == 0 {
+		return nil, nil
+	}
+
+	virtualInventories := make(map[string]*VirtualInventory, len(virtualMarketIDs))
+	req := t.ethrpcClient.NewRequest().SetContext(ctx).SetBlockNumber(blockNumber)
+	for _, id := range virtualMarketIDs {
+		vi := &VirtualInventory{}
+		virtualInventories[id.Hex()] = vi
+		for _, isLongToken := range []bool{true, false} {
+			req.AddCall(&ethrpc.Call{
+				ABI:    dataStoreABI,
+				Target: t.config.DataStore,
+				Method: dataStoreMethodGetUint,
+				Params: []any{virtualInventoryForSwapsKey(id, isLongToken)},
+			}, []any{lo.Ternary(isLongToken, &vi.Long, &vi.Short)})
+		}
+	}
+
+	if _, err := req.Aggregate(); err != nil {
+		return nil, err
+	}
+
+	return virtualInventories, nil
+}
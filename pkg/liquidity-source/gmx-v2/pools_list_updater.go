@@ -0,0 +1,120 @@
+package gmxv2
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/goccy/go-json"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	poollist "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/list"
+)
+
+// PoolsListUpdater lists the GM markets of a GMX v2 deployment as a single pool, addressed by its DataStore.
+type PoolsListUpdater struct {
+	config       *Config
+	ethrpcClient *ethrpc.Client
+}
+
+var _ = poollist.RegisterFactoryCE(DexType, NewPoolsListUpdater)
+
+func NewPoolsListUpdater(config *Config, ethrpcClient *ethrpc.Client) *PoolsListUpdater {
+	return &PoolsListUpdater{
+		config:       config,
+		ethrpcClient: ethrpcClient,
+	}
+}
+
+func (u *PoolsListUpdater) GetNewPools(ctx context.Context, metadataBytes []byte) ([]entity.Pool, []byte, error) {
+	var metadata Meta
+	if len(metadataBytes) > 0 {
+		if err := json.Unmarshal(metadataBytes, &metadata); err != nil {
+			return nil, metadataBytes, err
+		}
+	}
+
+	markets, err := getMarkets(ctx, u.ethrpcClient, u.config)
+	if err != nil {
+		logger.WithFields(logger.Fields{"dexId": u.config.DexID, "error": err}).Error("failed to get markets")
+		return nil, metadataBytes, err
+	}
+
+	if len(markets) == metadata.MarketCount {
+		return nil, metadataBytes, nil
+	}
+
+	tokens := poolTokens(markets)
+	reserves := make(entity.PoolReserves, len(tokens))
+	for i := range reserves {
+		reserves[i] = "0"
+	}
+
+	newMetadataBytes, err := json.Marshal(Meta{MarketCount: len(markets)})
+	if err != nil {
+		return nil, metadataBytes, err
+	}
+
+	return []entity.Pool{{
+		Address:   strings.ToLower(u.config.DataStore),
+		Exchange:  u.config.DexID,
+		Type:      DexType,
+		Timestamp: time.Now().Unix(),
+		Reserves:  reserves,
+		Tokens:    tokens,
+	}}, newMetadataBytes, nil
+}
+
+// getMarkets returns the swappable markets of the deployment, i.e. those with distinct long and short tokens.
+func getMarkets(ctx context.Context, ethrpcClient *ethrpc.Client, config *Config) ([]MarketProps, error) {
+	var markets []MarketProps
+	for start := 0; ; start += marketsPageSize {
+		var page []MarketProps
+		if _, err := ethrpcClient.NewRequest().SetContext(ctx).AddCall(&ethrpc.Call{
+			ABI:    readerABI,
+			Target: config.Reader,
+			Method: readerMethodGetMarkets,
+			Params: []any{
+				common.HexToAddress(config.DataStore),
+				big.NewInt(int64(start)),
+				big.NewInt(int64(start + marketsPageSize)),
+			},
+		}, []any{&page}).Call(); err != nil {
+			return nil, err
+		}
+
+		for _, market := range page {
+			if market.LongToken != market.ShortToken {
+				markets = append(markets, market)
+			}
+		}
+
+		if len(page) < marketsPageSize {
+			return markets, nil
+		}
+	}
+}
+
+// poolTokens returns the long and short tokens of markets, in the order of their first market.
+func poolTokens(markets []MarketProps) []*entity.PoolToken {
+	var tokens []*entity.PoolToken
+	seen := map[common.Address]bool{}
+	for _, market := range markets {
+		for _, token := range []common.Address{market.LongToken, market.ShortToken} {
+			if seen[token] {
+				continue
+			}
+			seen[token] = true
+			tokens = append(tokens, &entity.PoolToken{
+				Address:   hexutil.Encode(token[:]),
+				Swappable: true,
+			})
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,82 @@
+package gmxv2
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Extra struct {
+	Markets               []*Market                    `json:"markets"`
+	Prices                map[string]*Price            `json:"prices"`
+	VirtualInventories    map[string]*VirtualInventory `json:"virtualInventories,omitempty"`
+	SwapFeeReceiverFactor *big.Int                     `json:"swapFeeReceiverFactor"`
+}
+
+// Market is the state of a GM market. Long holds the amounts of the long token and the open interest of long
+// positions, Short those of the short token and short positions.
+type Market struct {
+	MarketToken     string `json:"marketToken"`
+	IndexToken      string `json:"indexToken"`
+	LongToken       string `json:"longToken"`
+	ShortToken      string `json:"shortToken"`
+	IsDisabled      bool   `json:"isDisabled,omitempty"`
+	VirtualMarketID string `json:"virtualMarketId,omitempty"`
+
+	Long  MarketSide `json:"long"`
+	Short MarketSide `json:"short"`
+
+	PositiveSwapImpactFactor           *big.Int `json:"positiveSwapImpactFactor"`
+	NegativeSwapImpactFactor           *big.Int `json:"negativeSwapImpactFactor"`
+	SwapImpactExponentFactor           *big.Int `json:"swapImpactExponentFactor"`
+	SwapFeeFactorForPositiveImpact     *big.Int `json:"swapFeeFactorForPositiveImpact"`
+	SwapFeeFactorForNegativeImpact     *big.Int `json:"swapFeeFactorForNegativeImpact"`
+	PositionFeeFactorForPositiveImpact *big.Int `json:"positionFeeFactorForPositiveImpact"`
+	PositionFeeFactorForNegativeImpact *big.Int `json:"positionFeeFactorForNegativeImpact"`
+}
+
+type MarketSide struct {
+	PoolAmount           *big.Int `json:"poolAmount"`
+	MaxPoolAmount        *big.Int `json:"maxPoolAmount"`
+	SwapImpactPoolAmount *big.Int `json:"swapImpactPoolAmount"`
+	ReserveFactor        *big.Int `json:"reserveFactor"`
+	OpenInterest         *big.Int `json:"openInterest"`
+	OpenInterestInTokens *big.Int `json:"openInterestInTokens"`
+}
+
+// Price is the oracle price of one unit of a token, with 30 - token decimals decimals.
+type Price struct {
+	Min *big.Int `json:"min"`
+	Max *big.Int `json:"max"`
+}
+
+// VirtualInventory is the virtual inventory for swaps shared by the markets of a virtual market id.
+type VirtualInventory struct {
+	Long  *big.Int `json:"long"`
+	Short *big.Int `json:"short"`
+}
+
+type SwapInfo struct {
+	SwapPath []string `json:"swapPath"`
+
+	hops []*swapResult
+}
+
+type Meta struct {
+	MarketCount int `json:"marketCount"`
+}
+
+// MarketProps is Market.Props returned by Reader.getMarkets.
+type MarketProps struct {
+	MarketToken common.Address
+	IndexToken  common.Address
+	LongToken   common.Address
+	ShortToken  common.Address
+}
+
+type Ticker struct {
+	TokenAddress string `json:"tokenAddress"`
+	TokenSymbol  string `json:"tokenSymbol"`
+	MinPrice     string `json:"minPrice"`
+	MaxPrice     string `json:"maxPrice"`
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/holiman/uint256"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/clob"
 )
 
 const (
@@ -26,5 +27,5 @@ var (
 	ErrInvalidAmount         = errors.New("invalid amount")
 	ErrEmptyOrders           = errors.New("empty orders")
 	ErrExceededSafetyBuffer  = errors.New("exceed safety buffer")
-	ErrInsufficientLiquidity = errors.New("insufficient liquidity")
+	ErrInsufficientLiquidity = clob.ErrInsufficientLiquidity
 )
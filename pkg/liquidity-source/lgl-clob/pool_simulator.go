@@ -14,6 +14,7 @@ import (
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/clob"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
 )
 
@@ -68,105 +69,29 @@ func (p *PoolSimulator) CalcAmountOut(param pool.CalcAmountOutParams) (swapResul
 	}
 
 	// tokenOut is tokenX means buy, tokenOut is tokenY means sell
-	var levels *OrderBookLevels
-	var scalingFactorIn, scalingFactorOut *uint256.Int
 	isBuy := indexOut == 0
-	if isBuy {
-		levels = &p.Asks
-		scalingFactorIn = p.ScalingFactorY
-		scalingFactorOut = p.ScalingFactorX
-	} else {
-		levels = &p.Bids
-		scalingFactorIn = p.ScalingFactorX
-		scalingFactorOut = p.ScalingFactorY
-	}
+	levels, book := p.book(isBuy)
 	if len(levels.ArrayPrices) == 0 {
 		return nil, ErrEmptyOrders
 	}
 
-	// for buys fees deducted from tokenIn (tokenY), for sells fees deducted from result tokenOut (tokenY)
-	var availableAmountIn, tmp uint256.Int
-	availableAmountIn.Set(amtIn)
-	if isBuy {
-		availableAmountIn.MulDivOverflow(&availableAmountIn, big256.BONE, tmp.Add(big256.BONE, p.swapFee))
-	}
-
-	scaledAmountIn := availableAmountIn.Div(&availableAmountIn, scalingFactorIn)
-	var executedValue, executedScaledAmountOut, executedScaledAmountIn uint256.Int
-
-	var executedLevels int
-	var executionDone bool
-	var executedShares uint256.Int
-	for i, price := range levels.ArrayPrices {
-		shares := levels.ArrayShares[i] // in tokenX
-		executedLevels++
-
-		if isBuy {
-			executedShares.Div(scaledAmountIn, price) // in tokenX
-		} else {
-			executedShares.Set(scaledAmountIn) // in tokenX
-		}
-		if executedShares.IsZero() { // scaledAmountIn/price can be 0 i.e. remaining>0
-			break
-		}
-		if executionDone = !executedShares.Gt(shares); !executionDone { // level not enough shares, fully used
-			executedShares.Set(shares) // in tokenX
-		}
-		executedValue.Mul(&executedShares, price) // in tokenY
-
-		if isBuy {
-			scaledAmountIn.Sub(scaledAmountIn, &executedValue)                     // in tokenY
-			executedScaledAmountOut.Add(&executedScaledAmountOut, &executedShares) // in tokenX
-			executedScaledAmountIn.Add(&executedScaledAmountIn, &executedValue)    // in tokenY
-		} else {
-			scaledAmountIn.Sub(scaledAmountIn, &executedShares)                   // in tokenX
-			executedScaledAmountOut.Add(&executedScaledAmountOut, &executedValue) // in tokenY
-			executedScaledAmountIn.Add(&executedScaledAmountIn, &executedShares)  // in tokenX
-		}
-
-		if executionDone {
-			break
-		}
-	}
-	// 1:190576 2:236653 3:267273 4:269108 5:272492 6:244971 8:241112 9:227946 10:237471
-	// 1:494222 2:497402 after latest update
-	gas := int64(197346*math.Log(float64(executedLevels+1)/2) + 494222)
-	if executedShares.Eq(levels.ArrayShares[executedLevels-1]) {
-		executedShares.Clear()
-		executedLevels++
-	}
-
-	executedAmountIn := executedScaledAmountIn.Mul(&executedScaledAmountIn, scalingFactorIn)
-	executedAmountOut := executedScaledAmountOut.Mul(&executedScaledAmountOut, scalingFactorOut)
-	reserveOutF, _ := p.GetReserves()[indexOut].Float64()
-	if p.cumAmtOutF+executedAmountOut.Float64() > reserveOutF*safetyBuffer {
-		return nil, ErrExceededSafetyBuffer
+	fill, err := book.FillExactIn(amtIn)
+	if err != nil {
+		return nil, err
 	}
-
-	var feesTokenY, remainingAmountIn *uint256.Int
-	priceLimit := levels.ArrayPrices[len(levels.ArrayPrices)-1]
-	if isBuy {
-		feesTokenY = big256.MulWadUp(&tmp, executedAmountIn, p.swapFee)
-		remainingAmountIn = amtIn.Sub(amtIn, executedAmountIn).Sub(amtIn, feesTokenY)
-		priceLimit = big256.MulDivUp(&executedValue, priceLimit, uPriceLimitMultiplier, big256.UBasisPoint)
-	} else {
-		feesTokenY = big256.MulWadUp(&tmp, executedAmountOut, p.swapFee)
-		executedAmountOut.Sub(executedAmountOut, feesTokenY)
-		remainingAmountIn = amtIn.Sub(amtIn, executedAmountIn)
-		priceLimit = big256.MulDivDown(&executedValue, priceLimit, big256.UBasisPoint, uPriceLimitMultiplier)
+	if err = p.checkSafetyBuffer(indexOut, fill, isBuy); err != nil {
+		return nil, err
 	}
-	priceLimit = round(priceLimit, priceLimitPrecision, isBuy)
 
 	return &pool.CalcAmountOutResult{
-		TokenAmountOut:         &pool.TokenAmount{Token: tokenOut, Amount: executedAmountOut.ToBig()},
-		Fee:                    &pool.TokenAmount{Token: p.Info.Tokens[1], Amount: feesTokenY.ToBig()},
-		RemainingTokenAmountIn: &pool.TokenAmount{Token: tokenIn, Amount: remainingAmountIn.ToBig()},
-		Gas:                    gas,
+		TokenAmountOut:         &pool.TokenAmount{Token: tokenOut, Amount: fill.AmountOut.ToBig()},
+		Fee:                    &pool.TokenAmount{Token: p.Info.Tokens[1], Amount: fill.Fee.ToBig()},
+		RemainingTokenAmountIn: &pool.TokenAmount{Token: tokenIn, Amount: fill.RemainingIn.ToBig()},
+		Gas:                    estimateGas(fill),
 		SwapInfo: SwapInfo{
-			executedLevels:     executedLevels,
-			lastExecutedShares: &executedShares,
-			HasNative:          p.SupportsNativeEth,
-			PriceLimit:         priceLimit,
+			fill:       fill,
+			HasNative:  p.SupportsNativeEth,
+			PriceLimit: priceLimit(levels, isBuy),
 		},
 	}, nil
 }
@@ -183,143 +108,82 @@ func (p *PoolSimulator) CalcAmountIn(param pool.CalcAmountInParams) (*pool.CalcA
 	}
 
 	isBuy := indexOut == 0
-	var levels *OrderBookLevels
-	var scalingFactorIn, scalingFactorOut *uint256.Int
-	if isBuy {
-		levels = &p.Asks
-		scalingFactorIn = p.ScalingFactorY
-		scalingFactorOut = p.ScalingFactorX
-	} else {
-		levels = &p.Bids
-		scalingFactorIn = p.ScalingFactorX
-		scalingFactorOut = p.ScalingFactorY
-	}
+	levels, book := p.book(isBuy)
 	if len(levels.ArrayPrices) == 0 {
 		return nil, ErrEmptyOrders
 	}
 
-	var executedScaledAmountIn, executedScaledAmountOut, executedShares, executedValue, tmp uint256.Int
-	var uOne uint256.Int
-	uOne.SetUint64(1)
-	var executedLevels int
-	var executionDone bool
-
-	if isBuy {
-		// Buy tokenX with tokenY: find min Y to receive at least amtOut tokenX.
-		// scaledTarget = ceil(amtOut / sfX): minimum scaled X units to produce >= amtOut
-		var scaledTarget uint256.Int
-		big256.MulDivUp(&scaledTarget, amtOut, &uOne, scalingFactorOut)
+	fill, err := book.FillExactOut(amtOut)
+	if err != nil {
+		return nil, err
+	}
+	if err = p.checkSafetyBuffer(indexOut, fill, isBuy); err != nil {
+		return nil, err
+	}
 
-		for i, price := range levels.ArrayPrices {
-			shares := levels.ArrayShares[i]
-			executedLevels++
-			executedShares.Set(&scaledTarget)
-			if executionDone = !executedShares.Gt(shares); !executionDone {
-				executedShares.Set(shares)
-			}
-			executedValue.Mul(&executedShares, price) // cost in scaled Y
-			scaledTarget.Sub(&scaledTarget, &executedShares)
-			executedScaledAmountOut.Add(&executedScaledAmountOut, &executedShares)
-			executedScaledAmountIn.Add(&executedScaledAmountIn, &executedValue)
-			if executionDone {
-				break
-			}
-		}
-		if !executionDone {
-			return nil, ErrInsufficientLiquidity
-		}
-	} else {
-		// Sell tokenX for tokenY: find min X to receive at least amtOut net tokenY.
-		// Find minimum scaledGrossOut such that grossOut - ceil(grossOut * fee / BONE) >= amtOut.
-		var scaledGrossOut, boneMinusFee uint256.Int
-		boneMinusFee.Sub(big256.BONE, p.swapFee)
-		big256.MulDivUp(&tmp, amtOut, big256.BONE, &boneMinusFee)
-		big256.MulDivUp(&scaledGrossOut, &tmp, &uOne, scalingFactorOut)
-		for {
-			var grossOut, fee, net uint256.Int
-			grossOut.Mul(&scaledGrossOut, scalingFactorOut)
-			big256.MulWadUp(&fee, &grossOut, p.swapFee)
-			net.Sub(&grossOut, &fee)
-			if !net.Lt(amtOut) {
-				break
-			}
-			scaledGrossOut.AddUint64(&scaledGrossOut, 1)
-		}
+	return &pool.CalcAmountInResult{
+		TokenAmountIn:           &pool.TokenAmount{Token: tokenIn, Amount: fill.AmountIn.ToBig()},
+		RemainingTokenAmountOut: &pool.TokenAmount{Token: tokenAmountOut.Token, Amount: big.NewInt(0)},
+		Fee:                     &pool.TokenAmount{Token: p.Info.Tokens[1], Amount: fill.Fee.ToBig()},
+		Gas:                     estimateGas(fill),
+		SwapInfo: SwapInfo{
+			fill:       fill,
+			HasNative:  p.SupportsNativeEth,
+			PriceLimit: priceLimit(levels, isBuy),
+		},
+	}, nil
+}
 
-		remaining := new(uint256.Int).Set(&scaledGrossOut)
-		for i, price := range levels.ArrayPrices {
-			shares := levels.ArrayShares[i]
-			executedLevels++
-			executedValue.Mul(shares, price) // max scaled Y output at this level
-			if !executedValue.Lt(remaining) {
-				// Partial fill: ceil(remaining / price) shares to get >= remaining scaled Y
-				big256.MulDivUp(&executedShares, remaining, &uOne, price)
-				if executedShares.Gt(shares) {
-					executedShares.Set(shares)
-				}
-				executedValue.Mul(&executedShares, price)
-				remaining.Clear()
-				executionDone = true
-			} else {
-				executedShares.Set(shares)
-				remaining.Sub(remaining, &executedValue)
-			}
-			executedScaledAmountIn.Add(&executedScaledAmountIn, &executedShares)
-			executedScaledAmountOut.Add(&executedScaledAmountOut, &executedValue)
-			if executionDone {
-				break
-			}
-		}
-		if !executionDone {
-			return nil, ErrInsufficientLiquidity
+// book returns the levels taken by a buy or a sell, and the book of them, in token amounts. Levels are filled by whole
+// shares; fees are charged in tokenY, on the input of buys and on the output of sells.
+func (p *PoolSimulator) book(isBuy bool) (*OrderBookLevels, *clob.Book) {
+	levels := lo.Ternary(isBuy, &p.Asks, &p.Bids)
+	book := &clob.Book{
+		Levels: make([]clob.Level, len(levels.ArrayPrices)),
+		Fee:    clob.Fee{Rate: p.swapFee, Precision: big256.BONE, OnInput: isBuy},
+	}
+	for i, price := range levels.ArrayPrices {
+		shares := levels.ArrayShares[i]
+		amountX := new(uint256.Int).Mul(shares, p.ScalingFactorX)
+		amountY := new(uint256.Int).Mul(shares, price)
+		amountY.Mul(amountY, p.ScalingFactorY)
+		if isBuy {
+			book.Levels[i] = clob.Level{In: amountY, Out: amountX, Lots: shares}
+		} else {
+			book.Levels[i] = clob.Level{In: amountX, Out: amountY, Lots: shares}
 		}
 	}
+	return levels, book
+}
 
-	gas := int64(197346*math.Log(float64(executedLevels+1)/2) + 494222)
-	if executedShares.Eq(levels.ArrayShares[executedLevels-1]) {
-		executedShares.Clear()
-		executedLevels++
+func (p *PoolSimulator) checkSafetyBuffer(indexOut int, fill *clob.Fill, isBuy bool) error {
+	amtOutF := fill.AmountOut.Float64()
+	if !isBuy {
+		amtOutF += fill.Fee.Float64()
 	}
-
-	executedAmountIn := executedScaledAmountIn.Mul(&executedScaledAmountIn, scalingFactorIn)
-	executedAmountOut := executedScaledAmountOut.Mul(&executedScaledAmountOut, scalingFactorOut)
-
 	reserveOutF, _ := p.GetReserves()[indexOut].Float64()
-	if p.cumAmtOutF+executedAmountOut.Float64() > reserveOutF*safetyBuffer {
-		return nil, ErrExceededSafetyBuffer
+	if p.cumAmtOutF+amtOutF > reserveOutF*safetyBuffer {
+		return ErrExceededSafetyBuffer
 	}
+	return nil
+}
 
-	var feesTokenY *uint256.Int
-	var amtIn *uint256.Int
-	if isBuy {
-		feesTokenY = big256.MulWadUp(&tmp, executedAmountIn, p.swapFee)
-		amtIn = new(uint256.Int).Add(executedAmountIn, feesTokenY)
-	} else {
-		feesTokenY = big256.MulWadUp(&tmp, executedAmountOut, p.swapFee)
-		executedAmountOut.Sub(executedAmountOut, feesTokenY)
-		amtIn = executedAmountIn
-	}
+// 1:190576 2:236653 3:267273 4:269108 5:272492 6:244971 8:241112 9:227946 10:237471
+// 1:494222 2:497402 after latest update
+func estimateGas(fill *clob.Fill) int64 {
+	return int64(197346*math.Log(float64(max(fill.Walked, 1)+1)/2) + 494222)
+}
 
-	priceLimit := levels.ArrayPrices[len(levels.ArrayPrices)-1]
+// priceLimit returns the limit price of the swap: the worst price of the levels, with some slippage.
+func priceLimit(levels *OrderBookLevels, isBuy bool) *uint256.Int {
+	var limit uint256.Int
+	worst := levels.ArrayPrices[len(levels.ArrayPrices)-1]
 	if isBuy {
-		priceLimit = big256.MulDivUp(&executedValue, priceLimit, uPriceLimitMultiplier, big256.UBasisPoint)
+		big256.MulDivUp(&limit, worst, uPriceLimitMultiplier, big256.UBasisPoint)
 	} else {
-		priceLimit = big256.MulDivDown(&executedValue, priceLimit, big256.UBasisPoint, uPriceLimitMultiplier)
+		big256.MulDivDown(&limit, worst, big256.UBasisPoint, uPriceLimitMultiplier)
 	}
-	priceLimit = round(priceLimit, priceLimitPrecision, isBuy)
-
-	return &pool.CalcAmountInResult{
-		TokenAmountIn:           &pool.TokenAmount{Token: tokenIn, Amount: amtIn.ToBig()},
-		RemainingTokenAmountOut: &pool.TokenAmount{Token: tokenAmountOut.Token, Amount: big.NewInt(0)},
-		Fee:                     &pool.TokenAmount{Token: p.Info.Tokens[1], Amount: feesTokenY.ToBig()},
-		Gas:                     gas,
-		SwapInfo: SwapInfo{
-			executedLevels:     executedLevels,
-			lastExecutedShares: &executedShares,
-			HasNative:          p.SupportsNativeEth,
-			PriceLimit:         priceLimit,
-		},
-	}, nil
+	return round(&limit, priceLimitPrecision, isBuy)
 }
 
 func (p *PoolSimulator) CloneState() pool.IPoolSimulator {
@@ -330,19 +194,19 @@ func (p *PoolSimulator) CloneState() pool.IPoolSimulator {
 
 func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
 	si, ok := params.SwapInfo.(SwapInfo)
-	if !ok {
+	if !ok || si.fill == nil {
 		logger.Warn("failed to UpdateBalance for OnchainClob pool, wrong swapInfo type")
 		return
 	}
 
 	isBuy := p.GetTokenIndex(params.TokenAmountOut.Token) == 0
 	levels := lo.Ternary(isBuy, &p.Asks, &p.Bids)
-	fullyExecutedLevels := si.executedLevels - 1
-	levels.ArrayPrices = levels.ArrayPrices[fullyExecutedLevels:]
-	levels.ArrayShares = levels.ArrayShares[fullyExecutedLevels:]
-	if !si.lastExecutedShares.IsZero() {
+	filled := min(si.fill.Filled, len(levels.ArrayPrices))
+	levels.ArrayPrices = levels.ArrayPrices[filled:]
+	levels.ArrayShares = levels.ArrayShares[filled:]
+	if partial := si.fill.Partial; partial != nil && len(levels.ArrayShares) > 0 {
 		levels.ArrayShares = slices.Clone(levels.ArrayShares)
-		levels.ArrayShares[0] = new(uint256.Int).Sub(levels.ArrayShares[0], si.lastExecutedShares)
+		levels.ArrayShares[0] = new(uint256.Int).Sub(levels.ArrayShares[0], partial.Lots)
 	}
 	amtOutF, _ := params.TokenAmountOut.Amount.Float64()
 	p.cumAmtOutF += amtOutF
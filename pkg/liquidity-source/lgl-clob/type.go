@@ -5,6 +5,8 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/clob"
 )
 
 type OrderBookLevels struct {
@@ -69,8 +71,7 @@ type MarketInfo struct {
 }
 
 type SwapInfo struct {
-	executedLevels     int
-	lastExecutedShares *uint256.Int
-	HasNative          bool         `json:"e,omitempty"`
-	PriceLimit         *uint256.Int `json:"p,omitempty"`
+	fill       *clob.Fill
+	HasNative  bool         `json:"e,omitempty"`
+	PriceLimit *uint256.Int `json:"p,omitempty"`
 }
@@ -0,0 +1,170 @@
+package lo1inch
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/KyberNetwork/blockchain-toolkit/number"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+
+	helper1inch "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/lo1inch/helper"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	big256 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/clob"
+)
+
+// sideOrders are the orders of a swap side as clob.Orders, recording the orders filled by a walk.
+type sideOrders struct {
+	orders       []*Order
+	limit        pool.SwapLimit
+	takerAddress common.Address
+	currentTime  int64
+
+	// we need to update maker's remaining balance in 2 places:
+	// - in UpdateBalance: mainly to deal with case where maker has orders with same makerAsset but different takerAsset
+	// - when simulating filling each order here: we cannot do the same as in kyber-pmm (simulating first then check inventory limit at the end)
+	// because in LO we have multiple makers, and also because we still need to allow orders that have part of the balance available
+	// the problem is that in this func we cannot update the limit,
+	// so we'll use this map to track filled amount for each maker, then subtract from the original balance, to have the remaining balance available
+	filledMakingAmountByMaker map[string]*uint256.Int
+
+	totalMakingAmount *uint256.Int
+	filledOrders      []*FilledOrderInfo
+}
+
+func (o *sideOrders) Len() int {
+	return len(o.orders)
+}
+
+// remainingMakingAmount returns what is left of order to its maker's balance.
+func (o *sideOrders) remainingMakingAmount(order *Order) *uint256.Int {
+	orderRemainingMakingAmount := order.RemainingMakerAmount
+
+	// the actual available balance might be less than `order.RemainingMakerAmount`
+	// for example: in this pool, we have used another order for the same maker and makerAsset:takerAsset pair in the previous loop
+	if makerRemainingBalance := getMakerRemainingBalance(
+		o.limit,
+		o.filledMakingAmountByMaker,
+		order.Maker,
+		order.MakerAsset,
+	); makerRemainingBalance != nil && orderRemainingMakingAmount.Cmp(makerRemainingBalance) > 0 {
+		orderRemainingMakingAmount = makerRemainingBalance
+	}
+	return orderRemainingMakingAmount
+}
+
+func (o *sideOrders) FillIn(i int, amountIn *uint256.Int) (clob.Level, bool, error) {
+	order := o.orders[i]
+	makerTraits := helper1inch.NewMakerTraits(order.MakerTraits)
+	// Filter out expired orders
+	// Note: This is different from pool-service, we don't have any buffer here because when we simulate the order, real-time is important
+	if makerTraits.IsExpired(o.currentTime) {
+		return clob.Level{}, false, clob.ErrSkipOrder
+	}
+
+	// Order was filled, just skip it
+	orderRemainingMakingAmount := o.remainingMakingAmount(order)
+	if orderRemainingMakingAmount.Sign() <= 0 {
+		return clob.Level{}, false, clob.ErrSkipOrder
+	}
+
+	// calculate order's remaining taking amount
+	// orderRemainingTakingAmount = order.TakingAmount * orderRemainingMakingAmount / order.MakingAmount
+	// but some orders have extension and fee, so we need to use the extension to calculate the taking amount
+	orderRemainingTakingAmount, err := order.CalcTakingAmount(o.takerAddress, orderRemainingMakingAmount)
+	if err != nil {
+		return clob.Level{}, false, skipFullFillOnly(err)
+	}
+
+	o.totalMakingAmount.Add(o.totalMakingAmount, orderRemainingMakingAmount)
+
+	// Case 1: This order can fulfill the remaining amount in
+	if orderRemainingTakingAmount.Cmp(amountIn) >= 0 {
+		orderAmountOut, err := order.CalcMakingAmount(o.takerAddress, amountIn)
+		if err != nil {
+			return clob.Level{}, false, skipFullFillOnly(err)
+		}
+
+		// order too small
+		if orderAmountOut.Sign() <= 0 {
+			return clob.Level{}, false, clob.ErrSkipOrder
+		}
+
+		orderFilledMakingAmount := number.Set(orderAmountOut)
+		orderFilledTakingAmount := number.Set(amountIn)
+		o.filledOrders = append(o.filledOrders, newFilledOrderInfo(
+			order,
+			orderFilledMakingAmount,
+			orderFilledTakingAmount,
+		))
+
+		// orderAmountOut is the filled making amount for this order because this order is partially filled
+		addFilledMakingAmount(
+			o.filledMakingAmountByMaker,
+			order.Maker,
+			orderAmountOut,
+		)
+		return clob.Level{In: orderFilledTakingAmount, Out: orderFilledMakingAmount}, true, nil
+	}
+
+	// Case 2: This order can't fulfill the remaining amount in
+
+	// Skip this order if orderRemainingMakingAmount (limited by maker's balance/allowance)
+	// is less than order's original RemainingMakerAmount. This is because when executing,
+	// the contract will attempt to transfer the full original RemainingMakerAmount from the maker,
+	// so we need to ensure the maker has at least that much balance/allowance available.
+	if orderRemainingMakingAmount.Lt(order.RemainingMakerAmount) {
+		return clob.Level{}, false, clob.ErrSkipOrder
+	}
+
+	orderFilledMakingAmount := orderRemainingMakingAmount // because this order is fully filled
+	orderFilledTakingAmount := orderRemainingTakingAmount // because this order is fully filled
+	o.filledOrders = append(o.filledOrders, newFilledOrderInfo(
+		order,
+		orderFilledMakingAmount,
+		orderFilledTakingAmount,
+	))
+
+	addFilledMakingAmount(o.filledMakingAmountByMaker, order.Maker, orderFilledMakingAmount)
+	return clob.Level{In: number.Set(orderFilledTakingAmount), Out: number.Set(orderFilledMakingAmount)}, false, nil
+}
+
+// skipFullFillOnly skips orders that only allow full fills, returning any other error as is.
+func skipFullFillOnly(err error) error {
+	if errors.Is(err, ErrOnlyAllowFullFill) {
+		return clob.ErrSkipOrder
+	}
+	return err
+}
+
+// addBackupOrders adds the orders after the one a walk stopped at until their remaining making amount reaches 130% of
+// totalAmountOut.
+//
+// Currently, the aggregator finds route, returns some orders and sends them to the smart contract to execute.
+// We often meet edge cases that these orders can be fulfilled by a trading bot or another taker on the aggregator beforehand.
+// From that, the estimated amount out and filled orders are not correct. So we need to add more "backup" orders when sending to SC to the executor.
+// In this case, we will send some orders util total MakingAmount(remainMakingAmount)/estimated amountOut >= 1.3 (130%)
+func (o *sideOrders) addBackupOrders(last int, totalAmountOut *uint256.Int) {
+	totalAmountOutBF := new(big.Float).SetInt(totalAmountOut.ToBig())
+	for j := last + 1; j < len(o.orders); j++ {
+		if new(big.Float).SetInt(o.totalMakingAmount.ToBig()).Cmp(new(big.Float).Mul(totalAmountOutBF, FallbackPercentageOfTotalMakingAmount)) >= 0 {
+			break
+		}
+
+		order := o.orders[j]
+		orderRemainingMakingAmount := o.remainingMakingAmount(order)
+		if orderRemainingMakingAmount.Sign() <= 0 {
+			continue
+		}
+
+		o.totalMakingAmount.Add(o.totalMakingAmount, orderRemainingMakingAmount)
+		filledOrderInfo := newFilledOrderInfo(
+			order,
+			big256.U0,
+			big256.U0,
+		)
+		filledOrderInfo.IsBackup = true
+		o.filledOrders = append(o.filledOrders, filledOrderInfo)
+	}
+}
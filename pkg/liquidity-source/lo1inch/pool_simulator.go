@@ -18,6 +18,7 @@ import (
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
 	big256 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/clob"
 )
 
 type PoolSimulator struct {
@@ -175,172 +176,31 @@ func (p *PoolSimulator) CalcAmountOut(param pool.CalcAmountOutParams) (*pool.Cal
 		return nil, ErrNoOrderAvailable
 	}
 
-	totalAmountOut := number.Set(number.Zero)
-	remainingAmountIn := number.SetFromBig(tokenAmountIn.Amount)
+	side := &sideOrders{
+		orders:                    orders,
+		limit:                     param.Limit,
+		takerAddress:              p.takerAddress,
+		filledMakingAmountByMaker: make(map[string]*uint256.Int, len(p.minBalanceAllowanceByMakerAndAsset)),
+		totalMakingAmount:         number.Set(number.Zero),
+		filledOrders:              []*FilledOrderInfo{},
+		// calculate current time once so we don't have to re-calculate it for each order
+		currentTime: time.Now().Unix(),
+	}
+	fill, err := clob.FillOrdersExactIn(side, number.SetFromBig(tokenAmountIn.Amount))
+	if err != nil {
+		return nil, err
+	}
+	last := fill.Last()
+	if last == nil {
+		return nil, ErrCannotFulfillAmountIn
+	}
 
+	totalAmountOut := fill.AmountOut
+	side.addBackupOrders(last.Index, totalAmountOut)
 	swapInfo := SwapInfo{
 		AmountIn:     tokenAmountIn.Amount.String(),
 		SwapSide:     swapSide,
-		FilledOrders: []*FilledOrderInfo{},
-	}
-	isAmountInFulfilled := false
-
-	// we need to update maker's remaining balance in 2 places:
-	// - in UpdateBalance: mainly to deal with case where maker has orders with same makerAsset but different takerAsset
-	// - when simulating filling each order here: we cannot do the same as in kyber-pmm (simulating first then check inventory limit at the end)
-	// because in LO we have multiple makers, and also because we still need to allow orders that have part of the balance available
-	// the problem is that in this func we cannot update the limit,
-	// so we'll use this map to track filled amount for each maker, then subtract from the original balance, to have the remaining balance available
-	filledMakingAmountByMaker := make(map[string]*uint256.Int, len(p.minBalanceAllowanceByMakerAndAsset))
-
-	totalMakingAmount := number.Set(number.Zero)
-
-	// calculate current time once so we don't have to re-calculate it for each order
-	currentTime := time.Now().Unix()
-
-	for i, order := range orders {
-		makerTraits := helper1inch.NewMakerTraits(order.MakerTraits)
-		// Filter out expired orders
-		// Note: This is different from pool-service, we don't have any buffer here because when we simulate the order, real-time is important
-		if makerTraits.IsExpired(currentTime) {
-			continue
-		}
-
-		orderRemainingMakingAmount := order.RemainingMakerAmount
-
-		// the actual available balance might be less than `order.RemainingMakerAmount`
-		// for example: in this pool, we have used another order for the same maker and makerAsset:takerAsset pair in the previous loop
-		if makerRemainingBalance := getMakerRemainingBalance(
-			param.Limit,
-			filledMakingAmountByMaker,
-			order.Maker,
-			order.MakerAsset,
-		); makerRemainingBalance != nil && orderRemainingMakingAmount.Cmp(makerRemainingBalance) > 0 {
-			orderRemainingMakingAmount = makerRemainingBalance
-		}
-
-		// Order was filled, just skip it
-		if orderRemainingMakingAmount.Sign() <= 0 {
-			continue
-		}
-
-		// calculate order's remaining taking amount
-		// orderRemainingTakingAmount = order.TakingAmount * orderRemainingMakingAmount / order.MakingAmount
-		// but some orders have extension and fee, so we need to use the extension to calculate the taking amount
-		orderRemainingTakingAmount, err := order.CalcTakingAmount(p.takerAddress, orderRemainingMakingAmount)
-		if err != nil {
-			// if only allow full fill, skip this order
-			if err == ErrOnlyAllowFullFill {
-				continue
-			}
-
-			return nil, err
-		}
-
-		totalMakingAmount.Add(totalMakingAmount, orderRemainingMakingAmount)
-
-		// Case 1: This order can fulfill the remaining amount in
-		if orderRemainingTakingAmount.Cmp(remainingAmountIn) >= 0 {
-			orderAmountOut, err := order.CalcMakingAmount(p.takerAddress, remainingAmountIn)
-			if err != nil {
-				// if only allow full fill, skip this order
-				if err == ErrOnlyAllowFullFill {
-					continue
-				}
-
-				return nil, err
-			}
-
-			// order too small
-			if orderAmountOut.Sign() <= 0 {
-				continue
-			}
-
-			totalAmountOut.Add(totalAmountOut, orderAmountOut)
-
-			orderFilledMakingAmount := number.Set(orderAmountOut)
-			orderFilledTakingAmount := number.Set(remainingAmountIn)
-			filledOrderInfo := newFilledOrderInfo(
-				order,
-				orderFilledMakingAmount,
-				orderFilledTakingAmount,
-			)
-			swapInfo.FilledOrders = append(swapInfo.FilledOrders, filledOrderInfo)
-
-			isAmountInFulfilled = true
-
-			// orderAmountOut is the filled making amount for this order because this order is partially filled
-			addFilledMakingAmount(
-				filledMakingAmountByMaker,
-				order.Maker,
-				orderAmountOut,
-			)
-
-			// Currently, the aggregator finds route, returns some orders and sends them to the smart contract to execute.
-			// We often meet edge cases that these orders can be fulfilled by a trading bot or another taker on the aggregator beforehand.
-			// From that, the estimated amount out and filled orders are not correct. So we need to add more "backup" orders when sending to SC to the executor.
-			// In this case, we will send some orders util total MakingAmount(remainMakingAmount)/estimated amountOut >= 1.3 (130%)
-			totalAmountOutBF := new(big.Float).SetInt(totalAmountOut.ToBig())
-			for j := i + 1; j < len(orders); j++ {
-				if new(big.Float).SetInt(totalMakingAmount.ToBig()).Cmp(new(big.Float).Mul(totalAmountOutBF, FallbackPercentageOfTotalMakingAmount)) >= 0 {
-					break
-				}
-
-				order := orders[j]
-
-				orderRemainingMakingAmount := number.Set(order.RemainingMakerAmount)
-				if makerRemainingBalance := getMakerRemainingBalance(
-					param.Limit,
-					filledMakingAmountByMaker,
-					order.Maker,
-					order.MakerAsset,
-				); makerRemainingBalance != nil && orderRemainingMakingAmount.Cmp(makerRemainingBalance) > 0 {
-					orderRemainingMakingAmount = makerRemainingBalance
-				}
-
-				if orderRemainingMakingAmount.Sign() <= 0 {
-					continue
-				}
-
-				totalMakingAmount.Add(totalMakingAmount, orderRemainingMakingAmount)
-				filledOrderInfo := newFilledOrderInfo(
-					order,
-					big256.U0,
-					big256.U0,
-				)
-				filledOrderInfo.IsBackup = true
-				swapInfo.FilledOrders = append(swapInfo.FilledOrders, filledOrderInfo)
-			}
-
-			break
-		}
-
-		// Case 2: This order can't fulfill the remaining amount in
-
-		// Skip this order if orderRemainingMakingAmount (limited by maker's balance/allowance)
-		// is less than order's original RemainingMakerAmount. This is because when executing,
-		// the contract will attempt to transfer the full original RemainingMakerAmount from the maker,
-		// so we need to ensure the maker has at least that much balance/allowance available.
-		if orderRemainingMakingAmount.Lt(order.RemainingMakerAmount) {
-			continue
-		}
-
-		remainingAmountIn = number.Sub(remainingAmountIn, orderRemainingTakingAmount)
-		orderFilledMakingAmount := orderRemainingMakingAmount // because this order is fully filled
-		orderFilledTakingAmount := orderRemainingTakingAmount // because this order is fully filled
-		totalAmountOut = number.Add(totalAmountOut, orderFilledMakingAmount)
-		filledOrderInfo := newFilledOrderInfo(
-			order,
-			orderFilledMakingAmount,
-			orderFilledTakingAmount,
-		)
-		swapInfo.FilledOrders = append(swapInfo.FilledOrders, filledOrderInfo)
-
-		addFilledMakingAmount(filledMakingAmountByMaker, order.Maker, orderFilledMakingAmount)
-	}
-
-	if !isAmountInFulfilled {
-		return nil, ErrCannotFulfillAmountIn
+		FilledOrders: side.filledOrders,
 	}
 
 	return &pool.CalcAmountOutResult{
@@ -1,5 +1,7 @@
 package machima
 
+import "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/reorg"
+
 // Config is the upstream-supplied config. The subgraph URL is not here: pool-service builds the
 // GraphQL client from its own subgraph config block and injects it into the factories.
 type Config struct {
@@ -18,4 +20,7 @@ type Config struct {
 	WETH string `json:"weth"`
 	USDC string `json:"usdc"`
 	XMA  string `json:"xma"`
+
+	// Reorg opts the log-driven updates into reorg handling.
+	Reorg reorg.Config `json:"reorg"`
 }
@@ -21,7 +21,7 @@ import (
 //
 // The embedded simulator is what makes the tick math available, but every method that the tax
 // layer changes the result of MUST be overridden here — a promoted method silently bypasses tax.
-// That currently means CalcAmountOut, CalcAmountIn, UpdateBalance, CloneState and GetMetaInfo.
+// That currently means CalcAmountOut, CalcAmountIn, UpdateBalance, CloneState, GetMetaInfo and GetSpotPrice.
 type PoolSimulator struct {
 	*uniswapv3.PoolSimulator
 
@@ -245,6 +245,11 @@ func (p *PoolSimulator) CloneState() pool.IPoolSimulator {
 	return &cloned
 }
 
+// GetSpotPrice defers to the numeric estimate, which goes through the tax layer.
+func (p *PoolSimulator) GetSpotPrice(_, _ string) (float64, error) {
+	return 0, pool.ErrSpotPriceUnsupported
+}
+
 func (p *PoolSimulator) GetMetaInfo(_ string, _ string) any {
 	return PoolMeta{Router: p.routerAddress, ApprovalAddress: p.routerAddress, BlockNumber: p.Info.BlockNumber}
 }
@@ -16,6 +16,7 @@ import (
 	uniswapv3 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v3"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v3/ticks"
 	poolpkg "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/reorg"
 	pooltrack "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/tracker"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/eth"
 	graphqlpkg "github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/graphql"
@@ -33,6 +34,7 @@ type PoolTracker struct {
 	config       *Config
 	ethrpcClient *ethrpc.Client
 	v3           *uniswapv3.Tracker
+	reorgGuard   *reorg.Guard
 }
 
 var _ = pooltrack.RegisterFactoryCEG(DexType, NewPoolTracker)
@@ -50,7 +52,7 @@ func NewPoolTracker(cfg *Config, ethrpcClient *ethrpc.Client,
 		return nil, err
 	}
 
-	return &PoolTracker{config: cfg, ethrpcClient: ethrpcClient, v3: v3}, nil
+	return &PoolTracker{config: cfg, ethrpcClient: ethrpcClient, v3: v3, reorgGuard: reorg.NewGuard(cfg.Reorg)}, nil
 }
 
 // BootstrapPoolState is the ticks-based first pass: every tick, plus full state.
@@ -98,7 +100,14 @@ func (t *PoolTracker) FetchPoolTicks(ctx context.Context, p entity.Pool) (entity
 //     reads slot0/liquidity/reserves at the log's block.
 //   - without logs (interval): there are no tick changes to apply, so only pool state is refreshed
 //     at latest. This is the path that keeps the tax config and XMA floor fresh.
+//
+// Reorgs deeper than the guard's snapshots fall back to BootstrapPoolState.
 func (t *PoolTracker) GetNewPoolState(ctx context.Context, p entity.Pool,
+	params poolpkg.GetNewPoolStateParams) (entity.Pool, error) {
+	return t.reorgGuard.GetNewPoolState(ctx, p, params, t.applyLogs, t.BootstrapPoolState)
+}
+
+func (t *PoolTracker) applyLogs(ctx context.Context, p entity.Pool,
 	params poolpkg.GetNewPoolStateParams) (entity.Pool, error) {
 	var (
 		blockNumber uint64
@@ -3,6 +3,7 @@ package maverickv2
 import (
 	"fmt"
 	"maps"
+	"math"
 	"math/big"
 	"strings"
 
@@ -149,6 +150,31 @@ func (p *PoolSimulator) CalcAmountIn(param pool.CalcAmountInParams) (*pool.CalcA
 	}, nil
 }
 
+// GetSpotPrice returns the price at the sqrt price of the active tick, net of the swap fee. A swap out of an active tick
+// without reserves of tokenOut starts at another tick, so it is not priced analytically.
+func (p *PoolSimulator) GetSpotPrice(tokenIn, tokenOut string) (float64, error) {
+	tokenInIndex, tokenOutIndex := p.GetTokenIndex(tokenIn), p.GetTokenIndex(tokenOut)
+	if tokenInIndex < 0 || tokenOutIndex < 0 {
+		return 0, fmt.Errorf("tokenInIndex %v or tokenOutIndex %v is not correct", tokenInIndex, tokenOutIndex)
+	}
+
+	tokenAIn := tokenInIndex == 0
+	sqrtPriceD18, tickData := tickSqrtPriceAndLiquidity(p.state, p.state.ActiveTick)
+	if tickData.CurrentLiquidity.IsZero() || lo.Ternary(tokenAIn, tickData.CurrentReserveB,
+		tickData.CurrentReserveA).IsZero() {
+		return 0, pool.ErrSpotPriceUnsupported
+	}
+
+	sqrtPrice := sqrtPriceD18.Float64() / 1e18
+	price := sqrtPrice * sqrtPrice
+	if tokenAIn {
+		price = 1 / price
+	}
+	fee := float64(lo.Ternary(tokenAIn, p.state.FeeAIn, p.state.FeeBIn)) / 1e18
+	// amounts are scaled to 18 decimals in the pool math
+	return price * (1 - fee) * math.Pow10(int(p.decimals[tokenOutIndex])-int(p.decimals[tokenInIndex])), nil
+}
+
 func (p *PoolSimulator) CloneState() pool.IPoolSimulator {
 	cloned := *p
 	cloned.state = p.state.Clone(false)
@@ -317,3 +317,22 @@ func TestDebugSwap_MAV_WETH(t *testing.T) {
 		fmt.Printf("✅ MATCH: Result matches expected value\n")
 	}
 }
+
+func TestPoolSimulator_GetSpotPrice(t *testing.T) {
+	t.Parallel()
+
+	for _, file := range []string{"./data/pool_data.json", "./data/mavweth.json"} {
+		t.Run(file, func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			require.NoError(t, err)
+
+			var poolEntity entity.Pool
+			require.NoError(t, json.Unmarshal(data, &poolEntity))
+
+			poolSim, err := NewPoolSimulator(poolEntity)
+			require.NoError(t, err)
+
+			testutil.TestSpotPrice(t, poolSim, 1)
+		})
+	}
+}
@@ -7,8 +7,10 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/samber/lo"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/events"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/poolfactory"
 )
 
@@ -37,47 +39,14 @@ func NewPoolFactory(config *EventParserConfig) *EventParser {
 	}
 }
 
+// Decode routes logs to the pools of their curves, and SetConfig logs to the curves updated by the PoolTracker.
 func (p *EventParser) Decode(ctx context.Context, logs []types.Log) (map[string][]types.Log, error) {
-	addressLogs := make(map[string][]types.Log)
-	for _, log := range logs {
-		poolAddresses, err := p.DecodePoolAddressesFromFactoryLog(ctx, log)
-		if err != nil {
-			return nil, err
-		}
-
-		for _, poolAddress := range poolAddresses {
-			addressLogs[poolAddress] = append(addressLogs[poolAddress], log)
-		}
-	}
-
-	return addressLogs, nil
+	return curvePools.Decode(ctx, logs, routeLog(p.config.BondingCurve))
 }
 
-func (p *EventParser) DecodePoolAddressesFromFactoryLog(_ context.Context, log types.Log) ([]string, error) {
-	if !strings.EqualFold(log.Address.Hex(), p.config.BondingCurve) {
-		return nil, nil
-	}
-	switch log.Topics[0] {
-	case bondingCurveABI.Events["CurveBuy"].ID,
-		bondingCurveABI.Events["CurveSell"].ID:
-		// topic 1: sender, topic 2: token
-		if len(log.Topics) < 3 {
-			break
-		}
-		token := common.HexToAddress(log.Topics[2].Hex()).Hex()
-		return []string{GetPoolAddress(token)}, nil
-
-	case bondingCurveABI.Events["CurveSync"].ID,
-		bondingCurveABI.Events["CurveTokenLocked"].ID,
-		bondingCurveABI.Events["CurveGraduate"].ID:
-		// topic 1: token
-		if len(log.Topics) < 2 {
-			break
-		}
-		token := common.HexToAddress(log.Topics[1].Hex()).Hex()
-		return []string{GetPoolAddress(token)}, nil
-	}
-	return nil, nil
+func (p *EventParser) DecodePoolAddressesFromFactoryLog(ctx context.Context, log types.Log) ([]string, error) {
+	addresses, err := routeLog(p.config.BondingCurve)(ctx, log)
+	return lo.Without(addresses, events.AllPools), err
 }
 
 func (ep *EventParser) DecodePoolCreated(event types.Log) (*entity.Pool, error) {
@@ -0,0 +1,93 @@
+package nadfun
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/events"
+)
+
+type curveSync struct {
+	RealMonReserve      *big.Int
+	RealTokenReserve    *big.Int
+	VirtualMonReserve   *big.Int
+	VirtualTokenReserve *big.Int
+}
+
+// eventHandlers apply the bonding curve events. Trades are followed by a CurveSync carrying the new reserves, so
+// CurveBuy and CurveSell change nothing by themselves. SetConfig may change the protocol fee of every curve, which
+// refreshes them.
+func eventHandlers() []events.Handler[Extra] {
+	return []events.Handler[Extra]{
+		events.On(bondingCurveABI.Events["CurveBuy"], ignoreEvent),
+		events.On(bondingCurveABI.Events["CurveSell"], ignoreEvent),
+		events.On(bondingCurveABI.Events["CurveSync"],
+			func(p *entity.Pool, extra *Extra, e *curveSync, _ types.Log) error {
+				extra.VirtualNative = uint256.MustFromBig(e.VirtualMonReserve)
+				extra.VirtualToken = uint256.MustFromBig(e.VirtualTokenReserve)
+				if !extra.IsLocked && !extra.IsGraduated {
+					p.Reserves = entity.PoolReserves{e.RealMonReserve.String(), e.RealTokenReserve.String()}
+				}
+				return nil
+			}),
+		events.On(bondingCurveABI.Events["CurveTokenLocked"],
+			func(p *entity.Pool, extra *Extra, _ *struct{}, _ types.Log) error {
+				extra.IsLocked = true
+				p.Reserves = entity.PoolReserves{"0", "0"}
+				return nil
+			}),
+		events.On(bondingCurveABI.Events["CurveGraduate"],
+			func(p *entity.Pool, extra *Extra, _ *struct{}, _ types.Log) error {
+				extra.IsGraduated = true
+				p.Reserves = entity.PoolReserves{"0", "0"}
+				return nil
+			}),
+		events.On(bondingCurveABI.Events["SetConfig"],
+			func(*entity.Pool, *Extra, *struct{}, types.Log) error {
+				return events.ErrRefresh
+			}),
+	}
+}
+
+func ignoreEvent(*entity.Pool, *Extra, *struct{}, types.Log) error {
+	return nil
+}
+
+// routeLog routes the logs of the bonding curve to the pool of the token they carry.
+func routeLog(bondingCurve string) events.RouteFunc {
+	return func(_ context.Context, log types.Log) ([]string, error) {
+		if len(log.Topics) == 0 || !strings.EqualFold(log.Address.Hex(), bondingCurve) {
+			return nil, nil
+		}
+		switch log.Topics[0] {
+		case bondingCurveABI.Events["CurveBuy"].ID,
+			bondingCurveABI.Events["CurveSell"].ID:
+			// topic 1: sender, topic 2: token
+			if len(log.Topics) < 3 {
+				break
+			}
+			token := common.HexToAddress(log.Topics[2].Hex()).Hex()
+			return []string{GetPoolAddress(token)}, nil
+
+		case bondingCurveABI.Events["CurveSync"].ID,
+			bondingCurveABI.Events["CurveTokenLocked"].ID,
+			bondingCurveABI.Events["CurveGraduate"].ID:
+			// topic 1: token
+			if len(log.Topics) < 2 {
+				break
+			}
+			token := common.HexToAddress(log.Topics[1].Hex()).Hex()
+			return []string{GetPoolAddress(token)}, nil
+
+		case bondingCurveABI.Events["SetConfig"].ID:
+			return []string{events.AllPools}, nil
+		}
+		return nil, nil
+	}
+}
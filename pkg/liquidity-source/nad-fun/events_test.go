@@ -0,0 +1,120 @@
+package nadfun
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goccy/go-json"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+func TestPoolTracker_Events(t *testing.T) {
+	t.Parallel()
+
+	bondingCurve := common.HexToAddress("0xa7283d07812a02afb7c09b60f8896bcea3f90ace")
+	token := common.HexToAddress("0x47c6ac22d1fbb5747b711b7d6602090c8dd37777")
+	tracker, err := NewPoolTracker(&Config{BondingCurveAddress: bondingCurve.Hex()}, nil)
+	require.NoError(t, err)
+
+	data, err := bondingCurveABI.Events["CurveSync"].Inputs.NonIndexed().Pack(big.NewInt(10), big.NewInt(20),
+		big.NewInt(30), big.NewInt(40))
+	require.NoError(t, err)
+	logs := []types.Log{{
+		Address:     bondingCurve,
+		Topics:      []common.Hash{bondingCurveABI.Events["CurveBuy"].ID, {}, common.BytesToHash(token[:])},
+		Data:        make([]byte, 64),
+		BlockNumber: 101,
+	}, {
+		Address:     bondingCurve,
+		Topics:      []common.Hash{bondingCurveABI.Events["CurveSync"].ID, common.BytesToHash(token[:])},
+		Data:        data,
+		BlockNumber: 101,
+		Index:       1,
+	}}
+
+	addressLogs, err := tracker.Decode(context.Background(), logs)
+	require.NoError(t, err)
+	poolAddress := GetPoolAddress(token.Hex())
+	assert.Equal(t, map[string][]types.Log{poolAddress: logs}, addressLogs)
+
+	p, err := tracker.GetNewPoolState(context.Background(), entity.Pool{
+		Address:     poolAddress,
+		Reserves:    entity.PoolReserves{"1", "2"},
+		Extra:       `{"virtualNative":"3","virtualToken":"4","k":"12"}`,
+		BlockNumber: 100,
+	}, pool.GetNewPoolStateParams{Logs: addressLogs[poolAddress]})
+	require.NoError(t, err)
+	assert.Equal(t, entity.PoolReserves{"10", "20"}, p.Reserves)
+	assert.Equal(t, uint64(101), p.BlockNumber)
+	var extra Extra
+	require.NoError(t, json.Unmarshal([]byte(p.Extra), &extra))
+	assert.Equal(t, uint256.NewInt(30), extra.VirtualNative)
+	assert.Equal(t, uint256.NewInt(40), extra.VirtualToken)
+	assert.Equal(t, uint256.NewInt(12), extra.K)
+}
+
+func TestPoolTracker_SetConfig(t *testing.T) {
+	t.Parallel()
+
+	bondingCurve := common.HexToAddress("0xa7283d07812a02afb7c09b60f8896bcea3f90ace")
+	tracker, err := NewPoolTracker(&Config{BondingCurveAddress: bondingCurve.Hex()}, nil)
+	require.NoError(t, err)
+	poolAddress := GetPoolAddress("0x47c6ac22d1fbb5747b711b7d6602090c8dd37777")
+	// graduated curves are left as they are by refreshes, so no RPC is made
+	graduated := entity.Pool{Address: poolAddress, Extra: `{"isGraduated":true}`, BlockNumber: 100}
+	_, err = tracker.GetNewPoolState(context.Background(), graduated, pool.GetNewPoolStateParams{})
+	require.NoError(t, err)
+
+	// the protocol fee of every curve may change, so SetConfig goes to all pools tracked and refreshes them
+	type (
+		config struct {
+			VirtualMonReserve, VirtualTokenReserve, TargetTokenAmount *big.Int
+		}
+		feeConfig struct {
+			DeployFeeAmount, GraduateFeeAmount, ProtocolFee *big.Int
+		}
+		antiSnipingConfig struct {
+			MaxPenaltyBlocks            *big.Int
+			PenaltyBlocks, PenaltyRates []*big.Int
+		}
+	)
+	data, err := bondingCurveABI.Events["SetConfig"].Inputs.Pack(struct {
+		Config            config
+		FeeConfig         feeConfig
+		AntiSnipingConfig antiSnipingConfig
+	}{
+		config{big.NewInt(1), big.NewInt(2), big.NewInt(3)},
+		feeConfig{big.NewInt(4), big.NewInt(5), big.NewInt(10000)},
+		antiSnipingConfig{big.NewInt(6), []*big.Int{}, []*big.Int{}},
+	})
+	require.NoError(t, err)
+	logs := []types.Log{{
+		Address:     bondingCurve,
+		Topics:      []common.Hash{bondingCurveABI.Events["SetConfig"].ID},
+		Data:        data,
+		BlockNumber: 101,
+	}}
+	addressLogs, err := tracker.Decode(context.Background(), logs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]types.Log{poolAddress: logs}, addressLogs)
+	// the event parser routes it to the same pools
+	parser := NewPoolFactory(&EventParserConfig{BondingCurve: bondingCurve.Hex()})
+	parsedLogs, err := parser.Decode(context.Background(), logs)
+	require.NoError(t, err)
+	assert.Equal(t, addressLogs, parsedLogs)
+
+	p, err := tracker.GetNewPoolState(context.Background(), graduated,
+		pool.GetNewPoolStateParams{Logs: addressLogs[poolAddress]})
+	require.NoError(t, err)
+	var extra Extra
+	require.NoError(t, json.Unmarshal([]byte(p.Extra), &extra))
+	assert.Equal(t, uint64(100), extra.Block, "refreshed rather than applied")
+}
@@ -7,7 +7,7 @@ import (
 
 	"github.com/KyberNetwork/ethrpc"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
-	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/events"
 	pooltrack "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/tracker"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/goccy/go-json"
@@ -16,23 +16,27 @@ import (
 
 var _ = pooltrack.RegisterFactoryCE(DexType, NewPoolTracker)
 
+// curvePools are the curves the PoolTracker updates, which the EventParser routes SetConfig logs to as well.
+var curvePools = events.NewPools(events.DefaultMaxPools)
+
 type PoolTracker struct {
+	*events.Tracker[Extra, *Extra]
 	config       *Config
 	ethrpcClient *ethrpc.Client
 }
 
 func NewPoolTracker(config *Config, ethrpcClient *ethrpc.Client) (*PoolTracker, error) {
-	return &PoolTracker{
+	t := &PoolTracker{
 		config:       config,
 		ethrpcClient: ethrpcClient,
-	}, nil
+	}
+	t.Tracker = events.NewTracker[Extra](DexType, t.refresh, eventHandlers()...).
+		WithRoute(routeLog(config.BondingCurveAddress)).WithPools(curvePools)
+	return t, nil
 }
 
-func (t *PoolTracker) GetNewPoolState(
-	ctx context.Context,
-	p entity.Pool,
-	_ pool.GetNewPoolStateParams,
-) (entity.Pool, error) {
+// refresh fetches the state of the curve of p over RPC.
+func (t *PoolTracker) refresh(ctx context.Context, p entity.Pool) (entity.Pool, error) {
 	var extra Extra
 	if err := json.Unmarshal([]byte(p.Extra), &extra); err == nil {
 		if extra.IsLocked || extra.IsGraduated {
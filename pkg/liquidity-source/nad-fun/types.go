@@ -2,9 +2,13 @@ package nadfun
 
 import (
 	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/events"
 )
 
 type Extra struct {
+	events.Cursor `json:"cursor"`
+
 	IsLocked    bool `json:"isLocked"`    // Trading locked when target reached
 	IsGraduated bool `json:"isGraduated"` // Token listed on Uniswap
 
@@ -1,15 +1,20 @@
 package v3
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/reorg"
+)
 
 type Config struct {
 	DexID              string
-	SubgraphAPI        string      `json:"subgraphAPI,omitempty"`
-	SubgraphHeaders    http.Header `json:"subgraphHeaders,omitempty"`
-	AllowSubgraphError bool        `json:"allowSubgraphError,omitempty"`
-	TickLensAddress    string      `json:"tickLensAddress,omitempty"`
-	AlwaysUseTickLens  bool        `json:"alwaysUseTickLens,omitempty"` // instead of fetching from subgraph
-	ExecutorAddress    string      `json:"executorAddress,omitempty"`
+	SubgraphAPI        string       `json:"subgraphAPI,omitempty"`
+	SubgraphHeaders    http.Header  `json:"subgraphHeaders,omitempty"`
+	AllowSubgraphError bool         `json:"allowSubgraphError,omitempty"`
+	TickLensAddress    string       `json:"tickLensAddress,omitempty"`
+	AlwaysUseTickLens  bool         `json:"alwaysUseTickLens,omitempty"` // instead of fetching from subgraph
+	ExecutorAddress    string       `json:"executorAddress,omitempty"`
+	Reorg              reorg.Config `json:"reorg"`
 }
 
 func (c *Config) IsAllowSubgraphError() bool {
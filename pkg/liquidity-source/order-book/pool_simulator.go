@@ -1,29 +1,35 @@
 package orderbook
 
 import (
+	"errors"
 	"math"
 	"math/big"
 	"strings"
 	"time"
 
 	"github.com/goccy/go-json"
+	"github.com/holiman/uint256"
 	"github.com/rs/zerolog/log"
 	"github.com/samber/lo"
-	"golang.org/x/exp/slices"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/swaplimit"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/clob"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
 )
 
 type PoolSimulator struct {
 	pool.Pool
-	Gas         Gas
-	levelsFroms [2][]Level
-	tokens      [2]*entity.PoolToken
-	minTrades   [2]float64
-	fee         float64
+	Gas Gas
+	// maker of the pool, sharing the exchange with other makers
+	maker string
+	// books[i] are the levels taking tokens[i], in wei
+	books     [2]clob.Book
+	tokens    [2]*entity.PoolToken
+	minTrades [2]*uint256.Int
 }
 
 var _ = pool.RegisterFactory(DexType, NewPoolSimulator)
@@ -53,11 +59,21 @@ func NewPoolSimulatorWith(entityPool entity.Pool, maxAge time.Duration) (*PoolSi
 		return nil, err
 	}
 
-	firstLevelFrom0, firstLevelFrom1 := lo.FirstOrEmpty(extra.LevelsFrom[0]), lo.FirstOrEmpty(extra.LevelsFrom[1])
+	tokens := [2]*entity.PoolToken(entity.ClonePoolTokens(entityPool.Tokens))
+	address := strings.ToLower(entityPool.Address)
+	fee := clob.Fee{Rate: toWei(entityPool.SwapFee, 18), Precision: big256.BONE}
+	var books [2]clob.Book
+	var minTrades [2]*uint256.Int
+	for i, levels := range extra.LevelsFrom {
+		books[i] = newBook(levels, tokens[i], tokens[1-i], fee)
+		minTrade := lo.FirstOrEmpty(levels)
+		minTrades[i] = toWei(minTrade.Size(), tokens[i].Decimals)
+	}
+
 	return &PoolSimulator{
 		Pool: pool.Pool{
 			Info: pool.PoolInfo{
-				Address:  strings.ToLower(entityPool.Address),
+				Address:  address,
 				Exchange: entityPool.Exchange,
 				Type:     entityPool.Type,
 				Tokens: lo.Map(entityPool.Tokens,
@@ -67,14 +83,59 @@ func NewPoolSimulatorWith(entityPool entity.Pool, maxAge time.Duration) (*PoolSi
 				BlockNumber: entityPool.BlockNumber,
 			},
 		},
-		Gas:         lo.ValueOr(gasByDex, entityPool.Exchange, defaultGas),
-		levelsFroms: extra.LevelsFrom,
-		tokens:      [2]*entity.PoolToken(entity.ClonePoolTokens(entityPool.Tokens)),
-		minTrades:   [2]float64{firstLevelFrom0.Size(), firstLevelFrom1.Size()},
-		fee:         entityPool.SwapFee,
+		Gas:       lo.ValueOr(gasByDex, entityPool.Exchange, defaultGas),
+		maker:     makerOf(address, tokens),
+		books:     books,
+		tokens:    tokens,
+		minTrades: minTrades,
 	}, nil
 }
 
+// makerOf returns the maker of a pool addressed <maker>_<token0>_<token1>.
+func makerOf(address string, tokens [2]*entity.PoolToken) string {
+	return strings.TrimSuffix(address, "_"+tokens[0].Address+"_"+tokens[1].Address)
+}
+
+// Books keep their amounts out in 10^outScaleDecimals units per wei, so that filling several levels rounds down once
+// rather than losing the fractional wei of each level.
+const outScaleDecimals = 9
+
+var outScale = big256.TenPow(outScaleDecimals)
+
+// newBook converts levels, sized in tokenIn units, to a book in wei of tokenIn and outScale units of tokenOut, fee
+// taken from the amount out.
+func newBook(levels []Level, tokenIn, tokenOut *entity.PoolToken, fee clob.Fee) clob.Book {
+	if len(levels) == 0 {
+		return clob.Book{Fee: fee}
+	}
+	book := clob.Book{Levels: make([]clob.Level, len(levels)), Fee: fee}
+	for i, level := range levels {
+		book.Levels[i] = clob.Level{
+			In:  toWei(level.Size(), tokenIn.Decimals),
+			Out: toWeiDown(level.Size()*level.Price(), tokenOut.Decimals+outScaleDecimals),
+		}
+	}
+	return book
+}
+
+// toWei converts amount in token units to wei, rounding to the nearest to undo float errors, e.g. 2996.9999999999995.
+func toWei(amount float64, decimals uint8) *uint256.Int {
+	return floatToInt(math.Round(amount * math.Pow10(int(decimals))))
+}
+
+// toWeiDown converts amount in token units to wei, rounding down.
+func toWeiDown(amount float64, decimals uint8) *uint256.Int {
+	return floatToInt(math.Floor(amount * math.Pow10(int(decimals))))
+}
+
+func floatToInt(amount float64) *uint256.Int {
+	wei, _ := big.NewFloat(amount).Int(nil)
+	if wei.Sign() <= 0 {
+		return new(uint256.Int)
+	}
+	return big256.FromBig(wei)
+}
+
 func (p *PoolSimulator) CalcAmountOut(params pool.CalcAmountOutParams) (*pool.CalcAmountOutResult, error) {
 	tokenIn, tokenOut, amtIn := params.TokenAmountIn.Token, params.TokenOut, params.TokenAmountIn.Amount
 	indexIn, indexOut := p.GetTokenIndex(tokenIn), p.GetTokenIndex(tokenOut)
@@ -82,14 +143,14 @@ func (p *PoolSimulator) CalcAmountOut(params pool.CalcAmountOutParams) (*pool.Ca
 		return nil, ErrInvalidToken
 	}
 
-	result, err := p.calcOut(amtIn, p.tokens[indexIn], p.tokens[indexOut], p.levelsFroms[indexIn], p.minTrades[indexIn])
+	result, err := p.calcOut(amtIn, indexIn, indexOut)
 	if err != nil {
 		return nil, err
 	}
 
 	if limit := params.Limit; limit != nil {
-		inventoryLimit := limit.GetLimit(tokenOut)
-		if result.TokenAmountOut.Amount.Cmp(inventoryLimit) > 0 {
+		inventoryLimit := limit.GetLimit(p.limitKey(limit, tokenOut))
+		if inventoryLimit != nil && result.TokenAmountOut.Amount.Cmp(inventoryLimit) > 0 {
 			return nil, ErrSwapLimitExceeded
 		}
 	}
@@ -104,13 +165,13 @@ func (p *PoolSimulator) CalcAmountIn(params pool.CalcAmountInParams) (*pool.Calc
 	}
 
 	if limit := params.Limit; limit != nil {
-		inventoryLimitOut := limit.GetLimit(tokenOut)
-		if amtOut.Cmp(inventoryLimitOut) > 0 {
+		inventoryLimitOut := limit.GetLimit(p.limitKey(limit, tokenOut))
+		if inventoryLimitOut != nil && amtOut.Cmp(inventoryLimitOut) > 0 {
 			return nil, ErrSwapLimitExceeded
 		}
 	}
 
-	return p.calcIn(amtOut, p.tokens[indexIn], p.tokens[indexOut], p.levelsFroms[indexIn], p.minTrades[indexIn])
+	return p.calcIn(amtOut, indexIn, indexOut)
 }
 
 func (p *PoolSimulator) CalculateLimit() map[string]*big.Int {
@@ -122,12 +183,22 @@ func (p *PoolSimulator) CalculateLimit() map[string]*big.Int {
 	return inventory
 }
 
+// GetMaker returns the maker of the pool.
+func (p *PoolSimulator) GetMaker() string {
+	return p.maker
+}
+
+// limitKey returns the key of the inventory of token: of the maker in a swaplimit.MakerInventory, or of the exchange.
+func (p *PoolSimulator) limitKey(limit pool.SwapLimit, token string) string {
+	if _, ok := limit.(*swaplimit.MakerInventory); ok {
+		return swaplimit.MakerKey(p.maker, token)
+	}
+	return token
+}
+
+// CloneState clones the pool state. Books copy their levels on write, so sharing them is safe.
 func (p *PoolSimulator) CloneState() pool.IPoolSimulator {
 	cloned := *p
-	cloned.levelsFroms = [2][]Level{
-		slices.Clone(p.levelsFroms[0]),
-		slices.Clone(p.levelsFroms[1]),
-	}
 	return &cloned
 }
 
@@ -136,12 +207,14 @@ func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
 	tokenIn, tokenOut := params.TokenAmountIn.Token, params.TokenAmountOut.Token
 	indexIn := p.GetTokenIndex(tokenIn)
 
-	amtInF, _ := amtIn.Float64()
-	amtInAfterDecimals := amtInF / math.Pow10(int(p.tokens[indexIn].Decimals))
-	p.levelsFroms[indexIn] = updateLevelsState(amtInAfterDecimals, p.levelsFroms[indexIn])
+	book := &p.books[indexIn]
+	if fill, err := book.FillExactIn(big256.FromBig(amtIn)); err == nil {
+		book.Consume(fill)
+	}
 
 	if limit := params.SwapLimit; limit != nil {
-		_, _, err := limit.UpdateLimit(tokenOut, tokenIn, params.TokenAmountOut.Amount, amtIn)
+		_, _, err := limit.UpdateLimit(p.limitKey(limit, tokenOut), p.limitKey(limit, tokenIn),
+			params.TokenAmountOut.Amount, amtIn)
 		if err != nil {
 			log.Err(err).Msg("orderbook.UpdateBalance failed")
 		}
@@ -152,108 +225,53 @@ func (p *PoolSimulator) GetMetaInfo(_, _ string) any {
 	return pool.MetaInfo{BlockNumber: p.Info.BlockNumber}
 }
 
-func (p *PoolSimulator) calcOut(amountIn *big.Int, tokenIn, tokenOut *entity.PoolToken, priceLevel []Level,
-	minTrade float64) (*pool.CalcAmountOutResult, error) {
-	amtInF, _ := amountIn.Float64()
-	amtInAfterDecimals := amtInF / math.Pow10(int(tokenIn.Decimals))
-	amtOutAfterDecimals, levels, err := getAmountOut(amtInAfterDecimals, priceLevel, minTrade)
-	if err != nil {
-		return nil, err
+func (p *PoolSimulator) calcOut(amountIn *big.Int, indexIn, indexOut int) (*pool.CalcAmountOutResult, error) {
+	book := &p.books[indexIn]
+	if len(book.Levels) == 0 {
+		return nil, ErrEmptyLevels
+	}
+	amtIn := big256.FromBig(amountIn)
+	if amtIn.Lt(p.minTrades[indexIn]) {
+		return nil, ErrInvalidAmountIn
 	}
 
-	amtOutF := amtOutAfterDecimals * math.Pow10(int(tokenOut.Decimals))
-	amtOutF *= 1 - p.fee
-	amountOut, _ := big.NewFloat(amtOutF).Int(nil)
-
-	return &pool.CalcAmountOutResult{
-		TokenAmountOut: &pool.TokenAmount{Token: tokenOut.Address, Amount: amountOut},
-		Fee:            &pool.TokenAmount{Token: tokenIn.Address, Amount: bignumber.ZeroBI},
-		Gas:            p.Gas.Base + levels*p.Gas.Level,
-	}, nil
-}
-
-func (p *PoolSimulator) calcIn(amountOut *big.Int, tokenIn, tokenOut *entity.PoolToken, priceLevel []Level,
-	minTrade float64) (*pool.CalcAmountInResult, error) {
-	amtOutF, _ := amountOut.Float64()
-	amtOutF /= 1 - p.fee
-	amtOutAfterDecimals := amtOutF / math.Pow10(int(tokenOut.Decimals))
-	amtInAfterDecimals, levels, err := getAmountIn(amtOutAfterDecimals, priceLevel, minTrade)
+	fill, err := book.FillExactIn(amtIn)
 	if err != nil {
 		return nil, err
+	} else if !fill.RemainingIn.IsZero() {
+		return nil, ErrInsufficientLiquidity
 	}
 
-	amtInF := amtInAfterDecimals * math.Pow10(int(tokenIn.Decimals))
-	amountIn, _ := big.NewFloat(amtInF).Int(nil)
-
-	return &pool.CalcAmountInResult{
-		TokenAmountIn: &pool.TokenAmount{Token: tokenIn.Address, Amount: amountIn},
-		Fee:           &pool.TokenAmount{Token: tokenIn.Address, Amount: bignumber.ZeroBI},
-		Gas:           p.Gas.Base + levels*p.Gas.Level,
+	return &pool.CalcAmountOutResult{
+		TokenAmountOut: &pool.TokenAmount{
+			Token:  p.tokens[indexOut].Address,
+			Amount: new(uint256.Int).Div(fill.AmountOut, outScale).ToBig(),
+		},
+		Fee: &pool.TokenAmount{Token: p.tokens[indexIn].Address, Amount: bignumber.ZeroBI},
+		Gas: p.Gas.Base + int64(fill.Walked)*p.Gas.Level,
 	}, nil
 }
 
-func getAmountOut(amtIn float64, priceLevels []Level, minTrade float64) (amountOut float64, levels int64, err error) {
-	if len(priceLevels) == 0 {
-		return 0, 0, ErrEmptyLevels
-	} else if amtIn < minTrade {
-		return 0, 0, ErrInvalidAmountIn
-	} else if amtIn > lo.SumBy(priceLevels, func(pl Level) float64 { return pl.Size() }) {
-		return 0, 0, ErrInsufficientLiquidity
+func (p *PoolSimulator) calcIn(amountOut *big.Int, indexIn, indexOut int) (*pool.CalcAmountInResult, error) {
+	book := &p.books[indexIn]
+	if len(book.Levels) == 0 {
+		return nil, ErrEmptyLevels
 	}
-
-	for _, currentLevel := range priceLevels {
-		size := currentLevel.Size()
-		if size == 0 {
-			continue
-		}
-		levels++
-		currentLevelAmount := min(size, amtIn)
-		amountOut += currentLevelAmount * currentLevel.Price()
-		if amtIn -= currentLevelAmount; amtIn <= 0 {
-			break
-		}
+	amtOut := new(uint256.Int).Mul(big256.FromBig(amountOut), outScale)
+	if minOut := book.Levels[0].Out; amtOut.Lt(minOut) {
+		return nil, ErrInvalidAmountIn
 	}
 
-	return amountOut, levels, nil
-}
-
-func getAmountIn(amtOut float64, priceLevels []Level, minTrade float64) (amountIn float64, levels int64, err error) {
-	if len(priceLevels) == 0 {
-		return 0, 0, ErrEmptyLevels
-	} else if amtOut < minTrade*priceLevels[0].Price() {
-		return 0, 0, ErrInvalidAmountIn
-	} else if amtOut > lo.SumBy(priceLevels, func(pl Level) float64 { return pl.Size() * pl.Price() }) {
-		return 0, 0, ErrInsufficientLiquidity
-	}
-
-	for _, currentLevel := range priceLevels {
-		size := currentLevel.Size()
-		if size == 0 {
-			continue
-		}
-		levels++
-		currentLevelAmount := min(size*currentLevel.Price(), amtOut)
-		amountIn += currentLevelAmount / currentLevel.Price()
-		if amtOut -= currentLevelAmount; amtOut <= 0 {
-			break
-		}
-	}
-
-	return amountIn, levels, nil
-}
-
-// updateLevelsState MAY MUTATE priceLevels
-func updateLevelsState(amountIn float64, priceLevels []Level) []Level {
-	for i, priceLevel := range priceLevels {
-		if levelAmount := priceLevel.Size(); levelAmount > amountIn {
-			priceLevels[i].SetSize(priceLevels[i].Size() - amountIn)
-			return priceLevels[i:]
-		} else if levelAmount == amountIn {
-			return priceLevels[i+1:]
-		} else {
-			amountIn -= levelAmount
-		}
+	fill, err := book.FillExactOut(amtOut)
+	if errors.Is(err, clob.ErrInsufficientLiquidity) {
+		return nil, ErrInsufficientLiquidity
+	} else if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return &pool.CalcAmountInResult{
+		TokenAmountIn: &pool.TokenAmount{Token: p.tokens[indexIn].Address, Amount: fill.AmountIn.ToBig()},
+		Fee:           &pool.TokenAmount{Token: p.tokens[indexIn].Address, Amount: bignumber.ZeroBI},
+		Gas:           p.Gas.Base + int64(fill.Walked)*p.Gas.Level,
+	}, nil
 }
@@ -39,8 +39,8 @@ func TestPoolSimulator_NewPool(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "0xaf88d065e77c8cc2239327c5edb3a432268e5831", poolSimulator.tokens[0].Address)
 	assert.Equal(t, "0xfd086bc7cd5c481dcc9c85ebe478a1c0b69fcbb9", poolSimulator.tokens[1].Address)
-	assert.Nil(t, poolSimulator.levelsFroms[0])
-	assert.NotNil(t, poolSimulator.levelsFroms[1])
+	assert.Nil(t, poolSimulator.books[0].Levels)
+	assert.NotNil(t, poolSimulator.books[1].Levels)
 	assert.Equal(t, []string{"0xaf88d065e77c8cc2239327c5edb3a432268e5831"},
 		poolSimulator.CanSwapTo("0xfd086bc7cd5c481dcc9c85ebe478a1c0b69fcbb9"))
 	assert.Equal(t, []string{"0xaf88d065e77c8cc2239327c5edb3a432268e5831"},
@@ -317,3 +317,46 @@ var (
 func TestCalcAmountIn_WithFee(t *testing.T) {
 	testutil.TestCalcAmountIn(t, poolSim2)
 }
+
+func TestPoolSimulator_MakerInventory(t *testing.T) {
+	usdc, usdt := entityPool.Tokens[0].Address, entityPool.Tokens[1].Address
+	poolA := entityPool
+	poolA.Reserves = []string{"1000000000", "0"}
+	poolB := entityPool
+	poolB.Address = strings.Replace(entityPool.Address, "mx_trading", "other_maker", 1)
+	simA := lo.Must(NewPoolSimulator(pool.FactoryParams{EntityPool: poolA}))
+	simB := lo.Must(NewPoolSimulator(pool.FactoryParams{EntityPool: poolB}))
+	assert.Equal(t, "mx_trading", simA.GetMaker())
+	assert.Equal(t, "other_maker", simB.GetMaker())
+
+	limit := swaplimit.NewMakerInventoryFromPools("pmm-1", []swaplimit.IMakerPool{simA, simB})
+	assert.Equal(t, "1000000000", limit.GetLimit(swaplimit.MakerKey("mx_trading", usdc)).String())
+	assert.Equal(t, "364190205979", limit.GetLimit(swaplimit.MakerKey("other_maker", usdc)).String())
+
+	params := pool.CalcAmountOutParams{
+		TokenAmountIn: pool.TokenAmount{Token: usdt, Amount: big.NewInt(600_000000)},
+		TokenOut:      usdc,
+		Limit:         limit,
+	}
+	res, err := simA.CalcAmountOut(params)
+	require.NoError(t, err)
+	simA.UpdateBalance(pool.UpdateBalanceParams{
+		TokenAmountIn:  params.TokenAmountIn,
+		TokenAmountOut: *res.TokenAmountOut,
+		SwapLimit:      limit,
+	})
+
+	// a second leg through the same maker cannot spend its balance again, nor the balance of the other maker
+	_, err = simA.CalcAmountOut(params)
+	assert.ErrorIs(t, err, ErrSwapLimitExceeded)
+	_, err = simB.CalcAmountOut(params)
+	assert.NoError(t, err)
+	assert.Equal(t, "364190205979", limit.GetLimit(swaplimit.MakerKey("other_maker", usdc)).String())
+
+	// makers the inventory has no balance of are not limited
+	poolC := entityPool
+	poolC.Address = strings.Replace(entityPool.Address, "mx_trading", "unknown_maker", 1)
+	simC := lo.Must(NewPoolSimulator(pool.FactoryParams{EntityPool: poolC}))
+	_, err = simC.CalcAmountOut(params)
+	assert.NoError(t, err)
+}
@@ -1,16 +1,19 @@
 package bin
 
+import "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/reorg"
+
 type Config struct {
-	ChainID                int    `json:"chainID"`
-	DexID                  string `json:"dexID"`
-	SubgraphAPI            string `json:"subgraphAPI"`
-	UniversalRouterAddress string `json:"universalRouterAddress"`
-	Permit2Address         string `json:"permit2Address"`
-	Multicall3Address      string `json:"multicall3Address"`
-	VaultAddress           string `json:"vaultAddress"`
-	BinPoolManagerAddress  string `json:"binPoolManagerAddress"`
-	NewPoolLimit           int    `json:"newPoolLimit"`
-	AllowSubgraphError     bool   `json:"allowSubgraphError"`
+	ChainID                int          `json:"chainID"`
+	DexID                  string       `json:"dexID"`
+	SubgraphAPI            string       `json:"subgraphAPI"`
+	UniversalRouterAddress string       `json:"universalRouterAddress"`
+	Permit2Address         string       `json:"permit2Address"`
+	Multicall3Address      string       `json:"multicall3Address"`
+	VaultAddress           string       `json:"vaultAddress"`
+	BinPoolManagerAddress  string       `json:"binPoolManagerAddress"`
+	NewPoolLimit           int          `json:"newPoolLimit"`
+	AllowSubgraphError     bool         `json:"allowSubgraphError"`
+	Reorg                  reorg.Config `json:"reorg"`
 }
 
 func (c *Config) IsAllowSubgraphError() bool {
@@ -1,5 +1,7 @@
 package cl
 
+import "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/reorg"
+
 type Config struct {
 	ChainID                int    `json:"chainID"`
 	DexID                  string `json:"dexID"`
@@ -14,7 +16,8 @@ type Config struct {
 
 	FetchTickFromRPC bool // instead of fetching from subgraph
 
-	StableHookFactories []string `json:"stableHookFactories,omitempty"`
+	StableHookFactories []string     `json:"stableHookFactories,omitempty"`
+	Reorg               reorg.Config `json:"reorg"`
 }
 
 func (c *Config) IsAllowSubgraphError() bool {
@@ -306,6 +306,11 @@ func (p *PoolSimulator) GetExchange() string {
 	return p.hook.GetExchange()
 }
 
+// GetSpotPrice defers to the numeric estimate, as hooks may override the fee or the amounts of a swap.
+func (p *PoolSimulator) GetSpotPrice(_, _ string) (float64, error) {
+	return 0, pool.ErrSpotPriceUnsupported
+}
+
 func (p *PoolSimulator) CloneState() pool.IPoolSimulator {
 	cloned := *p
 	cloned.PoolSimulator = p.PoolSimulator.CloneState().(*uniswapv3.PoolSimulator)
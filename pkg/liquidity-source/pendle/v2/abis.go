@@ -0,0 +1,34 @@
+package pendlev2
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+var (
+	pendleMarketABI      abi.ABI
+	standardizedYieldABI abi.ABI
+	yieldTokenABI        abi.ABI
+	marketFactoryABI     abi.ABI
+)
+
+func init() {
+	builder := []struct {
+		ABI  *abi.ABI
+		data []byte
+	}{
+		{&pendleMarketABI, pendleMarketABIJson},
+		{&standardizedYieldABI, standardizedYieldABIJson},
+		{&yieldTokenABI, yieldTokenABIJson},
+		{&marketFactoryABI, marketFactoryABIJson},
+	}
+
+	for _, b := range builder {
+		var err error
+		*b.ABI, err = abi.JSON(bytes.NewReader(b.data))
+		if err != nil {
+			panic(err)
+		}
+	}
+}
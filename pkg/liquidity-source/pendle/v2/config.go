@@ -0,0 +1,13 @@
+package pendlev2
+
+type Config struct {
+	DexID string `json:"dexID"`
+	// Router is the router the market fee config is read for, as fees may be overridden per router.
+	Router string `json:"router"`
+	// MarketFactories are the market factories whose CreateNewMarket events list the markets.
+	MarketFactories []string `json:"marketFactories"`
+	// StartBlock is the block the scan of CreateNewMarket events starts at.
+	StartBlock uint64 `json:"startBlock"`
+	// MaxBlockRangePerScan caps the block span of each scan of CreateNewMarket events.
+	MaxBlockRangePerScan uint64 `json:"maxBlockRangePerScan"`
+}
@@ -0,0 +1,46 @@
+package pendlev2
+
+import (
+	"github.com/KyberNetwork/int256"
+)
+
+const (
+	DexType = "pendle-v2"
+
+	marketMethodReadState  = "readState"
+	marketMethodReadTokens = "readTokens"
+	marketMethodExpiry     = "expiry"
+
+	syMethodExchangeRate    = "exchangeRate"
+	syMethodAssetInfo       = "assetInfo"
+	syMethodIsValidTokenIn  = "isValidTokenIn"
+	syMethodIsValidTokenOut = "isValidTokenOut"
+
+	ytMethodPyIndexStored = "pyIndexStored"
+
+	factoryEventCreateNewMarket = "CreateNewMarket"
+
+	// impliedRateTime is the period implied rates are annualized over, as MarketMathCore.IMPLIED_RATE_TIME.
+	impliedRateTime = 365 * 86400
+
+	// approxMaxIterations bounds the binary searches of the PT traded by swaps of an exact SY amount in.
+	approxMaxIterations = 256
+
+	gasMintSy           = 100000
+	gasRedeemSy         = 100000
+	gasSwapExactPtForSy = 160000
+	gasSwapExactSyForPt = 200000
+	gasSwapExactYtForSy = 260000
+	gasSwapExactSyForYt = 280000
+)
+
+var (
+	one = int256.NewInt(1e18)
+
+	// maxMarketProportion is MarketMathCore.MAX_MARKET_PROPORTION, 96%.
+	maxMarketProportion = int256.NewInt(96e16)
+	percentageDecimals  = int256.NewInt(100)
+
+	// approxPrecision stops the binary searches once the PT traded is known within 1 / approxPrecision of itself.
+	approxPrecision = int256.NewInt(1e9)
+)
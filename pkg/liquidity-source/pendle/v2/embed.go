@@ -0,0 +1,15 @@
+package pendlev2
+
+import _ "embed"
+
+//go:embed abis/PendleMarket.json
+var pendleMarketABIJson []byte
+
+//go:embed abis/StandardizedYield.json
+var standardizedYieldABIJson []byte
+
+//go:embed abis/YieldToken.json
+var yieldTokenABIJson []byte
+
+//go:embed abis/MarketFactory.json
+var marketFactoryABIJson []byte
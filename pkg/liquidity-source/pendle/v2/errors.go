@@ -0,0 +1,20 @@
+package pendlev2
+
+import "errors"
+
+var (
+	ErrInvalidToken                    = errors.New("pendle-v2: invalid token")
+	ErrInvalidAmountIn                 = errors.New("pendle-v2: invalid amount in")
+	ErrInsufficientAmountOut           = errors.New("pendle-v2: insufficient amount out")
+	ErrUnsupportedSwap                 = errors.New("pendle-v2: unsupported swap")
+	ErrMarketExpired                   = errors.New("pendle-v2: market expired")
+	ErrMarketZeroTotalPtOrAsset        = errors.New("pendle-v2: market zero total pt or total asset")
+	ErrMarketInsufficientPt            = errors.New("pendle-v2: market insufficient pt for trade")
+	ErrMarketExchangeRateBelowOne      = errors.New("pendle-v2: market exchange rate below one")
+	ErrMarketProportionTooHigh         = errors.New("pendle-v2: market proportion too high")
+	ErrMarketProportionMustNotEqualOne = errors.New("pendle-v2: market proportion must not equal one")
+	ErrMarketRateScalarBelowZero       = errors.New("pendle-v2: market rate scalar below zero")
+	ErrMarketZeroLnImpliedRate         = errors.New("pendle-v2: market zero ln implied rate")
+	ErrNegativeAmount                  = errors.New("pendle-v2: negative amount")
+	ErrLnOutOfBounds                   = errors.New("pendle-v2: ln out of bounds")
+)
@@ -0,0 +1,265 @@
+package pendlev2
+
+import (
+	"github.com/KyberNetwork/int256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v3/math"
+)
+
+// The market math follows MarketMathCore.
+// https://github.com/pendle-finance/pendle-core-v2-public/blob/main/contracts/core/Market/MarketMathCore.sol
+
+var iImpliedRateTime = int256.NewInt(impliedRateTime)
+
+// marketPreCompute is MarketPreCompute, the values of a market shared by the trades at a block time.
+type marketPreCompute struct {
+	timeToExpiry *int256.Int
+	rateScalar   *int256.Int
+	totalAsset   *int256.Int
+	rateAnchor   *int256.Int
+	feeRate      *int256.Int
+}
+
+// tradeResult is the outcome of a trade of netPtToAccount PT with the market, with the market state after it.
+type tradeResult struct {
+	netPtToAccount *int256.Int
+	netSyToAccount *int256.Int
+	netSyFee       *int256.Int
+	netSyToReserve *int256.Int
+
+	totalPt           *int256.Int
+	totalSy           *int256.Int
+	lastLnImpliedRate *int256.Int
+}
+
+// preCompute returns the values of the market shared by the trades at blockTime, as getMarketPreCompute.
+func (s *MarketState) preCompute(index *int256.Int, blockTime uint64) (*marketPreCompute, error) {
+	if blockTime >= s.Expiry {
+		return nil, ErrMarketExpired
+	}
+	timeToExpiry := int256.NewInt(int64(s.Expiry - blockTime))
+
+	rateScalar := new(int256.Int).Mul(s.ScalarRoot, iImpliedRateTime)
+	rateScalar.Quo(rateScalar, timeToExpiry)
+	if rateScalar.Sign() <= 0 {
+		return nil, ErrMarketRateScalarBelowZero
+	}
+
+	totalAsset := syToAsset(index, s.TotalSy)
+	if s.TotalPt.IsZero() || totalAsset.IsZero() {
+		return nil, ErrMarketZeroTotalPtOrAsset
+	}
+
+	rateAnchor, err := getRateAnchor(s.TotalPt, s.LastLnImpliedRate, totalAsset, rateScalar, timeToExpiry)
+	if err != nil {
+		return nil, err
+	}
+
+	feeRate, err := getExchangeRateFromImpliedRate(s.LnFeeRateRoot, timeToExpiry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &marketPreCompute{
+		timeToExpiry: timeToExpiry,
+		rateScalar:   rateScalar,
+		totalAsset:   totalAsset,
+		rateAnchor:   rateAnchor,
+		feeRate:      feeRate,
+	}, nil
+}
+
+// executeTrade trades netPtToAccount PT with the market, as executeTradeCore followed by _setNewMarketStateTrade.
+func (s *MarketState) executeTrade(comp *marketPreCompute, index, netPtToAccount *int256.Int) (*tradeResult, error) {
+	if s.TotalPt.Lte(netPtToAccount) {
+		return nil, ErrMarketInsufficientPt
+	}
+
+	result, err := s.calcTrade(comp, index, netPtToAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.totalPt, err = subNoNeg(s.TotalPt, netPtToAccount); err != nil {
+		return nil, err
+	}
+	if result.totalSy, err = subNoNeg(s.TotalSy,
+		new(int256.Int).Add(result.netSyToAccount, result.netSyToReserve)); err != nil {
+		return nil, err
+	}
+
+	result.lastLnImpliedRate, err = getLnImpliedRate(result.totalPt, syToAsset(index, result.totalSy),
+		comp.rateScalar, comp.rateAnchor, comp.timeToExpiry)
+	if err != nil {
+		return nil, err
+	}
+	if result.lastLnImpliedRate.IsZero() {
+		return nil, ErrMarketZeroLnImpliedRate
+	}
+
+	return result, nil
+}
+
+// calcTrade returns the SY to the account, the fee and the SY to the reserve of a trade of netPtToAccount PT, as
+// calcTrade.
+func (s *MarketState) calcTrade(comp *marketPreCompute, index, netPtToAccount *int256.Int) (*tradeResult, error) {
+	preFeeExchangeRate, err := getExchangeRate(s.TotalPt, comp.totalAsset, comp.rateScalar, comp.rateAnchor,
+		netPtToAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	preFeeAssetToAccount := divDown(netPtToAccount, preFeeExchangeRate)
+	preFeeAssetToAccount.Neg(preFeeAssetToAccount)
+
+	var fee *int256.Int
+	oneSubFeeRate := new(int256.Int).Sub(one, comp.feeRate)
+	if netPtToAccount.Sign() > 0 {
+		if divDown(preFeeExchangeRate, comp.feeRate).Lt(one) {
+			return nil, ErrMarketExchangeRateBelowOne
+		}
+		fee = mulDown(preFeeAssetToAccount, oneSubFeeRate)
+	} else {
+		fee = new(int256.Int).Mul(preFeeAssetToAccount, oneSubFeeRate)
+		fee.Quo(fee, comp.feeRate).Neg(fee)
+	}
+
+	netAssetToReserve := new(int256.Int).Mul(fee, s.ReserveFeePercent)
+	netAssetToReserve.Quo(netAssetToReserve, percentageDecimals)
+	netAssetToAccount := new(int256.Int).Sub(preFeeAssetToAccount, fee)
+
+	var netSyToAccount *int256.Int
+	if netAssetToAccount.Sign() < 0 {
+		netSyToAccount = assetToSyUp(index, netAssetToAccount)
+	} else {
+		netSyToAccount = assetToSy(index, netAssetToAccount)
+	}
+
+	return &tradeResult{
+		netPtToAccount: netPtToAccount,
+		netSyToAccount: netSyToAccount,
+		netSyFee:       assetToSy(index, fee),
+		netSyToReserve: assetToSy(index, netAssetToReserve),
+	}, nil
+}
+
+// getRateAnchor returns the rate anchor keeping the implied rate of the market at lastLnImpliedRate, as
+// _getRateAnchor.
+func getRateAnchor(totalPt, lastLnImpliedRate, totalAsset, rateScalar, timeToExpiry *int256.Int) (*int256.Int,
+	error) {
+	newExchangeRate, err := getExchangeRateFromImpliedRate(lastLnImpliedRate, timeToExpiry)
+	if err != nil {
+		return nil, err
+	}
+	if newExchangeRate.Lt(one) {
+		return nil, ErrMarketExchangeRateBelowOne
+	}
+
+	proportion := divDown(totalPt, new(int256.Int).Add(totalPt, totalAsset))
+	lnProportion, err := logProportion(proportion)
+	if err != nil {
+		return nil, err
+	}
+
+	return newExchangeRate.Sub(newExchangeRate, divDown(lnProportion, rateScalar)), nil
+}
+
+// getLnImpliedRate returns the annualized ln of the exchange rate of the market, as _getLnImpliedRate.
+func getLnImpliedRate(totalPt, totalAsset, rateScalar, rateAnchor, timeToExpiry *int256.Int) (*int256.Int, error) {
+	proportion := divDown(totalPt, new(int256.Int).Add(totalPt, totalAsset))
+	lnProportion, err := logProportion(proportion)
+	if err != nil {
+		return nil, err
+	}
+
+	exchangeRate := divDown(lnProportion, rateScalar)
+	exchangeRate.Add(exchangeRate, rateAnchor)
+
+	lnRate, err := ln(exchangeRate)
+	if err != nil {
+		return nil, err
+	}
+	if lnRate.Sign() < 0 {
+		return nil, ErrNegativeAmount
+	}
+
+	lnRate.Mul(lnRate, iImpliedRateTime)
+	return lnRate.Quo(lnRate, timeToExpiry), nil
+}
+
+// getExchangeRateFromImpliedRate returns e ^ (lnImpliedRate * timeToExpiry / impliedRateTime), as
+// _getExchangeRateFromImpliedRate.
+func getExchangeRateFromImpliedRate(lnImpliedRate, timeToExpiry *int256.Int) (*int256.Int, error) {
+	rt := new(int256.Int).Mul(lnImpliedRate, timeToExpiry)
+	return math.Exp(rt.Quo(rt, iImpliedRateTime))
+}
+
+// getExchangeRate returns the PT per asset of a trade of netPtToAccount PT before fees, as _getExchangeRate.
+func getExchangeRate(totalPt, totalAsset, rateScalar, rateAnchor, netPtToAccount *int256.Int) (*int256.Int, error) {
+	numerator, err := subNoNeg(totalPt, netPtToAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	proportion := divDown(numerator, new(int256.Int).Add(totalPt, totalAsset))
+	if proportion.Gt(maxMarketProportion) {
+		return nil, ErrMarketProportionTooHigh
+	}
+
+	lnProportion, err := logProportion(proportion)
+	if err != nil {
+		return nil, err
+	}
+
+	exchangeRate := divDown(lnProportion, rateScalar)
+	exchangeRate.Add(exchangeRate, rateAnchor)
+	if exchangeRate.Lt(one) {
+		return nil, ErrMarketExchangeRateBelowOne
+	}
+	return exchangeRate, nil
+}
+
+// logProportion returns ln(proportion / (1 - proportion)), as _logProportion.
+func logProportion(proportion *int256.Int) (*int256.Int, error) {
+	if proportion.Eq(one) {
+		return nil, ErrMarketProportionMustNotEqualOne
+	}
+	return ln(divDown(proportion, new(int256.Int).Sub(one, proportion)))
+}
+
+// ln returns the natural logarithm of a, as LogExpMath.ln.
+func ln(a *int256.Int) (*int256.Int, error) {
+	if a.Sign() <= 0 {
+		return nil, ErrLnOutOfBounds
+	}
+
+	if math.ILn36LowerBound.Lt(a) && a.Lt(math.ILn36UpperBound) {
+		result, err := math.Ln36(a)
+		if err != nil {
+			return nil, err
+		}
+		return result.Quo(result, one), nil
+	}
+
+	return math.Ln(new(int256.Int).Set(a))
+}
+
+// mulDown returns a * b / 1e18, as PMath.mulDown.
+func mulDown(a, b *int256.Int) *int256.Int {
+	result := new(int256.Int).Mul(a, b)
+	return result.Quo(result, one)
+}
+
+// divDown returns a * 1e18 / b, as PMath.divDown.
+func divDown(a, b *int256.Int) *int256.Int {
+	result := new(int256.Int).Mul(a, one)
+	return result.Quo(result, b)
+}
+
+// subNoNeg returns a - b, failing if it is negative, as PMath.subNoNeg.
+func subNoNeg(a, b *int256.Int) (*int256.Int, error) {
+	if a.Lt(b) {
+		return nil, ErrNegativeAmount
+	}
+	return new(int256.Int).Sub(a, b), nil
+}
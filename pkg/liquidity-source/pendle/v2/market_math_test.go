@@ -0,0 +1,119 @@
+package pendlev2
+
+import (
+	"math"
+	"testing"
+
+	"github.com/KyberNetwork/int256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// toFloat returns a 1e18 fixed point number as a float.
+func toFloat(a *int256.Int) float64 {
+	return float64(a.Int64()) / 1e18
+}
+
+func TestLn(t *testing.T) {
+	t.Parallel()
+
+	for _, x := range []int64{1e17, 9e17, 1e18, 11e17, 2e18, 5e18} {
+		result, err := ln(int256.NewInt(x))
+		require.NoError(t, err)
+		assert.InDelta(t, math.Log(float64(x)/1e18), toFloat(result), 1e-15, "ln(%d)", x)
+	}
+
+	_, err := ln(new(int256.Int))
+	assert.ErrorIs(t, err, ErrLnOutOfBounds)
+}
+
+func TestLogProportion(t *testing.T) {
+	t.Parallel()
+
+	result, err := logProportion(int256.NewInt(75e16))
+	require.NoError(t, err)
+	assert.InDelta(t, math.Log(3), toFloat(result), 1e-15)
+
+	_, err = logProportion(one)
+	assert.ErrorIs(t, err, ErrMarketProportionMustNotEqualOne)
+}
+
+func TestGetExchangeRateFromImpliedRate(t *testing.T) {
+	t.Parallel()
+
+	// 5% a year for half a year
+	result, err := getExchangeRateFromImpliedRate(int256.NewInt(int64(math.Log(1.05)*1e18)),
+		int256.NewInt(impliedRateTime/2))
+	require.NoError(t, err)
+	assert.InDelta(t, math.Sqrt(1.05), toFloat(result), 1e-15)
+}
+
+func TestMarketState_preCompute(t *testing.T) {
+	t.Parallel()
+
+	extra := testExtra()
+
+	t.Run("keeps the implied rate", func(t *testing.T) {
+		t.Parallel()
+
+		comp, err := extra.preCompute(extra.PYIndex, extra.BlockTimestamp)
+		require.NoError(t, err)
+
+		lnImpliedRate, err := getLnImpliedRate(extra.TotalPt, comp.totalAsset, comp.rateScalar, comp.rateAnchor,
+			comp.timeToExpiry)
+		require.NoError(t, err)
+		assert.InDelta(t, toFloat(extra.LastLnImpliedRate), toFloat(lnImpliedRate), 1e-12)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := extra.preCompute(extra.PYIndex, extra.Expiry)
+		assert.ErrorIs(t, err, ErrMarketExpired)
+	})
+}
+
+func TestMarketState_executeTrade(t *testing.T) {
+	t.Parallel()
+
+	extra := testExtra()
+	comp, err := extra.preCompute(extra.PYIndex, extra.BlockTimestamp)
+	require.NoError(t, err)
+
+	t.Run("buying PT lowers the implied rate", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := extra.executeTrade(comp, extra.PYIndex, mustInt("10000000000000000000000"))
+		require.NoError(t, err)
+		assert.Negative(t, result.netSyToAccount.Sign())
+		assert.Positive(t, result.netSyFee.Sign())
+		assert.True(t, result.lastLnImpliedRate.Lt(extra.LastLnImpliedRate))
+		assert.Equal(t, new(int256.Int).Sub(extra.TotalPt, mustInt("10000000000000000000000")), result.totalPt)
+		assert.Equal(t, new(int256.Int).Sub(extra.TotalSy,
+			new(int256.Int).Add(result.netSyToAccount, result.netSyToReserve)), result.totalSy)
+	})
+
+	t.Run("selling PT raises the implied rate", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := extra.executeTrade(comp, extra.PYIndex, mustInt("-10000000000000000000000"))
+		require.NoError(t, err)
+		assert.Positive(t, result.netSyToAccount.Sign())
+		assert.True(t, result.lastLnImpliedRate.Gt(extra.LastLnImpliedRate))
+	})
+
+	t.Run("proportion too high", func(t *testing.T) {
+		t.Parallel()
+
+		netPtToAccount := new(int256.Int).Mul(extra.TotalPt, int256.NewInt(-2))
+		_, err := extra.executeTrade(comp, extra.PYIndex, netPtToAccount)
+		assert.ErrorIs(t, err, ErrMarketProportionTooHigh)
+	})
+
+	t.Run("insufficient PT", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := extra.executeTrade(comp, extra.PYIndex, extra.TotalPt)
+		assert.ErrorIs(t, err, ErrMarketInsufficientPt)
+	})
+}
@@ -0,0 +1,323 @@
+package pendlev2
+
+import (
+	"math/big"
+	"slices"
+
+	"github.com/KyberNetwork/int256"
+	"github.com/goccy/go-json"
+	"github.com/samber/lo"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// PoolSimulator simulates the swaps of a Pendle V2 market between its SY, PT and YT, and the asset of the SY wrapped
+// into and unwrapped from SY at its exchange rate.
+type PoolSimulator struct {
+	pool.Pool
+	Extra
+	StaticExtra
+}
+
+// tokenRole is the role of a token of the pool.
+type tokenRole int
+
+const (
+	roleSy tokenRole = iota
+	rolePt
+	roleYt
+	roleToken
+)
+
+var roleNames = [...]string{roleSy: "Sy", rolePt: "Pt", roleYt: "Yt", roleToken: "Token"}
+
+var _ = pool.RegisterFactory0(DexType, NewPoolSimulator)
+
+func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
+	var extra Extra
+	if err := json.Unmarshal([]byte(entityPool.Extra), &extra); err != nil {
+		return nil, err
+	}
+
+	var staticExtra StaticExtra
+	if err := json.Unmarshal([]byte(entityPool.StaticExtra), &staticExtra); err != nil {
+		return nil, err
+	}
+
+	return &PoolSimulator{
+		Pool: pool.Pool{Info: pool.PoolInfo{
+			Address:  entityPool.Address,
+			Exchange: entityPool.Exchange,
+			Type:     entityPool.Type,
+			Tokens: lo.Map(entityPool.Tokens,
+				func(item *entity.PoolToken, index int) string { return item.Address }),
+			Reserves: lo.Map(entityPool.Reserves,
+				func(item string, index int) *big.Int { return bignumber.NewBig(item) }),
+			BlockNumber: entityPool.BlockNumber,
+		}},
+		Extra:       extra,
+		StaticExtra: staticExtra,
+	}, nil
+}
+
+func (p *PoolSimulator) CalcAmountOut(params pool.CalcAmountOutParams) (*pool.CalcAmountOutResult, error) {
+	roleIn, okIn := p.role(params.TokenAmountIn.Token)
+	roleOut, okOut := p.role(params.TokenOut)
+	if !okIn || !okOut || roleIn == roleOut {
+		return nil, ErrInvalidToken
+	}
+
+	amountIn, err := int256.FromBig(params.TokenAmountIn.Amount)
+	if err != nil || amountIn.Sign() <= 0 {
+		return nil, ErrInvalidAmountIn
+	}
+
+	var gas int64
+	if roleIn == roleToken {
+		amountIn = assetToSy(p.SYExchangeRate, amountIn)
+		gas += gasMintSy
+	}
+
+	marketRoleIn, marketRoleOut := roleIn, roleOut
+	if roleIn == roleToken {
+		marketRoleIn = roleSy
+	}
+	if roleOut == roleToken {
+		marketRoleOut = roleSy
+	}
+
+	// trades are priced at the block time of the state, so that the same state always quotes the same
+	swapInfo := SwapInfo{Action: action(roleIn, roleOut)}
+	blockTime := p.BlockTimestamp
+	amountOut, fee := amountIn, new(int256.Int)
+	switch {
+	case marketRoleIn == roleSy && marketRoleOut == roleSy:
+	case marketRoleIn == roleSy && marketRoleOut == rolePt:
+		swapInfo.trade, err = p.swapExactSyForPt(amountIn, blockTime)
+		if err == nil {
+			amountOut = swapInfo.trade.netPtToAccount
+			swapInfo.ApproxPt = amountOut.Dec()
+		}
+		gas += gasSwapExactSyForPt
+	case marketRoleIn == rolePt && marketRoleOut == roleSy:
+		swapInfo.trade, err = p.swapExactPtForSy(amountIn, blockTime)
+		if err == nil {
+			amountOut = swapInfo.trade.netSyToAccount
+		}
+		gas += gasSwapExactPtForSy
+	case marketRoleIn == roleSy && marketRoleOut == roleYt:
+		swapInfo.trade, amountOut, err = p.swapExactSyForYt(amountIn, blockTime)
+		if err == nil {
+			swapInfo.ApproxPt = new(int256.Int).Neg(swapInfo.trade.netPtToAccount).Dec()
+		}
+		gas += gasSwapExactSyForYt
+	case marketRoleIn == roleYt && marketRoleOut == roleSy:
+		swapInfo.trade, amountOut, err = p.swapExactYtForSy(amountIn, blockTime)
+		gas += gasSwapExactYtForSy
+	default:
+		return nil, ErrUnsupportedSwap
+	}
+	if err != nil {
+		return nil, err
+	}
+	if swapInfo.trade != nil {
+		fee = swapInfo.trade.netSyFee
+	}
+
+	if roleOut == roleToken {
+		amountOut = syToAsset(p.SYExchangeRate, amountOut)
+		gas += gasRedeemSy
+	}
+	if amountOut.Sign() <= 0 {
+		return nil, ErrInsufficientAmountOut
+	}
+
+	return &pool.CalcAmountOutResult{
+		TokenAmountOut: &pool.TokenAmount{Token: params.TokenOut, Amount: amountOut.ToBig()},
+		Fee:            &pool.TokenAmount{Token: p.SY, Amount: fee.ToBig()},
+		Gas:            gas,
+		SwapInfo:       swapInfo,
+	}, nil
+}
+
+// swapExactPtForSy sells exactPtIn PT to the market, as PendleMarket.swapExactPtForSy.
+func (p *PoolSimulator) swapExactPtForSy(exactPtIn *int256.Int, blockTime uint64) (*tradeResult, error) {
+	comp, err := p.preCompute(p.PYIndex, blockTime)
+	if err != nil {
+		return nil, err
+	}
+	return p.executeTrade(comp, p.PYIndex, new(int256.Int).Neg(exactPtIn))
+}
+
+// swapExactSyForPt buys the most PT costing at most exactSyIn SY, as the router approximating the PT of
+// PendleMarket.swapSyForExactPt.
+func (p *PoolSimulator) swapExactSyForPt(exactSyIn *int256.Int, blockTime uint64) (*tradeResult, error) {
+	comp, err := p.preCompute(p.PYIndex, blockTime)
+	if err != nil {
+		return nil, err
+	}
+
+	netPtOut, err := approxMax(new(int256.Int).Sub(p.TotalPt, int256.NewInt(1)), func(netPtOut *int256.Int) bool {
+		result, err := p.calcTrade(comp, p.PYIndex, netPtOut)
+		return err == nil && new(int256.Int).Neg(result.netSyToAccount).Lte(exactSyIn)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return p.executeTrade(comp, p.PYIndex, netPtOut)
+}
+
+// swapExactSyForYt flash-sells the most PT whose SY, along with exactSyIn SY, mints enough PT to repay the market,
+// and returns the YT minted, as the router approximating the PT of swapExactSyForYt.
+func (p *PoolSimulator) swapExactSyForYt(exactSyIn *int256.Int, blockTime uint64) (*tradeResult, *int256.Int,
+	error) {
+	comp, err := p.preCompute(p.PYIndex, blockTime)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	maxPtIn := mulDown(maxMarketProportion, new(int256.Int).Add(p.TotalPt, comp.totalAsset))
+	maxPtIn.Sub(maxPtIn, p.TotalPt)
+	netPtIn, err := approxMax(maxPtIn, func(netPtIn *int256.Int) bool {
+		result, err := p.calcTrade(comp, p.PYIndex, new(int256.Int).Neg(netPtIn))
+		if err != nil {
+			return false
+		}
+		netSyToPull := assetToSyUp(p.PYIndex, netPtIn)
+		return netSyToPull.Sub(netSyToPull, result.netSyToAccount).Lte(exactSyIn)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := p.executeTrade(comp, p.PYIndex, new(int256.Int).Neg(netPtIn))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// all the SY mints PT and YT, the PT repaying the market
+	netYtOut := syToAsset(p.PYIndex, new(int256.Int).Add(exactSyIn, result.netSyToAccount))
+	return result, netYtOut, nil
+}
+
+// swapExactYtForSy flash-buys exactYtIn PT, redeems it with the YT, and returns the SY left after paying the market,
+// as the router swapExactYtForSy.
+func (p *PoolSimulator) swapExactYtForSy(exactYtIn *int256.Int, blockTime uint64) (*tradeResult, *int256.Int,
+	error) {
+	comp, err := p.preCompute(p.PYIndex, blockTime)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := p.executeTrade(comp, p.PYIndex, exactYtIn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	netSyOut := assetToSy(p.PYIndex, exactYtIn)
+	return result, netSyOut.Add(netSyOut, result.netSyToAccount), nil
+}
+
+// approxMax returns the largest amount up to maxAmount satisfying the monotone feasible, within approxPrecision.
+func approxMax(maxAmount *int256.Int, feasible func(*int256.Int) bool) (*int256.Int, error) {
+	if maxAmount.Sign() <= 0 {
+		return nil, ErrInsufficientAmountOut
+	}
+	if feasible(maxAmount) {
+		return maxAmount, nil
+	}
+
+	low, high := new(int256.Int), new(int256.Int).Set(maxAmount)
+	gap, tolerance := new(int256.Int), new(int256.Int)
+	for range approxMaxIterations {
+		gap.Sub(high, low)
+		if gap.Lte(tolerance.Quo(low, approxPrecision)) || gap.IsOne() {
+			break
+		}
+
+		mid := gap.Quo(gap, int256.NewInt(2)).Add(gap, low)
+		if feasible(mid) {
+			low.Set(mid)
+		} else {
+			high.Set(mid)
+		}
+	}
+
+	if low.IsZero() {
+		return nil, ErrInsufficientAmountOut
+	}
+	return low, nil
+}
+
+func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	swapInfo, ok := params.SwapInfo.(SwapInfo)
+	if !ok || swapInfo.trade == nil {
+		return
+	}
+
+	p.TotalPt, p.TotalSy = swapInfo.trade.totalPt, swapInfo.trade.totalSy
+	p.LastLnImpliedRate = swapInfo.trade.lastLnImpliedRate
+
+	p.Info.Reserves = slices.Clone(p.Info.Reserves)
+	if i := p.GetTokenIndex(p.SY); i >= 0 {
+		p.Info.Reserves[i] = p.TotalSy.ToBig()
+	}
+	if i := p.GetTokenIndex(p.PT); i >= 0 {
+		p.Info.Reserves[i] = p.TotalPt.ToBig()
+	}
+}
+
+func (p *PoolSimulator) CloneState() pool.IPoolSimulator {
+	cloned := *p
+	return &cloned
+}
+
+func (p *PoolSimulator) CanSwapTo(address string) []string {
+	role, ok := p.role(address)
+	if !ok {
+		return nil
+	}
+
+	return lo.Filter(p.Info.Tokens, func(token string, _ int) bool {
+		other, ok := p.role(token)
+		return ok && other != role && !(role == rolePt && other == roleYt) && !(role == roleYt && other == rolePt)
+	})
+}
+
+func (p *PoolSimulator) CanSwapFrom(address string) []string {
+	return p.CanSwapTo(address)
+}
+
+func (p *PoolSimulator) GetMetaInfo(_, _ string) any {
+	return pool.MetaInfo{BlockNumber: p.Info.BlockNumber}
+}
+
+func (p *PoolSimulator) role(token string) (tokenRole, bool) {
+	switch token {
+	case p.SY:
+		return roleSy, true
+	case p.PT:
+		return rolePt, true
+	case p.YT:
+		return roleYt, true
+	case "":
+		return 0, false
+	case p.Asset:
+		return roleToken, true
+	}
+	return 0, false
+}
+
+// action returns the router method swapping the token of roleIn to the one of roleOut.
+func action(roleIn, roleOut tokenRole) string {
+	switch {
+	case roleIn == roleToken && roleOut == roleSy:
+		return "mintSyFromToken"
+	case roleIn == roleSy && roleOut == roleToken:
+		return "redeemSyToToken"
+	}
+	return "swapExact" + roleNames[roleIn] + "For" + roleNames[roleOut]
+}
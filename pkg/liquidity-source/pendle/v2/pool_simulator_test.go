@@ -0,0 +1,252 @@
+package pendlev2
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/KyberNetwork/int256"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+const (
+	market = "0xc374f7ec85f8c7de3207a10bb1978ba104bda3b2"
+	sy     = "0xcbc72d92b2dc8187414f6734718563898740c0bc"
+	pt     = "0xf7906f274c174a52d444175729e3fa98f9bde285"
+	yt     = "0xfb35fd0095dd1096b1ca49ad44d8c5812a201677"
+	asset  = "0xae7ab96520de3a18e5e111b5eaab095312d7fe84"
+	router = "0x888888888889758f76e7103c6cbf23abbf58f946"
+)
+
+func mustInt(s string) *int256.Int {
+	return int256.MustFromDec(s)
+}
+
+// testExtra returns a market with 1M PT and 1M SY, at 5% a year half a year before its expiry, whose SY is worth
+// 1.1 assets.
+func testExtra() *Extra {
+	const blockTimestamp = 4_000_000_000
+	return &Extra{
+		MarketState: MarketState{
+			TotalPt:           mustInt("1000000000000000000000000"),
+			TotalSy:           mustInt("1000000000000000000000000"),
+			ScalarRoot:        mustInt("11600000000000000000"),
+			Expiry:            blockTimestamp + impliedRateTime/2,
+			LnFeeRateRoot:     mustInt("799680170564244"),
+			ReserveFeePercent: int256.NewInt(80),
+			LastLnImpliedRate: mustInt("48790164169432048"),
+		},
+		PYIndex:        int256.NewInt(11e17),
+		SYExchangeRate: int256.NewInt(11e17),
+		BlockTimestamp: blockTimestamp,
+	}
+}
+
+func newTestPoolSimulator(t *testing.T, extra *Extra) *PoolSimulator {
+	t.Helper()
+
+	extraBytes, err := json.Marshal(extra)
+	require.NoError(t, err)
+	staticExtraBytes, err := json.Marshal(StaticExtra{SY: sy, PT: pt, YT: yt, Asset: asset, Router: router})
+	require.NoError(t, err)
+
+	p, err := NewPoolSimulator(entity.Pool{
+		Address:  market,
+		Exchange: DexType,
+		Type:     DexType,
+		Reserves: entity.PoolReserves{extra.TotalSy.Dec(), extra.TotalPt.Dec(), "0",
+			syToAsset(extra.SYExchangeRate, extra.TotalSy).Dec()},
+		Tokens: []*entity.PoolToken{
+			{Address: sy, Swappable: true},
+			{Address: pt, Swappable: true},
+			{Address: yt, Swappable: true},
+			{Address: asset, Swappable: true},
+		},
+		Extra:       string(extraBytes),
+		StaticExtra: string(staticExtraBytes),
+		BlockNumber: 20000000,
+	})
+	require.NoError(t, err)
+	return p
+}
+
+func calcAmountOut(p *PoolSimulator, tokenIn string, amountIn *big.Int, tokenOut string) (*pool.CalcAmountOutResult,
+	error) {
+	return p.CalcAmountOut(pool.CalcAmountOutParams{
+		TokenAmountIn: pool.TokenAmount{Token: tokenIn, Amount: amountIn},
+		TokenOut:      tokenOut,
+	})
+}
+
+func TestPoolSimulator_CalcAmountOut(t *testing.T) {
+	t.Parallel()
+
+	amountIn := bignumber.TenPowInt(21)
+
+	t.Run("PT is bought at a discount", func(t *testing.T) {
+		t.Parallel()
+
+		p := newTestPoolSimulator(t, testExtra())
+		result, err := calcAmountOut(p, sy, amountIn, pt)
+		require.NoError(t, err)
+
+		// 1000 SY is 1100 assets, buying at most 1100 * 1.05 ^ 0.5 PT
+		ptOut := result.TokenAmountOut.Amount
+		assert.True(t, ptOut.Cmp(bignumber.NewBig("1120000000000000000000")) > 0, ptOut.String())
+		assert.True(t, ptOut.Cmp(bignumber.NewBig("1127160000000000000000")) < 0, ptOut.String())
+		assert.Equal(t, "swapExactSyForPt", result.SwapInfo.(SwapInfo).Action)
+		assert.Equal(t, ptOut.String(), result.SwapInfo.(SwapInfo).ApproxPt)
+		assert.Equal(t, sy, result.Fee.Token)
+		assert.Positive(t, result.Fee.Amount.Sign())
+
+		// the SY paid is within the precision of the approximation below the SY in
+		trade := result.SwapInfo.(SwapInfo).trade
+		syIn := new(big.Int).Neg(trade.netSyToAccount.ToBig())
+		assert.True(t, syIn.Cmp(amountIn) <= 0)
+		assert.True(t, new(big.Int).Sub(amountIn, syIn).Cmp(bignumber.TenPowInt(13)) < 0)
+	})
+
+	t.Run("PT and SY round trip loses value", func(t *testing.T) {
+		t.Parallel()
+
+		p := newTestPoolSimulator(t, testExtra())
+		result, err := calcAmountOut(p, sy, amountIn, pt)
+		require.NoError(t, err)
+		p.UpdateBalance(pool.UpdateBalanceParams{SwapInfo: result.SwapInfo})
+
+		result, err = calcAmountOut(p, pt, result.TokenAmountOut.Amount, sy)
+		require.NoError(t, err)
+		assert.Equal(t, "swapExactPtForSy", result.SwapInfo.(SwapInfo).Action)
+		assert.True(t, result.TokenAmountOut.Amount.Cmp(amountIn) < 0)
+		assert.True(t, result.TokenAmountOut.Amount.Cmp(bignumber.NewBig("999000000000000000000")) > 0,
+			result.TokenAmountOut.Amount.String())
+	})
+
+	t.Run("YT is leveraged yield", func(t *testing.T) {
+		t.Parallel()
+
+		p := newTestPoolSimulator(t, testExtra())
+		result, err := calcAmountOut(p, sy, amountIn, yt)
+		require.NoError(t, err)
+
+		// YT is worth about 1 - 1 / 1.05 ^ 0.5 assets, 1100 assets buying at most 45600 YT
+		ytOut := result.TokenAmountOut.Amount
+		assert.True(t, ytOut.Cmp(bignumber.NewBig("35000000000000000000000")) > 0, ytOut.String())
+		assert.True(t, ytOut.Cmp(bignumber.NewBig("45600000000000000000000")) < 0, ytOut.String())
+		assert.Equal(t, "swapExactSyForYt", result.SwapInfo.(SwapInfo).Action)
+		assert.NotEmpty(t, result.SwapInfo.(SwapInfo).ApproxPt)
+
+		p.UpdateBalance(pool.UpdateBalanceParams{SwapInfo: result.SwapInfo})
+		result, err = calcAmountOut(p, yt, ytOut, sy)
+		require.NoError(t, err)
+		assert.Equal(t, "swapExactYtForSy", result.SwapInfo.(SwapInfo).Action)
+		assert.True(t, result.TokenAmountOut.Amount.Cmp(amountIn) < 0)
+		assert.Positive(t, result.TokenAmountOut.Amount.Sign())
+	})
+
+	t.Run("asset is wrapped into SY", func(t *testing.T) {
+		t.Parallel()
+
+		p := newTestPoolSimulator(t, testExtra())
+		viaSy, err := calcAmountOut(p, sy, amountIn, pt)
+		require.NoError(t, err)
+
+		// 1100 assets are 1000 SY
+		assetIn := new(big.Int).Mul(amountIn, big.NewInt(11))
+		result, err := calcAmountOut(p, asset, assetIn.Div(assetIn, big.NewInt(10)), pt)
+		require.NoError(t, err)
+		assert.Equal(t, "swapExactTokenForPt", result.SwapInfo.(SwapInfo).Action)
+		assert.Equal(t, viaSy.TokenAmountOut.Amount, result.TokenAmountOut.Amount)
+		assert.Equal(t, viaSy.Gas+gasMintSy, result.Gas)
+
+		result, err = calcAmountOut(p, sy, amountIn, asset)
+		require.NoError(t, err)
+		assert.Equal(t, "redeemSyToToken", result.SwapInfo.(SwapInfo).Action)
+		assert.Equal(t, bignumber.NewBig("1100000000000000000000"), result.TokenAmountOut.Amount)
+		assert.Zero(t, result.Fee.Amount.Sign())
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		t.Parallel()
+
+		extra := testExtra()
+		extra.BlockTimestamp = extra.Expiry
+		p := newTestPoolSimulator(t, extra)
+		_, err := calcAmountOut(p, pt, amountIn, sy)
+		assert.ErrorIs(t, err, ErrMarketExpired)
+	})
+
+	t.Run("priced at the block time", func(t *testing.T) {
+		t.Parallel()
+
+		// a state read years ago is still priced at its block, half a year before its expiry
+		extra := testExtra()
+		extra.BlockTimestamp = 1_500_000_000
+		extra.Expiry = extra.BlockTimestamp + impliedRateTime/2
+		want, err := calcAmountOut(newTestPoolSimulator(t, testExtra()), pt, amountIn, sy)
+		require.NoError(t, err)
+		got, err := calcAmountOut(newTestPoolSimulator(t, extra), pt, amountIn, sy)
+		require.NoError(t, err)
+		assert.Equal(t, want.TokenAmountOut.Amount, got.TokenAmountOut.Amount)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		p := newTestPoolSimulator(t, testExtra())
+		_, err := calcAmountOut(p, pt, amountIn, yt)
+		assert.ErrorIs(t, err, ErrUnsupportedSwap)
+		_, err = calcAmountOut(p, sy, amountIn, sy)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+		_, err = calcAmountOut(p, market, amountIn, sy)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+		_, err = calcAmountOut(p, sy, new(big.Int), pt)
+		assert.ErrorIs(t, err, ErrInvalidAmountIn)
+		_, err = calcAmountOut(p, pt, bignumber.TenPowInt(30), sy)
+		assert.Error(t, err)
+	})
+}
+
+func TestPoolSimulator_UpdateBalance(t *testing.T) {
+	t.Parallel()
+
+	p := newTestPoolSimulator(t, testExtra())
+	cloned := p.CloneState().(*PoolSimulator)
+
+	result, err := calcAmountOut(p, pt, bignumber.TenPowInt(21), sy)
+	require.NoError(t, err)
+	p.UpdateBalance(pool.UpdateBalanceParams{SwapInfo: result.SwapInfo})
+
+	trade := result.SwapInfo.(SwapInfo).trade
+	assert.Equal(t, trade.totalPt, p.TotalPt)
+	assert.Equal(t, trade.totalSy, p.TotalSy)
+	assert.Equal(t, trade.lastLnImpliedRate, p.LastLnImpliedRate)
+	assert.Equal(t, trade.totalSy.ToBig(), p.Info.Reserves[0])
+	assert.Equal(t, trade.totalPt.ToBig(), p.Info.Reserves[1])
+
+	extra := testExtra()
+	assert.Equal(t, extra.TotalPt, cloned.TotalPt)
+	assert.Equal(t, extra.TotalSy, cloned.TotalSy)
+	assert.Equal(t, extra.LastLnImpliedRate, cloned.LastLnImpliedRate)
+	assert.Equal(t, extra.TotalSy.ToBig(), cloned.Info.Reserves[0])
+
+	again, err := calcAmountOut(p, pt, bignumber.TenPowInt(21), sy)
+	require.NoError(t, err)
+	assert.True(t, again.TokenAmountOut.Amount.Cmp(result.TokenAmountOut.Amount) < 0)
+}
+
+func TestPoolSimulator_CanSwapTo(t *testing.T) {
+	t.Parallel()
+
+	p := newTestPoolSimulator(t, testExtra())
+	assert.ElementsMatch(t, []string{pt, yt, asset}, p.CanSwapTo(sy))
+	assert.ElementsMatch(t, []string{sy, asset}, p.CanSwapTo(pt))
+	assert.ElementsMatch(t, []string{sy, asset}, p.CanSwapFrom(yt))
+	assert.ElementsMatch(t, []string{sy, pt, yt}, p.CanSwapTo(asset))
+	assert.Empty(t, p.CanSwapTo(market))
+}
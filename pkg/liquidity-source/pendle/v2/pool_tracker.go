@@ -0,0 +1,141 @@
+package pendlev2
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/int256"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	pooltrack "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/tracker"
+)
+
+type PoolTracker struct {
+	config       *Config
+	ethrpcClient *ethrpc.Client
+}
+
+var _ = pooltrack.RegisterFactoryCE0(DexType, NewPoolTracker)
+
+func NewPoolTracker(config *Config, ethrpcClient *ethrpc.Client) *PoolTracker {
+	return &PoolTracker{
+		config:       config,
+		ethrpcClient: ethrpcClient,
+	}
+}
+
+func (t *PoolTracker) GetNewPoolState(
+	ctx context.Context,
+	p entity.Pool,
+	_ pool.GetNewPoolStateParams,
+) (entity.Pool, error) {
+	lg := logger.WithFields(logger.Fields{"poolAddress": p.Address, "dexID": t.config.DexID})
+	lg.Info("start getting new state of pool")
+	defer lg.Info("finished getting new state of pool")
+
+	var staticExtra StaticExtra
+	if err := json.Unmarshal([]byte(p.StaticExtra), &staticExtra); err != nil {
+		return p, err
+	}
+
+	var (
+		state                         MarketStateResp
+		syExchangeRate, pyIndexStored *big.Int
+	)
+	resp, err := t.ethrpcClient.NewRequest().SetContext(ctx).AddCall(&ethrpc.Call{
+		ABI:    pendleMarketABI,
+		Target: p.Address,
+		Method: marketMethodReadState,
+		Params: []any{common.HexToAddress(t.config.Router)},
+	}, []any{&state}).AddCall(&ethrpc.Call{
+		ABI:    standardizedYieldABI,
+		Target: staticExtra.SY,
+		Method: syMethodExchangeRate,
+	}, []any{&syExchangeRate}).AddCall(&ethrpc.Call{
+		ABI:    yieldTokenABI,
+		Target: staticExtra.YT,
+		Method: ytMethodPyIndexStored,
+	}, []any{&pyIndexStored}).Aggregate()
+	if err != nil {
+		lg.WithFields(logger.Fields{"error": err}).Error("failed to read market state")
+		return p, err
+	}
+
+	if resp.BlockNumber != nil && p.BlockNumber > resp.BlockNumber.Uint64() {
+		return p, nil
+	}
+
+	// the market state is priced at the time of the block it was read at, the latest one if unknown
+	header, err := t.ethrpcClient.GetETHClient().HeaderByNumber(ctx, resp.BlockNumber)
+	if err != nil {
+		lg.WithFields(logger.Fields{"error": err}).Error("failed to get block header")
+		return p, err
+	}
+	blockTimestamp := header.Time
+
+	// YT.pyIndexCurrent, without the cache of the index within a block
+	pyIndex := syExchangeRate
+	if pyIndexStored.Cmp(pyIndex) > 0 {
+		pyIndex = pyIndexStored
+	}
+
+	extraBytes, err := json.Marshal(Extra{
+		MarketState: MarketState{
+			TotalPt:           int256.MustFromBig(state.TotalPt),
+			TotalSy:           int256.MustFromBig(state.TotalSy),
+			ScalarRoot:        int256.MustFromBig(state.ScalarRoot),
+			Expiry:            state.Expiry.Uint64(),
+			LnFeeRateRoot:     int256.MustFromBig(state.LnFeeRateRoot),
+			ReserveFeePercent: int256.MustFromBig(state.ReserveFeePercent),
+			LastLnImpliedRate: int256.MustFromBig(state.LastLnImpliedRate),
+		},
+		PYIndex:        int256.MustFromBig(pyIndex),
+		SYExchangeRate: int256.MustFromBig(syExchangeRate),
+		BlockTimestamp: blockTimestamp,
+	})
+	if err != nil {
+		return p, err
+	}
+
+	p.Extra = string(extraBytes)
+	totalPt := state.TotalPt
+	if blockTimestamp >= state.Expiry.Uint64() {
+		// an expired market no longer trades PT, only minting and redeeming SY are left
+		lg.Info("market expired")
+		totalPt = new(big.Int)
+	}
+	p.Reserves = reserves(p.Tokens, staticExtra, state.TotalSy, totalPt, syExchangeRate)
+	p.Timestamp = time.Now().Unix()
+	if resp.BlockNumber != nil {
+		p.BlockNumber = resp.BlockNumber.Uint64()
+	}
+
+	return p, nil
+}
+
+// reserves returns the SY and PT of the market, and its SY in assets as the reserve of the asset. YT is minted on
+// demand, it has no reserve.
+func reserves(tokens []*entity.PoolToken, staticExtra StaticExtra, totalSy, totalPt,
+	syExchangeRate *big.Int) entity.PoolReserves {
+	result := make(entity.PoolReserves, len(tokens))
+	for i, token := range tokens {
+		switch token.Address {
+		case staticExtra.SY:
+			result[i] = totalSy.String()
+		case staticExtra.PT:
+			result[i] = totalPt.String()
+		case staticExtra.Asset:
+			assetAmount := new(big.Int).Mul(totalSy, syExchangeRate)
+			result[i] = assetAmount.Quo(assetAmount, one.ToBig()).String()
+		default:
+			result[i] = "0"
+		}
+	}
+	return result
+}
@@ -0,0 +1,214 @@
+package pendlev2
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/goccy/go-json"
+	"github.com/samber/lo"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	poollist "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/list"
+)
+
+// PoolsListUpdater lists the markets created by the market factories, scanning their CreateNewMarket events in
+// windows of at most MaxBlockRangePerScan blocks.
+type PoolsListUpdater struct {
+	config       *Config
+	ethrpcClient *ethrpc.Client
+}
+
+var _ = poollist.RegisterFactoryCE(DexType, NewPoolsListUpdater)
+
+func NewPoolsListUpdater(config *Config, ethrpcClient *ethrpc.Client) *PoolsListUpdater {
+	return &PoolsListUpdater{
+		config:       config,
+		ethrpcClient: ethrpcClient,
+	}
+}
+
+// marketTokens is a market with its tokens read by readTokens.
+type marketTokens struct {
+	market     common.Address
+	sy, pt, yt common.Address
+	expiry     *big.Int
+	assetInfo  AssetInfo
+	isValidIn  bool
+	isValidOut bool
+}
+
+func (u *PoolsListUpdater) GetNewPools(ctx context.Context, metadataBytes []byte) ([]entity.Pool, []byte, error) {
+	metadata := Metadata{LastScannedBlock: u.config.StartBlock}
+	if len(metadataBytes) > 0 {
+		if err := json.Unmarshal(metadataBytes, &metadata); err != nil {
+			return nil, metadataBytes, err
+		}
+	}
+
+	head, err := u.ethrpcClient.GetBlockNumber(ctx)
+	if err != nil {
+		return nil, metadataBytes, err
+	}
+	fromBlock := metadata.LastScannedBlock
+	if fromBlock > head {
+		return nil, metadataBytes, nil
+	}
+	toBlock := head
+	if u.config.MaxBlockRangePerScan > 0 {
+		toBlock = min(fromBlock+u.config.MaxBlockRangePerScan-1, head)
+	}
+
+	markets, err := u.getCreatedMarkets(ctx, fromBlock, toBlock)
+	if err != nil {
+		logger.WithFields(logger.Fields{"dexID": u.config.DexID, "error": err}).Error("failed to get created markets")
+		return nil, metadataBytes, err
+	}
+
+	pools, err := u.initPools(ctx, markets)
+	if err != nil {
+		logger.WithFields(logger.Fields{"dexID": u.config.DexID, "error": err}).Error("failed to init pools")
+		return nil, metadataBytes, err
+	}
+
+	newMetadataBytes, err := json.Marshal(Metadata{LastScannedBlock: toBlock + 1})
+	if err != nil {
+		return nil, metadataBytes, err
+	}
+
+	return pools, newMetadataBytes, nil
+}
+
+// getCreatedMarkets returns the markets of the CreateNewMarket events of the market factories within the blocks.
+func (u *PoolsListUpdater) getCreatedMarkets(ctx context.Context, fromBlock, toBlock uint64) ([]common.Address,
+	error) {
+	logs, err := u.ethrpcClient.GetETHClient().FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: lo.Map(u.config.MarketFactories, func(factory string, _ int) common.Address {
+			return common.HexToAddress(factory)
+		}),
+		Topics: [][]common.Hash{{marketFactoryABI.Events[factoryEventCreateNewMarket].ID}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	markets := make([]common.Address, 0, len(logs))
+	for _, l := range logs {
+		if len(l.Topics) < 2 {
+			continue
+		}
+		markets = append(markets, common.BytesToAddress(l.Topics[1].Bytes()))
+	}
+	return markets, nil
+}
+
+// initPools returns the pools of the markets not expired yet.
+func (u *PoolsListUpdater) initPools(ctx context.Context, markets []common.Address) ([]entity.Pool, error) {
+	if len(markets) == 0 {
+		return nil, nil
+	}
+
+	infos := lo.Map(markets, func(market common.Address, _ int) *marketTokens {
+		return &marketTokens{market: market}
+	})
+
+	req := u.ethrpcClient.NewRequest().SetContext(ctx)
+	for _, info := range infos {
+		req.AddCall(&ethrpc.Call{
+			ABI:    pendleMarketABI,
+			Target: info.market.Hex(),
+			Method: marketMethodReadTokens,
+		}, []any{&info.sy, &info.pt, &info.yt}).AddCall(&ethrpc.Call{
+			ABI:    pendleMarketABI,
+			Target: info.market.Hex(),
+			Method: marketMethodExpiry,
+		}, []any{&info.expiry})
+	}
+	if _, err := req.Aggregate(); err != nil {
+		return nil, err
+	}
+
+	req = u.ethrpcClient.NewRequest().SetContext(ctx)
+	for _, info := range infos {
+		req.AddCall(&ethrpc.Call{
+			ABI:    standardizedYieldABI,
+			Target: info.sy.Hex(),
+			Method: syMethodAssetInfo,
+		}, []any{&info.assetInfo})
+	}
+	if _, err := req.Aggregate(); err != nil {
+		return nil, err
+	}
+
+	req = u.ethrpcClient.NewRequest().SetContext(ctx)
+	for _, info := range infos {
+		req.AddCall(&ethrpc.Call{
+			ABI:    standardizedYieldABI,
+			Target: info.sy.Hex(),
+			Method: syMethodIsValidTokenIn,
+			Params: []any{info.assetInfo.AssetAddress},
+		}, []any{&info.isValidIn}).AddCall(&ethrpc.Call{
+			ABI:    standardizedYieldABI,
+			Target: info.sy.Hex(),
+			Method: syMethodIsValidTokenOut,
+			Params: []any{info.assetInfo.AssetAddress},
+		}, []any{&info.isValidOut})
+	}
+	// not every SY accepts its asset, and some revert instead of returning false
+	if _, err := req.TryAggregate(); err != nil {
+		return nil, err
+	}
+
+	nowTimestamp := time.Now().Unix()
+	pools := make([]entity.Pool, 0, len(infos))
+	for _, info := range infos {
+		if info.expiry.Int64() <= nowTimestamp {
+			continue
+		}
+
+		staticExtra := StaticExtra{
+			SY:     hexutil.Encode(info.sy[:]),
+			PT:     hexutil.Encode(info.pt[:]),
+			YT:     hexutil.Encode(info.yt[:]),
+			Router: strings.ToLower(u.config.Router),
+		}
+		// native assets are left out, as the pool tokens are ERC20s
+		if info.isValidIn && info.isValidOut && info.assetInfo.AssetAddress != info.sy &&
+			info.assetInfo.AssetAddress != (common.Address{}) {
+			staticExtra.Asset = hexutil.Encode(info.assetInfo.AssetAddress[:])
+		}
+		staticExtraBytes, err := json.Marshal(staticExtra)
+		if err != nil {
+			return nil, err
+		}
+
+		tokens := []*entity.PoolToken{
+			{Address: staticExtra.SY, Swappable: true},
+			{Address: staticExtra.PT, Swappable: true},
+			{Address: staticExtra.YT, Swappable: true},
+		}
+		if staticExtra.Asset != "" {
+			tokens = append(tokens, &entity.PoolToken{Address: staticExtra.Asset, Swappable: true})
+		}
+
+		pools = append(pools, entity.Pool{
+			Address:     hexutil.Encode(info.market[:]),
+			Exchange:    u.config.DexID,
+			Type:        DexType,
+			Timestamp:   time.Now().Unix(),
+			Reserves:    lo.Map(tokens, func(_ *entity.PoolToken, _ int) string { return "0" }),
+			Tokens:      tokens,
+			StaticExtra: string(staticExtraBytes),
+		})
+	}
+
+	return pools, nil
+}
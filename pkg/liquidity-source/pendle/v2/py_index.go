@@ -0,0 +1,32 @@
+package pendlev2
+
+import (
+	"github.com/KyberNetwork/int256"
+)
+
+// The conversions between SY and assets follow PYIndexLib and SYUtils, rounding the magnitude of amounts.
+// https://github.com/pendle-finance/pendle-core-v2-public/blob/main/contracts/core/StandardizedYield/PYIndex.sol
+
+// syToAsset returns syAmount * index / 1e18.
+func syToAsset(index, syAmount *int256.Int) *int256.Int {
+	result := new(int256.Int).Mul(syAmount, index)
+	return result.Quo(result, one)
+}
+
+// assetToSy returns assetAmount * 1e18 / index.
+func assetToSy(index, assetAmount *int256.Int) *int256.Int {
+	result := new(int256.Int).Mul(assetAmount, one)
+	return result.Quo(result, index)
+}
+
+// assetToSyUp returns assetAmount * 1e18 / index with the magnitude rounded up.
+func assetToSyUp(index, assetAmount *int256.Int) *int256.Int {
+	result := new(int256.Int).Mul(assetAmount, one)
+	if result.Sign() < 0 {
+		result.Neg(result)
+		result.Add(result, index).Sub(result, int256.NewInt(1)).Quo(result, index)
+		return result.Neg(result)
+	}
+	result.Add(result, index).Sub(result, int256.NewInt(1))
+	return result.Quo(result, index)
+}
@@ -0,0 +1,70 @@
+package pendlev2
+
+import (
+	"math/big"
+
+	"github.com/KyberNetwork/int256"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MarketState is the MarketState of a PendleMarket read by readState, without the fields swaps do not use.
+type MarketState struct {
+	TotalPt           *int256.Int `json:"totalPt"`
+	TotalSy           *int256.Int `json:"totalSy"`
+	ScalarRoot        *int256.Int `json:"scalarRoot"`
+	Expiry            uint64      `json:"expiry"`
+	LnFeeRateRoot     *int256.Int `json:"lnFeeRateRoot"`
+	ReserveFeePercent *int256.Int `json:"reserveFeePercent"`
+	LastLnImpliedRate *int256.Int `json:"lastLnImpliedRate"`
+}
+
+type Extra struct {
+	MarketState
+	// PYIndex is the index of the YT, the max of the SY exchange rate and the stored index.
+	PYIndex *int256.Int `json:"pyIndex"`
+	// SYExchangeRate is the exchange rate of the SY, in assets per SY.
+	SYExchangeRate *int256.Int `json:"syExchangeRate"`
+	BlockTimestamp uint64      `json:"blockTimestamp"`
+}
+
+type StaticExtra struct {
+	SY string `json:"sy"`
+	PT string `json:"pt"`
+	YT string `json:"yt"`
+	// Asset is the asset of the SY, if it is a valid token in and out of the SY.
+	Asset  string `json:"asset,omitempty"`
+	Router string `json:"router"`
+}
+
+type SwapInfo struct {
+	// Action is the router method executing the swap, e.g. swapExactTokenForPt.
+	Action string `json:"action"`
+	// ApproxPt is the PT bought or sold by swaps of an exact SY amount in, found by binary search.
+	ApproxPt string `json:"approxPt,omitempty"`
+
+	trade *tradeResult
+}
+
+type Metadata struct {
+	LastScannedBlock uint64 `json:"lastScannedBlock"`
+}
+
+// MarketStateResp is the MarketState returned by PendleMarket.readState.
+type MarketStateResp struct {
+	TotalPt           *big.Int
+	TotalSy           *big.Int
+	TotalLp           *big.Int
+	Treasury          common.Address
+	ScalarRoot        *big.Int
+	Expiry            *big.Int
+	LnFeeRateRoot     *big.Int
+	ReserveFeePercent *big.Int
+	LastLnImpliedRate *big.Int
+}
+
+// AssetInfo is returned by StandardizedYield.assetInfo.
+type AssetInfo struct {
+	AssetType     uint8
+	AssetAddress  common.Address
+	AssetDecimals uint8
+}
@@ -0,0 +1,137 @@
+package rfqmaker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/samber/lo"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util"
+)
+
+// Client calls the firm quote endpoint of a maker, with the timeout of its config. Firm quotes are not retried here:
+// they are not idempotent, and a retried firm quote the maker did serve would book its liquidity twice. RFQPolicy
+// retries unavailable makers instead.
+type Client struct {
+	config *Config
+	client *resty.Client
+}
+
+func NewClient(config *Config) *Client {
+	return NewClientWithRestyClient(config, resty.NewWithClient(lo.ToPtr(lo.FromPtr(http.DefaultClient))))
+}
+
+func NewClientWithRestyClient(config *Config, client *resty.Client) *Client {
+	httpConfig := config.HTTP
+	client.SetBaseURL(httpConfig.BaseURL).
+		SetTimeout(lo.CoalesceOrEmpty(httpConfig.Timeout.Duration, defaultTimeout)).
+		SetHeaders(httpConfig.Headers)
+
+	switch auth := httpConfig.Auth; auth.Type {
+	case AuthHeader:
+		client.SetHeader(auth.Header, auth.Key)
+	case AuthBearer:
+		client.SetAuthToken(auth.Key)
+	case AuthBasic:
+		username, password, _ := strings.Cut(auth.Key, ":")
+		client.SetBasicAuth(username, password)
+	}
+
+	return &Client{
+		config: config,
+		client: client,
+	}
+}
+
+// FirmQuote requests a firm quote.
+func (c *Client) FirmQuote(ctx context.Context, vars map[string]string) (*Quote, error) {
+	endpoint := &c.config.FirmQuote
+	path, err := render(endpoint.Path, vars)
+	if err != nil {
+		return nil, err
+	}
+	request, err := renderAll(endpoint.Request, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	req := c.client.R().SetContext(ctx)
+	var resp *resty.Response
+	if strings.EqualFold(endpoint.Method, http.MethodPost) {
+		resp, err = req.SetHeader("Content-Type", "application/json").SetBody(request).Post(path)
+	} else {
+		resp, err = req.SetQueryParams(request).Get(path)
+	}
+	if err != nil {
+		return nil, pool.NewRFQError(pool.RFQReasonTransport, fmt.Errorf("%w: %v", ErrFirmQuoteFailed, err))
+	}
+
+	doc, decodeErr := decode(resp.Body())
+	if !resp.IsSuccess() {
+		message := lookupString(doc, endpoint.Response.Error)
+		if message == "" {
+			message = util.MaxBytesToString(resp.Body(), maxErrorBytes)
+		}
+		return nil, pool.NewRFQError(statusReason(resp.StatusCode()),
+			fmt.Errorf("%w: status %d: %s", ErrFirmQuoteFailed, resp.StatusCode(), message))
+	} else if decodeErr != nil {
+		return nil, pool.NewRFQError(pool.RFQReasonInvalidResponse,
+			fmt.Errorf("%w: %w: %v", ErrFirmQuoteFailed, ErrInvalidResponse, decodeErr))
+	}
+
+	quote, err := c.parse(doc, &endpoint.Response)
+	if err != nil {
+		return nil, pool.NewRFQError(pool.RFQReasonInvalidResponse, fmt.Errorf("%w: %w", ErrFirmQuoteFailed, err))
+	}
+	return quote, nil
+}
+
+// statusReason is the reason of a failed response: unavailable for 429 and 503, which the maker did not serve, unknown
+// for other server errors, which it may have, or rejected.
+func statusReason(status int) pool.RFQReason {
+	switch {
+	case status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable:
+		return pool.RFQReasonUnavailable
+	case status >= http.StatusInternalServerError:
+		return pool.RFQReasonUnknown
+	}
+	return pool.RFQReasonRejected
+}
+
+func (c *Client) parse(doc any, schema *ResponseSchema) (*Quote, error) {
+	amountOut, err := lookupBig(doc, schema.AmountOut)
+	if err != nil {
+		return nil, err
+	}
+
+	quotedAt := time.Now()
+	expiresAt := quotedAt.Add(lo.CoalesceOrEmpty(c.config.QuoteTTL.Duration, defaultQuoteTTL))
+	if schema.Expiry != "" {
+		if expiresAt, err = lookupTime(doc, schema.Expiry, schema.ExpiryInMillis); err != nil {
+			return nil, err
+		}
+	}
+
+	var extra map[string]any
+	if len(schema.Extra) > 0 {
+		extra = make(map[string]any, len(schema.Extra))
+		for name, path := range schema.Extra {
+			if value, ok := lookup(doc, path); ok {
+				extra[name] = value
+			}
+		}
+	}
+
+	return &Quote{
+		AmountOut: amountOut,
+		QuotedAt:  quotedAt,
+		QuoteID:   lookupString(doc, schema.QuoteID),
+		ExpiresAt: expiresAt,
+		Extra:     extra,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package rfqmaker
+
+import (
+	"github.com/KyberNetwork/blockchain-toolkit/time/durationjson"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+// Config declares a market maker: its API, how to call it for firm quotes, and how to read its responses. Onboarding a
+// maker is adding one of these, priced by the order-book pool simulator of its DexID.
+type Config struct {
+	DexID     string              `mapstructure:"dex_id" json:"dex_id"`
+	ChainID   valueobject.ChainID `mapstructure:"chain_id" json:"chain_id"`
+	HTTP      HTTPConfig          `mapstructure:"http" json:"http"`
+	FirmQuote Endpoint            `mapstructure:"firm_quote" json:"firm_quote"`
+	// QuoteTTL is the validity of quotes whose responses carry no expiry.
+	QuoteTTL durationjson.Duration `mapstructure:"quote_ttl" json:"quote_ttl,omitempty"`
+}
+
+// HTTPConfig is the connection and the timeout of a maker. Firm quotes are not retried on it: RFQPolicy retries those
+// failing with 429 or 503, which the maker did not serve.
+type HTTPConfig struct {
+	BaseURL string                `mapstructure:"base_url" json:"base_url"`
+	Timeout durationjson.Duration `mapstructure:"timeout" json:"timeout,omitempty"`
+	Auth    AuthConfig            `mapstructure:"auth" json:"auth,omitempty"`
+	Headers map[string]string     `mapstructure:"headers" json:"headers,omitempty"`
+}
+
+type AuthType string
+
+const (
+	AuthNone   AuthType = ""
+	AuthHeader AuthType = "header" // Key in Header
+	AuthBearer AuthType = "bearer" // Authorization: Bearer Key
+	AuthBasic  AuthType = "basic"  // Key as user:password
+)
+
+type AuthConfig struct {
+	Type   AuthType `mapstructure:"type" json:"type,omitempty"`
+	Header string   `mapstructure:"header" json:"header,omitempty"`
+	Key    string   `mapstructure:"key" json:"key,omitempty"`
+}
+
+// Endpoint is an API of a maker. Path and request values are templates of RFQ variables in braces, e.g.
+// "/v1/{chainId}/quote" or "{amountIn}": chainId, requestId, poolId, tokenIn, tokenOut, amountIn, amountOut, sender,
+// recipient, origin, txSender, txRecipient, source, slippage and the fields of the swap info and pool extra of the
+// route, as swapInfo.<field> and poolExtra.<field>.
+type Endpoint struct {
+	// Method is GET, sending the request as query params, or POST, sending it as a JSON body.
+	Method   string            `mapstructure:"method" json:"method,omitempty"`
+	Path     string            `mapstructure:"path" json:"path"`
+	Request  map[string]string `mapstructure:"request" json:"request"`
+	Response ResponseSchema    `mapstructure:"response" json:"response"`
+}
+
+// ResponseSchema locates the fields of a response by dot-separated paths, e.g. "quote.buyAmount" or "quotes.0.id".
+type ResponseSchema struct {
+	AmountOut string `mapstructure:"amount_out" json:"amount_out"`
+	QuoteID   string `mapstructure:"quote_id" json:"quote_id,omitempty"`
+	// Expiry is the unix time quotes expire at, in seconds, or in milliseconds if ExpiryInMillis.
+	Expiry         string `mapstructure:"expiry" json:"expiry,omitempty"`
+	ExpiryInMillis bool   `mapstructure:"expiry_in_millis" json:"expiry_in_millis,omitempty"`
+	// Error is the error message of failed responses.
+	Error string `mapstructure:"error" json:"error,omitempty"`
+	// Extra are the fields passed on to the executor in the RFQ result, e.g. signatures or calldata, by name.
+	Extra map[string]string `mapstructure:"extra" json:"extra,omitempty"`
+}
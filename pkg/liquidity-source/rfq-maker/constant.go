@@ -0,0 +1,20 @@
+package rfqmaker
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	defaultTimeout  = 5 * time.Second
+	defaultQuoteTTL = 30 * time.Second
+
+	maxErrorBytes = 256
+)
+
+var (
+	ErrFirmQuoteFailed = errors.New("firm quote failed")
+	ErrInvalidResponse = errors.New("invalid maker response")
+	ErrQuoteExpired    = errors.New("quote expired")
+	ErrUnknownVariable = errors.New("unknown template variable")
+)
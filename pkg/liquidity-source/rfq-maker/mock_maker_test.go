@@ -0,0 +1,167 @@
+package rfqmaker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+const mockAPIKeyHeader = "X-API-Key"
+
+// mockRequest is a request received by a mockMaker.
+type mockRequest struct {
+	Path   string
+	Params map[string]string
+}
+
+// mockMaker is an in-process market maker serving firm quotes at a fixed rate, which can be made slow or failing to
+// exercise timeouts and RFQPolicy. Its API:
+//
+//	POST /v1/{chainId}/firm-quote {"sellToken": "", "buyToken": "", "sellAmount": "", "taker": "", "recipient": ""}
+//	     {"quote": {"id": "...", "buyAmount": "...", "expiry": <unix seconds>, "signature": "0x..."}}
+//
+// Requests are authenticated by the mockAPIKeyHeader header. Errors are {"error": {"code": <status>, "message": "..."}}.
+type mockMaker struct {
+	*httptest.Server
+	apiKey string
+
+	mu       sync.Mutex
+	rateNum  *big.Int
+	rateDen  *big.Int
+	ttl      time.Duration
+	delay    time.Duration
+	failures int
+	status   int
+	quotes   int
+	requests []mockRequest
+}
+
+// newMockMaker starts a maker quoting 1:1, requiring apiKey if not empty. It is closed at the end of tests by Close.
+func newMockMaker(apiKey string) *mockMaker {
+	s := &mockMaker{
+		apiKey:  apiKey,
+		rateNum: big.NewInt(1),
+		rateDen: big.NewInt(1),
+		ttl:     defaultQuoteTTL,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/{chainId}/firm-quote", s.handleFirmQuote)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// SetRate makes the maker pay num/den of the buy token per sell token.
+func (s *mockMaker) SetRate(num, den int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateNum, s.rateDen = big.NewInt(num), big.NewInt(den)
+}
+
+// SetTTL sets the validity of quotes, negative for expired quotes.
+func (s *mockMaker) SetTTL(ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ttl = ttl
+}
+
+// SetDelay delays responses by delay.
+func (s *mockMaker) SetDelay(delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delay = delay
+}
+
+// FailNext fails the next n requests with status.
+func (s *mockMaker) FailNext(n, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures, s.status = n, status
+}
+
+// Requests returns the requests received so far.
+func (s *mockMaker) Requests() []mockRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]mockRequest(nil), s.requests...)
+}
+
+func (s *mockMaker) handleFirmQuote(w http.ResponseWriter, r *http.Request) {
+	var params map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeMockError(w, http.StatusBadRequest, "invalid body")
+		return
+	}
+	buyAmount, expiry, ok := s.serve(w, r, params)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	s.quotes++
+	id := "quote-" + strconv.Itoa(s.quotes)
+	s.mu.Unlock()
+	signature := sha256.Sum256([]byte(id + params["recipient"] + buyAmount.String()))
+	writeMockJSON(w, http.StatusOK, map[string]any{
+		"quote": map[string]any{
+			"id":        id,
+			"buyAmount": buyAmount.String(),
+			"expiry":    expiry,
+			"signature": "0x" + hex.EncodeToString(signature[:]),
+		},
+	})
+}
+
+// serve records, authenticates and prices a request, writing the error response if any.
+func (s *mockMaker) serve(w http.ResponseWriter, r *http.Request, params map[string]string) (*big.Int, int64, bool) {
+	s.mu.Lock()
+	s.requests = append(s.requests, mockRequest{Path: r.URL.Path, Params: params})
+	delay, ttl := s.delay, s.ttl
+	rateNum, rateDen := s.rateNum, s.rateDen
+	fail, status := s.failures > 0, s.status
+	if fail {
+		s.failures--
+	}
+	s.mu.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return nil, 0, false
+		}
+	}
+	if s.apiKey != "" && r.Header.Get(mockAPIKeyHeader) != s.apiKey {
+		writeMockError(w, http.StatusUnauthorized, "invalid api key")
+		return nil, 0, false
+	} else if fail {
+		writeMockError(w, status, http.StatusText(status))
+		return nil, 0, false
+	}
+
+	sellAmount, ok := new(big.Int).SetString(params["sellAmount"], 10)
+	if !ok || sellAmount.Sign() <= 0 || params["sellToken"] == "" || params["buyToken"] == "" {
+		writeMockError(w, http.StatusBadRequest, "invalid quote request")
+		return nil, 0, false
+	}
+	buyAmount := sellAmount.Mul(sellAmount, rateNum)
+	return buyAmount.Div(buyAmount, rateDen), time.Now().Add(ttl).Unix(), true
+}
+
+func writeMockError(w http.ResponseWriter, status int, message string) {
+	writeMockJSON(w, status, map[string]any{
+		"error": map[string]any{"code": status, "message": message},
+	})
+}
+
+func writeMockJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,67 @@
+package rfqmaker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/KyberNetwork/kutils/klog"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+type IClient interface {
+	FirmQuote(ctx context.Context, vars map[string]string) (*Quote, error)
+}
+
+// RFQHandler requests firm quotes from a maker declared by a Config.
+type RFQHandler struct {
+	pool.RFQHandler
+	config *Config
+	client IClient
+}
+
+func NewRFQHandler(config *Config, client IClient) *RFQHandler {
+	if client == nil {
+		client = NewClient(config)
+	}
+	return &RFQHandler{
+		config: config,
+		client: client,
+	}
+}
+
+func (h *RFQHandler) RFQ(ctx context.Context, params pool.RFQParams) (*pool.RFQResult, error) {
+	vars, err := Variables(h.config.ChainID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := h.client.FirmQuote(ctx, vars)
+	if err != nil {
+		klog.WithFields(ctx, klog.Fields{
+			"rfq.client": h.config.DexID,
+			"rfq.pool":   params.PoolID,
+			"error":      err,
+		}).Error("firm quote failed")
+		return nil, err
+	}
+	if !quote.ExpiresAt.After(time.Now()) {
+		return nil, pool.NewRFQError(pool.RFQReasonExpired, fmt.Errorf("%w: at %s", ErrQuoteExpired, quote.ExpiresAt))
+	}
+
+	return &pool.RFQResult{
+		NewAmountOut: quote.AmountOut,
+		Extra: RFQExtra{
+			QuoteID:   quote.QuoteID,
+			ExpiresAt: quote.ExpiresAt.Unix(),
+			Extra:     quote.Extra,
+		},
+		QuotedAt:  quote.QuotedAt,
+		ExpiresAt: quote.ExpiresAt,
+	}, nil
+}
+
+func (h *RFQHandler) BatchRFQ(context.Context, []pool.RFQParams) ([]*pool.RFQResult, error) {
+	return nil, nil
+}
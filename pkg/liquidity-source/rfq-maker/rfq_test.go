@@ -0,0 +1,176 @@
+package rfqmaker
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/KyberNetwork/blockchain-toolkit/time/durationjson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+const apiKey = "secret"
+
+func mockConfig(baseURL string) *Config {
+	return &Config{
+		DexID:   "mock-maker",
+		ChainID: valueobject.ChainIDBase,
+		HTTP: HTTPConfig{
+			BaseURL: baseURL,
+			Timeout: durationjson.Duration{Duration: time.Second},
+			Auth:    AuthConfig{Type: AuthHeader, Header: mockAPIKeyHeader, Key: apiKey},
+		},
+		FirmQuote: Endpoint{
+			Method: http.MethodPost,
+			Path:   "/v1/{chainId}/firm-quote",
+			Request: map[string]string{
+				"sellToken":  "{tokenIn}",
+				"buyToken":   "{tokenOut}",
+				"sellAmount": "{amountIn}",
+				"taker":      "{sender}",
+				"recipient":  "{recipient}",
+				"side":       "{swapInfo.side}",
+			},
+			Response: ResponseSchema{
+				AmountOut: "quote.buyAmount",
+				QuoteID:   "quote.id",
+				Expiry:    "quote.expiry",
+				Error:     "error.message",
+				Extra:     map[string]string{"signature": "quote.signature"},
+			},
+		},
+	}
+}
+
+func rfqParams() pool.RFQParams {
+	return pool.RFQParams{
+		PoolID:       "mock-maker_0xa_0xb",
+		TokenIn:      "0xa",
+		TokenOut:     "0xb",
+		SwapAmount:   big.NewInt(1_000_000),
+		RFQSender:    "0xexecutor",
+		RFQRecipient: "0xrecipient",
+		SwapInfo:     map[string]any{"side": "sell"},
+	}
+}
+
+func TestRFQHandler_RFQ(t *testing.T) {
+	t.Parallel()
+	server := newMockMaker(apiKey)
+	defer server.Close()
+	server.SetRate(1, 2)
+
+	result, err := NewRFQHandler(mockConfig(server.URL), nil).RFQ(context.Background(), rfqParams())
+	require.NoError(t, err)
+	assert.Equal(t, "500000", result.NewAmountOut.String())
+	extra, ok := result.Extra.(RFQExtra)
+	require.True(t, ok)
+	assert.Equal(t, "quote-1", extra.QuoteID)
+	assert.Greater(t, extra.ExpiresAt, time.Now().Unix())
+	assert.Equal(t, extra.ExpiresAt, result.ExpiresAt.Unix())
+	assert.Contains(t, extra.Extra["signature"], "0x")
+
+	requests := server.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, map[string]string{
+		"sellToken":  "0xa",
+		"buyToken":   "0xb",
+		"sellAmount": "1000000",
+		"taker":      "0xexecutor",
+		"recipient":  "0xrecipient",
+		"side":       "sell",
+	}, requests[0].Params)
+}
+
+func TestRFQHandler_RFQ_Unavailable(t *testing.T) {
+	t.Parallel()
+	server := newMockMaker(apiKey)
+	defer server.Close()
+	server.FailNext(1, http.StatusServiceUnavailable)
+
+	// firm quotes are not retried, RFQPolicy retries unavailable makers
+	handler := NewRFQHandler(mockConfig(server.URL), nil)
+	_, err := handler.RFQ(context.Background(), rfqParams())
+	assert.ErrorIs(t, err, ErrFirmQuoteFailed)
+	assert.ErrorContains(t, err, "status 503: Service Unavailable")
+	assert.Equal(t, pool.RFQReasonUnavailable, pool.RFQReasonOf(err))
+	assert.Len(t, server.Requests(), 1)
+
+	server.FailNext(1, http.StatusServiceUnavailable)
+	results, err := (&pool.RFQPolicy{MaxRetries: 1}).Do(context.Background(), []pool.RFQParams{rfqParams()},
+		func() ([]*pool.RFQResult, error) {
+			result, err := handler.RFQ(context.Background(), rfqParams())
+			return []*pool.RFQResult{result}, err
+		})
+	require.NoError(t, err)
+	assert.Equal(t, "1000000", results[0].NewAmountOut.String())
+	assert.Len(t, server.Requests(), 3)
+}
+
+func TestRFQHandler_RFQ_NotRetried(t *testing.T) {
+	t.Parallel()
+	server := newMockMaker(apiKey)
+	defer server.Close()
+	policy := &pool.RFQPolicy{MaxRetries: 2}
+	rfq := func(handler *RFQHandler) error {
+		_, err := policy.Do(context.Background(), []pool.RFQParams{rfqParams()}, func() ([]*pool.RFQResult, error) {
+			result, err := handler.RFQ(context.Background(), rfqParams())
+			return []*pool.RFQResult{result}, err
+		})
+		return err
+	}
+
+	// a timed out firm quote may have been booked by the maker, it is not sent twice
+	server.SetDelay(time.Second)
+	config := mockConfig(server.URL)
+	config.HTTP.Timeout.Duration = 50 * time.Millisecond
+	err := rfq(NewRFQHandler(config, nil))
+	assert.ErrorIs(t, err, ErrFirmQuoteFailed)
+	assert.Equal(t, pool.RFQReasonTransport, pool.RFQReasonOf(err))
+	assert.Len(t, server.Requests(), 1)
+
+	// neither is one failing with a server error other than 503
+	server.SetDelay(0)
+	server.FailNext(1, http.StatusInternalServerError)
+	err = rfq(NewRFQHandler(mockConfig(server.URL), nil))
+	assert.ErrorContains(t, err, "status 500: Internal Server Error")
+	assert.Equal(t, pool.RFQReasonUnknown, pool.RFQReasonOf(err))
+	assert.Len(t, server.Requests(), 2)
+}
+
+func TestRFQHandler_RFQ_Errors(t *testing.T) {
+	t.Parallel()
+	server := newMockMaker(apiKey)
+	defer server.Close()
+
+	config := mockConfig(server.URL)
+	config.HTTP.Auth.Key = "wrong"
+	_, err := NewRFQHandler(config, nil).RFQ(context.Background(), rfqParams())
+	assert.ErrorIs(t, err, ErrFirmQuoteFailed)
+	assert.ErrorContains(t, err, "status 401: invalid api key")
+	assert.Equal(t, pool.RFQReasonRejected, pool.RFQReasonOf(err))
+	assert.Len(t, server.Requests(), 1)
+
+	params := rfqParams()
+	params.SwapInfo = nil
+	_, err = NewRFQHandler(mockConfig(server.URL), nil).RFQ(context.Background(), params)
+	assert.ErrorIs(t, err, ErrUnknownVariable)
+
+	server.SetTTL(-time.Minute)
+	_, err = NewRFQHandler(mockConfig(server.URL), nil).RFQ(context.Background(), rfqParams())
+	assert.ErrorIs(t, err, ErrQuoteExpired)
+	assert.Equal(t, pool.RFQReasonExpired, pool.RFQReasonOf(err))
+
+	server.SetTTL(time.Minute)
+	server.SetDelay(time.Second)
+	config = mockConfig(server.URL)
+	config.HTTP.Timeout.Duration = 50 * time.Millisecond
+	_, err = NewRFQHandler(config, nil).RFQ(context.Background(), rfqParams())
+	assert.ErrorIs(t, err, ErrFirmQuoteFailed)
+}
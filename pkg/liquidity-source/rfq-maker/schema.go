@@ -0,0 +1,204 @@
+package rfqmaker
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+// Variables returns the template variables of an RFQ for the endpoints of a maker.
+func Variables(chainID valueobject.ChainID, params pool.RFQParams) (map[string]string, error) {
+	vars := map[string]string{
+		"chainId":     strconv.FormatUint(uint64(chainID), 10),
+		"requestId":   params.RequestID,
+		"poolId":      params.PoolID,
+		"tokenIn":     params.TokenIn,
+		"tokenOut":    params.TokenOut,
+		"amountIn":    bigString(params.SwapAmount),
+		"amountOut":   bigString(params.AmountOut),
+		"sender":      params.RFQSender,
+		"recipient":   params.RFQRecipient,
+		"origin":      params.GetOrigin(),
+		"txSender":    params.Sender,
+		"txRecipient": params.Recipient,
+		"source":      params.Source,
+		"slippage":    strconv.FormatInt(params.Slippage, 10),
+	}
+	if err := flatten(vars, "swapInfo", params.SwapInfo); err != nil {
+		return nil, err
+	}
+	if err := flatten(vars, "poolExtra", params.PoolExtra); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+// render substitutes the variables in braces of template.
+func render(template string, vars map[string]string) (string, error) {
+	var sb strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		name := template[start+1 : start+end]
+		value, ok := vars[name]
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrUnknownVariable, name)
+		}
+		sb.WriteString(template[:start])
+		sb.WriteString(value)
+		template = template[start+end+1:]
+	}
+	sb.WriteString(template)
+	return sb.String(), nil
+}
+
+// renderAll renders the templates of a request.
+func renderAll(templates map[string]string, vars map[string]string) (map[string]string, error) {
+	rendered := make(map[string]string, len(templates))
+	for field, template := range templates {
+		value, err := render(template, vars)
+		if err != nil {
+			return nil, err
+		}
+		rendered[field] = value
+	}
+	return rendered, nil
+}
+
+// flatten adds the scalar fields of v, marshalled to JSON, to vars as prefix.<path>.
+func flatten(vars map[string]string, prefix string, v any) error {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	doc, err := decode(data)
+	if err != nil {
+		return err
+	}
+	var walk func(path string, node any)
+	walk = func(path string, node any) {
+		switch node := node.(type) {
+		case map[string]any:
+			for key, child := range node {
+				walk(path+"."+key, child)
+			}
+		case []any:
+			for i, child := range node {
+				walk(path+"."+strconv.Itoa(i), child)
+			}
+		case nil:
+		default:
+			vars[path] = toString(node)
+		}
+	}
+	walk(prefix, doc)
+	return nil
+}
+
+func decode(data []byte) (any, error) {
+	var doc any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// lookup returns the value at a dot-separated path of doc.
+func lookup(doc any, path string) (any, bool) {
+	if path == "" {
+		return nil, false
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch node := doc.(type) {
+		case map[string]any:
+			var ok bool
+			if doc, ok = node[key]; !ok {
+				return nil, false
+			}
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			doc = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return doc, doc != nil
+}
+
+func lookupString(doc any, path string) string {
+	value, _ := lookup(doc, path)
+	return toString(value)
+}
+
+func lookupBig(doc any, path string) (*big.Int, error) {
+	value, ok := lookup(doc, path)
+	if !ok {
+		return nil, fmt.Errorf("%w: no %s", ErrInvalidResponse, path)
+	}
+	s := toString(value)
+	base := 10
+	if strings.HasPrefix(s, "0x") {
+		s, base = s[2:], 16
+	}
+	amount, ok := new(big.Int).SetString(s, base)
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("%w: %s is %v", ErrInvalidResponse, path, value)
+	}
+	return amount, nil
+}
+
+func lookupTime(doc any, path string, inMillis bool) (time.Time, error) {
+	unix, err := lookupBig(doc, path)
+	if err != nil || !unix.IsInt64() {
+		return time.Time{}, fmt.Errorf("%w: %s is not a unix time", ErrInvalidResponse, path)
+	}
+	if inMillis {
+		return time.UnixMilli(unix.Int64()), nil
+	}
+	return time.Unix(unix.Int64(), 0), nil
+}
+
+func toString(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+func bigString(b *big.Int) string {
+	if b == nil {
+		return ""
+	}
+	return b.String()
+}
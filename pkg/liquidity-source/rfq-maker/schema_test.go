@@ -0,0 +1,65 @@
+package rfqmaker
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+func TestVariables(t *testing.T) {
+	t.Parallel()
+	vars, err := Variables(valueobject.ChainIDBase, pool.RFQParams{
+		PoolID:       "maker_0xa_0xb",
+		TokenIn:      "0xa",
+		TokenOut:     "0xb",
+		SwapAmount:   big.NewInt(1000),
+		RFQRecipient: "0xexecutor",
+		Slippage:     50,
+		SwapInfo:     map[string]any{"side": "sell", "levels": []int{1, 2}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "8453", vars["chainId"])
+	assert.Equal(t, "1000", vars["amountIn"])
+	assert.Equal(t, "", vars["amountOut"])
+	assert.Equal(t, "0xexecutor", vars["recipient"])
+	assert.Equal(t, "50", vars["slippage"])
+	assert.Equal(t, "sell", vars["swapInfo.side"])
+	assert.Equal(t, "2", vars["swapInfo.levels.1"])
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+	vars := map[string]string{"chainId": "1", "tokenIn": "0xa"}
+
+	s, err := render("/v1/{chainId}/quote/{tokenIn}?x={", vars)
+	require.NoError(t, err)
+	assert.Equal(t, "/v1/1/quote/0xa?x={", s)
+
+	_, err = render("/{poolId}", vars)
+	assert.ErrorIs(t, err, ErrUnknownVariable)
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+	doc, err := decode([]byte(`{"quotes":[{"id":7,"amount":"0x10","expiry":1700000000000}],"ok":true}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "7", lookupString(doc, "quotes.0.id"))
+	assert.Equal(t, "true", lookupString(doc, "ok"))
+	assert.Equal(t, "", lookupString(doc, "quotes.1.id"))
+
+	amount, err := lookupBig(doc, "quotes.0.amount")
+	require.NoError(t, err)
+	assert.Equal(t, int64(16), amount.Int64())
+	_, err = lookupBig(doc, "quotes.0.missing")
+	assert.ErrorIs(t, err, ErrInvalidResponse)
+
+	expiry, err := lookupTime(doc, "quotes.0.expiry", true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1700000000), expiry.Unix())
+}
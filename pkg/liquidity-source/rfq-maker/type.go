@@ -0,0 +1,22 @@
+package rfqmaker
+
+import (
+	"math/big"
+	"time"
+)
+
+// Quote is a firm quote of a maker.
+type Quote struct {
+	AmountOut *big.Int
+	QuoteID   string
+	QuotedAt  time.Time
+	ExpiresAt time.Time
+	Extra     map[string]any
+}
+
+// RFQExtra is the extra of the RFQ result of a firm quote, passed on to the executor.
+type RFQExtra struct {
+	QuoteID   string         `json:"quoteId,omitempty"`
+	ExpiresAt int64          `json:"expiresAt"`
+	Extra     map[string]any `json:"extra,omitempty"`
+}
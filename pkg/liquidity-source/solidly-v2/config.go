@@ -1,8 +1,22 @@
 package solidlyv2
 
+import "github.com/KyberNetwork/kyberswap-dex-lib/pkg/transfertax"
+
 type Config struct {
 	DexID          string `json:"dexID"`
 	FeePrecision   uint64 `json:"feePrecision"`
 	FactoryAddress string `json:"factoryAddress"`
 	NewPoolLimit   int    `json:"newPoolLimit"`
+	// ReserveSlots reads the reserves of standard pools from their storage with eth_getProof rather than getReserves,
+	// except when tracking with overrides.
+	ReserveSlots *ReserveSlots `json:"reserveSlots,omitempty"`
+	// TransferTax enables the detection of the transfer taxes of the pool tokens, applied by the simulators. Its chain
+	// must be set.
+	TransferTax *transfertax.Config `json:"transferTax,omitempty"`
+}
+
+// ReserveSlots locates reserve0 and reserve1 in the pool storage, which differs between Solidly forks.
+type ReserveSlots struct {
+	Reserve0 uint64 `json:"reserve0"`
+	Reserve1 uint64 `json:"reserve1"`
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
 	velodromev2 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/velodrome-v2"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/transfertax"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 )
@@ -42,7 +43,8 @@ type (
 		isPaused bool
 		fee      *uint256.Int
 
-		gas Gas
+		gas   Gas
+		taxes transfertax.Taxes
 	}
 
 	Gas struct {
@@ -63,6 +65,12 @@ func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
 		return nil, err
 	}
 
+	// pools with taxed tokens, as detected by the tracker, are quoted net of the taxes
+	taxes, err := transfertax.PoolTaxes(entityPool)
+	if err != nil {
+		return nil, err
+	}
+
 	return &PoolSimulator{
 		Pool: pool.Pool{Info: pool.PoolInfo{
 			Address:     entityPool.Address,
@@ -82,11 +90,16 @@ func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
 		feePrecision: uint256.NewInt(staticExtra.FeePrecision),
 		fee:          uint256.NewInt(extra.Fee),
 
-		gas: defaultGas,
+		gas:   defaultGas,
+		taxes: taxes,
 	}, nil
 }
 
 func (s *PoolSimulator) CalcAmountOut(params pool.CalcAmountOutParams) (*pool.CalcAmountOutResult, error) {
+	return s.taxes.CalcAmountOut(params, s.calcAmountOut)
+}
+
+func (s *PoolSimulator) calcAmountOut(params pool.CalcAmountOutParams) (*pool.CalcAmountOutResult, error) {
 	if s.isPaused {
 		return nil, ErrPoolIsPaused
 	}
@@ -115,6 +128,10 @@ func (s *PoolSimulator) CalcAmountOut(params pool.CalcAmountOutParams) (*pool.Ca
 }
 
 func (s *PoolSimulator) CalcAmountIn(params pool.CalcAmountInParams) (*pool.CalcAmountInResult, error) {
+	return s.taxes.CalcAmountIn(params, s.calcAmountIn)
+}
+
+func (s *PoolSimulator) calcAmountIn(params pool.CalcAmountInParams) (*pool.CalcAmountInResult, error) {
 	if s.isPaused {
 		return nil, ErrPoolIsPaused
 	}
@@ -146,6 +163,7 @@ func (s *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
 	if indexIn < 0 || indexOut < 0 {
 		return
 	}
+	params = s.taxes.PoolAmounts(params)
 	s.Info.Reserves[indexIn] = new(big.Int).Sub(new(big.Int).Add(s.Info.Reserves[indexIn], params.TokenAmountIn.Amount), params.Fee.Amount)
 	s.Info.Reserves[indexOut] = new(big.Int).Sub(s.Info.Reserves[indexOut], params.TokenAmountOut.Amount)
 }
@@ -10,9 +10,11 @@ import (
 	"github.com/holiman/uint256"
 	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
 	poolpkg "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/transfertax"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/testutil"
 )
@@ -424,3 +426,28 @@ func TestPoolSimulator_CalcAmountIn(t *testing.T) {
 	testutil.TestCalcAmountIn(t, stablePoolSim)
 }
 
+func TestPoolSimulator_TransferTax(t *testing.T) {
+	t.Parallel()
+	const token0, token1 = "0x4200000000000000000000000000000000000006", "0xde5ed76e7c05ec5e4572cfc88d1acea165109e44"
+	taxedEntity := poolEntity
+	require.NoError(t, transfertax.SetPoolTaxes(&taxedEntity, transfertax.Taxes{token1: {BuyBps: 500}}))
+
+	// the simulator applies the taxes persisted by the tracker
+	sim, err := NewPoolSimulator(poolEntity)
+	require.NoError(t, err)
+	taxedSim, err := NewPoolSimulator(taxedEntity)
+	require.NoError(t, err)
+
+	params := poolpkg.CalcAmountOutParams{
+		TokenAmountIn: poolpkg.TokenAmount{Token: token0, Amount: big.NewInt(1e15)},
+		TokenOut:      token1,
+	}
+	res, err := sim.CalcAmountOut(params)
+	require.NoError(t, err)
+	taxedRes, err := taxedSim.CalcAmountOut(params)
+	require.NoError(t, err)
+	assert.Equal(t, transfertax.Taxes{token1: {BuyBps: 500}}.ApplyBuy(token1, res.TokenAmountOut.Amount),
+		taxedRes.TokenAmountOut.Amount)
+
+	testutil.TestCalcAmountIn(t, taxedSim)
+}
@@ -2,6 +2,7 @@ package solidlyv2
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 	"time"
 
@@ -15,12 +16,15 @@ import (
 	velodromev2 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/velodrome-v2"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
 	pooltrack "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/tracker"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/transfertax"
+	utileth "github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/eth"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
 )
 
 type PoolTracker struct {
 	config       *Config
 	ethrpcClient *ethrpc.Client
+	transferTax  *transfertax.Detector
 }
 
 var _ = pooltrack.RegisterFactoryCE(DexType, NewPoolTracker)
@@ -32,6 +36,7 @@ func NewPoolTracker(
 	return &PoolTracker{
 		config:       config,
 		ethrpcClient: ethrpcClient,
+		transferTax:  transfertax.NewConfiguredDetector(config.TransferTax, 0, ethrpcClient),
 	}, nil
 }
 
@@ -52,6 +57,22 @@ func (t *PoolTracker) GetNewPoolStateWithOverrides(
 }
 
 func (d *PoolTracker) getNewPoolState(
+	ctx context.Context,
+	p entity.Pool,
+	params pool.GetNewPoolStateParams,
+	overrides map[common.Address]gethclient.OverrideAccount,
+) (entity.Pool, error) {
+	newState, err := d.fetchPoolState(ctx, p, params, overrides)
+	if err != nil {
+		return p, err
+	}
+	if newState, err = d.transferTax.Track(ctx, newState); err != nil {
+		return p, err
+	}
+	return newState, nil
+}
+
+func (d *PoolTracker) fetchPoolState(
 	ctx context.Context,
 	p entity.Pool,
 	_ pool.GetNewPoolStateParams,
@@ -190,6 +211,18 @@ func (d *PoolTracker) updateStandardPool(
 		Method: poolMethodFeeRatio,
 		Params: []any{},
 	}, []any{&fee})
+	if d.config.ReserveSlots != nil && overrides == nil {
+		reserves, blockNumber, err := d.getReservesFromStorage(ctx, pool.Address)
+		if err != nil {
+			return entity.Pool{}, err
+		}
+		if _, err = req.SetBlockNumber(new(big.Int).SetUint64(blockNumber)).TryAggregate(); err != nil {
+			return entity.Pool{}, err
+		}
+
+		return d.updatePool(pool, reserves, velodromev2.PoolExtra{IsPaused: isPaused, Fee: fee.Uint64()}, blockNumber)
+	}
+
 	req.AddCall(&ethrpc.Call{
 		ABI:    poolABI,
 		Target: pool.Address,
@@ -215,6 +248,24 @@ func (d *PoolTracker) updateStandardPool(
 	return d.updatePool(pool, reserves, poolExtra, resp.BlockNumber.Uint64())
 }
 
+// getReservesFromStorage reads the reserves of the pool at address from the configured slots, proven against the
+// state root of the latest block.
+func (d *PoolTracker) getReservesFromStorage(ctx context.Context,
+	address string) (velodromev2.ReserveData, uint64, error) {
+	poolAddr := common.HexToAddress(address)
+	slot0, slot1 := common.BigToHash(new(big.Int).SetUint64(d.config.ReserveSlots.Reserve0)),
+		common.BigToHash(new(big.Int).SetUint64(d.config.ReserveSlots.Reserve1))
+	snapshot, err := utileth.ReadStorage(ctx, d.ethrpcClient.GetETHClient().Client(), nil,
+		[]utileth.StorageQuery{{Address: poolAddr, Slots: []common.Hash{slot0, slot1}}}, utileth.DefaultBatchRetry)
+	if err != nil {
+		return velodromev2.ReserveData{}, 0, fmt.Errorf("failed to read reserves from storage: %w", err)
+	}
+	return velodromev2.ReserveData{
+		Reserve0: snapshot.Get(poolAddr, slot0).Big(),
+		Reserve1: snapshot.Get(poolAddr, slot1).Big(),
+	}, snapshot.BlockNumber, nil
+}
+
 func (d *PoolTracker) updatePool(
 	pool entity.Pool,
 	reserveData velodromev2.ReserveData,
@@ -0,0 +1,40 @@
+package solidlyv2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/evmstate"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/testutil"
+)
+
+func TestPoolTracker_getReservesFromStorage(t *testing.T) {
+	t.Parallel()
+
+	poolAddr := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	node, client := testutil.NewEVMStateNode(t, &evmstate.State{
+		BlockNumber: 20,
+		Accounts: map[common.Address]*evmstate.Account{
+			poolAddr: {Storage: map[common.Hash]common.Hash{
+				{31: 10}: common.BigToHash(bignumber.NewBig("1000000000000000000")),
+				{31: 11}: {31: 42},
+			}},
+		},
+	})
+	tracker := &PoolTracker{
+		config:       &Config{ReserveSlots: &ReserveSlots{Reserve0: 10, Reserve1: 11}},
+		ethrpcClient: client,
+	}
+
+	reserves, blockNumber, err := tracker.getReservesFromStorage(context.Background(), poolAddr.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, "1000000000000000000", reserves.Reserve0.String())
+	assert.Equal(t, "42", reserves.Reserve1.String())
+	assert.Equal(t, uint64(20), blockNumber)
+	assert.Equal(t, map[string]int{"eth_getBlockByNumber": 1, "eth_getProof": 1}, node.Calls)
+}
@@ -18,6 +18,40 @@ var (
 	maxFee = uint256.MustFromHex("0x4000000000000000")
 )
 
+// ViewOriginSwap mirrors Curve.viewOriginSwap: it converts amountIn and both reserves into the numeraire using the
+// oracle rates (8 decimals), runs the curve trade, takes the epsilon fee and converts the result back into tokenOut.
+// decIn and decOut are 10^decimals of the tokens.
+func ViewOriginSwap(c *CurveParams, amountIn, reserveIn, reserveOut, decIn, decOut, rateIn,
+	rateOut *uint256.Int) (*uint256.Int, error) {
+	// Convert input to numeraire: (amountIn * inputOracleRate) / 1e8
+	amtInNumeraire, _ := new(uint256.Int).MulDivOverflow(amountIn, rateIn, OracleDecimals)
+	amtInNumeraire = divu(amtInNumeraire, decIn)
+
+	var tmp1, tmp2 uint256.Int
+	tmp1.MulDivOverflow(reserveIn, rateIn, OracleDecimals)
+	resInNumeraire := divu(&tmp1, decIn)
+
+	tmp2.MulDivOverflow(reserveOut, rateOut, OracleDecimals)
+	resOutNumeraire := divu(&tmp2, decOut)
+
+	// Use the Stabull curve formula with greek parameters
+	amtOutNumeraire, err := calculateTrade(amtInNumeraire, resInNumeraire, resOutNumeraire, c.Alpha, c.Beta, c.Delta,
+		c.Lambda)
+	if err != nil {
+		return nil, err
+	}
+	// Apply epsilon fee: result = result * (ONE - epsilon) / ONE
+	// In the contract: _amt = _amt.us_mul(ONE - curve.epsilon)
+	fee := tmp1.Sub(big256.U2Pow64, c.Epsilon)
+	amtOutNumeraire = usMul(amtOutNumeraire, fee)
+
+	amountOut := mulu(amtOutNumeraire, decOut)
+	// Convert output from numeraire to token decimals: (amountOutNumeraire * 1e8) / outputOracleRate
+	amountOut.MulDivOverflow(amountOut, OracleDecimals, rateOut)
+
+	return amountOut, nil
+}
+
 // calculateTrade implements the Stabull curve swap calculation
 // Based on CurveMath.sol from https://github.com/stabull/v1-amm/blob/dev/src/CurveMath.sol
 //
@@ -87,31 +87,11 @@ func (s *PoolSimulator) CalcAmountOut(param pool.CalcAmountOutParams) (*pool.Cal
 		return nil, errors.New("missing or invalid QuoteOracleRate for output token")
 	}
 
-	// Convert input to numeraire: (amountIn * inputOracleRate) / 1e8
-	amtInNumeraire, _ := amountIn.MulDivOverflow(amountIn, inputOracleRate, OracleDecimals)
-	amtInNumeraire = divu(amtInNumeraire, s.decs[indexIn])
-
-	var tmp1, tmp2 uint256.Int
-	tmp1.MulDivOverflow(reserveIn, inputOracleRate, OracleDecimals)
-	resInNumeraire := divu(&tmp1, s.decs[indexIn])
-
-	tmp2.MulDivOverflow(reserveOut, outputOracleRate, OracleDecimals)
-	resOutNumeraire := divu(&tmp2, s.decs[indexOut])
-
-	// Use the Stabull curve formula with greek parameters
-	amtOutNumeraire, err := calculateTrade(amtInNumeraire, resInNumeraire, resOutNumeraire, s.Alpha, s.Beta, s.Delta,
-		s.Lambda)
+	amountOut, err := ViewOriginSwap(&s.CurveParams, amountIn, reserveIn, reserveOut, s.decs[indexIn], s.decs[indexOut],
+		inputOracleRate, outputOracleRate)
 	if err != nil {
 		return nil, err
 	}
-	// Apply epsilon fee: result = result * (ONE - epsilon) / ONE
-	// In the contract: _amt = _amt.us_mul(ONE - curve.epsilon)
-	fee := tmp1.Sub(big256.U2Pow64, s.Epsilon)
-	amtOutNumeraire = usMul(amtOutNumeraire, fee)
-
-	amountOut := mulu(amtOutNumeraire, s.decs[indexOut])
-	// Convert output from numeraire to token decimals: (amountOutNumeraire * 1e8) / outputOracleRate
-	amountOut.MulDivOverflow(amountOut, OracleDecimals, outputOracleRate)
 
 	return &pool.CalcAmountOutResult{
 		TokenAmountOut: &pool.TokenAmount{Token: tokenOut, Amount: amountOut.ToBig()},
@@ -31,7 +31,9 @@ const (
 const (
 	OpenOrderStatus OrderStatus = "open"
 
-	DutchV2OrderType OrderType = "Dutch_V2"
+	DutchV2OrderType  OrderType = "Dutch_V2"
+	DutchV3OrderType  OrderType = "Dutch_V3"
+	PriorityOrderType OrderType = "Priority"
 
 	CreatedAtSortKey SortKey = "createdAt"
 
@@ -0,0 +1,26 @@
+package uniswapx
+
+import (
+	"github.com/holiman/uint256"
+)
+
+const (
+	DexType = "uniswapx"
+
+	// maxCurvePoints is the maximum number of points of a Dutch V3 decay curve, relative blocks being packed as 16
+	// uint16 in a uint256.
+	maxCurvePoints = 16
+
+	// BaseGas and GasPerOrder are the gas of executing a batch of orders through a reactor, as for uniswap-lo.
+	BaseGas     = 50000
+	GasPerOrder = 250000
+)
+
+var (
+	// bps is the denominator of exclusivity overrides.
+	bps = uint256.NewInt(10000)
+	// mps is the denominator of priority fee scaling, in milli-bps.
+	mps = uint256.NewInt(10000000)
+	// gwei is the unit of the base fee adjustments of Dutch V3 orders.
+	gwei = uint256.NewInt(1e9)
+)
@@ -0,0 +1,163 @@
+package uniswapx
+
+import (
+	"strings"
+
+	"github.com/KyberNetwork/int256"
+	v3Utils "github.com/KyberNetwork/uniswapv3-sdk-uint256/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
+)
+
+// linearDecay decays startAmount to endAmount as now goes from start to end, see DutchDecayLib.
+func linearDecay(startAmount, endAmount *uint256.Int, start, end, now uint64) (*uint256.Int, error) {
+	switch {
+	case end < start:
+		return nil, ErrEndTimeBeforeStart
+	case end <= now:
+		return endAmount.Clone(), nil
+	case start >= now:
+		return startAmount.Clone(), nil
+	}
+
+	elapsed, duration := uint256.NewInt(now-start), uint256.NewInt(end-start)
+	var diff, delta uint256.Int
+	if endAmount.Lt(startAmount) {
+		big256.MulDivDown(&delta, diff.Sub(startAmount, endAmount), elapsed, duration)
+		return delta.Sub(startAmount, &delta), nil
+	}
+	big256.MulDivDown(&delta, diff.Sub(endAmount, startAmount), elapsed, duration)
+	return delta.Add(startAmount, &delta), nil
+}
+
+// decay returns startAmount less the relative amount of the curve at block, bounded to [minAmount, maxAmount], see
+// NonlinearDutchDecayLib.
+func (c *Curve) decay(startAmount *uint256.Int, decayStartBlock, block uint64,
+	minAmount, maxAmount *uint256.Int) (*uint256.Int, error) {
+	if len(c.RelativeAmounts) > maxCurvePoints || len(c.RelativeBlocks) != len(c.RelativeAmounts) {
+		return nil, ErrInvalidDecayCurve
+	} else if decayStartBlock >= block || len(c.RelativeAmounts) == 0 {
+		return bound(startAmount.Clone(), minAmount, maxAmount), nil
+	}
+
+	blockDelta := block - decayStartBlock
+	startPoint, endPoint, relStart, relEnd := c.locate(blockDelta)
+	return boundedSub(startAmount, curveDecay(startPoint, endPoint, blockDelta, relStart, relEnd), minAmount,
+		maxAmount), nil
+}
+
+// locate returns the segment of the curve blockDelta falls in, the last point repeated past the end of the curve.
+func (c *Curve) locate(blockDelta uint64) (uint64, uint64, *int256.Int, *int256.Int) {
+	if uint64(c.RelativeBlocks[0]) >= blockDelta {
+		return 0, uint64(c.RelativeBlocks[0]), new(int256.Int), c.RelativeAmounts[0]
+	}
+	for i := 1; i < len(c.RelativeBlocks); i++ {
+		if uint64(c.RelativeBlocks[i]) >= blockDelta {
+			return uint64(c.RelativeBlocks[i-1]), uint64(c.RelativeBlocks[i]), c.RelativeAmounts[i-1],
+				c.RelativeAmounts[i]
+		}
+	}
+	last := len(c.RelativeBlocks) - 1
+	return uint64(c.RelativeBlocks[last]), uint64(c.RelativeBlocks[last]), c.RelativeAmounts[last],
+		c.RelativeAmounts[last]
+}
+
+// curveDecay interpolates the relative amount at current between startPoint and endPoint.
+func curveDecay(startPoint, endPoint, current uint64, startAmount, endAmount *int256.Int) *int256.Int {
+	if current >= endPoint {
+		return endAmount
+	}
+	diff := new(int256.Int).Sub(endAmount, startAmount)
+	negative := diff.IsNegative()
+	if negative {
+		diff.Neg(diff)
+	}
+	var delta uint256.Int
+	big256.MulDivDown(&delta, (*uint256.Int)(diff), uint256.NewInt(current-startPoint),
+		uint256.NewInt(endPoint-startPoint))
+	if negative {
+		return new(int256.Int).Sub(startAmount, (*int256.Int)(&delta))
+	}
+	return new(int256.Int).Add(startAmount, (*int256.Int)(&delta))
+}
+
+// boundedSub returns amount - delta bounded to [minAmount, maxAmount], saturating instead of overflowing.
+func boundedSub(amount *uint256.Int, delta *int256.Int, minAmount, maxAmount *uint256.Int) *uint256.Int {
+	var res uint256.Int
+	if delta.IsNegative() {
+		if _, overflow := res.AddOverflow(amount, (*uint256.Int)(new(int256.Int).Neg(delta))); overflow {
+			return maxAmount.Clone()
+		}
+	} else if _, underflow := res.SubOverflow(amount, (*uint256.Int)(delta)); underflow {
+		return minAmount.Clone()
+	}
+	return bound(&res, minAmount, maxAmount)
+}
+
+func bound(amount, minAmount, maxAmount *uint256.Int) *uint256.Int {
+	if amount.Lt(minAmount) {
+		return amount.Set(minAmount)
+	} else if amount.Gt(maxAmount) {
+		return amount.Set(maxAmount)
+	}
+	return amount
+}
+
+// baseFeeAdjustment returns the adjustment of an amount adjusted by adjustmentPerGwei per gwei of gasDeltaWei,
+// rounded towards negative infinity, see V3DutchOrderReactor.
+func baseFeeAdjustment(adjustmentPerGwei *uint256.Int, gasDeltaWei *int256.Int) *int256.Int {
+	var res uint256.Int
+	if !gasDeltaWei.IsNegative() {
+		big256.MulDivDown(&res, adjustmentPerGwei, (*uint256.Int)(gasDeltaWei), gwei)
+		return (*int256.Int)(&res)
+	}
+	big256.MulDivUp(&res, adjustmentPerGwei, (*uint256.Int)(new(int256.Int).Neg(gasDeltaWei)), gwei)
+	return new(int256.Int).Neg((*int256.Int)(&res))
+}
+
+// scaleInput scales amount down by mpsPerPriorityFeeWei per wei of priorityFee, see PriorityFeeLib.
+func scaleInput(amount, mpsPerPriorityFeeWei, priorityFee *uint256.Int) *uint256.Int {
+	if mpsPerPriorityFeeWei == nil {
+		return amount.Clone()
+	}
+	var scale uint256.Int
+	if _, overflow := scale.MulOverflow(priorityFee, mpsPerPriorityFeeWei); overflow || !scale.Lt(mps) {
+		return new(uint256.Int)
+	}
+	return big256.MulDivDown(new(uint256.Int), amount, scale.Sub(mps, &scale), mps)
+}
+
+// scaleOutput scales amount up by mpsPerPriorityFeeWei per wei of priorityFee, see PriorityFeeLib. It fails with
+// ErrAmountOverflow where the reactor reverts on an overflow.
+func scaleOutput(amount, mpsPerPriorityFeeWei, priorityFee *uint256.Int) (*uint256.Int, error) {
+	if mpsPerPriorityFeeWei == nil {
+		return amount.Clone(), nil
+	}
+	var scale, res uint256.Int
+	if _, overflow := scale.MulOverflow(priorityFee, mpsPerPriorityFeeWei); overflow {
+		return nil, ErrAmountOverflow
+	} else if _, overflow = scale.AddOverflow(&scale, mps); overflow {
+		return nil, ErrAmountOverflow
+	} else if err := v3Utils.MulDivRoundingUpV2(amount, &scale, mps, &res); err != nil {
+		return nil, ErrAmountOverflow
+	}
+	return &res, nil
+}
+
+// applyExclusivity raises outputs by overrideBps when filler fills an order still exclusive to another filler, see
+// ExclusivityLib.
+func applyExclusivity(outputs []TokenAmount, exclusiveFiller, filler string, exclusive bool,
+	overrideBps *uint256.Int) error {
+	if !exclusive || common.HexToAddress(exclusiveFiller) == (common.Address{}) || strings.EqualFold(exclusiveFiller, filler) {
+		return nil
+	} else if overrideBps == nil || overrideBps.IsZero() {
+		return ErrNoExclusiveOverride
+	}
+	factor := new(uint256.Int).Add(bps, overrideBps)
+	for i := range outputs {
+		outputs[i].Amount = big256.MulDivUp(new(uint256.Int), outputs[i].Amount, factor, bps)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package uniswapx
+
+import (
+	"testing"
+
+	"github.com/KyberNetwork/int256"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
+)
+
+func TestLinearDecay(t *testing.T) {
+	t.Parallel()
+	start, end := uint256.NewInt(1000), uint256.NewInt(900)
+	for _, tc := range []struct {
+		now  uint64
+		want uint64
+	}{
+		{now: 50, want: 1000},
+		{now: 100, want: 1000},
+		{now: 125, want: 975},
+		{now: 133, want: 967},
+		{now: 200, want: 900},
+		{now: 300, want: 900},
+	} {
+		got, err := linearDecay(start, end, 100, 200, tc.now)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, got.Uint64(), "now %d", tc.now)
+
+		// an increasing input decays symmetrically
+		got, err = linearDecay(end, start, 100, 200, tc.now)
+		require.NoError(t, err)
+		assert.Equal(t, 1900-tc.want, got.Uint64(), "now %d", tc.now)
+	}
+
+	_, err := linearDecay(start, end, 200, 100, 150)
+	assert.ErrorIs(t, err, ErrEndTimeBeforeStart)
+}
+
+func TestCurveDecay(t *testing.T) {
+	t.Parallel()
+	// -100 after 10 blocks, then -150 after 20 blocks
+	curve := Curve{
+		RelativeBlocks:  []uint16{10, 20},
+		RelativeAmounts: []*int256.Int{int256.NewInt(100), int256.NewInt(150)},
+	}
+	start := uint256.NewInt(1000)
+	for _, tc := range []struct {
+		block uint64
+		want  uint64
+	}{
+		{block: 90, want: 1000},
+		{block: 100, want: 1000},
+		{block: 105, want: 950},
+		{block: 110, want: 900},
+		{block: 115, want: 875},
+		{block: 120, want: 850},
+		{block: 500, want: 850},
+	} {
+		got, err := curve.decay(start, 100, tc.block, big256.U0, start)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, got.Uint64(), "block %d", tc.block)
+	}
+
+	got, err := curve.decay(start, 100, 120, uint256.NewInt(870), start)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(870), got.Uint64(), "bounded to the min amount")
+
+	// inputs decay upwards with negative relative amounts
+	up := Curve{RelativeBlocks: []uint16{10}, RelativeAmounts: []*int256.Int{int256.NewInt(-100)}}
+	got, err = up.decay(start, 100, 105, start, uint256.NewInt(1080))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1050), got.Uint64())
+	got, err = up.decay(start, 100, 200, start, uint256.NewInt(1080))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1080), got.Uint64(), "bounded to the max amount")
+
+	_, err = (&Curve{RelativeBlocks: []uint16{1}}).decay(start, 100, 105, big256.U0, start)
+	assert.ErrorIs(t, err, ErrInvalidDecayCurve)
+}
+
+func TestBaseFeeAdjustment(t *testing.T) {
+	t.Parallel()
+	adjustment := uint256.NewInt(3)
+	assert.Equal(t, "6", baseFeeAdjustment(adjustment, int256.NewInt(2e9)).Dec())
+	assert.Equal(t, "1", baseFeeAdjustment(adjustment, int256.NewInt(5e8)).Dec())
+	assert.Equal(t, "-2", baseFeeAdjustment(adjustment, int256.NewInt(-5e8)).Dec())
+}
+
+func TestPriorityScaling(t *testing.T) {
+	t.Parallel()
+	amount := uint256.NewInt(1e6)
+	// 100 mps per wei at 1000 wei is 1%
+	mpsPerWei, fee := uint256.NewInt(100), uint256.NewInt(1000)
+	assert.Equal(t, uint64(990000), scaleInput(amount, mpsPerWei, fee).Uint64())
+	scaled, err := scaleOutput(amount, mpsPerWei, fee)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1010000), scaled.Uint64())
+	_, err = scaleOutput(amount, mpsPerWei, new(uint256.Int).Lsh(big256.U1, 250))
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+	_, err = scaleOutput(big256.UMax, mpsPerWei, fee)
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+	assert.Equal(t, uint64(0), scaleInput(amount, mpsPerWei, uint256.NewInt(1e5)).Uint64())
+	assert.Equal(t, uint64(1e6), scaleInput(amount, big256.U0, fee).Uint64())
+}
+
+func TestApplyExclusivity(t *testing.T) {
+	t.Parallel()
+	const exclusive, other = "0x00000000000000000000000000000000000000aa", "0x00000000000000000000000000000000000000bb"
+	outputs := func() []TokenAmount { return []TokenAmount{{Amount: uint256.NewInt(1001)}} }
+
+	o := outputs()
+	require.NoError(t, applyExclusivity(o, exclusive, exclusive, true, big256.U0))
+	assert.Equal(t, uint64(1001), o[0].Amount.Uint64(), "the exclusive filler fills as is")
+
+	require.NoError(t, applyExclusivity(o, exclusive, other, false, big256.U0))
+	assert.Equal(t, uint64(1001), o[0].Amount.Uint64(), "anyone fills as is once exclusivity ends")
+
+	assert.ErrorIs(t, applyExclusivity(o, exclusive, other, true, big256.U0), ErrNoExclusiveOverride)
+
+	require.NoError(t, applyExclusivity(o, exclusive, other, true, uint256.NewInt(100)))
+	assert.Equal(t, uint64(1012), o[0].Amount.Uint64(), "others pay the override, rounded up")
+}
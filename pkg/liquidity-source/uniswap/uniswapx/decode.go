@@ -0,0 +1,393 @@
+package uniswapx
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/KyberNetwork/int256"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+
+	uniswaplo "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/lo"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
+)
+
+var orderInfoComponents = []abi.ArgumentMarshaling{
+	{Name: "reactor", Type: "address"},
+	{Name: "swapper", Type: "address"},
+	{Name: "nonce", Type: "uint256"},
+	{Name: "deadline", Type: "uint256"},
+	{Name: "additionalValidationContract", Type: "address"},
+	{Name: "additionalValidationData", Type: "bytes"},
+}
+
+var curveComponents = []abi.ArgumentMarshaling{
+	{Name: "relativeBlocks", Type: "uint256"},
+	{Name: "relativeAmounts", Type: "int256[]"},
+}
+
+// dutchV2OrderArguments decodes a V2DutchOrder.
+// reference: https://github.com/Uniswap/UniswapX/blob/main/src/lib/V2DutchOrderLib.sol
+var dutchV2OrderArguments = mustOrderArguments([]abi.ArgumentMarshaling{
+	{Name: "info", Type: "tuple", Components: orderInfoComponents},
+	{Name: "cosigner", Type: "address"},
+	{Name: "baseInput", Type: "tuple", Components: []abi.ArgumentMarshaling{
+		{Name: "token", Type: "address"},
+		{Name: "startAmount", Type: "uint256"},
+		{Name: "endAmount", Type: "uint256"},
+	}},
+	{Name: "baseOutputs", Type: "tuple[]", Components: []abi.ArgumentMarshaling{
+		{Name: "token", Type: "address"},
+		{Name: "startAmount", Type: "uint256"},
+		{Name: "endAmount", Type: "uint256"},
+		{Name: "recipient", Type: "address"},
+	}},
+	{Name: "cosignerData", Type: "tuple", Components: []abi.ArgumentMarshaling{
+		{Name: "decayStartTime", Type: "uint256"},
+		{Name: "decayEndTime", Type: "uint256"},
+		{Name: "exclusiveFiller", Type: "address"},
+		{Name: "exclusivityOverrideBps", Type: "uint256"},
+		{Name: "inputOverride", Type: "uint256"},
+		{Name: "outputOverrides", Type: "uint256[]"},
+	}},
+	{Name: "cosignature", Type: "bytes"},
+})
+
+// dutchV3OrderArguments decodes a V3DutchOrder.
+// reference: https://github.com/Uniswap/UniswapX/blob/main/src/lib/V3DutchOrderLib.sol
+var dutchV3OrderArguments = mustOrderArguments([]abi.ArgumentMarshaling{
+	{Name: "info", Type: "tuple", Components: orderInfoComponents},
+	{Name: "cosigner", Type: "address"},
+	{Name: "startingBaseFee", Type: "uint256"},
+	{Name: "baseInput", Type: "tuple", Components: []abi.ArgumentMarshaling{
+		{Name: "token", Type: "address"},
+		{Name: "startAmount", Type: "uint256"},
+		{Name: "curve", Type: "tuple", Components: curveComponents},
+		{Name: "maxAmount", Type: "uint256"},
+		{Name: "adjustmentPerGweiBaseFee", Type: "uint256"},
+	}},
+	{Name: "baseOutputs", Type: "tuple[]", Components: []abi.ArgumentMarshaling{
+		{Name: "token", Type: "address"},
+		{Name: "startAmount", Type: "uint256"},
+		{Name: "curve", Type: "tuple", Components: curveComponents},
+		{Name: "recipient", Type: "address"},
+		{Name: "minAmount", Type: "uint256"},
+		{Name: "adjustmentPerGweiBaseFee", Type: "uint256"},
+	}},
+	{Name: "cosignerData", Type: "tuple", Components: []abi.ArgumentMarshaling{
+		{Name: "decayStartBlock", Type: "uint256"},
+		{Name: "exclusiveFiller", Type: "address"},
+		{Name: "exclusivityOverrideBps", Type: "uint256"},
+		{Name: "inputOverride", Type: "uint256"},
+		{Name: "outputOverrides", Type: "uint256[]"},
+	}},
+	{Name: "cosignature", Type: "bytes"},
+})
+
+// priorityOrderArguments decodes a PriorityOrder.
+// reference: https://github.com/Uniswap/UniswapX/blob/main/src/lib/PriorityOrderLib.sol
+var priorityOrderArguments = mustOrderArguments([]abi.ArgumentMarshaling{
+	{Name: "info", Type: "tuple", Components: orderInfoComponents},
+	{Name: "cosigner", Type: "address"},
+	{Name: "auctionStartBlock", Type: "uint256"},
+	{Name: "baselinePriorityFeeWei", Type: "uint256"},
+	{Name: "input", Type: "tuple", Components: []abi.ArgumentMarshaling{
+		{Name: "token", Type: "address"},
+		{Name: "amount", Type: "uint256"},
+		{Name: "mpsPerPriorityFeeWei", Type: "uint256"},
+	}},
+	{Name: "outputs", Type: "tuple[]", Components: []abi.ArgumentMarshaling{
+		{Name: "token", Type: "address"},
+		{Name: "amount", Type: "uint256"},
+		{Name: "mpsPerPriorityFeeWei", Type: "uint256"},
+		{Name: "recipient", Type: "address"},
+	}},
+	{Name: "cosignerData", Type: "tuple", Components: []abi.ArgumentMarshaling{
+		{Name: "auctionTargetBlock", Type: "uint256"},
+	}},
+	{Name: "cosignature", Type: "bytes"},
+})
+
+func mustOrderArguments(components []abi.ArgumentMarshaling) abi.Arguments {
+	orderTuple, err := abi.NewType("tuple", "", components)
+	if err != nil {
+		panic(err)
+	}
+	return abi.Arguments{{Type: orderTuple}}
+}
+
+type orderInfo struct {
+	Reactor                      common.Address
+	Swapper                      common.Address
+	Nonce                        *big.Int
+	Deadline                     *big.Int
+	AdditionalValidationContract common.Address
+	AdditionalValidationData     []byte
+}
+
+type abiCurve struct {
+	RelativeBlocks  *big.Int
+	RelativeAmounts []*big.Int
+}
+
+type abiDutchV2Order struct {
+	Info      orderInfo
+	Cosigner  common.Address
+	BaseInput struct {
+		Token       common.Address
+		StartAmount *big.Int
+		EndAmount   *big.Int
+	}
+	BaseOutputs []struct {
+		Token       common.Address
+		StartAmount *big.Int
+		EndAmount   *big.Int
+		Recipient   common.Address
+	}
+	CosignerData struct {
+		DecayStartTime         *big.Int
+		DecayEndTime           *big.Int
+		ExclusiveFiller        common.Address
+		ExclusivityOverrideBps *big.Int
+		InputOverride          *big.Int
+		OutputOverrides        []*big.Int
+	}
+	Cosignature []byte
+}
+
+type abiDutchV3Order struct {
+	Info            orderInfo
+	Cosigner        common.Address
+	StartingBaseFee *big.Int
+	BaseInput       struct {
+		Token                    common.Address
+		StartAmount              *big.Int
+		Curve                    abiCurve
+		MaxAmount                *big.Int
+		AdjustmentPerGweiBaseFee *big.Int
+	}
+	BaseOutputs []struct {
+		Token                    common.Address
+		StartAmount              *big.Int
+		Curve                    abiCurve
+		Recipient                common.Address
+		MinAmount                *big.Int
+		AdjustmentPerGweiBaseFee *big.Int
+	}
+	CosignerData struct {
+		DecayStartBlock        *big.Int
+		ExclusiveFiller        common.Address
+		ExclusivityOverrideBps *big.Int
+		InputOverride          *big.Int
+		OutputOverrides        []*big.Int
+	}
+	Cosignature []byte
+}
+
+type abiPriorityOrder struct {
+	Info                   orderInfo
+	Cosigner               common.Address
+	AuctionStartBlock      *big.Int
+	BaselinePriorityFeeWei *big.Int
+	Input                  struct {
+		Token                common.Address
+		Amount               *big.Int
+		MpsPerPriorityFeeWei *big.Int
+	}
+	Outputs []struct {
+		Token                common.Address
+		Amount               *big.Int
+		MpsPerPriorityFeeWei *big.Int
+		Recipient            common.Address
+	}
+	CosignerData struct {
+		AuctionTargetBlock *big.Int
+	}
+	Cosignature []byte
+}
+
+// NewOrder decodes an order fetched with uniswaplo.UniSwapXClient.
+func NewOrder(o *uniswaplo.DutchOrder) (*Order, error) {
+	order, err := DecodeOrder(uniswaplo.OrderType(o.Type), o.EncodedOrder)
+	if err != nil {
+		return nil, fmt.Errorf("order %s: %w", o.OrderHash, err)
+	}
+	order.Hash = o.OrderHash
+	order.Signature = o.Signature
+	return order, nil
+}
+
+// DecodeOrder decodes an ABI-encoded order of orderType.
+func DecodeOrder(orderType uniswaplo.OrderType, encoded []byte) (*Order, error) {
+	var order *Order
+	var err error
+	switch orderType {
+	case uniswaplo.DutchV2OrderType:
+		order, err = decodeDutchV2Order(encoded)
+	case uniswaplo.DutchV3OrderType:
+		order, err = decodeDutchV3Order(encoded)
+	case uniswaplo.PriorityOrderType:
+		order, err = decodePriorityOrder(encoded)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownOrderType, orderType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	order.Type = orderType
+	order.Encoded = encoded
+	return order, nil
+}
+
+func unpackOrder[T any](arguments abi.Arguments, encoded []byte) (*T, error) {
+	values, err := arguments.Unpack(encoded)
+	if err != nil {
+		return nil, err
+	}
+	var input struct{ Order T }
+	if err = arguments.Copy(&input, values); err != nil {
+		return nil, err
+	}
+	return &input.Order, nil
+}
+
+func decodeDutchV2Order(encoded []byte) (*Order, error) {
+	o, err := unpackOrder[abiDutchV2Order](dutchV2OrderArguments, encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	order := newOrder(o.Info)
+	order.DutchV2 = &DutchV2Order{
+		Input: DutchInput{
+			Token:       toLowerHex(o.BaseInput.Token),
+			StartAmount: uint256.MustFromBig(o.BaseInput.StartAmount),
+			EndAmount:   uint256.MustFromBig(o.BaseInput.EndAmount),
+		},
+		Outputs: make([]DutchOutput, len(o.BaseOutputs)),
+		CosignerData: DutchV2CosignerData{
+			DecayStartTime:         o.CosignerData.DecayStartTime.Uint64(),
+			DecayEndTime:           o.CosignerData.DecayEndTime.Uint64(),
+			ExclusiveFiller:        toLowerHex(o.CosignerData.ExclusiveFiller),
+			ExclusivityOverrideBps: uint256.MustFromBig(o.CosignerData.ExclusivityOverrideBps),
+			InputOverride:          uint256.MustFromBig(o.CosignerData.InputOverride),
+			OutputOverrides:        big256.MustFromBigs(o.CosignerData.OutputOverrides),
+		},
+	}
+	for i, output := range o.BaseOutputs {
+		order.DutchV2.Outputs[i] = DutchOutput{
+			Token:       toLowerHex(output.Token),
+			StartAmount: uint256.MustFromBig(output.StartAmount),
+			EndAmount:   uint256.MustFromBig(output.EndAmount),
+			Recipient:   toLowerHex(output.Recipient),
+		}
+	}
+	return order, nil
+}
+
+func decodeDutchV3Order(encoded []byte) (*Order, error) {
+	o, err := unpackOrder[abiDutchV3Order](dutchV3OrderArguments, encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	inputCurve, err := newCurve(o.BaseInput.Curve)
+	if err != nil {
+		return nil, err
+	}
+	order := newOrder(o.Info)
+	order.DutchV3 = &DutchV3Order{
+		StartingBaseFee: uint256.MustFromBig(o.StartingBaseFee),
+		Input: DutchV3Input{
+			Token:                    toLowerHex(o.BaseInput.Token),
+			StartAmount:              uint256.MustFromBig(o.BaseInput.StartAmount),
+			Curve:                    inputCurve,
+			MaxAmount:                uint256.MustFromBig(o.BaseInput.MaxAmount),
+			AdjustmentPerGweiBaseFee: uint256.MustFromBig(o.BaseInput.AdjustmentPerGweiBaseFee),
+		},
+		Outputs: make([]DutchV3Output, len(o.BaseOutputs)),
+		CosignerData: DutchV3CosignerData{
+			DecayStartBlock:        o.CosignerData.DecayStartBlock.Uint64(),
+			ExclusiveFiller:        toLowerHex(o.CosignerData.ExclusiveFiller),
+			ExclusivityOverrideBps: uint256.MustFromBig(o.CosignerData.ExclusivityOverrideBps),
+			InputOverride:          uint256.MustFromBig(o.CosignerData.InputOverride),
+			OutputOverrides:        big256.MustFromBigs(o.CosignerData.OutputOverrides),
+		},
+	}
+	for i, output := range o.BaseOutputs {
+		curve, err := newCurve(output.Curve)
+		if err != nil {
+			return nil, err
+		}
+		order.DutchV3.Outputs[i] = DutchV3Output{
+			Token:                    toLowerHex(output.Token),
+			StartAmount:              uint256.MustFromBig(output.StartAmount),
+			Curve:                    curve,
+			Recipient:                toLowerHex(output.Recipient),
+			MinAmount:                uint256.MustFromBig(output.MinAmount),
+			AdjustmentPerGweiBaseFee: uint256.MustFromBig(output.AdjustmentPerGweiBaseFee),
+		}
+	}
+	return order, nil
+}
+
+func decodePriorityOrder(encoded []byte) (*Order, error) {
+	o, err := unpackOrder[abiPriorityOrder](priorityOrderArguments, encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	order := newOrder(o.Info)
+	order.Priority = &PriorityOrder{
+		AuctionStartBlock:      o.AuctionStartBlock.Uint64(),
+		BaselinePriorityFeeWei: uint256.MustFromBig(o.BaselinePriorityFeeWei),
+		Input: PriorityInput{
+			Token:                toLowerHex(o.Input.Token),
+			Amount:               uint256.MustFromBig(o.Input.Amount),
+			MpsPerPriorityFeeWei: uint256.MustFromBig(o.Input.MpsPerPriorityFeeWei),
+		},
+		Outputs:            make([]PriorityOutput, len(o.Outputs)),
+		AuctionTargetBlock: o.CosignerData.AuctionTargetBlock.Uint64(),
+	}
+	for i, output := range o.Outputs {
+		order.Priority.Outputs[i] = PriorityOutput{
+			Token:                toLowerHex(output.Token),
+			Amount:               uint256.MustFromBig(output.Amount),
+			MpsPerPriorityFeeWei: uint256.MustFromBig(output.MpsPerPriorityFeeWei),
+			Recipient:            toLowerHex(output.Recipient),
+		}
+	}
+	return order, nil
+}
+
+func newOrder(info orderInfo) *Order {
+	return &Order{
+		Reactor:  toLowerHex(info.Reactor),
+		Swapper:  toLowerHex(info.Swapper),
+		Deadline: info.Deadline.Uint64(),
+	}
+}
+
+// newCurve unpacks the relative blocks of curve, packed as uint16 from the lowest bits.
+func newCurve(curve abiCurve) (Curve, error) {
+	if len(curve.RelativeAmounts) > maxCurvePoints {
+		return Curve{}, ErrInvalidDecayCurve
+	}
+	c := Curve{
+		RelativeBlocks:  make([]uint16, len(curve.RelativeAmounts)),
+		RelativeAmounts: make([]*int256.Int, len(curve.RelativeAmounts)),
+	}
+	relativeBlocks := uint256.MustFromBig(curve.RelativeBlocks)
+	for i, amount := range curve.RelativeAmounts {
+		var block uint256.Int
+		c.RelativeBlocks[i] = uint16(block.Rsh(relativeBlocks, uint(16*i)).Uint64())
+		c.RelativeAmounts[i] = int256.MustFromBig(amount)
+	}
+	return c, nil
+}
+
+func toLowerHex(addr common.Address) string {
+	return strings.ToLower(addr.Hex())
+}
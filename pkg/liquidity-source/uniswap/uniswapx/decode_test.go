@@ -0,0 +1,85 @@
+package uniswapx
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	uniswaplo "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/lo"
+)
+
+var (
+	testReactor = common.HexToAddress("0x00000011F84B9aa48e5f8aA8B9897600006289Be")
+	testSwapper = common.HexToAddress("0x00000000000000000000000000000000000000A1")
+	testUSDC    = common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	testWETH    = common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+)
+
+func TestDecodeOrder_DutchV3(t *testing.T) {
+	t.Parallel()
+	var o abiDutchV3Order
+	o.Info = orderInfo{Reactor: testReactor, Swapper: testSwapper, Nonce: big.NewInt(7), Deadline: big.NewInt(1e9)}
+	o.StartingBaseFee = big.NewInt(2e9)
+	o.BaseInput.Token = testUSDC
+	o.BaseInput.StartAmount = big.NewInt(1000e6)
+	o.BaseInput.Curve = abiCurve{RelativeBlocks: big.NewInt(0), RelativeAmounts: []*big.Int{}}
+	o.BaseInput.MaxAmount = big.NewInt(1000e6)
+	o.BaseInput.AdjustmentPerGweiBaseFee = big.NewInt(0)
+	o.BaseOutputs = append(o.BaseOutputs, struct {
+		Token                    common.Address
+		StartAmount              *big.Int
+		Curve                    abiCurve
+		Recipient                common.Address
+		MinAmount                *big.Int
+		AdjustmentPerGweiBaseFee *big.Int
+	}{
+		Token:       testWETH,
+		StartAmount: big.NewInt(5e17),
+		Curve: abiCurve{
+			RelativeBlocks:  big.NewInt(10 | 20<<16),
+			RelativeAmounts: []*big.Int{big.NewInt(1e16), big.NewInt(-2e15)},
+		},
+		Recipient:                testSwapper,
+		MinAmount:                big.NewInt(4e17),
+		AdjustmentPerGweiBaseFee: big.NewInt(1e12),
+	})
+	o.CosignerData.DecayStartBlock = big.NewInt(100)
+	o.CosignerData.ExclusivityOverrideBps = big.NewInt(0)
+	o.CosignerData.InputOverride = big.NewInt(0)
+	o.CosignerData.OutputOverrides = []*big.Int{big.NewInt(6e17)}
+	o.Cosignature = []byte{}
+	o.Info.AdditionalValidationData = []byte{}
+	encoded, err := dutchV3OrderArguments.Pack(o)
+	require.NoError(t, err)
+
+	order, err := NewOrder(&uniswaplo.DutchOrder{
+		Type:         string(uniswaplo.DutchV3OrderType),
+		OrderHash:    "0xabc",
+		EncodedOrder: encoded,
+		Signature:    []byte{1},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "0xabc", order.Hash)
+	assert.Equal(t, uniswaplo.DutchV3OrderType, order.Type)
+	assert.Equal(t, "0x00000011f84b9aa48e5f8aa8b9897600006289be", order.Reactor)
+	assert.Equal(t, "0x00000000000000000000000000000000000000a1", order.Swapper)
+	assert.Equal(t, uint64(1e9), order.Deadline)
+	assert.Equal(t, "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", order.InputToken())
+	require.NotNil(t, order.DutchV3)
+	output := order.DutchV3.Outputs[0]
+	assert.Equal(t, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", output.Token)
+	assert.Equal(t, []uint16{10, 20}, output.Curve.RelativeBlocks)
+	assert.Equal(t, "10000000000000000", output.Curve.RelativeAmounts[0].Dec())
+	assert.Equal(t, "-2000000000000000", output.Curve.RelativeAmounts[1].Dec())
+	assert.Equal(t, uint64(100), order.DutchV3.CosignerData.DecayStartBlock)
+	assert.Equal(t, "600000000000000000", order.DutchV3.CosignerData.OutputOverrides[0].Dec())
+
+	_, err = DecodeOrder("Dutch", encoded)
+	assert.ErrorIs(t, err, ErrUnknownOrderType)
+	_, err = DecodeOrder(uniswaplo.PriorityOrderType, encoded[:100])
+	assert.Error(t, err)
+}
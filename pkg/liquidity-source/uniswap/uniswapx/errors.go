@@ -0,0 +1,21 @@
+package uniswapx
+
+import "errors"
+
+var (
+	ErrTokenNotSupported     = errors.New("token is not supported")
+	ErrCannotFulfillAmountIn = errors.New("cannot fulfill amountIn")
+	ErrUnknownOrderType      = errors.New("unknown order type")
+	ErrBlockNotSet           = errors.New("block to resolve orders at is not set")
+
+	ErrDeadlinePassed        = errors.New("order deadline passed")
+	ErrDeadlineBeforeEndTime = errors.New("order deadline before decay end time")
+	ErrEndTimeBeforeStart    = errors.New("order decay ends before it starts")
+	ErrInvalidDecayCurve     = errors.New("invalid decay curve")
+	ErrInvalidCosignerInput  = errors.New("invalid cosigner input override")
+	ErrInvalidCosignerOutput = errors.New("invalid cosigner output override")
+	ErrNoExclusiveOverride   = errors.New("order is exclusive to another filler")
+	ErrOrderNotFillable      = errors.New("order auction has not started")
+	ErrMixedOutputTokens     = errors.New("order outputs several tokens")
+	ErrAmountOverflow        = errors.New("order amount overflows")
+)
@@ -0,0 +1,176 @@
+package uniswapx
+
+import (
+	"github.com/KyberNetwork/int256"
+	"github.com/holiman/uint256"
+
+	uniswaplo "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/lo"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
+)
+
+// Resolve resolves the order as filled by filler in block, as its reactor does, failing where the reactor reverts.
+// Cosignatures are not verified: the orders are served cosigned by the UniswapX API.
+func (o *Order) Resolve(block Block, filler string) (*ResolvedOrder, error) {
+	if block.Timestamp > o.Deadline {
+		return nil, ErrDeadlinePassed
+	}
+	switch {
+	case o.Type == uniswaplo.DutchV2OrderType && o.DutchV2 != nil:
+		return o.DutchV2.resolve(block, o.Deadline, filler)
+	case o.Type == uniswaplo.DutchV3OrderType && o.DutchV3 != nil:
+		return o.DutchV3.resolve(block, filler)
+	case o.Type == uniswaplo.PriorityOrderType && o.Priority != nil:
+		return o.Priority.resolve(block)
+	}
+	return nil, ErrUnknownOrderType
+}
+
+// InputToken returns the token the swapper pays.
+func (o *Order) InputToken() string {
+	switch {
+	case o.DutchV2 != nil:
+		return o.DutchV2.Input.Token
+	case o.DutchV3 != nil:
+		return o.DutchV3.Input.Token
+	case o.Priority != nil:
+		return o.Priority.Input.Token
+	}
+	return ""
+}
+
+func (o *DutchV2Order) resolve(block Block, deadline uint64, filler string) (*ResolvedOrder, error) {
+	c := &o.CosignerData
+	if deadline < c.DecayEndTime {
+		return nil, ErrDeadlineBeforeEndTime
+	}
+
+	inputStart, err := overrideInput(o.Input.StartAmount, c.InputOverride)
+	if err != nil {
+		return nil, err
+	} else if len(c.OutputOverrides) != 0 && len(c.OutputOverrides) != len(o.Outputs) {
+		return nil, ErrInvalidCosignerOutput
+	}
+	input, err := linearDecay(inputStart, o.Input.EndAmount, c.DecayStartTime, c.DecayEndTime, block.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := &ResolvedOrder{
+		Input:   TokenAmount{Token: o.Input.Token, Amount: input},
+		Outputs: make([]TokenAmount, len(o.Outputs)),
+	}
+	for i, output := range o.Outputs {
+		start, err := overrideOutput(output.StartAmount, c.OutputOverrides, i)
+		if err != nil {
+			return nil, err
+		}
+		amount, err := linearDecay(start, output.EndAmount, c.DecayStartTime, c.DecayEndTime, block.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		resolved.Outputs[i] = TokenAmount{Token: output.Token, Amount: amount}
+	}
+
+	return resolved, applyExclusivity(resolved.Outputs, c.ExclusiveFiller, filler,
+		block.Timestamp <= c.DecayStartTime, c.ExclusivityOverrideBps)
+}
+
+func (o *DutchV3Order) resolve(block Block, filler string) (*ResolvedOrder, error) {
+	c := &o.CosignerData
+	inputStart, err := overrideInput(o.Input.StartAmount, c.InputOverride)
+	if err != nil {
+		return nil, err
+	} else if len(c.OutputOverrides) != 0 && len(c.OutputOverrides) != len(o.Outputs) {
+		return nil, ErrInvalidCosignerOutput
+	}
+
+	var gasDeltaWei *int256.Int
+	if block.BaseFee != nil && o.StartingBaseFee != nil {
+		gasDeltaWei = new(int256.Int).Sub((*int256.Int)(block.BaseFee), (*int256.Int)(o.StartingBaseFee))
+	}
+
+	// a higher base fee raises the input...
+	if gasDeltaWei != nil && o.Input.AdjustmentPerGweiBaseFee != nil && !o.Input.AdjustmentPerGweiBaseFee.IsZero() {
+		inputStart = boundedSub(inputStart, new(int256.Int).Neg(baseFeeAdjustment(o.Input.AdjustmentPerGweiBaseFee,
+			gasDeltaWei)), big256.U0, o.Input.MaxAmount)
+	}
+	input, err := o.Input.Curve.decay(inputStart, c.DecayStartBlock, block.Number, inputStart, o.Input.MaxAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := &ResolvedOrder{
+		Input:   TokenAmount{Token: o.Input.Token, Amount: input},
+		Outputs: make([]TokenAmount, len(o.Outputs)),
+	}
+	for i, output := range o.Outputs {
+		start, err := overrideOutput(output.StartAmount, c.OutputOverrides, i)
+		if err != nil {
+			return nil, err
+		}
+		// ...and lowers the outputs
+		if gasDeltaWei != nil && output.AdjustmentPerGweiBaseFee != nil && !output.AdjustmentPerGweiBaseFee.IsZero() {
+			start = boundedSub(start, baseFeeAdjustment(output.AdjustmentPerGweiBaseFee, gasDeltaWei),
+				output.MinAmount, big256.UMax)
+		}
+		amount, err := output.Curve.decay(start, c.DecayStartBlock, block.Number, output.MinAmount, start)
+		if err != nil {
+			return nil, err
+		}
+		resolved.Outputs[i] = TokenAmount{Token: output.Token, Amount: amount}
+	}
+
+	return resolved, applyExclusivity(resolved.Outputs, c.ExclusiveFiller, filler,
+		block.Number <= c.DecayStartBlock, c.ExclusivityOverrideBps)
+}
+
+func (o *PriorityOrder) resolve(block Block) (*ResolvedOrder, error) {
+	auctionStartBlock := o.AuctionStartBlock
+	if o.AuctionTargetBlock != 0 && o.AuctionTargetBlock < auctionStartBlock {
+		auctionStartBlock = o.AuctionTargetBlock
+	}
+	if block.Number < auctionStartBlock {
+		return nil, ErrOrderNotFillable
+	}
+
+	var priorityFee uint256.Int
+	if block.PriorityFee != nil && o.BaselinePriorityFeeWei != nil && block.PriorityFee.Gt(o.BaselinePriorityFeeWei) {
+		priorityFee.Sub(block.PriorityFee, o.BaselinePriorityFeeWei)
+	}
+
+	resolved := &ResolvedOrder{
+		Input: TokenAmount{
+			Token:  o.Input.Token,
+			Amount: scaleInput(o.Input.Amount, o.Input.MpsPerPriorityFeeWei, &priorityFee),
+		},
+		Outputs: make([]TokenAmount, len(o.Outputs)),
+	}
+	for i, output := range o.Outputs {
+		amount, err := scaleOutput(output.Amount, output.MpsPerPriorityFeeWei, &priorityFee)
+		if err != nil {
+			return nil, err
+		}
+		resolved.Outputs[i] = TokenAmount{Token: output.Token, Amount: amount}
+	}
+	return resolved, nil
+}
+
+// overrideInput returns the cosigned input amount, which may only improve on the signed one.
+func overrideInput(startAmount, override *uint256.Int) (*uint256.Int, error) {
+	if override == nil || override.IsZero() {
+		return startAmount, nil
+	} else if override.Gt(startAmount) {
+		return nil, ErrInvalidCosignerInput
+	}
+	return override, nil
+}
+
+// overrideOutput returns the cosigned amount of the i-th output, which may only improve on the signed one.
+func overrideOutput(startAmount *uint256.Int, overrides []*uint256.Int, i int) (*uint256.Int, error) {
+	if len(overrides) == 0 || overrides[i] == nil || overrides[i].IsZero() {
+		return startAmount, nil
+	} else if overrides[i].Lt(startAmount) {
+		return nil, ErrInvalidCosignerOutput
+	}
+	return overrides[i], nil
+}
@@ -0,0 +1,278 @@
+package uniswapx
+
+import (
+	"fmt"
+	"maps"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/KyberNetwork/blockchain-toolkit/integer"
+	"github.com/KyberNetwork/logger"
+	"github.com/goccy/go-json"
+	"github.com/holiman/uint256"
+	"github.com/samber/lo"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// PoolSimulator fills the UniswapX orders between two tokens. Orders are filled in full or not at all, so a swap fills
+// the best orders fitting its amount in and leaves the rest of it unswapped.
+type PoolSimulator struct {
+	pool.Pool
+
+	filler string
+	// orders[i] are the orders paying tokens[i]
+	orders [2][]*Order
+	// filled are the hashes of the orders filled by previous swaps
+	filled map[string]struct{}
+	// balances are min(balance, permit2 allowance) of the input tokens of swappers, keyed by SwapperKey
+	balances map[string]*uint256.Int
+	// block is the block orders are resolved at, from Extra.Block or SetBlock
+	block *Block
+}
+
+var _ = pool.RegisterFactory0(DexType, NewPoolSimulator)
+var _ = pool.RegisterUseSwapLimit(DexType)
+
+func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
+	if len(entityPool.Tokens) != 2 {
+		return nil, fmt.Errorf("pool's number of tokens should equal 2")
+	}
+
+	var staticExtra StaticExtra
+	if err := json.Unmarshal([]byte(entityPool.StaticExtra), &staticExtra); err != nil {
+		return nil, err
+	}
+	var extra Extra
+	if err := json.Unmarshal([]byte(entityPool.Extra), &extra); err != nil {
+		return nil, err
+	}
+
+	tokens := lo.Map(entityPool.Tokens, func(t *entity.PoolToken, _ int) string { return t.Address })
+	var orders [2][]*Order
+	for _, order := range extra.Orders {
+		if i := slices.Index(tokens, order.InputToken()); i >= 0 {
+			orders[i] = append(orders[i], order)
+		}
+	}
+
+	return &PoolSimulator{
+		Pool: pool.Pool{
+			Info: pool.PoolInfo{
+				Address:     strings.ToLower(entityPool.Address),
+				SwapFee:     integer.Zero(),
+				Exchange:    entityPool.Exchange,
+				Type:        entityPool.Type,
+				Tokens:      tokens,
+				Reserves:    lo.Map(entityPool.Reserves, func(r string, _ int) *big.Int { return bignumber.NewBig10(r) }),
+				BlockNumber: entityPool.BlockNumber,
+			},
+		},
+		filler:   strings.ToLower(staticExtra.Filler),
+		orders:   orders,
+		filled:   make(map[string]struct{}),
+		balances: extra.Balances,
+		block:    extra.Block,
+	}, nil
+}
+
+// SetBlock sets the block orders are resolved at, overriding Extra.Block. Orders decay with its timestamp and number, so
+// swaps are deterministic, e.g. for backtests. Swaps fail with ErrBlockNotSet until a block is set.
+func (p *PoolSimulator) SetBlock(block Block) {
+	p.block = &block
+}
+
+// Block returns the block orders are resolved at, if set.
+func (p *PoolSimulator) Block() (Block, bool) {
+	if p.block == nil {
+		return Block{}, false
+	}
+	return *p.block, true
+}
+
+// SwapperKey returns the key of the balance of token of swapper, in Extra.Balances and in the SwapLimit.
+func SwapperKey(swapper, token string) string {
+	return swapper + ":" + token
+}
+
+// fill is an order resolved for a swap: the filler pays amountIn to its outputs and receives amountOut of its input.
+type fill struct {
+	order     *Order
+	amountIn  *uint256.Int
+	amountOut *uint256.Int
+}
+
+func (p *PoolSimulator) CalcAmountOut(param pool.CalcAmountOutParams) (*pool.CalcAmountOutResult, error) {
+	tokenIn, tokenOut := param.TokenAmountIn.Token, param.TokenOut
+	indexIn, indexOut := p.GetTokenIndex(tokenIn), p.GetTokenIndex(tokenOut)
+	if indexIn < 0 || indexOut < 0 || indexIn == indexOut {
+		return nil, ErrTokenNotSupported
+	}
+	amountIn, overflow := uint256.FromBig(param.TokenAmountIn.Amount)
+	if overflow {
+		return nil, ErrCannotFulfillAmountIn
+	}
+
+	block, ok := p.Block()
+	if !ok {
+		return nil, ErrBlockNotSet
+	}
+	fills := p.resolveFills(p.orders[indexOut], tokenIn, block)
+
+	remaining := amountIn.Clone()
+	amountOut := new(uint256.Int)
+	swapInfo := SwapInfo{Block: block}
+	// the balances swappers spend in this swap, to not count the same balance for several of their orders
+	spent := make(map[string]*uint256.Int)
+	for _, f := range fills {
+		if f.amountIn.Gt(remaining) {
+			continue
+		}
+		key := SwapperKey(f.order.Swapper, tokenOut)
+		if spent[key] == nil {
+			spent[key] = new(uint256.Int)
+		}
+		if available := p.availableBalance(param.Limit, key); available != nil &&
+			available.Lt(new(uint256.Int).Add(spent[key], f.amountOut)) {
+			continue
+		}
+
+		remaining.Sub(remaining, f.amountIn)
+		amountOut.Add(amountOut, f.amountOut)
+		spent[key].Add(spent[key], f.amountOut)
+		swapInfo.FilledOrders = append(swapInfo.FilledOrders, &FilledOrder{
+			Hash:      f.order.Hash,
+			Type:      f.order.Type,
+			Reactor:   f.order.Reactor,
+			Swapper:   f.order.Swapper,
+			Encoded:   f.order.Encoded,
+			Signature: f.order.Signature,
+			AmountIn:  f.amountIn,
+			AmountOut: f.amountOut,
+		})
+		if remaining.IsZero() {
+			break
+		}
+	}
+
+	if len(swapInfo.FilledOrders) == 0 {
+		return nil, ErrCannotFulfillAmountIn
+	}
+
+	return &pool.CalcAmountOutResult{
+		TokenAmountOut: &pool.TokenAmount{Token: tokenOut, Amount: amountOut.ToBig()},
+		Fee:            &pool.TokenAmount{Token: tokenIn, Amount: integer.Zero()},
+		RemainingTokenAmountIn: &pool.TokenAmount{
+			Token:  tokenIn,
+			Amount: remaining.ToBig(),
+		},
+		Gas:      BaseGas + int64(len(swapInfo.FilledOrders))*GasPerOrder,
+		SwapInfo: swapInfo,
+	}, nil
+}
+
+// resolveFills resolves the unfilled orders fillable in block paying tokenIn only, best rate first.
+func (p *PoolSimulator) resolveFills(orders []*Order, tokenIn string, block Block) []fill {
+	fills := make([]fill, 0, len(orders))
+	for _, order := range orders {
+		if _, ok := p.filled[order.Hash]; ok {
+			continue
+		}
+		resolved, err := order.Resolve(block, p.filler)
+		if err != nil {
+			continue
+		}
+		amountIn, err := resolved.outputAmount(tokenIn)
+		if err != nil || amountIn.IsZero() || resolved.Input.Amount.IsZero() {
+			continue
+		}
+		fills = append(fills, fill{order: order, amountIn: amountIn, amountOut: resolved.Input.Amount})
+	}
+	slices.SortStableFunc(fills, func(a, b fill) int {
+		// a.amountOut/a.amountIn > b.amountOut/b.amountIn first
+		var x, y big.Int
+		return y.Mul(b.amountOut.ToBig(), a.amountIn.ToBig()).Cmp(x.Mul(a.amountOut.ToBig(), b.amountIn.ToBig()))
+	})
+	return fills
+}
+
+// outputAmount returns the total of the outputs of the order, all of token.
+func (r *ResolvedOrder) outputAmount(token string) (*uint256.Int, error) {
+	total := new(uint256.Int)
+	for _, output := range r.Outputs {
+		if output.Token != token {
+			return nil, ErrMixedOutputTokens
+		}
+		total.Add(total, output.Amount)
+	}
+	return total, nil
+}
+
+// availableBalance returns the balance a swapper can pay, from limit if set, nil if unknown.
+func (p *PoolSimulator) availableBalance(limit pool.SwapLimit, key string) *uint256.Int {
+	if limit != nil {
+		if balance := limit.GetLimit(key); balance != nil {
+			return uint256.MustFromBig(balance)
+		}
+		return nil
+	}
+	return p.balances[key]
+}
+
+func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	swapInfo, ok := params.SwapInfo.(SwapInfo)
+	if !ok {
+		logger.Warn("failed to UpdateBalance for uniswapx pool, wrong swapInfo type")
+		return
+	}
+
+	tokenIn, tokenOut := params.TokenAmountIn.Token, params.TokenAmountOut.Token
+	for _, filled := range swapInfo.FilledOrders {
+		p.filled[filled.Hash] = struct{}{}
+
+		key := SwapperKey(filled.Swapper, tokenOut)
+		if balance := p.balances[key]; balance != nil {
+			if balance.Lt(filled.AmountOut) {
+				p.balances[key] = new(uint256.Int)
+			} else {
+				p.balances[key] = new(uint256.Int).Sub(balance, filled.AmountOut)
+			}
+		}
+		if params.SwapLimit != nil {
+			_, _, _ = params.SwapLimit.UpdateLimit(
+				key,
+				SwapperKey(filled.Swapper, tokenIn),
+				filled.AmountOut.ToBig(),
+				filled.AmountIn.ToBig(),
+			)
+		}
+	}
+}
+
+func (p *PoolSimulator) CloneState() pool.IPoolSimulator {
+	cloned := *p
+	cloned.filled = maps.Clone(p.filled)
+	cloned.balances = maps.Clone(p.balances)
+	return &cloned
+}
+
+// CalculateLimit returns the balances of swappers, keyed by SwapperKey.
+func (p *PoolSimulator) CalculateLimit() map[string]*big.Int {
+	if len(p.balances) == 0 {
+		return nil
+	}
+	limits := make(map[string]*big.Int, len(p.balances))
+	for key, balance := range p.balances {
+		limits[key] = balance.ToBig()
+	}
+	return limits
+}
+
+func (p *PoolSimulator) GetMetaInfo(_, _ string) any {
+	return MetaInfo{
+		BlockNumber: p.Info.BlockNumber,
+	}
+}
@@ -0,0 +1,303 @@
+package uniswapx
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	uniswaplo "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/lo"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/swaplimit"
+)
+
+const (
+	usdc    = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	weth    = "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+	filler  = "0x00000000000000000000000000000000000000f1"
+	rival   = "0x00000000000000000000000000000000000000f2"
+	alice   = "0x00000000000000000000000000000000000000a1"
+	bob     = "0x00000000000000000000000000000000000000b0"
+	reactor = "0x00000011f84b9aa48e5f8aa8b9897600006289be"
+)
+
+// dutchV2Order sells 1 WETH of swapper for usdcStart to usdcEnd USDC decaying over [1000, 1100].
+func dutchV2Order(hash, swapper string, usdcStart, usdcEnd uint64) *Order {
+	return &Order{
+		Hash:     hash,
+		Type:     uniswaplo.DutchV2OrderType,
+		Reactor:  reactor,
+		Swapper:  swapper,
+		Deadline: 2000,
+		DutchV2: &DutchV2Order{
+			Input: DutchInput{Token: weth, StartAmount: uint256.NewInt(1e18), EndAmount: uint256.NewInt(1e18)},
+			Outputs: []DutchOutput{{
+				Token:       usdc,
+				StartAmount: uint256.NewInt(usdcStart),
+				EndAmount:   uint256.NewInt(usdcEnd),
+				Recipient:   swapper,
+			}},
+			CosignerData: DutchV2CosignerData{DecayStartTime: 1000, DecayEndTime: 1100},
+		},
+	}
+}
+
+func newTestPool(t *testing.T, balances map[string]*uint256.Int, orders ...*Order) *PoolSimulator {
+	extra, err := json.Marshal(Extra{Orders: orders, Balances: balances})
+	require.NoError(t, err)
+	p, err := NewPoolSimulator(entity.Pool{
+		Address:     "uniswapx_" + usdc + "_" + weth,
+		Exchange:    DexType,
+		Type:        DexType,
+		BlockNumber: 100,
+		Reserves:    entity.PoolReserves{"0", "0"},
+		Tokens:      []*entity.PoolToken{{Address: usdc, Swappable: true}, {Address: weth, Swappable: true}},
+		StaticExtra: `{"filler":"` + filler + `"}`,
+		Extra:       string(extra),
+	})
+	require.NoError(t, err)
+	return p
+}
+
+func swap(p *PoolSimulator, usdcIn uint64, limit pool.SwapLimit) (*pool.CalcAmountOutResult, error) {
+	return p.CalcAmountOut(pool.CalcAmountOutParams{
+		TokenAmountIn: pool.TokenAmount{Token: usdc, Amount: new(big.Int).SetUint64(usdcIn)},
+		TokenOut:      weth,
+		Limit:         limit,
+	})
+}
+
+func filledHashes(res *pool.CalcAmountOutResult) []string {
+	var hashes []string
+	for _, o := range res.SwapInfo.(SwapInfo).FilledOrders {
+		hashes = append(hashes, o.Hash)
+	}
+	return hashes
+}
+
+func TestPoolSimulator_Decay(t *testing.T) {
+	t.Parallel()
+	p := newTestPool(t, nil, dutchV2Order("a", alice, 3000e6, 2900e6))
+
+	for _, tc := range []struct {
+		timestamp     uint64
+		wantRemaining int64
+	}{
+		{timestamp: 900, wantRemaining: 0},
+		{timestamp: 1050, wantRemaining: 50e6},
+		{timestamp: 1100, wantRemaining: 100e6},
+		{timestamp: 1500, wantRemaining: 100e6},
+	} {
+		p.SetBlock(Block{Number: 200, Timestamp: tc.timestamp})
+		res, err := swap(p, 3000e6, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1e18), res.TokenAmountOut.Amount.Int64())
+		assert.Equal(t, tc.wantRemaining, res.RemainingTokenAmountIn.Amount.Int64(), "timestamp %d", tc.timestamp)
+		assert.Equal(t, tc.timestamp, res.SwapInfo.(SwapInfo).Block.Timestamp)
+	}
+
+	p.SetBlock(Block{Number: 200, Timestamp: 2001})
+	_, err := swap(p, 3000e6, nil)
+	assert.ErrorIs(t, err, ErrCannotFulfillAmountIn, "expired")
+}
+
+func TestPoolSimulator_BestOrdersFirst(t *testing.T) {
+	t.Parallel()
+	p := newTestPool(t, nil,
+		dutchV2Order("expensive", bob, 3100e6, 3100e6),
+		dutchV2Order("cheap", alice, 3000e6, 2900e6),
+	)
+	p.SetBlock(Block{Number: 200, Timestamp: 1050})
+
+	res, err := swap(p, 3000e6, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cheap"}, filledHashes(res))
+	assert.Equal(t, int64(50e6), res.RemainingTokenAmountIn.Amount.Int64())
+
+	res, err = swap(p, 6100e6, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cheap", "expensive"}, filledHashes(res))
+	assert.Equal(t, "2000000000000000000", res.TokenAmountOut.Amount.String())
+	assert.Equal(t, int64(50e6), res.RemainingTokenAmountIn.Amount.Int64())
+	assert.Equal(t, int64(BaseGas+2*GasPerOrder), res.Gas)
+
+	_, err = swap(p, 2000e6, nil)
+	assert.ErrorIs(t, err, ErrCannotFulfillAmountIn, "orders are not partially filled")
+}
+
+func TestPoolSimulator_Block(t *testing.T) {
+	t.Parallel()
+	order := dutchV2Order("a", alice, 3000e6, 2900e6)
+	p := newTestPool(t, nil, order)
+	_, err := swap(p, 3000e6, nil)
+	assert.ErrorIs(t, err, ErrBlockNotSet)
+
+	// the block can come with the pool state, and be overridden
+	extra, err := json.Marshal(Extra{Orders: []*Order{order}, Block: &Block{Number: 200, Timestamp: 1050}})
+	require.NoError(t, err)
+	p, err = NewPoolSimulator(entity.Pool{
+		Address:     "uniswapx_" + usdc + "_" + weth,
+		Exchange:    DexType,
+		Type:        DexType,
+		Reserves:    entity.PoolReserves{"0", "0"},
+		Tokens:      []*entity.PoolToken{{Address: usdc, Swappable: true}, {Address: weth, Swappable: true}},
+		StaticExtra: `{"filler":"` + filler + `"}`,
+		Extra:       string(extra),
+	})
+	require.NoError(t, err)
+	res, err := swap(p, 3000e6, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(50e6), res.RemainingTokenAmountIn.Amount.Int64())
+
+	p.SetBlock(Block{Number: 200, Timestamp: 1100})
+	res, err = swap(p, 3000e6, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100e6), res.RemainingTokenAmountIn.Amount.Int64())
+}
+
+func TestPoolSimulator_SwapperBalances(t *testing.T) {
+	t.Parallel()
+	balances := map[string]*uint256.Int{
+		SwapperKey(alice, weth): uint256.NewInt(1e18),
+		SwapperKey(bob, weth):   uint256.NewInt(1e18),
+	}
+	p := newTestPool(t, balances,
+		dutchV2Order("alice-1", alice, 2800e6, 2800e6),
+		dutchV2Order("alice-2", alice, 2900e6, 2900e6),
+		dutchV2Order("bob", bob, 3100e6, 3100e6),
+	)
+	p.SetBlock(Block{Number: 200, Timestamp: 1050})
+
+	// alice can only pay one of her orders
+	res, err := swap(p, 10000e6, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice-1", "bob"}, filledHashes(res))
+	assert.Equal(t, int64(4100e6), res.RemainingTokenAmountIn.Amount.Int64())
+
+	limit := swaplimit.NewInventory(DexType, p.CalculateLimit())
+	res, err = swap(p, 2800e6, limit)
+	require.NoError(t, err)
+	cloned := p.CloneState().(*PoolSimulator)
+	p.UpdateBalance(pool.UpdateBalanceParams{
+		TokenAmountIn:  pool.TokenAmount{Token: usdc, Amount: big.NewInt(2800e6)},
+		TokenAmountOut: *res.TokenAmountOut,
+		SwapInfo:       res.SwapInfo,
+		SwapLimit:      limit,
+	})
+	assert.Equal(t, "0", limit.GetLimit(SwapperKey(alice, weth)).String())
+	assert.Equal(t, "2800000000", limit.GetLimit(SwapperKey(alice, usdc)).String())
+
+	res, err = swap(p, 10000e6, limit)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob"}, filledHashes(res), "alice-1 is filled and alice spent her balance")
+
+	res, err = swap(cloned, 10000e6, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice-1", "bob"}, filledHashes(res), "the clone is unaffected")
+}
+
+func TestPoolSimulator_Exclusivity(t *testing.T) {
+	t.Parallel()
+	exclusive := dutchV2Order("a", alice, 3000e6, 2900e6)
+	exclusive.DutchV2.CosignerData.ExclusiveFiller = rival
+	p := newTestPool(t, nil, exclusive)
+
+	p.SetBlock(Block{Number: 200, Timestamp: 1000})
+	_, err := swap(p, 3000e6, nil)
+	assert.ErrorIs(t, err, ErrCannotFulfillAmountIn)
+
+	exclusive.DutchV2.CosignerData.ExclusivityOverrideBps = uint256.NewInt(100)
+	p = newTestPool(t, nil, exclusive)
+	p.SetBlock(Block{Number: 200, Timestamp: 1000})
+	res, err := swap(p, 3030e6, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), res.RemainingTokenAmountIn.Amount.Int64(), "pays the 1% override")
+
+	p.SetBlock(Block{Number: 200, Timestamp: 1001})
+	res, err = swap(p, 3030e6, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(30e6+1e6), res.RemainingTokenAmountIn.Amount.Int64(), "exclusivity ended")
+}
+
+func TestOrder_Resolve(t *testing.T) {
+	t.Parallel()
+	t.Run("cosigner overrides", func(t *testing.T) {
+		t.Parallel()
+		o := dutchV2Order("a", alice, 3000e6, 2900e6)
+		o.DutchV2.CosignerData.OutputOverrides = []*uint256.Int{uint256.NewInt(3050e6)}
+		resolved, err := o.Resolve(Block{Timestamp: 1000}, filler)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3050e6), resolved.Outputs[0].Amount.Uint64())
+
+		o.DutchV2.CosignerData.OutputOverrides = []*uint256.Int{uint256.NewInt(2000e6)}
+		_, err = o.Resolve(Block{Timestamp: 1000}, filler)
+		assert.ErrorIs(t, err, ErrInvalidCosignerOutput)
+
+		o.DutchV2.CosignerData.OutputOverrides = nil
+		o.DutchV2.CosignerData.InputOverride = uint256.NewInt(2e18)
+		_, err = o.Resolve(Block{Timestamp: 1000}, filler)
+		assert.ErrorIs(t, err, ErrInvalidCosignerInput)
+	})
+
+	t.Run("dutch v3 base fee", func(t *testing.T) {
+		t.Parallel()
+		o := &Order{
+			Type:     uniswaplo.DutchV3OrderType,
+			Deadline: 2000,
+			DutchV3: &DutchV3Order{
+				StartingBaseFee: uint256.NewInt(1e9),
+				Input: DutchV3Input{
+					Token: weth, StartAmount: uint256.NewInt(1e18), MaxAmount: uint256.NewInt(1e18),
+				},
+				Outputs: []DutchV3Output{{
+					Token:                    usdc,
+					StartAmount:              uint256.NewInt(3000e6),
+					MinAmount:                uint256.NewInt(2900e6),
+					AdjustmentPerGweiBaseFee: uint256.NewInt(10e6),
+				}},
+				CosignerData: DutchV3CosignerData{DecayStartBlock: 100},
+			},
+		}
+		resolved, err := o.Resolve(Block{Number: 150, Timestamp: 1000, BaseFee: uint256.NewInt(4e9)}, filler)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2970e6), resolved.Outputs[0].Amount.Uint64(), "3 gwei above the starting base fee")
+
+		resolved, err = o.Resolve(Block{Number: 150, Timestamp: 1000, BaseFee: uint256.NewInt(1e12)}, filler)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2900e6), resolved.Outputs[0].Amount.Uint64(), "bounded to the min amount")
+	})
+
+	t.Run("priority", func(t *testing.T) {
+		t.Parallel()
+		o := &Order{
+			Type:     uniswaplo.PriorityOrderType,
+			Deadline: 2000,
+			Priority: &PriorityOrder{
+				AuctionStartBlock:      200,
+				BaselinePriorityFeeWei: uint256.NewInt(1000),
+				Input: PriorityInput{
+					Token: weth, Amount: uint256.NewInt(1e18), MpsPerPriorityFeeWei: uint256.NewInt(0),
+				},
+				Outputs: []PriorityOutput{{
+					Token: usdc, Amount: uint256.NewInt(3000e6), MpsPerPriorityFeeWei: uint256.NewInt(10),
+				}},
+				AuctionTargetBlock: 150,
+			},
+		}
+		_, err := o.Resolve(Block{Number: 149}, filler)
+		assert.ErrorIs(t, err, ErrOrderNotFillable)
+
+		resolved, err := o.Resolve(Block{Number: 150}, filler)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3000e6), resolved.Outputs[0].Amount.Uint64(), "at the baseline fee")
+
+		resolved, err = o.Resolve(Block{Number: 150, PriorityFee: uint256.NewInt(11000)}, filler)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3030e6), resolved.Outputs[0].Amount.Uint64(), "1% for 10000 wei above the baseline")
+		assert.Equal(t, uint64(1e18), resolved.Input.Amount.Uint64())
+	})
+}
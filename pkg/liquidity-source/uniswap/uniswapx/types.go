@@ -0,0 +1,171 @@
+package uniswapx
+
+import (
+	"github.com/KyberNetwork/int256"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/holiman/uint256"
+
+	uniswaplo "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/lo"
+)
+
+type StaticExtra struct {
+	// Filler is the lowercase address filling orders, for exclusivity.
+	Filler string `json:"filler"`
+}
+
+type Extra struct {
+	Orders []*Order `json:"orders"`
+	// Balances is min(balance, permit2 allowance) of the input tokens of swappers, keyed by SwapperKey.
+	Balances map[string]*uint256.Int `json:"balances"`
+	// Block is the block orders are resolved at, unless set with SetBlock.
+	Block *Block `json:"block,omitempty"`
+}
+
+// Block is the block orders are resolved at: their decay, exclusivity and auctions all depend on it.
+type Block struct {
+	Number    uint64 `json:"number"`
+	Timestamp uint64 `json:"timestamp"`
+	// BaseFee is the base fee of the block, adjusting the amounts of Dutch V3 orders. Nil adjusts nothing.
+	BaseFee *uint256.Int `json:"baseFee,omitempty"`
+	// PriorityFee is the priority fee of the fill, scaling the amounts of priority orders. Nil scales nothing.
+	PriorityFee *uint256.Int `json:"priorityFee,omitempty"`
+}
+
+// Order is a signed UniswapX order, holding the decoded order of its type.
+type Order struct {
+	Hash      string              `json:"hash"`
+	Type      uniswaplo.OrderType `json:"type"`
+	Reactor   string              `json:"reactor"`
+	Swapper   string              `json:"swapper"`
+	Deadline  uint64              `json:"deadline"`
+	Encoded   hexutil.Bytes       `json:"encoded"`
+	Signature hexutil.Bytes       `json:"signature"`
+
+	DutchV2  *DutchV2Order  `json:"dutchV2,omitempty"`
+	DutchV3  *DutchV3Order  `json:"dutchV3,omitempty"`
+	Priority *PriorityOrder `json:"priority,omitempty"`
+}
+
+type DutchInput struct {
+	Token       string       `json:"token"`
+	StartAmount *uint256.Int `json:"startAmount"`
+	EndAmount   *uint256.Int `json:"endAmount"`
+}
+
+type DutchOutput struct {
+	Token       string       `json:"token"`
+	StartAmount *uint256.Int `json:"startAmount"`
+	EndAmount   *uint256.Int `json:"endAmount"`
+	Recipient   string       `json:"recipient"`
+}
+
+// DutchV2Order decays linearly over time, see V2DutchOrderLib.
+type DutchV2Order struct {
+	Input        DutchInput          `json:"input"`
+	Outputs      []DutchOutput       `json:"outputs"`
+	CosignerData DutchV2CosignerData `json:"cosignerData"`
+}
+
+type DutchV2CosignerData struct {
+	DecayStartTime         uint64         `json:"decayStartTime"`
+	DecayEndTime           uint64         `json:"decayEndTime"`
+	ExclusiveFiller        string         `json:"exclusiveFiller"`
+	ExclusivityOverrideBps *uint256.Int   `json:"exclusivityOverrideBps"`
+	InputOverride          *uint256.Int   `json:"inputOverride"`
+	OutputOverrides        []*uint256.Int `json:"outputOverrides"`
+}
+
+// Curve is a piecewise linear decay curve over blocks: RelativeAmounts[i] is subtracted from the start amount
+// RelativeBlocks[i] blocks after the decay starts.
+type Curve struct {
+	RelativeBlocks  []uint16      `json:"relativeBlocks"`
+	RelativeAmounts []*int256.Int `json:"relativeAmounts"`
+}
+
+type DutchV3Input struct {
+	Token                    string       `json:"token"`
+	StartAmount              *uint256.Int `json:"startAmount"`
+	Curve                    Curve        `json:"curve"`
+	MaxAmount                *uint256.Int `json:"maxAmount"`
+	AdjustmentPerGweiBaseFee *uint256.Int `json:"adjustmentPerGweiBaseFee"`
+}
+
+type DutchV3Output struct {
+	Token                    string       `json:"token"`
+	StartAmount              *uint256.Int `json:"startAmount"`
+	Curve                    Curve        `json:"curve"`
+	Recipient                string       `json:"recipient"`
+	MinAmount                *uint256.Int `json:"minAmount"`
+	AdjustmentPerGweiBaseFee *uint256.Int `json:"adjustmentPerGweiBaseFee"`
+}
+
+// DutchV3Order decays along curves over blocks, adjusted by the base fee, see V3DutchOrderLib.
+type DutchV3Order struct {
+	StartingBaseFee *uint256.Int        `json:"startingBaseFee"`
+	Input           DutchV3Input        `json:"input"`
+	Outputs         []DutchV3Output     `json:"outputs"`
+	CosignerData    DutchV3CosignerData `json:"cosignerData"`
+}
+
+type DutchV3CosignerData struct {
+	DecayStartBlock        uint64         `json:"decayStartBlock"`
+	ExclusiveFiller        string         `json:"exclusiveFiller"`
+	ExclusivityOverrideBps *uint256.Int   `json:"exclusivityOverrideBps"`
+	InputOverride          *uint256.Int   `json:"inputOverride"`
+	OutputOverrides        []*uint256.Int `json:"outputOverrides"`
+}
+
+type PriorityInput struct {
+	Token                string       `json:"token"`
+	Amount               *uint256.Int `json:"amount"`
+	MpsPerPriorityFeeWei *uint256.Int `json:"mpsPerPriorityFeeWei"`
+}
+
+type PriorityOutput struct {
+	Token                string       `json:"token"`
+	Amount               *uint256.Int `json:"amount"`
+	MpsPerPriorityFeeWei *uint256.Int `json:"mpsPerPriorityFeeWei"`
+	Recipient            string       `json:"recipient"`
+}
+
+// PriorityOrder scales with the priority fee of the fill, see PriorityOrderLib.
+type PriorityOrder struct {
+	AuctionStartBlock      uint64           `json:"auctionStartBlock"`
+	BaselinePriorityFeeWei *uint256.Int     `json:"baselinePriorityFeeWei"`
+	Input                  PriorityInput    `json:"input"`
+	Outputs                []PriorityOutput `json:"outputs"`
+	AuctionTargetBlock     uint64           `json:"auctionTargetBlock"`
+}
+
+// TokenAmount is an amount of a lowercase token.
+type TokenAmount struct {
+	Token  string       `json:"token"`
+	Amount *uint256.Int `json:"amount"`
+}
+
+// ResolvedOrder is an order resolved at a block: what the swapper pays and receives if filled in it.
+type ResolvedOrder struct {
+	Input   TokenAmount   `json:"input"`
+	Outputs []TokenAmount `json:"outputs"`
+}
+
+type SwapInfo struct {
+	Block        Block          `json:"block"`
+	FilledOrders []*FilledOrder `json:"filledOrders"`
+}
+
+// FilledOrder is an order filled by a swap: the filler pays AmountIn to its outputs and receives AmountOut of its input.
+type FilledOrder struct {
+	Hash      string              `json:"hash"`
+	Type      uniswaplo.OrderType `json:"type"`
+	Reactor   string              `json:"reactor"`
+	Swapper   string              `json:"swapper"`
+	Encoded   hexutil.Bytes       `json:"encoded"`
+	Signature hexutil.Bytes       `json:"signature"`
+	AmountIn  *uint256.Int        `json:"amountIn"`
+	AmountOut *uint256.Int        `json:"amountOut"`
+}
+
+type MetaInfo struct {
+	BlockNumber uint64 `json:"blockNumber"`
+}
@@ -13,6 +13,18 @@ type Config struct {
 	FeePrecision   uint64              `json:"feePrecision"`
 	FeeTracker     *FeeTrackerCfg      `json:"feeTracker"`
 	NewPoolLimit   int                 `json:"newPoolLimit"`
+	// StorageLayout reads the reserves from the pair storage with eth_getProof rather than getReserves, skipping the
+	// balanceOf sanity check. Fee and tax calls still go through multicall, at the same block. Pairs are always
+	// called when unset, as forks lay out their storage differently.
+	StorageLayout *StorageLayout `json:"storageLayout,omitempty"`
+}
+
+// StorageLayout locates the reserves in the pair storage.
+type StorageLayout struct {
+	// ReservesSlot packs reserve0, reserve1 and blockTimestampLast (uint32), from the lowest bits up.
+	ReservesSlot uint64 `json:"reservesSlot"`
+	// ReserveBits is the width of each reserve in ReservesSlot.
+	ReserveBits uint `json:"reserveBits"`
 }
 
 type FeeTrackerCfg struct {
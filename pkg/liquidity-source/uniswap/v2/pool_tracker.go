@@ -67,8 +67,18 @@ func (d *PoolTracker) GetNewPoolState(
 
 	req := d.ethrpcClient.NewRequest().SetContext(ctx)
 	reserveData := logsReserve
+	fromStorage := !fromLogs && d.config.StorageLayout != nil
+	if fromStorage {
+		var err error
+		if reserveData, blockNumber, err = d.getReservesFromStorage(ctx, p); err != nil {
+			return p, err
+		} else if p.BlockNumber > blockNumber.Uint64() {
+			return p, nil
+		}
+		req.SetBlockNumber(blockNumber)
+	}
 	var bals [2]*big.Int
-	if !fromLogs {
+	if !fromLogs && !fromStorage {
 		d.addReservesCall(req, p, &reserveData, bals[:])
 	}
 
@@ -87,7 +97,7 @@ func (d *PoolTracker) GetNewPoolState(
 	}
 
 	var resp *ethrpc.Response
-	if !fromLogs {
+	if !fromLogs && !fromStorage {
 		var err error
 		resp, err = req.TryBlockAndAggregate()
 		if err != nil {
@@ -4,13 +4,18 @@ import (
 	"context"
 	"math/big"
 	"testing"
+
 	"github.com/KyberNetwork/ethrpc"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/goccy/go-json"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/evmstate"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/testutil"
 )
 
 type staticLogDecoder struct {
@@ -75,3 +80,52 @@ func TestPoolTracker_RegularPoolWithLegacyExtra(t *testing.T) {
 	// ChainID is unsupported here (see comment above), so tax detection never runs.
 	assert.Nil(t, extra.TaxInfo)
 }
+
+func TestPoolTracker_StorageProof(t *testing.T) {
+	t.Parallel()
+
+	pair := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	// blockTimestampLast 300, reserve1 2000, reserve0 1000
+	reserves := common.HexToHash("0x0000012c00000000000000000000000007d000000000000000000000000003e8")
+	node, client := testutil.NewEVMStateNode(t, &evmstate.State{
+		BlockNumber: 12,
+		Accounts: map[common.Address]*evmstate.Account{
+			pair: {Storage: map[common.Hash]common.Hash{common.BigToHash(big.NewInt(8)): reserves}},
+		},
+	})
+	tracker := &PoolTracker{
+		config:       &Config{Fee: 30, FeePrecision: 10000, StorageLayout: &UniswapV2PairStorageLayout},
+		ethrpcClient: client,
+		logDecoder:   NewLogDecoder(),
+	}
+
+	p := entity.Pool{
+		Address:     "0x0000000000000000000000000000000000000001",
+		Tokens:      []*entity.PoolToken{{Address: "0x1111"}, {Address: "0x2222"}},
+		BlockNumber: 10,
+	}
+	updated, err := tracker.GetNewPoolState(context.Background(), p, pool.GetNewPoolStateParams{})
+	require.NoError(t, err)
+	assert.Equal(t, entity.PoolReserves{"1000", "2000"}, updated.Reserves)
+	assert.Equal(t, uint64(12), updated.BlockNumber)
+	assert.Equal(t, int64(300), updated.Timestamp)
+	assert.Equal(t, 1, node.Calls["eth_getProof"])
+
+	// a stale block
+	p.BlockNumber = 13
+	updated, err = tracker.GetNewPoolState(context.Background(), p, pool.GetNewPoolStateParams{})
+	require.NoError(t, err)
+	assert.Equal(t, p, updated)
+}
+
+func TestStorageLayout_DecodeReserves(t *testing.T) {
+	t.Parallel()
+
+	// blockTimestampLast 300, reserve1 2000, reserve0 1000 packed as uint96
+	layout := StorageLayout{ReservesSlot: 6, ReserveBits: 96}
+	reserves := layout.decodeReserves(
+		common.HexToHash("0x000000000000012c0000000000000000000007d00000000000000000000003e8"))
+	assert.Equal(t, "1000", reserves.Reserve0.String())
+	assert.Equal(t, "2000", reserves.Reserve1.String())
+	assert.Equal(t, uint32(300), reserves.BlockTimestampLast)
+}
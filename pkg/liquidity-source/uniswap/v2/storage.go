@@ -0,0 +1,40 @@
+package uniswapv2
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	utileth "github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/eth"
+)
+
+// UniswapV2PairStorageLayout is the StorageLayout of UniswapV2Pair: reserve0 (uint112), reserve1 (uint112) and
+// blockTimestampLast (uint32) packed in slot 8.
+var UniswapV2PairStorageLayout = StorageLayout{ReservesSlot: 8, ReserveBits: 112}
+
+// getReservesFromStorage reads the reserves of p from its storage, proven against the state root of the latest block.
+func (d *PoolTracker) getReservesFromStorage(ctx context.Context, p entity.Pool) (ReserveData, *big.Int, error) {
+	layout := d.config.StorageLayout
+	addr, slot := common.HexToAddress(p.Address), common.BigToHash(new(big.Int).SetUint64(layout.ReservesSlot))
+	snapshot, err := utileth.ReadStorage(ctx, d.ethrpcClient.GetETHClient().Client(), nil,
+		[]utileth.StorageQuery{{Address: addr, Slots: []common.Hash{slot}}}, utileth.DefaultBatchRetry)
+	if err != nil {
+		return ReserveData{}, nil, fmt.Errorf("failed to read reserves from storage: %w", err)
+	}
+	return layout.decodeReserves(snapshot.Get(addr, slot)), new(big.Int).SetUint64(snapshot.BlockNumber), nil
+}
+
+func (l *StorageLayout) decodeReserves(word common.Hash) ReserveData {
+	packed := word.Big()
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), l.ReserveBits), big.NewInt(1))
+	reserve0 := new(big.Int).And(packed, mask)
+	reserve1 := new(big.Int).And(packed.Rsh(packed, l.ReserveBits), mask)
+	return ReserveData{
+		Reserve0:           reserve0,
+		Reserve1:           reserve1,
+		BlockTimestampLast: uint32(packed.Rsh(packed, l.ReserveBits).Uint64()),
+	}
+}
@@ -3,6 +3,8 @@ package uniswapv3
 import (
 	"net/http"
 
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/reorg"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/transfertax"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
 )
 
@@ -23,9 +25,32 @@ type Config struct {
 
 	ForksConfig map[string]ForkConfig `json:"forksConfig,omitempty"`
 
+	// StorageLayout reads slot0, liquidity and ticks from the pool storage with eth_getProof rather than calling them,
+	// proven against the state root. Pools are always called when unset, as forks such as pancake-v3, ramses-v2,
+	// solidly-v3 and slipstream lay out their storage differently.
+	StorageLayout *StorageLayout `json:"storageLayout,omitempty"`
+
+	Reorg reorg.Config `json:"reorg"`
+
+	// TransferTax enables the detection of the transfer taxes of the pool tokens, applied by the simulators. The chain
+	// defaults to ChainID.
+	TransferTax *transfertax.Config `json:"transferTax,omitempty"`
+
 	preGenesisPoolIDs []string
 }
 
+// StorageLayout locates the pool state in the pool storage. slot0 packs sqrtPriceX96 (uint160) in its lowest bits and
+// the first slot of a tick packs liquidityGross (uint128) then liquidityNet (int128), from the lowest bits up.
+type StorageLayout struct {
+	Slot0Slot     uint64 `json:"slot0Slot"`
+	LiquiditySlot uint64 `json:"liquiditySlot"`
+	TicksSlot     uint64 `json:"ticksSlot"`
+	// TickOffset is the bit offset of tick (int24) in slot0.
+	TickOffset uint `json:"tickOffset"`
+	// UnlockedOffset is the bit offset of unlocked (bool) in slot0.
+	UnlockedOffset uint `json:"unlockedOffset"`
+}
+
 type ForkConfig struct {
 	// pons-fun
 	Multicall3 string `json:"multicall3"`
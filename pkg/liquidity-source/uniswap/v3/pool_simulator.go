@@ -1,6 +1,7 @@
 package uniswapv3
 
 import (
+	"math"
 	"math/big"
 	"slices"
 	"strings"
@@ -10,7 +11,10 @@ import (
 	"github.com/holiman/uint256"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/gasmodel"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v3/ticks"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/transfertax"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
 )
@@ -26,6 +30,7 @@ type PoolSimulator struct {
 	allowEmptyTicks   bool
 
 	buyRestrictedToken string // pons-fun
+	taxes              transfertax.Taxes
 }
 
 // NewPoolSimulator reads entityPool.Type/Exchange straight through, so the same constructor
@@ -39,6 +44,14 @@ var (
 	_ = pool.RegisterFactory1(DexTypeSolidlyV3, NewPoolSimulator)
 	_ = pool.RegisterFactory1(DexTypeSlipstream, NewPoolSimulator)
 	_ = pool.RegisterFactory1(DexTypeNuriV2, NewPoolSimulator)
+
+	// every fork reads the gas model of its own pool type through GasFor
+	_ = gasmodel.RegisterPoolType(DexTypeUniswapV3)
+	_ = gasmodel.RegisterPoolType(DexTypePancakeV3)
+	_ = gasmodel.RegisterPoolType(DexTypeRamsesV2)
+	_ = gasmodel.RegisterPoolType(DexTypeSolidlyV3)
+	_ = gasmodel.RegisterPoolType(DexTypeSlipstream)
+	_ = gasmodel.RegisterPoolType(DexTypeNuriV2)
 )
 
 func NewPoolSimulator(entityPool entity.Pool, _ valueobject.ChainID) (*PoolSimulator, error) {
@@ -123,11 +136,12 @@ func NewPoolSimulatorWithExtra(entityPool entity.Pool,
 			BlockNumber: entityPool.BlockNumber,
 		}},
 		V3Pool:             v3Pool,
-		Gas:                defaultGas,
+		Gas:                GasFor(entityPool.Type, defaultGas),
 		tickMin:            tickMin,
 		tickMax:            tickMax,
 		allowEmptyTicks:    cfg.AllowEmptyTicks,
 		buyRestrictedToken: extra.BuyRestrictedToken,
+		taxes:              extra.TransferTaxes,
 	}
 	if err := GetSqrtRatioAtTick(tickMin, &sim.sqrtPriceLimitMin); err != nil {
 		return nil, err
@@ -157,6 +171,13 @@ func (p *PoolSimulator) CalcAmountIn(param pool.CalcAmountInParams) (*pool.CalcA
 // "no limit" and reproduces plain CalcAmountIn. Forks that pin a price floor/ceiling (e.g. machima's
 // XMA sell floor) call this so they do not have to rebuild the result and SwapInfo by hand.
 func (p *PoolSimulator) CalcAmountInWithPriceLimit(param pool.CalcAmountInParams,
+	sqrtPriceLimitX96 uint256.Int) (*pool.CalcAmountInResult, error) {
+	return p.taxes.CalcAmountIn(param, func(param pool.CalcAmountInParams) (*pool.CalcAmountInResult, error) {
+		return p.calcAmountInWithPriceLimit(param, sqrtPriceLimitX96)
+	})
+}
+
+func (p *PoolSimulator) calcAmountInWithPriceLimit(param pool.CalcAmountInParams,
 	sqrtPriceLimitX96 uint256.Int) (*pool.CalcAmountInResult, error) {
 	tokenIn, tokenAmountOut := param.TokenIn, param.TokenAmountOut
 	tokenOut := tokenAmountOut.Token
@@ -196,6 +217,7 @@ func (p *PoolSimulator) CalcAmountInWithPriceLimit(param pool.CalcAmountInParams
 			NextStateSqrtRatioX96: &result.SqrtRatioX96,
 			NextStateLiquidity:    result.Liquidity,
 			NextStateTickCurrent:  result.CurrentTick,
+			CrossedTicks:          result.CrossInitTickLoops,
 		},
 	}, nil
 }
@@ -208,6 +230,13 @@ func (p *PoolSimulator) CalcAmountOut(param pool.CalcAmountOutParams) (*pool.Cal
 // "no limit" and reproduces plain CalcAmountOut. When the limit is hit the swap only partially fills
 // and the unspent input is reported in RemainingTokenAmountIn.
 func (p *PoolSimulator) CalcAmountOutWithPriceLimit(param pool.CalcAmountOutParams,
+	sqrtPriceLimitX96 uint256.Int) (*pool.CalcAmountOutResult, error) {
+	return p.taxes.CalcAmountOut(param, func(param pool.CalcAmountOutParams) (*pool.CalcAmountOutResult, error) {
+		return p.calcAmountOutWithPriceLimit(param, sqrtPriceLimitX96)
+	})
+}
+
+func (p *PoolSimulator) calcAmountOutWithPriceLimit(param pool.CalcAmountOutParams,
 	sqrtPriceLimitX96 uint256.Int) (*pool.CalcAmountOutResult, error) {
 	tokenAmountIn, tokenOut := param.TokenAmountIn, param.TokenOut
 	tokenIn := tokenAmountIn.Token
@@ -251,10 +280,44 @@ func (p *PoolSimulator) CalcAmountOutWithPriceLimit(param pool.CalcAmountOutPara
 			NextStateSqrtRatioX96: &result.SqrtRatioX96,
 			NextStateLiquidity:    result.Liquidity,
 			NextStateTickCurrent:  result.CurrentTick,
+			CrossedTicks:          result.CrossInitTickLoops,
 		},
 	}, nil
 }
 
+// GetSpotPrice returns the price at the current sqrt price, net of the swap fee and transfer taxes.
+func (p *PoolSimulator) GetSpotPrice(tokenIn, tokenOut string) (float64, error) {
+	tokenInIndex, tokenOutIndex := p.GetTokenIndex(tokenIn), p.GetTokenIndex(tokenOut)
+	if tokenInIndex < 0 || tokenOutIndex < 0 || tokenInIndex == tokenOutIndex {
+		return 0, ErrInvalidToken
+	} else if p.isBuyRestricted(tokenOut) {
+		return 0, ErrBuyRestricted
+	} else if p.V3Pool.Liquidity.IsZero() {
+		return 0, pool.ErrSpotPriceUnsupported // the marginal price is the one of the next initialized tick
+	}
+
+	sqrtPrice := math.Ldexp(p.V3Pool.SqrtRatioX96.Float64(), -96)
+	price := sqrtPrice * sqrtPrice // token1 per token0
+	if tokenInIndex == 1 {
+		price = 1 / price
+	}
+	return price * (1 - float64(p.V3Pool.Fee)/float64(FeeMax)) * p.taxes.NetShare(tokenIn, tokenOut), nil
+}
+
+// GetTickDepth returns the liquidity of each range between initialized ticks at the current state.
+func (p *PoolSimulator) GetTickDepth() []pool.TickDepth {
+	v3Ticks := make([]ticks.Tick, 0, len(p.V3Pool.Ticks))
+	for _, t := range p.V3Pool.Ticks {
+		v3Ticks = append(v3Ticks, ticks.Tick{
+			TickIdx:        t.Index,
+			LiquidityGross: t.LiquidityGross.ToBig(),
+			LiquidityNet:   t.LiquidityNet.ToBig(),
+		})
+	}
+	sqrtPrice := math.Ldexp(p.V3Pool.SqrtRatioX96.Float64(), -96)
+	return ticks.Depth(sqrtPrice, p.V3Pool.TickCurrent, p.V3Pool.Liquidity.ToBig(), v3Ticks)
+}
+
 func (p *PoolSimulator) CloneState() pool.IPoolSimulator {
 	cloned := *p
 	v3Pool := *p.V3Pool
@@ -268,6 +331,7 @@ func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
 		logger.Warn("failed to UpdateBalance for UniV3 pool, wrong swapInfo type")
 		return
 	}
+	params = p.taxes.PoolAmounts(params)
 	p.V3Pool.SqrtRatioX96.Set(si.NextStateSqrtRatioX96)
 	p.V3Pool.Liquidity.Set(&si.NextStateLiquidity)
 	p.V3Pool.TickCurrent = si.NextStateTickCurrent
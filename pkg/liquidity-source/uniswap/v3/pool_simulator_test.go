@@ -8,6 +8,7 @@ import (
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/transfertax"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/testutil"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
@@ -142,3 +143,56 @@ func TestCloneState(t *testing.T) {
 		TokenOut: "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
 	}, nil)
 }
+
+func TestGetSpotPrice(t *testing.T) {
+	t.Parallel()
+	poolEntity := new(entity.Pool)
+	require.NoError(t, json.Unmarshal([]byte(poolEncoded), poolEntity))
+	poolSim, err := NewPoolSimulator(*poolEntity, valueobject.ChainIDEthereum)
+	require.NoError(t, err)
+
+	testutil.TestSpotPrice(t, poolSim, 1)
+}
+
+func TestTransferTax(t *testing.T) {
+	t.Parallel()
+	poolEntity := new(entity.Pool)
+	require.NoError(t, json.Unmarshal([]byte(poolEncoded), poolEntity))
+	require.NoError(t, transfertax.SetPoolTaxes(poolEntity, transfertax.Taxes{
+		"0x1f9840a85d5af5bf1d1762f925bdaddc4201f984": {SellBps: 300, BuyBps: 500},
+	}))
+	sim, err := pool.Factory(DexTypeUniswapV3)(pool.FactoryParams{EntityPool: *poolEntity})
+	require.NoError(t, err)
+
+	// the taxed pool keeps its optional interfaces, and its spot price matches its quotes net of the taxes
+	poolSim, ok := sim.(*PoolSimulator)
+	require.True(t, ok)
+	testutil.TestCalcAmountIn(t, poolSim)
+	testutil.TestSpotPrice(t, poolSim, 1)
+	require.NotEmpty(t, pool.IPoolTickDepth(poolSim).GetTickDepth())
+}
+
+func TestGetTickDepth(t *testing.T) {
+	t.Parallel()
+	poolEntity := new(entity.Pool)
+	require.NoError(t, json.Unmarshal([]byte(poolEncoded), poolEntity))
+	poolSim, err := NewPoolSimulator(*poolEntity, valueobject.ChainIDEthereum)
+	require.NoError(t, err)
+
+	depth := pool.IPoolTickDepth(poolSim).GetTickDepth()
+	require.NotEmpty(t, depth)
+	active := 0
+	for i, d := range depth {
+		require.Less(t, d.TickLower, d.TickUpper)
+		if i > 0 {
+			require.LessOrEqual(t, depth[i-1].TickUpper, d.TickLower)
+		}
+		if d.TickLower <= poolSim.V3Pool.TickCurrent && poolSim.V3Pool.TickCurrent < d.TickUpper {
+			require.Equal(t, poolSim.V3Pool.Liquidity.ToBig(), d.Liquidity)
+			require.Positive(t, d.Amount0)
+			require.Positive(t, d.Amount1)
+			active++
+		}
+	}
+	require.Equal(t, 1, active)
+}
@@ -22,7 +22,9 @@ import (
 	ponsfun "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v3/forks/pons-fun"
 	tickspkg "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v3/ticks"
 	poolpkg "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/reorg"
 	pooltrack "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/tracker"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/transfertax"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/abi"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/eth"
 	graphqlpkg "github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/graphql"
@@ -76,6 +78,8 @@ type Tracker struct {
 	config        *Config
 	ethrpcClient  *ethrpc.Client
 	graphqlClient *graphqlpkg.Client
+	reorgGuard    *reorg.Guard
+	transferTax   *transfertax.Detector
 }
 
 func NewTracker(
@@ -92,6 +96,8 @@ func NewTracker(
 		config:        initializedCfg,
 		ethrpcClient:  ethrpcClient,
 		graphqlClient: graphqlClient,
+		reorgGuard:    reorg.NewGuard(initializedCfg.Reorg),
+		transferTax:   transfertax.NewConfiguredDetector(initializedCfg.TransferTax, initializedCfg.ChainID, ethrpcClient),
 	}, nil
 }
 
@@ -124,6 +130,17 @@ func initializeConfig(cfg *Config) (*Config, error) {
 }
 
 func (t *Tracker) GetNewPoolState(ctx context.Context, p entity.Pool, param poolpkg.GetNewPoolStateParams) (entity.Pool, error) {
+	newState, err := t.reorgGuard.GetNewPoolState(ctx, p, param, t.applyLogs, t.BootstrapPoolState)
+	if err != nil {
+		return p, err
+	}
+	if newState, err = t.transferTax.Track(ctx, newState); err != nil {
+		return p, err
+	}
+	return newState, nil
+}
+
+func (t *Tracker) applyLogs(ctx context.Context, p entity.Pool, param poolpkg.GetNewPoolStateParams) (entity.Pool, error) {
 	if len(param.Logs) == 0 {
 		return p, nil
 	}
@@ -607,6 +624,10 @@ func (t *Tracker) queryTicksFromRPC(
 	tickIndexes []int,
 	blockNumber uint64,
 ) ([]tickspkg.Tick, error) {
+	if t.config.StorageLayout != nil {
+		return t.readTicksFromStorage(ctx, address, tickIndexes, blockNumber)
+	}
+
 	if len(tickIndexes) <= tickChunkSize {
 		return t.queryRPCTicksByChunk(ctx, address, tickIndexes, blockNumber)
 	}
@@ -793,27 +814,67 @@ func (t *Tracker) FetchRPCData(ctx context.Context, p *entity.Pool, blockNumber
 
 	rpcRequest := t.ethrpcClient.NewRequest()
 	rpcRequest.SetContext(ctx)
+
+	// In storage proof mode, liquidity and slot0 are read from storage, and the remaining calls pinned to its block.
+	// Both run concurrently: the latest block is pinned first, while the header of a known block is fetched along with
+	// the proofs.
+	g := pool.New().WithContext(ctx)
+	var storageSlot0 *Slot0
+	if layout := t.config.StorageLayout; layout != nil {
+		var header *ethtypes.Header
+		if blockNumber == 0 {
+			var err error
+			if header, err = eth.FetchHeader(ctx, t.ethrpcClient.GetETHClient().Client(), nil); err != nil {
+				l.WithFields(logger.Fields{
+					"error": err,
+				}).Error("failed to fetch block header")
+				return nil, err
+			}
+			blockNumber = header.Number.Uint64()
+		}
+
+		poolAddr := common.HexToAddress(p.Address)
+		slot0Slot, liquiditySlot := storageSlot(layout.Slot0Slot), storageSlot(layout.LiquiditySlot)
+		storageSlot0 = &Slot0{}
+		g.Go(func(ctx context.Context) error {
+			snapshot, err := t.readStorage(ctx,
+				[]eth.StorageQuery{{Address: poolAddr, Slots: []common.Hash{slot0Slot, liquiditySlot}}}, header,
+				blockNumber)
+			if err != nil {
+				l.WithFields(logger.Fields{
+					"error": err,
+				}).Error("failed to read storage")
+				return err
+			}
+			*storageSlot0 = layout.decodeSlot0(snapshot.Get(poolAddr, slot0Slot))
+			liquidity = snapshot.Get(poolAddr, liquiditySlot).Big()
+			return nil
+		})
+	}
+
 	if blockNumber > 0 {
 		var blockNumberBI big.Int
 		blockNumberBI.SetUint64(blockNumber)
 		rpcRequest.SetBlockNumber(&blockNumberBI)
 	}
 
-	rpcRequest.AddCall(&ethrpc.Call{
-		ABI:    abis.UniswapV3PoolABI,
-		Target: p.Address,
-		Method: methodGetLiquidity,
-	}, []any{&liquidity})
+	if storageSlot0 == nil {
+		rpcRequest.AddCall(&ethrpc.Call{
+			ABI:    abis.UniswapV3PoolABI,
+			Target: p.Address,
+			Method: methodGetLiquidity,
+		}, []any{&liquidity})
 
-	// Try katana (8-word) first, then standard (7-word), then slipstream (6-word), then solidly (4-word) shapes, longest
-	// first - go-ethereum ABI decoder only errors on insufficient data, not unconsumed trailing bytes.
-	// A shorter ABI silently misdecodes longer returndata by putting wrong bytes into its last fields.
-	rpcRequest.AddCall(&ethrpc.Call{
-		ABI:       abis.UniswapV3PoolABI,
-		UnpackABI: []ethabi.ABI{abis.Slot0KatanaABI, abis.UniswapV3PoolABI, abis.Slot0SlipstreamABI, abis.Slot0SolidlyABI},
-		Target:    p.Address,
-		Method:    methodGetSlot0,
-	}, []any{&slot0Katana, &slot0Std, &slot0Slip, &slot0Solid})
+		// Try katana (8-word) first, then standard (7-word), then slipstream (6-word), then solidly (4-word) shapes,
+		// longest first - go-ethereum ABI decoder only errors on insufficient data, not unconsumed trailing bytes.
+		// A shorter ABI silently misdecodes longer returndata by putting wrong bytes into its last fields.
+		rpcRequest.AddCall(&ethrpc.Call{
+			ABI:       abis.UniswapV3PoolABI,
+			UnpackABI: []ethabi.ABI{abis.Slot0KatanaABI, abis.UniswapV3PoolABI, abis.Slot0SlipstreamABI, abis.Slot0SolidlyABI},
+			Target:    p.Address,
+			Method:    methodGetSlot0,
+		}, []any{&slot0Katana, &slot0Std, &slot0Slip, &slot0Solid})
+	}
 
 	rpcRequest.AddCall(&ethrpc.Call{
 		ABI:    abis.UniswapV3PoolABI,
@@ -858,14 +919,23 @@ func (t *Tracker) FetchRPCData(ctx context.Context, p *entity.Pool, blockNumber
 		ponsGuard.AddCalls(rpcRequest)
 	}
 
-	if _, err := rpcRequest.TryAggregate(); err != nil {
-		l.WithFields(logger.Fields{
-			"error": err,
-		}).Error("failed to process tryAggregate")
+	g.Go(func(context.Context) error {
+		if _, err := rpcRequest.TryAggregate(); err != nil {
+			l.WithFields(logger.Fields{
+				"error": err,
+			}).Error("failed to process tryAggregate")
+			return err
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
 		return nil, err
 	}
 
 	slot0, err := resolveSlot0(slot0Katana, slot0Std, slot0Slip, slot0Solid)
+	if storageSlot0 != nil {
+		slot0, err = *storageSlot0, nil
+	}
 	if err != nil {
 		l.WithFields(logger.Fields{
 			"error": err,
@@ -1,6 +1,7 @@
 package uniswapv3
 
 import (
+	"context"
 	"math/big"
 	"testing"
 
@@ -8,6 +9,9 @@ import (
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/evmstate"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/testutil"
 )
 
 // word encodes v as a left-padded 32-byte big-endian word, the same shape both indexed
@@ -195,3 +199,65 @@ func TestResolveSlot0(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestDecodeSlot0(t *testing.T) {
+	t.Parallel()
+
+	slot0 := UniswapV3PoolStorageLayout.decodeSlot0(
+		common.HexToHash("0x000100000100010000ffff380000000000000001000000000000000000000000"))
+	assert.Equal(t, "79228162514264337593543950336", slot0.SqrtPriceX96.String())
+	assert.Equal(t, big.NewInt(-200), slot0.Tick)
+	assert.True(t, slot0.Unlocked)
+	assert.Nil(t, slot0.Fee)
+
+	// unlocked right after the observation fields, without feeProtocol
+	layout := UniswapV3PoolStorageLayout
+	layout.UnlockedOffset = 232
+	slot0 = layout.decodeSlot0(common.HexToHash("0x000001000000000000ffff380000000000000001000000000000000000000000"))
+	assert.Equal(t, "79228162514264337593543950336", slot0.SqrtPriceX96.String())
+	assert.Equal(t, big.NewInt(-200), slot0.Tick)
+	assert.True(t, slot0.Unlocked)
+	assert.False(t, UniswapV3PoolStorageLayout.decodeSlot0(
+		common.HexToHash("0x000001000000000000ffff380000000000000001000000000000000000000000")).Unlocked)
+}
+
+func TestQueryTicksFromRPC_StorageProof(t *testing.T) {
+	t.Parallel()
+
+	poolAddr := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	node, client := testutil.NewEVMStateNode(t, &evmstate.State{
+		BlockNumber: 10,
+		Accounts: map[common.Address]*evmstate.Account{
+			poolAddr: {Storage: map[common.Hash]common.Hash{
+				// liquidityNet -5000, liquidityGross 7000
+				UniswapV3PoolStorageLayout.tickStorageSlot(-60): common.HexToHash(
+					"0xffffffffffffffffffffffffffffec7800000000000000000000000000001b58"),
+			}},
+		},
+	})
+	tr := &Tracker{config: &Config{StorageLayout: &UniswapV3PoolStorageLayout}, ethrpcClient: client}
+
+	ticks, err := tr.queryTicksFromRPC(context.Background(), poolAddr.Hex(), []int{-60, 60}, 10)
+	require.NoError(t, err)
+	require.Len(t, ticks, 2)
+	assert.Equal(t, -60, ticks[0].TickIdx)
+	assert.Equal(t, "7000", ticks[0].LiquidityGross.String())
+	assert.Equal(t, "-5000", ticks[0].LiquidityNet.String())
+	assert.Equal(t, 60, ticks[1].TickIdx)
+	assert.Zero(t, ticks[1].LiquidityGross.Sign())
+	assert.Equal(t, 1, node.Calls["eth_getProof"])
+	assert.Equal(t, 1, node.Requests)
+
+	// the chunks of ticks and the header are read in one batch
+	node.Calls, node.Requests = map[string]int{}, 0
+	tickIdxs := make([]int, 2*tickChunkSize+1)
+	for i := range tickIdxs {
+		tickIdxs[i] = (i - tickChunkSize) * 60
+	}
+	ticks, err = tr.queryTicksFromRPC(context.Background(), poolAddr.Hex(), tickIdxs, 10)
+	require.NoError(t, err)
+	require.Len(t, ticks, len(tickIdxs))
+	assert.Equal(t, "-5000", ticks[tickChunkSize-1].LiquidityNet.String())
+	assert.Equal(t, map[string]int{"eth_getBlockByNumber": 1, "eth_getProof": 3}, node.Calls)
+	assert.Equal(t, 1, node.Requests)
+}
@@ -0,0 +1,109 @@
+package uniswapv3
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	tickspkg "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v3/ticks"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/eth"
+)
+
+// UniswapV3PoolStorageLayout is the StorageLayout of UniswapV3Pool. slot0 packs sqrtPriceX96 (uint160), tick (int24),
+// the 3 uint16 observation fields, feeProtocol (uint8) and unlocked, from the lowest bits up.
+var UniswapV3PoolStorageLayout = StorageLayout{
+	Slot0Slot:      0,
+	LiquiditySlot:  4,
+	TicksSlot:      5,
+	TickOffset:     160,
+	UnlockedOffset: 240,
+}
+
+var (
+	two128      = new(big.Int).Lsh(big.NewInt(1), 128)
+	maskUint160 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 160), big.NewInt(1))
+	maskUint24  = big.NewInt(1<<24 - 1)
+	maskUint8   = big.NewInt(1<<8 - 1)
+)
+
+func storageSlot(slot uint64) common.Hash {
+	return common.BigToHash(new(big.Int).SetUint64(slot))
+}
+
+// readStorage reads queries as of header if known, otherwise as of blockNumber, the latest block if 0.
+func (t *Tracker) readStorage(ctx context.Context, queries []eth.StorageQuery, header *ethtypes.Header,
+	blockNumber uint64) (*eth.StorageSnapshot, error) {
+	client := t.ethrpcClient.GetETHClient().Client()
+	if header != nil {
+		return eth.ReadStorageAt(ctx, client, header, queries, eth.DefaultBatchRetry)
+	}
+	var blockNumberBI *big.Int
+	if blockNumber > 0 {
+		blockNumberBI = new(big.Int).SetUint64(blockNumber)
+	}
+	return eth.ReadStorage(ctx, client, blockNumberBI, queries, eth.DefaultBatchRetry)
+}
+
+// tickStorageSlot returns the slot of ticks[tick].
+func (l *StorageLayout) tickStorageSlot(tick int) common.Hash {
+	ticksSlot := storageSlot(l.TicksSlot)
+	return crypto.Keccak256Hash(math.U256Bytes(big.NewInt(int64(tick))), ticksSlot[:])
+}
+
+func (l *StorageLayout) decodeSlot0(word common.Hash) Slot0 {
+	packed := word.Big()
+	var tick, unlocked big.Int
+	tick.And(tick.Rsh(packed, l.TickOffset), maskUint24)
+	unlocked.And(unlocked.Rsh(packed, l.UnlockedOffset), maskUint8)
+	return Slot0{
+		SqrtPriceX96: packed.And(packed, maskUint160),
+		Tick:         big.NewInt(int64(int32(tick.Uint64())<<8) >> 8),
+		Unlocked:     unlocked.Sign() != 0,
+	}
+}
+
+func decodeTick(tickIdx int, word common.Hash) tickspkg.Tick {
+	liquidityNet := new(big.Int).SetBytes(word[:16])
+	if word[0]&0x80 != 0 {
+		liquidityNet.Sub(liquidityNet, two128)
+	}
+	return tickspkg.Tick{
+		TickIdx:        tickIdx,
+		LiquidityGross: new(big.Int).SetBytes(word[16:]),
+		LiquidityNet:   liquidityNet,
+	}
+}
+
+// readTicksFromStorage is queryTicksFromRPC reading the ticks mapping from storage. The chunks of ticks are proven in
+// one batch.
+func (t *Tracker) readTicksFromStorage(ctx context.Context, address string, ticks []int,
+	blockNumber uint64) ([]tickspkg.Tick, error) {
+	poolAddr := common.HexToAddress(address)
+	slots := make([]common.Hash, len(ticks))
+	for i, tick := range ticks {
+		slots[i] = t.config.StorageLayout.tickStorageSlot(tick)
+	}
+	queries := make([]eth.StorageQuery, 0, (len(slots)+tickChunkSize-1)/tickChunkSize)
+	for chunk := range slices.Chunk(slots, tickChunkSize) {
+		queries = append(queries, eth.StorageQuery{Address: poolAddr, Slots: chunk})
+	}
+	snapshot, err := t.readStorage(ctx, queries, nil, blockNumber)
+	if err != nil {
+		if blockNumber > 0 && tickspkg.IsMissingTrieNodeError(err) {
+			return t.readTicksFromStorage(ctx, address, ticks, 0)
+		}
+		return nil, fmt.Errorf("failed to read ticks from storage: %w", err)
+	}
+
+	result := make([]tickspkg.Tick, len(ticks))
+	for i, tick := range ticks {
+		result[i] = decodeTick(tick, snapshot.Get(poolAddr, slots[i]))
+	}
+	return result, nil
+}
@@ -0,0 +1,64 @@
+package ticks
+
+import (
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+// Depth returns the liquidity of each range between consecutive ticks of a pool at sqrtPrice (the square root of the
+// price of token0 in token1, in wei) in tickCurrent with liquidity active, ticks being its initialized ticks sorted
+// by index. The
+// liquidity of each range is derived from the active one and the liquidity nets of the ticks crossed to reach it, so
+// a partial tick list still yields the ranges around the current price. Ranges without liquidity are left out.
+func Depth(sqrtPrice float64, tickCurrent int, liquidity *big.Int, ticks []Tick) []pool.TickDepth {
+	if len(ticks) < 2 {
+		return nil
+	}
+	// cur is the position of the range containing tickCurrent, -1 if below all initialized ticks
+	cur := sort.Search(len(ticks), func(i int) bool { return ticks[i].TickIdx > tickCurrent }) - 1
+
+	rangeLiquidity := make([]*big.Int, len(ticks)-1)
+	if cur >= 0 && cur < len(rangeLiquidity) {
+		rangeLiquidity[cur] = liquidity
+	}
+	up := liquidity
+	for i := cur + 1; i < len(rangeLiquidity); i++ {
+		up = new(big.Int).Add(up, ticks[i].LiquidityNet)
+		rangeLiquidity[i] = up
+	}
+	down := liquidity
+	for i := min(cur, len(rangeLiquidity)) - 1; i >= 0; i-- {
+		down = new(big.Int).Sub(down, ticks[i+1].LiquidityNet)
+		rangeLiquidity[i] = down
+	}
+
+	depth := make([]pool.TickDepth, 0, len(rangeLiquidity))
+	for i, l := range rangeLiquidity {
+		if l.Sign() <= 0 {
+			continue
+		}
+		tickLower, tickUpper := ticks[i].TickIdx, ticks[i+1].TickIdx
+		sqrtLower, sqrtUpper := tickSqrtPrice(tickLower), tickSqrtPrice(tickUpper)
+		lf, _ := l.Float64()
+		d := pool.TickDepth{TickLower: tickLower, TickUpper: tickUpper, Liquidity: new(big.Int).Set(l)}
+		switch {
+		case sqrtPrice <= sqrtLower:
+			d.Amount0 = lf * (1/sqrtLower - 1/sqrtUpper)
+		case sqrtPrice >= sqrtUpper:
+			d.Amount1 = lf * (sqrtUpper - sqrtLower)
+		default:
+			d.Amount0 = lf * (1/sqrtPrice - 1/sqrtUpper)
+			d.Amount1 = lf * (sqrtPrice - sqrtLower)
+		}
+		depth = append(depth, d)
+	}
+	return depth
+}
+
+// tickSqrtPrice returns 1.0001^(tick/2).
+func tickSqrtPrice(tick int) float64 {
+	return math.Pow(1.0001, float64(tick)/2)
+}
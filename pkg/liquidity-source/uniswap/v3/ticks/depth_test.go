@@ -0,0 +1,54 @@
+package ticks
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDepth(t *testing.T) {
+	t.Parallel()
+	// positions: 100 on [-20, 20], 50 on [-10, 40]
+	ticks := []Tick{
+		{TickIdx: -20, LiquidityGross: big.NewInt(100), LiquidityNet: big.NewInt(100)},
+		{TickIdx: -10, LiquidityGross: big.NewInt(50), LiquidityNet: big.NewInt(50)},
+		{TickIdx: 20, LiquidityGross: big.NewInt(100), LiquidityNet: big.NewInt(-100)},
+		{TickIdx: 40, LiquidityGross: big.NewInt(50), LiquidityNet: big.NewInt(-50)},
+	}
+	sqrtPrice := tickSqrtPrice(0)
+
+	depth := Depth(sqrtPrice, 0, big.NewInt(150), ticks)
+	require.Len(t, depth, 3)
+	for i, want := range []struct {
+		tickLower, tickUpper int
+		liquidity            int64
+	}{{-20, -10, 100}, {-10, 20, 150}, {20, 40, 50}} {
+		assert.Equal(t, want.tickLower, depth[i].TickLower)
+		assert.Equal(t, want.tickUpper, depth[i].TickUpper)
+		assert.Equal(t, big.NewInt(want.liquidity), depth[i].Liquidity)
+	}
+	assert.Zero(t, depth[0].Amount0)
+	assert.InEpsilon(t, 100*(tickSqrtPrice(-10)-tickSqrtPrice(-20)), depth[0].Amount1, 1e-12)
+	assert.InEpsilon(t, 150*(1/sqrtPrice-1/tickSqrtPrice(20)), depth[1].Amount0, 1e-12)
+	assert.InEpsilon(t, 150*(sqrtPrice-tickSqrtPrice(-10)), depth[1].Amount1, 1e-12)
+	assert.InEpsilon(t, 50*(1/tickSqrtPrice(20)-1/tickSqrtPrice(40)), depth[2].Amount0, 1e-12)
+	assert.Zero(t, depth[2].Amount1)
+
+	// out of range, the liquidity is derived from the ticks crossed from the empty active range
+	below := Depth(tickSqrtPrice(-30), -30, big.NewInt(0), ticks)
+	above := Depth(tickSqrtPrice(50), 50, big.NewInt(0), ticks)
+	require.Len(t, below, 3)
+	require.Len(t, above, 3)
+	for i := range depth {
+		assert.Equal(t, depth[i].Liquidity, below[i].Liquidity)
+		assert.Equal(t, depth[i].Liquidity, above[i].Liquidity)
+		assert.Positive(t, below[i].Amount0)
+		assert.Zero(t, below[i].Amount1)
+		assert.Zero(t, above[i].Amount0)
+		assert.Positive(t, above[i].Amount1)
+	}
+
+	assert.Nil(t, Depth(sqrtPrice, 0, big.NewInt(0), ticks[:1]))
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/KyberNetwork/int256"
 	"github.com/holiman/uint256"
 
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/gasmodel"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/transfertax"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/ticklens"
 )
 
@@ -16,11 +18,24 @@ type Gas struct {
 	CrossInitTickGas int64
 }
 
+// GasFor returns the calibrated gas model of poolType if any, fallback otherwise.
+func GasFor(poolType string, fallback Gas) Gas {
+	if model, ok := gasmodel.Lookup(poolType); ok {
+		return Gas{BaseGas: model.Base, CrossInitTickGas: model.PerTickCrossed}
+	}
+	return fallback
+}
+
 type SwapInfo struct {
 	RemainingAmountIn     *uint256.Int `json:"rAI,omitempty"`
 	NextStateSqrtRatioX96 *uint256.Int `json:"nSqrtRx96"`
 	NextStateLiquidity    uint256.Int  `json:"-"`
 	NextStateTickCurrent  int          `json:"nT"`
+	CrossedTicks          int          `json:"-"`
+}
+
+func (si SwapInfo) GasFeatures() gasmodel.Features {
+	return gasmodel.Features{TicksCrossed: si.CrossedTicks}
 }
 
 type Metadata struct {
@@ -78,6 +93,8 @@ type ExtraTickU256 struct {
 	Ticks        []TickU256   `json:"ticks"`
 
 	BuyRestrictedToken string `json:"buyRestrictedToken,omitempty"`
+	// TransferTaxes are the taxes of the tokens the tracker detected, applied to the quotes.
+	TransferTaxes transfertax.Taxes `json:"transferTaxes,omitempty"`
 }
 
 // SimulatorConfig holds construction-time options for NewPoolSimulatorWithExtra.
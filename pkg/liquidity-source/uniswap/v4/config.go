@@ -5,6 +5,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/reorg"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
 )
 
@@ -24,6 +25,8 @@ type Config struct {
 	FetchTickFromStateView bool // instead of fetching from subgraph
 
 	HookConfigs map[common.Address]any `json:"hookConfigs" mapstructure:"hookConfigs"`
+
+	Reorg reorg.Config `json:"reorg"`
 }
 
 func (c *Config) IsAllowSubgraphError() bool {
@@ -0,0 +1,10 @@
+package auto
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/samber/lo"
+)
+
+var hooksABI = lo.Must(abi.JSON(bytes.NewReader(hooksABIJson)))
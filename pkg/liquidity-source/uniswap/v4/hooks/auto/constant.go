@@ -0,0 +1,42 @@
+package auto
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+const (
+	// callGas is the gas limit of each hook call.
+	callGas = 5_000_000
+
+	// overrideFeeFlag marks an lpFeeOverride returned by beforeSwap as an override, see LPFeeLibrary.
+	overrideFeeFlag = 0x400000
+)
+
+var (
+	ErrNoState          = errors.New("auto: hook state not tracked")
+	ErrStateNotCached   = errors.New("auto: hook reads state outside its snapshot")
+	ErrInvalidHookReply = errors.New("auto: invalid hook response")
+)
+
+var (
+	// minSqrtPriceLimit and maxSqrtPriceLimit are TickMath.MIN_SQRT_PRICE+1 and TickMath.MAX_SQRT_PRICE-1, the limits
+	// of unbounded swaps.
+	minSqrtPriceLimit = big.NewInt(4295128740)
+	maxSqrtPriceLimit = bignumber.NewBig10("1461446703485210103287273052203988822378723970341")
+
+	uint128Mask = new(big.Int).Sub(new(big.Int).Lsh(bignumber.One, 128), bignumber.One)
+
+	// isUnlockedSlot is the transient slot of the PoolManager lock, see Lock.sol.
+	isUnlockedSlot = common.Hash(new(uint256.Int).SubUint64(
+		new(uint256.Int).SetBytes(crypto.Keccak256([]byte("Unlocked"))), 1).Bytes32())
+
+	// probeDivisors size the swaps Track probes the hook with, as fractions of the pool reserves.
+	probeDivisors = []int64{10_000, 100, 5}
+)
@@ -0,0 +1,6 @@
+package auto
+
+import _ "embed"
+
+//go:embed abis/IHooks.json
+var hooksABIJson []byte
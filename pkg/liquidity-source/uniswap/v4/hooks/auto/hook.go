@@ -0,0 +1,236 @@
+package auto
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	uniswapv4 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v4"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/evmstate"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+// Hook is the fallback for hooks with swap permissions but without a dedicated implementation. It runs the
+// beforeSwap/afterSwap bytecode of the hook in an embedded EVM against a snapshot of the state the hook reads, which
+// Track collects.
+//
+// afterSwap runs against the snapshot as is: the writes of beforeSwap are only applied by UpdateBalance, and the
+// writes of afterSwap are not applied at all.
+type Hook struct {
+	uniswapv4.Hook `json:"-"`
+
+	State *evmstate.State `json:"st,omitempty"`
+
+	chainID     valueobject.ChainID
+	hookAddress common.Address
+	poolManager common.Address
+	sender      common.Address
+	poolKey     poolKey
+}
+
+// poolKey is the PoolKey struct of the PoolManager.
+type poolKey struct {
+	Currency0   common.Address
+	Currency1   common.Address
+	Fee         *big.Int
+	TickSpacing *big.Int
+	Hooks       common.Address
+}
+
+// swapParams is the SwapParams struct of the PoolManager.
+type swapParams struct {
+	ZeroForOne        bool
+	AmountSpecified   *big.Int
+	SqrtPriceLimitX96 *big.Int
+}
+
+var _ = uniswapv4.SetFallbackHookFactory(func(param *uniswapv4.HookParam) uniswapv4.Hook {
+	if !uniswapv4.HasSwapPermissions(param.HookAddress) {
+		return nil
+	}
+	hook := &Hook{
+		Hook:        &uniswapv4.BaseHook{Exchange: valueobject.ExchangeUniswapV4},
+		hookAddress: param.HookAddress,
+	}
+	if param.Cfg != nil {
+		hook.chainID = param.Cfg.ChainID
+		var ok bool
+		if hook.poolManager, ok = uniswapv4.PoolManager[hook.chainID]; !ok {
+			return nil
+		}
+	}
+	if param.Pool != nil {
+		hook.setPool(param.Pool)
+	}
+	_ = param.HookExtra.Unmarshal(hook)
+	return hook
+})
+
+func (h *Hook) setPool(p *entity.Pool) {
+	var staticExtra uniswapv4.StaticExtra
+	if err := json.Unmarshal([]byte(p.StaticExtra), &staticExtra); err != nil || len(p.Tokens) < 2 {
+		return
+	}
+	currencies := [2]common.Address{}
+	for i := range currencies {
+		if !staticExtra.IsNative[i] {
+			currencies[i] = common.HexToAddress(p.Tokens[i].Address)
+		}
+	}
+	h.sender = staticExtra.UniversalRouterAddress
+	h.poolKey = poolKey{
+		Currency0:   currencies[0],
+		Currency1:   currencies[1],
+		Fee:         big.NewInt(int64(staticExtra.Fee)),
+		TickSpacing: big.NewInt(int64(staticExtra.TickSpacing)),
+		Hooks:       h.hookAddress,
+	}
+}
+
+func (h *Hook) BeforeSwap(params *uniswapv4.BeforeSwapParams) (*uniswapv4.BeforeSwapResult, error) {
+	input, err := h.beforeSwapInput(params)
+	if err != nil {
+		return nil, err
+	}
+	res, err := h.call(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// see Hooks.beforeSwap
+	ret := res.Ret
+	if len(ret) != 96 || !bytes.Equal(ret[:4], hooksABI.Methods["beforeSwap"].ID) {
+		return nil, ErrInvalidHookReply
+	}
+	result := &uniswapv4.BeforeSwapResult{
+		DeltaSpecified:   bignumber.ZeroBI,
+		DeltaUnspecified: bignumber.ZeroBI,
+		Gas:              res.GasUsed,
+		SwapInfo:         res.Diff,
+	}
+	if lpFee := uint32(ret[93])<<16 | uint32(ret[94])<<8 | uint32(ret[95]); lpFee&overrideFeeFlag != 0 {
+		result.SwapFee = uniswapv4.FeeAmount(lpFee &^ overrideFeeFlag)
+	}
+	if permits(h.hookAddress, uniswapv4.BeforeSwapReturnsDelta) {
+		result.DeltaSpecified = signed(ret[32:48])
+		result.DeltaUnspecified = signed(ret[48:64])
+	}
+	return result, nil
+}
+
+func (h *Hook) AfterSwap(params *uniswapv4.AfterSwapParams) (*uniswapv4.AfterSwapResult, error) {
+	input, err := h.afterSwapInput(params)
+	if err != nil {
+		return nil, err
+	}
+	res, err := h.call(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// see Hooks.afterSwap
+	result := &uniswapv4.AfterSwapResult{HookFee: bignumber.ZeroBI, Gas: res.GasUsed}
+	ret := res.Ret
+	if len(ret) < 32 || !bytes.Equal(ret[:4], hooksABI.Methods["afterSwap"].ID) {
+		return nil, ErrInvalidHookReply
+	} else if !permits(h.hookAddress, uniswapv4.AfterSwapReturnsDelta) {
+		return result, nil
+	} else if len(ret) != 64 {
+		return nil, ErrInvalidHookReply
+	}
+	if result.HookFee = signed(ret[32:64]); result.HookFee.Cmp(signed(ret[48:64])) != 0 {
+		return nil, ErrInvalidHookReply // not an int128
+	}
+	return result, nil
+}
+
+func (h *Hook) CloneState() uniswapv4.Hook {
+	cloned := *h
+	if h.State != nil {
+		cloned.State = h.State.Clone()
+	}
+	return &cloned
+}
+
+func (h *Hook) UpdateBalance(swapInfo any) {
+	if diff, ok := swapInfo.(*evmstate.Diff); ok && h.State != nil {
+		h.State.Apply(diff)
+	}
+}
+
+func (h *Hook) beforeSwapInput(params *uniswapv4.BeforeSwapParams) ([]byte, error) {
+	return hooksABI.Pack("beforeSwap", h.sender, h.poolKey, h.swapParams(params), uniswapv4.EmptyBytes)
+}
+
+func (h *Hook) afterSwapInput(params *uniswapv4.AfterSwapParams) ([]byte, error) {
+	// the BalanceDelta of the swap, packing amount0 and amount1 as owed to the swapper
+	amount0, amount1 := new(big.Int).Neg(params.AmountIn), params.AmountOut
+	if !params.ZeroForOne {
+		amount0, amount1 = amount1, amount0
+	}
+	delta := new(big.Int).Lsh(amount0, 128)
+	delta.Add(delta, new(big.Int).And(amount1, uint128Mask))
+	return hooksABI.Pack("afterSwap", h.sender, h.poolKey, h.swapParams(params.BeforeSwapParams), delta,
+		uniswapv4.EmptyBytes)
+}
+
+func (h *Hook) swapParams(params *uniswapv4.BeforeSwapParams) swapParams {
+	amountSpecified := new(big.Int).Set(params.AmountSpecified)
+	if params.CalcOut {
+		amountSpecified.Neg(amountSpecified) // exact input
+	}
+	sqrtPriceLimitX96 := maxSqrtPriceLimit
+	if params.ZeroForOne {
+		sqrtPriceLimitX96 = minSqrtPriceLimit
+	}
+	return swapParams{
+		ZeroForOne:        params.ZeroForOne,
+		AmountSpecified:   amountSpecified,
+		SqrtPriceLimitX96: sqrtPriceLimitX96,
+	}
+}
+
+func (h *Hook) call(input []byte) (*evmstate.Result, error) {
+	if h.State == nil || h.State.Accounts[h.hookAddress] == nil {
+		return nil, ErrNoState
+	}
+	res, missing, err := h.State.Call(h.msg(input))
+	if err != nil {
+		return nil, err
+	} else if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrStateNotCached, missing)
+	}
+	return res, nil
+}
+
+// msg returns the hook call of input, as made by the PoolManager in the middle of a swap, i.e. unlocked.
+func (h *Hook) msg(input []byte) *evmstate.Msg {
+	return &evmstate.Msg{
+		ChainID:   h.chainID,
+		From:      h.poolManager,
+		To:        h.hookAddress,
+		Input:     input,
+		Gas:       callGas,
+		Transient: map[common.Address]map[common.Hash]common.Hash{h.poolManager: {isUnlockedSlot: {31: 1}}},
+	}
+}
+
+// permits reports whether the hook address has the permission flag of option, see Hooks.hasPermission.
+func permits(hookAddress common.Address, option uniswapv4.HookOption) bool {
+	flags := uint16(hookAddress[common.AddressLength-2])<<8 | uint16(hookAddress[common.AddressLength-1])
+	return flags&(1<<option) != 0
+}
+
+// signed returns the two's complement big-endian integer b.
+func signed(b []byte) *big.Int {
+	x := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		x.Sub(x, new(big.Int).Lsh(bignumber.One, uint(8*len(b))))
+	}
+	return x
+}
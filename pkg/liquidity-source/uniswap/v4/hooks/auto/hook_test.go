@@ -0,0 +1,150 @@
+package auto
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	uniswapv4 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v4"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/evmstate"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/testutil"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+var (
+	// testHookAddress has the beforeSwap, afterSwap, beforeSwapReturnsDelta and afterSwapReturnsDelta flags.
+	testHookAddress = common.HexToAddress("0x00000000000000000000000000000000000000cc")
+
+	// testHookCode returns (selector, sload(0), sload(2)), or (selector, sload(0)) for afterSwap, and increments slot 2:
+	//
+	//	mstore(0, shl(224, shr(224, calldataload(0))))
+	//	mstore(0x20, sload(0))
+	//	let n := sload(2)
+	//	mstore(0x40, n)
+	//	sstore(2, add(n, 1))
+	//	return(0, sub(0x60, shl(5, eq(shr(224, mload(0)), afterSwapSelector))))
+	testHookCode = common.FromHex("0x" +
+		"60003560e01c60e01b600052" +
+		"600054602052" +
+		"60025480604052" +
+		"600101600255" +
+		"606060005160e01c63" + common.Bytes2Hex(hooksABI.Methods["afterSwap"].ID) + "1460051b90036000f3")
+
+	testSlotDelta   = common.Hash{}
+	testSlotCounter = common.Hash{31: 2}
+)
+
+func newTestHook(t *testing.T, hookExtra []byte) *Hook {
+	t.Helper()
+	hook, ok := uniswapv4.GetHook(testHookAddress, &uniswapv4.HookParam{
+		Cfg: &uniswapv4.Config{ChainID: valueobject.ChainIDEthereum},
+		Pool: &entity.Pool{
+			Tokens: []*entity.PoolToken{
+				{Address: "0x0000000000000000000000000000000000000001"},
+				{Address: "0x0000000000000000000000000000000000000002"},
+			},
+			Reserves:    entity.PoolReserves{"1000000", "2000000"},
+			StaticExtra: `{"fee":8388608,"tS":60,"hooks":"` + testHookAddress.Hex() + `"}`,
+		},
+		HookExtra: hookExtra,
+	})
+	require.True(t, ok)
+	require.IsType(t, &Hook{}, hook)
+	return hook.(*Hook)
+}
+
+func newTestState() *evmstate.State {
+	poolManager := uniswapv4.PoolManager[valueobject.ChainIDEthereum]
+	return &evmstate.State{
+		BlockNumber: 100,
+		Time:        1700000000,
+		Accounts: map[common.Address]*evmstate.Account{
+			testHookAddress: {
+				Nonce: 1,
+				Code:  testHookCode,
+				Storage: map[common.Hash]common.Hash{
+					testSlotDelta:   common.BigToHash(big.NewInt(5)),
+					testSlotCounter: common.BigToHash(big.NewInt(overrideFeeFlag | 3000)),
+				},
+			},
+			poolManager: {Nonce: 1},
+		},
+	}
+}
+
+func TestHook(t *testing.T) {
+	t.Parallel()
+	hookExtra, err := json.Marshal(&Hook{State: newTestState()})
+	require.NoError(t, err)
+	hook := newTestHook(t, hookExtra)
+
+	params := &uniswapv4.BeforeSwapParams{CalcOut: true, ZeroForOne: true, AmountSpecified: big.NewInt(1e18)}
+	before, err := hook.BeforeSwap(params)
+	require.NoError(t, err)
+	assert.Zero(t, before.DeltaSpecified.Sign())
+	assert.Equal(t, big.NewInt(5), before.DeltaUnspecified)
+	assert.Equal(t, uniswapv4.FeeAmount(3000), before.SwapFee)
+	assert.Positive(t, before.Gas)
+
+	after, err := hook.AfterSwap(&uniswapv4.AfterSwapParams{
+		BeforeSwapParams: params,
+		AmountIn:         big.NewInt(1e18),
+		AmountOut:        big.NewInt(2e18),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(5), after.HookFee)
+
+	// the counter incremented by beforeSwap feeds the fee of the next swap, on the clone only
+	cloned := hook.CloneState()
+	cloned.UpdateBalance(before.SwapInfo)
+	next, err := cloned.BeforeSwap(params)
+	require.NoError(t, err)
+	assert.Equal(t, uniswapv4.FeeAmount(3001), next.SwapFee)
+	next, err = hook.BeforeSwap(params)
+	require.NoError(t, err)
+	assert.Equal(t, uniswapv4.FeeAmount(3000), next.SwapFee)
+}
+
+func TestHook_MissingState(t *testing.T) {
+	t.Parallel()
+	params := &uniswapv4.BeforeSwapParams{CalcOut: true, ZeroForOne: true, AmountSpecified: big.NewInt(1e18)}
+	_, err := newTestHook(t, nil).BeforeSwap(params)
+	assert.ErrorIs(t, err, ErrNoState)
+
+	state := newTestState()
+	delete(state.Accounts[testHookAddress].Storage, testSlotCounter)
+	hookExtra, err := json.Marshal(&Hook{State: state})
+	require.NoError(t, err)
+	_, err = newTestHook(t, hookExtra).BeforeSwap(params)
+	assert.ErrorIs(t, err, ErrStateNotCached)
+
+	// hooks without swap permissions are left to the base hook
+	_, ok := uniswapv4.GetHook(common.HexToAddress("0x0000000000000000000000000000000000000100"),
+		&uniswapv4.HookParam{Cfg: &uniswapv4.Config{ChainID: valueobject.ChainIDEthereum}})
+	assert.False(t, ok)
+}
+
+func TestHook_Track(t *testing.T) {
+	t.Parallel()
+	node, client := testutil.NewEVMStateNode(t, newTestState())
+	node.State.Accounts[testHookAddress].Storage[common.Hash{31: 9}] = common.Hash{31: 9} // never read
+
+	hook := newTestHook(t, nil)
+	hookExtra, err := hook.Track(context.Background(), &uniswapv4.HookParam{
+		RpcClient:   client,
+		Pool:        &entity.Pool{Reserves: entity.PoolReserves{"1000000", "2000000"}},
+		HookAddress: testHookAddress,
+	})
+	require.NoError(t, err)
+
+	tracked := newTestHook(t, hookExtra)
+	assert.Equal(t, newTestState(), tracked.State)
+	// a round fetching the accounts, then one fetching the slots read
+	assert.Equal(t, map[string]int{"eth_getBlockByNumber": 1, "eth_getProof": 3, "eth_getCode": 2}, node.Calls)
+}
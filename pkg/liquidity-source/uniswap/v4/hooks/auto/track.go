@@ -0,0 +1,91 @@
+package auto
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/goccy/go-json"
+
+	uniswapv4 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v4"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/evmstate"
+)
+
+// Track collects the state the hook reads when swapping. It probes the hook in the embedded EVM with swaps of a few
+// sizes in both directions, and fetches the accounts and storage slots the probes found missing, round after round
+// until the probes run through.
+func (h *Hook) Track(ctx context.Context, param *uniswapv4.HookParam) (json.RawMessage, error) {
+	probes, err := h.probes(param)
+	if err != nil {
+		return nil, err
+	}
+	seed := evmstate.Keys{}
+	seed.Add(h.hookAddress)
+	seed.Add(h.poolManager)
+	state, err := evmstate.Collect(ctx, param.RpcClient, param.BlockNumber, param.Overrides, seed,
+		func(state *evmstate.State) evmstate.Keys {
+			missing := evmstate.Keys{}
+			for _, input := range probes {
+				// a probe may revert, as the hook may well reject some swaps
+				_, probeMissing, _ := state.Call(h.msg(input))
+				missing.Merge(probeMissing)
+			}
+			return missing
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect hook state: %w", err)
+	}
+	h.State = state
+	return json.Marshal(h)
+}
+
+// probes returns the hook calls of swaps of the pool reserves divided by probeDivisors, exact input and output, in
+// both directions.
+func (h *Hook) probes(param *uniswapv4.HookParam) ([][]byte, error) {
+	var reserves [2]*big.Int
+	for i := range reserves {
+		reserves[i] = bignumber.One
+		if param.Pool != nil && i < len(param.Pool.Reserves) {
+			if reserve, ok := new(big.Int).SetString(param.Pool.Reserves[i], 10); ok && reserve.Sign() > 0 {
+				reserves[i] = reserve
+			}
+		}
+	}
+
+	var probes [][]byte
+	for _, divisor := range probeDivisors {
+		for _, zeroForOne := range []bool{true, false} {
+			for _, calcOut := range []bool{true, false} {
+				reserve := reserves[0]
+				if zeroForOne != calcOut {
+					reserve = reserves[1]
+				}
+				amount := new(big.Int).Div(reserve, big.NewInt(divisor))
+				if amount.Sign() == 0 {
+					amount = bignumber.One
+				}
+				params := &uniswapv4.BeforeSwapParams{CalcOut: calcOut, ZeroForOne: zeroForOne, AmountSpecified: amount}
+				if h.CanBeforeSwap(h.hookAddress) {
+					input, err := h.beforeSwapInput(params)
+					if err != nil {
+						return nil, err
+					}
+					probes = append(probes, input)
+				}
+				if h.CanAfterSwap(h.hookAddress) {
+					input, err := h.afterSwapInput(&uniswapv4.AfterSwapParams{
+						BeforeSwapParams: params,
+						AmountIn:         amount,
+						AmountOut:        amount,
+					})
+					if err != nil {
+						return nil, err
+					}
+					probes = append(probes, input)
+				}
+			}
+		}
+	}
+	return probes, nil
+}
@@ -5,11 +5,13 @@ import (
 	"maps"
 	"math/big"
 	"slices"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/goccy/go-json"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/gasmodel"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/pancake/infinity/shared"
 	uniswapv3 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v3"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v4/hooks/few"
@@ -23,9 +25,14 @@ type PoolSimulator struct {
 	hook          Hook
 	chainID       valueobject.ChainID
 	tokenWrappers []ITokenWrapper
+	// calibratedHookGas is the calibrated gas of the hook calls of a swap
+	calibratedHookGas *int64
 }
 
-var _ = pool.RegisterFactory1(DexType, NewPoolSimulator)
+var (
+	_ = pool.RegisterFactory1(DexType, NewPoolSimulator)
+	_ = gasmodel.RegisterPoolType(DexType)
+)
 
 func NewPoolSimulator(entityPool entity.Pool, chainID valueobject.ChainID) (*PoolSimulator, error) {
 	var extra ExtraU256
@@ -53,17 +60,40 @@ func NewPoolSimulator(entityPool entity.Pool, chainID valueobject.ChainID) (*Poo
 	if err != nil {
 		return nil, err
 	}
-	v3PoolSimulator.Gas = defaultGas
+	v3PoolSimulator.Gas = uniswapv3.GasFor(DexType, defaultGas)
+	var hookGas *int64
+	if model, ok := gasmodel.Lookup(DexType); ok {
+		if gas, ok := model.HookGas(staticExtra.HooksAddress.Hex()); ok {
+			hookGas = &gas
+		}
+	}
 
 	return &PoolSimulator{
-		PoolSimulator: v3PoolSimulator,
-		staticExtra:   staticExtra,
-		hook:          hook,
-		chainID:       chainID,
-		tokenWrappers: []ITokenWrapper{few.NewTokenWrapper()},
+		PoolSimulator:     v3PoolSimulator,
+		staticExtra:       staticExtra,
+		hook:              hook,
+		chainID:           chainID,
+		tokenWrappers:     []ITokenWrapper{few.NewTokenWrapper()},
+		calibratedHookGas: hookGas,
 	}, nil
 }
 
+// hookGas returns the gas of the hook calls of a swap: the calibrated gas of the hook if any, or else the estimates
+// of the hook.
+func (p *PoolSimulator) hookGas(beforeSwapResult *BeforeSwapResult, afterSwapResult *AfterSwapResult) int64 {
+	if p.calibratedHookGas != nil {
+		return *p.calibratedHookGas
+	}
+	var gas int64
+	if beforeSwapResult != nil {
+		gas += beforeSwapResult.Gas
+	}
+	if afterSwapResult != nil {
+		gas += afterSwapResult.Gas
+	}
+	return gas
+}
+
 func (p *PoolSimulator) CalcAmountOut(param pool.CalcAmountOutParams) (swapResult *pool.CalcAmountOutResult, err error) {
 	originalTokenIn, originalTokenOut := param.TokenAmountIn.Token, param.TokenOut
 	var wrapAdditionalGas int64
@@ -83,13 +113,16 @@ func (p *PoolSimulator) CalcAmountOut(param pool.CalcAmountOutParams) (swapResul
 
 			if beforeSwapResult != nil {
 				swapResult.TokenAmountOut.Amount.Sub(swapResult.TokenAmountOut.Amount, beforeSwapResult.DeltaUnspecified)
-				swapResult.Gas += beforeSwapResult.Gas
 				v4SwapInfo.HookSwapInfo = beforeSwapResult.SwapInfo
 			}
 
 			if afterSwapResult != nil {
 				swapResult.TokenAmountOut.Amount.Sub(swapResult.TokenAmountOut.Amount, afterSwapResult.HookFee)
-				swapResult.Gas += afterSwapResult.Gas
+			}
+
+			if beforeSwapResult != nil || afterSwapResult != nil {
+				swapResult.Gas += p.hookGas(beforeSwapResult, afterSwapResult)
+				v4SwapInfo.hook = strings.ToLower(p.staticExtra.HooksAddress.Hex())
 			}
 		}
 		swapResult.SwapInfo = v4SwapInfo
@@ -214,13 +247,16 @@ func (p *PoolSimulator) CalcAmountIn(param pool.CalcAmountInParams) (swapResult
 
 			if beforeSwapResult != nil {
 				swapResult.TokenAmountIn.Amount.Add(swapResult.TokenAmountIn.Amount, beforeSwapResult.DeltaUnspecified)
-				swapResult.Gas += beforeSwapResult.Gas
 				v4SwapInfo.HookSwapInfo = beforeSwapResult.SwapInfo
 			}
 
 			if afterSwapResult != nil {
 				swapResult.TokenAmountIn.Amount.Add(swapResult.TokenAmountIn.Amount, afterSwapResult.HookFee)
-				swapResult.Gas += afterSwapResult.Gas
+			}
+
+			if beforeSwapResult != nil || afterSwapResult != nil {
+				swapResult.Gas += p.hookGas(beforeSwapResult, afterSwapResult)
+				v4SwapInfo.hook = strings.ToLower(p.staticExtra.HooksAddress.Hex())
 			}
 		}
 		swapResult.SwapInfo = v4SwapInfo
@@ -325,6 +361,18 @@ func (p *PoolSimulator) CalcAmountIn(param pool.CalcAmountInParams) (swapResult
 	return
 }
 
+// GetSpotPrice returns the uniswap v3 spot price when no hook takes part in swaps. Pools with swap hooks and wrapped
+// tokens are priced numerically.
+func (p *PoolSimulator) GetSpotPrice(tokenIn, tokenOut string) (float64, error) {
+	if p.hook != nil && (p.hook.CanBeforeSwap(p.staticExtra.HooksAddress) ||
+		p.hook.CanAfterSwap(p.staticExtra.HooksAddress)) {
+		return 0, pool.ErrSpotPriceUnsupported
+	} else if p.GetTokenIndex(tokenIn) < 0 || p.GetTokenIndex(tokenOut) < 0 {
+		return 0, pool.ErrSpotPriceUnsupported
+	}
+	return p.PoolSimulator.GetSpotPrice(tokenIn, tokenOut)
+}
+
 func (p *PoolSimulator) CanSwapFrom(address string) []string {
 	return p.CanSwapTo(address)
 }
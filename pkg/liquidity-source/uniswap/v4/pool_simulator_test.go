@@ -10,6 +10,7 @@ import (
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/testutil"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
 )
 
@@ -97,3 +98,13 @@ func TestPoolSimulator_CalcAmountIn(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, bignumber.NewBig10("3823278233713"), got.TokenAmountOut.Amount)
 }
+
+func TestPoolSimulator_GetSpotPrice(t *testing.T) {
+	t.Parallel()
+	var poolEnt entity.Pool
+	assert.NoError(t, json.Unmarshal([]byte(poolData), &poolEnt))
+	pSim, err := NewPoolSimulator(poolEnt, valueobject.ChainIDEthereum)
+	assert.NoError(t, err)
+
+	testutil.TestSpotPrice(t, pSim, 1)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/holiman/uint256"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/gasmodel"
 	uniswapv3 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v3"
 )
 
@@ -16,6 +17,13 @@ type PoolSwapInfo = uniswapv3.SwapInfo
 type SwapInfo struct {
 	PoolSwapInfo
 	HookSwapInfo any
+	hook         string
+}
+
+func (si SwapInfo) GasFeatures() gasmodel.Features {
+	features := si.PoolSwapInfo.GasFeatures()
+	features.Hook = si.hook
+	return features
 }
 
 type SubgraphToken struct {
@@ -55,7 +55,7 @@ func main() {
 	emitf(outFileBuf, "func init() {\n")
 	for _, path := range paths {
 		file := fileByPath[path]
-		emitf(outFileBuf, "\t_ = msgpack.RegisterConcreteType(&%s.%s{})\n", nameByFile[file], structByFile[file])
+		emitf(outFileBuf, "\tregisterConcreteType(&%s.%s{})\n", nameByFile[file], structByFile[file])
 	}
 	emitf(outFileBuf, "}\n")
 }
@@ -69,8 +69,6 @@ func emitImports(outFileBuf io.Writer, dir string, paths []string, nameByFile, f
 	emitf(outFileBuf, "\n")
 
 	emitf(outFileBuf, "import (\n")
-	emitf(outFileBuf, "\t\"github.com/KyberNetwork/msgpack/v5\"\n")
-	emitf(outFileBuf, "\n")
 	for _, path := range paths {
 		emitf(outFileBuf, "\t%s \"%s\"\n", nameByFile[fileByPath[path]], path)
 	}
@@ -4,8 +4,6 @@ package msgpack
 //go:generate go run ./generate
 
 import (
-	"github.com/KyberNetwork/msgpack/v5"
-
 	pkg_liquiditysource_1010prop "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/1010-prop"
 	pkg_liquiditysource_aavev3 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/aave-v3"
 	pkg_liquiditysource_algebra_integral "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/algebra/integral"
@@ -19,6 +17,8 @@ import (
 	pkg_liquiditysource_axima "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/axima"
 	pkg_liquiditysource_balancer_v1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v1"
 	pkg_liquiditysource_balancer_v2_composablestable "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/composable-stable"
+	pkg_liquiditysource_balancer_v2_fx "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/fx"
+	pkg_liquiditysource_balancer_v2_linear "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/linear"
 	pkg_liquiditysource_balancer_v2_stable "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/stable"
 	pkg_liquiditysource_balancer_v2_weighted "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/weighted"
 	pkg_liquiditysource_balancer_v3_base "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v3/base"
@@ -72,6 +72,7 @@ import (
 	pkg_liquiditysource_etherfi_weeth "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/etherfi/weeth"
 	pkg_liquiditysource_eulerswap_v1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/euler-swap/v1"
 	pkg_liquiditysource_eulerswap_v2 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/euler-swap/v2"
+	pkg_liquiditysource_evmquoter "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/evm-quoter"
 	pkg_liquiditysource_feltir "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/feltir"
 	pkg_liquiditysource_flap "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/flap"
 	pkg_liquiditysource_fluid_atokenswap "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/fluid/atoken-swap"
@@ -84,6 +85,7 @@ import (
 	pkg_liquiditysource_genericarm "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/generic-arm"
 	pkg_liquiditysource_genericsimplerate "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/generic-simple-rate"
 	pkg_liquiditysource_ghost "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/ghost"
+	pkg_liquiditysource_gmxv2 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/gmx-v2"
 	pkg_liquiditysource_gohm "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/gohm"
 	pkg_liquiditysource_gsm4626 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/gsm-4626"
 	pkg_liquiditysource_gyroscope_2clp "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/gyroscope/2clp"
@@ -143,6 +145,7 @@ import (
 	pkg_liquiditysource_pandafun "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/pandafun"
 	pkg_liquiditysource_parityprop "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/parityprop"
 	pkg_liquiditysource_pendle_spendle "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/pendle/spendle"
+	pkg_liquiditysource_pendle_v2 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/pendle/v2"
 	pkg_liquiditysource_ponsv2 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/pons-v2"
 	pkg_liquiditysource_poolparty "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/pool-party"
 	pkg_liquiditysource_primeeth "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/primeeth"
@@ -169,6 +172,7 @@ import (
 	pkg_liquiditysource_umbrae_dlmm "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/umbrae/dlmm"
 	pkg_liquiditysource_unipool "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/unipool"
 	pkg_liquiditysource_uniswap_lo "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/lo"
+	pkg_liquiditysource_uniswap_uniswapx "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/uniswapx"
 	pkg_liquiditysource_uniswap_v1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v1"
 	pkg_liquiditysource_uniswap_v2 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v2"
 	pkg_liquiditysource_uniswap_v3 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v3"
@@ -180,6 +184,7 @@ import (
 	pkg_liquiditysource_uniswap_v4_hooks_angstrom "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v4/hooks/angstrom"
 	pkg_liquiditysource_uniswap_v4_hooks_arena "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v4/hooks/arena"
 	pkg_liquiditysource_uniswap_v4_hooks_arrakis "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v4/hooks/arrakis"
+	pkg_liquiditysource_uniswap_v4_hooks_auto "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v4/hooks/auto"
 	pkg_liquiditysource_uniswap_v4_hooks_bunniv2 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v4/hooks/bunni-v2"
 	pkg_liquiditysource_uniswap_v4_hooks_clanker "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v4/hooks/clanker"
 	pkg_liquiditysource_uniswap_v4_hooks_cult "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v4/hooks/cult"
@@ -225,6 +230,7 @@ import (
 	pkg_source_fxdx "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/fxdx"
 	pkg_source_gmx "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx"
 	pkg_source_gmxglp "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-glp"
+	pkg_source_gmxv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-v1"
 	pkg_source_iziswap "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/iziswap"
 	pkg_source_kokonutcrypto "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/kokonut-crypto"
 	pkg_source_lido "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/lido"
@@ -254,249 +260,257 @@ import (
 )
 
 func init() {
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_1010prop.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_aavev3.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_algebra_integral.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_algebra_v1.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_altfun.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_ambient.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_angletransmuter.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_arbera_den.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_arbera_zap.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_arenabc.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_axima.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_balancer_v1.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_balancer_v2_composablestable.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_balancer_v2_stable.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_balancer_v2_weighted.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_balancer_v3_base.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_balancer_v3_eclp.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_balancer_v3_quantamm.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_balancer_v3_reclamm.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_balancer_v3_stable.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_balancer_v3_weighted.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_bancorv21.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_bancorv3.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_baseline.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_bedrock_unibtc.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_bedrock_unieth.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_beetsss.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_bouncetech.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_brownfi.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_brownfi_v2.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_brownfi_v3.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_caliberprop.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_canonic.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_cap_cusd.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_capricornpamm.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_carbon.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_clear.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_clipper.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_cloberob.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_compound_v2.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_compound_v3.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_curve_llamma.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_curve_plain.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_curve_stablemetang.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_curve_stableng.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_curve_tricryptong.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_curve_twocryptong.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_daiusds.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_deltaswapv1.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_dodo_classical.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_dodo_dpp.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_dodo_dsp.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_dodo_dvm.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_ekubo.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_ekubo_v3.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_elfomofi.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_erc4626.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_ethena_susde.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_ethervista.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_etherfi_ebtc.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_etherfi_eeth.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_etherfi_liquid.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_etherfi_vampire.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_etherfi_weeth.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_eulerswap_v1.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_eulerswap_v2.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_feltir.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_flap.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_fluid_atokenswap.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_fluid_dexlite.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_fluid_dext1.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_fluid_dexv2.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_fluid_vaultt1.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_frax_sfrxeth.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_frax_sfrxethconvertor.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_genericarm.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_genericsimplerate.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_ghost.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_gohm.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_gsm4626.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_gyroscope_2clp.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_gyroscope_3clp.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_gyroscope_eclp.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_hiddenocean.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_honey.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_hyeth.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_hyperamm.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_infinifi_gateway.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_integral.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_kelp_rseth.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_kelp_rsethl2.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_kipseli_pamm.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_kipseli_prop.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_kuruob.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_ladder.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_lfj_poe.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_lglclob.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_liquidcore.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_liquidityparty.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_lista_stable.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_litepsm.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_lo1inch.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_lunarbase.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_machima.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_maker_savingsdai.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_maker_skypsm.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_mantle_cmeth.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_mantle_meth.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_maplesyrup.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_maverick_v1.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_maverick_v2.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_metronome_swap.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_midas.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_miromigrator.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_mkrsky.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_mooniswap.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_nabla.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_nadfun.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_nadswap.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_native_v3.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_nomiswap_nomiswapstable.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_obric.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_ondousdy.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_orderbook.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_overnightusdp.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_pancake_infinity_bin.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_pancake_infinity_cl.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_pancake_infinity_cl_hooks_alpha.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_pancake_infinity_cl_hooks_brevis.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_pancake_infinity_cl_hooks_dynamicfee.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_pancake_infinity_cl_hooks_feemanager.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_pancake_infinity_cl_hooks_limitorder.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_pancake_infinity_cl_hooks_stable.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_pancake_infinity_cl_hooks_tax.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_pandafun.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_parityprop.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_pendle_spendle.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_ponsv2.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_poolparty.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_primeeth.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_printr.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_puffer_pufeth.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_renzo_ezeth.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_ringswap.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_rocketpool_reth.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_smardex.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_smoothy.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_solidlyv2.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_someswap_v1.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_someswap_v2.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_stabull.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_staderethx.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_swell_rsweth.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_swell_sweth.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_syncswapv2_aqua.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_syncswapv2_classic.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_syncswapv2_stable.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_synthereum.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_tessera.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_umbrae_damm.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_umbrae_dlmm.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_unipool.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_lo.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v1.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v2.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v3.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v4.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_aegis.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_aegisprop.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_alpha.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_alphix.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_angstrom.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_arena.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_arrakis.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_bunniv2.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_clanker.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_cult.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_deli.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_doppler.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_flaunch.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_idle.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_livo.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_nft_strategy.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_renzo.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_st0x.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_stablestable.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_zora.Hook{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_usdai.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_usd0pp.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_valantisstex.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_velocorev2_cpmm.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_velocorev2_wombatstable.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_velodromev1.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_velodromev2.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_virtualfun_v1.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_virtualfun_v2.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_wasabiprop.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_wcm.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_whlp.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_wildcard.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_woofiv2.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_woofiv21.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_liquiditysource_xsolvbtc.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_camelot.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_curve_aave.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_curve_base.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_curve_compound.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_curve_meta.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_curve_plainoracle.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_curve_tricrypto.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_curve_two.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_dmm.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_elastic.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_equalizer.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_fraxswap.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_fulcrom.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_fxdx.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_gmx.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_gmxglp.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_iziswap.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_kokonutcrypto.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_lido.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_lidosteth.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_limitorder.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_liquiditybookv20.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_liquiditybookv21.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_madmex.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_makerpsm.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_mantisswap.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_metavault.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_platypus.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_polmatic.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_quickperps.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_saddle.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_swapbasedperp.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_syncswap_syncswapclassic.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_syncswap_syncswapstable.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_synthetix.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_uniswap.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_usdfi.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_velocimeter.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_vooi.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_wombat_wombatlsd.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_wombat_wombatmain.PoolSimulator{})
-	_ = msgpack.RegisterConcreteType(&pkg_source_zkerafinance.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_1010prop.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_aavev3.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_algebra_integral.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_algebra_v1.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_altfun.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_ambient.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_angletransmuter.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_arbera_den.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_arbera_zap.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_arenabc.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_axima.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_balancer_v1.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_balancer_v2_composablestable.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_balancer_v2_fx.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_balancer_v2_linear.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_balancer_v2_stable.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_balancer_v2_weighted.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_balancer_v3_base.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_balancer_v3_eclp.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_balancer_v3_quantamm.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_balancer_v3_reclamm.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_balancer_v3_stable.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_balancer_v3_weighted.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_bancorv21.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_bancorv3.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_baseline.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_bedrock_unibtc.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_bedrock_unieth.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_beetsss.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_bouncetech.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_brownfi.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_brownfi_v2.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_brownfi_v3.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_caliberprop.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_canonic.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_cap_cusd.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_capricornpamm.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_carbon.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_clear.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_clipper.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_cloberob.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_compound_v2.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_compound_v3.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_curve_llamma.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_curve_plain.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_curve_stablemetang.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_curve_stableng.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_curve_tricryptong.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_curve_twocryptong.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_daiusds.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_deltaswapv1.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_dodo_classical.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_dodo_dpp.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_dodo_dsp.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_dodo_dvm.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_ekubo.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_ekubo_v3.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_elfomofi.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_erc4626.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_ethena_susde.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_ethervista.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_etherfi_ebtc.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_etherfi_eeth.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_etherfi_liquid.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_etherfi_vampire.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_etherfi_weeth.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_eulerswap_v1.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_eulerswap_v2.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_evmquoter.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_feltir.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_flap.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_fluid_atokenswap.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_fluid_dexlite.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_fluid_dext1.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_fluid_dexv2.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_fluid_vaultt1.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_frax_sfrxeth.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_frax_sfrxethconvertor.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_genericarm.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_genericsimplerate.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_ghost.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_gmxv2.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_gohm.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_gsm4626.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_gyroscope_2clp.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_gyroscope_3clp.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_gyroscope_eclp.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_hiddenocean.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_honey.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_hyeth.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_hyperamm.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_infinifi_gateway.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_integral.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_kelp_rseth.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_kelp_rsethl2.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_kipseli_pamm.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_kipseli_prop.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_kuruob.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_ladder.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_lfj_poe.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_lglclob.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_liquidcore.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_liquidityparty.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_lista_stable.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_litepsm.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_lo1inch.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_lunarbase.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_machima.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_maker_savingsdai.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_maker_skypsm.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_mantle_cmeth.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_mantle_meth.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_maplesyrup.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_maverick_v1.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_maverick_v2.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_metronome_swap.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_midas.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_miromigrator.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_mkrsky.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_mooniswap.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_nabla.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_nadfun.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_nadswap.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_native_v3.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_nomiswap_nomiswapstable.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_obric.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_ondousdy.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_orderbook.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_overnightusdp.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_pancake_infinity_bin.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_pancake_infinity_cl.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_pancake_infinity_cl_hooks_alpha.Hook{})
+	registerConcreteType(&pkg_liquiditysource_pancake_infinity_cl_hooks_brevis.Hook{})
+	registerConcreteType(&pkg_liquiditysource_pancake_infinity_cl_hooks_dynamicfee.Hook{})
+	registerConcreteType(&pkg_liquiditysource_pancake_infinity_cl_hooks_feemanager.Hook{})
+	registerConcreteType(&pkg_liquiditysource_pancake_infinity_cl_hooks_limitorder.Hook{})
+	registerConcreteType(&pkg_liquiditysource_pancake_infinity_cl_hooks_stable.Hook{})
+	registerConcreteType(&pkg_liquiditysource_pancake_infinity_cl_hooks_tax.Hook{})
+	registerConcreteType(&pkg_liquiditysource_pandafun.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_parityprop.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_pendle_spendle.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_pendle_v2.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_ponsv2.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_poolparty.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_primeeth.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_printr.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_puffer_pufeth.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_renzo_ezeth.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_ringswap.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_rocketpool_reth.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_smardex.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_smoothy.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_solidlyv2.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_someswap_v1.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_someswap_v2.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_stabull.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_staderethx.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_swell_rsweth.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_swell_sweth.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_syncswapv2_aqua.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_syncswapv2_classic.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_syncswapv2_stable.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_synthereum.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_tessera.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_umbrae_damm.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_umbrae_dlmm.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_unipool.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_lo.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_uniswapx.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v1.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v2.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v3.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_aegis.Hook{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_aegisprop.Hook{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_alpha.Hook{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_alphix.Hook{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_angstrom.Hook{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_arena.Hook{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_arrakis.Hook{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_auto.Hook{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_bunniv2.Hook{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_clanker.Hook{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_cult.Hook{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_deli.Hook{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_doppler.Hook{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_flaunch.Hook{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_idle.Hook{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_livo.Hook{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_nft_strategy.Hook{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_renzo.Hook{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_st0x.Hook{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_stablestable.Hook{})
+	registerConcreteType(&pkg_liquiditysource_uniswap_v4_hooks_zora.Hook{})
+	registerConcreteType(&pkg_liquiditysource_usdai.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_usd0pp.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_valantisstex.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_velocorev2_cpmm.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_velocorev2_wombatstable.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_velodromev1.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_velodromev2.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_virtualfun_v1.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_virtualfun_v2.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_wasabiprop.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_wcm.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_whlp.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_wildcard.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_woofiv2.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_woofiv21.PoolSimulator{})
+	registerConcreteType(&pkg_liquiditysource_xsolvbtc.PoolSimulator{})
+	registerConcreteType(&pkg_source_camelot.PoolSimulator{})
+	registerConcreteType(&pkg_source_curve_aave.PoolSimulator{})
+	registerConcreteType(&pkg_source_curve_base.PoolSimulator{})
+	registerConcreteType(&pkg_source_curve_compound.PoolSimulator{})
+	registerConcreteType(&pkg_source_curve_meta.PoolSimulator{})
+	registerConcreteType(&pkg_source_curve_plainoracle.PoolSimulator{})
+	registerConcreteType(&pkg_source_curve_tricrypto.PoolSimulator{})
+	registerConcreteType(&pkg_source_curve_two.PoolSimulator{})
+	registerConcreteType(&pkg_source_dmm.PoolSimulator{})
+	registerConcreteType(&pkg_source_elastic.PoolSimulator{})
+	registerConcreteType(&pkg_source_equalizer.PoolSimulator{})
+	registerConcreteType(&pkg_source_fraxswap.PoolSimulator{})
+	registerConcreteType(&pkg_source_fulcrom.PoolSimulator{})
+	registerConcreteType(&pkg_source_fxdx.PoolSimulator{})
+	registerConcreteType(&pkg_source_gmx.PoolSimulator{})
+	registerConcreteType(&pkg_source_gmxglp.PoolSimulator{})
+	registerConcreteType(&pkg_source_gmxv1.PoolSimulator{})
+	registerConcreteType(&pkg_source_iziswap.PoolSimulator{})
+	registerConcreteType(&pkg_source_kokonutcrypto.PoolSimulator{})
+	registerConcreteType(&pkg_source_lido.PoolSimulator{})
+	registerConcreteType(&pkg_source_lidosteth.PoolSimulator{})
+	registerConcreteType(&pkg_source_limitorder.PoolSimulator{})
+	registerConcreteType(&pkg_source_liquiditybookv20.PoolSimulator{})
+	registerConcreteType(&pkg_source_liquiditybookv21.PoolSimulator{})
+	registerConcreteType(&pkg_source_madmex.PoolSimulator{})
+	registerConcreteType(&pkg_source_makerpsm.PoolSimulator{})
+	registerConcreteType(&pkg_source_mantisswap.PoolSimulator{})
+	registerConcreteType(&pkg_source_metavault.PoolSimulator{})
+	registerConcreteType(&pkg_source_platypus.PoolSimulator{})
+	registerConcreteType(&pkg_source_polmatic.PoolSimulator{})
+	registerConcreteType(&pkg_source_quickperps.PoolSimulator{})
+	registerConcreteType(&pkg_source_saddle.PoolSimulator{})
+	registerConcreteType(&pkg_source_swapbasedperp.PoolSimulator{})
+	registerConcreteType(&pkg_source_syncswap_syncswapclassic.PoolSimulator{})
+	registerConcreteType(&pkg_source_syncswap_syncswapstable.PoolSimulator{})
+	registerConcreteType(&pkg_source_synthetix.PoolSimulator{})
+	registerConcreteType(&pkg_source_uniswap.PoolSimulator{})
+	registerConcreteType(&pkg_source_usdfi.PoolSimulator{})
+	registerConcreteType(&pkg_source_velocimeter.PoolSimulator{})
+	registerConcreteType(&pkg_source_vooi.PoolSimulator{})
+	registerConcreteType(&pkg_source_wombat_wombatlsd.PoolSimulator{})
+	registerConcreteType(&pkg_source_wombat_wombatmain.PoolSimulator{})
+	registerConcreteType(&pkg_source_zkerafinance.PoolSimulator{})
 }
@@ -1,18 +1,16 @@
 package msgpack
 
 import (
+	"fmt"
+	"reflect"
+
 	"github.com/KyberNetwork/msgpack/v5"
 	pancakev3_entities "github.com/KyberNetwork/pancake-v3-sdk/entities"
 	uniswapv3uint256_entities "github.com/KyberNetwork/uniswapv3-sdk-uint256/entities"
 	uniswapv3_entities "github.com/daoleno/uniswapv3-sdk/entities"
 
-	pkg_source_gmx "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx"
-	pkg_source_gmxglp "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-glp"
-	pkg_source_madmex "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/madmex"
-	pkg_source_metavault "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/metavault"
-	pkg_source_quickperps "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/quickperps"
-	pkg_source_swapbasedperp "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/swapbased-perp"
-	pkg_source_zkerafinance "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/zkera-finance"
+	pkg_source_fxdx "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/fxdx"
+	pkg_source_gmxv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-v1"
 )
 
 func mustNotError(err error) {
@@ -21,34 +19,49 @@ func mustNotError(err error) {
 	}
 }
 
-func init() {
-	mustNotError(msgpack.RegisterConcreteType(&pkg_source_gmx.FastPriceFeedV1{}))
-	mustNotError(msgpack.RegisterConcreteType(&pkg_source_gmx.FastPriceFeedV2{}))
+var (
+	// concreteTypes maps the concrete type tags written by msgpack for interface values to the registered types.
+	concreteTypes = map[string]reflect.Type{}
+	// registerErrors maps the concrete type tags of types that failed to register to their errors.
+	registerErrors = map[string]error{}
+)
 
-	mustNotError(msgpack.RegisterConcreteType(&pkg_source_gmxglp.FastPriceFeedV1{}))
-	mustNotError(msgpack.RegisterConcreteType(&pkg_source_gmxglp.FastPriceFeedV2{}))
+// registerConcreteType registers v with msgpack and records its type so that snapshot records can be decoded into it.
+// It does not panic on failure: the type is left unregistered, and snapshots fail to write or load it with the error.
+func registerConcreteType(v any) {
+	typ := reflect.TypeOf(v)
+	if err := msgpack.RegisterConcreteType(v); err != nil {
+		registerErrors[concreteTypeTag(typ)] = err
+		return
+	}
+	concreteTypes[concreteTypeTag(typ)] = typ
+}
 
-	mustNotError(msgpack.RegisterConcreteType(&pkg_source_madmex.FastPriceFeedV1{}))
-	mustNotError(msgpack.RegisterConcreteType(&pkg_source_madmex.FastPriceFeedV2{}))
+// unknownTypeError returns ErrSnapshotUnknownType for the concrete type tag, with its registration error if any.
+func unknownTypeError(tag string) error {
+	if err, ok := registerErrors[tag]; ok {
+		return fmt.Errorf("%w: %s: %v", ErrSnapshotUnknownType, tag, err)
+	}
+	return fmt.Errorf("%w: %s", ErrSnapshotUnknownType, tag)
+}
 
-	mustNotError(msgpack.RegisterConcreteType(&pkg_source_metavault.FastPriceFeedV1{}))
-	mustNotError(msgpack.RegisterConcreteType(&pkg_source_metavault.FastPriceFeedV2{}))
+// concreteTypeTag returns the tag msgpack uses to identify the concrete type of interface values.
+func concreteTypeTag(typ reflect.Type) string {
+	if typ.Kind() == reflect.Pointer {
+		return "*" + typ.Elem().PkgPath() + " " + typ.Elem().Name()
+	}
+	return typ.PkgPath() + " " + typ.Name()
+}
 
-	mustNotError(msgpack.RegisterConcreteType(&pancakev3_entities.TickListDataProvider{}))
+func init() {
+	mustNotError(msgpack.RegisterConcreteType(&pkg_source_gmxv1.FastPriceFeedV1{}))
+	mustNotError(msgpack.RegisterConcreteType(&pkg_source_gmxv1.FastPriceFeedV2{}))
+	mustNotError(msgpack.RegisterConcreteType(&pkg_source_gmxv1.VaultUtils{}))
+	mustNotError(msgpack.RegisterConcreteType(&pkg_source_fxdx.FeeUtilsV2{}))
 
-	mustNotError(msgpack.RegisterConcreteType(&pkg_source_metavault.FastPriceFeedV1{}))
-	mustNotError(msgpack.RegisterConcreteType(&pkg_source_metavault.FastPriceFeedV2{}))
+	mustNotError(msgpack.RegisterConcreteType(&pancakev3_entities.TickListDataProvider{}))
 
 	mustNotError(msgpack.RegisterConcreteType(&uniswapv3_entities.TickListDataProvider{}))
 
 	mustNotError(msgpack.RegisterConcreteType(&uniswapv3uint256_entities.TickListDataProvider{}))
-
-	mustNotError(msgpack.RegisterConcreteType(&pkg_source_quickperps.FastPriceFeedV1{}))
-	mustNotError(msgpack.RegisterConcreteType(&pkg_source_quickperps.FastPriceFeedV2{}))
-
-	mustNotError(msgpack.RegisterConcreteType(&pkg_source_swapbasedperp.FastPriceFeedV1{}))
-	mustNotError(msgpack.RegisterConcreteType(&pkg_source_swapbasedperp.FastPriceFeedV2{}))
-
-	mustNotError(msgpack.RegisterConcreteType(&pkg_source_zkerafinance.FastPriceFeedV1{}))
-	mustNotError(msgpack.RegisterConcreteType(&pkg_source_zkerafinance.FastPriceFeedV2{}))
 }
@@ -0,0 +1,369 @@
+package msgpack
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"hash/fnv"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/KyberNetwork/msgpack/v5"
+	"github.com/klauspost/compress/snappy"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+// SnapshotVersion is the version of the snapshot framing written by SnapshotWriter. Snapshots of other versions are
+// refused by OpenSnapshot.
+const SnapshotVersion uint8 = 1
+
+var snapshotMagic = [4]byte{'K', 'S', 'P', 'S'}
+
+var (
+	ErrSnapshotInvalid         = errors.New("invalid pool snapshot")
+	ErrSnapshotVersion         = errors.New("unsupported pool snapshot version")
+	ErrSnapshotPoolNotFound    = errors.New("pool not found in snapshot")
+	ErrSnapshotUnknownType     = errors.New("pool simulator type is not registered")
+	ErrSnapshotStaleSchema     = errors.New("pool simulator schema changed since snapshot was written")
+	ErrSnapshotChecksumInvalid = errors.New("pool snapshot record checksum mismatch")
+)
+
+var (
+	customEncoderType = reflect.TypeOf((*msgpack.CustomEncoder)(nil)).Elem()
+	marshalerType     = reflect.TypeOf((*msgpack.Marshaler)(nil)).Elem()
+	schemaHashes      sync.Map // reflect.Type -> string
+)
+
+// SnapshotRecord describes a pool record of a snapshot.
+type SnapshotRecord struct {
+	Address string
+	// PoolType is the simulator's GetType().
+	PoolType string
+	// ConcreteType is the msgpack concrete type tag of the simulator struct.
+	ConcreteType string
+	// SchemaHash is the SchemaHash of the simulator struct at the time the record was written.
+	SchemaHash string
+	Checksum   uint32
+
+	offset int64
+	size   int64
+}
+
+// recordHeader is the encoded header of a snapshot record. Its layout is covered by SnapshotVersion.
+type recordHeader struct {
+	Address      string
+	PoolType     string
+	ConcreteType string
+	SchemaHash   string
+	Checksum     uint32
+}
+
+// SnapshotWriter writes pool simulators as a stream of length-prefixed records. Each record is made of a header
+// (address, pool type, concrete type, schema hash and checksum) followed by the Snappy compressed msgpack encoding of
+// the simulator, so that a reader can index all records by only reading their headers.
+type SnapshotWriter struct {
+	w io.Writer
+}
+
+// NewSnapshotWriter writes the snapshot preamble to w and returns a SnapshotWriter appending records to it.
+func NewSnapshotWriter(w io.Writer) (*SnapshotWriter, error) {
+	if _, err := w.Write(append(snapshotMagic[:], SnapshotVersion)); err != nil {
+		return nil, err
+	}
+	return &SnapshotWriter{w: w}, nil
+}
+
+// Write appends a record for the pool simulator. The simulator type must be registered with msgpack.
+func (sw *SnapshotWriter) Write(address string, poolSim pool.IPoolSimulator) error {
+	typ := reflect.TypeOf(poolSim)
+	if typ == nil {
+		return fmt.Errorf("%w: nil simulator for pool %s", ErrSnapshotUnknownType, address)
+	}
+	tag := concreteTypeTag(typ)
+	if _, ok := concreteTypes[tag]; !ok {
+		return unknownTypeError(tag)
+	}
+
+	var raw bytes.Buffer
+	en := NewEncoder(&raw)
+	defer PutEncoder(en)
+	if err := en.Encode(poolSim); err != nil {
+		return fmt.Errorf("encode pool %s: %w", address, err)
+	}
+	payload := snappy.Encode(nil, raw.Bytes())
+
+	return sw.writeRecord(SnapshotRecord{
+		Address:      address,
+		PoolType:     poolSim.GetType(),
+		ConcreteType: tag,
+		SchemaHash:   SchemaHash(typ),
+		Checksum:     crc32.ChecksumIEEE(payload),
+	}, payload)
+}
+
+func (sw *SnapshotWriter) writeRecord(rec SnapshotRecord, payload []byte) error {
+	var header bytes.Buffer
+	en := NewEncoder(&header)
+	defer PutEncoder(en)
+	if err := en.Encode(recordHeader{rec.Address, rec.PoolType, rec.ConcreteType, rec.SchemaHash,
+		rec.Checksum}); err != nil {
+		return err
+	}
+	buf := make([]byte, 0, 2*binary.MaxVarintLen64+header.Len()+len(payload))
+	buf = binary.AppendUvarint(buf, uint64(header.Len()))
+	buf = append(buf, header.Bytes()...)
+	buf = binary.AppendUvarint(buf, uint64(len(payload)))
+	buf = append(buf, payload...)
+	_, err := sw.w.Write(buf)
+	return err
+}
+
+// EncodePoolSnapshot encodes a map from pool ID to IPoolSimulator as a snapshot, in address order.
+func EncodePoolSnapshot(poolsMap map[string]pool.IPoolSimulator) ([]byte, error) {
+	var buf bytes.Buffer
+	sw, err := NewSnapshotWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+	for _, address := range slices.Sorted(maps.Keys(poolsMap)) {
+		if err := sw.Write(address, poolsMap[address]); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// Snapshot is an index over the records of a snapshot. Pool simulators are only decoded when loaded.
+type Snapshot struct {
+	r       io.ReaderAt
+	records map[string]SnapshotRecord
+	order   []string
+}
+
+// DecodePoolSnapshot indexes a snapshot held in memory.
+func DecodePoolSnapshot(encoded []byte) (*Snapshot, error) {
+	return OpenSnapshot(bytes.NewReader(encoded), int64(len(encoded)))
+}
+
+// OpenSnapshot indexes the snapshot of the given size read from r by reading the record headers only. r must stay
+// readable for as long as pools are loaded from the returned Snapshot.
+func OpenSnapshot(r io.ReaderAt, size int64) (*Snapshot, error) {
+	var preamble [len(snapshotMagic) + 1]byte
+	if _, err := r.ReadAt(preamble[:], 0); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSnapshotInvalid, err)
+	}
+	if !bytes.Equal(preamble[:len(snapshotMagic)], snapshotMagic[:]) {
+		return nil, fmt.Errorf("%w: bad magic", ErrSnapshotInvalid)
+	}
+	if version := preamble[len(snapshotMagic)]; version != SnapshotVersion {
+		return nil, fmt.Errorf("%w: got %d, want %d", ErrSnapshotVersion, version, SnapshotVersion)
+	}
+
+	s := &Snapshot{r: r, records: make(map[string]SnapshotRecord)}
+	for offset := int64(len(preamble)); offset < size; {
+		headerLen, n, err := readUvarintAt(r, offset, size)
+		if err != nil {
+			return nil, err
+		}
+		offset += n
+		if headerLen > uint64(size-offset) {
+			return nil, fmt.Errorf("%w: truncated record header at %d", ErrSnapshotInvalid, offset)
+		}
+		header := make([]byte, headerLen)
+		if _, err = r.ReadAt(header, offset); err != nil {
+			return nil, fmt.Errorf("%w: record header at %d: %v", ErrSnapshotInvalid, offset, err)
+		}
+		offset += int64(headerLen)
+
+		var h recordHeader
+		de := NewDecoder(bytes.NewReader(header))
+		err = de.Decode(&h)
+		PutDecoder(de)
+		if err != nil {
+			return nil, fmt.Errorf("%w: record header at %d: %v", ErrSnapshotInvalid, offset, err)
+		}
+		rec := SnapshotRecord{
+			Address:      h.Address,
+			PoolType:     h.PoolType,
+			ConcreteType: h.ConcreteType,
+			SchemaHash:   h.SchemaHash,
+			Checksum:     h.Checksum,
+		}
+
+		payloadLen, n, err := readUvarintAt(r, offset, size)
+		if err != nil {
+			return nil, err
+		}
+		offset += n
+		if payloadLen > uint64(size-offset) {
+			return nil, fmt.Errorf("%w: truncated record for pool %s", ErrSnapshotInvalid, rec.Address)
+		}
+		rec.offset, rec.size = offset, int64(payloadLen)
+		offset += int64(payloadLen)
+
+		if _, ok := s.records[rec.Address]; !ok {
+			s.order = append(s.order, rec.Address)
+		}
+		s.records[rec.Address] = rec
+	}
+	return s, nil
+}
+
+// Addresses returns the addresses of the pools in the snapshot, in the order they were written.
+func (s *Snapshot) Addresses() []string {
+	return slices.Clone(s.order)
+}
+
+// Record returns the record header of a pool.
+func (s *Snapshot) Record(address string) (SnapshotRecord, bool) {
+	rec, ok := s.records[address]
+	return rec, ok
+}
+
+// Load decodes the pool simulator of a pool. It fails with ErrSnapshotStaleSchema if the simulator struct has changed
+// since the snapshot was written, and with ErrSnapshotUnknownType if the simulator type is no longer registered.
+func (s *Snapshot) Load(address string) (pool.IPoolSimulator, error) {
+	rec, ok := s.records[address]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSnapshotPoolNotFound, address)
+	}
+	typ, ok := concreteTypes[rec.ConcreteType]
+	if !ok {
+		return nil, fmt.Errorf("pool %s of type %s: %w", address, rec.PoolType, unknownTypeError(rec.ConcreteType))
+	}
+	if schemaHash := SchemaHash(typ); schemaHash != rec.SchemaHash {
+		return nil, fmt.Errorf("%w: pool %s of type %s (%s) was written with schema %s, current schema is %s",
+			ErrSnapshotStaleSchema, address, rec.PoolType, rec.ConcreteType, rec.SchemaHash, schemaHash)
+	}
+
+	payload := make([]byte, rec.size)
+	if _, err := s.r.ReadAt(payload, rec.offset); err != nil {
+		return nil, fmt.Errorf("%w: pool %s: %v", ErrSnapshotInvalid, address, err)
+	}
+	if crc32.ChecksumIEEE(payload) != rec.Checksum {
+		return nil, fmt.Errorf("%w: pool %s", ErrSnapshotChecksumInvalid, address)
+	}
+	raw, err := snappy.Decode(nil, payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: pool %s: %v", ErrSnapshotInvalid, address, err)
+	}
+
+	var v reflect.Value
+	if typ.Kind() == reflect.Pointer {
+		v = reflect.New(typ.Elem())
+	} else {
+		v = reflect.New(typ)
+	}
+	de := NewDecoder(bytes.NewReader(raw))
+	defer PutDecoder(de)
+	if err = de.DecodeValue(v); err != nil {
+		return nil, fmt.Errorf("decode pool %s: %w", address, err)
+	}
+	if typ.Kind() != reflect.Pointer {
+		v = v.Elem()
+	}
+	poolSim, ok := v.Interface().(pool.IPoolSimulator)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s does not implement IPoolSimulator", ErrSnapshotUnknownType, rec.ConcreteType)
+	}
+	return poolSim, nil
+}
+
+// LoadAll loads every pool of the snapshot. Pools that fail to load are left out of the returned map and their errors
+// are joined into the returned error, so that callers can warm start from the rest and rebuild the others.
+func (s *Snapshot) LoadAll() (map[string]pool.IPoolSimulator, error) {
+	poolsMap := make(map[string]pool.IPoolSimulator, len(s.order))
+	var errs []error
+	for _, address := range s.order {
+		poolSim, err := s.Load(address)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		poolsMap[address] = poolSim
+	}
+	return poolsMap, errors.Join(errs...)
+}
+
+// SchemaHash returns a hash of the msgpack layout of typ: field names, msgpack tags, kinds and element types, walked
+// recursively. Types with custom msgpack encoders contribute their name only, and interface fields their interface
+// type only.
+func SchemaHash(typ reflect.Type) string {
+	if hash, ok := schemaHashes.Load(typ); ok {
+		return hash.(string)
+	}
+	h := fnv.New64a()
+	writeSchema(h, typ, make(map[reflect.Type]struct{}))
+	hash := fmt.Sprintf("%016x", h.Sum64())
+	schemaHashes.Store(typ, hash)
+	return hash
+}
+
+func writeSchema(w io.Writer, typ reflect.Type, visiting map[reflect.Type]struct{}) {
+	if _, ok := visiting[typ]; ok {
+		_, _ = fmt.Fprintf(w, "ref(%s)", typ)
+		return
+	}
+	visiting[typ] = struct{}{}
+	defer delete(visiting, typ)
+
+	if typ.Implements(customEncoderType) || typ.Implements(marshalerType) ||
+		typ.Kind() != reflect.Pointer && (reflect.PointerTo(typ).Implements(customEncoderType) ||
+			reflect.PointerTo(typ).Implements(marshalerType)) {
+		_, _ = fmt.Fprintf(w, "custom(%s)", typ)
+		return
+	}
+
+	switch typ.Kind() {
+	case reflect.Pointer:
+		_, _ = io.WriteString(w, "*")
+		writeSchema(w, typ.Elem(), visiting)
+	case reflect.Slice:
+		_, _ = io.WriteString(w, "[]")
+		writeSchema(w, typ.Elem(), visiting)
+	case reflect.Array:
+		_, _ = fmt.Fprintf(w, "[%d]", typ.Len())
+		writeSchema(w, typ.Elem(), visiting)
+	case reflect.Map:
+		_, _ = io.WriteString(w, "map[")
+		writeSchema(w, typ.Key(), visiting)
+		_, _ = io.WriteString(w, "]")
+		writeSchema(w, typ.Elem(), visiting)
+	case reflect.Struct:
+		_, _ = io.WriteString(w, "struct{")
+		for i := range typ.NumField() {
+			f := typ.Field(i)
+			tag := f.Tag.Get("msgpack")
+			if tag == "-" {
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "%s %q ", f.Name, tag)
+			writeSchema(w, f.Type, visiting)
+			_, _ = io.WriteString(w, ";")
+		}
+		_, _ = io.WriteString(w, "}")
+	case reflect.Interface:
+		_, _ = fmt.Fprintf(w, "interface(%s)", typ)
+	default:
+		_, _ = io.WriteString(w, typ.Kind().String())
+	}
+}
+
+func readUvarintAt(r io.ReaderAt, offset, size int64) (uint64, int64, error) {
+	var buf [binary.MaxVarintLen64]byte
+	n, err := r.ReadAt(buf[:min(int64(len(buf)), size-offset)], offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, 0, fmt.Errorf("%w: %v", ErrSnapshotInvalid, err)
+	}
+	v, read := binary.Uvarint(buf[:n])
+	if read <= 0 {
+		return 0, 0, fmt.Errorf("%w: bad record length at %d", ErrSnapshotInvalid, offset)
+	}
+	return v, int64(read), nil
+}
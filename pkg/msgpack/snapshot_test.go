@@ -0,0 +1,156 @@
+package msgpack
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	uniswapv2 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v2"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+func newUniswapV2Pool(t *testing.T, address string, reserve0, reserve1 string) *uniswapv2.PoolSimulator {
+	t.Helper()
+	p, err := uniswapv2.NewPoolSimulator(entity.Pool{
+		Address:  address,
+		Exchange: "uniswap",
+		Type:     uniswapv2.DexType,
+		Reserves: entity.PoolReserves{reserve0, reserve1},
+		Tokens:   []*entity.PoolToken{{Address: "0xa", Decimals: 18}, {Address: "0xb", Decimals: 18}},
+		Extra:    `{"fee":3,"feePrecision":1000}`,
+	})
+	require.NoError(t, err)
+	return p
+}
+
+func TestPoolSnapshot(t *testing.T) {
+	t.Parallel()
+	pools := map[string]pool.IPoolSimulator{
+		"0x2": newUniswapV2Pool(t, "0x2", "1000000", "2000000"),
+		"0x1": newUniswapV2Pool(t, "0x1", "3000000", "4000000"),
+	}
+	encoded, err := EncodePoolSnapshot(pools)
+	require.NoError(t, err)
+
+	snapshot, err := DecodePoolSnapshot(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0x1", "0x2"}, snapshot.Addresses())
+
+	rec, ok := snapshot.Record("0x1")
+	require.True(t, ok)
+	assert.Equal(t, uniswapv2.DexType, rec.PoolType)
+	assert.Equal(t, SchemaHash(reflect.TypeOf(&uniswapv2.PoolSimulator{})), rec.SchemaHash)
+
+	loaded, err := snapshot.Load("0x2")
+	require.NoError(t, err)
+	tokenAmountIn := pool.TokenAmount{Token: "0xa", Amount: big.NewInt(10000)}
+	want, err := pools["0x2"].CalcAmountOut(pool.CalcAmountOutParams{TokenAmountIn: tokenAmountIn, TokenOut: "0xb"})
+	require.NoError(t, err)
+	got, err := loaded.CalcAmountOut(pool.CalcAmountOutParams{TokenAmountIn: tokenAmountIn, TokenOut: "0xb"})
+	require.NoError(t, err)
+	assert.Equal(t, want.TokenAmountOut.Amount, got.TokenAmountOut.Amount)
+
+	all, err := snapshot.LoadAll()
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	_, err = snapshot.Load("0x3")
+	assert.ErrorIs(t, err, ErrSnapshotPoolNotFound)
+}
+
+func TestPoolSnapshot_Refused(t *testing.T) {
+	t.Parallel()
+	p := newUniswapV2Pool(t, "0x1", "1000000", "2000000")
+	tag := concreteTypeTag(reflect.TypeOf(p))
+
+	writeRecord := func(rec SnapshotRecord, payload []byte) []byte {
+		var buf bytes.Buffer
+		sw, err := NewSnapshotWriter(&buf)
+		require.NoError(t, err)
+		require.NoError(t, sw.Write("0x1", p))
+		rec.Checksum = crc32.ChecksumIEEE(payload)
+		require.NoError(t, sw.writeRecord(rec, payload))
+		return buf.Bytes()
+	}
+
+	t.Run("stale schema", func(t *testing.T) {
+		snapshot, err := DecodePoolSnapshot(writeRecord(SnapshotRecord{Address: "0x2", PoolType: uniswapv2.DexType,
+			ConcreteType: tag, SchemaHash: "0000000000000000"}, []byte{0}))
+		require.NoError(t, err)
+		_, err = snapshot.Load("0x1")
+		require.NoError(t, err)
+		_, err = snapshot.Load("0x2")
+		assert.ErrorIs(t, err, ErrSnapshotStaleSchema)
+		assert.ErrorContains(t, err, "0x2")
+
+		all, err := snapshot.LoadAll()
+		assert.ErrorIs(t, err, ErrSnapshotStaleSchema)
+		assert.Len(t, all, 1)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		snapshot, err := DecodePoolSnapshot(writeRecord(SnapshotRecord{Address: "0x2", PoolType: "removed",
+			ConcreteType: "*example.com/removed PoolSimulator"}, []byte{0}))
+		require.NoError(t, err)
+		_, err = snapshot.Load("0x2")
+		assert.ErrorIs(t, err, ErrSnapshotUnknownType)
+	})
+
+	t.Run("version", func(t *testing.T) {
+		encoded := writeRecord(SnapshotRecord{Address: "0x2"}, nil)
+		encoded[len(snapshotMagic)] = SnapshotVersion + 1
+		_, err := DecodePoolSnapshot(encoded)
+		assert.ErrorIs(t, err, ErrSnapshotVersion)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		encoded := writeRecord(SnapshotRecord{Address: "0x2"}, []byte{1, 2, 3})
+		_, err := DecodePoolSnapshot(encoded[:len(encoded)-1])
+		assert.ErrorIs(t, err, ErrSnapshotInvalid)
+	})
+
+	t.Run("record length overflow", func(t *testing.T) {
+		// lengths past math.MaxInt64 must not wrap around the size check
+		encoded := binary.AppendUvarint(append(snapshotMagic[:], SnapshotVersion), math.MaxUint64)
+		_, err := DecodePoolSnapshot(encoded)
+		assert.ErrorIs(t, err, ErrSnapshotInvalid)
+	})
+}
+
+func TestSchemaHash(t *testing.T) {
+	t.Parallel()
+	type node struct {
+		Value *big.Int
+		Next  *node
+	}
+	type state struct {
+		Reserves []*big.Int
+		Fee      uint64
+	}
+	type renamed struct {
+		Balances []*big.Int
+		Fee      uint64
+	}
+	type skipped struct {
+		Reserves []*big.Int
+		Fee      uint64
+		Cache    map[string]int `msgpack:"-"`
+	}
+	type added struct {
+		Reserves []*big.Int
+		Fee      uint64
+		Cache    map[string]int
+	}
+	assert.Equal(t, SchemaHash(reflect.TypeOf(node{})), SchemaHash(reflect.TypeOf(node{})))
+	assert.Equal(t, SchemaHash(reflect.TypeOf(state{})), SchemaHash(reflect.TypeOf(skipped{})))
+	assert.NotEqual(t, SchemaHash(reflect.TypeOf(state{})), SchemaHash(reflect.TypeOf(renamed{})))
+	assert.NotEqual(t, SchemaHash(reflect.TypeOf(state{})), SchemaHash(reflect.TypeOf(added{})))
+}
@@ -0,0 +1,12 @@
+package pathfinder
+
+import "errors"
+
+var (
+	ErrInvalidAmountIn      = errors.New("invalid amount in")
+	ErrNoPathFound          = errors.New("no path found")
+	ErrPoolNotFound         = errors.New("pool not found")
+	ErrPoolStateNotClonable = errors.New("pool state is not clonable and has already been used")
+	ErrInvalidSwapResult    = errors.New("invalid swap result")
+	ErrPartialFill          = errors.New("pool only partially filled the amount in")
+)
@@ -0,0 +1,208 @@
+package pathfinder
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/samber/lo"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+const (
+	defaultMaxHops       = 3
+	defaultMaxCandidates = 512
+	defaultSplitParts    = 10
+)
+
+// Config configures a Finder. Zero values fall back to sensible defaults.
+type Config struct {
+	// MaxHops is the maximum number of hops of a path.
+	MaxHops int
+	// MaxCandidates bounds the number of candidate paths enumerated per search.
+	MaxCandidates int
+	// SplitParts is the number of equal chunks amountIn is split into by FindRoute.
+	SplitParts int
+}
+
+// Finder is a reference path-finder over pool.IPoolSimulator graphs. It searches bounded-hop paths with
+// pool.CalcAmountOut and applies UpdateBalance on CloneState copies, so paths that reuse a pool (within a path or
+// across split parts) are priced against the updated pool state. Swap limits are honored for exchanges registered
+// via pool.RegisterUseSwapLimit; the legs of a path through maker pools sharing a swaplimit.MakerInventory are reserved
+// together, all or none.
+type Finder struct {
+	graph  *Graph
+	config Config
+}
+
+// NewFinder creates a Finder over the given pools.
+func NewFinder(pools []pool.IPoolSimulator, config Config) *Finder {
+	config.MaxHops = lo.Ternary(config.MaxHops > 0, config.MaxHops, defaultMaxHops)
+	config.MaxCandidates = lo.Ternary(config.MaxCandidates > 0, config.MaxCandidates, defaultMaxCandidates)
+	config.SplitParts = lo.Ternary(config.SplitParts > 0, config.SplitParts, defaultSplitParts)
+	return &Finder{
+		graph:  NewGraph(pools),
+		config: config,
+	}
+}
+
+// Graph returns the token graph the finder searches on.
+func (f *Finder) Graph() *Graph {
+	return f.graph
+}
+
+// FindBestPath returns the single path yielding the most tokenOut for amountIn. limits maps exchange to its
+// SwapLimit; it is never modified.
+func (f *Finder) FindBestPath(ctx context.Context, tokenIn, tokenOut string, amountIn *big.Int,
+	limits map[string]pool.SwapLimit) (*Path, error) {
+	if amountIn == nil || amountIn.Sign() <= 0 {
+		return nil, ErrInvalidAmountIn
+	}
+	candidates := f.graph.candidatePaths(tokenIn, tokenOut, f.config.MaxHops, f.config.MaxCandidates)
+	best, _ := f.bestPath(ctx, newState(f.graph, limits), candidates, amountIn)
+	if best == nil {
+		return nil, ErrNoPathFound
+	}
+	return best, nil
+}
+
+// FindRoute splits amountIn into Config.SplitParts equal chunks and greedily assigns each chunk to the best path
+// given the state left by the previous chunks. Chunks assigned to the same path are merged, and the merged paths are
+// then re-priced in order on a fresh state. Merged paths that fail to re-price are dropped and their amount is routed
+// through the best other path, or the whole amount through the best single path if there is none. limits is never
+// modified.
+func (f *Finder) FindRoute(ctx context.Context, tokenIn, tokenOut string, amountIn *big.Int,
+	limits map[string]pool.SwapLimit) (*Route, error) {
+	if amountIn == nil || amountIn.Sign() <= 0 {
+		return nil, ErrInvalidAmountIn
+	}
+	candidates := f.graph.candidatePaths(tokenIn, tokenOut, f.config.MaxHops, f.config.MaxCandidates)
+	if len(candidates) == 0 {
+		return nil, ErrNoPathFound
+	}
+
+	parts := int64(f.config.SplitParts)
+	if amountIn.Cmp(big.NewInt(parts)) < 0 {
+		parts = 1
+	}
+	chunk := new(big.Int).Quo(amountIn, big.NewInt(parts))
+	lastChunk := new(big.Int).Sub(amountIn, new(big.Int).Mul(chunk, big.NewInt(parts-1)))
+
+	var (
+		committed = newState(f.graph, limits)
+		paths     []*Path
+		pathIdx   = make(map[string]int)
+	)
+	for i := range parts {
+		partAmountIn := lo.Ternary(i == parts-1, lastChunk, chunk)
+		best, next := f.bestPath(ctx, committed, candidates, partAmountIn)
+		if best == nil {
+			return nil, ErrNoPathFound
+		}
+		committed = next
+
+		if idx, ok := pathIdx[best.key()]; ok {
+			paths[idx].AmountIn = new(big.Int).Add(paths[idx].AmountIn, best.AmountIn)
+			continue
+		}
+		pathIdx[best.key()] = len(paths)
+		paths = append(paths, best)
+	}
+
+	// merged chunks are executed as a single swap per path, so re-price them in order on a fresh state. A merged path
+	// can fail where its chunks did not (e.g. a pool that cannot be cloned or rejects the larger amount); it is
+	// skipped and its amount goes to the best remaining path instead.
+	var (
+		final    = newState(f.graph, limits)
+		kept     = paths[:0]
+		skipped  = new(big.Int)
+		keptKeys = make(map[string]struct{}, len(paths))
+	)
+	for _, p := range paths {
+		s := final.fork()
+		repriced, err := simulatePath(ctx, s, p.Edges, p.AmountIn)
+		if err != nil {
+			skipped.Add(skipped, p.AmountIn)
+			continue
+		}
+		final = s
+		kept = append(kept, repriced)
+		keptKeys[repriced.key()] = struct{}{}
+	}
+	paths = kept
+	if skipped.Sign() > 0 {
+		remaining := lo.Filter(candidates, func(edges []Edge, _ int) bool {
+			_, ok := keptKeys[(&Path{Edges: edges}).key()]
+			return !ok
+		})
+		if best, _ := f.bestPath(ctx, final, remaining, skipped); best != nil {
+			paths = append(paths, best)
+		} else if best, _ = f.bestPath(ctx, newState(f.graph, limits), candidates, amountIn); best != nil {
+			paths = []*Path{best}
+		} else {
+			return nil, ErrNoPathFound
+		}
+	}
+
+	route := &Route{
+		TokenIn:   tokenIn,
+		TokenOut:  tokenOut,
+		AmountIn:  new(big.Int).Set(amountIn),
+		AmountOut: new(big.Int),
+		Paths:     paths,
+	}
+	for _, p := range paths {
+		route.AmountOut.Add(route.AmountOut, p.AmountOut)
+		route.Gas += p.Gas
+	}
+	return route, nil
+}
+
+// bestPath prices every candidate on a fork of base and returns the best path together with the state it leaves.
+func (f *Finder) bestPath(ctx context.Context, base *state, candidates [][]Edge, amountIn *big.Int) (*Path, *state) {
+	var (
+		best      *Path
+		bestState *state
+	)
+	for _, edges := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+		s := base.fork()
+		p, err := simulatePath(ctx, s, edges, amountIn)
+		if err != nil {
+			continue
+		}
+		if best == nil || p.AmountOut.Cmp(best.AmountOut) > 0 ||
+			p.AmountOut.Cmp(best.AmountOut) == 0 && p.Gas < best.Gas {
+			best, bestState = p, s
+		}
+	}
+	return best, bestState
+}
+
+// simulatePath prices a path hop by hop on s, updating s along the way. The legs of the path through maker pools are
+// then reserved together, so that the path cannot spend a maker balance twice.
+func simulatePath(ctx context.Context, s *state, edges []Edge, amountIn *big.Int) (*Path, error) {
+	p := &Path{
+		Edges:      edges,
+		AmountIn:   amountIn,
+		HopAmounts: make([]*big.Int, 0, len(edges)+1),
+	}
+	amount := amountIn
+	p.HopAmounts = append(p.HopAmounts, amount)
+	for _, e := range edges {
+		res, err := s.swap(ctx, e, amount)
+		if err != nil {
+			return nil, err
+		}
+		amount = res.TokenAmountOut.Amount
+		p.HopAmounts = append(p.HopAmounts, amount)
+		p.Gas += res.Gas
+	}
+	if err := s.reserve(); err != nil {
+		return nil, err
+	}
+	p.AmountOut = amount
+	return p, nil
+}
@@ -0,0 +1,257 @@
+package pathfinder
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	uniswapv2 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v2"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/swaplimit"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+const (
+	tokenA = "0x000000000000000000000000000000000000000a"
+	tokenB = "0x000000000000000000000000000000000000000b"
+	tokenC = "0x000000000000000000000000000000000000000c"
+)
+
+func newV2Pool(t *testing.T, address, token0, token1, reserve0, reserve1 string) pool.IPoolSimulator {
+	t.Helper()
+	p, err := uniswapv2.NewPoolSimulator(entity.Pool{
+		Address:  address,
+		Exchange: "uniswap-v2",
+		Type:     uniswapv2.DexType,
+		Reserves: []string{reserve0, reserve1},
+		Tokens:   []*entity.PoolToken{{Address: token0, Swappable: true}, {Address: token1, Swappable: true}},
+		Extra:    `{"fee":3,"feePrecision":1000}`,
+	})
+	require.NoError(t, err)
+	return p
+}
+
+func testPools(t *testing.T) []pool.IPoolSimulator {
+	return []pool.IPoolSimulator{
+		newV2Pool(t, "0xab", tokenA, tokenB, "3000000000", "3000000000"),
+		newV2Pool(t, "0xac", tokenA, tokenC, "10000000000", "10000000000"),
+		newV2Pool(t, "0xcb", tokenC, tokenB, "10000000000", "10000000000"),
+	}
+}
+
+func TestGraph_candidatePaths(t *testing.T) {
+	t.Parallel()
+	g := NewGraph(testPools(t))
+
+	paths := g.candidatePaths(tokenA, tokenB, 3, 100)
+	require.Len(t, paths, 2)
+	assert.Equal(t, []Edge{{Pool: "0xab", TokenIn: tokenA, TokenOut: tokenB}}, paths[0])
+	assert.Equal(t, []Edge{
+		{Pool: "0xac", TokenIn: tokenA, TokenOut: tokenC},
+		{Pool: "0xcb", TokenIn: tokenC, TokenOut: tokenB},
+	}, paths[1])
+
+	assert.Len(t, g.candidatePaths(tokenA, tokenB, 1, 100), 1)
+	assert.Len(t, g.candidatePaths(tokenA, tokenB, 3, 1), 1)
+}
+
+func TestFinder_FindBestPath(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	f := NewFinder(testPools(t), Config{})
+
+	small, err := f.FindBestPath(ctx, tokenA, tokenB, big.NewInt(1000), nil)
+	require.NoError(t, err)
+	assert.Equal(t, entity.MinimalPath{Pools: []string{"0xab"}, Tokens: []string{tokenA, tokenB}}, small.MinimalPath())
+
+	large, err := f.FindBestPath(ctx, tokenA, tokenB, big.NewInt(500000000), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0xac", "0xcb"}, large.Pools())
+	assert.Equal(t, []string{tokenA, tokenC, tokenB}, large.Tokens())
+
+	_, err = f.FindBestPath(ctx, tokenB, "0xdead", big.NewInt(1000), nil)
+	assert.ErrorIs(t, err, ErrNoPathFound)
+	_, err = f.FindBestPath(ctx, tokenA, tokenB, big.NewInt(0), nil)
+	assert.ErrorIs(t, err, ErrInvalidAmountIn)
+}
+
+func TestFinder_FindRoute(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	pools := testPools(t)
+	f := NewFinder(pools, Config{SplitParts: 20})
+	amountIn := bignumber.NewBig("2000000000")
+
+	single, err := f.FindBestPath(ctx, tokenA, tokenB, amountIn, nil)
+	require.NoError(t, err)
+	route, err := f.FindRoute(ctx, tokenA, tokenB, amountIn, nil)
+	require.NoError(t, err)
+
+	assert.Len(t, route.Paths, 2)
+	assert.True(t, route.AmountOut.Cmp(single.AmountOut) > 0, "split %s <= single %s", route.AmountOut,
+		single.AmountOut)
+
+	sumIn := new(big.Int)
+	for _, p := range route.Paths {
+		sumIn.Add(sumIn, p.AmountIn)
+	}
+	assert.Equal(t, amountIn, sumIn)
+
+	// re-pricing the paths in order on fresh pools must match the route
+	s := newState(f.Graph(), nil)
+	total := new(big.Int)
+	for _, p := range route.Paths {
+		repriced, err := simulatePath(ctx, s, p.Edges, p.AmountIn)
+		require.NoError(t, err)
+		total.Add(total, repriced.AmountOut)
+	}
+	assert.Equal(t, route.AmountOut, total)
+
+	// the original pools are never mutated
+	res, err := pools[0].CalcAmountOut(pool.CalcAmountOutParams{
+		TokenAmountIn: pool.TokenAmount{Token: tokenA, Amount: big.NewInt(1000)},
+		TokenOut:      tokenB,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(996), res.TokenAmountOut.Amount)
+}
+
+func TestFinder_PoolReuse(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	ab := newV2Pool(t, "0xab", tokenA, tokenB, "1000000", "1000000")
+	f := NewFinder([]pool.IPoolSimulator{ab}, Config{SplitParts: 2})
+
+	route, err := f.FindRoute(ctx, tokenA, tokenB, big.NewInt(200000), nil)
+	require.NoError(t, err)
+	require.Len(t, route.Paths, 1)
+
+	// the second chunk must be priced against the pool updated by the first chunk
+	first, err := ab.CalcAmountOut(pool.CalcAmountOutParams{
+		TokenAmountIn: pool.TokenAmount{Token: tokenA, Amount: big.NewInt(100000)},
+		TokenOut:      tokenB,
+	})
+	require.NoError(t, err)
+	assert.True(t, route.AmountOut.Cmp(new(big.Int).Mul(first.TokenAmountOut.Amount, big.NewInt(2))) < 0,
+		fmt.Sprintf("route %s should be worse than 2x first chunk %s", route.AmountOut, first.TokenAmountOut.Amount))
+}
+
+// cappedPool rejects swaps above maxAmountIn, so chunks priced separately can succeed where their merged swap fails.
+type cappedPool struct {
+	pool.IPoolSimulator
+	maxAmountIn *big.Int
+	clonable    bool
+}
+
+func (p *cappedPool) CalcAmountOut(params pool.CalcAmountOutParams) (*pool.CalcAmountOutResult, error) {
+	if params.TokenAmountIn.Amount.Cmp(p.maxAmountIn) > 0 {
+		return nil, ErrPartialFill
+	}
+	return p.IPoolSimulator.CalcAmountOut(params)
+}
+
+func (p *cappedPool) CloneState() pool.IPoolSimulator {
+	if !p.clonable {
+		return nil
+	}
+	return &cappedPool{IPoolSimulator: p.IPoolSimulator.CloneState(), maxAmountIn: p.maxAmountIn, clonable: true}
+}
+
+func TestFinder_FindRoute_SkipFailedMerge(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	amountIn := big.NewInt(4000000)
+
+	for _, clonable := range []bool{true, false} {
+		t.Run(fmt.Sprintf("clonable=%v", clonable), func(t *testing.T) {
+			t.Parallel()
+			ab := &cappedPool{
+				IPoolSimulator: newV2Pool(t, "0xab", tokenA, tokenB, "1000000000000", "1000000000000"),
+				maxAmountIn:    big.NewInt(1500000),
+				clonable:       clonable,
+			}
+			pools := append(testPools(t)[1:], ab)
+			f := NewFinder(pools, Config{SplitParts: 4})
+
+			route, err := f.FindRoute(ctx, tokenA, tokenB, amountIn, nil)
+			require.NoError(t, err)
+
+			sumIn := new(big.Int)
+			for _, p := range route.Paths {
+				sumIn.Add(sumIn, p.AmountIn)
+				if p.Pools()[0] == "0xab" {
+					assert.True(t, p.AmountIn.Cmp(ab.maxAmountIn) <= 0, "path through 0xab with %s", p.AmountIn)
+				}
+			}
+			assert.Equal(t, amountIn, sumIn)
+
+			s := newState(f.Graph(), nil)
+			total := new(big.Int)
+			for _, p := range route.Paths {
+				repriced, err := simulatePath(ctx, s, p.Edges, p.AmountIn)
+				require.NoError(t, err)
+				total.Add(total, repriced.AmountOut)
+			}
+			assert.Equal(t, route.AmountOut, total)
+		})
+	}
+}
+
+const makerExchange = "pathfinder-test-maker"
+
+var _ = pool.RegisterUseSwapLimit(makerExchange)
+
+// makerPool is a pool of maker sharing makerExchange. It does not check its swap limit, leaving it to the finder.
+type makerPool struct {
+	pool.IPoolSimulator
+	maker string
+}
+
+func (p *makerPool) GetExchange() string { return makerExchange }
+
+func (p *makerPool) GetMaker() string { return p.maker }
+
+func (p *makerPool) CloneState() pool.IPoolSimulator {
+	return &makerPool{IPoolSimulator: p.IPoolSimulator.CloneState(), maker: p.maker}
+}
+
+func TestFinder_FindRoute_MakerInventory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	m1 := &makerPool{IPoolSimulator: newV2Pool(t, "0xm1", tokenA, tokenB, "1000000000000", "1000000000000"), maker: "m"}
+	m2 := &makerPool{IPoolSimulator: newV2Pool(t, "0xm2", tokenA, tokenB, "1000000000000", "1000000000000"), maker: "m"}
+	ab := newV2Pool(t, "0xab", tokenA, tokenB, "2000", "2000")
+	inventory := swaplimit.NewMakerInventory(makerExchange, map[string]map[string]*big.Int{"m": {tokenB: big.NewInt(150)}})
+	limits := map[string]pool.SwapLimit{makerExchange: inventory}
+	f := NewFinder([]pool.IPoolSimulator{m1, m2, ab}, Config{SplitParts: 2})
+
+	// each chunk is best swapped through a pool of m, but m can only fill one of them
+	route, err := f.FindRoute(ctx, tokenA, tokenB, big.NewInt(200), limits)
+	require.NoError(t, err)
+	makerOut := new(big.Int)
+	for _, p := range route.Paths {
+		if p.Pools()[0] != "0xab" {
+			makerOut.Add(makerOut, p.AmountOut)
+		}
+	}
+	assert.True(t, makerOut.Sign() > 0)
+	assert.True(t, makerOut.Cmp(big.NewInt(150)) <= 0, "maker m spent %s", makerOut)
+	assert.Equal(t, int64(150), inventory.GetLimit(swaplimit.MakerKey("m", tokenB)).Int64(), "limits are not modified")
+
+	// the legs of a path are reserved in order, each against what the previous ones left: the second leg spends the
+	// tokenA the first one gave m, and the last path cannot spend what the first one left of m's tokenB
+	s := newState(f.Graph(), limits)
+	_, err = simulatePath(ctx, s, []Edge{
+		{Pool: "0xm1", TokenIn: tokenA, TokenOut: tokenB},
+		{Pool: "0xm2", TokenIn: tokenB, TokenOut: tokenA},
+		{Pool: "0xm1", TokenIn: tokenA, TokenOut: tokenB},
+	}, big.NewInt(100))
+	require.NoError(t, err)
+	_, err = simulatePath(ctx, s, []Edge{{Pool: "0xm2", TokenIn: tokenA, TokenOut: tokenB}}, big.NewInt(100))
+	assert.ErrorIs(t, err, pool.ErrNotEnoughInventory)
+}
@@ -0,0 +1,121 @@
+package pathfinder
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+// Edge is a directed hop from one token to another through a single pool.
+type Edge struct {
+	Pool     string
+	TokenIn  string
+	TokenOut string
+}
+
+// Graph is a directed token graph built from pool simulators. Each (pool, tokenIn, tokenOut) triple that the pool
+// reports via CanSwapFrom or CanSwapTo becomes an Edge. Graph is immutable after construction and safe for
+// concurrent reads.
+type Graph struct {
+	pools map[string]pool.IPoolSimulator
+	edges map[string][]Edge // tokenIn -> outgoing edges, sorted by (tokenOut, pool) for determinism
+}
+
+// NewGraph builds a Graph from the given pools. Pools with duplicated addresses are ignored after the first one.
+func NewGraph(pools []pool.IPoolSimulator) *Graph {
+	g := &Graph{
+		pools: make(map[string]pool.IPoolSimulator, len(pools)),
+		edges: make(map[string][]Edge),
+	}
+	type key struct{ pool, in, out string }
+	seen := make(map[key]struct{})
+	addEdge := func(poolAddr, tokenIn, tokenOut string) {
+		if tokenIn == tokenOut {
+			return
+		}
+		k := key{poolAddr, tokenIn, tokenOut}
+		if _, ok := seen[k]; ok {
+			return
+		}
+		seen[k] = struct{}{}
+		g.edges[tokenIn] = append(g.edges[tokenIn], Edge{Pool: poolAddr, TokenIn: tokenIn, TokenOut: tokenOut})
+	}
+
+	for _, p := range pools {
+		if p == nil {
+			continue
+		}
+		addr := p.GetAddress()
+		if _, ok := g.pools[addr]; ok {
+			continue
+		}
+		g.pools[addr] = p
+		for _, token := range p.GetTokens() {
+			for _, tokenOut := range p.CanSwapFrom(token) {
+				addEdge(addr, token, tokenOut)
+			}
+			for _, tokenIn := range p.CanSwapTo(token) {
+				addEdge(addr, tokenIn, token)
+			}
+		}
+	}
+
+	for _, edges := range g.edges {
+		slices.SortFunc(edges, func(a, b Edge) int {
+			return cmp.Or(cmp.Compare(a.TokenOut, b.TokenOut), cmp.Compare(a.Pool, b.Pool))
+		})
+	}
+	return g
+}
+
+// Pool returns the pool simulator with the given address, or nil if it is not part of the graph.
+func (g *Graph) Pool(address string) pool.IPoolSimulator {
+	return g.pools[address]
+}
+
+// Edges returns the outgoing edges of a token. Do not modify the result.
+func (g *Graph) Edges(tokenIn string) []Edge {
+	return g.edges[tokenIn]
+}
+
+// Tokens returns the number of tokens having at least one outgoing edge.
+func (g *Graph) Tokens() int {
+	return len(g.edges)
+}
+
+// candidatePaths enumerates simple token paths (no repeated tokens) from tokenIn to tokenOut with at most maxHops
+// hops, stopping after maxPaths paths have been collected.
+func (g *Graph) candidatePaths(tokenIn, tokenOut string, maxHops, maxPaths int) [][]Edge {
+	var (
+		result  [][]Edge
+		path    = make([]Edge, 0, maxHops)
+		visited = map[string]struct{}{tokenIn: {}}
+	)
+
+	var dfs func(token string)
+	dfs = func(token string) {
+		for _, e := range g.edges[token] {
+			if len(result) >= maxPaths {
+				return
+			}
+			if e.TokenOut == tokenOut {
+				result = append(result, append(slices.Clone(path), e))
+				continue
+			}
+			if len(path)+1 >= maxHops {
+				continue
+			}
+			if _, ok := visited[e.TokenOut]; ok {
+				continue
+			}
+			visited[e.TokenOut] = struct{}{}
+			path = append(path, e)
+			dfs(e.TokenOut)
+			path = path[:len(path)-1]
+			delete(visited, e.TokenOut)
+		}
+	}
+	dfs(tokenIn)
+	return result
+}
@@ -0,0 +1,65 @@
+package pathfinder
+
+import (
+	"math/big"
+
+	"github.com/samber/lo"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+)
+
+// Path is a priced sequence of hops from tokenIn to tokenOut.
+type Path struct {
+	Edges     []Edge
+	AmountIn  *big.Int
+	AmountOut *big.Int
+	// HopAmounts[i] is the amount going into hop i; HopAmounts[len(Edges)] equals AmountOut.
+	HopAmounts []*big.Int
+	Gas        int64
+}
+
+// Pools returns the pool addresses the path swaps through.
+func (p *Path) Pools() []string {
+	return lo.Map(p.Edges, func(e Edge, _ int) string { return e.Pool })
+}
+
+// Tokens returns the tokens the path swaps through, including tokenIn and tokenOut.
+func (p *Path) Tokens() []string {
+	if len(p.Edges) == 0 {
+		return nil
+	}
+	tokens := make([]string, 0, len(p.Edges)+1)
+	tokens = append(tokens, p.Edges[0].TokenIn)
+	for _, e := range p.Edges {
+		tokens = append(tokens, e.TokenOut)
+	}
+	return tokens
+}
+
+// MinimalPath converts the path to an entity.MinimalPath.
+func (p *Path) MinimalPath() entity.MinimalPath {
+	return entity.MinimalPath{
+		Pools:  p.Pools(),
+		Tokens: p.Tokens(),
+	}
+}
+
+func (p *Path) key() string {
+	return p.MinimalPath().Encode()
+}
+
+// Route is a split route: amountIn is distributed over several paths that are priced sequentially on a shared state,
+// so that paths sharing a pool see each other's balance changes.
+type Route struct {
+	TokenIn   string
+	TokenOut  string
+	AmountIn  *big.Int
+	AmountOut *big.Int
+	Paths     []*Path
+	Gas       int64
+}
+
+// MinimalPaths converts the route's paths to entity.MinimalPath values.
+func (r *Route) MinimalPaths() []entity.MinimalPath {
+	return lo.Map(r.Paths, func(p *Path, _ int) entity.MinimalPath { return p.MinimalPath() })
+}
@@ -0,0 +1,167 @@
+package pathfinder
+
+import (
+	"context"
+	"maps"
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/swaplimit"
+)
+
+// state is a copy-on-write view over the graph's pools and the caller's swap limits. Pools and limits are only
+// cloned the first time a state writes to them, so evaluating many candidate paths stays cheap.
+type state struct {
+	graph       *Graph
+	pools       map[string]pool.IPoolSimulator
+	limits      map[string]pool.SwapLimit
+	ownedPools  map[string]struct{}
+	ownedLimits map[string]struct{}
+	// frozen holds pools whose CloneState is unimplemented. They can be swapped through once per state, after which
+	// their balance can no longer be tracked and they are excluded.
+	frozen map[string]struct{}
+	// legs are the swaps through maker pools of the path being priced, by exchange. They are reserved on the
+	// exchange's swaplimit.MakerInventory together once the path is priced, see reserve.
+	legs map[string][]swaplimit.Leg
+}
+
+func newState(graph *Graph, limits map[string]pool.SwapLimit) *state {
+	return &state{
+		graph:       graph,
+		pools:       make(map[string]pool.IPoolSimulator),
+		limits:      maps.Clone(limits),
+		ownedPools:  make(map[string]struct{}),
+		ownedLimits: make(map[string]struct{}),
+		frozen:      make(map[string]struct{}),
+		legs:        make(map[string][]swaplimit.Leg),
+	}
+}
+
+// fork returns a child state. Writes to the child never affect the parent.
+func (s *state) fork() *state {
+	return &state{
+		graph:       s.graph,
+		pools:       maps.Clone(s.pools),
+		limits:      maps.Clone(s.limits),
+		ownedPools:  make(map[string]struct{}),
+		ownedLimits: make(map[string]struct{}),
+		frozen:      maps.Clone(s.frozen),
+		legs:        make(map[string][]swaplimit.Leg),
+	}
+}
+
+func (s *state) pool(address string) pool.IPoolSimulator {
+	if p, ok := s.pools[address]; ok {
+		return p
+	}
+	return s.graph.Pool(address)
+}
+
+// swapLimit returns the swap limit for the pool's exchange if it uses one, or nil otherwise.
+func (s *state) swapLimit(p pool.IPoolSimulator) pool.SwapLimit {
+	if _, ok := pool.UseSwapLimit[p.GetExchange()]; !ok {
+		return nil
+	}
+	return s.limits[p.GetExchange()]
+}
+
+// mutablePool returns a pool owned by this state, cloning it if needed. It returns nil if the pool cannot be cloned.
+func (s *state) mutablePool(address string) pool.IPoolSimulator {
+	if _, ok := s.ownedPools[address]; ok {
+		return s.pools[address]
+	}
+	cloned := s.pool(address).CloneState()
+	if cloned == nil {
+		return nil
+	}
+	s.pools[address] = cloned
+	s.ownedPools[address] = struct{}{}
+	return cloned
+}
+
+// mutableSwapLimit returns a swap limit owned by this state, cloning it if needed.
+func (s *state) mutableSwapLimit(exchange string) pool.SwapLimit {
+	limit, ok := s.limits[exchange]
+	if !ok || limit == nil {
+		return nil
+	}
+	if _, ok := s.ownedLimits[exchange]; ok {
+		return limit
+	}
+	limit = limit.Clone()
+	s.limits[exchange] = limit
+	s.ownedLimits[exchange] = struct{}{}
+	return limit
+}
+
+// swap simulates a single hop and applies its result to this state.
+func (s *state) swap(ctx context.Context, e Edge, amountIn *big.Int) (*pool.CalcAmountOutResult, error) {
+	if _, ok := s.frozen[e.Pool]; ok {
+		return nil, ErrPoolStateNotClonable
+	}
+	p := s.pool(e.Pool)
+	if p == nil {
+		return nil, ErrPoolNotFound
+	}
+
+	tokenAmountIn := pool.TokenAmount{Token: e.TokenIn, Amount: amountIn}
+	res, err := pool.CalcAmountOut(ctx, p, tokenAmountIn, e.TokenOut, s.swapLimit(p))
+	if err != nil {
+		return nil, err
+	} else if res == nil || !res.IsValid() {
+		return nil, ErrInvalidSwapResult
+	} else if res.RemainingTokenAmountIn != nil && res.RemainingTokenAmountIn.Amount.Sign() > 0 {
+		return nil, ErrPartialFill
+	}
+
+	// the inventory of a maker pool is spent by reserve rather than by UpdateBalance, so that legs of the path
+	// spending the same maker balance are checked together
+	_, isMakerInventory := s.swapLimit(p).(*swaplimit.MakerInventory)
+	makerPool, isMakerPool := p.(swaplimit.IMakerPool)
+	if isMakerPool && isMakerInventory {
+		s.legs[p.GetExchange()] = append(s.legs[p.GetExchange()], swaplimit.Leg{
+			DecreaseKey:   swaplimit.MakerKey(makerPool.GetMaker(), e.TokenOut),
+			IncreaseKey:   swaplimit.MakerKey(makerPool.GetMaker(), e.TokenIn),
+			DecreaseDelta: res.TokenAmountOut.Amount,
+			IncreaseDelta: amountIn,
+		})
+	}
+
+	mutable := s.mutablePool(e.Pool)
+	if mutable == nil {
+		s.frozen[e.Pool] = struct{}{}
+		return res, nil
+	}
+
+	var limit pool.SwapLimit
+	if _, ok := pool.UseSwapLimit[p.GetExchange()]; ok && !(isMakerPool && isMakerInventory) {
+		limit = s.mutableSwapLimit(p.GetExchange())
+	}
+	updateBalanceParams := pool.UpdateBalanceParams{
+		TokenAmountIn:  tokenAmountIn,
+		TokenAmountOut: *res.TokenAmountOut,
+		SwapInfo:       res.SwapInfo,
+		SwapLimit:      limit,
+	}
+	if res.Fee != nil {
+		updateBalanceParams.Fee = *res.Fee
+	}
+	mutable.UpdateBalance(updateBalanceParams)
+	return res, nil
+}
+
+// reserve reserves the legs of the path priced since the last call on the inventories of their exchanges, all or none
+// per exchange. It fails if the legs spend more than a maker's balance, in which case the state must be discarded.
+func (s *state) reserve() error {
+	for exchange, legs := range s.legs {
+		inventory, ok := s.mutableSwapLimit(exchange).(*swaplimit.MakerInventory)
+		if !ok {
+			continue
+		}
+		if _, err := inventory.Reserve(legs...); err != nil {
+			return err
+		}
+	}
+	clear(s.legs)
+	return nil
+}
@@ -45,8 +45,9 @@ func TestPoolFactory(t *testing.T) {
 
 func TestCanCalcAmountIn(t *testing.T) {
 	t.Parallel()
-	dexes := []string{"algebra-integral", "algebra-v1", "balancer-v2-composable-stable", "balancer-v2-stable",
-		"balancer-v2-weighted", "balancer-v3-eclp", "balancer-v3-stable", "balancer-v3-weighted", "bancor-v3",
+	dexes := []string{"algebra-integral", "algebra-v1", "balancer-v2-composable-stable", "balancer-v2-linear",
+		"balancer-v2-stable", "balancer-v2-weighted", "balancer-v3-eclp", "balancer-v3-stable", "balancer-v3-weighted",
+		"bancor-v3",
 		"curve-compound", "curve-lending", "curve-llamma", "curve-stable-meta-ng", "curve-stable-ng",
 		"curve-stable-plain", "curve-tricrypto-ng", "curve-twocrypto-ng", "deltaswap-v1", "dodo-classical", "ekubo",
 		"ekubo-v3", "euler-swap", "fluid-dex-t1", "iziswap", "limit-order", "liquiditybook-v21", "baseline",
@@ -81,7 +82,8 @@ func TestPoolListerFactory(t *testing.T) {
 		"maverick-v1", "iziswap", "wombat", "kokonut-crypto", "woofi-v2", "woofi-v21", "equalizer",
 		"mantisswap", "gmx-glp", "swapbased-perp", "usdfi", "vooi", "pol-matic", "liquiditybook-v21",
 		"liquiditybook-v20", "smardex", "integral", "fxdx", "uniswap-v1", "uniswap-v2", "quickperps", "balancer-v1",
-		"balancer-v2-weighted", "balancer-v2-stable", "balancer-v2-composable-stable", "balancer-v3-stable",
+		"balancer-v2-weighted", "balancer-v2-stable", "balancer-v2-composable-stable", "balancer-v2-linear",
+		"balancer-v2-fx", "balancer-v3-stable",
 		"balancer-v3-weighted", "velocore-v2-cpmm", "velocore-v2-wombat-stable", "fulcrom", "gyroscope-2clp",
 		"gyroscope-3clp", "gyroscope-eclp", "zkera-finance", "bancor-v3", "etherfi-eeth", "etherfi-weeth", "kelp-rseth",
 		"rocketpool-reth", "ethena-susde", "maker-savingsdai", "bancor-v21", "nomiswap-stable", "renzo-ezeth",
@@ -112,7 +114,8 @@ func TestPoolTrackerFactory(t *testing.T) {
 		"pancake-v3", "maverick-v1", "iziswap", "kokonut-crypto", "wombat", "woofi-v2", "woofi-v21",
 		"equalizer", "mantisswap", "gmx-glp", "swapbased-perp", "usdfi", "vooi", "pol-matic", "liquiditybook-v21",
 		"liquiditybook-v20", "smardex", "integral", "fxdx", "uniswap-v1", "uniswap-v2", "quickperps", "balancer-v1",
-		"fulcrom", "balancer-v2-weighted", "balancer-v2-stable", "balancer-v2-composable-stable", "balancer-v3-stable",
+		"fulcrom", "balancer-v2-weighted", "balancer-v2-stable", "balancer-v2-composable-stable", "balancer-v2-linear",
+		"balancer-v2-fx", "balancer-v3-stable",
 		"balancer-v3-weighted", "velocore-v2-cpmm", "velocore-v2-wombat-stable", "gyroscope-2clp", "gyroscope-3clp",
 		"gyroscope-eclp", "zkera-finance", "bancor-v3", "etherfi-eeth", "etherfi-weeth", "kelp-rseth",
 		"rocketpool-reth", "ethena-susde", "maker-savingsdai", "bancor-v21", "nomiswap-stable", "renzo-ezeth",
@@ -16,6 +16,8 @@ import (
 	metricpropamm "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/axima/metric-propamm"
 	balancerv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v1"
 	balancerv2composablestable "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/composable-stable"
+	balancerv2fx "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/fx"
+	balancerv2linear "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/linear"
 	balancerv2stable "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/stable"
 	balancerv2weighted "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/weighted"
 	_ "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/balancer/v2/weighted/lazy"
@@ -78,6 +80,7 @@ import (
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/etherfi/weeth"
 	eulerswapv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/euler-swap/v1"
 	eulerswapv2 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/euler-swap/v2"
+	evmquoter "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/evm-quoter"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/feltir"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/flap"
 	fluidDexLite "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/fluid/dex-lite"
@@ -92,6 +95,7 @@ import (
 	genericarm "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/generic-arm"
 	genericsimplerate "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/generic-simple-rate"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/ghost"
+	gmxv2 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/gmx-v2"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/gohm"
 	gsm4626 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/gsm-4626"
 	gyro2clp "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/gyroscope/2clp"
@@ -142,6 +146,7 @@ import (
 	pancakestable "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/pancake/stable"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/pandafun"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/parityprop"
+	pendlev2 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/pendle/v2"
 	ponsv2 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/pons-v2"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/primeeth"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/printr"
@@ -168,6 +173,7 @@ import (
 	umbraedlmm "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/umbrae/dlmm"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/unipool"
 	uniswaplo "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/lo"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/uniswapx"
 	uniswapv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v1"
 	uniswapv2 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v2"
 	uniswapv3 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v3"
@@ -309,6 +315,8 @@ type Types struct {
 	QuickPerps                 string
 	BalancerV1                 string
 	BalancerV2ComposableStable string
+	BalancerV2FX               string
+	BalancerV2Linear           string
 	BalancerV2Stable           string
 	BalancerV2Weighted         string
 	BalancerV3ECLP             string
@@ -411,8 +419,10 @@ type Types struct {
 	Pmm2                       string
 	Pmm3                       string
 	UniswapLO                  string
+	UniswapX                   string
 	EulerSwap                  string
 	EulerSwapV2                string
+	EVMQuoter                  string
 	AaveV3                     string
 	CompoundV2                 string
 	CompoundV3                 string
@@ -470,6 +480,8 @@ type Types struct {
 	MetronomeSwap              string
 	FluxProp                   string
 	ParityProp                 string
+	GMXV2                      string
+	PendleV2                   string
 }
 
 var (
@@ -551,6 +563,8 @@ var (
 		QuickPerps:                 quickperps.DexTypeQuickperps,
 		BalancerV1:                 balancerv1.DexType,
 		BalancerV2ComposableStable: balancerv2composablestable.DexType,
+		BalancerV2FX:               balancerv2fx.DexType,
+		BalancerV2Linear:           balancerv2linear.DexType,
 		BalancerV2Stable:           balancerv2stable.DexType,
 		BalancerV2Weighted:         balancerv2weighted.DexType,
 		BalancerV3ECLP:             balancerv3eclp.DexType,
@@ -654,8 +668,10 @@ var (
 		HashflowV3:                 valueobject.ExchangeHashflowV3,
 		Dexalot:                    valueobject.ExchangeDexalot,
 		UniswapLO:                  uniswaplo.DexType,
+		UniswapX:                   uniswapx.DexType,
 		EulerSwap:                  eulerswapv1.DexType,
 		EulerSwapV2:                eulerswapv2.DexType,
+		EVMQuoter:                  evmquoter.DexType,
 		AaveV3:                     aavev3.DexType,
 		CompoundV2:                 compoundv2.DexType,
 		CompoundV3:                 compoundv3.DexType,
@@ -713,5 +729,7 @@ var (
 		MetronomeSwap:              metronomeswap.DexType,
 		FluxProp:                   valueobject.ExchangeFluxProp,
 		ParityProp:                 parityprop.DexType,
+		GMXV2:                      gmxv2.DexType,
+		PendleV2:                   pendlev2.DexType,
 	}
 )
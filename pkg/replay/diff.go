@@ -0,0 +1,61 @@
+package replay
+
+import (
+	"bytes"
+
+	"github.com/goccy/go-json"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+)
+
+// poolView is the part of entity.Pool that trackers are responsible for. Timestamp, ReserveUsd and AmplifiedTvl are
+// left out since they depend on wall-clock time or prices. Extra and StaticExtra are compared as parsed JSON so that
+// field order and formatting do not matter.
+type poolView struct {
+	Address     string
+	Exchange    string
+	Type        string
+	SwapFee     float64
+	Reserves    []string
+	Tokens      []*entity.PoolToken
+	Extra       any
+	StaticExtra any
+	TotalSupply string
+	BlockNumber uint64
+}
+
+func newPoolView(p entity.Pool) poolView {
+	return poolView{
+		Address:     p.Address,
+		Exchange:    p.Exchange,
+		Type:        p.Type,
+		SwapFee:     p.SwapFee,
+		Reserves:    p.Reserves,
+		Tokens:      p.Tokens,
+		Extra:       parseJSON(p.Extra),
+		StaticExtra: parseJSON(p.StaticExtra),
+		TotalSupply: p.TotalSupply,
+		BlockNumber: p.BlockNumber,
+	}
+}
+
+// parseJSON parses s keeping numbers as json.Number to avoid precision loss. Invalid JSON is returned as is.
+func parseJSON(s string) any {
+	if s == "" {
+		return nil
+	}
+	var v any
+	dec := json.NewDecoder(bytes.NewReader([]byte(s)))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return s
+	}
+	return v
+}
+
+// DiffPool returns a human-readable diff between want and got, or an empty string if they are equivalent.
+func DiffPool(want, got entity.Pool) string {
+	return cmp.Diff(newPoolView(want), newPoolView(got), cmpopts.EquateEmpty())
+}
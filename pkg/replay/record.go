@@ -0,0 +1,153 @@
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+)
+
+// RecordKind is the kind of a capture record.
+type RecordKind string
+
+const (
+	// KindPool is the initial state of a pool, before any replayed block.
+	KindPool RecordKind = "pool"
+	// KindGolden is the expected state of a pool after replaying block Pool.BlockNumber.
+	KindGolden RecordKind = "golden"
+	// KindLog is an event log.
+	KindLog RecordKind = "log"
+	// KindHeader is a block header.
+	KindHeader RecordKind = "header"
+	// KindRPC is a recorded JSON-RPC call and its response.
+	KindRPC RecordKind = "rpc"
+)
+
+// Record is a single line of a JSONL capture.
+type Record struct {
+	Kind   RecordKind          `json:"kind"`
+	Pool   *entity.Pool        `json:"pool,omitempty"`
+	Log    *types.Log          `json:"log,omitempty"`
+	Header *entity.BlockHeader `json:"header,omitempty"`
+	RPC    *RPCRecord          `json:"rpc,omitempty"`
+}
+
+// RPCRecord is a recorded JSON-RPC call. Block is the block the call was made against; when zero it is derived from
+// the block parameter of Params, and calls against a non-numeric block tag (e.g. "latest") match any block.
+type RPCRecord struct {
+	Block  uint64          `json:"block,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError is a JSON-RPC error object.
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Capture is a parsed JSONL capture.
+type Capture struct {
+	Pools   []entity.Pool
+	Goldens []entity.Pool
+	Logs    []types.Log // sorted by (BlockNumber, Index)
+	Headers map[uint64]entity.BlockHeader
+	RPCs    []RPCRecord
+}
+
+// ReadCapture parses a JSONL capture. Empty lines are skipped.
+func ReadCapture(r io.Reader) (*Capture, error) {
+	c := &Capture{Headers: make(map[uint64]entity.BlockHeader)}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 1<<20), 1<<28)
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return nil, errors.WithMessagef(err, "line %d", line)
+		}
+		if err := c.add(rec); err != nil {
+			return nil, errors.WithMessagef(err, "line %d", line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(c.Logs, func(a, b types.Log) int {
+		return cmp.Or(cmp.Compare(a.BlockNumber, b.BlockNumber), cmp.Compare(a.Index, b.Index))
+	})
+	return c, nil
+}
+
+func (c *Capture) add(rec Record) error {
+	switch rec.Kind {
+	case KindPool, KindGolden:
+		if rec.Pool == nil {
+			return errors.Errorf("%s record without pool", rec.Kind)
+		}
+		p := *rec.Pool
+		p.Address = strings.ToLower(p.Address)
+		if rec.Kind == KindPool {
+			c.Pools = append(c.Pools, p)
+		} else {
+			c.Goldens = append(c.Goldens, p)
+		}
+	case KindLog:
+		if rec.Log == nil {
+			return errors.New("log record without log")
+		}
+		c.Logs = append(c.Logs, *rec.Log)
+	case KindHeader:
+		if rec.Header == nil || rec.Header.Number == nil {
+			return errors.New("header record without block number")
+		}
+		c.Headers[rec.Header.Number.Uint64()] = *rec.Header
+	case KindRPC:
+		if rec.RPC == nil || rec.RPC.Method == "" {
+			return errors.New("rpc record without method")
+		}
+		c.RPCs = append(c.RPCs, *rec.RPC)
+	default:
+		return errors.Errorf("unknown record kind %q", rec.Kind)
+	}
+	return nil
+}
+
+// Writer writes capture records as JSONL. It is safe for concurrent use.
+type Writer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriter creates a Writer.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write writes a record on its own line.
+func (w *Writer) Write(rec Record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err = w.w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,94 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/goccy/go-json"
+)
+
+// RecordingTransport is an http.RoundTripper that forwards JSON-RPC calls to a real node and writes each call and its
+// response as a KindRPC record, producing captures the Server can replay.
+type RecordingTransport struct {
+	Base   http.RoundTripper
+	Writer *Writer
+}
+
+// NewRecordingClient dials url through a RecordingTransport and returns an ethrpc.Client whose calls are recorded to w.
+func NewRecordingClient(ctx context.Context, url string, w *Writer) (*ethrpc.Client, error) {
+	rpcClient, err := rpc.DialOptions(ctx, url, rpc.WithHTTPClient(&http.Client{
+		Transport: &RecordingTransport{Base: http.DefaultTransport, Writer: w},
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return ethrpc.NewWithClient(ethclient.NewClient(rpcClient)), nil
+}
+
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	t.record(reqBody, respBody)
+	return resp, nil
+}
+
+// record pairs requests and responses by id. Malformed payloads are silently skipped.
+func (t *RecordingTransport) record(reqBody, respBody []byte) {
+	var (
+		reqs  []rpcRequest
+		resps []rpcResponse
+	)
+	if trimmed := bytes.TrimSpace(reqBody); len(trimmed) > 0 && trimmed[0] == '[' {
+		_ = json.Unmarshal(trimmed, &reqs)
+		_ = json.Unmarshal(respBody, &resps)
+	} else {
+		var (
+			req  rpcRequest
+			resp rpcResponse
+		)
+		if json.Unmarshal(trimmed, &req) != nil || json.Unmarshal(respBody, &resp) != nil {
+			return
+		}
+		reqs, resps = []rpcRequest{req}, []rpcResponse{resp}
+	}
+
+	respByID := make(map[string]rpcResponse, len(resps))
+	for _, resp := range resps {
+		respByID[string(resp.ID)] = resp
+	}
+	for _, req := range reqs {
+		resp, ok := respByID[string(req.ID)]
+		if !ok {
+			continue
+		}
+		_ = t.Writer.Write(Record{Kind: KindRPC, RPC: &RPCRecord{
+			Method: req.Method,
+			Params: req.Params,
+			Result: resp.Result,
+			Error:  resp.Error,
+		}})
+	}
+}
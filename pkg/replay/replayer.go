@@ -0,0 +1,212 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+// Options configures a Replayer.
+type Options struct {
+	// ChainID is returned by eth_chainId when the capture has no recording for it.
+	ChainID uint64
+	// RefreshOnGolden also calls the tracker without logs for pools that have a golden state at a block but no logs in
+	// it, exercising the tracker's RPC refresh path against the recorded responses.
+	RefreshOnGolden bool
+	// Decoder routes logs to pools for trackers that do not implement pool.IPoolDecoder themselves, e.g. the pool
+	// factory of a source whose pools all emit their logs from a singleton contract.
+	Decoder pool.IPoolDecoder
+}
+
+// Mismatch is a difference between the replayed and the golden state of a pool after a block.
+type Mismatch struct {
+	Block   uint64
+	Address string
+	Diff    string
+}
+
+// StepError is an error returned by the tracker while replaying a block.
+type StepError struct {
+	Block   uint64
+	Address string
+	Err     error
+}
+
+// Report summarizes a replay.
+type Report struct {
+	Blocks     int
+	Updates    int
+	Compared   int
+	Mismatches []Mismatch
+	Errors     []StepError
+	// RPCMisses lists calls the tracker made that had no recorded response.
+	RPCMisses []string
+}
+
+// OK returns true if every golden state matched and no errors occurred.
+func (r *Report) OK() bool {
+	return len(r.Mismatches) == 0 && len(r.Errors) == 0 && len(r.RPCMisses) == 0
+}
+
+func (r *Report) String() string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "replayed %d blocks, %d tracker updates, %d golden states compared\n", r.Blocks,
+		r.Updates, r.Compared)
+	for _, m := range r.Mismatches {
+		_, _ = fmt.Fprintf(&sb, "mismatch at block %d for pool %s (-want +got):\n%s\n", m.Block, m.Address, m.Diff)
+	}
+	for _, e := range r.Errors {
+		_, _ = fmt.Fprintf(&sb, "error at block %d for pool %s: %v\n", e.Block, e.Address, e.Err)
+	}
+	for _, miss := range r.RPCMisses {
+		_, _ = fmt.Fprintf(&sb, "unrecorded rpc call: %s\n", miss)
+	}
+	return sb.String()
+}
+
+// Replayer replays a capture through a pool tracker block by block. Trackers should be built with Client so that
+// their RPC calls are served from the capture.
+type Replayer struct {
+	capture *Capture
+	server  *Server
+	opts    Options
+}
+
+// NewReplayer starts a Replayer for the capture. Close must be called once done.
+func NewReplayer(capture *Capture, opts Options) *Replayer {
+	return &Replayer{
+		capture: capture,
+		server:  NewServer(capture.RPCs, opts.ChainID),
+		opts:    opts,
+	}
+}
+
+// Client returns an ethrpc.Client served from the capture's recorded RPC responses.
+func (r *Replayer) Client() *ethrpc.Client {
+	return r.server.Client()
+}
+
+// Server returns the underlying RPC server.
+func (r *Replayer) Server() *Server {
+	return r.server
+}
+
+// Close shuts the RPC server down.
+func (r *Replayer) Close() {
+	r.server.Close()
+}
+
+// Run replays every block of the capture through tracker, starting from the capture's initial pool states. For each
+// block, the logs are routed to pools (via pool.IPoolDecoder when the tracker implements it, through Options.Decoder
+// when set, by log address otherwise) and passed to GetNewPoolState along with the block header. After the block,
+// every golden state recorded for it is diffed against the replayed state. It returns the final pool states keyed by
+// address.
+func (r *Replayer) Run(ctx context.Context, tracker pool.IPoolTracker) (*Report, map[string]entity.Pool, error) {
+	states := make(map[string]entity.Pool, len(r.capture.Pools))
+	for _, p := range r.capture.Pools {
+		states[p.Address] = p
+	}
+
+	logsByBlock := make(map[uint64][]types.Log)
+	for _, log := range r.capture.Logs {
+		logsByBlock[log.BlockNumber] = append(logsByBlock[log.BlockNumber], log)
+	}
+	goldensByBlock := make(map[uint64][]entity.Pool)
+	for _, g := range r.capture.Goldens {
+		goldensByBlock[g.BlockNumber] = append(goldensByBlock[g.BlockNumber], g)
+	}
+	blocks := slices.Sorted(maps.Keys(logsByBlock))
+	for block := range goldensByBlock {
+		if _, ok := logsByBlock[block]; !ok {
+			blocks = append(blocks, block)
+		}
+	}
+	slices.Sort(blocks)
+
+	decoder, ok := tracker.(pool.IPoolDecoder)
+	if !ok {
+		decoder = r.opts.Decoder
+	}
+	report := &Report{}
+	for _, block := range blocks {
+		if err := ctx.Err(); err != nil {
+			return report, states, err
+		}
+		r.server.SetBlock(block)
+		report.Blocks++
+
+		addressLogs, err := r.route(ctx, decoder, logsByBlock[block])
+		if err != nil {
+			report.Errors = append(report.Errors, StepError{Block: block, Err: err})
+			continue
+		}
+		if r.opts.RefreshOnGolden {
+			for _, g := range goldensByBlock[block] {
+				if _, ok := addressLogs[g.Address]; !ok {
+					addressLogs[g.Address] = nil
+				}
+			}
+		}
+
+		headers := make(map[uint64]entity.BlockHeader, 1)
+		if header, ok := r.capture.Headers[block]; ok {
+			headers[block] = header
+		}
+		for _, address := range slices.Sorted(maps.Keys(addressLogs)) {
+			p, ok := states[address]
+			if !ok {
+				continue
+			}
+			newPool, err := tracker.GetNewPoolState(ctx, p, pool.GetNewPoolStateParams{
+				Logs:         addressLogs[address],
+				BlockHeaders: headers,
+			})
+			if err != nil {
+				report.Errors = append(report.Errors, StepError{Block: block, Address: address, Err: err})
+				continue
+			}
+			states[address] = newPool
+			report.Updates++
+		}
+
+		for _, golden := range goldensByBlock[block] {
+			report.Compared++
+			if diff := DiffPool(golden, states[golden.Address]); diff != "" {
+				report.Mismatches = append(report.Mismatches, Mismatch{Block: block, Address: golden.Address,
+					Diff: diff})
+			}
+		}
+	}
+	report.RPCMisses = r.server.Misses()
+	return report, states, nil
+}
+
+func (r *Replayer) route(ctx context.Context, decoder pool.IPoolDecoder, logs []types.Log) (map[string][]types.Log,
+	error) {
+	if decoder != nil {
+		addressLogs, err := decoder.Decode(ctx, logs)
+		if err != nil {
+			return nil, err
+		}
+		routed := make(map[string][]types.Log, len(addressLogs))
+		for address, logs := range addressLogs {
+			routed[strings.ToLower(address)] = logs
+		}
+		return routed, nil
+	}
+
+	routed := make(map[string][]types.Log)
+	for _, log := range logs {
+		address := strings.ToLower(log.Address.Hex())
+		routed[address] = append(routed[address], log)
+	}
+	return routed, nil
+}
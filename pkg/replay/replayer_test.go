@@ -0,0 +1,215 @@
+package replay
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/carbon"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/ekubo"
+	uniswapv2 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v2"
+	uniswapv3 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v3"
+)
+
+func readTestCapture(t *testing.T, name string) *Capture {
+	t.Helper()
+	f, err := os.Open("testdata/" + name)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	capture, err := ReadCapture(f)
+	require.NoError(t, err)
+	return capture
+}
+
+func TestReplayer_Run(t *testing.T) {
+	t.Parallel()
+	capture := readTestCapture(t, "uniswap-v2.jsonl")
+	require.Len(t, capture.Logs, 3)
+	assert.Equal(t, uint(1), capture.Logs[0].Index, "logs must be sorted by (block, index)")
+
+	replayer := NewReplayer(capture, Options{})
+	defer replayer.Close()
+	tracker, err := uniswapv2.NewPoolTracker(&uniswapv2.Config{Fee: 3, FeePrecision: 1000}, replayer.Client())
+	require.NoError(t, err)
+
+	report, states, err := replayer.Run(context.Background(), tracker)
+	require.NoError(t, err)
+	assert.True(t, report.OK(), report.String())
+	assert.Equal(t, 2, report.Blocks)
+	assert.Equal(t, 2, report.Compared)
+
+	final := states["0x9eb0bc7a207f77811ee365729d00152622a745b7"]
+	assert.Equal(t, []string{"1400", "1430"}, []string(final.Reserves))
+	assert.Equal(t, int64(1700000012), final.Timestamp)
+}
+
+func TestReplayer_Run_Mismatch(t *testing.T) {
+	t.Parallel()
+	capture := readTestCapture(t, "uniswap-v2.jsonl")
+	capture.Goldens[0].Reserves = []string{"1500", "1333"}
+	capture.Goldens[1].Extra = `{"feePrecision":1000,"fee":30}`
+
+	replayer := NewReplayer(capture, Options{})
+	defer replayer.Close()
+	tracker, err := uniswapv2.NewPoolTracker(&uniswapv2.Config{Fee: 3, FeePrecision: 1000}, replayer.Client())
+	require.NoError(t, err)
+
+	report, _, err := replayer.Run(context.Background(), tracker)
+	require.NoError(t, err)
+	require.Len(t, report.Mismatches, 2)
+	assert.Equal(t, uint64(100), report.Mismatches[0].Block)
+	assert.Contains(t, report.Mismatches[0].Diff, "1333")
+	assert.Contains(t, report.Mismatches[1].Diff, "fee")
+	assert.False(t, report.OK())
+}
+
+func TestReplayer_Run_UniswapV3(t *testing.T) {
+	t.Parallel()
+	replayer := NewReplayer(readTestCapture(t, "uniswap-v3.jsonl"), Options{})
+	defer replayer.Close()
+	tracker, err := uniswapv3.NewTracker(&uniswapv3.Config{DexID: "uniswap-v3", ChainID: 1}, replayer.Client(), nil)
+	require.NoError(t, err)
+
+	report, states, err := replayer.Run(context.Background(), tracker)
+	require.NoError(t, err)
+	assert.True(t, report.OK(), report.String())
+	assert.Equal(t, 2, report.Blocks)
+	assert.Equal(t, 2, report.Compared)
+
+	// a mint of [-120,120] at block 100, then a swap to tick -30 and a partial burn of [-600,600] at block 101
+	var extra uniswapv3.Extra
+	require.NoError(t, json.Unmarshal([]byte(states["0x8ad599c3a0ff1de082011efddc58f1908eb6e6d8"].Extra), &extra))
+	assert.Equal(t, "1100000", extra.Liquidity.String())
+	assert.Equal(t, "-30", extra.Tick.String())
+	require.Len(t, extra.Ticks, 4)
+	assert.Equal(t, []int{-600, -120, 120, 600},
+		[]int{extra.Ticks[0].Index, extra.Ticks[1].Index, extra.Ticks[2].Index, extra.Ticks[3].Index})
+	assert.Equal(t, "600000", extra.Ticks[0].LiquidityGross.String())
+	assert.Equal(t, "-500000", extra.Ticks[2].LiquidityNet.String())
+}
+
+func TestReplayer_Run_Ekubo(t *testing.T) {
+	t.Parallel()
+	cfg := &ekubo.Config{
+		DexId:   "ekubo",
+		ChainId: 1,
+		Core:    common.HexToAddress("0xe0e0e08A6A4b9Dc7bD67BCB7aadE5cF48157d444"),
+		Oracle:  common.HexToAddress("0x51d02a5948496a67827242eabc5725531342527c"),
+		Twamm:   common.HexToAddress("0xd4279c050da1f5c5b2830558c7a08e57e12b54ec"),
+	}
+	// every ekubo pool emits its logs from the core contract, they are routed by pool id
+	replayer := NewReplayer(readTestCapture(t, "ekubo.jsonl"), Options{Decoder: ekubo.NewPoolFactory(cfg)})
+	defer replayer.Close()
+
+	report, states, err := replayer.Run(context.Background(), ekubo.NewPoolTracker(cfg, replayer.Client()))
+	require.NoError(t, err)
+	assert.True(t, report.OK(), report.String())
+	assert.Equal(t, 2, report.Blocks)
+	assert.Equal(t, 2, report.Compared)
+
+	// the swap of another pool at block 101 is ignored, the position update adds liquidity around the active tick
+	final := states["0x949b7f76e6712fd5d03935d263361443a425004048ea54e31e4e32c36cd7da83"]
+	var extra struct {
+		Liquidity   string `json:"liquidity"`
+		SortedTicks []struct {
+			Number int32 `json:"number"`
+		} `json:"sortedTicks"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(final.Extra), &extra))
+	assert.Equal(t, "65497697411278", extra.Liquidity)
+	require.Len(t, extra.SortedTicks, 6)
+	assert.Equal(t, int32(-20075592), extra.SortedTicks[2].Number)
+	assert.Equal(t, int32(-20063628), extra.SortedTicks[3].Number)
+	assert.Equal(t, []string{"260417007575506636", "499980726"}, []string(final.Reserves))
+}
+
+func TestReplayer_Run_Carbon(t *testing.T) {
+	t.Parallel()
+	capture := readTestCapture(t, "carbon.jsonl")
+	// the tracker rescans every strategy once a minute has passed since the last full scan, stamping the scan with the
+	// wall clock; the capture exercises the incremental path, so its scan time is moved to now
+	now := `"u":` + strconv.FormatInt(time.Now().Unix(), 10)
+	for _, pools := range [][]entity.Pool{capture.Pools, capture.Goldens} {
+		for i := range pools {
+			pools[i].Extra = strings.ReplaceAll(pools[i].Extra, `"u":1700000000`, now)
+		}
+	}
+
+	// carbon ignores logs and reads the controller at every block
+	replayer := NewReplayer(capture, Options{RefreshOnGolden: true})
+	defer replayer.Close()
+	tracker := carbon.NewPoolTracker(&carbon.Config{
+		DexId:      "carbon",
+		ChainId:    1,
+		Controller: common.HexToAddress("0xC537e898CD774e2dCBa3B14Ea6f34C93d5eA45e1"),
+	}, replayer.Client())
+
+	report, states, err := replayer.Run(context.Background(), tracker)
+	require.NoError(t, err)
+	assert.True(t, report.OK(), report.String())
+	assert.Equal(t, 2, report.Compared)
+	assert.Empty(t, replayer.Server().Misses())
+
+	// a third strategy is created at block 101
+	final := states["0x2a687b7e028a51bd952ec5a05a05cebdc91ddbffa75f15beb038167cc5a66eaa"]
+	var extra carbon.Extra
+	require.NoError(t, json.Unmarshal([]byte(final.Extra), &extra))
+	assert.Len(t, extra.Strategies, 3)
+	assert.Equal(t, []string{"1875543", "244011820"}, []string(final.Reserves))
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+	to := common.HexToAddress("0x9eb0bc7a207f77811ee365729d00152622a745b7")
+	callParams := func(block string) json.RawMessage {
+		return json.RawMessage(`[{"from":"0x0000000000000000000000000000000000000000","input":"0x0902f1ac",` +
+			`"to":"0x` + strings.ToUpper(to.Hex()[2:]) + `"},"` + block + `"]`)
+	}
+	server := NewServer([]RPCRecord{
+		{Method: "eth_call", Params: callParams("0x64"), Result: json.RawMessage(`"0x01"`)},
+		{Method: "eth_call", Params: callParams("0x66"), Result: json.RawMessage(`"0x02"`)},
+	}, 56)
+	defer server.Close()
+
+	client, err := ethclient.Dial(server.URL())
+	require.NoError(t, err)
+	ctx := context.Background()
+	msg := ethereum.CallMsg{To: &to, Data: common.FromHex("0x0902f1ac")}
+
+	chainID, err := client.ChainID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(56), chainID.Int64())
+
+	server.SetBlock(101)
+	blockNumber, err := client.BlockNumber(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(101), blockNumber)
+
+	// exact match, then fallback to the latest recording at or before the current block
+	res, err := client.CallContract(ctx, msg, big.NewInt(100))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1}, res)
+	res, err = client.CallContract(ctx, msg, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1}, res)
+	server.SetBlock(102)
+	res, err = client.CallContract(ctx, msg, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{2}, res)
+
+	_, err = client.BalanceAt(ctx, to, nil)
+	assert.ErrorContains(t, err, "no recorded response")
+	assert.Len(t, server.Misses(), 1)
+}
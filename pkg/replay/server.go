@@ -0,0 +1,242 @@
+package replay
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/goccy/go-json"
+	"github.com/samber/lo"
+)
+
+const errCodeNoRecord = -32000
+
+// blockParamIndex is the position of the block parameter of JSON-RPC methods that take one.
+var blockParamIndex = map[string]int{
+	"eth_call":                1,
+	"eth_getBalance":          1,
+	"eth_getCode":             1,
+	"eth_getTransactionCount": 1,
+	"eth_getStorageAt":        2,
+	"eth_getProof":            2,
+	"eth_estimateGas":         1,
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// Server is a local JSON-RPC endpoint serving recorded responses, so that an ethrpc.Client can be pointed at it in
+// place of a real node. Calls are first matched on (method, params); calls with a block parameter then fall back to
+// the latest recording of the same call made at or before the current replay block.
+type Server struct {
+	server  *httptest.Server
+	chainID uint64
+	block   atomic.Uint64
+
+	exact map[string][]RPCRecord
+	loose map[string][]RPCRecord
+
+	mu     sync.Mutex
+	misses []string
+}
+
+// NewServer starts a Server serving the given records. Close must be called once done.
+func NewServer(records []RPCRecord, chainID uint64) *Server {
+	s := &Server{
+		chainID: chainID,
+		exact:   make(map[string][]RPCRecord),
+		loose:   make(map[string][]RPCRecord),
+	}
+	for _, rec := range records {
+		params := splitParams(rec.Params)
+		if rec.Block == 0 {
+			rec.Block = blockOf(rec.Method, params)
+		}
+		s.exact[exactKey(rec.Method, params)] = append(s.exact[exactKey(rec.Method, params)], rec)
+		if key, ok := looseKey(rec.Method, params); ok {
+			s.loose[key] = append(s.loose[key], rec)
+		}
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// URL returns the endpoint URL.
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Client returns a new ethrpc.Client connected to the server.
+func (s *Server) Client() *ethrpc.Client {
+	return ethrpc.New(s.server.URL)
+}
+
+// SetBlock sets the current replay block, returned by eth_blockNumber and used to pick recordings.
+func (s *Server) SetBlock(block uint64) {
+	s.block.Store(block)
+}
+
+// Misses returns the calls that had no recorded response so far.
+func (s *Server) Misses() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.misses...)
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var resp any
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		var reqs []rpcRequest
+		if err = json.Unmarshal(trimmed, &reqs); err == nil {
+			resp = lo.Map(reqs, func(req rpcRequest, _ int) rpcResponse { return s.handle(req) })
+		}
+	} else {
+		var req rpcRequest
+		if err = json.Unmarshal(trimmed, &req); err == nil {
+			resp = s.handle(req)
+		}
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Server) handle(req rpcRequest) rpcResponse {
+	resp := rpcResponse{JSONRPC: "2.0", ID: req.ID}
+	params := splitParams(req.Params)
+	if rec, ok := s.lookup(req.Method, params); ok {
+		resp.Result, resp.Error = rec.Result, rec.Error
+		if resp.Result == nil && resp.Error == nil {
+			resp.Result = json.RawMessage("null")
+		}
+		return resp
+	}
+
+	switch req.Method {
+	case "eth_blockNumber":
+		resp.Result = lo.Must(json.Marshal(hexutil.Uint64(s.block.Load())))
+	case "eth_chainId", "net_version":
+		if s.chainID != 0 {
+			resp.Result = lo.Must(json.Marshal(hexutil.Uint64(s.chainID)))
+			if req.Method == "net_version" {
+				resp.Result = lo.Must(json.Marshal(strconv.FormatUint(s.chainID, 10)))
+			}
+			break
+		}
+		fallthrough
+	default:
+		miss := req.Method + " " + string(req.Params)
+		s.mu.Lock()
+		s.misses = append(s.misses, miss)
+		s.mu.Unlock()
+		resp.Error = &RPCError{Code: errCodeNoRecord, Message: "replay: no recorded response for " + miss}
+	}
+	return resp
+}
+
+func (s *Server) lookup(method string, params []json.RawMessage) (RPCRecord, bool) {
+	block := s.block.Load()
+	if rec, ok := latestAtOrBefore(s.exact[exactKey(method, params)], block); ok {
+		return rec, true
+	}
+	if key, ok := looseKey(method, params); ok {
+		return latestAtOrBefore(s.loose[key], block)
+	}
+	return RPCRecord{}, false
+}
+
+// latestAtOrBefore picks the recording with the greatest block not after block. Recordings without a block match any.
+func latestAtOrBefore(records []RPCRecord, block uint64) (RPCRecord, bool) {
+	var (
+		best  RPCRecord
+		found bool
+	)
+	for _, rec := range records {
+		if rec.Block > block {
+			continue
+		}
+		if !found || rec.Block >= best.Block {
+			best, found = rec, true
+		}
+	}
+	return best, found
+}
+
+func splitParams(raw json.RawMessage) []json.RawMessage {
+	var params []json.RawMessage
+	_ = json.Unmarshal(raw, &params)
+	return params
+}
+
+func normalizeParam(param json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, param); err != nil {
+		return strings.ToLower(string(param))
+	}
+	return strings.ToLower(buf.String())
+}
+
+func exactKey(method string, params []json.RawMessage) string {
+	return method + "|" + strings.Join(lo.Map(params, func(p json.RawMessage, _ int) string {
+		return normalizeParam(p)
+	}), "|")
+}
+
+// looseKey is exactKey without the block parameter.
+func looseKey(method string, params []json.RawMessage) (string, bool) {
+	idx, ok := blockParamIndex[method]
+	if !ok || idx >= len(params) {
+		return "", false
+	}
+	return exactKey(method, params[:idx]) + "|" + exactKey("", params[idx+1:]), true
+}
+
+// blockOf returns the numeric block parameter of a call, or 0 if it has none or uses a block tag.
+func blockOf(method string, params []json.RawMessage) uint64 {
+	idx, ok := blockParamIndex[method]
+	if !ok || idx >= len(params) {
+		return 0
+	}
+	var tag string
+	if err := json.Unmarshal(params[idx], &tag); err != nil {
+		return 0
+	}
+	block, err := hexutil.DecodeUint64(tag)
+	if err != nil {
+		return 0
+	}
+	return block
+}
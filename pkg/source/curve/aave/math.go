@@ -5,14 +5,16 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/math/stableswap"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 )
 
 var FeeDenominator = bignumber.NewBig10("10000000000")
 
-const MaxLoopLimit = 256
-
-var APrecision = big.NewInt(100)
+var APrecision = uint256.NewInt(100)
 
 /**
  * @notice Given a set of balances and precision multipliers, return the
@@ -54,37 +56,10 @@ func _getAPrecise(
 	initialATime int64,
 	initialA *big.Int,
 ) *big.Int {
-	var t1 = futureATime
-	var a1 = futureA
-	var now = time.Now().Unix()
-	if t1 > now {
-		var t0 = initialATime
-		var a0 = initialA
-		if a1.Cmp(a0) > 0 {
-			return new(big.Int).Add(
-				a0,
-				new(big.Int).Div(
-					new(big.Int).Mul(
-						new(big.Int).Sub(a1, a0),
-						new(big.Int).SetInt64(now-t0),
-					),
-					new(big.Int).SetInt64(t1-t0),
-				),
-			)
-		} else {
-			return new(big.Int).Sub(
-				a0,
-				new(big.Int).Div(
-					new(big.Int).Mul(
-						new(big.Int).Sub(a0, a1),
-						new(big.Int).SetInt64(now-t0),
-					),
-					new(big.Int).SetInt64(t1-t0),
-				),
-			)
-		}
-	}
-	return a1
+	var a0, a1 uint256.Int
+	a0.SetFromBig(initialA)
+	a1.SetFromBig(futureA)
+	return stableswap.A(&a0, &a1, initialATime, futureATime, time.Now().Unix()).ToBig()
 }
 
 /**
@@ -96,49 +71,16 @@ func _getAPrecise(
  * @return the invariant, at the precision of the pool
  */
 func getD(xp []*big.Int, a *big.Int) (*big.Int, error) {
-	var numTokens = len(xp)
-	var s = new(big.Int)
-	for i := range numTokens {
-		s = s.Add(s, xp[i])
-	}
-	if s.Sign() == 0 {
-		return s, nil
+	var xpU = make([]uint256.Int, len(xp))
+	var amp, d uint256.Int
+	if big256.FromBigs(xpU, xp) || amp.SetFromBig(a) {
+		return nil, ErrOverflow
 	}
-	var numTokensBI = big.NewInt(int64(numTokens))
-	var prevD big.Int
-	var d = new(big.Int).Set(s)
-	var nA = new(big.Int).Mul(a, numTokensBI)
-	var tmp, tmp2, mul big.Int
-	for range MaxLoopLimit {
-		var dP = new(big.Int).Set(d)
-		for j := range numTokens {
-			dP = dP.Div(
-				dP.Mul(dP, d),
-				tmp.Add(tmp.Mul(xp[j], numTokensBI), bignumber.One), // +1 is to prevent /0 (https://github.com/curvefi/curve-contract/blob/d4e8589/contracts/pools/aave/StableSwapAave.vy#L299)
-			)
-		}
-		prevD.Set(d)
-		if mul.Mul(
-			tmp.Add(
-				tmp.Div(tmp.Mul(nA, s), APrecision),
-				tmp2.Mul(dP, numTokensBI),
-			),
-			d,
-		).Cmp(bignumber.MaxUint256) > 0 {
-			return nil, ErrOverflow
-		}
-		d = d.Div(
-			&mul,
-			tmp.Add(
-				tmp.Div(tmp.Mul(tmp.Sub(nA, APrecision), d), APrecision),
-				tmp2.Mul(dP, big.NewInt(int64(numTokens+1))),
-			),
-		)
-		if tmp.Sub(d, &prevD).CmpAbs(bignumber.One) <= 0 {
-			return d, nil
-		}
+	// +1 is to prevent /0 (https://github.com/curvefi/curve-contract/blob/d4e8589/contracts/pools/aave/StableSwapAave.vy#L299)
+	if err := stableswap.GetD(xpU, &amp, APrecision, stableswap.DPPerCoinPlusOne, &d); err != nil {
+		return nil, err
 	}
-	return nil, ErrDDoesNotConverge
+	return d.ToBig(), nil
 }
 
 /**
@@ -165,70 +107,25 @@ func getY(
 	xp []*big.Int,
 	dCached *big.Int,
 ) (*big.Int, error) {
-	var numTokens = len(xp)
-	if tokenIndexFrom == tokenIndexTo {
-		return nil, ErrTokenFromEqualsTokenTo
-	}
-	if tokenIndexFrom >= numTokens && tokenIndexTo >= numTokens {
-		return nil, ErrTokenIndexesOutOfRange
-	}
-	var numTokensBI = big.NewInt(int64(numTokens))
 	var a = _getAPrecise(futureATime, futureA, initialATime, initialA)
 	d := dCached
-	if d == nil {
+	if d == nil && tokenIndexFrom != tokenIndexTo {
 		var err error
 		d, err = getD(xp, a)
 		if err != nil {
 			return nil, err
 		}
 	}
-	var c = new(big.Int).Set(d)
-	var s = big.NewInt(0)
-	var nA = new(big.Int).Mul(a, numTokensBI)
-	var _x *big.Int
-	for i := range numTokens {
-		if i == tokenIndexFrom {
-			_x = new(big.Int).Set(x)
-		} else if i != tokenIndexTo {
-			_x = new(big.Int).Set(xp[i])
-		} else {
-			continue
-		}
-		if _x.Cmp(bignumber.ZeroBI) == 0 {
-			return nil, ErrZero
-		}
-		s = new(big.Int).Add(s, _x)
-		c = new(big.Int).Div(
-			new(big.Int).Mul(c, d),
-			new(big.Int).Mul(_x, numTokensBI),
-		)
-	}
-	if nA.Cmp(bignumber.ZeroBI) == 0 {
-		return nil, ErrZero
+
+	var xpU = make([]uint256.Int, len(xp))
+	var xU, amp, dU, y uint256.Int
+	if big256.FromBigs(xpU, xp) || xU.SetFromBig(x) || amp.SetFromBig(a) || d != nil && dU.SetFromBig(d) {
+		return nil, ErrOverflow
 	}
-	c = new(big.Int).Div(
-		new(big.Int).Mul(new(big.Int).Mul(c, d), APrecision),
-		new(big.Int).Mul(nA, numTokensBI),
-	)
-	var b = new(big.Int).Add(
-		s,
-		new(big.Int).Div(new(big.Int).Mul(d, APrecision), nA),
-	)
-	var yPrev *big.Int
-	var y = new(big.Int).Set(d)
-	var denom big.Int
-	for range MaxLoopLimit {
-		yPrev = new(big.Int).Set(y)
-		denom.Sub(denom.Add(denom.Mul(y, big.NewInt(2)), b), d)
-		if denom.Sign() <= 0 {
-			return nil, ErrDenominatorZero
-		}
-		y = new(big.Int).Div(new(big.Int).Add(new(big.Int).Mul(y, y), c), &denom)
-		if new(big.Int).Sub(y, yPrev).CmpAbs(bignumber.One) <= 0 {
-			return y, nil
-		}
+	if err := stableswap.GetY(tokenIndexFrom, tokenIndexTo, &xU, xpU, &amp, APrecision, &dU, &y); err != nil {
+		return nil, err
 	}
-	return nil, ErrAmountOutNotConverge
+	return y.ToBig(), nil
 }
 
 /**
@@ -324,55 +221,18 @@ func getYD(
 	xp []*big.Int,
 	d *big.Int,
 ) (*big.Int, error) {
-	var numTokens = len(xp)
-	if tokenIndex >= numTokens {
+	if tokenIndex >= len(xp) {
 		return nil, ErrTokenNotFound
 	}
-	var numTokensBI = big.NewInt(int64(numTokens))
-	var c = new(big.Int).Set(d)
-	var s = big.NewInt(0)
-	var nA = new(big.Int).Mul(a, numTokensBI)
-	for i := range numTokens {
-		if i != tokenIndex {
-			s = new(big.Int).Add(s, xp[i])
-			c = new(big.Int).Div(
-				new(big.Int).Mul(c, d),
-				new(big.Int).Mul(xp[i], numTokensBI),
-			)
-		}
-	}
-	if nA.Cmp(bignumber.ZeroBI) == 0 {
-		return nil, ErrZero
+	var xpU = make([]uint256.Int, len(xp))
+	var amp, dU, y uint256.Int
+	if big256.FromBigs(xpU, xp) || amp.SetFromBig(a) || dU.SetFromBig(d) {
+		return nil, ErrOverflow
 	}
-	c = new(big.Int).Div(
-		new(big.Int).Mul(
-			new(big.Int).Mul(c, d),
-			APrecision,
-		),
-		new(big.Int).Mul(nA, numTokensBI),
-	)
-	var b = new(big.Int).Add(
-		s,
-		new(big.Int).Div(
-			new(big.Int).Mul(d, APrecision),
-			nA,
-		),
-	)
-	var yPrev *big.Int
-	var y = new(big.Int).Set(d)
-	var denom big.Int
-	for range MaxLoopLimit {
-		yPrev = new(big.Int).Set(y)
-		denom.Sub(denom.Add(denom.Mul(y, bignumber.Two), b), d)
-		if denom.Sign() <= 0 {
-			return nil, ErrDenominatorZero
-		}
-		y = new(big.Int).Div(new(big.Int).Add(new(big.Int).Mul(y, y), c), &denom)
-		if new(big.Int).Sub(y, yPrev).CmpAbs(bignumber.One) <= 0 {
-			return y, nil
-		}
+	if err := stableswap.GetYD(&amp, APrecision, tokenIndex, xpU, &dU, &y); err != nil {
+		return nil, err
 	}
-	return nil, ErrAmountOutNotConverge
+	return y.ToBig(), nil
 }
 
 /**
@@ -23,6 +23,7 @@ var (
 	compoundABI         abi.ABI
 	metaABIV0_2_12      abi.ABI
 	redemptionPriceSnap abi.ABI
+	ngABI               abi.ABI
 )
 
 func init() {
@@ -46,6 +47,7 @@ func init() {
 		{&compoundABI, compoundABIBytes},
 		{&metaABIV0_2_12, metaV0_2_12ABIBytes},
 		{&redemptionPriceSnap, redemptionPriceSnapABIBytes},
+		{&ngABI, ngABIBytes},
 	}
 
 	for _, b := range build {
@@ -5,8 +5,6 @@ import (
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 )
 
-const MaxLoopLimit = 256
-
 var (
 	DefaultGas     = curve.Gas{Exchange: 128000}
 	Precision      = bignumber.NewBig10("1000000000000000000")
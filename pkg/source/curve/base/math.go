@@ -4,6 +4,10 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/math/stableswap"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 )
 
@@ -40,37 +44,13 @@ func (t *PoolSimulator) get_D_mem(balances []*big.Int, amp *big.Int) (*big.Int,
 }
 
 func (t *PoolSimulator) _A() *big.Int {
-	var t1 = t.FutureATime
-	var a1 = t.FutureA
-	var now = time.Now().Unix()
-	if t1 > now {
-		var t0 = t.InitialATime
-		var a0 = t.InitialA
-		if a1.Cmp(a0) > 0 {
-			return new(big.Int).Add(
-				a0,
-				new(big.Int).Div(
-					new(big.Int).Mul(
-						new(big.Int).Sub(a1, a0),
-						new(big.Int).SetInt64(now-t0),
-					),
-					new(big.Int).SetInt64(t1-t0),
-				),
-			)
-		} else {
-			return new(big.Int).Sub(
-				a0,
-				new(big.Int).Div(
-					new(big.Int).Mul(
-						new(big.Int).Sub(a0, a1),
-						new(big.Int).SetInt64(now-t0),
-					),
-					new(big.Int).SetInt64(t1-t0),
-				),
-			)
-		}
+	if t.InitialA == nil || t.FutureA == nil {
+		return nil
 	}
-	return a1
+	var initialA, futureA uint256.Int
+	initialA.SetFromBig(t.InitialA)
+	futureA.SetFromBig(t.FutureA)
+	return stableswap.A(&initialA, &futureA, t.InitialATime, t.FutureATime, time.Now().Unix()).ToBig()
 }
 
 func (t *PoolSimulator) A() *big.Int {
@@ -83,93 +63,17 @@ func (t *PoolSimulator) APrecise() *big.Int {
 }
 
 func (t *PoolSimulator) getD(xp []*big.Int, a *big.Int) (*big.Int, error) {
-	var numTokens = len(xp)
-	var s = new(big.Int).SetInt64(0)
-	for i := range numTokens {
-		s = new(big.Int).Add(s, xp[i])
-	}
-	if s.Sign() == 0 {
-		return s, nil
-	}
-
-	// this is in a loop so should use local variable instead of allocating
-	// writing like this will hurt readability so double check with the original SC code here if needed
-	// https://github.com/curvefi/curve-contract/blob/d4e8589ac92c4019b3064b2f3a8a87dbc3281b46/contracts/pool-templates/base/SwapTemplateBase.vy#L217
-
-	/*
-		D: uint256 = S
-		Ann: uint256 = amp * N_COINS
-		for _i in range(255):
-		  D_P: uint256 = D
-		  for _x in xp:
-		    D_P = D_P * D / (_x * N_COINS)  # If division by 0, this will be borked: only withdrawal will work. And that is good
-		  Dprev = D
-		  D = (Ann * S / A_PRECISION + D_P * N_COINS) * D / ((Ann - A_PRECISION) * D / A_PRECISION + (N_COINS + 1) * D_P)
-		  # Equality with the precision of 1
-		  if D > Dprev:
-		    if D - Dprev <= 1:
-		      return D
-		  else:
-		    if Dprev - D <= 1:
-		      return D
-	*/
-	var d, dP, numTokensPlus1, nA, nA_mul_s_div_APrec, nA_sub_APrec, diff, prevD, tmp0, tmp1, tmp2, tmp3, tmp4, tmp5, tmp6, tmp7 big.Int
-	numTokensPlus1.SetInt64(int64(numTokens + 1))
-	d.Set(s)
-	nA.Mul(a, t.numTokensBI)
-	nA_mul_s_div_APrec.Mul(&nA, s)
-	nA_mul_s_div_APrec.Div(&nA_mul_s_div_APrec, t.APrecision)
-	nA_sub_APrec.Sub(&nA, t.APrecision)
-
-	for range MaxLoopLimit {
-		// D_P: uint256 = D
-		dP.Set(&d)
-
-		for j := range numTokens {
-			// D_P = D_P * D / (_x * N_COINS +1)
-			// +1 is to prevent /0 (https://github.com/curvefi/curve-contract/blob/d4e8589/contracts/pools/aave/StableSwapAave.vy#L299)
-
-			// nominator
-			tmp0.Mul(&dP, &d)
-
-			// On-chain uses uint256; if D_P*D would overflow uint256 the contract reverts.
-			// big.Int never overflows so we must check explicitly.
-			if tmp0.BitLen() > 256 {
-				return nil, ErrDDoesNotConverge
-			}
-
-			// denominator
-			tmp1.Mul(xp[j], t.numTokensBI)
-			tmp1.Add(&tmp1, bignumber.One)
-
-			// update dP
-			dP.Div(&tmp0, &tmp1)
-		}
-		// Dprev = D
-		prevD.Set(&d)
-
-		// D = (Ann * S / A_PRECISION + D_P * N_COINS) * D / ((Ann - A_PRECISION) * D / A_PRECISION + (N_COINS + 1) * D_P)
-
-		// nominator
-		tmp6.Add(&nA_mul_s_div_APrec, tmp3.Mul(&dP, t.numTokensBI)) // (Ann * S / A_PRECISION + D_P * N_COINS)
-		tmp2.Mul(&tmp6, &d)                                         // (Ann * S / A_PRECISION + D_P * N_COINS) * D
-
-		// denominator
-		tmp7.Mul(&nA_sub_APrec, &d)    // (Ann - A_PRECISION) * D
-		tmp4.Div(&tmp7, t.APrecision)  // (Ann - A_PRECISION) * D / A_PRECISION
-		tmp5.Mul(&dP, &numTokensPlus1) // (N_COINS + 1) * D_P
-		tmp4.Add(&tmp4, &tmp5)         // (Ann - A_PRECISION) * D / A_PRECISION + (N_COINS + 1) * D_P
-
-		// update d
-		d.Div(&tmp2, &tmp4)
-
-		// calc abs(D - Dprev) and compare against 1
-		diff.Sub(&d, &prevD)
-		if diff.CmpAbs(bignumber.One) <= 0 {
-			return new(big.Int).Set(&d), nil
-		}
+	var xpU = make([]uint256.Int, len(xp))
+	var amp, aPrecision, D uint256.Int
+	if big256.FromBigs(xpU, xp) || amp.SetFromBig(a) || aPrecision.SetFromBig(t.APrecision) {
+		return nil, ErrDDoesNotConverge
+	}
+	// D_P = D_P * D / (_x * N_COINS + 1)
+	// +1 is to prevent /0 (https://github.com/curvefi/curve-contract/blob/d4e8589/contracts/pools/aave/StableSwapAave.vy#L299)
+	if err := stableswap.GetD(xpU, &amp, &aPrecision, stableswap.DPPerCoinPlusOne, &D); err != nil {
+		return nil, err
 	}
-	return nil, ErrDDoesNotConverge
+	return D.ToBig(), nil
 }
 
 func (t *PoolSimulator) GetY(
@@ -179,105 +83,30 @@ func (t *PoolSimulator) GetY(
 	xp []*big.Int,
 	dCached *big.Int,
 ) (*big.Int, error) {
-	var numTokens = len(xp)
-	if tokenIndexFrom == tokenIndexTo {
-		return nil, ErrTokenFromEqualsTokenTo
-	}
-	if tokenIndexFrom >= numTokens && tokenIndexTo >= numTokens {
-		return nil, ErrTokenIndexesOutOfRange
-	}
-
 	var a = t._A()
 	if a == nil {
 		return nil, ErrInvalidAValue
 	}
 
 	d := dCached
-	if d == nil {
+	if d == nil && tokenIndexFrom != tokenIndexTo {
 		var err error
 		d, err = t.getD(xp, a)
 		if err != nil {
 			return nil, err
 		}
 	}
-	var c = new(big.Int).Set(d)
-	var s = big.NewInt(0)
-	var nA = new(big.Int).Mul(a, t.numTokensBI)
-	var _x *big.Int
-	for i := range numTokens {
-		if i == tokenIndexFrom {
-			_x = new(big.Int).Set(x)
-		} else if i != tokenIndexTo {
-			_x = new(big.Int).Set(xp[i])
-		} else {
-			continue
-		}
-		if _x.Cmp(bignumber.ZeroBI) == 0 {
-			return nil, ErrZero
-		}
-		s = new(big.Int).Add(s, _x)
-		c = new(big.Int).Div(
-			new(big.Int).Mul(c, d),
-			new(big.Int).Mul(_x, t.numTokensBI),
-		)
-	}
-	if nA.Cmp(bignumber.ZeroBI) == 0 {
-		return nil, ErrZero
-	}
-	c = new(big.Int).Div(
-		new(big.Int).Mul(new(big.Int).Mul(c, d), t.APrecision),
-		new(big.Int).Mul(nA, t.numTokensBI),
-	)
-	var b = new(big.Int).Add(
-		s,
-		new(big.Int).Div(new(big.Int).Mul(d, t.APrecision), nA),
-	)
-
-	// this is in a loop so should use local variable instead of allocating
-	// writing like this will hurt readability so double check with the original SC code here if needed
-	// https://github.com/curvefi/curve-contract/blob/d4e8589ac92c4019b3064b2f3a8a87dbc3281b46/contracts/pool-templates/base/SwapTemplateBase.vy#L408
-	/*
-		for _i in range(255):
-			y_prev = y
-			y = (y*y + c) / (2 * y + b - D)
-			# Equality with the precision of 1
-			if y > y_prev:
-		  	if y - y_prev <= 1:
-		    	return y
-			else:
-		  	if y_prev - y <= 1:
-		    	return y
-	*/
-	var tmp, tmp1 big.Int
-	var yPrev big.Int
-	var y big.Int
-	y.Set(d)
-	var diff big.Int
-	for range MaxLoopLimit {
-		// y_prev = y
-		yPrev.Set(&y)
 
-		// y = (y*y + c) / (2 * y + b - D)
-		// first calc denominator into tmp
-		tmp.Mul(&y, bignumber.Two)
-		tmp.Add(&tmp, b)
-		tmp.Sub(&tmp, d)
-		if tmp.Sign() <= 0 {
-			return nil, ErrDenominatorZero
-		}
-		// then calc nominator into tmp1
-		tmp1.Mul(&y, &y)
-		tmp1.Add(&tmp1, c)
-		// then the whole y
-		y.Div(&tmp1, &tmp)
-
-		// calc abs(y - y_prev) and compare against 1
-		diff.Sub(&y, &yPrev)
-		if diff.CmpAbs(bignumber.One) <= 0 {
-			return new(big.Int).Set(&y), nil
-		}
+	var xpU = make([]uint256.Int, len(xp))
+	var xU, amp, aPrecision, dU, y uint256.Int
+	if big256.FromBigs(xpU, xp) || xU.SetFromBig(x) || amp.SetFromBig(a) ||
+		aPrecision.SetFromBig(t.APrecision) || d != nil && dU.SetFromBig(d) {
+		return nil, ErrOverflow
+	}
+	if err := stableswap.GetY(tokenIndexFrom, tokenIndexTo, &xU, xpU, &amp, &aPrecision, &dU, &y); err != nil {
+		return nil, err
 	}
-	return nil, ErrAmountOutNotConverge
+	return y.ToBig(), nil
 }
 
 func (t *PoolSimulator) GetDy(
@@ -317,51 +146,18 @@ func (t *PoolSimulator) getYD(
 	xp []*big.Int,
 	d *big.Int,
 ) (*big.Int, error) {
-	var numTokens = len(xp)
-	if tokenIndex >= numTokens {
+	if tokenIndex >= len(xp) {
 		return nil, ErrTokenNotFound
 	}
-	var c = new(big.Int).Set(d)
-	var s = big.NewInt(0)
-	var nA = new(big.Int).Mul(a, t.numTokensBI)
-	for i := range numTokens {
-		if i != tokenIndex {
-			s = new(big.Int).Add(s, xp[i])
-			c = new(big.Int).Div(
-				new(big.Int).Mul(c, d),
-				new(big.Int).Mul(xp[i], t.numTokensBI),
-			)
-		}
+	var xpU = make([]uint256.Int, len(xp))
+	var amp, aPrecision, dU, y uint256.Int
+	if big256.FromBigs(xpU, xp) || amp.SetFromBig(a) || aPrecision.SetFromBig(t.APrecision) || dU.SetFromBig(d) {
+		return nil, ErrOverflow
 	}
-	if nA.Cmp(bignumber.ZeroBI) == 0 {
-		return nil, ErrZero
-	}
-	c = new(big.Int).Div(
-		new(big.Int).Mul(new(big.Int).Mul(c, d), t.APrecision),
-		new(big.Int).Mul(nA, t.numTokensBI),
-	)
-	var b = new(big.Int).Add(
-		s,
-		new(big.Int).Div(new(big.Int).Mul(d, t.APrecision), nA),
-	)
-	var yPrev *big.Int
-	var y = new(big.Int).Set(d)
-	var denom big.Int
-	for range MaxLoopLimit {
-		yPrev = new(big.Int).Set(y)
-		denom.Sub(denom.Add(denom.Mul(y, bignumber.Two), b), d)
-		if denom.Sign() <= 0 {
-			return nil, ErrDenominatorZero
-		}
-		y = new(big.Int).Div(
-			new(big.Int).Add(new(big.Int).Mul(y, y), c),
-			&denom,
-		)
-		if new(big.Int).Sub(y, yPrev).CmpAbs(bignumber.One) <= 0 {
-			return y, nil
-		}
+	if err := stableswap.GetYD(&amp, &aPrecision, tokenIndex, xpU, &dU, &y); err != nil {
+		return nil, err
 	}
-	return nil, ErrAmountOutNotConverge
+	return y.ToBig(), nil
 }
 
 /**
@@ -1,10 +1,15 @@
 package compound
 
-import "github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+import (
+	"github.com/holiman/uint256"
 
-const MaxLoopLimit = 256
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
 
 var (
 	DefaultGas     = Gas{Exchange: 285000, ExchangeUnderlying: 390000}
 	FeeDenominator = bignumber.NewBig10("10000000000")
+
+	// aPrecision is 1 as compound pools store A without A_PRECISION.
+	aPrecision = uint256.NewInt(1)
 )
@@ -1,13 +1,18 @@
 package compound
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/math/stableswap"
+)
 
 var (
-	ErrZero                         = errors.New("zero")
+	ErrZero                         = stableswap.ErrZero
 	ErrBalancesMustMatchMultipliers = errors.New("balances must match multipliers")
-	ErrDDoesNotConverge             = errors.New("d does not converge")
-	ErrTokenFromEqualsTokenTo       = errors.New("can't compare token to itself")
-	ErrTokenIndexesOutOfRange       = errors.New("token index out of range")
-	ErrAmountOutNotConverge         = errors.New("approximation did not converge")
-	ErrDenominatorZero              = errors.New("denominator should not be 0")
+	ErrDDoesNotConverge             = stableswap.ErrDDoesNotConverge
+	ErrTokenFromEqualsTokenTo       = stableswap.ErrTokenFromEqualsTokenTo
+	ErrTokenIndexesOutOfRange       = stableswap.ErrTokenIndexesOutOfRange
+	ErrAmountOutNotConverge         = stableswap.ErrAmountOutNotConverge
+	ErrDenominatorZero              = stableswap.ErrDenominatorZero
+	ErrOverflow                     = stableswap.ErrOverflow
 )
@@ -3,6 +3,10 @@ package compound
 import (
 	"math/big"
 
+	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/math/stableswap"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 )
 
@@ -24,46 +28,17 @@ func _xp(
 }
 
 func getD(xp []*big.Int, a *big.Int) (*big.Int, error) {
-
-	var numTokens = len(xp)
-	var s = new(big.Int).SetInt64(0)
-	for i := range numTokens {
-		s = new(big.Int).Add(s, xp[i])
-	}
-	if s.Sign() == 0 {
-		return s, nil
+	var xpU = make([]uint256.Int, len(xp))
+	var amp, d uint256.Int
+	if big256.FromBigs(xpU, xp) || amp.SetFromBig(a) {
+		return nil, ErrDDoesNotConverge
 	}
-	var numTokensBI = big.NewInt(int64(numTokens))
-	var prevD *big.Int
-	var d = new(big.Int).Set(s)
-	var nA = new(big.Int).Mul(a, numTokensBI)
-	for range MaxLoopLimit {
-		var dP = new(big.Int).Set(d)
-		for j := range numTokens {
-			dP = new(big.Int).Div(
-				new(big.Int).Mul(dP, d),
-				new(big.Int).Add(new(big.Int).Mul(xp[j], numTokensBI), bignumber.One), // +1 is to prevent /0 (https://github.com/curvefi/curve-contract/blob/d4e8589/contracts/pools/aave/StableSwapAave.vy#L299)
-			)
-		}
-		prevD = d
-		d = new(big.Int).Div(
-			new(big.Int).Mul(
-				new(big.Int).Add(
-					new(big.Int).Mul(nA, s),
-					new(big.Int).Mul(dP, numTokensBI),
-				),
-				d,
-			),
-			new(big.Int).Add(
-				new(big.Int).Mul(new(big.Int).Sub(nA, new(big.Int).SetInt64(1)), d),
-				new(big.Int).Mul(dP, big.NewInt(int64(numTokens+1))),
-			),
-		)
-		if new(big.Int).Sub(d, prevD).CmpAbs(big.NewInt(1)) <= 0 {
-			return d, nil
-		}
+	// these pools predate A_PRECISION, and +1 is to prevent /0
+	// (https://github.com/curvefi/curve-contract/blob/d4e8589/contracts/pools/aave/StableSwapAave.vy#L299)
+	if err := stableswap.GetD(xpU, &amp, aPrecision, stableswap.DPPerCoinPlusOne, &d); err != nil {
+		return nil, err
 	}
-	return nil, ErrDDoesNotConverge
+	return d.ToBig(), nil
 }
 
 func getY(
@@ -73,68 +48,23 @@ func getY(
 	x *big.Int,
 	xp []*big.Int,
 ) (*big.Int, error) {
-	var numTokens = len(xp)
 	if tokenIndexFrom == tokenIndexTo {
 		return nil, ErrTokenFromEqualsTokenTo
 	}
-	if tokenIndexFrom >= numTokens && tokenIndexTo >= numTokens {
-		return nil, ErrTokenIndexesOutOfRange
-	}
-	var numTokensBI = big.NewInt(int64(numTokens))
-	var a = APrecise
-	var d, err = getD(xp, a)
+	var d, err = getD(xp, APrecise)
 	if err != nil {
 		return nil, err
 	}
-	var c = new(big.Int).Set(d)
-	var s = big.NewInt(0)
-	var nA = new(big.Int).Mul(a, numTokensBI)
-	var _x *big.Int
-	for i := range numTokens {
-		if i == tokenIndexFrom {
-			_x = new(big.Int).Set(x)
-		} else if i != tokenIndexTo {
-			_x = new(big.Int).Set(xp[i])
-		} else {
-			continue
-		}
-		if _x.Cmp(bignumber.ZeroBI) == 0 {
-			return nil, ErrZero
-		}
-		s = new(big.Int).Add(s, _x)
-		c = new(big.Int).Div(
-			new(big.Int).Mul(c, d),
-			new(big.Int).Mul(_x, numTokensBI),
-		)
-	}
-	if nA.Cmp(bignumber.ZeroBI) == 0 {
-		return nil, ErrZero
-	}
-	c = new(big.Int).Div(
-		new(big.Int).Mul(c, d),
-		new(big.Int).Mul(nA, numTokensBI),
-	)
 
-	var b = new(big.Int).Add(
-		s,
-		new(big.Int).Div(d, nA),
-	)
-	var yPrev *big.Int
-	var y = new(big.Int).Set(d)
-	var denom big.Int
-	for range MaxLoopLimit {
-		yPrev = new(big.Int).Set(y)
-		denom.Sub(denom.Add(denom.Mul(y, big.NewInt(2)), b), d)
-		if denom.Sign() <= 0 {
-			return nil, ErrDenominatorZero
-		}
-		y = new(big.Int).Div(new(big.Int).Add(new(big.Int).Mul(y, y), c), &denom)
-		if new(big.Int).Sub(y, yPrev).CmpAbs(bignumber.One) <= 0 {
-			return y, nil
-		}
+	var xpU = make([]uint256.Int, len(xp))
+	var xU, amp, dU, y uint256.Int
+	if big256.FromBigs(xpU, xp) || xU.SetFromBig(x) || amp.SetFromBig(APrecise) || dU.SetFromBig(d) {
+		return nil, ErrOverflow
 	}
-	return nil, ErrAmountOutNotConverge
-
+	if err = stableswap.GetY(tokenIndexFrom, tokenIndexTo, &xU, xpU, &amp, aPrecision, &dU, &y); err != nil {
+		return nil, err
+	}
+	return y.ToBig(), nil
 }
 
 func GetDyUnderlying(
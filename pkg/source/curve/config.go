@@ -5,9 +5,12 @@ type Config struct {
 	ChainID      int    `json:"chainID"`
 	PoolPath     string `json:"poolPath"`
 	NewPoolLimit int    `json:"newPoolLimit"`
-	// SkipInitFactory include dexes that don't have factory.
+	// SkipInitFactory skips resolving the registries and factories from AddressProvider,
+	// for dexes that don't have one; only the addresses configured below and the pools of PoolPath are used.
 	SkipInitFactory bool `json:"skipInitFactory"`
 
+	// AddressProvider resolves the addresses below it has. Chains without one, such as ethw, fantom and ellipsis on
+	// bsc, list their pools in PoolPath and configure their registries and factories instead.
 	AddressProvider            string `json:"addressProvider"`
 	MainRegistryAddress        string `json:"mainRegistryAddress"`
 	MetaRegistryAddress        string `json:"metaRegistryAddress"`
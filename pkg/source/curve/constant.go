@@ -51,6 +51,10 @@ const (
 	poolMethodLastPrices          = "last_prices"
 	poolMethodBasePool            = "base_pool"
 
+	// only implemented by the ng pools: stable-ng, twocrypto-ng and tricrypto-ng
+	ngMethodDMaTime                 = "D_ma_time"
+	ngMethodPackedRebalancingParams = "packed_rebalancing_params"
+
 	poolMethodRedemptionPriceSnap      = "redemption_price_snap"
 	oracleMethodSnappedRedemptionPrice = "snappedRedemptionPrice"
 
@@ -89,6 +93,7 @@ const (
 	sourceMetaPoolsFactory
 	sourceCryptoPoolsRegistry
 	sourceCryptoPoolsFactory
+	sourceMetaRegistry
 )
 
 // Known weth9 implementation addresses, used in our implementation of Ether#wrapped
@@ -50,6 +50,9 @@ var compoundABIBytes []byte
 //go:embed abi/ERC20.json
 var erc20ABIBytes []byte
 
+//go:embed abi/NG.json
+var ngABIBytes []byte
+
 //go:embed pools/arbitrum.json
 var arbitrumPoolsBytes []byte
 
@@ -71,6 +74,9 @@ var polygonPoolsBytes []byte
 //go:embed pools/base.json
 var basePoolsBytes []byte
 
+//go:embed pools/ethw.json
+var ethwPoolsBytes []byte
+
 // Ellipsis pool bytes
 
 //go:embed pools/ellipsis/bsc.json
@@ -84,6 +90,7 @@ var bytesByPath = map[string][]byte{
 	"pools/optimism.json":  optimismPoolsBytes,
 	"pools/polygon.json":   polygonPoolsBytes,
 	"pools/base.json":      basePoolsBytes,
+	"pools/ethw.json":      ethwPoolsBytes,
 
 	"pools/ellipsis/bsc.json": ellipsisBscPoolsBytes,
 }
@@ -11,7 +11,13 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
+// initConfig resolves the registries and factories of config from its AddressProvider. Addresses the provider does not
+// have are left as configured, and chains without a provider only use the configured ones.
 func initConfig(config *Config, ethrpcClient *ethrpc.Client) error {
+	if config.AddressProvider == "" {
+		return nil
+	}
+
 	var (
 		mainRegistryAddress, metaRegistryAddress, metaFactoryAddress, cryptoRegistryAddress, cryptoFactoryAddress common.Address
 	)
@@ -53,11 +59,17 @@ func initConfig(config *Config, ethrpcClient *ethrpc.Client) error {
 		return err
 	}
 
-	config.MainRegistryAddress = mainRegistryAddress.Hex()
-	config.MetaRegistryAddress = metaRegistryAddress.Hex()
-	config.MetaPoolsFactoryAddress = metaFactoryAddress.Hex()
-	config.CryptoPoolsRegistryAddress = cryptoRegistryAddress.Hex()
-	config.CryptoPoolsFactoryAddress = cryptoFactoryAddress.Hex()
+	for address, resolved := range map[*string]common.Address{
+		&config.MainRegistryAddress:        mainRegistryAddress,
+		&config.MetaRegistryAddress:        metaRegistryAddress,
+		&config.MetaPoolsFactoryAddress:    metaFactoryAddress,
+		&config.CryptoPoolsRegistryAddress: cryptoRegistryAddress,
+		&config.CryptoPoolsFactoryAddress:  cryptoFactoryAddress,
+	} {
+		if resolved != (common.Address{}) || *address == "" {
+			*address = resolved.Hex()
+		}
+	}
 
 	return nil
 }
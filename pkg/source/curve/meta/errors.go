@@ -1,15 +1,20 @@
 package meta
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/math/stableswap"
+)
 
 var (
 	ErrInvalidBasePool               = errors.New("invalid base pool")
-	ErrDDoesNotConverge              = errors.New("d does not converge")
-	ErrTokenFromEqualsTokenTo        = errors.New("can't compare token to itself")
-	ErrTokenIndexesOutOfRange        = errors.New("token index out of range")
+	ErrDDoesNotConverge              = stableswap.ErrDDoesNotConverge
+	ErrTokenFromEqualsTokenTo        = stableswap.ErrTokenFromEqualsTokenTo
+	ErrTokenIndexesOutOfRange        = stableswap.ErrTokenIndexesOutOfRange
 	ErrAmountInOverflow              = errors.New("amount in overflow")
-	ErrAmountOutNotConverge          = errors.New("approximation did not converge")
+	ErrAmountOutNotConverge          = stableswap.ErrAmountOutNotConverge
 	ErrBasePoolExchangeNotSupported  = errors.New("not support exchange in base pool")
 	ErrTokenToUnderLyingNotSupported = errors.New("not support exchange from base pool token to its underlying")
-	ErrDenominatorZero               = errors.New("denominator should not be 0")
+	ErrDenominatorZero               = stableswap.ErrDenominatorZero
+	ErrZero                          = stableswap.ErrZero
 )
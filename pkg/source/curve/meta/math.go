@@ -7,6 +7,7 @@ import (
 	"github.com/KyberNetwork/blockchain-toolkit/number"
 	"github.com/holiman/uint256"
 
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/math/stableswap"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 )
@@ -21,59 +22,15 @@ func (t *PoolSimulator) _xp_mem(_balances []*uint256.Int, vPrice *uint256.Int) (
 }
 
 func (t *PoolSimulator) _get_D(xp []*uint256.Int, a *uint256.Int) (*uint256.Int, error) {
-	numTokens := len(xp)
-	var s uint256.Int
-	for i := range numTokens {
-		s.Add(&s, xp[i])
-	}
-	if s.IsZero() {
-		return &s, nil
-	}
-	var uNumTokens, uNumTokensPlusOne uint256.Int
-	uNumTokens.SetUint64(uint64(numTokens))
-	uNumTokensPlusOne.SetUint64(uint64(numTokens + 1))
-	var prevD, d, nA uint256.Int
-	d.Set(&s)
-	nA.Mul(a, &uNumTokens)
-	var dP, tmp, tmp2 uint256.Int
-	for range MaxLoopLimit {
-		dP.Set(&d)
-		for j := range numTokens {
-			if xp[j].IsZero() {
-				return nil, ErrDenominatorZero
-			}
-			dP.MulDivOverflow(&dP, &d, tmp.Mul(xp[j], &uNumTokens))
-		}
-		prevD.Set(&d)
-		tmp.MulDivOverflow(&nA, &s, t.APrecision)
-		tmp.Mul(tmp.Add(&tmp, tmp2.Mul(&dP, &uNumTokens)), &d)
-		d.MulDivOverflow(tmp2.Sub(&nA, t.APrecision), &d, t.APrecision)
-		d.Div(&tmp, d.Add(&d, tmp2.Mul(&dP, &uNumTokensPlusOne)))
-		if tmp.Abs(tmp.Sub(&d, &prevD)).CmpUint64(1) <= 0 {
-			return &d, nil
-		}
+	var d uint256.Int
+	if err := stableswap.GetD(derefXp(xp), a, t.APrecision, stableswap.DPPerCoin, &d); err != nil {
+		return nil, err
 	}
-	return nil, ErrDDoesNotConverge
+	return &d, nil
 }
 
 func (t *PoolSimulator) _A() *uint256.Int {
-	t1, a1 := t.FutureATime, t.FutureA
-	now := time.Now().Unix()
-	if t1 <= now {
-		return a1
-	}
-	t0, a0 := t.InitialATime, t.InitialA
-	var tmp, tmp2 uint256.Int
-	return tmp.Add(
-		a0,
-		tmp.SDiv(
-			tmp.Mul(
-				tmp.Sub(a1, a0),
-				tmp2.SetUint64(uint64(now-t0)),
-			),
-			tmp2.SetUint64(uint64(t1-t0)),
-		),
-	)
+	return stableswap.A(t.InitialA, t.FutureA, t.InitialATime, t.FutureATime, time.Now().Unix())
 }
 
 func (t *PoolSimulator) A() *uint256.Int {
@@ -90,47 +47,28 @@ func (t *PoolSimulator) _get_y(
 	x *uint256.Int,
 	xp []*uint256.Int,
 ) (*uint256.Int, error) {
-	var numTokens = len(xp)
 	if i == j {
 		return nil, ErrTokenFromEqualsTokenTo
 	}
-	if i >= numTokens && j >= numTokens {
-		return nil, ErrTokenIndexesOutOfRange
-	}
-	var nCoins = uint256.NewInt(uint64(numTokens))
 	a := t._A()
 	d, err := t._get_D(xp, a)
 	if err != nil {
 		return nil, err
 	}
-	var c, s, Ann, y_prev, b, y, tmp uint256.Int
-	var _x *uint256.Int
-	c.Set(d)
-	Ann.Mul(a, nCoins)
-	for _i := range numTokens {
-		if _i == i {
-			_x = x
-		} else if _i != j {
-			_x = xp[_i]
-		} else {
-			continue
-		}
-		s.Add(&s, _x)
-		c.MulDivOverflow(&c, d, tmp.Mul(_x, nCoins))
+	var y uint256.Int
+	if err = stableswap.GetY(i, j, x, derefXp(xp), a, t.APrecision, d, &y); err != nil {
+		return nil, err
 	}
-	c.MulDivOverflow(c.Mul(&c, d), t.APrecision, tmp.Mul(&Ann, nCoins))
-	b.MulDivOverflow(d, t.APrecision, &Ann)
-	b.Add(&s, &b)
-	y.Set(d)
-	for range MaxLoopLimit {
-		y_prev.Set(&y)
-		tmp.Sub(tmp.Add(tmp.Mul(&y, big256.U2), &b), d)
-		y.Div(y.Add(y.Mul(&y, &y), &c), &tmp)
-		if tmp.Abs(tmp.Sub(&y, &y_prev)).CmpUint64(1) <= 0 {
-			return &y, nil
-		}
+	return &y, nil
+}
+
+// derefXp copies xp into the value slice used by the stableswap math.
+func derefXp(xp []*uint256.Int) []uint256.Int {
+	ret := make([]uint256.Int, len(xp))
+	for i := range xp {
+		ret[i].Set(xp[i])
 	}
-	return nil, ErrAmountOutNotConverge
+	return ret
 }
 
 func (t *PoolSimulator) _get_dy_mem(i int, j int, _dx *uint256.Int, _balances []*uint256.Int) (*uint256.Int,
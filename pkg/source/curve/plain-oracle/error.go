@@ -1,17 +1,22 @@
 package plainoracle
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/math/stableswap"
+)
 
 var (
 	ErrInvalidAValue                = errors.New("invalid A value")
 	ErrBalancesMustMatchMultipliers = errors.New("balances must match multipliers")
-	ErrZero                         = errors.New("zero")
-	ErrDDoesNotConverge             = errors.New("d does not converge")
-	ErrTokenFromEqualsTokenTo       = errors.New("can't compare token to itself")
-	ErrTokenIndexesOutOfRange       = errors.New("token index out of range")
-	ErrAmountOutNotConverge         = errors.New("approximation did not converge")
+	ErrZero                         = stableswap.ErrZero
+	ErrDDoesNotConverge             = stableswap.ErrDDoesNotConverge
+	ErrTokenFromEqualsTokenTo       = stableswap.ErrTokenFromEqualsTokenTo
+	ErrTokenIndexesOutOfRange       = stableswap.ErrTokenIndexesOutOfRange
+	ErrAmountOutNotConverge         = stableswap.ErrAmountOutNotConverge
 	ErrTokenNotFound                = errors.New("token not found")
 	ErrWithdrawMoreThanAvailable    = errors.New("cannot withdraw more than available")
 	ErrD1LowerThanD0                = errors.New("d1 <= d0")
-	ErrDenominatorZero              = errors.New("denominator should not be 0")
+	ErrDenominatorZero              = stableswap.ErrDenominatorZero
+	ErrOverflow                     = stableswap.ErrOverflow
 )
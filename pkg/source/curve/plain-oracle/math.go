@@ -4,6 +4,10 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/math/stableswap"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 )
 
@@ -44,79 +48,27 @@ func (t *PoolSimulator) get_D_mem(balances []*big.Int, amp *big.Int) (*big.Int,
 }
 
 func (t *PoolSimulator) _A() *big.Int {
-	var t1 = t.FutureATime
-	var a1 = t.FutureA
-	var now = time.Now().Unix()
-	if t1 > now {
-		var t0 = t.InitialATime
-		var a0 = t.InitialA
-		if a1.Cmp(a0) > 0 {
-			return new(big.Int).Add(
-				a0,
-				new(big.Int).Div(
-					new(big.Int).Mul(
-						new(big.Int).Sub(a1, a0),
-						new(big.Int).SetInt64(now-t0),
-					),
-					new(big.Int).SetInt64(t1-t0),
-				),
-			)
-		} else {
-			return new(big.Int).Sub(
-				a0,
-				new(big.Int).Div(
-					new(big.Int).Mul(
-						new(big.Int).Sub(a0, a1),
-						new(big.Int).SetInt64(now-t0),
-					),
-					new(big.Int).SetInt64(t1-t0),
-				),
-			)
-		}
+	if t.InitialA == nil || t.FutureA == nil {
+		return nil
 	}
-	return a1
+	var initialA, futureA uint256.Int
+	initialA.SetFromBig(t.InitialA)
+	futureA.SetFromBig(t.FutureA)
+	return stableswap.A(&initialA, &futureA, t.InitialATime, t.FutureATime, time.Now().Unix()).ToBig()
 }
 
 func (t *PoolSimulator) getD(xp []*big.Int, a *big.Int) (*big.Int, error) {
-	var numTokens = len(xp)
-	var s = new(big.Int).SetInt64(0)
-	for i := range numTokens {
-		s = new(big.Int).Add(s, xp[i])
-	}
-	if s.Sign() == 0 {
-		return s, nil
-	}
-	var numTokensBI = big.NewInt(int64(numTokens))
-	var prevD *big.Int
-	var d = new(big.Int).Set(s)
-	var nA = new(big.Int).Mul(a, numTokensBI)
-	for range MaxLoopLimit {
-		var dP = new(big.Int).Set(d)
-		for j := range numTokens {
-			dP = new(big.Int).Div(
-				new(big.Int).Mul(dP, d),
-				new(big.Int).Add(new(big.Int).Mul(xp[j], numTokensBI), bignumber.One), // +1 is to prevent /0 (https://github.com/curvefi/curve-contract/blob/d4e8589/contracts/pools/aave/StableSwapAave.vy#L299)
-			)
-		}
-		prevD = d
-		d = new(big.Int).Div(
-			new(big.Int).Mul(
-				new(big.Int).Add(
-					new(big.Int).Div(new(big.Int).Mul(nA, s), t.APrecision),
-					new(big.Int).Mul(dP, numTokensBI),
-				),
-				d,
-			),
-			new(big.Int).Add(
-				new(big.Int).Div(new(big.Int).Mul(new(big.Int).Sub(nA, t.APrecision), d), t.APrecision),
-				new(big.Int).Mul(dP, big.NewInt(int64(numTokens+1))),
-			),
-		)
-		if new(big.Int).Sub(d, prevD).CmpAbs(big.NewInt(1)) <= 0 {
-			return d, nil
-		}
+	var xpU = make([]uint256.Int, len(xp))
+	var amp, aPrecision, D uint256.Int
+	if big256.FromBigs(xpU, xp) || amp.SetFromBig(a) || aPrecision.SetFromBig(t.APrecision) {
+		return nil, ErrDDoesNotConverge
+	}
+	// D_P = D_P * D / (_x * N_COINS + 1)
+	// +1 is to prevent /0 (https://github.com/curvefi/curve-contract/blob/d4e8589/contracts/pools/aave/StableSwapAave.vy#L299)
+	if err := stableswap.GetD(xpU, &amp, &aPrecision, stableswap.DPPerCoinPlusOne, &D); err != nil {
+		return nil, err
 	}
-	return nil, ErrDDoesNotConverge
+	return D.ToBig(), nil
 }
 
 func (t *PoolSimulator) getY(
@@ -126,74 +78,30 @@ func (t *PoolSimulator) getY(
 	xp []*big.Int,
 	dCached *big.Int,
 ) (*big.Int, error) {
-	var numTokens = len(xp)
-	if tokenIndexFrom == tokenIndexTo {
-		return nil, ErrTokenFromEqualsTokenTo
-	}
-	if tokenIndexFrom >= numTokens && tokenIndexTo >= numTokens {
-		return nil, ErrTokenIndexesOutOfRange
-	}
-	var numTokensBI = big.NewInt(int64(numTokens))
 	var a = t._A()
 	if a == nil {
 		return nil, ErrInvalidAValue
 	}
 
 	d := dCached
-	if d == nil {
+	if d == nil && tokenIndexFrom != tokenIndexTo {
 		var err error
 		d, err = t.getD(xp, a)
 		if err != nil {
 			return nil, err
 		}
 	}
-	var c = new(big.Int).Set(d)
-	var s = big.NewInt(0)
-	var nA = new(big.Int).Mul(a, numTokensBI)
-	var _x *big.Int
-	for i := range numTokens {
-		if i == tokenIndexFrom {
-			_x = new(big.Int).Set(x)
-		} else if i != tokenIndexTo {
-			_x = new(big.Int).Set(xp[i])
-		} else {
-			continue
-		}
-		if _x.Cmp(bignumber.ZeroBI) == 0 {
-			return nil, ErrZero
-		}
-		s = new(big.Int).Add(s, _x)
-		c = new(big.Int).Div(
-			new(big.Int).Mul(c, d),
-			new(big.Int).Mul(_x, numTokensBI),
-		)
-	}
-	if nA.Cmp(bignumber.ZeroBI) == 0 {
-		return nil, ErrZero
-	}
-	c = new(big.Int).Div(
-		new(big.Int).Mul(new(big.Int).Mul(c, d), t.APrecision),
-		new(big.Int).Mul(nA, numTokensBI),
-	)
-	var b = new(big.Int).Add(
-		s,
-		new(big.Int).Div(new(big.Int).Mul(d, t.APrecision), nA),
-	)
-	var yPrev *big.Int
-	var y = new(big.Int).Set(d)
-	var denom big.Int
-	for range MaxLoopLimit {
-		yPrev = new(big.Int).Set(y)
-		denom.Sub(denom.Add(denom.Mul(y, big.NewInt(2)), b), d)
-		if denom.Sign() <= 0 {
-			return nil, ErrDenominatorZero
-		}
-		y = new(big.Int).Div(new(big.Int).Add(new(big.Int).Mul(y, y), c), &denom)
-		if new(big.Int).Sub(y, yPrev).CmpAbs(bignumber.One) <= 0 {
-			return y, nil
-		}
+
+	var xpU = make([]uint256.Int, len(xp))
+	var xU, amp, aPrecision, dU, y uint256.Int
+	if big256.FromBigs(xpU, xp) || xU.SetFromBig(x) || amp.SetFromBig(a) ||
+		aPrecision.SetFromBig(t.APrecision) || d != nil && dU.SetFromBig(d) {
+		return nil, ErrOverflow
+	}
+	if err := stableswap.GetY(tokenIndexFrom, tokenIndexTo, &xU, xpU, &amp, &aPrecision, &dU, &y); err != nil {
+		return nil, err
 	}
-	return nil, ErrAmountOutNotConverge
+	return y.ToBig(), nil
 }
 
 func (t *PoolSimulator) GetDy(
@@ -232,49 +140,18 @@ func (t *PoolSimulator) getYD(
 	xp []*big.Int,
 	d *big.Int,
 ) (*big.Int, error) {
-	var numTokens = len(xp)
-	if tokenIndex >= numTokens {
+	if tokenIndex >= len(xp) {
 		return nil, ErrTokenNotFound
 	}
-	var numTokensBI = big.NewInt(int64(numTokens))
-	var c = new(big.Int).Set(d)
-	var s = big.NewInt(0)
-	var nA = new(big.Int).Mul(a, numTokensBI)
-	for i := range numTokens {
-		if i != tokenIndex {
-			s = new(big.Int).Add(s, xp[i])
-			c = new(big.Int).Div(
-				new(big.Int).Mul(c, d),
-				new(big.Int).Mul(xp[i], numTokensBI),
-			)
-		}
+	var xpU = make([]uint256.Int, len(xp))
+	var amp, aPrecision, dU, y uint256.Int
+	if big256.FromBigs(xpU, xp) || amp.SetFromBig(a) || aPrecision.SetFromBig(t.APrecision) || dU.SetFromBig(d) {
+		return nil, ErrOverflow
 	}
-	if nA.Cmp(bignumber.ZeroBI) == 0 {
-		return nil, ErrZero
-	}
-	c = new(big.Int).Div(
-		new(big.Int).Mul(new(big.Int).Mul(c, d), t.APrecision),
-		new(big.Int).Mul(nA, numTokensBI),
-	)
-	var b = new(big.Int).Add(
-		s,
-		new(big.Int).Div(new(big.Int).Mul(d, t.APrecision), nA),
-	)
-	var yPrev *big.Int
-	var y = new(big.Int).Set(d)
-	var denom big.Int
-	for range MaxLoopLimit {
-		yPrev = new(big.Int).Set(y)
-		denom.Sub(denom.Add(denom.Mul(y, bignumber.Two), b), d)
-		if denom.Sign() <= 0 {
-			return nil, ErrDenominatorZero
-		}
-		y = new(big.Int).Div(new(big.Int).Add(new(big.Int).Mul(y, y), c), &denom)
-		if new(big.Int).Sub(y, yPrev).CmpAbs(bignumber.One) <= 0 {
-			return y, nil
-		}
+	if err := stableswap.GetYD(&amp, &aPrecision, tokenIndex, xpU, &dU, &y); err != nil {
+		return nil, err
 	}
-	return nil, ErrAmountOutNotConverge
+	return y.ToBig(), nil
 }
 
 func (t *PoolSimulator) CalculateWithdrawOneCoin(
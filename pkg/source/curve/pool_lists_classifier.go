@@ -27,6 +27,13 @@ func (d *PoolsListUpdater) classifyPoolTypes(
 		return d.classifyCurveV2PoolTypes(ctx, registryOrFactoryABI, registryOrFactoryAddress, poolAddresses)
 	case sourceCryptoPoolsFactory:
 		return d.classifyCurveV2PoolTypes(ctx, registryOrFactoryABI, registryOrFactoryAddress, poolAddresses)
+	case sourceMetaRegistry:
+		// the meta registry exposes the main registry getters for every pool it indexes
+		poolTypes, err := d.classifyPoolsFromMainRegistry(ctx, registryOrFactoryABI, registryOrFactoryAddress, poolAddresses)
+		if err != nil {
+			return nil, err
+		}
+		return d.skipNgPools(ctx, poolAddresses, poolTypes)
 	default:
 		// Index can be found here https://github.com/KyberNetwork/kyberswap-dex-lib/blob/0e4796ffde08481ef8b456e354cf2cb7b3aa8268/pkg/source/curve/pools_list_updater.go#L69-L79
 		logger.Errorf("unknown pools source index %v", poolsSourceIndex)
@@ -215,6 +222,45 @@ func (d *PoolsListUpdater) classifyCurveV2PoolTypes(
 	return poolTypes, nil
 }
 
+// skipNgPools marks the ng pools (stable-ng, twocrypto-ng and tricrypto-ng) as unsupported, they are tracked by
+// their own liquidity sources. They are told apart by the getters only they implement.
+func (d *PoolsListUpdater) skipNgPools(
+	ctx context.Context,
+	poolAddresses []common.Address,
+	poolTypes []string,
+) ([]string, error) {
+	var dMaTimes = make([]*big.Int, len(poolAddresses))
+	var packedRebalancingParams = make([]*big.Int, len(poolAddresses))
+
+	calls := d.ethrpcClient.NewRequest().SetContext(ctx).SetRequireSuccess(false)
+	for i := range poolAddresses {
+		calls.AddCall(&ethrpc.Call{
+			ABI:    ngABI,
+			Target: poolAddresses[i].Hex(),
+			Method: ngMethodDMaTime,
+		}, []any{&dMaTimes[i]}).AddCall(&ethrpc.Call{
+			ABI:    ngABI,
+			Target: poolAddresses[i].Hex(),
+			Method: ngMethodPackedRebalancingParams,
+		}, []any{&packedRebalancingParams[i]})
+	}
+	if _, err := calls.TryAggregate(); err != nil {
+		logger.WithFields(logger.Fields{
+			"error": err,
+		}).Errorf("failed to aggregate to detect ng pools")
+		return nil, err
+	}
+
+	for i := range poolAddresses {
+		if dMaTimes[i] != nil || packedRebalancingParams[i] != nil {
+			logger.Infof("skip curve ng pool: %s", poolAddresses[i].Hex())
+			poolTypes[i] = PoolTypeUnsupported
+		}
+	}
+
+	return poolTypes, nil
+}
+
 // isPlainOraclePool PlainOraclePool should
 // be a BasePool but having method "oracle" in its contract
 func (d *PoolsListUpdater) isPlainOraclePool(oracleAddress common.Address) bool {
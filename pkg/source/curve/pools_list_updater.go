@@ -3,7 +3,9 @@ package curve
 import (
 	"context"
 	"errors"
+	"maps"
 	"math/big"
+	"slices"
 	"strings"
 
 	"github.com/KyberNetwork/ethrpc"
@@ -21,6 +23,7 @@ import (
 // 2. meta pool factory
 // 3. crypto pools registry
 // 4. crypto pools factory
+// 5. meta registry
 type PoolsSource struct {
 	ABI     abi.ABI
 	Address string
@@ -69,60 +72,85 @@ func (d *PoolsListUpdater) GetNewPools(ctx context.Context, metadataBytes []byte
 	}
 
 	var (
+		pools       []entity.Pool
 		poolTypeMap = make(map[string][]PoolAndRegistries)
+		// the meta registry also lists the pools of the other sources and of PoolPath, including those of past runs
+		seenPools = make(map[string]struct{}, len(metadata.SeenPools))
 
 		// registryOrFactoryList is a list of sources to get new pools
 		// 1. main registry
 		// 2. meta pool factory
 		// 3. crypto pools registry
 		// 4. crypto pools factory
+		// 5. meta registry, which also indexes the legacy pools missing from the others; its ng pools are skipped
 		// At the moment, we MUST keep the order of the sources like this
 		registryOrFactoryList = []PoolsSource{
 			{mainRegistryABI, d.config.MainRegistryAddress, metadata.MainRegistryOffset},
 			{metaPoolFactoryABI, d.config.MetaPoolsFactoryAddress, metadata.MetaFactoryOffset},
 			{cryptoRegistryABI, d.config.CryptoPoolsRegistryAddress, metadata.CryptoRegistryOffset},
 			{cryptoFactoryABI, d.config.CryptoPoolsFactoryAddress, metadata.CryptoFactoryOffset},
+			{mainRegistryABI, d.config.MetaRegistryAddress, metadata.MetaRegistryOffset},
 		}
 	)
+	for _, poolAddress := range metadata.SeenPools {
+		seenPools[poolAddress] = struct{}{}
+	}
 
-	if !d.config.SkipInitFactory {
-		for i := range registryOrFactoryList {
-			if strings.EqualFold(registryOrFactoryList[i].Address, addressZero) {
-				logger.Debugf("skip zero factory %v", i)
-				continue
-			}
+	if !d.hasInitialized {
+		newPools, err := d.initPool()
+		if err != nil {
+			logger.WithFields(logger.Fields{
+				"error": err,
+			}).Errorf("failed to init new pool from file")
+			return nil, nil, err
+		}
+		for _, pool := range newPools {
+			seenPools[strings.ToLower(pool.Address)] = struct{}{}
+		}
+		pools = append(pools, newPools...)
+		d.hasInitialized = true
+	}
 
-			poolAddresses, poolTypes, nextOffset, err := d.getNewPoolAddressesFromSource(
-				ctx,
-				i,
-				registryOrFactoryList[i].ABI,
-				registryOrFactoryList[i].Address,
-				registryOrFactoryList[i].Offset,
-				d.config.NewPoolLimit,
-			)
-			if err != nil {
-				logger.Errorf("failed to get new pool addresses from the registry or factory, address: %v, offset: %v, err: %v", registryOrFactoryList[i].Address, registryOrFactoryList[i].Offset, err)
-				return nil, nil, err
-			}
+	for i := range registryOrFactoryList {
+		if registryOrFactoryList[i].Address == "" || strings.EqualFold(registryOrFactoryList[i].Address, addressZero) {
+			logger.Debugf("skip zero factory %v", i)
+			continue
+		}
 
-			for j := range poolAddresses {
-				// Skip unsupported pools
-				if poolTypes[j] == PoolTypeUnsupported {
-					continue
-				}
+		poolAddresses, poolTypes, nextOffset, err := d.getNewPoolAddressesFromSource(
+			ctx,
+			i,
+			registryOrFactoryList[i].ABI,
+			registryOrFactoryList[i].Address,
+			registryOrFactoryList[i].Offset,
+			d.config.NewPoolLimit,
+		)
+		if err != nil {
+			logger.Errorf("failed to get new pool addresses from the registry or factory, address: %v, offset: %v, err: %v", registryOrFactoryList[i].Address, registryOrFactoryList[i].Offset, err)
+			return nil, nil, err
+		}
 
-				poolTypeMap[poolTypes[j]] = append(poolTypeMap[poolTypes[j]], PoolAndRegistries{
-					PoolAddress:              poolAddresses[j],
-					RegistryOrFactoryABI:     registryOrFactoryList[i].ABI,
-					RegistryOrFactoryAddress: registryOrFactoryList[i].Address,
-				})
+		for j := range poolAddresses {
+			// Skip unsupported pools
+			if poolTypes[j] == PoolTypeUnsupported {
+				continue
+			}
+			poolAddress := strings.ToLower(poolAddresses[j].Hex())
+			if _, ok := seenPools[poolAddress]; ok {
+				continue
 			}
+			seenPools[poolAddress] = struct{}{}
 
-			registryOrFactoryList[i].Offset = nextOffset
+			poolTypeMap[poolTypes[j]] = append(poolTypeMap[poolTypes[j]], PoolAndRegistries{
+				PoolAddress:              poolAddresses[j],
+				RegistryOrFactoryABI:     registryOrFactoryList[i].ABI,
+				RegistryOrFactoryAddress: registryOrFactoryList[i].Address,
+			})
 		}
+
+		registryOrFactoryList[i].Offset = nextOffset
 	}
 
-	var pools []entity.Pool
 	for poolType, poolAndRegistries := range poolTypeMap {
 		var newPools []entity.Pool
 		var err error
@@ -154,23 +182,13 @@ func (d *PoolsListUpdater) GetNewPools(ctx context.Context, metadataBytes []byte
 		logger.Infof("got total of %v %s pools of %v types from registry and factory", len(newPools), d.config.DexID, poolType)
 	}
 
-	if !d.hasInitialized {
-		newPools, err := d.initPool()
-		if err != nil {
-			logger.WithFields(logger.Fields{
-				"error": err,
-			}).Errorf("failed to init new pool from file")
-			return nil, nil, err
-		}
-		pools = append(pools, newPools...)
-		d.hasInitialized = true
-	}
-
 	newMetaDataBytes, err := json.Marshal(Metadata{
 		MainRegistryOffset:   registryOrFactoryList[0].Offset,
 		MetaFactoryOffset:    registryOrFactoryList[1].Offset,
 		CryptoRegistryOffset: registryOrFactoryList[2].Offset,
 		CryptoFactoryOffset:  registryOrFactoryList[3].Offset,
+		MetaRegistryOffset:   registryOrFactoryList[4].Offset,
+		SeenPools:            slices.Sorted(maps.Keys(seenPools)),
 	})
 	if err != nil {
 		logger.WithFields(logger.Fields{
@@ -329,6 +347,7 @@ func (d *PoolsListUpdater) initPool() ([]entity.Pool, error) {
 // 2. meta pool factory
 // 3. crypto pools registry
 // 4. crypto pools factory
+// 5. meta registry
 func (d *PoolsListUpdater) getNewPoolAddressesFromSource(
 	ctx context.Context,
 	poolsSourceIndex int,
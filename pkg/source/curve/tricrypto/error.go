@@ -4,4 +4,5 @@ import "errors"
 
 var (
 	ErrDenominatorZero = errors.New("denominator should not be 0")
+	ErrOverflow        = errors.New("uint256 overflow")
 )
@@ -3,6 +3,8 @@ package two
 import (
 	"math/big"
 
+	"github.com/holiman/uint256"
+
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 )
 
@@ -16,4 +18,9 @@ var (
 	Precision        = bignumber.BONE
 	PriceMask        = new(big.Int).Sub(new(big.Int).Lsh(bignumber.One, 128), bignumber.One)
 	PriceSize   uint = 128
+
+	// bounds newton_D checks the sorted balances against
+	minX0     = uint256.NewInt(1e9)
+	maxX0     = uint256.MustFromDecimal("1000000000000000000000000000000000")
+	minXiFrac = uint256.NewInt(1e11)
 )
@@ -6,199 +6,86 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/KyberNetwork/blockchain-toolkit/number"
+	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/curve/math/cryptoswap"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 )
 
-func sortArray(A0 []*big.Int) []*big.Int {
-	var nCoins = len(A0)
-	var ret = make([]*big.Int, nCoins)
-	for i := range nCoins {
-		ret[i] = A0[i]
-	}
-	for i := 1; i < nCoins; i += 1 {
-		var x = ret[i]
-		var cur = i
-		for range nCoins {
-			var y = ret[cur-1]
-			if y.Cmp(x) > 0 {
-				break
-			}
-			ret[cur] = y
-			cur -= 1
-			if cur == 0 {
-				break
-			}
-		}
-		ret[cur] = x
+// toU256 converts xs for the cryptoswap math.
+func toU256(xs []*big.Int) ([]uint256.Int, error) {
+	x := make([]uint256.Int, len(xs))
+	if big256.FromBigs(x, xs) {
+		return nil, ErrOverflow
 	}
-	return ret
+	return x, nil
 }
 
 func geometricMean(unsortedX []*big.Int, sort bool) (*big.Int, error) {
-	var nCoins = len(unsortedX)
-	var nCoinsBi = big.NewInt(int64(nCoins))
-	var x = unsortedX
+	x, err := toU256(unsortedX)
+	if err != nil {
+		return nil, err
+	}
 	if sort {
-		x = sortArray(unsortedX)
-	}
-	var D = x[0]
-	var diff = bignumber.ZeroBI
-	for range 255 {
-		var DPrev = D
-		var tmp = bignumber.BONE
-		for _, _x := range x {
-			tmp = new(big.Int).Div(new(big.Int).Mul(tmp, _x), D)
-		}
-		D = new(big.Int).Div(
-			new(big.Int).Mul(
-				D, new(big.Int).Add(new(big.Int).Mul(big.NewInt(int64(nCoins-1)), bignumber.BONE), tmp),
-			), new(big.Int).Mul(nCoinsBi, bignumber.BONE),
-		)
-		if D.Cmp(DPrev) > 0 {
-			diff = new(big.Int).Sub(D, DPrev)
-		} else {
-			diff = new(big.Int).Sub(DPrev, D)
-		}
-		if diff.Cmp(bignumber.One) <= 0 || new(big.Int).Mul(diff, bignumber.BONE).Cmp(D) < 0 {
-			return D, nil
-		}
+		cryptoswap.Sort(x)
 	}
-	return nil, errors.New("did not converge")
+	var D uint256.Int
+	if err = cryptoswap.IterativeGeometricMean(x, &D); err != nil {
+		return nil, err
+	}
+	return D.ToBig(), nil
 }
 
 func sqrtInt(x *big.Int) (*big.Int, error) {
-	if x.Cmp(bignumber.ZeroBI) == 0 {
-		return bignumber.ZeroBI, nil
-	}
-	var z = new(big.Int).Div(new(big.Int).Add(x, bignumber.BONE), bignumber.Two)
-	var y = x
-	for range 256 {
-		if z.Cmp(y) == 0 {
-			return y, nil
-		}
-		y = z
-		z = new(big.Int).Div(new(big.Int).Add(new(big.Int).Div(new(big.Int).Mul(x, bignumber.BONE), z), z), bignumber.Two)
+	var xU, result uint256.Int
+	if xU.SetFromBig(x) {
+		return nil, ErrOverflow
 	}
-	return nil, errors.New("sqrt_int did not converge")
+	if err := cryptoswap.SqrtInt(&xU, &result); err != nil {
+		return nil, err
+	}
+	return result.ToBig(), nil
 }
 
 func newtonD(ANN *big.Int, gamma *big.Int, xUnsorted []*big.Int) (*big.Int, error) {
 	if gamma.Cmp(MinGamma) < 0 || gamma.Cmp(MaxGamma) > 0 {
 		return nil, errors.New("unsafe values gamma")
 	}
-	var nCoins = len(xUnsorted)
-	var nCoinsBi = big.NewInt(int64(nCoins))
-	var x = sortArray(xUnsorted)
-	if x[0].Cmp(bignumber.TenPowInt(9)) < 0 || x[0].Cmp(bignumber.TenPowInt(33)) > 0 {
+	x, err := toU256(xUnsorted)
+	if err != nil {
+		return nil, err
+	}
+	var params [2]uint256.Int
+	if big256.FromBigs(params[:], []*big.Int{ANN, gamma}) {
+		return nil, ErrOverflow
+	}
+	cryptoswap.Sort(x)
+	if x[0].Lt(minX0) || x[0].Gt(maxX0) {
 		return nil, errors.New("unsafe values x[0]")
 	}
-	for i := 1; i < nCoins; i += 1 {
-		var frac = new(big.Int).Div(new(big.Int).Mul(x[i], bignumber.BONE), x[0])
-		if frac.Cmp(bignumber.TenPowInt(11)) < 0 {
-			return nil, errors.New("unsafe values x[i]")
+	for i := 1; i < len(x); i++ {
+		if number.Div(number.Mul(&x[i], cryptoswap.U_1e18), &x[0]).Lt(minXiFrac) {
+			return nil, cryptoswap.ErrUnsafeXi
 		}
 	}
-	var mean, err = geometricMean(x, false)
-	if err != nil {
+
+	var D uint256.Int
+	if err = cryptoswap.IterativeGeometricMean(x, &D); err != nil {
 		return nil, err
 	}
-	var D = new(big.Int).Mul(nCoinsBi, mean)
-	var S = bignumber.ZeroBI
-	for _, xI := range x {
-		S = new(big.Int).Add(S, xI)
+	D.Mul(&D, uint256.NewInt(uint64(len(x))))
+	if err = cryptoswap.NewtonD(&params[0], &params[1], x, &D); err != nil {
+		return nil, err
 	}
-	for range 255 {
-		var DPrev = D
-		var K0 = bignumber.BONE
-		for _, _x := range x {
-			K0 = new(big.Int).Div(new(big.Int).Mul(new(big.Int).Mul(K0, _x), nCoinsBi), D)
-		}
-		var _g1k0 = new(big.Int).Add(gamma, bignumber.BONE)
-		if _g1k0.Cmp(K0) > 0 {
-			_g1k0 = new(big.Int).Add(new(big.Int).Sub(_g1k0, K0), bignumber.One)
-		} else {
-			_g1k0 = new(big.Int).Add(new(big.Int).Sub(K0, _g1k0), bignumber.One)
-		}
-		var mul1 = new(big.Int).Div(
-			new(big.Int).Mul(
-				new(big.Int).Mul(
-					new(big.Int).Div(
-						new(big.Int).Mul(
-							new(big.Int).Div(new(big.Int).Mul(bignumber.BONE, D), gamma), _g1k0,
-						), gamma,
-					),
-					_g1k0,
-				),
-				AMultiplier,
-			), ANN,
-		)
-		var mul2 = new(big.Int).Div(
-			new(big.Int).Mul(
-				new(big.Int).Mul(
-					new(big.Int).Mul(bignumber.Two, bignumber.BONE), nCoinsBi,
-				), K0,
-			), _g1k0,
-		)
-		var negFprime = new(big.Int).Sub(
-			new(big.Int).Add(
-				new(big.Int).Add(S, new(big.Int).Div(new(big.Int).Mul(S, mul2), bignumber.BONE)),
-				new(big.Int).Div(new(big.Int).Mul(mul1, nCoinsBi), K0),
-			),
-			new(big.Int).Div(new(big.Int).Mul(mul2, D), bignumber.BONE),
-		)
-		var DPlus = new(big.Int).Div(new(big.Int).Mul(D, new(big.Int).Add(negFprime, S)), negFprime)
-		var DMinus = new(big.Int).Div(new(big.Int).Mul(D, D), negFprime)
-		if bignumber.BONE.Cmp(K0) > 0 {
-			DMinus = new(big.Int).Add(
-				DMinus,
-				new(big.Int).Div(
-					new(big.Int).Mul(
-						new(big.Int).Div(
-							new(big.Int).Mul(D, new(big.Int).Div(mul1, negFprime)), bignumber.BONE,
-						), new(big.Int).Sub(bignumber.BONE, K0),
-					),
-					K0,
-				),
-			)
-		} else {
-			DMinus = new(big.Int).Sub(
-				DMinus,
-				new(big.Int).Div(
-					new(big.Int).Mul(
-						new(big.Int).Div(
-							new(big.Int).Mul(D, new(big.Int).Div(mul1, negFprime)), bignumber.BONE,
-						), new(big.Int).Sub(K0, bignumber.BONE),
-					),
-					K0,
-				),
-			)
-		}
-		if DPlus.Cmp(DMinus) > 0 {
-			D = new(big.Int).Sub(DPlus, DMinus)
-		} else {
-			D = new(big.Int).Div(new(big.Int).Sub(DMinus, DPlus), bignumber.Two)
-		}
-		var diff *big.Int
-		if D.Cmp(DPrev) > 0 {
-			diff = new(big.Int).Sub(D, DPrev)
-		} else {
-			diff = new(big.Int).Sub(DPrev, D)
-		}
-		var temp = bignumber.TenPowInt(16)
-		if D.Cmp(temp) > 0 {
-			temp = D
-		}
-		if new(big.Int).Mul(diff, bignumber.TenPowInt(14)).Cmp(temp) < 0 {
-			for _, _x := range x {
-				var frac = new(big.Int).Div(new(big.Int).Mul(_x, bignumber.BONE), D)
-				if frac.Cmp(bignumber.TenPowInt(16)) < 0 || frac.Cmp(bignumber.TenPowInt(20)) > 0 {
-					return nil, errors.New("unsafe values x[i]")
-				}
-			}
-			return D, nil
+	for i := range x {
+		frac := number.Div(number.Mul(&x[i], cryptoswap.U_1e18), &D)
+		if frac.Lt(cryptoswap.MinFrac) || frac.Gt(cryptoswap.MaxFrac) {
+			return nil, cryptoswap.ErrUnsafeXi
 		}
 	}
-	return nil, errors.New("did not converge")
+	return D.ToBig(), nil
 }
 
 func newtonY(ann *big.Int, gamma *big.Int, x []*big.Int, D *big.Int, i int) (*big.Int, error) {
@@ -218,175 +105,48 @@ func newtonY(ann *big.Int, gamma *big.Int, x []*big.Int, D *big.Int, i int) (*bi
 		return nil, errors.New("unsafe values D")
 	}
 
-	var nCoins = len(x)
-	var nCoinBi = big.NewInt(int64(nCoins))
-	var y = new(big.Int).Div(D, nCoinBi)
-	var K0i = bignumber.BONE
-	var Si = bignumber.ZeroBI
-
-	var xSorted = make([]*big.Int, nCoins)
-	for j := range nCoins {
-		xSorted[j] = x[j]
-	}
-	xSorted[i] = bignumber.ZeroBI
-	xSorted = sortArray(xSorted)
-	var tenPow14 = bignumber.TenPowInt(14)
-	var convergenceLimit = new(big.Int).Div(xSorted[0], tenPow14)
-	var temp = new(big.Int).Div(D, tenPow14)
-	if temp.Cmp(convergenceLimit) > 0 {
-		convergenceLimit = temp
+	xU, err := toU256(x)
+	if err != nil {
+		return nil, err
 	}
-	if big.NewInt(100).Cmp(convergenceLimit) > 0 {
-		convergenceLimit = big.NewInt(100)
+	var params [3]uint256.Int
+	if big256.FromBigs(params[:], []*big.Int{ann, gamma, D}) {
+		return nil, ErrOverflow
 	}
-
-	for j := 2; j < nCoins+1; j += 1 {
-		var _x = xSorted[nCoins-j]
-		if _x.Cmp(bignumber.ZeroBI) == 0 {
+	var y uint256.Int
+	if err = cryptoswap.NewtonY(&params[0], &params[1], xU, &params[2], i, &y); err != nil {
+		if errors.Is(err, cryptoswap.ErrZero) {
 			return nil, ErrDenominatorZero
 		}
-		y = new(big.Int).Div(new(big.Int).Mul(y, D), new(big.Int).Mul(_x, nCoinBi))
-		Si = new(big.Int).Add(Si, _x)
-	}
-	for j := 0; j < nCoins-1; j += 1 {
-		K0i = new(big.Int).Div(new(big.Int).Mul(new(big.Int).Mul(K0i, xSorted[j]), nCoinBi), D)
-	}
-	for range 255 {
-		var yPrev = y
-		var K0 = new(big.Int).Div(new(big.Int).Mul(new(big.Int).Mul(K0i, y), nCoinBi), D)
-		var S = new(big.Int).Add(Si, y)
-		var _g1k0 = new(big.Int).Add(gamma, bignumber.BONE)
-		if _g1k0.Cmp(K0) > 0 {
-			_g1k0 = new(big.Int).Add(new(big.Int).Sub(_g1k0, K0), bignumber.One)
-		} else {
-			_g1k0 = new(big.Int).Add(new(big.Int).Sub(K0, _g1k0), bignumber.One)
-		}
-		var mul1 = new(big.Int).Div(
-			new(big.Int).Mul(
-				new(big.Int).Div(
-					new(big.Int).Mul(
-						new(big.Int).Div(new(big.Int).Mul(bignumber.BONE, D), gamma),
-						_g1k0,
-					), gamma,
-				),
-				new(big.Int).Mul(_g1k0, AMultiplier),
-			), ann,
-		)
-		var mul2 = new(big.Int).Add(
-			new(big.Int).Div(
-				new(big.Int).Mul(
-					new(big.Int).Mul(bignumber.Two, bignumber.BONE), K0,
-				), _g1k0,
-			), bignumber.BONE,
-		)
-		var yfprime = new(big.Int).Add(
-			new(big.Int).Add(new(big.Int).Mul(bignumber.BONE, y), new(big.Int).Mul(S, mul2)), mul1,
-		)
-		var _dyfprime = new(big.Int).Mul(D, mul2)
-		if yfprime.Cmp(_dyfprime) < 0 {
-			y = new(big.Int).Div(yPrev, bignumber.Two)
-			continue
-		} else {
-			yfprime = new(big.Int).Sub(yfprime, _dyfprime)
-		}
-
-		if y.Cmp(bignumber.ZeroBI) == 0 {
-			return nil, ErrDenominatorZero
-		}
-
-		var fprime = new(big.Int).Div(yfprime, y)
-
-		if fprime.Cmp(bignumber.ZeroBI) == 0 {
-			return nil, ErrDenominatorZero
-		}
-
-		var yMinus = new(big.Int).Div(mul1, fprime)
-		var yPlus = new(big.Int).Add(
-			new(big.Int).Div(
-				new(big.Int).Add(yfprime, new(big.Int).Mul(bignumber.BONE, D)),
-				fprime,
-			),
-			new(big.Int).Div(new(big.Int).Mul(yMinus, bignumber.BONE), K0),
-		)
-		yMinus = new(big.Int).Add(yMinus, new(big.Int).Div(new(big.Int).Mul(bignumber.BONE, S), fprime))
-		if yPlus.Cmp(yMinus) < 0 {
-			y = new(big.Int).Div(yPrev, bignumber.Two)
-		} else {
-			y = new(big.Int).Sub(yPlus, yMinus)
-		}
-		var diff = bignumber.ZeroBI
-		if y.Cmp(yPrev) > 0 {
-			diff = new(big.Int).Sub(y, yPrev)
-		} else {
-			diff = new(big.Int).Sub(yPrev, y)
-		}
-		var t = new(big.Int).Div(y, tenPow14)
-		if convergenceLimit.Cmp(t) > 0 {
-			t = convergenceLimit
-		}
-		if diff.Cmp(t) < 0 {
-			var frac = new(big.Int).Div(new(big.Int).Mul(y, bignumber.BONE), D)
-			if frac.Cmp(bignumber.TenPowInt(16)) < 0 || frac.Cmp(bignumber.TenPowInt(20)) > 0 {
-				return nil, errors.New("unsafe value for y")
-			}
-			return y, nil
-		}
+		return nil, err
 	}
-	return nil, errors.New("did not converge")
+	return y.ToBig(), nil
 }
 
-func reductionCoefficient(x []*big.Int, feeGamma *big.Int) *big.Int {
-	var nCoinsBi = big.NewInt(int64(len(x)))
-	var K = bignumber.BONE
-	var S = bignumber.ZeroBI
-	for _, xi := range x {
-		S = new(big.Int).Add(S, xi)
+func reductionCoefficient(x []*big.Int, feeGamma *big.Int) (*big.Int, error) {
+	xU, err := toU256(x)
+	if err != nil {
+		return nil, err
 	}
-	for _, xi := range x {
-		K = new(big.Int).Div(new(big.Int).Mul(new(big.Int).Mul(K, nCoinsBi), xi), S)
+	var feeGammaU, K uint256.Int
+	if feeGammaU.SetFromBig(feeGamma) {
+		return nil, ErrOverflow
 	}
-	if feeGamma.Cmp(bignumber.ZeroBI) > 0 {
-		K = new(big.Int).Div(
-			new(big.Int).Mul(feeGamma, bignumber.BONE), new(big.Int).Sub(new(big.Int).Add(feeGamma, bignumber.BONE), K),
-		)
+	if err = cryptoswap.ReductionCoefficient(xU, &feeGammaU, &K); err != nil {
+		return nil, err
 	}
-	return K
+	return K.ToBig(), nil
 }
 
 func halfpow(power *big.Int, precision *big.Int) (*big.Int, error) {
-	var intpow = new(big.Int).Div(power, bignumber.BONE)
-	var otherpow = new(big.Int).Sub(power, new(big.Int).Mul(intpow, bignumber.BONE))
-	if intpow.Cmp(big.NewInt(59)) > 0 {
-		return bignumber.ZeroBI, nil
-	}
-	var result = new(big.Int).Div(bignumber.BONE, new(big.Int).Exp(bignumber.Two, intpow, nil))
-	if otherpow.Cmp(bignumber.ZeroBI) == 0 {
-		return result, nil
-	}
-	var term = bignumber.BONE
-	var x = new(big.Int).Mul(bignumber.Five, bignumber.TenPowInt(17))
-	var S = bignumber.BONE
-	var neg = false
-	for i := 1; i < 256; i += 1 {
-		var K = new(big.Int).Mul(big.NewInt(int64(i)), bignumber.BONE)
-		var c = new(big.Int).Sub(K, bignumber.BONE)
-		if otherpow.Cmp(c) > 0 {
-			c = new(big.Int).Sub(otherpow, c)
-			neg = !neg
-		} else {
-			c = new(big.Int).Sub(c, otherpow)
-		}
-		term = new(big.Int).Div(new(big.Int).Mul(term, new(big.Int).Div(new(big.Int).Mul(c, x), bignumber.BONE)), K)
-		if neg {
-			S = new(big.Int).Sub(S, term)
-		} else {
-			S = new(big.Int).Add(S, term)
-		}
-		if term.Cmp(precision) < 0 {
-			return new(big.Int).Div(new(big.Int).Mul(result, S), bignumber.BONE), nil
-		}
+	var powerU, precisionU, result uint256.Int
+	if powerU.SetFromBig(power) || precisionU.SetFromBig(precision) {
+		return nil, ErrOverflow
+	}
+	if err := cryptoswap.HalfPow(&powerU, &precisionU, &result); err != nil {
+		return nil, err
 	}
-	return nil, errors.New("did not converge")
+	return result.ToBig(), nil
 }
 
 // func (t *PoolSimulator) packedView(k uint, p *big.Int) *big.Int {
@@ -434,14 +194,17 @@ func (t *PoolSimulator) aGamma() []*big.Int {
 	}
 }
 
-func (t *PoolSimulator) FeeCalc(xp []*big.Int) *big.Int {
-	var f = reductionCoefficient(xp, t.FeeGamma)
+func (t *PoolSimulator) FeeCalc(xp []*big.Int) (*big.Int, error) {
+	var f, err = reductionCoefficient(xp, t.FeeGamma)
+	if err != nil {
+		return nil, err
+	}
 	var ret = new(big.Int).Div(
 		new(big.Int).Add(
 			new(big.Int).Mul(t.MidFee, f), new(big.Int).Mul(t.OutFee, new(big.Int).Sub(bignumber.BONE, f)),
 		), bignumber.BONE,
 	)
-	return ret
+	return ret, nil
 }
 
 // GetDy https://github.com/curvefi/curve-crypto-contract/blob/d7d04cd9ae038970e40be850df99de8c1ff7241b/contracts/two/CurveCryptoSwap2.vy#L842
@@ -474,7 +237,10 @@ func (t *PoolSimulator) GetDy(i int, j int, dx *big.Int) (*big.Int, *big.Int, er
 		dy = new(big.Int).Div(dy, t.Precisions[0]) // dy /= precisions[0]
 	}
 	// dy -= self._fee(xp) * dy / 10**10
-	var fee = t.FeeCalc(xp)
+	fee, err := t.FeeCalc(xp)
+	if err != nil {
+		return nil, nil, err
+	}
 	dy = new(big.Int).Sub(dy, new(big.Int).Div(new(big.Int).Mul(fee, dy), bignumber.TenPowInt(10)))
 
 	return dy, fee, nil
@@ -549,7 +315,11 @@ func (t *PoolSimulator) Exchange(i int, j int, dx *big.Int) (*big.Int, error) {
 		dy = new(big.Int).Div(new(big.Int).Mul(dy, Precision), priceScale[j-1])
 	}
 	dy = new(big.Int).Div(dy, t.Precisions[j])
-	dy = new(big.Int).Sub(dy, new(big.Int).Div(new(big.Int).Mul(t.FeeCalc(xp), dy), bignumber.TenPowInt(10)))
+	fee, err := t.FeeCalc(xp)
+	if err != nil {
+		return nil, err
+	}
+	dy = new(big.Int).Sub(dy, new(big.Int).Div(new(big.Int).Mul(fee, dy), bignumber.TenPowInt(10)))
 	// assert dy >= min_dy, "Slippage"
 	y = new(big.Int).Sub(y, dy)
 	t.Info.Reserves[j] = y
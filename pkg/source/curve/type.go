@@ -19,6 +19,9 @@ type Metadata struct {
 	MetaFactoryOffset    int `json:"metaFactoryOffset"`
 	CryptoRegistryOffset int `json:"cryptoRegistryOffset"`
 	CryptoFactoryOffset  int `json:"cryptoFactoryOffset"`
+	MetaRegistryOffset   int `json:"metaRegistryOffset"`
+	// SeenPools are the pools already emitted, so that the meta registry does not emit them again
+	SeenPools []string `json:"seenPools,omitempty"`
 }
 
 type PoolToken struct {
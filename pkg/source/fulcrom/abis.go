@@ -9,7 +9,6 @@ import (
 var (
 	vaultABI          abi.ABI
 	vaultPriceFeedABI abi.ABI
-	erc20ABI          abi.ABI
 )
 
 func init() {
@@ -19,7 +18,6 @@ func init() {
 	}{
 		{&vaultABI, vaultJson},
 		{&vaultPriceFeedABI, vaultPriceFeedJson},
-		{&erc20ABI, erc20Json},
 	}
 
 	for _, b := range builder {
@@ -1,13 +1,29 @@
 package fulcrom
 
-import "math/big"
+const (
+	DexTypeFulcrom = "fulcrom"
 
-const DexTypeFulcrom = "fulcrom"
+	vaultMethodHasDynamicFees           = "hasDynamicFees"
+	vaultMethodIsSwapEnabled            = "isSwapEnabled"
+	vaultMethodPriceFeed                = "priceFeed"
+	vaultMethodStableSwapFeeBasisPoints = "stableSwapFeeBasisPoints"
+	vaultMethodStableTaxBasisPoints     = "stableTaxBasisPoints"
+	vaultMethodSwapFeeBasisPoints       = "swapFeeBasisPoints"
+	vaultMethodTaxBasisPoints           = "taxBasisPoints"
+	vaultMethodTotalTokenWeights        = "totalTokenWeights"
+	vaultMethodUSDG                     = "usdg"
+	vaultMethodWhitelistedTokenCount    = "whitelistedTokenCount"
 
-var (
-	DefaultGas         = Gas{Swap: 286524}
-	BasisPointsDivisor = big.NewInt(10000)
-	PricePrecision     = new(big.Int).Exp(big.NewInt(10), big.NewInt(30), nil)
-	USDGDecimals       = big.NewInt(18)
-	OneUSD             = PricePrecision
+	vaultMethodWhitelistedTokens = "whitelistedTokens"
+
+	vaultMethodPoolAmounts     = "poolAmounts"
+	vaultMethodBufferAmounts   = "bufferAmounts"
+	vaultMethodReservedAmounts = "reservedAmounts"
+	vaultMethodTokenDecimals   = "tokenDecimals"
+	vaultMethodStableTokens    = "stableTokens"
+	vaultMethodUSDGAmounts     = "usdgAmounts"
+	vaultMethodMaxUSDGAmounts  = "maxUsdgAmounts"
+	vaultMethodTokenWeights    = "tokenWeights"
+
+	vaultPriceFeedMethodGetPrice = "getPrice"
 )
@@ -1,14 +0,0 @@
-package fulcrom
-
-import (
-	"math/big"
-)
-
-type USDG struct {
-	Address     string   `json:"address"`
-	TotalSupply *big.Int `json:"totalSupply"`
-}
-
-const (
-	usdgMethodTotalSupply = "totalSupply"
-)
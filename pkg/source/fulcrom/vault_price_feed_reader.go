@@ -3,13 +3,17 @@ package fulcrom
 import (
 	"context"
 	"math/big"
+	"strconv"
 
 	"github.com/KyberNetwork/ethrpc"
 	"github.com/KyberNetwork/logger"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+
+	gmxv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-v1"
 )
 
+// VaultPriceFeedReader reads the min and max prices of the tokens from the vault price feed of Fulcrom.
 type VaultPriceFeedReader struct {
 	abi          abi.ABI
 	ethrpcClient *ethrpc.Client
@@ -31,8 +35,9 @@ func (r *VaultPriceFeedReader) Read(
 	ctx context.Context,
 	address string,
 	tokens []string,
-) (*VaultPriceFeed, error) {
-	vaultPriceFeed := NewVaultPriceFeed()
+) (*gmxv1.VaultPriceFeed, error) {
+	vaultPriceFeed := gmxv1.NewVaultPriceFeed()
+	vaultPriceFeed.PriceFeedType = gmxv1.PriceFeedTypeVault
 
 	if err := r.readTokenData(ctx, address, vaultPriceFeed, tokens); err != nil {
 		r.log.Errorf("error when read token data: %s", err)
@@ -42,17 +47,11 @@ func (r *VaultPriceFeedReader) Read(
 	return vaultPriceFeed, nil
 }
 
-// readTokenData reads data which required token address as parameter, included:
-// - PriceFeedsAddresses
-// - PriceDecimals
-// - SpreadBasisPoints
-// - AdjustmentBasisPoints
-// - StrictStableTokens
-// - IsAdjustmentAdditive
+// readTokenData reads the min and max prices of tokens
 func (r *VaultPriceFeedReader) readTokenData(
 	ctx context.Context,
 	address string,
-	vaultPriceFeed *VaultPriceFeed,
+	vaultPriceFeed *gmxv1.VaultPriceFeed,
 	tokens []string,
 ) error {
 	tokensLen := len(tokens)
@@ -60,8 +59,8 @@ func (r *VaultPriceFeedReader) readTokenData(
 	maxPrices := make([]*big.Int, tokensLen)
 	minPrices := make([]*big.Int, tokensLen)
 
-	callParamsFactory := CallParamsFactory(r.abi, address)
-	rpcRequest := r.ethrpcClient.NewRequest().SetContext(ctx)
+	callParamsFactory := gmxv1.CallParamsFactory(r.abi, address)
+	rpcRequest := gmxv1.NewRequest(r.ethrpcClient, ctx)
 
 	for i, token := range tokens {
 		tokenAddress := common.HexToAddress(token)
@@ -76,8 +75,10 @@ func (r *VaultPriceFeedReader) readTokenData(
 	}
 
 	for i, token := range tokens {
-		vaultPriceFeed.MinPrices[token] = minPrices[i]
-		vaultPriceFeed.MaxPrices[token] = maxPrices[i]
+		priceFeed := gmxv1.NewPriceFeed()
+		priceFeed.Answers[strconv.FormatBool(false)] = minPrices[i]
+		priceFeed.Answers[strconv.FormatBool(true)] = maxPrices[i]
+		vaultPriceFeed.PriceFeeds[token] = priceFeed
 	}
 
 	return nil
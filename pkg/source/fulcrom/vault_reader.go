@@ -9,8 +9,11 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	gmxv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-v1"
 )
 
+// VaultReader reads the vault of Fulcrom, whose ABI differs from GMX's.
 type VaultReader struct {
 	abi          abi.ABI
 	ethrpcClient *ethrpc.Client
@@ -29,8 +32,8 @@ func NewVaultReader(ethrpcClient *ethrpc.Client) *VaultReader {
 }
 
 // Read reads all data required for finding route
-func (r *VaultReader) Read(ctx context.Context, address string) (*Vault, error) {
-	vault := NewVault()
+func (r *VaultReader) Read(ctx context.Context, address string) (*gmxv1.Vault, error) {
+	vault := gmxv1.NewVault()
 
 	if err := r.readData(ctx, address, vault); err != nil {
 		r.log.Errorf("error when read data: %s", err)
@@ -62,8 +65,8 @@ func (r *VaultReader) Read(ctx context.Context, address string) (*Vault, error)
 //   - TotalTokenWeights
 //   - USDGAddress
 //   - WhitelistedTokensCount
-func (r *VaultReader) readData(ctx context.Context, address string, vault *Vault) error {
-	callParamsFactory := CallParamsFactory(r.abi, address)
+func (r *VaultReader) readData(ctx context.Context, address string, vault *gmxv1.Vault) error {
+	callParamsFactory := gmxv1.CallParamsFactory(r.abi, address)
 	rpcRequest := r.ethrpcClient.NewRequest().SetContext(ctx)
 
 	rpcRequest.AddCall(callParamsFactory(vaultMethodHasDynamicFees, nil), []any{&vault.HasDynamicFees})
@@ -86,7 +89,7 @@ func (r *VaultReader) readData(ctx context.Context, address string, vault *Vault
 func (r *VaultReader) readWhitelistedTokens(
 	ctx context.Context,
 	address string,
-	vault *Vault,
+	vault *gmxv1.Vault,
 ) error {
 	tokensLen := int(vault.WhitelistedTokensCount.Int64())
 
@@ -125,7 +128,7 @@ func (r *VaultReader) readWhitelistedTokens(
 func (r *VaultReader) readTokensData(
 	ctx context.Context,
 	address string,
-	vault *Vault,
+	vault *gmxv1.Vault,
 ) error {
 	tokensLen := len(vault.WhitelistedTokens)
 	poolAmounts := make([]*big.Int, tokensLen)
@@ -138,7 +141,7 @@ func (r *VaultReader) readTokensData(
 	tokenWeights := make([]*big.Int, tokensLen)
 
 	rpcRequest := r.ethrpcClient.NewRequest().SetContext(ctx)
-	callParamsFactory := CallParamsFactory(r.abi, address)
+	callParamsFactory := gmxv1.CallParamsFactory(r.abi, address)
 
 	for i, token := range vault.WhitelistedTokens {
 		tokenAddress := common.HexToAddress(token)
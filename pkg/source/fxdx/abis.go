@@ -7,14 +7,8 @@ import (
 )
 
 var (
-	vaultABI          abi.ABI
-	erc20ABI          abi.ABI
-	vaultPriceFeedABI abi.ABI
-	chainlinkABI      abi.ABI
-	pancakePairABI    abi.ABI
-	fastPriceFeedABI  abi.ABI
-	priceFeedABI      abi.ABI
-	feeUtilsABI       abi.ABI
+	vaultABI    abi.ABI
+	feeUtilsABI abi.ABI
 )
 
 func init() {
@@ -23,12 +17,6 @@ func init() {
 		data []byte
 	}{
 		{&vaultABI, vaultJson},
-		{&erc20ABI, erc20Json},
-		{&vaultPriceFeedABI, vaultPriceFeedJson},
-		{&chainlinkABI, chainlinkFlagsJson},
-		{&pancakePairABI, pancakePairJson},
-		{&fastPriceFeedABI, fastPriceFeedJson},
-		{&priceFeedABI, priceFeedJson},
 		{&feeUtilsABI, feeUtilsV2Json},
 	}
 
@@ -0,0 +1,28 @@
+package fxdx
+
+const (
+	DexTypeFxdx = "fxdx"
+
+	// usdfForkName is the name of USDG in the vault of FXDX
+	usdfForkName = "usdf"
+
+	vaultMethodIncludeAmmPrice       = "includeAmmPrice"
+	vaultMethodIsSwapEnabled         = "isSwapEnabled"
+	vaultMethodPriceFeed             = "priceFeed"
+	vaultMethodTotalTokenWeights     = "totalTokenWeights"
+	vaultMethodUSDF                  = "usdf"
+	vaultMethodWhitelistedTokenCount = "whitelistedTokenCount"
+
+	vaultMethodAllWhitelistedTokens = "allWhitelistedTokens"
+
+	vaultMethodPoolAmounts     = "poolAmounts"
+	vaultMethodBufferAmounts   = "bufferAmounts"
+	vaultMethodReservedAmounts = "reservedAmounts"
+	vaultMethodTokenDecimals   = "tokenDecimals"
+	vaultMethodStableTokens    = "stableTokens"
+	vaultMethodUSDFAmounts     = "usdfAmounts"
+	vaultMethodMaxUSDFAmounts  = "maxUsdfAmounts"
+	vaultMethodTokenWeights    = "tokenWeights"
+
+	vaultMethodFeeUtils = "feeUtils"
+)
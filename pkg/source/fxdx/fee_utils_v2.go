@@ -4,8 +4,11 @@ import (
 	"math/big"
 
 	"github.com/KyberNetwork/blockchain-toolkit/integer"
+
+	gmxv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-v1"
 )
 
+// FeeUtilsV2 is the gmxv1.IFeeUtils of FXDX, whose swap fees are set by token instead of by the vault.
 type FeeUtilsV2 struct {
 	Address string `json:"address"`
 
@@ -16,8 +19,6 @@ type FeeUtilsV2 struct {
 
 	TaxBasisPoints     map[string]*big.Int `json:"taxBasisPoints"`
 	SwapFeeBasisPoints map[string]*big.Int `json:"swapFeeBasisPoints"`
-
-	Vault *Vault `json:"-" msgpack:"-"`
 }
 
 func NewFeeUtilsV2() *FeeUtilsV2 {
@@ -33,16 +34,17 @@ const (
 )
 
 func (f *FeeUtilsV2) GetSwapFeeBasisPoints(
+	vault *gmxv1.Vault,
 	tokenIn string,
 	tokenOut string,
 	usdfAmount *big.Int,
 ) (*big.Int, error) {
-	if !f.IsInitialized {
+	if f == nil || !f.IsInitialized {
 		return nil, ErrFeeUtilsV2IsNotInitialized
 	}
 
-	feesBasisPoints0 := f.getFeeBasisPoints(tokenIn, usdfAmount, f.SwapFeeBasisPoints[tokenIn], f.TaxBasisPoints[tokenIn], true)
-	feesBasisPoints1 := f.getFeeBasisPoints(tokenOut, usdfAmount, f.SwapFeeBasisPoints[tokenOut], f.TaxBasisPoints[tokenOut], false)
+	feesBasisPoints0 := f.getFeeBasisPoints(vault, tokenIn, usdfAmount, f.SwapFeeBasisPoints[tokenIn], f.TaxBasisPoints[tokenIn], true)
+	feesBasisPoints1 := f.getFeeBasisPoints(vault, tokenOut, usdfAmount, f.SwapFeeBasisPoints[tokenOut], f.TaxBasisPoints[tokenOut], false)
 
 	if feesBasisPoints0.Cmp(feesBasisPoints1) > 0 {
 		return feesBasisPoints0, nil
@@ -52,6 +54,7 @@ func (f *FeeUtilsV2) GetSwapFeeBasisPoints(
 }
 
 func (f *FeeUtilsV2) getFeeBasisPoints(
+	vault *gmxv1.Vault,
 	token string,
 	usdfDelta *big.Int,
 	feeBasisPoints *big.Int,
@@ -67,7 +70,7 @@ func (f *FeeUtilsV2) getFeeBasisPoints(
 		return feeBps
 	}
 
-	initialAmount := f.Vault.USDFAmounts[token]
+	initialAmount := vault.USDGAmounts[token]
 	nextAmount := new(big.Int).Add(initialAmount, usdfDelta)
 	if !increment {
 		if usdfDelta.Cmp(initialAmount) > 0 {
@@ -77,7 +80,7 @@ func (f *FeeUtilsV2) getFeeBasisPoints(
 		}
 	}
 
-	targetAmount := f.Vault.GetTargetUSDFAmount(token)
+	targetAmount := vault.GetTargetUSDGAmount(token)
 	if targetAmount.Cmp(integer.Zero()) == 0 {
 		return feeBps
 	}
@@ -8,6 +8,8 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	gmxv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-v1"
 )
 
 type FeeUtilsV2Reader struct {
@@ -22,11 +24,21 @@ func NewFeeUtilsV2Reader(ethrpcClient *ethrpc.Client) *FeeUtilsV2Reader {
 	}
 }
 
-func (r *FeeUtilsV2Reader) Read(ctx context.Context, vault *Vault) (*FeeUtilsV2, error) {
+// Read reads the FeeUtilsV2 of the vault at vaultAddress, at the block of vault.
+func (r *FeeUtilsV2Reader) Read(ctx context.Context, vaultAddress string, vault *gmxv1.Vault) (*FeeUtilsV2, error) {
+	var feeUtilsAddress common.Address
+	if _, err := r.newRequest(ctx, vault).AddCall(&ethrpc.Call{
+		ABI:    vaultABI,
+		Target: vaultAddress,
+		Method: vaultMethodFeeUtils,
+	}, []any{&feeUtilsAddress}).Call(); err != nil {
+		return nil, err
+	}
+
 	feeUtils := NewFeeUtilsV2()
 
 	var (
-		address = vault.FeeUtils.Hex()
+		address = feeUtilsAddress.Hex()
 		tokens  = make([]common.Address, len(vault.WhitelistedTokens))
 
 		isInitialized bool
@@ -42,7 +54,7 @@ func (r *FeeUtilsV2Reader) Read(ctx context.Context, vault *Vault) (*FeeUtilsV2,
 		tokens[i] = common.HexToAddress(token)
 	}
 
-	request := r.ethrpcClient.R()
+	request := r.newRequest(ctx, vault)
 
 	request.AddCall(&ethrpc.Call{
 		ABI:    r.abi,
@@ -77,3 +89,11 @@ func (r *FeeUtilsV2Reader) Read(ctx context.Context, vault *Vault) (*FeeUtilsV2,
 
 	return feeUtils, nil
 }
+
+func (r *FeeUtilsV2Reader) newRequest(ctx context.Context, vault *gmxv1.Vault) *ethrpc.Request {
+	request := r.ethrpcClient.NewRequest().SetContext(ctx)
+	if vault.BlockNumber != nil {
+		request.SetBlockNumber(vault.BlockNumber)
+	}
+	return request
+}
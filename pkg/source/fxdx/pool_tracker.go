@@ -3,20 +3,18 @@ package fxdx
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/KyberNetwork/ethrpc"
 	"github.com/KyberNetwork/logger"
-	"github.com/goccy/go-json"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	gmxv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-v1"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
 	pooltrack "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/tracker"
 )
 
 type PoolTracker struct {
-	config       *Config
-	ethrpcClient *ethrpc.Client
+	scanner *VaultScanner
 }
 
 var _ = pooltrack.RegisterFactoryCE(DexTypeFxdx, NewPoolTracker)
@@ -26,8 +24,7 @@ func NewPoolTracker(
 	ethrpcClient *ethrpc.Client,
 ) (*PoolTracker, error) {
 	return &PoolTracker{
-		config:       cfg,
-		ethrpcClient: ethrpcClient,
+		scanner: NewVaultScanner(cfg, ethrpcClient),
 	}, nil
 }
 
@@ -42,44 +39,17 @@ func (d *PoolTracker) GetNewPoolState(
 	})
 	log.Info("Start getting new state of pool")
 
-	vault, blockNumber, err := NewVaultScanner(d.config, d.ethrpcClient).getVault(ctx, p.Address)
+	extra, err := d.scanner.getExtra(ctx, p.Address)
 	if err != nil {
 		log.Errorf("get vault failed: %v", err)
 		return entity.Pool{}, fmt.Errorf("get vault failed, pool: %s, err: %v", p.Address, err)
 	}
 
-	poolTokens := make([]*entity.PoolToken, 0, len(vault.WhitelistedTokens))
-	reserves := make(entity.PoolReserves, 0, len(vault.WhitelistedTokens))
-	for _, token := range vault.WhitelistedTokens {
-		poolTokens = append(poolTokens, &entity.PoolToken{
-			Address:   token,
-			Swappable: true,
-		})
-		reserves = append(reserves, vault.PoolAmounts[token].String())
-	}
-
-	feeUtils, err := NewFeeUtilsV2Reader(d.ethrpcClient).Read(ctx, vault)
-	if err != nil {
-		log.Errorf("get fee utils failed: %v", err)
-		return entity.Pool{}, err
-	}
-
-	extra := Extra{Vault: vault, FeeUtils: feeUtils}
-
-	extraBytes, err := json.Marshal(extra)
-	if err != nil {
+	if err = gmxv1.UpdatePool(&p, extra.Vault, extra); err != nil {
 		log.Errorf("marshal extra failed: %v", err)
 		return entity.Pool{}, err
 	}
 
-	p.Extra = string(extraBytes)
-	if blockNumber != nil {
-		p.BlockNumber = blockNumber.Uint64()
-	}
-	p.Reserves = reserves
-	p.Tokens = poolTokens
-	p.Timestamp = time.Now().Unix()
-
 	log.Info("Finish getting new state")
 
 	return p, nil
@@ -2,19 +2,18 @@ package fxdx
 
 import (
 	"context"
-	"time"
 
 	"github.com/KyberNetwork/ethrpc"
 	"github.com/KyberNetwork/logger"
-	"github.com/goccy/go-json"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	gmxv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-v1"
 	poollist "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/list"
 )
 
 type PoolsListUpdater struct {
 	config         *Config
-	ethrpcClient   *ethrpc.Client
+	scanner        *VaultScanner
 	hasInitialized bool
 }
 
@@ -26,12 +25,12 @@ func NewPoolsListUpdater(
 ) *PoolsListUpdater {
 	return &PoolsListUpdater{
 		config:         cfg,
-		ethrpcClient:   ethrpcClient,
+		scanner:        NewVaultScanner(cfg, ethrpcClient),
 		hasInitialized: false,
 	}
 }
 
-func (p *PoolsListUpdater) GetNewPools(ctx context.Context, metadataBytes []byte) ([]entity.Pool, []byte, error) {
+func (p *PoolsListUpdater) GetNewPools(ctx context.Context, _ []byte) ([]entity.Pool, []byte, error) {
 	log := logger.WithFields(logger.Fields{
 		"liquidityType": DexTypeFxdx,
 	})
@@ -40,46 +39,22 @@ func (p *PoolsListUpdater) GetNewPools(ctx context.Context, metadataBytes []byte
 		return nil, nil, nil
 	}
 
-	vault, _, err := NewVaultScanner(p.config, p.ethrpcClient).getVault(ctx, p.config.VaultAddress)
+	extra, err := p.scanner.getExtra(ctx, p.config.VaultAddress)
 	if err != nil {
 		log.Errorf("get vault failed: %v", err)
 		return nil, nil, err
 	}
 
-	poolTokens := make([]*entity.PoolToken, 0, len(vault.WhitelistedTokens))
-	reserves := make(entity.PoolReserves, 0, len(vault.WhitelistedTokens))
-	for _, token := range vault.WhitelistedTokens {
-		poolTokens = append(poolTokens, &entity.PoolToken{
-			Address:   token,
-			Swappable: true,
-		})
-		reserves = append(reserves, vault.PoolAmounts[token].String())
-	}
-
-	feeUtils, err := NewFeeUtilsV2Reader(p.ethrpcClient).Read(ctx, vault)
-	if err != nil {
-		log.Errorf("get fee utils failed: %v", err)
-		return nil, nil, err
+	pool := entity.Pool{
+		Address:  p.config.VaultAddress,
+		Exchange: p.config.DexID,
+		Type:     DexTypeFxdx,
 	}
-
-	extra := Extra{Vault: vault, FeeUtils: feeUtils}
-
-	extraBytes, err := json.Marshal(extra)
-	if err != nil {
+	if err = gmxv1.UpdatePool(&pool, extra.Vault, extra); err != nil {
 		log.Errorf("error when marshal extra: %v", err)
 		return nil, nil, err
 	}
 
-	pool := entity.Pool{
-		Address:   p.config.VaultAddress,
-		Exchange:  p.config.DexID,
-		Type:      DexTypeFxdx,
-		Tokens:    poolTokens,
-		Reserves:  reserves,
-		Extra:     string(extraBytes),
-		Timestamp: time.Now().Unix(),
-	}
-
 	p.hasInitialized = true
 	log.Infof("got %v vault", p.config.VaultAddress)
 
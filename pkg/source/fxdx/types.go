@@ -1,6 +1,8 @@
 package fxdx
 
+import gmxv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-v1"
+
 type Extra struct {
-	Vault    *Vault      `json:"vault"`
-	FeeUtils *FeeUtilsV2 `json:"feeUtils"`
+	Vault    *gmxv1.Vault `json:"vault"`
+	FeeUtils *FeeUtilsV2  `json:"feeUtils"`
 }
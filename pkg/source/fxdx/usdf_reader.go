@@ -1,49 +0,0 @@
-package fxdx
-
-import (
-	"context"
-	"math/big"
-
-	"github.com/KyberNetwork/ethrpc"
-	"github.com/KyberNetwork/logger"
-	"github.com/ethereum/go-ethereum/accounts/abi"
-)
-
-type USDFReader struct {
-	abi          abi.ABI
-	ethrpcClient *ethrpc.Client
-	log          logger.Logger
-}
-
-func NewUSDFReader(ethrpcClient *ethrpc.Client) *USDFReader {
-	return &USDFReader{
-		abi:          erc20ABI,
-		ethrpcClient: ethrpcClient,
-		log: logger.WithFields(logger.Fields{
-			"dexType": DexTypeFxdx,
-			"reader":  "USDFReader",
-		}),
-	}
-}
-
-func (r *USDFReader) Read(ctx context.Context, address string) (*USDF, error) {
-	var totalSupply *big.Int
-	rpcRequest := r.ethrpcClient.NewRequest().SetContext(ctx)
-
-	rpcRequest.AddCall(&ethrpc.Call{
-		ABI:    r.abi,
-		Target: address,
-		Method: usdfMethodTotalSupply,
-		Params: nil,
-	}, []any{&totalSupply})
-
-	if _, err := rpcRequest.Call(); err != nil {
-		r.log.Errorf("error when call rpc request %v", err)
-		return nil, err
-	}
-
-	return &USDF{
-		Address:     address,
-		TotalSupply: totalSupply,
-	}, nil
-}
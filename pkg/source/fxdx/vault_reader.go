@@ -9,8 +9,11 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	gmxv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-v1"
 )
 
+// VaultReader reads the vault of FXDX, which names its stablecoin USDF and charges its fees by a FeeUtilsV2.
 type VaultReader struct {
 	abi          abi.ABI
 	ethrpcClient *ethrpc.Client
@@ -28,39 +31,38 @@ func NewVaultReader(ethrpcClient *ethrpc.Client) *VaultReader {
 	}
 }
 
-func (r *VaultReader) Read(ctx context.Context, address string) (*Vault, *big.Int, error) {
-	vault := NewVault()
+func (r *VaultReader) Read(ctx context.Context, address string) (*gmxv1.Vault, error) {
+	vault := gmxv1.NewVault()
+	vault.USDGForkName = usdfForkName
 
 	if err := r.readData(ctx, address, vault); err != nil {
 		r.log.Errorf("error when read data: %s", err)
-		return nil, nil, err
+		return nil, err
 	}
 
 	if err := r.readWhitelistedTokens(ctx, address, vault); err != nil {
 		r.log.Errorf("error when read white listed token: %s", err)
-		return nil, nil, err
+		return nil, err
 	}
 
-	blockNumber, err := r.readTokensData(ctx, address, vault)
-	if err != nil {
+	if err := r.readTokensData(ctx, address, vault); err != nil {
 		r.log.Errorf("error when read tokens data: %s", err)
-		return nil, nil, err
+		return nil, err
 	}
 
-	return vault, blockNumber, nil
+	return vault, nil
 }
 
-func (r *VaultReader) readData(ctx context.Context, address string, vault *Vault) error {
-	callParamsFactory := CallParamsFactory(r.abi, address)
+func (r *VaultReader) readData(ctx context.Context, address string, vault *gmxv1.Vault) error {
+	callParamsFactory := gmxv1.CallParamsFactory(r.abi, address)
 	rpcRequest := r.ethrpcClient.NewRequest().SetContext(ctx)
 
 	rpcRequest.AddCall(callParamsFactory(vaultMethodIncludeAmmPrice, nil), []any{&vault.IncludeAmmPrice})
 	rpcRequest.AddCall(callParamsFactory(vaultMethodIsSwapEnabled, nil), []any{&vault.IsSwapEnabled})
 	rpcRequest.AddCall(callParamsFactory(vaultMethodPriceFeed, nil), []any{&vault.PriceFeedAddress})
 	rpcRequest.AddCall(callParamsFactory(vaultMethodTotalTokenWeights, nil), []any{&vault.TotalTokenWeights})
-	rpcRequest.AddCall(callParamsFactory(vaultMethodUSDF, nil), []any{&vault.USDFAddress})
+	rpcRequest.AddCall(callParamsFactory(vaultMethodUSDF, nil), []any{&vault.USDGAddress})
 	rpcRequest.AddCall(callParamsFactory(vaultMethodWhitelistedTokenCount, nil), []any{&vault.WhitelistedTokensCount})
-	rpcRequest.AddCall(callParamsFactory(vaultMethodFeeUtils, nil), []any{&vault.FeeUtils})
 
 	_, err := rpcRequest.TryAggregate()
 
@@ -70,7 +72,7 @@ func (r *VaultReader) readData(ctx context.Context, address string, vault *Vault
 func (r *VaultReader) readWhitelistedTokens(
 	ctx context.Context,
 	address string,
-	vault *Vault,
+	vault *gmxv1.Vault,
 ) error {
 	tokensLen := int(vault.WhitelistedTokensCount.Int64())
 	whitelistedTokens := make([]common.Address, tokensLen)
@@ -98,7 +100,7 @@ func (r *VaultReader) readWhitelistedTokens(
 	return nil
 }
 
-func (r *VaultReader) readTokensData(ctx context.Context, address string, vault *Vault) (*big.Int, error) {
+func (r *VaultReader) readTokensData(ctx context.Context, address string, vault *gmxv1.Vault) error {
 	tokensLen := len(vault.WhitelistedTokens)
 	poolAmounts := make([]*big.Int, tokensLen)
 	bufferAmounts := make([]*big.Int, tokensLen)
@@ -110,7 +112,7 @@ func (r *VaultReader) readTokensData(ctx context.Context, address string, vault
 	tokenWeights := make([]*big.Int, tokensLen)
 
 	rpcRequest := r.ethrpcClient.NewRequest().SetContext(ctx)
-	callParamsFactory := CallParamsFactory(r.abi, address)
+	callParamsFactory := gmxv1.CallParamsFactory(r.abi, address)
 
 	for i, token := range vault.WhitelistedTokens {
 		tokenAddress := common.HexToAddress(token)
@@ -127,8 +129,9 @@ func (r *VaultReader) readTokensData(ctx context.Context, address string, vault
 
 	response, err := rpcRequest.TryBlockAndAggregate()
 	if err != nil {
-		return nil, err
+		return err
 	}
+	vault.BlockNumber = response.BlockNumber
 
 	for i, token := range vault.WhitelistedTokens {
 		vault.PoolAmounts[token] = poolAmounts[i]
@@ -136,10 +139,10 @@ func (r *VaultReader) readTokensData(ctx context.Context, address string, vault
 		vault.ReservedAmounts[token] = reservedAmounts[i]
 		vault.TokenDecimals[token] = tokenDecimals[i]
 		vault.StableTokens[token] = stableTokens[i]
-		vault.USDFAmounts[token] = usdfAmounts[i]
-		vault.MaxUSDFAmounts[token] = maxUSDFAmounts[i]
+		vault.USDGAmounts[token] = usdfAmounts[i]
+		vault.MaxUSDGAmounts[token] = maxUSDFAmounts[i]
 		vault.TokenWeights[token] = tokenWeights[i]
 	}
 
-	return response.BlockNumber, nil
+	return nil
 }
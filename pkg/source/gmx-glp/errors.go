@@ -13,13 +13,6 @@ var (
 	ErrVaultNegativeRedemptionAmount       = errors.New("vault: redemptionAmount < 0") // code: 20
 	ErrVaultNegativeAmountOut              = errors.New("vault: amountOut < 0 ")       // ocde: 22
 
-	ErrVaultPriceFeedInvalidPriceFeed         = errors.New("vaultPriceFeed: invalid price feed")
-	ErrVaultPriceFeedInvalidPrice             = errors.New("vaultPriceFeed: invalid price")
-	ErrVaultPriceFeedCouldNotFetchPrice       = errors.New("vaultPriceFeed: could not fetch price")
-	ErrVaultPriceFeedChainlinkFeedsNotUpdated = errors.New("chainlink feeds are not being updated")
-
-	ErrInvalidSecondaryPriceFeedVersion = errors.New("invalid secondary price feed version")
-
 	ErrRewardRouterInvalidAmount    = errors.New("rewardRouter: invalid amount")
 	ErrRewardRouterInvalidGlpAmount = errors.New("rewardRouter: invalid glpAmount")
 	ErrGlpManagerInvalidAmount      = errors.New("glpManager: invalid _amount")
@@ -34,6 +34,9 @@ func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
 	if err := json.Unmarshal([]byte(entityPool.Extra), &extra); err != nil {
 		return nil, err
 	}
+	if extra.Vault != nil && extra.Vault.PriceFeed != nil {
+		extra.Vault.PriceFeed.SamplePreviousRound = true
+	}
 
 	tokens := make([]string, 0, len(entityPool.Tokens))
 	for _, poolToken := range entityPool.Tokens {
@@ -3,9 +3,6 @@ package gmxglp
 import "math/big"
 
 const (
-	secondaryPriceFeedVersion1 SecondaryPriceFeedVersion = 1
-	secondaryPriceFeedVersion2 SecondaryPriceFeedVersion = 2
-
 	calcAmountOutTypeStake   = "stake"
 	calcAmountOutTypeUnStake = "un-stake"
 )
@@ -29,10 +26,6 @@ type Meta struct {
 	BlockNumber     uint64 `json:"blockNumber"`
 }
 
-type ChainID uint
-
-type SecondaryPriceFeedVersion int
-
 type gmxGlpSwapInfo struct {
 	calcAmountOutType       string
 	mintAmount              *big.Int
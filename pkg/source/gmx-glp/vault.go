@@ -5,6 +5,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 
+	gmxv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-v1"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 )
 
@@ -30,11 +31,11 @@ type Vault struct {
 	MaxUSDGAmounts    map[string]*big.Int `json:"maxUsdgAmounts"`
 	TokenWeights      map[string]*big.Int `json:"tokenWeights"`
 
-	PriceFeedAddress common.Address  `json:"-"`
-	PriceFeed        *VaultPriceFeed `json:"priceFeed"`
+	PriceFeedAddress common.Address        `json:"-"`
+	PriceFeed        *gmxv1.VaultPriceFeed `json:"priceFeed"`
 
 	USDGAddress common.Address `json:"-"`
-	USDG        *USDG          `json:"usdg"`
+	USDG        *gmxv1.USDG    `json:"usdg"`
 
 	WhitelistedTokensCount *big.Int `json:"-"`
 }
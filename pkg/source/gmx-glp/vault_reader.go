@@ -9,6 +9,8 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	gmxv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-v1"
 )
 
 type VaultReader struct {
@@ -63,7 +65,7 @@ func (r *VaultReader) Read(ctx context.Context, address string) (*Vault, error)
 //   - USDGAddress
 //   - WhitelistedTokensCount
 func (r *VaultReader) readData(ctx context.Context, address string, vault *Vault) error {
-	callParamsFactory := CallParamsFactory(r.abi, address)
+	callParamsFactory := gmxv1.CallParamsFactory(r.abi, address)
 	rpcRequest := r.ethrpcClient.NewRequest().SetContext(ctx)
 
 	rpcRequest.AddCall(callParamsFactory(vaultMethodHasDynamicFees, nil), []any{&vault.HasDynamicFees})
@@ -159,7 +161,7 @@ func (r *VaultReader) readTokensData(
 	tokenWeights := make([]*big.Int, tokensLen)
 
 	rpcRequest := r.ethrpcClient.NewRequest().SetContext(ctx)
-	callParamsFactory := CallParamsFactory(r.abi, address)
+	callParamsFactory := gmxv1.CallParamsFactory(r.abi, address)
 
 	for i, token := range vault.WhitelistedTokens {
 		tokenAddress := common.HexToAddress(token)
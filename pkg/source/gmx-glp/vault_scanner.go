@@ -2,26 +2,19 @@ package gmxglp
 
 import (
 	"context"
-	"math/big"
 
 	"github.com/KyberNetwork/ethrpc"
 	"github.com/KyberNetwork/logger"
-	"github.com/ethereum/go-ethereum/common"
 
-	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/eth"
+	gmxv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-v1"
 )
 
+// VaultScanner reads the vault of GLP, whose price feeds are read by the scanner shared by the GMX v1 forks.
 type VaultScanner struct {
-	config                *Config
-	vaultReader           IVaultReader
-	vaultPriceFeedReader  IVaultPriceFeedReader
-	fastPriceFeedV1Reader IFastPriceFeedV1Reader
-	fastPriceFeedV2Reader IFastPriceFeedV2Reader
-	priceFeedReader       IPriceFeedReader
-	usdgReader            IUSDGReader
-	chainlinkFlagsReader  IChainlinkFlagsReader
-	pancakePairReader     IPancakePairReader
-	log                   logger.Logger
+	vaultReader      IVaultReader
+	usdgReader       gmxv1.IUSDGReader
+	priceFeedScanner *gmxv1.VaultScanner
+	log              logger.Logger
 }
 
 func NewVaultScanner(
@@ -29,15 +22,13 @@ func NewVaultScanner(
 	ethrpcClient *ethrpc.Client,
 ) *VaultScanner {
 	return &VaultScanner{
-		config:                config,
-		vaultReader:           NewVaultReader(ethrpcClient),
-		vaultPriceFeedReader:  NewVaultPriceFeedReader(ethrpcClient),
-		fastPriceFeedV1Reader: NewFastPriceFeedV1Reader(ethrpcClient),
-		fastPriceFeedV2Reader: NewFastPriceFeedV2Reader(ethrpcClient),
-		priceFeedReader:       NewPriceFeedReader(ethrpcClient),
-		usdgReader:            NewUSDGReader(ethrpcClient),
-		chainlinkFlagsReader:  NewChainlinkFlagsReader(ethrpcClient),
-		pancakePairReader:     NewPancakePairReader(ethrpcClient),
+		vaultReader: NewVaultReader(ethrpcClient),
+		usdgReader:  gmxv1.NewUSDGReader(DexTypeGmxGlp, ethrpcClient),
+		priceFeedScanner: gmxv1.NewVaultScanner(DexTypeGmxGlp, &gmxv1.Config{
+			DexID:                   config.DexID,
+			VaultAddress:            config.VaultAddress,
+			UseSecondaryPriceFeedV1: config.UseSecondaryPriceFeedV1,
+		}, ethrpcClient),
 		log: logger.WithFields(logger.Fields{
 			"liquiditySource": DexTypeGmxGlp,
 			"scanner":         "VaultScanner",
@@ -60,7 +51,8 @@ func (vs *VaultScanner) getVault(ctx context.Context, address string) (*Vault, e
 
 	vault.USDG = usdg
 
-	vaultPriceFeed, err := vs.getVaultPriceFeed(ctx, vault.PriceFeedAddress.String(), vault.WhitelistedTokens)
+	vaultPriceFeed, err := vs.priceFeedScanner.GetVaultPriceFeed(ctx, vault.PriceFeedAddress.String(),
+		vault.WhitelistedTokens)
 	if err != nil {
 		vs.log.Errorf("error when get vaultPriceFeed: %s", err)
 		return nil, err
@@ -70,113 +62,3 @@ func (vs *VaultScanner) getVault(ctx context.Context, address string) (*Vault, e
 
 	return vault, nil
 }
-
-// ================================================================================
-
-func (vs *VaultScanner) getVaultPriceFeed(ctx context.Context, address string, tokens []string) (*VaultPriceFeed, error) {
-	vaultPriceFeed, err := vs.vaultPriceFeedReader.Read(ctx, address, tokens)
-	if err != nil {
-		return nil, err
-	}
-
-	if !eth.IsZeroAddress(vaultPriceFeed.ChainlinkFlagsAddress) {
-		chainlinkFlags, err := vs.chainlinkFlagsReader.Read(ctx, vaultPriceFeed.ChainlinkFlagsAddress.String())
-		if err != nil {
-			return nil, err
-		}
-
-		vaultPriceFeed.ChainlinkFlags = chainlinkFlags
-	}
-
-	if !eth.IsZeroAddress(vaultPriceFeed.BNBBUSDAddress) {
-		bnbBusd, err := vs.pancakePairReader.Read(ctx, vaultPriceFeed.BNBBUSDAddress.String())
-		if err != nil {
-			return nil, err
-		}
-
-		vaultPriceFeed.BNBBUSD = bnbBusd
-	}
-
-	if !eth.IsZeroAddress(vaultPriceFeed.BTCBNBAddress) {
-		btcBnb, err := vs.pancakePairReader.Read(ctx, vaultPriceFeed.BTCBNBAddress.String())
-		if err != nil {
-			return nil, err
-		}
-
-		vaultPriceFeed.BTCBNB = btcBnb
-	}
-
-	if !eth.IsZeroAddress(vaultPriceFeed.ETHBNBAddress) {
-		ethBnb, err := vs.pancakePairReader.Read(ctx, vaultPriceFeed.ETHBNBAddress.String())
-		if err != nil {
-			return nil, err
-		}
-
-		vaultPriceFeed.ETHBNB = ethBnb
-	}
-
-	secondaryPriceFeedVersion := vs.getSecondaryPriceFeedVersion()
-
-	vaultPriceFeed.SecondaryPriceFeedVersion = int(secondaryPriceFeedVersion)
-
-	fastPriceFeed, err := vs.getFastPriceFeed(
-		ctx,
-		secondaryPriceFeedVersion,
-		vaultPriceFeed.SecondaryPriceFeedAddress.String(),
-		tokens,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	vaultPriceFeed.SecondaryPriceFeed = fastPriceFeed
-
-	priceFeeds, err := vs.getPriceFeeds(ctx, vaultPriceFeed.PriceFeedsAddresses, vaultPriceFeed.PriceSampleSpace)
-	if err != nil {
-		return nil, err
-	}
-
-	vaultPriceFeed.PriceFeeds = priceFeeds
-
-	return vaultPriceFeed, nil
-}
-
-func (vs *VaultScanner) getPriceFeeds(
-	ctx context.Context,
-	priceFeedAddresses map[string]common.Address,
-	priceSampleSpace *big.Int,
-) (map[string]*PriceFeed, error) {
-	roundCount := int(priceSampleSpace.Int64())
-	priceFeeds := make(map[string]*PriceFeed, len(priceFeedAddresses))
-
-	for tokenAddress, priceFeedAddress := range priceFeedAddresses {
-		priceFeed, err := vs.priceFeedReader.Read(ctx, priceFeedAddress.String(), roundCount)
-		if err != nil {
-			return nil, err
-		}
-
-		priceFeeds[tokenAddress] = priceFeed
-	}
-
-	return priceFeeds, nil
-}
-
-func (vs *VaultScanner) getFastPriceFeed(
-	ctx context.Context,
-	version SecondaryPriceFeedVersion,
-	address string,
-	tokens []string,
-) (IFastPriceFeed, error) {
-	if version == secondaryPriceFeedVersion2 {
-		return vs.fastPriceFeedV2Reader.Read(ctx, address, tokens)
-	}
-
-	return vs.fastPriceFeedV1Reader.Read(ctx, address, tokens)
-}
-
-func (vs *VaultScanner) getSecondaryPriceFeedVersion() SecondaryPriceFeedVersion {
-	if vs.config.UseSecondaryPriceFeedV1 {
-		return secondaryPriceFeedVersion1
-	}
-	return secondaryPriceFeedVersion2
-}
@@ -1,4 +1,4 @@
-package gmx
+package gmxv1
 
 type ChainlinkFlags struct {
 	Flags map[string]bool `json:"flags,omitempty"`
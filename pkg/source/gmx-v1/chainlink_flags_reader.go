@@ -1,4 +1,4 @@
-package gmx
+package gmxv1
 
 import (
 	"context"
@@ -15,12 +15,12 @@ type ChainlinkFlagsReader struct {
 	log          logger.Logger
 }
 
-func NewChainlinkFlagsReader(ethrpcClient *ethrpc.Client) *ChainlinkFlagsReader {
+func NewChainlinkFlagsReader(dexType string, ethrpcClient *ethrpc.Client) *ChainlinkFlagsReader {
 	return &ChainlinkFlagsReader{
 		abi:          chainlinkABI,
 		ethrpcClient: ethrpcClient,
 		log: logger.WithFields(logger.Fields{
-			"liquiditySource": DexTypeGmx,
+			"liquiditySource": dexType,
 			"reader":          "ChainlinkFlagsReader",
 		}),
 	}
@@ -29,7 +29,7 @@ func NewChainlinkFlagsReader(ethrpcClient *ethrpc.Client) *ChainlinkFlagsReader
 func (r *ChainlinkFlagsReader) Read(ctx context.Context, address string) (*ChainlinkFlags, error) {
 	var value bool
 
-	rpcRequest := r.ethrpcClient.NewRequest().SetContext(ctx)
+	rpcRequest := NewRequest(r.ethrpcClient, ctx)
 
 	rpcRequest.AddCall(&ethrpc.Call{
 		ABI:    r.abi,
@@ -0,0 +1,11 @@
+package gmxv1
+
+type Config struct {
+	DexID                   string        `json:"dexID"`
+	VaultAddress            string        `json:"vaultAddress"`
+	UseSecondaryPriceFeedV1 bool          `json:"useSecondaryPriceFeedV1"`
+	PriceFeedType           PriceFeedType `json:"priceFeedType"`
+	UsdgForkName            string        `json:"usdgForkName"`
+	// SkipUnsetSecondaryPriceFeed does not read the secondary price feed of vault price feeds which have none
+	SkipUnsetSecondaryPriceFeed bool `json:"skipUnsetSecondaryPriceFeed"`
+}
@@ -0,0 +1,20 @@
+package gmxv1
+
+import (
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+const (
+	FlagArbitrumSeqOffline = "0xa438451d6458044c3c8cd2f6f31c91ac882a6d91"
+
+	DefaultGas = 286524
+)
+
+var (
+	BasisPointsDivisor = bignumber.BasisPoint
+	PricePrecision     = bignumber.TenPowInt(30)
+	USDGDecimals       = big.NewInt(18)
+	OneUSD             = PricePrecision
+)
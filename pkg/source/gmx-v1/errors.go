@@ -1,4 +1,4 @@
-package zkerafinance
+package gmxv1
 
 import "errors"
 
@@ -13,8 +13,9 @@ var (
 	ErrVaultPriceFeedInvalidPrice             = errors.New("vaultPriceFeed: invalid price")
 	ErrVaultPriceFeedCouldNotFetchPrice       = errors.New("vaultPriceFeed: could not fetch price")
 	ErrVaultPriceFeedChainlinkFeedsNotUpdated = errors.New("chainlink feeds are not being updated")
-
-	ErrInvalidSecondaryPriceFeedVersion = errors.New("invalid secondary price feed version")
+	ErrVaultPriceFeedExpired                  = errors.New("vaultPriceFeed: expired")
 
 	ErrDivisionByZero = errors.New("division by zero")
+
+	ErrInvalidSecondaryPriceFeedVersion = errors.New("invalid secondary price feed version")
 )
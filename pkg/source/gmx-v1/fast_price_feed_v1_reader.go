@@ -1,4 +1,4 @@
-package zkerafinance
+package gmxv1
 
 import (
 	"context"
@@ -16,12 +16,12 @@ type FastPriceFeedV1Reader struct {
 	log          logger.Logger
 }
 
-func NewFastPriceFeedV1Reader(ethrpcClient *ethrpc.Client) *FastPriceFeedV1Reader {
+func NewFastPriceFeedV1Reader(dexType string, ethrpcClient *ethrpc.Client) *FastPriceFeedV1Reader {
 	return &FastPriceFeedV1Reader{
 		abi:          fastPriceFeedV1ABI,
 		ethrpcClient: ethrpcClient,
 		log: logger.WithFields(logger.Fields{
-			"liquiditySource": DexType,
+			"liquiditySource": dexType,
 			"reader":          "FastPriceFeedV1Reader",
 		}),
 	}
@@ -57,7 +57,7 @@ func (r *FastPriceFeedV1Reader) Read(
 // - VolBasisPoints
 func (r *FastPriceFeedV1Reader) readData(ctx context.Context, address string, fastPriceFeed *FastPriceFeedV1) error {
 	callParamsFactory := CallParamsFactory(r.abi, address)
-	rpcRequest := newRequest(r.ethrpcClient, ctx)
+	rpcRequest := NewRequest(r.ethrpcClient, ctx)
 
 	rpcRequest.AddCall(callParamsFactory(fastPriceFeedMethodV1DisableFastPriceVoteCount, nil), []any{&fastPriceFeed.DisableFastPriceVoteCount})
 	rpcRequest.AddCall(callParamsFactory(fastPriceFeedMethodV1IsSpreadEnabled, nil), []any{&fastPriceFeed.IsSpreadEnabled})
@@ -84,7 +84,7 @@ func (r *FastPriceFeedV1Reader) readTokenData(
 
 	prices := make([]*big.Int, tokensLen)
 
-	rpcRequest := newRequest(r.ethrpcClient, ctx)
+	rpcRequest := NewRequest(r.ethrpcClient, ctx)
 
 	for i, token := range tokens {
 		rpcRequest.AddCall(&ethrpc.Call{
@@ -1,4 +1,4 @@
-package zkerafinance
+package gmxv1
 
 import (
 	"context"
@@ -16,12 +16,12 @@ type FastPriceFeedV2Reader struct {
 	log          logger.Logger
 }
 
-func NewFastPriceFeedV2Reader(ethrpcClient *ethrpc.Client) *FastPriceFeedV2Reader {
+func NewFastPriceFeedV2Reader(dexType string, ethrpcClient *ethrpc.Client) *FastPriceFeedV2Reader {
 	return &FastPriceFeedV2Reader{
 		abi:          fastPriceFeedV2ABI,
 		ethrpcClient: ethrpcClient,
 		log: logger.WithFields(logger.Fields{
-			"liquiditySource": DexType,
+			"liquiditySource": dexType,
 			"reader":          "FastPriceFeedV2Reader",
 		}),
 	}
@@ -60,7 +60,7 @@ func (r *FastPriceFeedV2Reader) Read(
 func (r *FastPriceFeedV2Reader) readData(ctx context.Context, address string, fastPriceFeed *FastPriceFeedV2) error {
 
 	callParamsFactory := CallParamsFactory(r.abi, address)
-	rpcRequest := newRequest(r.ethrpcClient, ctx)
+	rpcRequest := NewRequest(r.ethrpcClient, ctx)
 
 	rpcRequest.AddCall(callParamsFactory(fastPriceFeedMethodV2DisableFastPriceVoteCount, nil), []any{&fastPriceFeed.DisableFastPriceVoteCount})
 	rpcRequest.AddCall(callParamsFactory(fastPriceFeedMethodV2IsSpreadEnabled, nil), []any{&fastPriceFeed.IsSpreadEnabled})
@@ -89,7 +89,7 @@ func (r *FastPriceFeedV2Reader) readTokenData(
 	maxCumulativeDeltaDiffs := make([]*big.Int, tokensLen)
 	priceData := make([]PriceDataItem, tokensLen)
 	callParamsFactory := CallParamsFactory(r.abi, address)
-	rpcRequest := newRequest(r.ethrpcClient, ctx)
+	rpcRequest := NewRequest(r.ethrpcClient, ctx)
 
 	for i, token := range tokens {
 		rpcRequest.AddCall(callParamsFactory(fastPriceFeedMethodV2Prices, []any{common.HexToAddress(token)}), []any{&prices[i]})
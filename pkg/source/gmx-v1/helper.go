@@ -1,4 +1,4 @@
-package zkerafinance
+package gmxv1
 
 import (
 	"context"
@@ -10,7 +10,8 @@ import (
 
 type blockNumberContextKey struct{}
 
-func newRequest(client *ethrpc.Client, ctx context.Context) *ethrpc.Request {
+// NewRequest creates a request at the block number of ctx if any, for the readers of a vault to read at the same block.
+func NewRequest(client *ethrpc.Client, ctx context.Context) *ethrpc.Request {
 	request := client.NewRequest().SetContext(ctx)
 	if blockNumber, ok := ctx.Value(blockNumberContextKey{}).(*big.Int); ok {
 		request.SetBlockNumber(blockNumber)
@@ -1,7 +1,7 @@
-package gmx
+package gmxv1
 
 // NOTE: we generate mock files in the same package to avoid cycle dependencies
-//go:generate go run go.uber.org/mock/mockgen -destination ./mocks.go -package gmx . IVaultReader,IVaultPriceFeedReader,IFastPriceFeedV1Reader,IPriceFeedReader,IUSDGReader,IChainlinkFlagsReader,IPancakePairReader
+//go:generate go run go.uber.org/mock/mockgen -destination ./mocks.go -package gmxv1 . IVaultReader,IVaultPriceFeedReader,IFastPriceFeedV1Reader,IPriceFeedReader,IUSDGReader,IChainlinkFlagsReader,IPancakePairReader
 
 import (
 	"context"
@@ -51,3 +51,8 @@ type IFastPriceFeed interface {
 	GetVersion() int
 	GetPrice(token string, refPrice *big.Int, maximise bool) *big.Int
 }
+
+// IFeeUtils computes the swap fees of a vault
+type IFeeUtils interface {
+	GetSwapFeeBasisPoints(vault *Vault, tokenIn string, tokenOut string, usdgAmount *big.Int) (*big.Int, error)
+}
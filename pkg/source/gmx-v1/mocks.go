@@ -1,13 +1,13 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/madmex (interfaces: IVaultReader,IVaultPriceFeedReader,IFastPriceFeedV1Reader,IPriceFeedReader,IUSDGReader,IChainlinkFlagsReader,IPancakePairReader)
+// Source: github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-v1 (interfaces: IVaultReader,IVaultPriceFeedReader,IFastPriceFeedV1Reader,IPriceFeedReader,IUSDGReader,IChainlinkFlagsReader,IPancakePairReader)
 //
 // Generated by this command:
 //
-//	mockgen -destination ./mocks.go -package madmex . IVaultReader,IVaultPriceFeedReader,IFastPriceFeedV1Reader,IPriceFeedReader,IUSDGReader,IChainlinkFlagsReader,IPancakePairReader
+//	mockgen -destination ./mocks.go -package gmxv1 . IVaultReader,IVaultPriceFeedReader,IFastPriceFeedV1Reader,IPriceFeedReader,IUSDGReader,IChainlinkFlagsReader,IPancakePairReader
 //
 
-// Package madmex is a generated GoMock package.
-package madmex
+// Package gmxv1 is a generated GoMock package.
+package gmxv1
 
 import (
 	context "context"
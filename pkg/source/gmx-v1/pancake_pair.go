@@ -1,4 +1,4 @@
-package gmx
+package gmxv1
 
 import "math/big"
 
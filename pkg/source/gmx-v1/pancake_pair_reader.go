@@ -1,4 +1,4 @@
-package zkerafinance
+package gmxv1
 
 import (
 	"context"
@@ -15,12 +15,12 @@ type PancakePairReader struct {
 	log          logger.Logger
 }
 
-func NewPancakePairReader(ethrpcClient *ethrpc.Client) *PancakePairReader {
+func NewPancakePairReader(dexType string, ethrpcClient *ethrpc.Client) *PancakePairReader {
 	return &PancakePairReader{
 		abi:          pancakePairABI,
 		ethrpcClient: ethrpcClient,
 		log: logger.WithFields(logger.Fields{
-			"liquiditySource": DexType,
+			"liquiditySource": dexType,
 			"reader":          "PancakePairReader",
 		}),
 	}
@@ -33,7 +33,7 @@ func (r *PancakePairReader) Read(ctx context.Context, address string) (*PancakeP
 		BlockTimestampLast uint32
 	}
 
-	rpcRequest := newRequest(r.ethrpcClient, ctx)
+	rpcRequest := NewRequest(r.ethrpcClient, ctx)
 
 	rpcRequest.AddCall(&ethrpc.Call{
 		ABI:    r.abi,
@@ -0,0 +1,206 @@
+package gmxv1
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// newTestVault returns a vault holding tokenA (18 decimals) and tokenB (6 decimals) priced by priceFeedType.
+func newTestVault(priceFeedType PriceFeedType) *Vault {
+	vault := NewVault()
+	vault.IsSwapEnabled = true
+	vault.SwapFeeBasisPoints = big.NewInt(30)
+	vault.StableSwapFeeBasisPoints = big.NewInt(4)
+	vault.TaxBasisPoints = big.NewInt(50)
+	vault.StableTaxBasisPoints = big.NewInt(20)
+	vault.TotalTokenWeights = big.NewInt(100)
+	vault.WhitelistedTokens = []string{tokenA, tokenB}
+	vault.USDG = &USDG{Address: "0x0000000000000000000000000000000000000001", TotalSupply: bignumber.TenPowInt(24)}
+	vault.PriceFeed = newTestVaultPriceFeed(priceFeedType)
+
+	for token, decimals := range map[string]int64{tokenA: 18, tokenB: 6} {
+		vault.TokenDecimals[token] = big.NewInt(decimals)
+		vault.PoolAmounts[token] = bignumber.TenPowInt(decimals + 6)
+		vault.BufferAmounts[token] = bignumber.ZeroBI
+		vault.ReservedAmounts[token] = bignumber.ZeroBI
+		vault.USDGAmounts[token] = bignumber.TenPowInt(23)
+		vault.MaxUSDGAmounts[token] = bignumber.ZeroBI
+		vault.TokenWeights[token] = big.NewInt(50)
+	}
+
+	return vault
+}
+
+func newTestEntityPool(t *testing.T, vault *Vault) entity.Pool {
+	extra, err := json.Marshal(Extra{Vault: vault})
+	require.NoError(t, err)
+
+	return entity.Pool{
+		Address:  "0x0000000000000000000000000000000000000002",
+		Exchange: "gmx",
+		Type:     "gmx",
+		Tokens:   []*entity.PoolToken{{Address: tokenA}, {Address: tokenB}},
+		Reserves: []string{vault.PoolAmounts[tokenA].String(), vault.PoolAmounts[tokenB].String()},
+		Extra:    string(extra),
+	}
+}
+
+type fixedFeeUtils struct {
+	feeBasisPoints *big.Int
+}
+
+func (u *fixedFeeUtils) GetSwapFeeBasisPoints(*Vault, string, string, *big.Int) (*big.Int, error) {
+	return u.feeBasisPoints, nil
+}
+
+// TestPoolSimulator_CalcAmountOut_PriceFeedTypes checks that the vault engine quotes the same swap identically
+// whichever price feeds the fork reads.
+func TestPoolSimulator_CalcAmountOut_PriceFeedTypes(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range testPriceFeedTypes {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := NewPoolSimulator(newTestEntityPool(t, newTestVault(tc.priceFeedType)))
+			require.NoError(t, err)
+
+			result, err := p.CalcAmountOut(pool.CalcAmountOutParams{
+				TokenAmountIn: pool.TokenAmount{Token: tokenA, Amount: bignumber.TenPowInt(18)},
+				TokenOut:      tokenB,
+			})
+			require.NoError(t, err)
+
+			// 1 tokenA = 2000 tokenB, minus 0.3% of swap fees
+			assert.Equal(t, big.NewInt(1994e6), result.TokenAmountOut.Amount)
+			assert.Equal(t, big.NewInt(6e6), result.Fee.Amount)
+			assert.Equal(t, int64(DefaultGas), result.Gas)
+		})
+	}
+}
+
+func TestPoolSimulator_CalcAmountOut_FeeUtils(t *testing.T) {
+	t.Parallel()
+
+	vault := newTestVault(PriceFeedTypeLatestRoundData)
+	p := NewPoolSimulatorWithVault(newTestEntityPool(t, vault), vault, &fixedFeeUtils{feeBasisPoints: big.NewInt(100)})
+
+	result, err := p.CalcAmountOut(pool.CalcAmountOutParams{
+		TokenAmountIn: pool.TokenAmount{Token: tokenA, Amount: bignumber.TenPowInt(18)},
+		TokenOut:      tokenB,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1980e6), result.TokenAmountOut.Amount)
+	assert.Equal(t, big.NewInt(20e6), result.Fee.Amount)
+}
+
+func TestPoolSimulator_CalcAmountOut_Errors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		modify      func(vault *Vault)
+		expectedErr error
+	}{
+		{
+			name:        "swaps not enabled",
+			modify:      func(vault *Vault) { vault.IsSwapEnabled = false },
+			expectedErr: ErrVaultSwapsNotEnabled,
+		},
+		{
+			name: "zero price out",
+			modify: func(vault *Vault) {
+				vault.PriceFeed.PriceFeeds[tokenB].Answers["true"] = bignumber.ZeroBI
+			},
+			expectedErr: ErrDivisionByZero,
+		},
+		{
+			name:        "max usdg exceeded",
+			modify:      func(vault *Vault) { vault.MaxUSDGAmounts[tokenA] = bignumber.TenPowInt(23) },
+			expectedErr: ErrVaultMaxUsdgExceeded,
+		},
+		{
+			name:        "pool amount exceeded",
+			modify:      func(vault *Vault) { vault.PoolAmounts[tokenB] = big.NewInt(1e9) },
+			expectedErr: ErrVaultPoolAmountExceeded,
+		},
+		{
+			name:        "reserve exceeds pool",
+			modify:      func(vault *Vault) { vault.ReservedAmounts[tokenB] = bignumber.TenPowInt(12) },
+			expectedErr: ErrVaultReserveExceedsPool,
+		},
+		{
+			name:        "pool amount less than buffer amount",
+			modify:      func(vault *Vault) { vault.BufferAmounts[tokenB] = new(big.Int).SetUint64(999999e6) },
+			expectedErr: ErrVaultPoolAmountLessThanBufferAmount,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			vault := newTestVault(PriceFeedTypeDirect)
+			tc.modify(vault)
+			p := NewPoolSimulatorWithVault(newTestEntityPool(t, vault), vault, &VaultUtils{})
+
+			_, err := p.CalcAmountOut(pool.CalcAmountOutParams{
+				TokenAmountIn: pool.TokenAmount{Token: tokenA, Amount: bignumber.TenPowInt(18)},
+				TokenOut:      tokenB,
+			})
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestPoolSimulator_UpdateBalance(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewPoolSimulator(newTestEntityPool(t, newTestVault(PriceFeedTypeLatestRoundData)))
+	require.NoError(t, err)
+	cloned := p.CloneState().(*PoolSimulator)
+
+	params := pool.CalcAmountOutParams{
+		TokenAmountIn: pool.TokenAmount{Token: tokenA, Amount: bignumber.TenPowInt(18)},
+		TokenOut:      tokenB,
+	}
+	result, err := p.CalcAmountOut(params)
+	require.NoError(t, err)
+	p.UpdateBalance(pool.UpdateBalanceParams{
+		TokenAmountIn:  params.TokenAmountIn,
+		TokenAmountOut: *result.TokenAmountOut,
+		Fee:            *result.Fee,
+	})
+
+	usdgAmount := new(big.Int).Mul(big.NewInt(2000), bignumber.TenPowInt(18))
+	assert.Equal(t, new(big.Int).Add(bignumber.TenPowInt(23), usdgAmount), p.Vault().USDGAmounts[tokenA])
+	assert.Equal(t, new(big.Int).Sub(bignumber.TenPowInt(23), usdgAmount), p.Vault().USDGAmounts[tokenB])
+	assert.Equal(t, new(big.Int).Add(bignumber.TenPowInt(24), bignumber.TenPowInt(18)), p.Vault().PoolAmounts[tokenA])
+	assert.Equal(t, new(big.Int).Sub(bignumber.TenPowInt(12), big.NewInt(2000e6)), p.Vault().PoolAmounts[tokenB])
+
+	// the cloned state is not affected by the swap
+	clonedResult, err := cloned.CalcAmountOut(params)
+	require.NoError(t, err)
+	assert.Equal(t, result.TokenAmountOut.Amount, clonedResult.TokenAmountOut.Amount)
+	assert.Equal(t, bignumber.TenPowInt(23), cloned.Vault().USDGAmounts[tokenA])
+	assert.Equal(t, bignumber.TenPowInt(24), cloned.Vault().PoolAmounts[tokenA])
+}
+
+func TestPoolSimulator_CanSwapTo(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewPoolSimulator(newTestEntityPool(t, newTestVault(PriceFeedTypeLatestRoundData)))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{tokenB}, p.CanSwapTo(tokenA))
+	assert.Equal(t, []string{tokenA}, p.CanSwapFrom(tokenB))
+	assert.Empty(t, p.CanSwapTo("0x0000000000000000000000000000000000000000"))
+}
@@ -0,0 +1,81 @@
+package gmxv1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/KyberNetwork/logger"
+	"github.com/goccy/go-json"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+// PoolTracker tracks the vault of a GMX v1 fork, read by its VaultScanner.
+type PoolTracker struct {
+	dexType string
+	scanner *VaultScanner
+}
+
+func NewPoolTracker(dexType string, scanner *VaultScanner) *PoolTracker {
+	return &PoolTracker{
+		dexType: dexType,
+		scanner: scanner,
+	}
+}
+
+func (d *PoolTracker) GetNewPoolState(
+	ctx context.Context,
+	p entity.Pool,
+	_ pool.GetNewPoolStateParams,
+) (entity.Pool, error) {
+	log := logger.WithFields(logger.Fields{
+		"liquiditySource": d.dexType,
+		"poolAddress":     p.Address,
+	})
+	log.Info("Start getting new state of pool")
+
+	vault, err := d.scanner.GetVault(ctx, p.Address)
+	if err != nil {
+		log.Errorf("get vault failed: %v", err)
+		return entity.Pool{}, fmt.Errorf("get vault failed, pool: %s, err: %v", p.Address, err)
+	}
+
+	if err = UpdatePool(&p, vault, Extra{Vault: vault}); err != nil {
+		log.Errorf("marshal extra failed: %v", err)
+		return entity.Pool{}, err
+	}
+
+	log.Info("Finish getting new state")
+
+	return p, nil
+}
+
+// UpdatePool sets the tokens, reserves and extra of the pool of vault.
+func UpdatePool(p *entity.Pool, vault *Vault, extra any) error {
+	poolTokens := make([]*entity.PoolToken, 0, len(vault.WhitelistedTokens))
+	reserves := make(entity.PoolReserves, 0, len(vault.WhitelistedTokens))
+	for _, token := range vault.WhitelistedTokens {
+		poolTokens = append(poolTokens, &entity.PoolToken{
+			Address:   token,
+			Swappable: true,
+		})
+		reserves = append(reserves, vault.PoolAmounts[token].String())
+	}
+
+	extraBytes, err := json.Marshal(extra)
+	if err != nil {
+		return err
+	}
+
+	p.Extra = string(extraBytes)
+	if vault.BlockNumber != nil {
+		p.BlockNumber = vault.BlockNumber.Uint64()
+	}
+	p.Reserves = reserves
+	p.Tokens = poolTokens
+	p.Timestamp = time.Now().Unix()
+
+	return nil
+}
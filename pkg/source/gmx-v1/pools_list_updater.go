@@ -0,0 +1,59 @@
+package gmxv1
+
+import (
+	"context"
+
+	"github.com/KyberNetwork/logger"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+)
+
+// PoolsListUpdater lists the single pool of the vault of a GMX v1 fork.
+type PoolsListUpdater struct {
+	dexType        string
+	dexID          string
+	vaultAddress   string
+	scanner        *VaultScanner
+	hasInitialized bool
+}
+
+func NewPoolsListUpdater(dexType, dexID, vaultAddress string, scanner *VaultScanner) *PoolsListUpdater {
+	return &PoolsListUpdater{
+		dexType:      dexType,
+		dexID:        dexID,
+		vaultAddress: vaultAddress,
+		scanner:      scanner,
+	}
+}
+
+func (d *PoolsListUpdater) GetNewPools(ctx context.Context, _ []byte) ([]entity.Pool, []byte, error) {
+	log := logger.WithFields(logger.Fields{
+		"liquiditySource": d.dexID,
+		"kind":            "getNewPools",
+	})
+	if d.hasInitialized {
+		log.Infof("initialized. Ignore making new pools")
+		return nil, nil, nil
+	}
+
+	vault, err := d.scanner.GetVault(ctx, d.vaultAddress)
+	if err != nil {
+		log.Errorf("get vault failed: %v", err)
+		return nil, nil, err
+	}
+
+	pool := entity.Pool{
+		Address:  d.vaultAddress,
+		Exchange: d.dexID,
+		Type:     d.dexType,
+	}
+	if err = UpdatePool(&pool, vault, Extra{Vault: vault}); err != nil {
+		log.Errorf("error when marshal extra: %v", err)
+		return nil, nil, err
+	}
+
+	d.hasInitialized = true
+	log.Infof("got %v vault", d.vaultAddress)
+
+	return []entity.Pool{pool}, nil, nil
+}
@@ -0,0 +1,99 @@
+package gmxv1
+
+import (
+	"math/big"
+
+	"github.com/goccy/go-json"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+type PriceFeed struct {
+	RoundID *big.Int            `json:"roundId,omitempty"`
+	Answer  *big.Int            `json:"answer,omitempty"`
+	Answers map[string]*big.Int `json:"answers,omitempty"`
+	// Timestamp is the time of Answer, for PriceFeedTypeProxy
+	Timestamp uint32 `json:"timestamp,omitempty"`
+
+	// legacyPriceFeedType is the type of the price feeds of the fork whose extra keyed this price feed
+	legacyPriceFeedType PriceFeedType
+}
+
+// priceFeedJSON is a PriceFeed, also keyed as in the extras of forks which read their price feeds differently:
+// the price of QuickPerps' price feed proxies and the latestAnswer(maximise) of zkEra Finance's price feeds.
+type priceFeedJSON struct {
+	priceFeed
+
+	Price         *big.Int            `json:"price,omitempty"`
+	LatestAnswers map[string]*big.Int `json:"latestAnswers,omitempty"`
+}
+
+type priceFeed PriceFeed
+
+func (pf *PriceFeed) UnmarshalJSON(bytes []byte) error {
+	aux := priceFeedJSON{priceFeed: priceFeed(*pf)}
+	if err := json.Unmarshal(bytes, &aux); err != nil {
+		return err
+	}
+	*pf = PriceFeed(aux.priceFeed)
+
+	if aux.Price != nil {
+		pf.Answer = aux.Price
+		pf.legacyPriceFeedType = PriceFeedTypeProxy
+	}
+	if aux.LatestAnswers != nil {
+		pf.Answers = aux.LatestAnswers
+		pf.legacyPriceFeedType = PriceFeedTypeLatestAnswerMinMax
+	}
+
+	return nil
+}
+
+// marshalLegacy keys the price feed as in the extras of the forks of priceFeedType.
+func (pf *PriceFeed) marshalLegacy(priceFeedType PriceFeedType) any {
+	if pf == nil {
+		return pf
+	}
+
+	switch priceFeedType {
+	case PriceFeedTypeProxy:
+		return priceFeedJSON{priceFeed: priceFeed{Timestamp: pf.Timestamp}, Price: pf.Answer}
+	case PriceFeedTypeLatestAnswerMinMax:
+		return priceFeedJSON{LatestAnswers: pf.Answers}
+	default:
+		return pf
+	}
+}
+
+type RoundData struct {
+	RoundId         *big.Int
+	Answer          *big.Int
+	StartedAt       *big.Int
+	UpdatedAt       *big.Int
+	AnsweredInRound *big.Int
+}
+
+func NewPriceFeed() *PriceFeed {
+	return &PriceFeed{
+		Answers: make(map[string]*big.Int),
+	}
+}
+
+const (
+	priceFeedMethodLatestRoundData = "latestRoundData"
+	priceFeedMethodGetRoundData    = "getRoundData"
+	minRoundCount                  = 2
+)
+
+func (pf *PriceFeed) LatestRound() *big.Int {
+	return pf.RoundID
+}
+
+func (pf *PriceFeed) LatestAnswer() *big.Int {
+	return pf.Answer
+}
+
+// GetRoundData returns roundId, answer, startedAt, updatedAt, answeredInRound
+func (pf *PriceFeed) GetRoundData(roundID *big.Int) (*big.Int, *big.Int, *big.Int, *big.Int, *big.Int) {
+	return roundID, pf.Answers[roundID.String()], bignumber.ZeroBI, bignumber.ZeroBI, bignumber.ZeroBI
+}
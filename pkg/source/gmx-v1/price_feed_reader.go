@@ -1,4 +1,4 @@
-package gmx
+package gmxv1
 
 import (
 	"context"
@@ -17,6 +17,12 @@ const (
 	PriceFeedTypeLatestRoundData PriceFeedType = iota
 	PriceFeedTypeLatestRoundAnswer
 	PriceFeedTypeDirect // should prefer direct if VaultPriceFeed exposes getPrimaryPrice method
+	// PriceFeedTypeLatestAnswerMinMax price feeds answer latestAnswer(maximise), as zkEra Finance's
+	PriceFeedTypeLatestAnswerMinMax
+	// PriceFeedTypeProxy price feed proxies answer read() with a price and its timestamp, as QuickPerps'
+	PriceFeedTypeProxy
+	// PriceFeedTypeVault VaultPriceFeed answers the final getPrice(token, maximise) of the vault, as Fulcrom's
+	PriceFeedTypeVault
 )
 
 type PriceFeedReader struct {
@@ -27,16 +33,17 @@ type PriceFeedReader struct {
 	PriceFeedType PriceFeedType
 }
 
-func NewPriceFeedReader(ethrpcClient *ethrpc.Client) *PriceFeedReader {
-	return NewPriceFeedReaderWithParam(ethrpcClient, PriceFeedTypeLatestRoundData)
+func NewPriceFeedReader(dexType string, ethrpcClient *ethrpc.Client) *PriceFeedReader {
+	return NewPriceFeedReaderWithParam(dexType, ethrpcClient, PriceFeedTypeLatestRoundData)
 }
 
-func NewPriceFeedReaderWithParam(ethrpcClient *ethrpc.Client, priceFeedType PriceFeedType) *PriceFeedReader {
+func NewPriceFeedReaderWithParam(dexType string, ethrpcClient *ethrpc.Client,
+	priceFeedType PriceFeedType) *PriceFeedReader {
 	return &PriceFeedReader{
 		abi:          priceFeedABI,
 		ethrpcClient: ethrpcClient,
 		log: logger.WithFields(logger.Fields{
-			"liquiditySource": DexTypeGmx,
+			"liquiditySource": dexType,
 			"reader":          "PriceFeedReader",
 		}),
 
@@ -67,7 +74,7 @@ func (r *PriceFeedReader) getLatestRoundData(ctx context.Context, address string
 		latestAnswer    = bignumber.ZeroBI
 	)
 
-	rpcRequest := r.ethrpcClient.NewRequest().SetContext(ctx)
+	rpcRequest := NewRequest(r.ethrpcClient, ctx)
 
 	switch r.PriceFeedType {
 	case PriceFeedTypeLatestRoundData:
@@ -108,7 +115,7 @@ func (r *PriceFeedReader) getLatestRoundData(ctx context.Context, address string
 		priceFeed.Answer = latestAnswer
 		priceFeed.Answers[latestRound.String()] = latestAnswer
 
-	case PriceFeedTypeDirect: // already read directly by VaultPriceFeedReader
+	default: // read directly by VaultPriceFeedReader, or by the reader of the fork
 		return nil
 	}
 
@@ -117,11 +124,11 @@ func (r *PriceFeedReader) getLatestRoundData(ctx context.Context, address string
 
 func (r *PriceFeedReader) getHistoryRoundData(ctx context.Context, address string, priceFeed *PriceFeed,
 	roundCount int) error {
-	if roundCount < minRoundCount {
+	if roundCount < minRoundCount || priceFeed.RoundID == nil {
 		return nil
 	}
 
-	rpcRequest := r.ethrpcClient.NewRequest().SetContext(ctx)
+	rpcRequest := NewRequest(r.ethrpcClient, ctx)
 	roundDataList := make([]RoundData, roundCount-1)
 	for i := 1; i < roundCount; i++ {
 		roundID := new(big.Int).Sub(priceFeed.RoundID, big.NewInt(int64(i)))
@@ -1,4 +1,4 @@
-package gmx
+package gmxv1
 
 type VaultAddress struct {
 	Vault string `json:"vault"`
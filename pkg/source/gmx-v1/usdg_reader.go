@@ -1,4 +1,4 @@
-package zkerafinance
+package gmxv1
 
 import (
 	"context"
@@ -15,12 +15,12 @@ type USDGReader struct {
 	log          logger.Logger
 }
 
-func NewUSDGReader(ethrpcClient *ethrpc.Client) *USDGReader {
+func NewUSDGReader(dexType string, ethrpcClient *ethrpc.Client) *USDGReader {
 	return &USDGReader{
 		abi:          erc20ABI,
 		ethrpcClient: ethrpcClient,
 		log: logger.WithFields(logger.Fields{
-			"liquiditySource": DexType,
+			"liquiditySource": dexType,
 			"reader":          "USDGReader",
 		}),
 	}
@@ -28,7 +28,7 @@ func NewUSDGReader(ethrpcClient *ethrpc.Client) *USDGReader {
 
 func (r *USDGReader) Read(ctx context.Context, address string) (*USDG, error) {
 	var totalSupply *big.Int
-	rpcRequest := newRequest(r.ethrpcClient, ctx)
+	rpcRequest := NewRequest(r.ethrpcClient, ctx)
 
 	rpcRequest.AddCall(&ethrpc.Call{
 		ABI:    r.abi,
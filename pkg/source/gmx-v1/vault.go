@@ -1,9 +1,10 @@
-package gmx
+package gmxv1
 
 import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 )
@@ -34,6 +35,9 @@ type Vault struct {
 
 	USDGAddress common.Address `json:"-"`
 	USDG        *USDG          `json:"usdg,omitempty"`
+	// USDGForkName is the name of USDG in the vault of the fork, which keys its USDG fields in extras, e.g. usdm for
+	// Metavault's usdmAmounts, maxUsdmAmounts and usdm. Defaults to usdg.
+	USDGForkName string `json:"-"`
 
 	WhitelistedTokensCount *big.Int `json:"-"`
 
@@ -53,6 +57,77 @@ func NewVault() *Vault {
 	}
 }
 
+// vaultJSON keys the USDG fields of a Vault by the name of USDG in the vault of each fork.
+type vaultJSON struct {
+	vault
+
+	USDGAmounts    map[string]*big.Int `json:"usdgAmounts,omitempty"`
+	MaxUSDGAmounts map[string]*big.Int `json:"maxUsdgAmounts,omitempty"`
+	USDG           *USDG               `json:"usdg,omitempty"`
+
+	USDMAmounts    map[string]*big.Int `json:"usdmAmounts,omitempty"`
+	MaxUSDMAmounts map[string]*big.Int `json:"maxUsdmAmounts,omitempty"`
+	USDM           *USDG               `json:"usdm,omitempty"`
+
+	USDFAmounts    map[string]*big.Int `json:"usdfAmounts,omitempty"`
+	MaxUSDFAmounts map[string]*big.Int `json:"maxUsdfAmounts,omitempty"`
+	USDF           *USDG               `json:"usdf,omitempty"`
+
+	USDQAmounts    map[string]*big.Int `json:"usdqAmounts,omitempty"`
+	MaxUSDQAmounts map[string]*big.Int `json:"maxUsdqAmounts,omitempty"`
+	USDQ           *USDG               `json:"usdq,omitempty"`
+
+	USDBAmounts    map[string]*big.Int `json:"usdbAmounts,omitempty"`
+	MaxUSDBAmounts map[string]*big.Int `json:"maxUsdbAmounts,omitempty"`
+	USDB           *USDG               `json:"usdb,omitempty"`
+}
+
+type vault Vault
+
+var usdgForkNames = []string{"usdg", "usdm", "usdf", "usdq", "usdb"}
+
+func (v *vaultJSON) usdgFields(usdgForkName string) (*map[string]*big.Int, *map[string]*big.Int, **USDG) {
+	switch usdgForkName {
+	case "usdm":
+		return &v.USDMAmounts, &v.MaxUSDMAmounts, &v.USDM
+	case "usdf":
+		return &v.USDFAmounts, &v.MaxUSDFAmounts, &v.USDF
+	case "usdq":
+		return &v.USDQAmounts, &v.MaxUSDQAmounts, &v.USDQ
+	case "usdb":
+		return &v.USDBAmounts, &v.MaxUSDBAmounts, &v.USDB
+	default:
+		return &v.USDGAmounts, &v.MaxUSDGAmounts, &v.USDG
+	}
+}
+
+func (v *Vault) MarshalJSON() ([]byte, error) {
+	aux := vaultJSON{vault: vault(*v)}
+	usdgAmounts, maxUSDGAmounts, usdg := aux.usdgFields(v.USDGForkName)
+	*usdgAmounts, *maxUSDGAmounts, *usdg = v.USDGAmounts, v.MaxUSDGAmounts, v.USDG
+	return json.Marshal(aux)
+}
+
+func (v *Vault) UnmarshalJSON(bytes []byte) error {
+	aux := vaultJSON{vault: vault(*v)}
+	if err := json.Unmarshal(bytes, &aux); err != nil {
+		return err
+	}
+	*v = Vault(aux.vault)
+
+	for _, usdgForkName := range usdgForkNames {
+		usdgAmounts, maxUSDGAmounts, usdg := aux.usdgFields(usdgForkName)
+		if *usdgAmounts == nil && *maxUSDGAmounts == nil && *usdg == nil {
+			continue
+		}
+		v.USDGAmounts, v.MaxUSDGAmounts, v.USDG = *usdgAmounts, *maxUSDGAmounts, *usdg
+		v.USDGForkName = usdgForkName
+		break
+	}
+
+	return nil
+}
+
 const (
 	vaultMethodHasDynamicFees           = "hasDynamicFees"
 	vaultMethodIncludeAmmPrice          = "includeAmmPrice"
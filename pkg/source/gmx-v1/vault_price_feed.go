@@ -1,8 +1,9 @@
-package gmx
+package gmxv1
 
 import (
 	"math/big"
 	"strconv"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/goccy/go-json"
@@ -25,6 +26,7 @@ type VaultPriceFeed struct {
 	SpreadThresholdBasisPoints *big.Int      `json:"spreadThresholdBasisPoints,omitempty"`
 	UseV2Pricing               bool          `json:"useV2Pricing,omitempty"`
 	PriceFeedType              PriceFeedType `json:"priceFeedType,omitempty"`
+	ExpireTimeForPriceFeed     *big.Int      `json:"expireTimeForPriceFeed,omitempty"`
 
 	PriceDecimals         map[string]*big.Int `json:"priceDecimals,omitempty"`
 	SpreadBasisPoints     map[string]*big.Int `json:"spreadBasisPoints,omitempty"`
@@ -50,6 +52,10 @@ type VaultPriceFeed struct {
 
 	PriceFeedsAddresses map[string]common.Address `json:"-"`
 	PriceFeeds          map[string]*PriceFeed     `json:"priceFeeds,omitempty"`
+
+	// SamplePreviousRound samples the round before the latest one at each of PriceSampleSpace and keeps the min price
+	// only, as the forks other than GMX priced their primary price feeds.
+	SamplePreviousRound bool `json:"-"`
 }
 
 func NewVaultPriceFeed() *VaultPriceFeed {
@@ -90,6 +96,38 @@ const (
 	vaultPriceFeedMethodGetPrimaryPrice       = "getPrimaryPrice"
 )
 
+// MarshalJSON keys the prices of forks which read their price feeds differently as in their extras.
+func (pf *VaultPriceFeed) MarshalJSON() ([]byte, error) {
+	type vaultPriceFeed VaultPriceFeed
+	aux := struct {
+		vaultPriceFeed
+		PriceFeeds map[string]any      `json:"priceFeeds,omitempty"`
+		MinPrices  map[string]*big.Int `json:"minPrices,omitempty"`
+		MaxPrices  map[string]*big.Int `json:"maxPrices,omitempty"`
+	}{vaultPriceFeed: vaultPriceFeed(*pf)}
+
+	if pf.PriceFeedType == PriceFeedTypeVault {
+		aux.MinPrices = make(map[string]*big.Int, len(pf.PriceFeeds))
+		aux.MaxPrices = make(map[string]*big.Int, len(pf.PriceFeeds))
+		for token, priceFeed := range pf.PriceFeeds {
+			if priceFeed == nil {
+				continue
+			}
+			aux.MinPrices[token] = priceFeed.Answers[strconv.FormatBool(false)]
+			aux.MaxPrices[token] = priceFeed.Answers[strconv.FormatBool(true)]
+		}
+		return json.Marshal(aux)
+	}
+
+	if pf.PriceFeeds != nil {
+		aux.PriceFeeds = make(map[string]any, len(pf.PriceFeeds))
+		for token, priceFeed := range pf.PriceFeeds {
+			aux.PriceFeeds[token] = priceFeed.marshalLegacy(pf.PriceFeedType)
+		}
+	}
+	return json.Marshal(aux)
+}
+
 func (pf *VaultPriceFeed) UnmarshalJSON(bytes []byte) error {
 	var priceFeed struct {
 		BNB                        string                `json:"bnb"`
@@ -102,7 +140,8 @@ func (pf *VaultPriceFeed) UnmarshalJSON(bytes []byte) error {
 		PriceSampleSpace           *big.Int              `json:"priceSampleSpace"`
 		SpreadThresholdBasisPoints *big.Int              `json:"spreadThresholdBasisPoints"`
 		UseV2Pricing               bool                  `json:"useV2Pricing"`
-		PriceFeedType              PriceFeedType         `json:"priceFeedType"`
+		PriceFeedType              *PriceFeedType        `json:"priceFeedType"`
+		ExpireTimeForPriceFeed     *big.Int              `json:"expireTimeForPriceFeed"`
 		PriceDecimals              map[string]*big.Int   `json:"priceDecimals"`
 		SpreadBasisPoints          map[string]*big.Int   `json:"spreadBasisPoints"`
 		AdjustmentBasisPoints      map[string]*big.Int   `json:"adjustmentBasisPoints"`
@@ -112,8 +151,11 @@ func (pf *VaultPriceFeed) UnmarshalJSON(bytes []byte) error {
 		BTCBNB                     *PancakePair          `json:"btcBnb"`
 		ETHBNB                     *PancakePair          `json:"ethBnb"`
 		ChainlinkFlags             *ChainlinkFlags       `json:"chainlinkFlags"`
-		SecondaryPriceFeedVersion  int                   `json:"secondaryPriceFeedVersion"`
+		SecondaryPriceFeed         json.RawMessage       `json:"secondaryPriceFeed"`
+		SecondaryPriceFeedVersion  *int                  `json:"secondaryPriceFeedVersion"`
 		PriceFeeds                 map[string]*PriceFeed `json:"priceFeeds"`
+		MinPrices                  map[string]*big.Int   `json:"minPrices"`
+		MaxPrices                  map[string]*big.Int   `json:"maxPrices"`
 	}
 
 	if err := json.Unmarshal(bytes, &priceFeed); err != nil {
@@ -130,7 +172,7 @@ func (pf *VaultPriceFeed) UnmarshalJSON(bytes []byte) error {
 	pf.PriceSampleSpace = priceFeed.PriceSampleSpace
 	pf.SpreadThresholdBasisPoints = priceFeed.SpreadThresholdBasisPoints
 	pf.UseV2Pricing = priceFeed.UseV2Pricing
-	pf.PriceFeedType = priceFeed.PriceFeedType
+	pf.ExpireTimeForPriceFeed = priceFeed.ExpireTimeForPriceFeed
 	pf.PriceDecimals = priceFeed.PriceDecimals
 	pf.SpreadBasisPoints = priceFeed.SpreadBasisPoints
 	pf.AdjustmentBasisPoints = priceFeed.AdjustmentBasisPoints
@@ -140,9 +182,42 @@ func (pf *VaultPriceFeed) UnmarshalJSON(bytes []byte) error {
 	pf.BTCBNB = priceFeed.BTCBNB
 	pf.ETHBNB = priceFeed.ETHBNB
 	pf.ChainlinkFlags = priceFeed.ChainlinkFlags
-	pf.SecondaryPriceFeedVersion = priceFeed.SecondaryPriceFeedVersion
 	pf.PriceFeeds = priceFeed.PriceFeeds
 
+	switch {
+	case priceFeed.SecondaryPriceFeedVersion != nil:
+		pf.SecondaryPriceFeedVersion = *priceFeed.SecondaryPriceFeedVersion
+	case len(priceFeed.SecondaryPriceFeed) > 0 && string(priceFeed.SecondaryPriceFeed) != "null":
+		// FXDX keyed its FastPriceFeedV2 without a version
+		pf.SecondaryPriceFeedVersion = int(secondaryPriceFeedVersion2)
+	}
+
+	if priceFeed.MinPrices != nil || priceFeed.MaxPrices != nil {
+		// Fulcrom keys the prices of its VaultPriceFeed by minPrices and maxPrices
+		pf.PriceFeedType = PriceFeedTypeVault
+		pf.PriceFeeds = make(map[string]*PriceFeed, len(priceFeed.MaxPrices))
+		for token, price := range priceFeed.MinPrices {
+			pf.PriceFeeds[token] = &PriceFeed{Answers: map[string]*big.Int{strconv.FormatBool(false): price}}
+		}
+		for token, price := range priceFeed.MaxPrices {
+			if pf.PriceFeeds[token] == nil {
+				pf.PriceFeeds[token] = &PriceFeed{Answers: make(map[string]*big.Int, 1)}
+			}
+			pf.PriceFeeds[token].Answers[strconv.FormatBool(true)] = price
+		}
+	}
+
+	if priceFeed.PriceFeedType != nil {
+		pf.PriceFeedType = *priceFeed.PriceFeedType
+	} else {
+		for _, priceFeed := range pf.PriceFeeds {
+			if priceFeed != nil && priceFeed.legacyPriceFeedType != PriceFeedTypeLatestRoundData {
+				pf.PriceFeedType = priceFeed.legacyPriceFeedType
+				break
+			}
+		}
+	}
+
 	if err := pf.UnmarshalJSONSecondaryPriceFeed(bytes); err != nil {
 		return err
 	}
@@ -152,6 +227,7 @@ func (pf *VaultPriceFeed) UnmarshalJSON(bytes []byte) error {
 
 func (pf *VaultPriceFeed) UnmarshalJSONSecondaryPriceFeed(bytes []byte) error {
 	switch pf.SecondaryPriceFeedVersion {
+	case 0: // no secondary price feed
 	case 1:
 		var priceFeed struct {
 			SecondaryPriceFeed *FastPriceFeedV1 `json:"secondaryPriceFeed"`
@@ -180,6 +256,10 @@ func (pf *VaultPriceFeed) UnmarshalJSONSecondaryPriceFeed(bytes []byte) error {
 }
 
 func (pf *VaultPriceFeed) GetPrice(token string, maximise bool, includeAmmPrice bool, _ bool) (*big.Int, error) {
+	if pf.PriceFeedType == PriceFeedTypeVault {
+		return pf.getVaultPrice(token, maximise)
+	}
+
 	var price *big.Int
 	var err error
 
@@ -338,9 +418,20 @@ func (pf *VaultPriceFeed) getPrimaryPrice(token string, maximise bool) (*big.Int
 		}
 	}
 
-	if pf.PriceFeedType == PriceFeedTypeDirect {
+	switch pf.PriceFeedType {
+	case PriceFeedTypeDirect:
 		return lo.CoalesceOrEmpty(priceFeed.Answers[strconv.FormatBool(maximise)], bignumber.ZeroBI), nil
-	} else if pf.PriceSampleSpace == nil {
+	case PriceFeedTypeLatestAnswerMinMax:
+		price := priceFeed.Answers[strconv.FormatBool(maximise)]
+		if price == nil {
+			return nil, ErrVaultPriceFeedCouldNotFetchPrice
+		}
+		return pf.toPricePrecision(token, price), nil
+	case PriceFeedTypeProxy:
+		return pf.getProxyPrice(token, priceFeed)
+	}
+
+	if pf.PriceSampleSpace == nil {
 		return priceFeed.LatestAnswer(), nil
 	}
 
@@ -360,7 +451,11 @@ func (pf *VaultPriceFeed) getPrimaryPrice(token string, maximise bool) (*big.Int
 				return nil, ErrVaultPriceFeedInvalidPrice
 			}
 		} else {
-			_, p, _, _, _ = priceFeed.GetRoundData(new(big.Int).Sub(roundID, i))
+			roundDelta := i
+			if pf.SamplePreviousRound {
+				roundDelta = bignumber.One
+			}
+			_, p, _, _, _ = priceFeed.GetRoundData(new(big.Int).Sub(roundID, roundDelta))
 
 			if p.Sign() <= 0 {
 				return nil, ErrVaultPriceFeedInvalidPrice
@@ -372,7 +467,7 @@ func (pf *VaultPriceFeed) getPrimaryPrice(token string, maximise bool) (*big.Int
 			continue
 		}
 
-		if maximise && p.Cmp(price) > 0 {
+		if maximise && !pf.SamplePreviousRound && p.Cmp(price) > 0 {
 			price = p
 			continue
 		}
@@ -386,9 +481,43 @@ func (pf *VaultPriceFeed) getPrimaryPrice(token string, maximise bool) (*big.Int
 		return nil, ErrVaultPriceFeedCouldNotFetchPrice
 	}
 
-	priceDecimal := pf.PriceDecimals[token]
+	return pf.toPricePrecision(token, price), nil
+}
+
+// getProxyPrice returns the price of a price feed proxy, which must have been updated within ExpireTimeForPriceFeed.
+func (pf *VaultPriceFeed) getProxyPrice(token string, priceFeed *PriceFeed) (*big.Int, error) {
+	price := priceFeed.LatestAnswer()
+	if price == nil || price.Sign() <= 0 {
+		return nil, ErrVaultPriceFeedInvalidPrice
+	}
+
+	expireTime := lo.CoalesceOrEmpty(pf.ExpireTimeForPriceFeed, bignumber.ZeroBI)
+	if int64(priceFeed.Timestamp)+expireTime.Int64() <= time.Now().Unix() {
+		return nil, ErrVaultPriceFeedExpired
+	}
+
+	return pf.toPricePrecision(token, price), nil
+}
+
+// getVaultPrice returns the price answered by getPrice of the VaultPriceFeed, for PriceFeedTypeVault.
+func (pf *VaultPriceFeed) getVaultPrice(token string, maximise bool) (*big.Int, error) {
+	priceFeed, ok := pf.PriceFeeds[token]
+	if !ok {
+		return nil, ErrVaultPriceFeedInvalidPriceFeed
+	}
+
+	price := priceFeed.Answers[strconv.FormatBool(maximise)]
+	if price == nil {
+		return nil, ErrVaultPriceFeedCouldNotFetchPrice
+	}
+
+	return new(big.Int).Set(price), nil
+}
+
+func (pf *VaultPriceFeed) toPricePrecision(token string, price *big.Int) *big.Int {
+	priceDecimal := lo.CoalesceOrEmpty(pf.PriceDecimals[token], bignumber.ZeroBI)
 	price = new(big.Int).Mul(price, PricePrecision)
-	return price.Div(price, bignumber.TenPowInt(priceDecimal.Int64())), nil
+	return price.Div(price, bignumber.TenPowInt(priceDecimal.Int64()))
 }
 
 func (pf *VaultPriceFeed) getSecondaryPrice(token string, referencePrice *big.Int, maximise bool) *big.Int {
@@ -1,4 +1,4 @@
-package gmx
+package gmxv1
 
 import (
 	"context"
@@ -19,16 +19,17 @@ type VaultPriceFeedReader struct {
 	priceFeedType PriceFeedType
 }
 
-func NewVaultPriceFeedReader(ethrpcClient *ethrpc.Client) *VaultPriceFeedReader {
-	return NewVaultPriceFeedReaderWithParam(ethrpcClient, PriceFeedTypeLatestRoundData)
+func NewVaultPriceFeedReader(dexType string, ethrpcClient *ethrpc.Client) *VaultPriceFeedReader {
+	return NewVaultPriceFeedReaderWithParam(dexType, ethrpcClient, PriceFeedTypeLatestRoundData)
 }
 
-func NewVaultPriceFeedReaderWithParam(ethrpcClient *ethrpc.Client, priceFeedType PriceFeedType) *VaultPriceFeedReader {
+func NewVaultPriceFeedReaderWithParam(dexType string, ethrpcClient *ethrpc.Client,
+	priceFeedType PriceFeedType) *VaultPriceFeedReader {
 	return &VaultPriceFeedReader{
 		abi:          vaultPriceFeedABI,
 		ethrpcClient: ethrpcClient,
 		log: logger.WithFields(logger.Fields{
-			"liquiditySource": DexTypeGmx,
+			"liquiditySource": dexType,
 			"reader":          "VaultPriceFeedReader",
 		}),
 
@@ -81,7 +82,7 @@ func (r *VaultPriceFeedReader) readData(
 	var bnb, btc, eth common.Address
 
 	callParamsFactory := CallParamsFactory(r.abi, address)
-	rpcRequest := r.ethrpcClient.NewRequest().SetContext(ctx)
+	rpcRequest := NewRequest(r.ethrpcClient, ctx)
 
 	rpcRequest.AddCall(callParamsFactory(vaultPriceFeedMethodBNB, nil), []any{&bnb})
 	rpcRequest.AddCall(callParamsFactory(vaultPriceFeedMethodBNBBUSD, nil),
@@ -149,7 +150,7 @@ func (r *VaultPriceFeedReader) readTokenData(
 	}
 
 	callParamsFactory := CallParamsFactory(r.abi, address)
-	rpcRequest := r.ethrpcClient.NewRequest().SetContext(ctx)
+	rpcRequest := NewRequest(r.ethrpcClient, ctx)
 
 	for i, token := range tokens {
 		tokenAddress := common.HexToAddress(token)
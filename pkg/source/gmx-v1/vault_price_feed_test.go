@@ -0,0 +1,296 @@
+package gmxv1
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+const (
+	tokenA = "0x000000000000000000000000000000000000000a"
+	tokenB = "0x000000000000000000000000000000000000000b"
+)
+
+// usd returns amount USD in PricePrecision.
+func usd(amount int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(amount), PricePrecision)
+}
+
+// chainlinkAnswer returns amount USD with the 8 decimals of chainlink feeds.
+func chainlinkAnswer(amount int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(amount), bignumber.TenPowInt(8))
+}
+
+// newTestVaultPriceFeed returns a VaultPriceFeed of priceFeedType pricing tokenA at 2000 USD and tokenB at 1 USD,
+// the way the readers of the forks using priceFeedType fill it.
+func newTestVaultPriceFeed(priceFeedType PriceFeedType) *VaultPriceFeed {
+	pf := NewVaultPriceFeed()
+	pf.PriceFeedType = priceFeedType
+	pf.PriceSampleSpace = big.NewInt(1)
+	pf.ExpireTimeForPriceFeed = big.NewInt(3600)
+
+	prices := map[string]int64{tokenA: 2000, tokenB: 1}
+	for token, price := range prices {
+		pf.PriceDecimals[token] = big.NewInt(8)
+		pf.SpreadBasisPoints[token] = bignumber.ZeroBI
+		pf.AdjustmentBasisPoints[token] = bignumber.ZeroBI
+
+		priceFeed := NewPriceFeed()
+		switch priceFeedType {
+		case PriceFeedTypeDirect, PriceFeedTypeVault:
+			priceFeed.Answers["true"] = usd(price)
+			priceFeed.Answers["false"] = usd(price)
+		case PriceFeedTypeLatestAnswerMinMax:
+			priceFeed.Answers["true"] = chainlinkAnswer(price)
+			priceFeed.Answers["false"] = chainlinkAnswer(price)
+		case PriceFeedTypeProxy:
+			priceFeed.Answer = chainlinkAnswer(price)
+			priceFeed.Timestamp = uint32(time.Now().Unix())
+		default:
+			priceFeed.RoundID = big.NewInt(10)
+			priceFeed.Answer = chainlinkAnswer(price)
+		}
+		pf.PriceFeeds[token] = priceFeed
+	}
+
+	return pf
+}
+
+var testPriceFeedTypes = []struct {
+	name          string
+	priceFeedType PriceFeedType
+}{
+	{"latest round data", PriceFeedTypeLatestRoundData},
+	{"latest round answer", PriceFeedTypeLatestRoundAnswer},
+	{"direct", PriceFeedTypeDirect},
+	{"latest answer min max", PriceFeedTypeLatestAnswerMinMax},
+	{"proxy", PriceFeedTypeProxy},
+	{"vault", PriceFeedTypeVault},
+}
+
+func TestVaultPriceFeed_GetPrice(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range testPriceFeedTypes {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			pf := newTestVaultPriceFeed(tc.priceFeedType)
+			for _, maximise := range []bool{true, false} {
+				price, err := pf.GetPrice(tokenA, maximise, false, false)
+				require.NoError(t, err)
+				assert.Equal(t, usd(2000), price)
+
+				price, err = pf.GetPrice(tokenB, maximise, false, false)
+				require.NoError(t, err)
+				assert.Equal(t, usd(1), price)
+			}
+		})
+	}
+}
+
+func TestVaultPriceFeed_JSON(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range testPriceFeedTypes {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			extra, err := json.Marshal(newTestVaultPriceFeed(tc.priceFeedType))
+			require.NoError(t, err)
+
+			var pf VaultPriceFeed
+			require.NoError(t, json.Unmarshal(extra, &pf))
+			assert.Equal(t, tc.priceFeedType, pf.PriceFeedType)
+
+			price, err := pf.GetPrice(tokenA, true, false, false)
+			require.NoError(t, err)
+			assert.Equal(t, usd(2000), price)
+		})
+	}
+}
+
+// TestVaultPriceFeed_UnmarshalJSON_Legacy decodes the price feeds of forks as keyed in their extras before they shared
+// the vault engine.
+func TestVaultPriceFeed_UnmarshalJSON_Legacy(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                  string
+		extra                 string
+		expectedPriceFeedType PriceFeedType
+		expectedPriceFeed     *PriceFeed
+	}{
+		{
+			name:                  "fulcrom",
+			extra:                 `{"minPrices":{"0xa":1},"maxPrices":{"0xa":2}}`,
+			expectedPriceFeedType: PriceFeedTypeVault,
+			expectedPriceFeed:     &PriceFeed{Answers: map[string]*big.Int{"false": big.NewInt(1), "true": big.NewInt(2)}},
+		},
+		{
+			name:                  "quickperps",
+			extra:                 `{"priceFeeds":{"0xa":{"price":1,"timestamp":2}}}`,
+			expectedPriceFeedType: PriceFeedTypeProxy,
+			expectedPriceFeed:     &PriceFeed{Answer: big.NewInt(1), Timestamp: 2},
+		},
+		{
+			name:                  "zkera finance",
+			extra:                 `{"priceFeeds":{"0xa":{"latestAnswers":{"false":1,"true":2}}}}`,
+			expectedPriceFeedType: PriceFeedTypeLatestAnswerMinMax,
+			expectedPriceFeed:     &PriceFeed{Answers: map[string]*big.Int{"false": big.NewInt(1), "true": big.NewInt(2)}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var pf VaultPriceFeed
+			require.NoError(t, json.Unmarshal([]byte(tc.extra), &pf))
+			assert.Equal(t, tc.expectedPriceFeedType, pf.PriceFeedType)
+			require.Contains(t, pf.PriceFeeds, "0xa")
+			assert.Equal(t, tc.expectedPriceFeed.Answer, pf.PriceFeeds["0xa"].Answer)
+			assert.Equal(t, tc.expectedPriceFeed.Answers, pf.PriceFeeds["0xa"].Answers)
+			assert.Equal(t, tc.expectedPriceFeed.Timestamp, pf.PriceFeeds["0xa"].Timestamp)
+
+			extra, err := json.Marshal(&pf)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.extra, removeJSONKey(t, string(extra), "priceFeedType"))
+		})
+	}
+
+	t.Run("fxdx secondary price feed without version", func(t *testing.T) {
+		t.Parallel()
+
+		var pf VaultPriceFeed
+		require.NoError(t, json.Unmarshal([]byte(`{"secondaryPriceFeed":{"lastUpdatedAt":1}}`), &pf))
+		assert.Equal(t, int(secondaryPriceFeedVersion2), pf.SecondaryPriceFeedVersion)
+		assert.IsType(t, &FastPriceFeedV2{}, pf.SecondaryPriceFeed)
+	})
+}
+
+func removeJSONKey(t *testing.T, extra string, key string) string {
+	var m map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal([]byte(extra), &m))
+	delete(m, key)
+	bytes, err := json.Marshal(m)
+	require.NoError(t, err)
+	return string(bytes)
+}
+
+func TestVaultPriceFeed_GetPrice_RoundSampling(t *testing.T) {
+	t.Parallel()
+
+	pf := newTestVaultPriceFeed(PriceFeedTypeLatestRoundData)
+	pf.PriceSampleSpace = big.NewInt(3)
+	priceFeed := pf.PriceFeeds[tokenA]
+	priceFeed.Answers["9"] = chainlinkAnswer(2010)
+	priceFeed.Answers["8"] = chainlinkAnswer(1990)
+	priceFeed.Answers["7"] = chainlinkAnswer(3000)
+
+	maxPrice, err := pf.GetPrice(tokenA, true, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, usd(2010), maxPrice)
+
+	minPrice, err := pf.GetPrice(tokenA, false, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, usd(1990), minPrice)
+
+	// rounds before the first one are not sampled
+	priceFeed.RoundID = big.NewInt(2)
+	priceFeed.Answers["1"] = chainlinkAnswer(2010)
+	maxPrice, err = pf.GetPrice(tokenA, true, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, usd(2010), maxPrice)
+
+	priceFeed.Answers["1"] = bignumber.ZeroBI
+	_, err = pf.GetPrice(tokenA, true, false, false)
+	assert.ErrorIs(t, err, ErrVaultPriceFeedInvalidPrice)
+}
+
+func TestVaultPriceFeed_GetPrice_SamplePreviousRound(t *testing.T) {
+	t.Parallel()
+
+	pf := newTestVaultPriceFeed(PriceFeedTypeLatestRoundData)
+	pf.PriceSampleSpace = big.NewInt(3)
+	pf.SamplePreviousRound = true
+	priceFeed := pf.PriceFeeds[tokenA]
+	priceFeed.Answers["9"] = chainlinkAnswer(1990)
+	priceFeed.Answers["8"] = chainlinkAnswer(1000)
+
+	maxPrice, err := pf.GetPrice(tokenA, true, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, usd(2000), maxPrice)
+
+	minPrice, err := pf.GetPrice(tokenA, false, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, usd(1990), minPrice)
+}
+
+func TestVaultPriceFeed_GetPrice_Errors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing price feed", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range testPriceFeedTypes {
+			pf := newTestVaultPriceFeed(tc.priceFeedType)
+			_, err := pf.GetPrice("0x0000000000000000000000000000000000000000", true, false, false)
+			assert.ErrorIs(t, err, ErrVaultPriceFeedInvalidPriceFeed, tc.name)
+		}
+	})
+
+	t.Run("expired proxy", func(t *testing.T) {
+		t.Parallel()
+
+		pf := newTestVaultPriceFeed(PriceFeedTypeProxy)
+		pf.PriceFeeds[tokenA].Timestamp = uint32(time.Now().Add(-2 * time.Hour).Unix())
+		_, err := pf.GetPrice(tokenA, true, false, false)
+		assert.ErrorIs(t, err, ErrVaultPriceFeedExpired)
+	})
+
+	t.Run("missing min max answer", func(t *testing.T) {
+		t.Parallel()
+
+		for _, priceFeedType := range []PriceFeedType{PriceFeedTypeLatestAnswerMinMax, PriceFeedTypeVault} {
+			pf := newTestVaultPriceFeed(priceFeedType)
+			delete(pf.PriceFeeds[tokenA].Answers, "true")
+			_, err := pf.GetPrice(tokenA, true, false, false)
+			assert.ErrorIs(t, err, ErrVaultPriceFeedCouldNotFetchPrice)
+		}
+	})
+
+	t.Run("raised chainlink flag", func(t *testing.T) {
+		t.Parallel()
+
+		pf := newTestVaultPriceFeed(PriceFeedTypeLatestRoundData)
+		pf.ChainlinkFlags = &ChainlinkFlags{Flags: map[string]bool{FlagArbitrumSeqOffline: true}}
+		_, err := pf.GetPrice(tokenA, true, false, false)
+		assert.ErrorIs(t, err, ErrVaultPriceFeedChainlinkFeedsNotUpdated)
+	})
+}
+
+func TestVaultPriceFeed_GetPrice_SpreadAndAdjustment(t *testing.T) {
+	t.Parallel()
+
+	pf := newTestVaultPriceFeed(PriceFeedTypeLatestRoundData)
+	pf.SpreadBasisPoints[tokenA] = big.NewInt(10)
+	pf.AdjustmentBasisPoints[tokenA] = big.NewInt(100)
+	pf.IsAdjustmentAdditive[tokenA] = true
+
+	// 2000 * (1 + 0.1%) * (1 + 1%)
+	maxPrice, err := pf.GetPrice(tokenA, true, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, new(big.Int).Div(usd(2022020), big.NewInt(1000)), maxPrice)
+
+	// 2000 * (1 - 0.1%) * (1 + 1%)
+	minPrice, err := pf.GetPrice(tokenA, false, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, new(big.Int).Div(usd(2017980), big.NewInt(1000)), minPrice)
+}
@@ -1,4 +1,4 @@
-package gmx
+package gmxv1
 
 import (
 	"context"
@@ -20,9 +20,10 @@ type VaultReader struct {
 	vaultMethodUSDG           string
 	vaultMethodUSDGAmounts    string
 	vaultMethodMaxUSDGAmounts string
+	usdgForkName              string
 }
 
-func NewVaultReader(ethrpcClient *ethrpc.Client, usdgForkName string) *VaultReader {
+func NewVaultReader(dexType string, ethrpcClient *ethrpc.Client, usdgForkName string) *VaultReader {
 	if usdgForkName == "" {
 		usdgForkName = "usdg"
 	}
@@ -34,13 +35,14 @@ func NewVaultReader(ethrpcClient *ethrpc.Client, usdgForkName string) *VaultRead
 		abi:          vaultABI,
 		ethrpcClient: ethrpcClient,
 		log: logger.WithFields(logger.Fields{
-			"liquiditySource": DexTypeGmx,
+			"liquiditySource": dexType,
 			"reader":          "VaultReader",
 		}),
 
 		vaultMethodUSDG:           replaceUsdgForkName("usdg", usdgForkName),
 		vaultMethodUSDGAmounts:    replaceUsdgForkName("usdgAmounts", usdgForkName),
 		vaultMethodMaxUSDGAmounts: replaceUsdgForkName("maxUsdgAmounts", usdgForkName),
+		usdgForkName:              usdgForkName,
 	}
 }
 
@@ -54,6 +56,7 @@ func replaceUsdgForkName(s string, usdgForkName string) string {
 // Read reads all data required for finding route
 func (r *VaultReader) Read(ctx context.Context, address string) (*Vault, error) {
 	vault := NewVault()
+	vault.USDGForkName = r.usdgForkName
 
 	if err := r.readData(ctx, address, vault); err != nil {
 		r.log.Errorf("error when read data: %s", err)
@@ -1,4 +1,4 @@
-package gmx
+package gmxv1
 
 import (
 	"context"
@@ -22,33 +22,63 @@ type VaultScanner struct {
 	log                   logger.Logger
 }
 
+// VaultScannerOption replaces a reader of a VaultScanner, for the forks whose contracts differ from GMX's.
+type VaultScannerOption func(*VaultScanner)
+
+func WithVaultReader(reader IVaultReader) VaultScannerOption {
+	return func(vs *VaultScanner) { vs.vaultReader = reader }
+}
+
+func WithVaultPriceFeedReader(reader IVaultPriceFeedReader) VaultScannerOption {
+	return func(vs *VaultScanner) { vs.vaultPriceFeedReader = reader }
+}
+
+func WithFastPriceFeedV2Reader(reader IFastPriceFeedV2Reader) VaultScannerOption {
+	return func(vs *VaultScanner) { vs.fastPriceFeedV2Reader = reader }
+}
+
+func WithPriceFeedReader(reader IPriceFeedReader) VaultScannerOption {
+	return func(vs *VaultScanner) { vs.priceFeedReader = reader }
+}
+
+// NewVaultScanner returns the scanner of a fork, logging as its dexType.
 func NewVaultScanner(
+	dexType string,
 	config *Config,
 	ethrpcClient *ethrpc.Client,
+	opts ...VaultScannerOption,
 ) *VaultScanner {
-	return &VaultScanner{
+	vs := &VaultScanner{
 		config:                config,
-		vaultReader:           NewVaultReader(ethrpcClient, config.UsdgForkName),
-		vaultPriceFeedReader:  NewVaultPriceFeedReaderWithParam(ethrpcClient, config.PriceFeedType),
-		fastPriceFeedV1Reader: NewFastPriceFeedV1Reader(ethrpcClient),
-		fastPriceFeedV2Reader: NewFastPriceFeedV2Reader(ethrpcClient),
-		priceFeedReader:       NewPriceFeedReaderWithParam(ethrpcClient, config.PriceFeedType),
-		usdgReader:            NewUSDGReader(ethrpcClient),
-		chainlinkFlagsReader:  NewChainlinkFlagsReader(ethrpcClient),
-		pancakePairReader:     NewPancakePairReader(ethrpcClient),
+		vaultReader:           NewVaultReader(dexType, ethrpcClient, config.UsdgForkName),
+		vaultPriceFeedReader:  NewVaultPriceFeedReaderWithParam(dexType, ethrpcClient, config.PriceFeedType),
+		fastPriceFeedV1Reader: NewFastPriceFeedV1Reader(dexType, ethrpcClient),
+		fastPriceFeedV2Reader: NewFastPriceFeedV2Reader(dexType, ethrpcClient),
+		priceFeedReader:       NewPriceFeedReaderWithParam(dexType, ethrpcClient, config.PriceFeedType),
+		usdgReader:            NewUSDGReader(dexType, ethrpcClient),
+		chainlinkFlagsReader:  NewChainlinkFlagsReader(dexType, ethrpcClient),
+		pancakePairReader:     NewPancakePairReader(dexType, ethrpcClient),
 		log: logger.WithFields(logger.Fields{
-			"liquiditySource": DexTypeGmx,
+			"liquiditySource": dexType,
 			"scanner":         "VaultScanner",
 		}),
 	}
+	for _, opt := range opts {
+		opt(vs)
+	}
+	return vs
 }
 
-func (vs *VaultScanner) getVault(ctx context.Context, address string) (*Vault, error) {
+// GetVault reads the vault at address, with its USDG and price feeds.
+func (vs *VaultScanner) GetVault(ctx context.Context, address string) (*Vault, error) {
 	vault, err := vs.vaultReader.Read(ctx, address)
 	if err != nil {
 		vs.log.Errorf("error when vaultReader read: %s", err)
 		return nil, err
 	}
+	if validBlockNumber(vault.BlockNumber) {
+		ctx = withBlockNumber(ctx, vault.BlockNumber)
+	}
 
 	usdg, err := vs.usdgReader.Read(ctx, vault.USDGAddress.String())
 	if err != nil {
@@ -58,7 +88,7 @@ func (vs *VaultScanner) getVault(ctx context.Context, address string) (*Vault, e
 
 	vault.USDG = usdg
 
-	vaultPriceFeed, err := vs.getVaultPriceFeed(ctx, vault.PriceFeedAddress.String(), vault.WhitelistedTokens)
+	vaultPriceFeed, err := vs.GetVaultPriceFeed(ctx, vault.PriceFeedAddress.String(), vault.WhitelistedTokens)
 	if err != nil {
 		vs.log.Errorf("error when get vaultPriceFeed: %s", err)
 		return nil, err
@@ -71,7 +101,8 @@ func (vs *VaultScanner) getVault(ctx context.Context, address string) (*Vault, e
 
 // ================================================================================
 
-func (vs *VaultScanner) getVaultPriceFeed(ctx context.Context, address string, tokens []string) (*VaultPriceFeed,
+// GetVaultPriceFeed reads the vault price feed at address, with the price feeds of tokens.
+func (vs *VaultScanner) GetVaultPriceFeed(ctx context.Context, address string, tokens []string) (*VaultPriceFeed,
 	error) {
 	vaultPriceFeed, err := vs.vaultPriceFeedReader.Read(ctx, address, tokens)
 	if err != nil {
@@ -114,21 +145,23 @@ func (vs *VaultScanner) getVaultPriceFeed(ctx context.Context, address string, t
 		vaultPriceFeed.ETHBNB = ethBnb
 	}
 
-	secondaryPriceFeedVersion := vs.getSecondaryPriceFeedVersion()
+	if !vs.config.SkipUnsetSecondaryPriceFeed || !eth.IsZeroAddress(vaultPriceFeed.SecondaryPriceFeedAddress) {
+		secondaryPriceFeedVersion := vs.getSecondaryPriceFeedVersion()
 
-	vaultPriceFeed.SecondaryPriceFeedVersion = int(secondaryPriceFeedVersion)
+		vaultPriceFeed.SecondaryPriceFeedVersion = int(secondaryPriceFeedVersion)
 
-	fastPriceFeed, err := vs.getFastPriceFeed(
-		ctx,
-		secondaryPriceFeedVersion,
-		vaultPriceFeed.SecondaryPriceFeedAddress.String(),
-		tokens,
-	)
-	if err != nil {
-		return nil, err
-	}
+		fastPriceFeed, err := vs.getFastPriceFeed(
+			ctx,
+			secondaryPriceFeedVersion,
+			vaultPriceFeed.SecondaryPriceFeedAddress.String(),
+			tokens,
+		)
+		if err != nil {
+			return nil, err
+		}
 
-	vaultPriceFeed.SecondaryPriceFeed = fastPriceFeed
+		vaultPriceFeed.SecondaryPriceFeed = fastPriceFeed
+	}
 
 	vaultPriceFeed.PriceFeeds, err = vs.getPriceFeeds(ctx, vaultPriceFeed)
 	if err != nil {
@@ -142,7 +175,7 @@ func (vs *VaultScanner) getPriceFeeds(
 	ctx context.Context,
 	vaultPriceFeed *VaultPriceFeed,
 ) (map[string]*PriceFeed, error) {
-	if vaultPriceFeed.PriceFeedType == PriceFeedTypeDirect {
+	if vaultPriceFeed.PriceFeedType == PriceFeedTypeDirect || vaultPriceFeed.PriceFeedType == PriceFeedTypeVault {
 		return vaultPriceFeed.PriceFeeds, nil
 	}
 
@@ -153,6 +186,11 @@ func (vs *VaultScanner) getPriceFeeds(
 	priceFeeds := make(map[string]*PriceFeed, len(vaultPriceFeed.PriceFeedsAddresses))
 
 	for tokenAddress, priceFeedAddress := range vaultPriceFeed.PriceFeedsAddresses {
+		if eth.IsZeroAddress(priceFeedAddress) {
+			vs.log.Warnf("priceFeedAddress for token %s is zero address", tokenAddress)
+			continue
+		}
+
 		priceFeed, err := vs.priceFeedReader.Read(ctx, priceFeedAddress.String(), roundCount)
 		if err != nil {
 			return nil, err
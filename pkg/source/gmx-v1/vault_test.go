@@ -0,0 +1,42 @@
+package gmxv1
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVault_JSON_USDGForkName(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		usdgForkName string
+		extra        string
+	}{
+		{"usdg", `{"usdgAmounts":{"0xa":1},"maxUsdgAmounts":{"0xa":2},"usdg":{"address":"0x1","totalSupply":3}}`},
+		{"usdm", `{"usdmAmounts":{"0xa":1},"maxUsdmAmounts":{"0xa":2},"usdm":{"address":"0x1","totalSupply":3}}`},
+		{"usdf", `{"usdfAmounts":{"0xa":1},"maxUsdfAmounts":{"0xa":2},"usdf":{"address":"0x1","totalSupply":3}}`},
+		{"usdq", `{"usdqAmounts":{"0xa":1},"maxUsdqAmounts":{"0xa":2},"usdq":{"address":"0x1","totalSupply":3}}`},
+		{"usdb", `{"usdbAmounts":{"0xa":1},"maxUsdbAmounts":{"0xa":2},"usdb":{"address":"0x1","totalSupply":3}}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.usdgForkName, func(t *testing.T) {
+			t.Parallel()
+
+			var vault Vault
+			require.NoError(t, json.Unmarshal([]byte(tc.extra), &vault))
+			assert.Equal(t, tc.usdgForkName, vault.USDGForkName)
+			assert.Equal(t, map[string]*big.Int{"0xa": big.NewInt(1)}, vault.USDGAmounts)
+			assert.Equal(t, map[string]*big.Int{"0xa": big.NewInt(2)}, vault.MaxUSDGAmounts)
+			assert.Equal(t, &USDG{Address: "0x1", TotalSupply: big.NewInt(3)}, vault.USDG)
+
+			extra, err := json.Marshal(&vault)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.extra, string(extra))
+		})
+	}
+}
@@ -1,4 +1,4 @@
-package gmx
+package gmxv1
 
 import (
 	"math/big"
@@ -6,51 +6,45 @@ import (
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 )
 
-// VaultUtils
+// VaultUtils is the IFeeUtils of the vaults charging the swap fees of the vault itself.
 // https://github.com/gmx-io/gmx-contracts/blob/master/contracts/core/VaultUtils.sol
-type VaultUtils struct {
-	vault *Vault `msgpack:"-"`
-}
-
-func NewVaultUtils(vault *Vault) *VaultUtils {
-	return &VaultUtils{
-		vault: vault,
-	}
-}
+type VaultUtils struct{}
 
-func (u *VaultUtils) GetSwapFeeBasisPoints(tokenIn string, tokenOut string, usdgAmount *big.Int) *big.Int {
-	isStableSwap := u.vault.StableTokens[tokenIn] && u.vault.StableTokens[tokenOut]
+func (u *VaultUtils) GetSwapFeeBasisPoints(vault *Vault, tokenIn string, tokenOut string,
+	usdgAmount *big.Int) (*big.Int, error) {
+	isStableSwap := vault.StableTokens[tokenIn] && vault.StableTokens[tokenOut]
 
 	var baseBps *big.Int
 	if isStableSwap {
-		baseBps = u.vault.StableSwapFeeBasisPoints
+		baseBps = vault.StableSwapFeeBasisPoints
 	} else {
-		baseBps = u.vault.SwapFeeBasisPoints
+		baseBps = vault.SwapFeeBasisPoints
 	}
 
 	var taxBps *big.Int
 	if isStableSwap {
-		taxBps = u.vault.StableTaxBasisPoints
+		taxBps = vault.StableTaxBasisPoints
 	} else {
-		taxBps = u.vault.TaxBasisPoints
+		taxBps = vault.TaxBasisPoints
 	}
 
-	feeBasisPoints0 := u.GetFeeBasisPoints(tokenIn, usdgAmount, baseBps, taxBps, true)
-	feeBasisPoints1 := u.GetFeeBasisPoints(tokenOut, usdgAmount, baseBps, taxBps, false)
+	feeBasisPoints0 := u.GetFeeBasisPoints(vault, tokenIn, usdgAmount, baseBps, taxBps, true)
+	feeBasisPoints1 := u.GetFeeBasisPoints(vault, tokenOut, usdgAmount, baseBps, taxBps, false)
 
 	if feeBasisPoints0.Cmp(feeBasisPoints1) > 0 {
-		return feeBasisPoints0
+		return feeBasisPoints0, nil
 	} else {
-		return feeBasisPoints1
+		return feeBasisPoints1, nil
 	}
 }
 
-func (u *VaultUtils) GetFeeBasisPoints(token string, usdgDelta *big.Int, feeBasisPoints *big.Int, taxBasisPoints *big.Int, increment bool) *big.Int {
-	if !u.vault.HasDynamicFees {
+func (u *VaultUtils) GetFeeBasisPoints(vault *Vault, token string, usdgDelta *big.Int, feeBasisPoints *big.Int,
+	taxBasisPoints *big.Int, increment bool) *big.Int {
+	if !vault.HasDynamicFees {
 		return feeBasisPoints
 	}
 
-	initialAmount := u.vault.USDGAmounts[token]
+	initialAmount := vault.USDGAmounts[token]
 	nextAmount := new(big.Int).Add(initialAmount, usdgDelta)
 
 	if !increment {
@@ -61,7 +55,7 @@ func (u *VaultUtils) GetFeeBasisPoints(token string, usdgDelta *big.Int, feeBasi
 		}
 	}
 
-	targetAmount := u.vault.GetTargetUSDGAmount(token)
+	targetAmount := vault.GetTargetUSDGAmount(token)
 
 	if targetAmount.Cmp(bignumber.ZeroBI) == 0 {
 		return feeBasisPoints
@@ -1,9 +1,7 @@
 package gmx
 
-type Config struct {
-	DexID                   string        `json:"dexID"`
-	VaultAddress            string        `json:"vaultAddress"`
-	UseSecondaryPriceFeedV1 bool          `json:"useSecondaryPriceFeedV1"`
-	PriceFeedType           PriceFeedType `json:"priceFeedType"`
-	UsdgForkName            string        `json:"usdgForkName"`
-}
+import (
+	gmxv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-v1"
+)
+
+type Config = gmxv1.Config
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	gmxv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-v1"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/testutil"
@@ -228,7 +229,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 			expectedAmountOut: nil,
 			expectedFee:       nil,
 			expectedGas:       0,
-			expectedErr:       ErrVaultSwapsNotEnabled,
+			expectedErr:       gmxv1.ErrVaultSwapsNotEnabled,
 		},
 		{
 			name: "it should return ErrVaultPriceFeedInvalidPriceFeed when price feed is invalid v1",
@@ -295,7 +296,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 			expectedAmountOut: nil,
 			expectedFee:       nil,
 			expectedGas:       0,
-			expectedErr:       ErrVaultPriceFeedInvalidPriceFeed,
+			expectedErr:       gmxv1.ErrVaultPriceFeedInvalidPriceFeed,
 		},
 		{
 			name: "it should return ErrVaultPriceFeedInvalidPriceFeed when price feed is invalid v2",
@@ -362,7 +363,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 			expectedAmountOut: nil,
 			expectedFee:       nil,
 			expectedGas:       0,
-			expectedErr:       ErrVaultPriceFeedInvalidPriceFeed,
+			expectedErr:       gmxv1.ErrVaultPriceFeedInvalidPriceFeed,
 		},
 	}
 
@@ -515,7 +516,7 @@ func TestPool_UpdateBalance(t *testing.T) {
 			}
 			poolSim.UpdateBalance(params)
 
-			assert.Equal(t, tc.expectedUSDGAmounts, poolSim.vault.USDGAmounts)
+			assert.Equal(t, tc.expectedUSDGAmounts, poolSim.Vault().USDGAmounts)
 		})
 	}
 }
@@ -524,7 +525,7 @@ func TestPool_CanSwapTo(t *testing.T) {
 	t.Parallel()
 	t.Run("it should return correct swappable tokens", func(t *testing.T) {
 		poolSim := PoolSimulator{
-			vault: &Vault{
+			PoolSimulator: gmxv1.NewPoolSimulatorWithVault(entity.Pool{}, &gmxv1.Vault{
 				WhitelistedTokens: []string{
 					"0x17fc002b466eec40dae837fc4be5c67993ddbd6f",
 					"0x2f2a2543b76a4166549f7aab2e75bef0aefc5b0f",
@@ -536,7 +537,7 @@ func TestPool_CanSwapTo(t *testing.T) {
 					"0xfea7a6a0b346362bf88a9e4a88416b77a57d6c2a",
 					"0xff970a61a04b1ca14834a43f5de4533ebddb5cc8",
 				},
-			},
+			}, nil),
 		}
 
 		tokens := poolSim.CanSwapTo("0xff970a61a04b1ca14834a43f5de4533ebddb5cc8")
@@ -557,7 +558,7 @@ func TestPool_CanSwapTo(t *testing.T) {
 func TestPool_GetMetaInfo(t *testing.T) {
 	t.Parallel()
 	t.Run("it should return block metadata", func(t *testing.T) {
-		poolSim := PoolSimulator{}
+		poolSim := PoolSimulator{PoolSimulator: &gmxv1.PoolSimulator{}}
 
 		assert.Equal(t, pool.MetaInfo{}, poolSim.GetMetaInfo("0xda10009cbd5d07dd0cecc66161fc93d7c9000da1",
 			"0xff970a61a04b1ca14834a43f5de4533ebddb5cc8"))
@@ -0,0 +1,176 @@
+package limitorder
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/clob"
+)
+
+// sideOrders are the orders of a swap side as clob.ExactOutOrders, recording the orders filled by a walk and the fees
+// charged on them.
+type sideOrders struct {
+	p        *PoolSimulator
+	orderIDs []int64
+	limit    pool.SwapLimit
+
+	// we need to update maker's remaining balance in 2 places:
+	// - in UpdateBalance: mainly to deal with case where maker has orders with same makerAsset but different takerAsset
+	// - when simulating filling each order here: we cannot do the same as in kyber-pmm (simulating first then check inventory limit at the end)
+	//				because in LO we have multiple makers, and also because we still need to allow orders that have part of the balance available
+	//		the problem is that in this func we cannot update the limit,
+	//		so we'll use this map to track filled amount for each maker, then subtract from the original balance, to have the remaining balance available
+	filledMakingAmountByMaker map[string]*big.Int
+
+	totalMakingAmount  *big.Int
+	totalFeeAmount     *big.Int
+	filledTakingAmount *big.Int
+	// partialMakingAmount is the making amount, before fee, of the order filled in part by an exact out walk.
+	partialMakingAmount *big.Int
+	filledOrders        []*FilledOrderInfo
+}
+
+func (p *PoolSimulator) newSideOrders(orderIDs []int64, limit pool.SwapLimit) *sideOrders {
+	return &sideOrders{
+		p:                         p,
+		orderIDs:                  orderIDs,
+		limit:                     limit,
+		filledMakingAmountByMaker: make(map[string]*big.Int, len(p.allMakersBalanceAllowance)),
+		totalMakingAmount:         new(big.Int),
+		totalFeeAmount:            new(big.Int),
+		filledTakingAmount:        new(big.Int),
+		filledOrders:              []*FilledOrderInfo{},
+	}
+}
+
+func (o *sideOrders) Len() int {
+	return len(o.orderIDs)
+}
+
+// remainingAmount returns order i with what is left of it, ErrSkipOrder if nothing is.
+func (o *sideOrders) remainingAmount(i int) (*order, *big.Int, *big.Int, error) {
+	order, ok := o.p.ordersMapping[o.orderIDs[i]]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("order %d is not existed in pool", o.orderIDs[i])
+	}
+
+	remainingMakingAmountWei, remainingTakingAmountWei := order.RemainingAmount(o.limit, o.filledMakingAmountByMaker)
+	if remainingMakingAmountWei.Sign() <= 0 || remainingTakingAmountWei.Sign() <= 0 {
+		return nil, nil, nil, clob.ErrSkipOrder
+	}
+	return order, remainingMakingAmountWei, remainingTakingAmountWei, nil
+}
+
+func (o *sideOrders) FillIn(i int, amountIn *uint256.Int) (clob.Level, bool, error) {
+	order, remainingMakingAmountWei, remainingTakingAmountWei, err := o.remainingAmount(i)
+	if err != nil {
+		return clob.Level{}, false, err
+	}
+
+	// ideally we should return totalFeeAmountWei in takerAsset here
+	// but for now it's not used, and we might get mixed up with makerAsset fee, so will ignore for now
+	totalAmountInAfterFee, _ := o.p.calcTakerAssetFeeAmountExactIn(order, amountIn.ToBig())
+	if totalAmountInAfterFee.Sign() <= 0 {
+		return clob.Level{}, false, clob.ErrSkipOrder
+	}
+
+	o.totalMakingAmount = new(big.Int).Add(o.totalMakingAmount, remainingMakingAmountWei)
+
+	if remainingTakingAmountWei.Cmp(totalAmountInAfterFee) >= 0 {
+		filledTakingAmountWei := totalAmountInAfterFee
+		filledMakingAmountWei := new(big.Int).Div(
+			new(big.Int).Mul(filledTakingAmountWei, order.MakingAmount),
+			order.TakingAmount,
+		) // filledMakingAmountWei = filledTakingAmountWei * order.MakingAmount / order.TakingAmount
+
+		// order too small
+		if filledMakingAmountWei.Sign() <= 0 {
+			return clob.Level{}, false, clob.ErrSkipOrder
+		}
+
+		feeAmountWeiByOrder := o.fill(order, filledTakingAmountWei, filledMakingAmountWei,
+			o.p.calcMakerAssetFeeAmount(order, filledMakingAmountWei))
+		actualAmountOut := new(big.Int).Sub(filledMakingAmountWei, feeAmountWeiByOrder)
+		return clob.Level{In: amountIn.Clone(), Out: uint256.MustFromBig(actualAmountOut)}, true, nil
+	}
+
+	_, takerAssetFee := o.p.calcTakerAssetFeeAmountExactOut(order, remainingTakingAmountWei)
+	feeAmountWeiByOrder := o.fill(order, remainingTakingAmountWei, remainingMakingAmountWei,
+		o.p.calcMakerAssetFeeAmount(order, remainingMakingAmountWei))
+	actualAmountIn := new(big.Int).Add(remainingTakingAmountWei, takerAssetFee)
+	actualAmountOut := new(big.Int).Sub(remainingMakingAmountWei, feeAmountWeiByOrder)
+	return clob.Level{In: uint256.MustFromBig(actualAmountIn), Out: uint256.MustFromBig(actualAmountOut)}, false, nil
+}
+
+func (o *sideOrders) FillOut(i int, amountOut *uint256.Int) (clob.Level, bool, error) {
+	order, remainingMakingAmountWei, remainingTakingAmountWei, err := o.remainingAmount(i)
+	if err != nil {
+		return clob.Level{}, false, err
+	}
+
+	o.totalMakingAmount = new(big.Int).Add(o.totalMakingAmount, remainingMakingAmountWei)
+
+	totalAmountOutBeforeFee, _ := o.p.calcMakerAssetAmountBeforeFee(order, amountOut.ToBig())
+
+	if remainingMakingAmountWei.Cmp(totalAmountOutBeforeFee) >= 0 {
+		filledMakingAmountWei := totalAmountOutBeforeFee
+		filledTakingAmountWei := divCeil(
+			new(big.Int).Mul(totalAmountOutBeforeFee, order.TakingAmount),
+			order.MakingAmount,
+		) // filledTakingAmountWei =  ceil(takingAmount * totalAmountOutBeforeFee / makingAmount)
+
+		// order too small
+		if filledTakingAmountWei.Sign() == 0 {
+			return clob.Level{}, false, clob.ErrSkipOrder
+		}
+
+		actualAmountIn, feeAmountWeiByOrder := o.p.calcTakerAssetFeeAmountExactOut(order, filledTakingAmountWei)
+		o.fill(order, filledTakingAmountWei, filledMakingAmountWei, feeAmountWeiByOrder)
+		o.partialMakingAmount = filledMakingAmountWei
+		return clob.Level{In: uint256.MustFromBig(actualAmountIn), Out: amountOut.Clone()}, true, nil
+	}
+
+	_, takerAssetFee := o.p.calcTakerAssetFeeAmountExactOut(order, remainingTakingAmountWei)
+	o.fill(order, remainingTakingAmountWei, remainingMakingAmountWei, takerAssetFee)
+	actualAmountIn := new(big.Int).Add(remainingTakingAmountWei, takerAssetFee)
+	return clob.Level{In: uint256.MustFromBig(actualAmountIn), Out: uint256.MustFromBig(remainingMakingAmountWei)}, false, nil
+}
+
+// fill records order as filled with the given amounts and fee, returning the fee.
+func (o *sideOrders) fill(order *order, filledTakingAmount, filledMakingAmount, feeAmount *big.Int) *big.Int {
+	o.totalFeeAmount.Add(o.totalFeeAmount, feeAmount)
+	o.filledTakingAmount.Add(o.filledTakingAmount, filledTakingAmount)
+	o.filledOrders = append(o.filledOrders,
+		newFilledOrderInfo(order, filledTakingAmount.String(), filledMakingAmount.String(), feeAmount.String()))
+	addFilledMakingAmount(o.filledMakingAmountByMaker, order.Maker, filledMakingAmount)
+	return feeAmount
+}
+
+// addFallbackOrders adds the orders after the one a walk stopped at until their remaining making amount reaches
+// threshold.
+//
+// Currently, when Aggregator finds route and returns some orders and sends them to the smart contract to execute.
+// We will often meet edge cases that these orders can be fulfilled by a trading bot or taker on Aggregator.
+// From that, the estimated amount out and filled orders are not correct. So we need to add more orders when sending to SC to the executor.
+// In this case, we will some orders util total MakingAmount(remainMakingAmount)/estimated amountOut >= 1.3 (130%)
+func (o *sideOrders) addFallbackOrders(last int, threshold *big.Float) {
+	for j := last + 1; j < len(o.orderIDs); j++ {
+		if new(big.Float).SetInt(o.totalMakingAmount).Cmp(threshold) >= 0 {
+			break
+		}
+		order, ok := o.p.ordersMapping[o.orderIDs[j]]
+		if !ok {
+			continue
+		}
+		remainingMakingAmountWei, remainingTakingAmountWei := order.RemainingAmount(o.limit, o.filledMakingAmountByMaker)
+		if remainingMakingAmountWei.Sign() <= 0 || remainingTakingAmountWei.Sign() <= 0 {
+			continue
+		}
+
+		o.totalMakingAmount = new(big.Int).Add(o.totalMakingAmount, remainingMakingAmountWei)
+		o.filledOrders = append(o.filledOrders, newFallbackOrderInfo(order))
+	}
+}
@@ -8,12 +8,14 @@ import (
 	"github.com/KyberNetwork/logger"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/goccy/go-json"
+	"github.com/holiman/uint256"
 	"github.com/samber/lo"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/swaplimit"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/clob"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
 )
 
@@ -228,109 +230,26 @@ func (p *PoolSimulator) calcAmountOutWithSwapInfo(swapSide SwapSide, tokenAmount
 		return big.NewInt(0), SwapInfo{}, nil, nil
 	}
 
-	totalAmountOutWei := big.NewInt(0)
-	totalAmountIn := new(big.Int).Set(tokenAmountIn.Amount)
-
-	swapInfo := SwapInfo{
-		FilledOrders: []*FilledOrderInfo{},
-		SwapSide:     swapSide,
-		AmountIn:     tokenAmountIn.Amount.String(),
+	orders := p.newSideOrders(orderIDs, limit)
+	fill, err := clob.FillOrdersExactIn(orders, uint256.MustFromBig(tokenAmountIn.Amount))
+	if err != nil {
+		return nil, SwapInfo{}, nil, err
 	}
-	isFulfillAmountIn := false
-	totalFeeAmountWei := new(big.Int)
-
-	// we need to update maker's remaining balance in 2 places:
-	// - in UpdateBalance: mainly to deal with case where maker has orders with same makerAsset but different takerAsset
-	// - when simulating filling each order here: we cannot do the same as in kyber-pmm (simulating first then check inventory limit at the end)
-	//				because in LO we have multiple makers, and also because we still need to allow orders that have part of the balance available
-	//		the problem is that in this func we cannot update the limit,
-	//		so we'll use this map to track filled amount for each maker, then subtract from the original balance, to have the remaining balance available
-	filledMakingAmountByMaker := make(map[string]*big.Int, len(p.allMakersBalanceAllowance))
-
-	totalMakingAmountWei := new(big.Int)
-	for i, orderID := range orderIDs {
-		order, ok := p.ordersMapping[orderID]
-		if !ok {
-			return nil, swapInfo, nil, fmt.Errorf("order %d is not existed in pool", orderID)
-		}
-
-		// Get remaining making amount, taking amount
-		remainingMakingAmountWei, remainingTakingAmountWei := order.RemainingAmount(limit, filledMakingAmountByMaker)
-		if remainingMakingAmountWei.Sign() <= 0 || remainingTakingAmountWei.Sign() <= 0 {
-			continue
-		}
-
-		// ideally we should return totalFeeAmountWei in takerAsset here
-		// but for now it's not used, and we might get mixed up with makerAsset fee, so will ignore for now
-		totalAmountInAfterFee, _ := p.calcTakerAssetFeeAmountExactIn(order, totalAmountIn)
-		if totalAmountInAfterFee.Sign() <= 0 {
-			continue
-		}
-
-		totalMakingAmountWei = new(big.Int).Add(totalMakingAmountWei, remainingMakingAmountWei)
-
-		if remainingTakingAmountWei.Cmp(totalAmountInAfterFee) >= 0 {
-			filledTakingAmountWei := totalAmountInAfterFee
-			filledMakingAmountWei := new(big.Int).Div(
-				new(big.Int).Mul(filledTakingAmountWei, order.MakingAmount),
-				order.TakingAmount,
-			) // filledMakingAmountWei = filledTakingAmountWei * order.MakingAmount / order.TakingAmount
-
-			// order too small
-			if filledMakingAmountWei.Sign() <= 0 {
-				continue
-			}
-
-			feeAmountWeiByOrder := p.calcMakerAssetFeeAmount(order, filledMakingAmountWei)
-			totalFeeAmountWei.Add(totalFeeAmountWei, feeAmountWeiByOrder)
-			actualAmountOut := new(big.Int).Sub(filledMakingAmountWei, feeAmountWeiByOrder)
-			totalAmountOutWei.Add(totalAmountOutWei, actualAmountOut)
-			filledOrderInfo := newFilledOrderInfo(order, filledTakingAmountWei.String(), filledMakingAmountWei.String(), feeAmountWeiByOrder.String())
-			swapInfo.FilledOrders = append(swapInfo.FilledOrders, filledOrderInfo)
-			isFulfillAmountIn = true
-			addFilledMakingAmount(filledMakingAmountByMaker, order.Maker, filledMakingAmountWei)
-
-			// Currently, when Aggregator finds route and returns some orders and sends them to the smart contract to execute.
-			// We will often meet edge cases that these orders can be fulfilled by a trading bot or taker on Aggregator.
-			// From that, the estimated amount out and filled orders are not correct. So we need to add more orders when sending to SC to the executor.
-			// In this case, we will some orders util total MakingAmount(remainMakingAmount)/estimated amountOut >= 1.3 (130%)
-			totalAmountOutWeiBigFloat := new(big.Float).SetInt(totalAmountOutWei)
-			// threshold = totalAmountOutWei * FallbackPercentageOfTotalMakingAmount
-			threshold := new(big.Float).Mul(totalAmountOutWeiBigFloat, FallbackPercentageOfTotalMakingAmount)
-			for j := i + 1; j < len(orderIDs); j++ {
-				if new(big.Float).SetInt(totalMakingAmountWei).Cmp(threshold) >= 0 {
-					break
-				}
-				order, ok := p.ordersMapping[orderIDs[j]]
-				if !ok {
-					continue
-				}
-				remainingMakingAmountWei, remainingTakingAmountWei := order.RemainingAmount(limit, filledMakingAmountByMaker)
-				if remainingMakingAmountWei.Sign() <= 0 || remainingTakingAmountWei.Sign() <= 0 {
-					continue
-				}
-
-				totalMakingAmountWei = new(big.Int).Add(totalMakingAmountWei, remainingMakingAmountWei)
-				filledOrderInfo := newFallbackOrderInfo(order)
-				swapInfo.FilledOrders = append(swapInfo.FilledOrders, filledOrderInfo)
-			}
-			break
-		}
-		_, takerAssetFee := p.calcTakerAssetFeeAmountExactOut(order, remainingTakingAmountWei)
-		totalAmountIn.Sub(totalAmountIn, takerAssetFee)
-		totalAmountIn.Sub(totalAmountIn, remainingTakingAmountWei)
-		feeAmountWeiByOrder := p.calcMakerAssetFeeAmount(order, remainingMakingAmountWei)
-		actualAmountOut := new(big.Int).Sub(remainingMakingAmountWei, feeAmountWeiByOrder)
-		totalAmountOutWei.Add(totalAmountOutWei, actualAmountOut)
-		totalFeeAmountWei.Add(totalFeeAmountWei, feeAmountWeiByOrder)
-		filledOrderInfo := newFilledOrderInfo(order, remainingTakingAmountWei.String(), remainingMakingAmountWei.String(), feeAmountWeiByOrder.String())
-		swapInfo.FilledOrders = append(swapInfo.FilledOrders, filledOrderInfo)
-		addFilledMakingAmount(filledMakingAmountByMaker, order.Maker, remainingMakingAmountWei)
-	}
-	if !isFulfillAmountIn {
+	last := fill.Last()
+	if last == nil {
 		return nil, SwapInfo{}, nil, ErrCannotFulfillAmountIn
 	}
-	return totalAmountOutWei, swapInfo, totalFeeAmountWei, nil
+
+	totalAmountOutWei := fill.AmountOut.ToBig()
+	// threshold = totalAmountOutWei * FallbackPercentageOfTotalMakingAmount
+	threshold := new(big.Float).Mul(new(big.Float).SetInt(totalAmountOutWei), FallbackPercentageOfTotalMakingAmount)
+	orders.addFallbackOrders(last.Index, threshold)
+
+	return totalAmountOutWei, SwapInfo{
+		FilledOrders: orders.filledOrders,
+		SwapSide:     swapSide,
+		AmountIn:     tokenAmountIn.Amount.String(),
+	}, orders.totalFeeAmount, nil
 }
 
 // feeAmount = (params.makingAmount * params.order.makerTokenFeePercent + BPS - 1) / BPS
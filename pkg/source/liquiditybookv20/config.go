@@ -2,13 +2,16 @@ package liquiditybookv20
 
 import (
 	"net/http"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/reorg"
 )
 
 type Config struct {
-	DexID              string      `json:"dexID"`
-	FactoryAddress     string      `json:"factoryAddress"`
-	NewPoolLimit       int         `json:"newPoolLimit"`
-	SubgraphAPI        string      `json:"subgraphAPI"`
-	SubgraphHeaders    http.Header `json:"subgraphHeaders"`
-	AllowSubgraphError bool        `json:"allowSubgraphError"`
+	DexID              string       `json:"dexID"`
+	FactoryAddress     string       `json:"factoryAddress"`
+	NewPoolLimit       int          `json:"newPoolLimit"`
+	SubgraphAPI        string       `json:"subgraphAPI"`
+	SubgraphHeaders    http.Header  `json:"subgraphHeaders"`
+	AllowSubgraphError bool         `json:"allowSubgraphError"`
+	Reorg              reorg.Config `json:"reorg"`
 }
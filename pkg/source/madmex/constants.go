@@ -1,20 +1,8 @@
 package madmex
 
-import "math/big"
-
 const DexTypeMadmex = "madmex"
 
-const flagArbitrumSeqOffline = "0xa438451d6458044c3c8cd2f6f31c91ac882a6d91"
-
-var secondaryPriceFeedVersionByChainID = map[ChainID]SecondaryPriceFeedVersion{
-	POLYGON: SecondaryPriceFeedVersion1,
+// useSecondaryPriceFeedV1ByChainID tells the chains whose vaults use a FastPriceFeedV1, others using a FastPriceFeedV2.
+var useSecondaryPriceFeedV1ByChainID = map[ChainID]bool{
+	POLYGON: true,
 }
-
-var (
-	DefaultGas             = Gas{Swap: 286524}
-	BasisPointsDivisor     = big.NewInt(10000)
-	PricePrecision         = new(big.Int).Exp(big.NewInt(10), big.NewInt(30), nil)
-	USDGDecimals           = big.NewInt(18)
-	OneUSD                 = PricePrecision
-	FlagArbitrumSeqOffline = "0xa438451d6458044c3c8cd2f6f31c91ac882a6d91"
-)
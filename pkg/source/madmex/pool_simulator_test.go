@@ -101,12 +101,12 @@ func TestPoolSimulator_UpdateBalance(t *testing.T) {
 
 			fmt.Println(p.Info.Reserves)
 			for i, expPoolAmount := range tc.expPoolAmounts {
-				tok := p.vault.WhitelistedTokens[i]
-				assert.Equal(t, bignumber.NewBig10(expPoolAmount), p.vault.PoolAmounts[tok])
+				tok := p.Vault().WhitelistedTokens[i]
+				assert.Equal(t, bignumber.NewBig10(expPoolAmount), p.Vault().PoolAmounts[tok])
 			}
 			for i, expUsdg := range tc.expUsdg {
-				tok := p.vault.WhitelistedTokens[i]
-				assert.Equal(t, bignumber.NewBig10(expUsdg), p.vault.USDGAmounts[tok])
+				tok := p.Vault().WhitelistedTokens[i]
+				assert.Equal(t, bignumber.NewBig10(expUsdg), p.Vault().USDGAmounts[tok])
 			}
 		})
 	}
@@ -1,80 +1,14 @@
 package madmex
 
 import (
-	"context"
-	"fmt"
-	"time"
-
 	"github.com/KyberNetwork/ethrpc"
-	"github.com/KyberNetwork/logger"
-	"github.com/goccy/go-json"
 
-	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
-	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	gmxv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-v1"
 	pooltrack "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/tracker"
 )
 
-type PoolTracker struct {
-	config       *Config
-	ethrpcClient *ethrpc.Client
-}
-
 var _ = pooltrack.RegisterFactoryCE(DexTypeMadmex, NewPoolTracker)
 
-func NewPoolTracker(
-	cfg *Config,
-	ethrpcClient *ethrpc.Client,
-) (*PoolTracker, error) {
-	return &PoolTracker{
-		config:       cfg,
-		ethrpcClient: ethrpcClient,
-	}, nil
-}
-
-func (d *PoolTracker) GetNewPoolState(
-	ctx context.Context,
-	p entity.Pool,
-	_ pool.GetNewPoolStateParams,
-) (entity.Pool, error) {
-	log := logger.WithFields(logger.Fields{
-		"liquiditySource": DexTypeMadmex,
-		"poolAddress":     p.Address,
-	})
-	log.Info("Start getting new state of pool")
-
-	vault, err := NewVaultScanner(ChainID(d.config.ChainID), d.ethrpcClient).getVault(ctx, p.Address)
-	if err != nil {
-		log.Errorf("get vault failed: %v", err)
-		return entity.Pool{}, fmt.Errorf("get vault failed, pool: %s, err: %v", p.Address, err)
-	}
-
-	poolTokens := make([]*entity.PoolToken, 0, len(vault.WhitelistedTokens))
-	reserves := make(entity.PoolReserves, 0, len(vault.WhitelistedTokens))
-	for _, token := range vault.WhitelistedTokens {
-		poolTokens = append(poolTokens, &entity.PoolToken{
-			Address:   token,
-			Swappable: true,
-		})
-		reserves = append(reserves, vault.PoolAmounts[token].String())
-	}
-
-	extra := Extra{Vault: vault}
-
-	extraBytes, err := json.Marshal(extra)
-	if err != nil {
-		log.Errorf("marshal extra failed: %v", err)
-		return entity.Pool{}, err
-	}
-
-	p.Extra = string(extraBytes)
-	if vault.BlockNumber != nil {
-		p.BlockNumber = vault.BlockNumber.Uint64()
-	}
-	p.Reserves = reserves
-	p.Tokens = poolTokens
-	p.Timestamp = time.Now().Unix()
-
-	log.Info("Finish getting new state")
-
-	return p, nil
+func NewPoolTracker(cfg *Config, ethrpcClient *ethrpc.Client) (*gmxv1.PoolTracker, error) {
+	return gmxv1.NewPoolTracker(DexTypeMadmex, NewVaultScanner(cfg, ethrpcClient)), nil
 }
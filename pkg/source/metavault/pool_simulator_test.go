@@ -119,12 +119,12 @@ func TestPoolSimulator_UpdateBalance(t *testing.T) {
 
 			fmt.Println(p.Info.Reserves)
 			for i, expPoolAmount := range tc.expPoolAmounts {
-				tok := p.vault.WhitelistedTokens[i]
-				assert.Equal(t, bignumber.NewBig10(expPoolAmount), p.vault.PoolAmounts[tok])
+				tok := p.Vault().WhitelistedTokens[i]
+				assert.Equal(t, bignumber.NewBig10(expPoolAmount), p.Vault().PoolAmounts[tok])
 			}
 			for i, expUsdm := range tc.expUsdm {
-				tok := p.vault.WhitelistedTokens[i]
-				assert.Equal(t, bignumber.NewBig10(expUsdm), p.vault.USDMAmounts[tok])
+				tok := p.Vault().WhitelistedTokens[i]
+				assert.Equal(t, bignumber.NewBig10(expUsdm), p.Vault().USDGAmounts[tok])
 			}
 		})
 	}
@@ -3,7 +3,3 @@ package metavault
 type VaultAddress struct {
 	Vault string `json:"vault"`
 }
-
-type Extra struct {
-	Vault *Vault `json:"vault"`
-}
@@ -0,0 +1,130 @@
+package pool
+
+import (
+	"context"
+	"math"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+var ErrDepthCurveRange = errors.New("invalid depth curve range")
+
+const (
+	// depthCurveDefaultPoints is the number of sizes DepthCurve samples if not specified.
+	depthCurveDefaultPoints = 20
+	// depthCurveDefaultRange is the ratio of the largest to the smallest default size.
+	depthCurveDefaultRange = 1e6
+)
+
+// DepthPoint is the outcome of swapping one size on a pool.
+type DepthPoint struct {
+	AmountIn  *big.Int
+	AmountOut *big.Int
+	// Price is the effective price amountOut/amountIn, in wei of tokenOut per wei of tokenIn.
+	Price float64
+	// SlippageBps is the shortfall of Price from the spot price of the pool, in basis points.
+	SlippageBps float64
+}
+
+// DepthCurveOptions configures the sizes DepthCurve samples.
+type DepthCurveOptions struct {
+	// MinAmountIn is the smallest size, a millionth of the tokenIn reserve if nil.
+	MinAmountIn *big.Int
+	// MaxAmountIn is the largest size, the tokenIn reserve if nil.
+	MaxAmountIn *big.Int
+	// Points is the number of sizes, 20 if not positive.
+	Points int
+}
+
+// IPoolTickDepth is an optional interface of concentrated-liquidity pool simulators exposing the liquidity of each
+// initialized tick range.
+type IPoolTickDepth interface {
+	GetTickDepth() []TickDepth
+}
+
+// TickDepth is the liquidity active between two consecutive initialized ticks of a concentrated-liquidity pool, and
+// the amounts of token0 and token1 (in wei) it holds at the current price: ranges above the current price only hold
+// token0, ranges below only token1.
+type TickDepth struct {
+	TickLower int
+	TickUpper int
+	Liquidity *big.Int
+	Amount0   float64
+	Amount1   float64
+}
+
+// DepthCurve quotes swapping log-spaced sizes of tokenIn for tokenOut on poolSim and returns the resulting depth curve,
+// by increasing amount in. Sizes that fail or are only partially filled are left out. poolSim is not modified.
+func DepthCurve(ctx context.Context, poolSim IPoolSimulator, tokenIn, tokenOut string,
+	opts DepthCurveOptions) ([]DepthPoint, error) {
+	amountsIn, err := depthCurveAmounts(poolSim, tokenIn, opts)
+	if err != nil {
+		return nil, err
+	}
+	spotPrice, err := SpotPrice(ctx, poolSim, tokenIn, tokenOut)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]DepthPoint, 0, len(amountsIn))
+	for _, amountIn := range amountsIn {
+		amountOut := quoteSpotPriceProbe(ctx, poolSim, tokenIn, tokenOut, amountIn)
+		if amountOut == nil {
+			continue
+		}
+		in, _ := amountIn.Float64()
+		out, _ := amountOut.Float64()
+		price := out / in
+		points = append(points, DepthPoint{
+			AmountIn:    amountIn,
+			AmountOut:   amountOut,
+			Price:       price,
+			SlippageBps: (1 - price/spotPrice) * 1e4,
+		})
+	}
+	return points, nil
+}
+
+// depthCurveAmounts returns opts.Points sizes evenly spaced on a log scale from opts.MinAmountIn to opts.MaxAmountIn.
+func depthCurveAmounts(poolSim IPoolSimulator, tokenIn string, opts DepthCurveOptions) ([]*big.Int, error) {
+	minAmountIn, maxAmountIn := opts.MinAmountIn, opts.MaxAmountIn
+	if minAmountIn == nil || maxAmountIn == nil {
+		idx := poolSim.GetTokenIndex(tokenIn)
+		if idx < 0 || idx >= len(poolSim.GetReserves()) || poolSim.GetReserves()[idx] == nil {
+			return nil, errors.WithMessage(ErrDepthCurveRange, "unknown reserve")
+		}
+		reserve := poolSim.GetReserves()[idx]
+		if maxAmountIn == nil {
+			maxAmountIn = reserve
+		}
+		if minAmountIn == nil {
+			minAmountIn = new(big.Int).Div(reserve, big.NewInt(depthCurveDefaultRange))
+		}
+	}
+	if minAmountIn.Sign() <= 0 || minAmountIn.Cmp(maxAmountIn) > 0 {
+		return nil, errors.WithMessagef(ErrDepthCurveRange, "[%v, %v]", minAmountIn, maxAmountIn)
+	}
+
+	n := opts.Points
+	if n <= 0 {
+		n = depthCurveDefaultPoints
+	}
+	if n == 1 || minAmountIn.Cmp(maxAmountIn) == 0 {
+		return []*big.Int{new(big.Int).Set(maxAmountIn)}, nil
+	}
+	minF, maxF := new(big.Float).SetInt(minAmountIn), new(big.Float).SetInt(maxAmountIn)
+	ratio, _ := new(big.Float).Quo(maxF, minF).Float64()
+	amounts := make([]*big.Int, 0, n)
+	for i := range n - 1 {
+		step := math.Pow(ratio, float64(i)/float64(n-1))
+		amount, _ := new(big.Float).Mul(minF, big.NewFloat(step)).Int(nil)
+		if len(amounts) == 0 || amount.Cmp(amounts[len(amounts)-1]) > 0 {
+			amounts = append(amounts, amount)
+		}
+	}
+	if amounts[len(amounts)-1].Cmp(maxAmountIn) < 0 {
+		amounts = append(amounts, new(big.Int).Set(maxAmountIn))
+	}
+	return amounts, nil
+}
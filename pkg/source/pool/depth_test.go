@@ -0,0 +1,52 @@
+package pool_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+func TestDepthCurve(t *testing.T) {
+	t.Parallel()
+	poolSim := newExactOutTestPool(t)
+	tokens, reserves := poolSim.GetTokens(), poolSim.GetReserves()
+
+	points, err := DepthCurve(ctx, poolSim, tokens[0], tokens[1], DepthCurveOptions{})
+	require.NoError(t, err)
+	require.Len(t, points, 20)
+	assert.Equal(t, new(big.Int).Div(reserves[0], big.NewInt(1e6)), points[0].AmountIn)
+	assert.Equal(t, reserves[0], points[len(points)-1].AmountIn)
+	assert.InDelta(t, 0, points[0].SlippageBps, 0.1)
+	for i := 1; i < len(points); i++ {
+		assert.Equal(t, 1, points[i].AmountIn.Cmp(points[i-1].AmountIn))
+		assert.Equal(t, 1, points[i].AmountOut.Cmp(points[i-1].AmountOut))
+		assert.Less(t, points[i].Price, points[i-1].Price)
+		assert.Greater(t, points[i].SlippageBps, points[i-1].SlippageBps)
+	}
+	// swapping x times the reserve in on a constant product pool divides the price by 1+x
+	assert.InDelta(t, (1-1/(1+0.9975))*1e4, points[len(points)-1].SlippageBps, 1e-6)
+
+	points, err = DepthCurve(ctx, poolSim, tokens[1], tokens[0], DepthCurveOptions{
+		MinAmountIn: big.NewInt(1e15),
+		MaxAmountIn: big.NewInt(1e18),
+		Points:      4,
+	})
+	require.NoError(t, err)
+	require.Len(t, points, 4)
+	for i, amountIn := range []float64{1e15, 1e16, 1e17, 1e18} {
+		got, _ := points[i].AmountIn.Float64()
+		assert.InEpsilon(t, amountIn, got, 1e-12)
+	}
+
+	_, err = DepthCurve(ctx, poolSim, tokens[0], tokens[1], DepthCurveOptions{
+		MinAmountIn: big.NewInt(2),
+		MaxAmountIn: big.NewInt(1),
+	})
+	assert.ErrorIs(t, err, ErrDepthCurveRange)
+	_, err = DepthCurve(ctx, poolSim, "0x0000000000000000000000000000000000000000", tokens[1], DepthCurveOptions{})
+	assert.ErrorIs(t, err, ErrDepthCurveRange)
+}
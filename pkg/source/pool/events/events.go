@@ -0,0 +1,361 @@
+// Package events tracks pools from their logs. A source declares the ABI events it understands as pure state
+// transitions over its Extra type, and a Tracker takes care of the rest: routing logs to pools, applying them in
+// (block, log index) order exactly once, and falling back to a full refresh on logs it cannot apply.
+package events
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goccy/go-json"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/eth"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/metrics"
+)
+
+// ErrRefresh is returned by an ApplyFunc that cannot apply its event from the event alone, making the Tracker refresh
+// the pool instead.
+var ErrRefresh = errors.New("event requires a full refresh")
+
+// Cursor is the position of the last log applied to a pool. Embed it in the Extra of event-tracked pools.
+type Cursor struct {
+	Block    uint64 `json:"block,omitempty"`
+	LogIndex uint   `json:"logIndex,omitempty"`
+}
+
+// EventCursor returns the cursor, for the Tracker to skip logs already applied.
+func (c *Cursor) EventCursor() *Cursor {
+	return c
+}
+
+// after reports whether log comes after the cursor.
+func (c *Cursor) after(log types.Log) bool {
+	return log.BlockNumber > c.Block || log.BlockNumber == c.Block && log.Index > c.LogIndex
+}
+
+// Extra is the pointer constraint of an Extra type T embedding Cursor.
+type Extra[T any] interface {
+	*T
+	EventCursor() *Cursor
+}
+
+// ApplyFunc applies the decoded event e of log to the pool p and its decoded Extra.
+type ApplyFunc[T, E any] func(p *entity.Pool, extra *T, e *E, log types.Log) error
+
+// Handler applies one ABI event, see On.
+type Handler[T any] struct {
+	event abi.Event
+	apply func(p *entity.Pool, extra *T, log types.Log) error
+}
+
+// On returns the Handler of event, decoding its logs into E as abigen-generated bindings do.
+func On[T, E any](event abi.Event, apply ApplyFunc[T, E]) Handler[T] {
+	return Handler[T]{
+		event: event,
+		apply: func(p *entity.Pool, extra *T, log types.Log) error {
+			var e E
+			if err := Unpack(&e, event, log); err != nil {
+				return err
+			}
+			return apply(p, extra, &e, log)
+		},
+	}
+}
+
+// Unpack decodes log of event into out, a pointer to a struct with fields named after the event inputs, as abigen
+// names them. Inputs without a field are skipped, so out can hold only the inputs of interest.
+func Unpack(out any, event abi.Event, log types.Log) error {
+	if len(log.Topics) == 0 || log.Topics[0] != event.ID {
+		return fmt.Errorf("log is not a %s event", event.Name)
+	}
+	values := make(map[string]any, len(event.Inputs))
+	if err := event.Inputs.NonIndexed().UnpackIntoMap(values, log.Data); err != nil {
+		return fmt.Errorf("failed to unpack %s: %w", event.Name, err)
+	}
+	var indexed abi.Arguments
+	for _, arg := range event.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(values, indexed, log.Topics[1:]); err != nil {
+		return fmt.Errorf("failed to unpack %s: %w", event.Name, err)
+	}
+
+	fields := reflect.ValueOf(out).Elem()
+	for name, value := range values {
+		field := fields.FieldByName(abi.ToCamelCase(name))
+		if !field.IsValid() {
+			continue
+		}
+		v := reflect.ValueOf(value)
+		if !v.Type().AssignableTo(field.Type()) {
+			return fmt.Errorf("failed to unpack %s: %s is %s, not %s", event.Name, name, v.Type(), field.Type())
+		}
+		field.Set(v)
+	}
+	return nil
+}
+
+// AllPools is returned by a RouteFunc for logs concerning every pool, such as a change to a config they share. The
+// Tracker and Pools.Decode route them to the pools tracked, Decode drops them.
+const AllPools = "*"
+
+// DefaultMaxPools is the default number of pools a Tracker routes logs for all pools to.
+const DefaultMaxPools = 10000
+
+// RouteFunc returns the addresses of the pools a log concerns.
+type RouteFunc func(ctx context.Context, log types.Log) ([]string, error)
+
+// RefreshFunc fetches the state of a pool in full, typically over RPC.
+type RefreshFunc func(ctx context.Context, p entity.Pool) (entity.Pool, error)
+
+// Tracker tracks the pools of a pool type from their events.
+type Tracker[T any, PT Extra[T]] struct {
+	poolType string
+	handlers map[common.Hash]Handler[T]
+	route    RouteFunc
+	refresh  RefreshFunc
+	pools    *Pools
+}
+
+// NewTracker returns the Tracker of the pools of poolType, applying the events of handlers and refreshing pools with
+// refresh. Logs are routed to the pool at their address, unless set otherwise with WithRoute.
+func NewTracker[T any, PT Extra[T]](poolType string, refresh RefreshFunc, handlers ...Handler[T]) *Tracker[T, PT] {
+	t := &Tracker[T, PT]{
+		poolType: poolType,
+		handlers: make(map[common.Hash]Handler[T], len(handlers)),
+		route:    routeToEmitter,
+		refresh:  refresh,
+		pools:    NewPools(DefaultMaxPools),
+	}
+	for _, handler := range handlers {
+		t.handlers[handler.event.ID] = handler
+	}
+	return t
+}
+
+// WithRoute sets how logs are routed to pools.
+func (t *Tracker[T, PT]) WithRoute(route RouteFunc) *Tracker[T, PT] {
+	t.route = route
+	return t
+}
+
+// WithPools sets the Pools the Tracker routes logs for all pools to, to share them with the event parser of the pool
+// type.
+func (t *Tracker[T, PT]) WithPools(pools *Pools) *Tracker[T, PT] {
+	t.pools = pools
+	return t
+}
+
+// WithMaxPools sets how many pools the Tracker routes logs for all pools to. The least recently updated pool is
+// evicted past it.
+func (t *Tracker[T, PT]) WithMaxPools(maxPools int) *Tracker[T, PT] {
+	t.pools.setMaxPools(maxPools)
+	return t
+}
+
+// Topics returns the topic0 of the events the Tracker applies, sorted, for filtering logs to subscribe to.
+func (t *Tracker[T, PT]) Topics() []common.Hash {
+	topics := make([]common.Hash, 0, len(t.handlers))
+	for topic := range t.handlers {
+		topics = append(topics, topic)
+	}
+	slices.SortFunc(topics, func(a, b common.Hash) int { return a.Cmp(b) })
+	return topics
+}
+
+// Decode routes logs to the addresses of their pools, implementing pool.IPoolDecoder. Logs routed to AllPools go to
+// every pool the Tracker tracks, logs without topics go nowhere as no handler applies them.
+func (t *Tracker[T, PT]) Decode(ctx context.Context, logs []types.Log) (map[string][]types.Log, error) {
+	return t.pools.Decode(ctx, logs, func(ctx context.Context, log types.Log) ([]string, error) {
+		if len(log.Topics) == 0 {
+			return nil, nil
+		}
+		return t.route(ctx, log)
+	})
+}
+
+// Decode routes logs with route to the lowercase addresses of their pools, dropping those routed to AllPools. Logs
+// without topics, such as those of anonymous events, are routed too.
+func Decode(ctx context.Context, logs []types.Log, route RouteFunc) (map[string][]types.Log, error) {
+	addressLogs := make(map[string][]types.Log)
+	for _, log := range logs {
+		addresses, err := route(ctx, log)
+		if err != nil {
+			return nil, err
+		}
+		for _, address := range addresses {
+			if address != "" && address != AllPools {
+				address = strings.ToLower(address)
+				addressLogs[address] = append(addressLogs[address], log)
+			}
+		}
+	}
+	return addressLogs, nil
+}
+
+// GetNewPoolState applies params.Logs to p in (block, log index) order, skipping the logs applied already. It
+// refreshes p instead when there are no logs, when p was never refreshed, on removed logs, when p was not tracked as a
+// log for all pools was routed, or on logs of an event without a handler, counting their topic as unprocessed.
+func (t *Tracker[T, PT]) GetNewPoolState(ctx context.Context, p entity.Pool,
+	params pool.GetNewPoolStateParams) (entity.Pool, error) {
+	missedAllPools := !t.pools.track(p.Address) && p.BlockNumber < t.pools.allPoolsBlock.Load()
+	if len(params.Logs) == 0 || p.BlockNumber == 0 || eth.HasRevertedLog(params.Logs) || missedAllPools {
+		return t.refreshPool(ctx, p)
+	}
+	var extra T
+	if err := json.Unmarshal([]byte(p.Extra), &extra); err != nil {
+		return t.refreshPool(ctx, p)
+	}
+
+	logs := slices.Clone(params.Logs)
+	eth.SortLogs(logs)
+	cursor := PT(&extra).EventCursor()
+	if *cursor == (Cursor{}) {
+		// pools refreshed before being tracked by events hold the state as of the end of their block
+		*cursor = Cursor{Block: p.BlockNumber, LogIndex: math.MaxUint}
+	}
+
+	updated := p
+	for _, log := range logs {
+		if len(log.Topics) == 0 || !cursor.after(log) {
+			continue
+		}
+		handler, ok := t.handlers[log.Topics[0]]
+		if !ok {
+			metrics.IncrUnprocessedEventTopic(t.poolType, log.Topics[0].Hex())
+			return t.refreshPool(ctx, p)
+		}
+		if err := handler.apply(&updated, &extra, log); errors.Is(err, ErrRefresh) {
+			return t.refreshPool(ctx, p)
+		} else if err != nil {
+			return p, fmt.Errorf("failed to apply %s log %d of block %d: %w", handler.event.Name, log.Index,
+				log.BlockNumber, err)
+		}
+		*cursor = Cursor{Block: log.BlockNumber, LogIndex: log.Index}
+		updated.BlockNumber = max(updated.BlockNumber, log.BlockNumber)
+		if header, ok := params.BlockHeaders[log.BlockNumber]; ok {
+			updated.Timestamp = max(updated.Timestamp, int64(header.Timestamp))
+		}
+	}
+
+	extraBytes, err := json.Marshal(extra)
+	if err != nil {
+		return p, err
+	}
+	updated.Extra = string(extraBytes)
+	return updated, nil
+}
+
+// Pools are the pools logs for all pools are routed to: the most recently updated pools of a pool type, up to a
+// maximum. A Tracker keeps its own unless set with WithPools, for an event parser to route logs for all pools through
+// the same ones.
+type Pools struct {
+	mu       sync.Mutex
+	maxPools int
+	tracked  map[string]*list.Element // of the address, most recently updated first
+	lru      *list.List
+	// allPoolsBlock is the latest block of a log routed to AllPools. Pools updated before it that are not tracked,
+	// e.g. after their eviction, missed it and are refreshed.
+	allPoolsBlock atomic.Uint64
+}
+
+// NewPools returns Pools of at most maxPools pools.
+func NewPools(maxPools int) *Pools {
+	return &Pools{
+		maxPools: maxPools,
+		tracked:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// Decode routes logs with route to the lowercase addresses of their pools, as Decode, and logs routed to AllPools to
+// every pool of ps.
+func (ps *Pools) Decode(ctx context.Context, logs []types.Log, route RouteFunc) (map[string][]types.Log, error) {
+	return Decode(ctx, logs, func(ctx context.Context, log types.Log) ([]string, error) {
+		addresses, err := route(ctx, log)
+		if err != nil || !slices.Contains(addresses, AllPools) {
+			return addresses, err
+		}
+		for block := ps.allPoolsBlock.Load(); block < log.BlockNumber; block = ps.allPoolsBlock.Load() {
+			if ps.allPoolsBlock.CompareAndSwap(block, log.BlockNumber) {
+				break
+			}
+		}
+		ps.mu.Lock()
+		defer ps.mu.Unlock()
+		all := make([]string, 0, len(ps.tracked))
+		for address := range ps.tracked {
+			all = append(all, address)
+		}
+		return all, nil
+	})
+}
+
+// track remembers address as one of the pools, for routing logs to AllPools, and reports whether it was tracked
+// already. Past maxPools, the least recently updated pool is evicted.
+func (ps *Pools) track(address string) bool {
+	address = strings.ToLower(address)
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if e, ok := ps.tracked[address]; ok {
+		ps.lru.MoveToFront(e)
+		return true
+	}
+	ps.tracked[address] = ps.lru.PushFront(address)
+	ps.evict()
+	return false
+}
+
+func (ps *Pools) setMaxPools(maxPools int) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.maxPools = maxPools
+	ps.evict()
+}
+
+// evict evicts the least recently updated pools past maxPools. ps.mu must be held.
+func (ps *Pools) evict() {
+	for ps.lru.Len() > max(ps.maxPools, 1) {
+		oldest := ps.lru.Back()
+		ps.lru.Remove(oldest)
+		delete(ps.tracked, oldest.Value.(string))
+	}
+}
+
+// refreshPool refreshes p, moving its cursor to the end of the block of the refreshed state.
+func (t *Tracker[T, PT]) refreshPool(ctx context.Context, p entity.Pool) (entity.Pool, error) {
+	refreshed, err := t.refresh(ctx, p)
+	if err != nil {
+		return p, err
+	}
+	var extra T
+	if err = json.Unmarshal([]byte(refreshed.Extra), &extra); err != nil {
+		return p, err
+	}
+	*PT(&extra).EventCursor() = Cursor{Block: refreshed.BlockNumber, LogIndex: math.MaxUint}
+	extraBytes, err := json.Marshal(extra)
+	if err != nil {
+		return p, err
+	}
+	refreshed.Extra = string(extraBytes)
+	return refreshed, nil
+}
+
+func routeToEmitter(_ context.Context, log types.Log) ([]string, error) {
+	return []string{log.Address.Hex()}, nil
+}
@@ -0,0 +1,245 @@
+package events_test
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool/events"
+)
+
+var (
+	testABI = func() abi.ABI {
+		parsed, err := abi.JSON(strings.NewReader(`[
+			{"type":"event","name":"Set","inputs":[{"name":"pool","type":"address","indexed":true},
+				{"name":"value","type":"uint256","indexed":false}]},
+			{"type":"event","name":"Add","inputs":[{"name":"pool","type":"address","indexed":true},
+				{"name":"amount","type":"uint256","indexed":false}]},
+			{"type":"event","name":"Reset","inputs":[{"name":"pool","type":"address","indexed":true}]},
+			{"type":"event","name":"Unknown","inputs":[]}
+		]`))
+		if err != nil {
+			panic(err)
+		}
+		return parsed
+	}()
+	testPool = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+)
+
+type testExtra struct {
+	events.Cursor `json:"cursor"`
+	Value         int64 `json:"value"`
+}
+
+func testTracker(refreshes *int) *events.Tracker[testExtra, *testExtra] {
+	return events.NewTracker[testExtra](
+		"test",
+		func(_ context.Context, p entity.Pool) (entity.Pool, error) {
+			*refreshes++
+			p.Extra = `{"value":100}`
+			p.BlockNumber = 50
+			return p, nil
+		},
+		events.On(testABI.Events["Set"], func(p *entity.Pool, extra *testExtra, e *struct {
+			Pool  common.Address
+			Value *big.Int
+		}, _ types.Log) error {
+			if e.Pool != testPool {
+				return assert.AnError
+			}
+			extra.Value = e.Value.Int64()
+			return nil
+		}),
+		events.On(testABI.Events["Add"], func(p *entity.Pool, extra *testExtra, e *struct{ Amount *big.Int },
+			_ types.Log) error {
+			extra.Value += e.Amount.Int64()
+			return nil
+		}),
+		events.On(testABI.Events["Reset"], func(*entity.Pool, *testExtra, *struct{}, types.Log) error {
+			return events.ErrRefresh
+		}),
+	)
+}
+
+func testLog(event string, block uint64, index uint, value int64) types.Log {
+	log := types.Log{
+		Address:     testPool,
+		Topics:      []common.Hash{testABI.Events[event].ID},
+		BlockNumber: block,
+		Index:       index,
+	}
+	if event != "Unknown" {
+		log.Topics = append(log.Topics, common.BytesToHash(testPool[:]))
+	}
+	if event == "Set" || event == "Add" {
+		log.Data = common.BigToHash(big.NewInt(value)).Bytes()
+	}
+	return log
+}
+
+func extraOf(t *testing.T, p entity.Pool) testExtra {
+	var extra testExtra
+	require.NoError(t, json.Unmarshal([]byte(p.Extra), &extra))
+	return extra
+}
+
+func TestTracker_GetNewPoolState(t *testing.T) {
+	t.Parallel()
+	var refreshes int
+	tracker := testTracker(&refreshes)
+	p := entity.Pool{Address: strings.ToLower(testPool.Hex()), Extra: `{"value":1}`, BlockNumber: 10}
+
+	// applied in (block, log index) order, skipping the logs of blocks up to the pool block
+	updated, err := tracker.GetNewPoolState(context.Background(), p, pool.GetNewPoolStateParams{
+		Logs:         []types.Log{testLog("Add", 12, 3, 5), testLog("Set", 12, 1, 7), testLog("Set", 10, 4, 1000)},
+		BlockHeaders: map[uint64]entity.BlockHeader{12: {Timestamp: 1700000000}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, testExtra{Cursor: events.Cursor{Block: 12, LogIndex: 3}, Value: 12}, extraOf(t, updated))
+	assert.Equal(t, uint64(12), updated.BlockNumber)
+	assert.Equal(t, int64(1700000000), updated.Timestamp)
+	assert.Zero(t, refreshes)
+
+	// logs applied already are skipped
+	updated, err = tracker.GetNewPoolState(context.Background(), updated, pool.GetNewPoolStateParams{
+		Logs: []types.Log{testLog("Add", 12, 3, 5), testLog("Add", 12, 4, 1)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, testExtra{Cursor: events.Cursor{Block: 12, LogIndex: 4}, Value: 13}, extraOf(t, updated))
+	assert.Zero(t, refreshes)
+}
+
+func TestTracker_GetNewPoolState_Refresh(t *testing.T) {
+	t.Parallel()
+	p := entity.Pool{Address: strings.ToLower(testPool.Hex()), Extra: `{"value":1}`, BlockNumber: 10}
+	removed := testLog("Add", 11, 0, 1)
+	removed.Removed = true
+	for name, params := range map[string]pool.GetNewPoolStateParams{
+		"no logs":       {},
+		"removed logs":  {Logs: []types.Log{removed}},
+		"unknown event": {Logs: []types.Log{testLog("Add", 11, 0, 1), testLog("Unknown", 11, 1, 0)}},
+		"refresh event": {Logs: []types.Log{testLog("Reset", 11, 0, 0)}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var refreshes int
+			updated, err := testTracker(&refreshes).GetNewPoolState(context.Background(), p, params)
+			require.NoError(t, err)
+			assert.Equal(t, 1, refreshes)
+			assert.Equal(t, int64(100), extraOf(t, updated).Value)
+			assert.Equal(t, uint64(50), extraOf(t, updated).Block)
+		})
+	}
+
+	// pools never refreshed are refreshed before applying logs
+	var refreshes int
+	_, err := testTracker(&refreshes).GetNewPoolState(context.Background(), entity.Pool{Extra: "{}"},
+		pool.GetNewPoolStateParams{Logs: []types.Log{testLog("Add", 11, 0, 1)}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, refreshes)
+
+	// failing to decode is an error
+	_, err = testTracker(&refreshes).GetNewPoolState(context.Background(), p,
+		pool.GetNewPoolStateParams{Logs: []types.Log{{Topics: testLog("Set", 11, 0, 0).Topics, BlockNumber: 11}}})
+	assert.Error(t, err)
+}
+
+func TestTracker_Decode(t *testing.T) {
+	t.Parallel()
+	var refreshes int
+	tracker := testTracker(&refreshes)
+	assert.Len(t, tracker.Topics(), 3)
+
+	logs := []types.Log{testLog("Set", 1, 0, 1), {}}
+	addressLogs, err := tracker.Decode(context.Background(), logs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]types.Log{strings.ToLower(testPool.Hex()): logs[:1]}, addressLogs)
+
+	tracker.WithRoute(func(context.Context, types.Log) ([]string, error) { return []string{"A", "B"}, nil })
+	addressLogs, err = tracker.Decode(context.Background(), logs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]types.Log{"a": logs[:1], "b": logs[:1]}, addressLogs)
+}
+
+func TestTracker_Decode_AllPools(t *testing.T) {
+	t.Parallel()
+	var refreshes int
+	tracker := testTracker(&refreshes).WithRoute(func(context.Context, types.Log) ([]string, error) {
+		return []string{events.AllPools}, nil
+	})
+	logs := []types.Log{testLog("Reset", 11, 0, 0)}
+
+	// no pool tracked yet
+	addressLogs, err := tracker.Decode(context.Background(), logs)
+	require.NoError(t, err)
+	assert.Empty(t, addressLogs)
+
+	_, err = tracker.GetNewPoolState(context.Background(), entity.Pool{Address: "0xA", Extra: `{}`, BlockNumber: 10},
+		pool.GetNewPoolStateParams{})
+	require.NoError(t, err)
+	addressLogs, err = tracker.Decode(context.Background(), logs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]types.Log{"0xa": logs}, addressLogs)
+
+	// past its max pools, the least recently updated pool is evicted, and refreshed on its next update as it missed
+	// the logs for all pools
+	tracker.WithMaxPools(1)
+	_, err = tracker.GetNewPoolState(context.Background(), entity.Pool{Address: "0xB", Extra: `{}`, BlockNumber: 10},
+		pool.GetNewPoolStateParams{})
+	require.NoError(t, err)
+	addressLogs, err = tracker.Decode(context.Background(), logs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]types.Log{"0xb": logs}, addressLogs)
+	refreshes = 0
+	_, err = tracker.GetNewPoolState(context.Background(), entity.Pool{Address: "0xA", Extra: `{}`, BlockNumber: 10},
+		pool.GetNewPoolStateParams{Logs: []types.Log{testLog("Add", 12, 0, 1)}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, refreshes)
+
+	// Pools shared with a Tracker route them to its pools
+	pools := events.NewPools(events.DefaultMaxPools)
+	_, err = testTracker(&refreshes).WithPools(pools).GetNewPoolState(context.Background(),
+		entity.Pool{Address: "0xC", Extra: `{}`, BlockNumber: 10}, pool.GetNewPoolStateParams{})
+	require.NoError(t, err)
+	addressLogs, err = pools.Decode(context.Background(), logs, func(context.Context, types.Log) ([]string, error) {
+		return []string{events.AllPools}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]types.Log{"0xc": logs}, addressLogs)
+
+	// without a Tracker, logs for all pools have nowhere to go
+	addressLogs, err = events.Decode(context.Background(), logs, func(context.Context, types.Log) ([]string, error) {
+		return []string{events.AllPools}, nil
+	})
+	require.NoError(t, err)
+	assert.Empty(t, addressLogs)
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+	// anonymous events have no topics, their route finds the pool from the data
+	logs := []types.Log{{Data: testPool[:]}, testLog("Set", 1, 0, 1)}
+	addressLogs, err := events.Decode(context.Background(), logs, func(_ context.Context, log types.Log) ([]string, error) {
+		if len(log.Topics) == 0 {
+			return []string{common.BytesToAddress(log.Data).Hex()}, nil
+		}
+		return []string{"", "0xB"}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]types.Log{strings.ToLower(testPool.Hex()): logs[:1], "0xb": logs[1:]}, addressLogs)
+
+	_, err = events.Decode(context.Background(), logs, func(context.Context, types.Log) ([]string, error) {
+		return nil, assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+}
@@ -0,0 +1,212 @@
+package pool
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrAmountOutUnreachable = errors.New("amount out is unreachable")
+	ErrInversionNotConverge = errors.New("amount in search did not converge")
+)
+
+const (
+	// defaultInversionMaxIterations is enough to double amountIn up to maxInversionAmountInBits and bisect it back down
+	// to 1 wei, to tell unreachable amounts out apart.
+	defaultInversionMaxIterations = 2 * (maxInversionAmountInBits + 1)
+	// maxInversionAmountInBits bounds the exponential search for an upper bracket.
+	maxInversionAmountInBits = 255
+)
+
+// InversionConfig configures the amountIn search of ExactOutAdapter.
+type InversionConfig struct {
+	// MaxIterations bounds the number of CalcAmountOut calls. Defaults to 512.
+	MaxIterations int
+	// AmountInTolerance stops the search once the bracket [lower, upper] is at most this wide. Defaults to 1, i.e. the
+	// smallest amountIn yielding at least the requested amountOut.
+	AmountInTolerance *big.Int
+	// AmountOutToleranceBps stops the search once the upper bracket overshoots the requested amountOut by at most this
+	// many basis points. Defaults to 0.
+	AmountOutToleranceBps int64
+}
+
+// InversionStats reports how an inverted CalcAmountIn converged. The smallest amountIn yielding at least the requested
+// amountOut lies in (AmountInLowerBound, AmountInUpperBound]; the returned amountIn is AmountInUpperBound.
+type InversionStats struct {
+	Iterations         int
+	AmountInLowerBound *big.Int
+	AmountInUpperBound *big.Int
+	// AmountOut is the actual amount out of AmountInUpperBound, which is at least the requested amount out.
+	AmountOut *big.Int
+}
+
+// ExactOutAdapter implements IPoolExactOutSimulator for any IPoolSimulator by inverting its CalcAmountOut, assuming
+// amountOut is monotonically non-decreasing in amountIn. It brackets the answer with an exponential search, bisecting
+// back down once CalcAmountOut fails, e.g. past the reserves or a max amount in, and then narrows the bracket with
+// secant steps, falling back to bisection whenever a secant step does not shrink it enough.
+type ExactOutAdapter struct {
+	IPoolSimulator
+	Config InversionConfig
+}
+
+// NewExactOutAdapter wraps a pool simulator so that it supports CalcAmountIn.
+func NewExactOutAdapter(poolSim IPoolSimulator, config InversionConfig) *ExactOutAdapter {
+	return &ExactOutAdapter{IPoolSimulator: poolSim, Config: config}
+}
+
+// CalcAmountIn returns the smallest amountIn (within the configured tolerance) whose CalcAmountOut is at least the
+// requested amountOut. Fee, Gas and SwapInfo come from the CalcAmountOut result of the returned amountIn, so it can be
+// passed to UpdateBalance as is.
+func (a *ExactOutAdapter) CalcAmountIn(params CalcAmountInParams) (*CalcAmountInResult, error) {
+	res, _, err := a.CalcAmountInWithStats(context.Background(), params)
+	return res, err
+}
+
+// CalcAmountInWithStats is CalcAmountIn that also reports the convergence statistics of the search.
+func (a *ExactOutAdapter) CalcAmountInWithStats(ctx context.Context, params CalcAmountInParams) (*CalcAmountInResult,
+	*InversionStats, error) {
+	target := params.TokenAmountOut.Amount
+	if target == nil || target.Sign() <= 0 {
+		return nil, nil, ErrAmountOutUnreachable
+	}
+
+	maxIterations := a.Config.MaxIterations
+	if maxIterations <= 0 {
+		maxIterations = defaultInversionMaxIterations
+	}
+	amountInTolerance := a.Config.AmountInTolerance
+	if amountInTolerance == nil || amountInTolerance.Sign() <= 0 {
+		amountInTolerance = big.NewInt(1)
+	}
+	// acceptable overshoot above target
+	slack := new(big.Int).Mul(target, big.NewInt(a.Config.AmountOutToleranceBps))
+	slack.Quo(slack, big.NewInt(10000))
+
+	stats := &InversionStats{}
+	calc := func(amountIn *big.Int) *CalcAmountOutResult {
+		stats.Iterations++
+		res, err := CalcAmountOut(ctx, a.IPoolSimulator, TokenAmount{Token: params.TokenIn, Amount: amountIn},
+			params.TokenAmountOut.Token, params.Limit)
+		if err != nil || res == nil || res.TokenAmountOut == nil || res.TokenAmountOut.Amount == nil {
+			return nil
+		}
+		return res
+	}
+	amountOutOf := func(res *CalcAmountOutResult) *big.Int {
+		if res == nil || res.RemainingTokenAmountIn != nil && res.RemainingTokenAmountIn.Amount.Sign() > 0 {
+			return nil // errors and partial fills count as not reaching target
+		}
+		return res.TokenAmountOut.Amount
+	}
+
+	// exponential search for an upper bracket, starting from amountIn = amountOut. Failures before any amountIn quotes
+	// are of amounts too small to quote; after, of amounts too large, bisected down between the largest amountIn short of
+	// target and the smallest failing one.
+	lower, lowerOut := new(big.Int), new(big.Int)
+	var failing *big.Int
+	upper := new(big.Int).Set(target)
+	upperRes := calc(upper)
+	upperOut := amountOutOf(upperRes)
+	for upperOut == nil || upperOut.Cmp(target) < 0 {
+		if upperOut != nil {
+			lower, lowerOut = upper, upperOut
+		} else if lower.Sign() > 0 {
+			failing = upper
+		}
+		if failing == nil && upper.BitLen() >= maxInversionAmountInBits ||
+			failing != nil && new(big.Int).Sub(failing, lower).Cmp(big.NewInt(1)) <= 0 {
+			return nil, stats, ErrAmountOutUnreachable
+		} else if stats.Iterations >= maxIterations {
+			return nil, stats, ErrInversionNotConverge
+		}
+		if failing == nil {
+			upper = new(big.Int).Lsh(upper, 1)
+		} else {
+			upper = new(big.Int).Add(lower, failing)
+			upper.Rsh(upper, 1)
+		}
+		upperRes = calc(upper)
+		upperOut = amountOutOf(upperRes)
+	}
+
+	var (
+		width       = new(big.Int)
+		prevWidth   = new(big.Int).Sub(upper, lower)
+		useBisect   bool
+		mid, numer  = new(big.Int), new(big.Int)
+		acceptedOut = new(big.Int).Add(target, slack)
+	)
+	for {
+		width.Sub(upper, lower)
+		if width.Cmp(amountInTolerance) <= 0 || upperOut.Cmp(acceptedOut) <= 0 && slack.Sign() > 0 {
+			break
+		}
+		if stats.Iterations >= maxIterations {
+			stats.AmountInLowerBound, stats.AmountInUpperBound, stats.AmountOut = lower, upper, upperOut
+			return nil, stats, ErrInversionNotConverge
+		}
+
+		// secant step: lower + (target - lowerOut) * (upper - lower) / (upperOut - lowerOut)
+		denom := new(big.Int).Sub(upperOut, lowerOut)
+		if useBisect || denom.Sign() <= 0 {
+			mid.Rsh(mid.Add(lower, upper), 1)
+		} else {
+			numer.Sub(target, lowerOut)
+			numer.Mul(numer, width)
+			mid.Add(lower, numer.Quo(numer, denom))
+		}
+		// keep mid strictly inside the bracket
+		if mid.Cmp(lower) <= 0 {
+			mid.Add(lower, big.NewInt(1))
+		} else if mid.Cmp(upper) >= 0 {
+			mid.Sub(upper, big.NewInt(1))
+		}
+
+		midRes := calc(mid)
+		if midOut := amountOutOf(midRes); midOut != nil && midOut.Cmp(target) >= 0 {
+			upper, upperOut, upperRes = new(big.Int).Set(mid), midOut, midRes
+		} else {
+			lower = new(big.Int).Set(mid)
+			if midOut != nil {
+				lowerOut = midOut
+			}
+		}
+
+		// alternate to bisection when the bracket did not at least halve
+		newWidth := new(big.Int).Sub(upper, lower)
+		useBisect = newWidth.Lsh(newWidth, 1).Cmp(prevWidth) > 0
+		prevWidth.Sub(upper, lower)
+	}
+
+	stats.AmountInLowerBound, stats.AmountInUpperBound, stats.AmountOut = lower, upper, upperOut
+	return &CalcAmountInResult{
+		TokenAmountIn: &TokenAmount{Token: params.TokenIn, Amount: upper},
+		Fee:           upperRes.Fee,
+		Gas:           upperRes.Gas,
+		SwapInfo:      upperRes.SwapInfo,
+	}, stats, nil
+}
+
+// CalcAmountIn wraps CalcAmountIn of pools that natively support exact out, falls back to ExactOutAdapter with the
+// default InversionConfig for the others, and catches panics.
+func CalcAmountIn(ctx context.Context, pool IPoolSimulator, tokenAmountOut TokenAmount, tokenIn string,
+	limit SwapLimit) (res *CalcAmountInResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("calcAmountIn panicked: %v", r)
+		}
+	}()
+
+	params := CalcAmountInParams{
+		TokenAmountOut: tokenAmountOut,
+		TokenIn:        tokenIn,
+		Limit:          limit,
+	}
+	if exactOut, ok := pool.(IPoolExactOutSimulator); ok {
+		return exactOut.CalcAmountIn(params)
+	}
+	res, _, err = NewExactOutAdapter(pool, InversionConfig{}).CalcAmountInWithStats(ctx, params)
+	return res, err
+}
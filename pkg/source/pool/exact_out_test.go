@@ -0,0 +1,136 @@
+package pool_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	uniswapv2 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v2"
+	. "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/testutil"
+)
+
+func newExactOutTestPool(t *testing.T) *uniswapv2.PoolSimulator {
+	poolSim, err := uniswapv2.NewPoolSimulator(entity.Pool{
+		Address:  "0x9eb0bc7a207f77811ee365729d00152622a745b7",
+		Exchange: "pancake",
+		Type:     uniswapv2.DexType,
+		Reserves: []string{"5789592094546501478373016", "793623036600773033475"},
+		Tokens: []*entity.PoolToken{
+			{Address: "0x6d5ad1592ed9d6d1df9b93c793ab759573ed6714", Swappable: true},
+			{Address: "0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095c", Swappable: true},
+		},
+		Extra: `{"fee":25,"feePrecision":10000}`,
+	})
+	require.NoError(t, err)
+	return poolSim
+}
+
+func TestExactOutAdapter_CalcAmountIn(t *testing.T) {
+	t.Parallel()
+	poolSim := newExactOutTestPool(t)
+	tokens := poolSim.GetTokens()
+	adapter := NewExactOutAdapter(poolSim, InversionConfig{})
+
+	for _, amountOut := range []string{"1", "1000000", "1000000000000000000", "500000000000000000000"} {
+		t.Run(amountOut, func(t *testing.T) {
+			params := CalcAmountInParams{
+				TokenAmountOut: TokenAmount{Token: tokens[1], Amount: bignumber.NewBig(amountOut)},
+				TokenIn:        tokens[0],
+			}
+			native, err := poolSim.CalcAmountIn(params)
+			require.NoError(t, err)
+
+			inverted, stats, err := adapter.CalcAmountInWithStats(ctx, params)
+			require.NoError(t, err)
+			assert.Equal(t, inverted.TokenAmountIn.Amount, stats.AmountInUpperBound)
+			assert.LessOrEqual(t, new(big.Int).Sub(stats.AmountInUpperBound, stats.AmountInLowerBound).Int64(),
+				int64(1))
+			assert.GreaterOrEqual(t, stats.AmountOut.Cmp(params.TokenAmountOut.Amount), 0)
+			assert.Less(t, stats.Iterations, 256)
+
+			// uniswap v2 getAmountIn rounds up by one wei, so the native result may exceed the minimal amountIn by 1
+			diff := new(big.Int).Sub(native.TokenAmountIn.Amount, inverted.TokenAmountIn.Amount)
+			assert.True(t, diff.Sign() >= 0 && diff.Cmp(big.NewInt(1)) <= 0, "native %s, inverted %s",
+				native.TokenAmountIn.Amount, inverted.TokenAmountIn.Amount)
+		})
+	}
+}
+
+func TestExactOutAdapter_Tolerance(t *testing.T) {
+	t.Parallel()
+	poolSim := newExactOutTestPool(t)
+	tokens := poolSim.GetTokens()
+	params := CalcAmountInParams{
+		TokenAmountOut: TokenAmount{Token: tokens[1], Amount: bignumber.NewBig("1000000000000000000")},
+		TokenIn:        tokens[0],
+	}
+
+	_, exact, err := NewExactOutAdapter(poolSim, InversionConfig{}).CalcAmountInWithStats(ctx, params)
+	require.NoError(t, err)
+	_, loose, err := NewExactOutAdapter(poolSim, InversionConfig{AmountOutToleranceBps: 10}).
+		CalcAmountInWithStats(ctx, params)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, loose.Iterations, exact.Iterations)
+
+	_, _, err = NewExactOutAdapter(poolSim, InversionConfig{MaxIterations: 3}).CalcAmountInWithStats(ctx, params)
+	assert.ErrorIs(t, err, ErrInversionNotConverge)
+}
+
+// cappedPool fails to quote amounts in above maxAmountIn.
+type cappedPool struct {
+	*uniswapv2.PoolSimulator
+	maxAmountIn *big.Int
+}
+
+func (p *cappedPool) CalcAmountOut(params CalcAmountOutParams) (*CalcAmountOutResult, error) {
+	if params.TokenAmountIn.Amount.Cmp(p.maxAmountIn) > 0 {
+		return nil, ErrNotEnoughInventory
+	}
+	return p.PoolSimulator.CalcAmountOut(params)
+}
+
+func TestExactOutAdapter_FailingUpperBracket(t *testing.T) {
+	t.Parallel()
+	poolSim := newExactOutTestPool(t)
+	tokens := poolSim.GetTokens()
+	params := CalcAmountInParams{
+		TokenAmountOut: TokenAmount{Token: tokens[1], Amount: bignumber.NewBig("1000000000000000000")},
+		TokenIn:        tokens[0],
+	}
+	expected, err := NewExactOutAdapter(poolSim, InversionConfig{}).CalcAmountIn(params)
+	require.NoError(t, err)
+
+	// the exponential search overshoots the cap, just above the amount in, then bisects back below it
+	maxAmountIn := new(big.Int).Add(expected.TokenAmountIn.Amount, big.NewInt(1e18))
+	res, err := NewExactOutAdapter(&cappedPool{PoolSimulator: poolSim, maxAmountIn: maxAmountIn},
+		InversionConfig{}).CalcAmountIn(params)
+	require.NoError(t, err)
+	assert.Equal(t, expected.TokenAmountIn.Amount, res.TokenAmountIn.Amount)
+
+	// target is out of reach below the cap
+	_, err = NewExactOutAdapter(&cappedPool{PoolSimulator: poolSim, maxAmountIn: big.NewInt(1e18)},
+		InversionConfig{}).CalcAmountIn(params)
+	assert.ErrorIs(t, err, ErrAmountOutUnreachable)
+}
+
+func TestExactOutAdapter_Unreachable(t *testing.T) {
+	t.Parallel()
+	poolSim := newExactOutTestPool(t)
+	tokens := poolSim.GetTokens()
+
+	_, err := NewExactOutAdapter(poolSim, InversionConfig{}).CalcAmountIn(CalcAmountInParams{
+		TokenAmountOut: TokenAmount{Token: tokens[1], Amount: bignumber.NewBig("793623036600773033475")},
+		TokenIn:        tokens[0],
+	})
+	assert.ErrorIs(t, err, ErrAmountOutUnreachable)
+}
+
+func TestExactOutAdapter_MatchesNative(t *testing.T) {
+	t.Parallel()
+	testutil.TestCalcAmountInMatchesInversion(t, newExactOutTestPool(t), 1e-9)
+}
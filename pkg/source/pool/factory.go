@@ -1,6 +1,9 @@
 package pool
 
 import (
+	"maps"
+	"slices"
+
 	"github.com/ethereum/go-ethereum"
 	"github.com/pkg/errors"
 
@@ -96,3 +99,8 @@ func RegisterFactoryMeta[P IPoolSimulator](poolType string,
 func Factory(poolType string) FactoryFn {
 	return factoryMap[poolType]
 }
+
+// PoolTypes returns the sorted pool types with a registered factory
+func PoolTypes() []string {
+	return slices.Sorted(maps.Keys(factoryMap))
+}
@@ -136,6 +136,7 @@ type ITBPoolTracker[T any] interface {
 // ITicksBasedPoolTracker fetches ticks for pool from Swap, Mint and Burn events.
 // GetNewPoolState (from IPoolTracker) applies log-based updates using params.Logs and params.BlockHeaders.
 // BootstrapPoolState performs full RPC/subgraph refresh (e.g. when params have no logs).
+// Implementations route GetNewPoolState through a reorg.Guard, which is opt-in through their Reorg config.
 type ITicksBasedPoolTracker interface {
 	IPoolTracker
 	BootstrapPoolState(ctx context.Context, p entity.Pool, params GetNewPoolStateParams) (entity.Pool, error)
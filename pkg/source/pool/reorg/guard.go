@@ -0,0 +1,294 @@
+package reorg
+
+import (
+	"cmp"
+	"container/list"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+// DefaultMaxPools is the default number of pools whose snapshots are kept.
+const DefaultMaxPools = 10000
+
+// ApplyFunc computes a new pool state from p and params, e.g. a tracker's log-driven GetNewPoolState.
+type ApplyFunc func(ctx context.Context, p entity.Pool, params pool.GetNewPoolStateParams) (entity.Pool, error)
+
+// Config configures a Guard. Reorg handling is opt-in: with a zero Depth, NewGuard returns a nil Guard.
+type Config struct {
+	// Depth is the number of snapshots kept per pool, i.e. how many tracker updates a reorg can reach back into
+	// before the Guard falls back to a full refresh.
+	Depth int `json:"depth"`
+	// MaxPools is the number of pools whose snapshots are kept. The least recently updated pool is evicted past it,
+	// and its next update starts over from the caller's state. Defaults to DefaultMaxPools.
+	MaxPools int `json:"maxPools"`
+}
+
+// Guard adds reorg handling to log-driven trackers. It keeps a bounded ring of per-pool entity.Pool snapshots, each
+// tagged with the block hashes of the logs that produced it. A reorg is detected when
+//   - a log is marked as Removed,
+//   - a log or block header carries a different hash than the one recorded for its block, or
+//   - a block header's ParentHash differs from the hash recorded for the previous block.
+//
+// On reorg, the pool is rolled back to the latest snapshot preceding the first orphaned block, and the logs recorded
+// since that snapshot that are still canonical are re-applied together with the new non-removed logs. If the reorg
+// reaches back past the oldest snapshot, the refresh function (typically a full RPC bootstrap) is used instead.
+//
+// A nil *Guard simply calls apply.
+type Guard struct {
+	depth    int
+	maxPools int
+
+	mu    sync.Mutex
+	rings map[string]*list.Element // of *ring, most recently used first
+	lru   *list.List
+}
+
+type snapshot struct {
+	block  uint64
+	hashes map[uint64]common.Hash // block hashes seen for the logs and headers that produced this snapshot
+	logs   []types.Log            // non-removed logs applied on top of the previous snapshot
+	pool   entity.Pool
+}
+
+type ring struct {
+	address   string
+	mu        sync.Mutex
+	snapshots []snapshot // oldest first
+}
+
+// NewGuard creates a Guard, or returns nil, which passes updates straight through, if config.Depth is not positive.
+func NewGuard(config Config) *Guard {
+	depth := config.Depth
+	if depth <= 0 {
+		return nil
+	}
+	maxPools := config.MaxPools
+	if maxPools <= 0 {
+		maxPools = DefaultMaxPools
+	}
+	return &Guard{
+		depth:    depth,
+		maxPools: maxPools,
+		rings:    make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// Forget drops the snapshots of a pool, e.g. when it is no longer tracked.
+func (g *Guard) Forget(address string) {
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	address = strings.ToLower(address)
+	if e, ok := g.rings[address]; ok {
+		g.lru.Remove(e)
+		delete(g.rings, address)
+	}
+}
+
+func (g *Guard) ring(address string) *ring {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	address = strings.ToLower(address)
+	if e, ok := g.rings[address]; ok {
+		g.lru.MoveToFront(e)
+		return e.Value.(*ring)
+	}
+	r := &ring{address: address}
+	g.rings[address] = g.lru.PushFront(r)
+	for g.lru.Len() > g.maxPools {
+		oldest := g.lru.Back()
+		g.lru.Remove(oldest)
+		delete(g.rings, oldest.Value.(*ring).address)
+	}
+	return r
+}
+
+// GetNewPoolState applies params to p through apply, rolling back and re-applying on reorg. refresh is called without
+// logs when a reorg is deeper than the kept snapshots; it may be nil, in which case apply is called without logs.
+func (g *Guard) GetNewPoolState(ctx context.Context, p entity.Pool, params pool.GetNewPoolStateParams,
+	apply, refresh ApplyFunc) (entity.Pool, error) {
+	if g == nil {
+		return apply(ctx, p, params)
+	}
+	if refresh == nil {
+		refresh = apply
+	}
+
+	r := g.ring(p.Address)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// the caller's state is the source of truth: drop snapshots that do not lead to it
+	if len(r.snapshots) == 0 || r.snapshots[len(r.snapshots)-1].pool.BlockNumber != p.BlockNumber {
+		r.snapshots = []snapshot{{block: p.BlockNumber, pool: p}}
+	}
+
+	if len(params.Logs) == 0 {
+		newPool, err := apply(ctx, p, params)
+		if err != nil {
+			return p, err
+		}
+		r.snapshots = []snapshot{{block: newPool.BlockNumber, pool: newPool}}
+		return newPool, nil
+	}
+
+	base, keep := p, len(r.snapshots)
+	logs := canonicalLogs(params.Logs)
+	if reorgBlock, ok := r.detect(params); ok {
+		idx := r.rollbackIndex(reorgBlock)
+		l := logger.WithFields(logger.Fields{
+			"pool_id":     p.Address,
+			"reorg_block": reorgBlock,
+		})
+		if idx < 0 {
+			l.Warn("reorg deeper than kept snapshots, refreshing pool state")
+			newPool, err := refresh(ctx, p, pool.GetNewPoolStateParams{BlockHeaders: params.BlockHeaders})
+			if err != nil {
+				return p, err
+			}
+			r.snapshots = []snapshot{{block: newPool.BlockNumber, pool: newPool}}
+			return newPool, nil
+		}
+
+		l.WithFields(logger.Fields{"rollback_to": r.snapshots[idx].block}).Warn("reorg detected, rolling back")
+		var survivors []types.Log
+		for _, s := range r.snapshots[idx+1:] {
+			for _, log := range s.logs {
+				if log.BlockNumber < reorgBlock {
+					survivors = append(survivors, log)
+				}
+			}
+		}
+		base, keep, logs = r.snapshots[idx].pool, idx+1, mergeLogs(survivors, logs)
+	}
+
+	newPool, err := apply(ctx, base, pool.GetNewPoolStateParams{Logs: logs, BlockHeaders: params.BlockHeaders})
+	if err != nil {
+		return p, err
+	}
+
+	r.snapshots = append(r.snapshots[:keep], snapshot{
+		block:  newPool.BlockNumber,
+		hashes: blockHashes(logs, params.BlockHeaders),
+		logs:   logs,
+		pool:   newPool,
+	})
+	if extra := len(r.snapshots) - g.depth; extra > 0 {
+		r.snapshots = slices.Delete(r.snapshots, 0, extra)
+	}
+	return newPool, nil
+}
+
+// detect returns the first orphaned block, if any.
+func (r *ring) detect(params pool.GetNewPoolStateParams) (uint64, bool) {
+	var (
+		reorgBlock uint64
+		found      bool
+	)
+	orphan := func(block uint64) {
+		if !found || block < reorgBlock {
+			reorgBlock, found = block, true
+		}
+	}
+
+	for _, log := range params.Logs {
+		if log.Removed {
+			orphan(log.BlockNumber)
+		} else if known, ok := r.hash(log.BlockNumber); ok && log.BlockHash != (common.Hash{}) &&
+			known != log.BlockHash {
+			orphan(log.BlockNumber)
+		}
+	}
+	for number, header := range params.BlockHeaders {
+		if known, ok := r.hash(number); ok && header.Hash != "" && known != common.HexToHash(header.Hash) {
+			orphan(number)
+		}
+		if number == 0 || header.ParentHash == "" {
+			continue
+		}
+		if known, ok := r.hash(number - 1); ok && known != common.HexToHash(header.ParentHash) {
+			orphan(number - 1)
+		}
+	}
+	return reorgBlock, found
+}
+
+// hash returns the latest recorded hash of a block.
+func (r *ring) hash(block uint64) (common.Hash, bool) {
+	for i := len(r.snapshots) - 1; i >= 0; i-- {
+		if h, ok := r.snapshots[i].hashes[block]; ok {
+			return h, true
+		}
+	}
+	return common.Hash{}, false
+}
+
+// rollbackIndex returns the index of the latest snapshot strictly before block, or -1 if there is none.
+func (r *ring) rollbackIndex(block uint64) int {
+	for i := len(r.snapshots) - 1; i >= 0; i-- {
+		if r.snapshots[i].block < block {
+			return i
+		}
+	}
+	return -1
+}
+
+func canonicalLogs(logs []types.Log) []types.Log {
+	result := make([]types.Log, 0, len(logs))
+	for _, log := range logs {
+		if !log.Removed {
+			result = append(result, log)
+		}
+	}
+	return result
+}
+
+// mergeLogs merges and sorts logs by (block, index), dropping duplicates.
+func mergeLogs(a, b []types.Log) []types.Log {
+	type key struct {
+		block uint64
+		index uint
+		hash  common.Hash
+	}
+	seen := make(map[key]struct{}, len(a)+len(b))
+	result := make([]types.Log, 0, len(a)+len(b))
+	for _, log := range slices.Concat(a, b) {
+		k := key{log.BlockNumber, log.Index, log.BlockHash}
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		result = append(result, log)
+	}
+	slices.SortStableFunc(result, func(x, y types.Log) int {
+		return cmp.Or(cmp.Compare(x.BlockNumber, y.BlockNumber), cmp.Compare(x.Index, y.Index))
+	})
+	return result
+}
+
+func blockHashes(logs []types.Log, headers map[uint64]entity.BlockHeader) map[uint64]common.Hash {
+	hashes := make(map[uint64]common.Hash, len(headers))
+	for _, log := range logs {
+		if log.BlockHash != (common.Hash{}) {
+			hashes[log.BlockNumber] = log.BlockHash
+		}
+	}
+	for number, header := range headers {
+		if header.Hash != "" {
+			hashes[number] = common.HexToHash(header.Hash)
+		}
+	}
+	return hashes
+}
@@ -0,0 +1,147 @@
+package reorg
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+// sumTracker is an event-sourced tracker whose state is the sum of the first data byte of every applied log.
+type sumTracker struct {
+	refreshes int
+	refreshed int // state returned by refresh
+}
+
+func (s *sumTracker) apply(_ context.Context, p entity.Pool, params pool.GetNewPoolStateParams) (entity.Pool,
+	error) {
+	sum, _ := strconv.Atoi(p.Extra)
+	fromBlock := p.BlockNumber
+	for _, log := range params.Logs {
+		if log.BlockNumber <= fromBlock {
+			continue
+		}
+		sum += int(log.Data[0])
+		p.BlockNumber = log.BlockNumber
+	}
+	p.Extra = strconv.Itoa(sum)
+	return p, nil
+}
+
+func (s *sumTracker) refresh(_ context.Context, p entity.Pool, _ pool.GetNewPoolStateParams) (entity.Pool, error) {
+	s.refreshes++
+	p.Extra = strconv.Itoa(s.refreshed)
+	p.BlockNumber = 1000
+	return p, nil
+}
+
+func newLog(block uint64, fork byte, index uint, value byte) types.Log {
+	return types.Log{
+		Address:     common.HexToAddress("0x01"),
+		BlockNumber: block,
+		BlockHash:   common.BytesToHash([]byte{fork, byte(block)}),
+		Index:       index,
+		Data:        []byte{value},
+	}
+}
+
+func removed(log types.Log) types.Log {
+	log.Removed = true
+	return log
+}
+
+func TestGuard_GetNewPoolState(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	tracker := &sumTracker{refreshed: 42}
+	guard := NewGuard(Config{Depth: 3})
+	p := entity.Pool{Address: "0x01", Extra: "0", BlockNumber: 9}
+
+	step := func(logs []types.Log, headers map[uint64]entity.BlockHeader) {
+		t.Helper()
+		var err error
+		p, err = guard.GetNewPoolState(ctx, p, pool.GetNewPoolStateParams{Logs: logs, BlockHeaders: headers},
+			tracker.apply, tracker.refresh)
+		require.NoError(t, err)
+	}
+
+	step([]types.Log{newLog(10, 0, 0, 1)}, nil)
+	step([]types.Log{newLog(11, 0, 0, 2), newLog(11, 0, 1, 3)}, nil)
+	step([]types.Log{newLog(12, 0, 0, 4)}, nil)
+	assert.Equal(t, "10", p.Extra)
+
+	t.Run("removed logs roll back and re-apply", func(t *testing.T) {
+		// block 11 is reorged: its logs are removed and replaced by a single log in the new block 11
+		step([]types.Log{
+			removed(newLog(11, 0, 0, 2)), removed(newLog(11, 0, 1, 3)), removed(newLog(12, 0, 0, 4)),
+			newLog(11, 1, 0, 5), newLog(12, 1, 0, 6),
+		}, nil)
+		assert.Equal(t, "12", p.Extra)
+		assert.Equal(t, uint64(12), p.BlockNumber)
+	})
+
+	t.Run("different block hash without removed logs", func(t *testing.T) {
+		step([]types.Log{newLog(12, 2, 0, 7), newLog(13, 2, 0, 1)}, nil)
+		assert.Equal(t, "14", p.Extra)
+	})
+
+	t.Run("parent hash mismatch", func(t *testing.T) {
+		step([]types.Log{newLog(14, 2, 0, 1)}, nil)
+		assert.Equal(t, "15", p.Extra)
+
+		// block 15 declares a parent that is not the block 14 we applied
+		step([]types.Log{newLog(15, 3, 0, 2)}, map[uint64]entity.BlockHeader{
+			14: {Number: big.NewInt(14), Hash: common.BytesToHash([]byte{3, 14}).Hex()},
+			15: {Number: big.NewInt(15), ParentHash: common.BytesToHash([]byte{3, 14}).Hex()},
+		})
+		assert.Equal(t, "16", p.Extra)
+	})
+
+	t.Run("reorg deeper than ring refreshes", func(t *testing.T) {
+		step([]types.Log{removed(newLog(10, 0, 0, 1)), newLog(16, 4, 0, 1)}, nil)
+		assert.Equal(t, 1, tracker.refreshes)
+		assert.Equal(t, "42", p.Extra)
+		assert.Equal(t, uint64(1000), p.BlockNumber)
+	})
+}
+
+func TestGuard_MaxPools(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	tracker := &sumTracker{}
+	guard := NewGuard(Config{Depth: 4, MaxPools: 2})
+
+	for _, address := range []string{"0x01", "0x02", "0x01", "0x03"} {
+		_, err := guard.GetNewPoolState(ctx, entity.Pool{Address: address, Extra: "0", BlockNumber: 9},
+			pool.GetNewPoolStateParams{Logs: []types.Log{newLog(10, 0, 0, 1)}}, tracker.apply, tracker.refresh)
+		require.NoError(t, err)
+	}
+	// 0x02 is the least recently updated pool
+	assert.Len(t, guard.rings, 2)
+	assert.Contains(t, guard.rings, "0x01")
+	assert.Contains(t, guard.rings, "0x03")
+
+	guard.Forget("0x01")
+	assert.Len(t, guard.rings, 1)
+	assert.Equal(t, 1, guard.lru.Len())
+}
+
+func TestGuard_Nil(t *testing.T) {
+	t.Parallel()
+	guard := NewGuard(Config{})
+	require.Nil(t, guard)
+	tracker := &sumTracker{}
+	p, err := guard.GetNewPoolState(context.Background(), entity.Pool{Extra: "1"},
+		pool.GetNewPoolStateParams{Logs: []types.Log{newLog(1, 0, 0, 2)}}, tracker.apply, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "3", p.Extra)
+	guard.Forget("0x01")
+}
@@ -3,6 +3,7 @@ package pool
 import (
 	"context"
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/samber/lo"
@@ -42,6 +43,9 @@ func (r *RFQParams) GetOrigin() string {
 type RFQResult struct {
 	NewAmountOut *big.Int
 	Extra        any
+	QuotedAt     time.Time // when the firm quote was made, zero if unknown
+	ExpiresAt    time.Time // when the firm quote stops being executable, zero if it does not expire
+	DeviationBps int64     // shortfall (in bps) of NewAmountOut from RFQParams.AmountOut, negative for improvements
 }
 
 // RFQHandler is the default no-op RFQ handler
@@ -79,22 +83,32 @@ func (h *RFQSequentialBatcher) SupportBatch() bool {
 	return true
 }
 
-// RFQWithPoolState knows how to load pool state for simulations before RFQ or BatchRFQ call
+// RFQWithPoolState knows how to load pool state for simulations before RFQ or BatchRFQ call, and checks the results
+// against Policy if set. Each retry or re-quote runs on a fresh clone of the pool state.
 type RFQWithPoolState struct {
 	IPoolRFQ
 	IPoolManager
 	ICustomFuncs
-	DexId string
+	DexId  string
+	Policy *RFQPolicy
 }
 
 func (h *RFQWithPoolState) RFQ(ctx context.Context, params RFQParams) (*RFQResult, error) {
-	ctx = h.GetAndClonePoolState(ctx, params.PoolID)
-	return h.IPoolRFQ.RFQ(ctx, params)
+	results, err := h.Policy.Do(ctx, []RFQParams{params}, func() ([]*RFQResult, error) {
+		result, err := h.IPoolRFQ.RFQ(h.GetAndClonePoolState(ctx, params.PoolID), params)
+		return []*RFQResult{result}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results[0], nil
 }
 
 func (h *RFQWithPoolState) BatchRFQ(ctx context.Context, paramsSlice []RFQParams) (results []*RFQResult, err error) {
-	ctx = h.GetAndClonePoolState(ctx, lo.Map(paramsSlice, func(p RFQParams, _ int) string { return p.PoolID })...)
-	return h.IPoolRFQ.BatchRFQ(ctx, paramsSlice)
+	poolAddrs := lo.Map(paramsSlice, func(p RFQParams, _ int) string { return p.PoolID })
+	return h.Policy.Do(ctx, paramsSlice, func() ([]*RFQResult, error) {
+		return h.IPoolRFQ.BatchRFQ(h.GetAndClonePoolState(ctx, poolAddrs...), paramsSlice)
+	})
 }
 
 func (h *RFQWithPoolState) GetAndClonePoolState(ctx context.Context, poolAddrs ...string) context.Context {
@@ -0,0 +1,167 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// RFQReason is the structured reason of a failed firm quote.
+type RFQReason string
+
+const (
+	RFQReasonUnknown         RFQReason = "unknown"
+	RFQReasonUnavailable     RFQReason = "unavailable"      // rate limited or unavailable, the maker did not serve it
+	RFQReasonTransport       RFQReason = "transport"        // transport error or timeout, the maker may have served it
+	RFQReasonRejected        RFQReason = "rejected"         // the maker declined to quote
+	RFQReasonInvalidResponse RFQReason = "invalid_response" // the maker response could not be read
+	RFQReasonExpired         RFQReason = "expired"          // the quote expires before it can be executed
+	RFQReasonDeviation       RFQReason = "deviation"        // the quote deviates too much from the indicative amount
+)
+
+// RFQError is a failed firm quote with its reason. RFQ handlers return it to let RFQPolicy tell failures apart; other
+// errors are of RFQReasonUnknown.
+type RFQError struct {
+	Reason   RFQReason
+	PoolID   string
+	Attempts int
+	Err      error
+}
+
+func NewRFQError(reason RFQReason, err error) *RFQError {
+	return &RFQError{Reason: reason, Err: err}
+}
+
+func (e *RFQError) Error() string {
+	msg := "rfq " + string(e.Reason)
+	if e.PoolID != "" {
+		msg += " for " + e.PoolID
+	}
+	if e.Attempts > 1 {
+		msg += fmt.Sprintf(" after %d attempts", e.Attempts)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *RFQError) Unwrap() error {
+	return e.Err
+}
+
+// RFQReasonOf returns the reason of a failed firm quote.
+func RFQReasonOf(err error) RFQReason {
+	if rfqErr := (*RFQError)(nil); errors.As(err, &rfqErr) {
+		return rfqErr.Reason
+	}
+	return RFQReasonUnknown
+}
+
+// RFQPolicy decides whether firm quotes are accepted, and what to do with the others: retry unavailable makers up to
+// MaxRetries times, re-quote expired or deviating quotes up to MaxRequotes times, and reject all else. Firm quotes the
+// maker may have served, such as timed out ones, are not retried, as the maker would book its liquidity twice.
+type RFQPolicy struct {
+	// MinValidity is the least time a quote must stay valid for, to be executed.
+	MinValidity time.Duration
+	// MaxDeviationBps caps the shortfall of a firm quote from the indicative RFQParams.AmountOut, which is otherwise
+	// bounded by RFQParams.Slippage only. Without either, deviations are not checked.
+	MaxDeviationBps int64
+	MaxRetries      int
+	MaxRequotes     int
+	// RetryWait is the wait before each retry or re-quote.
+	RetryWait time.Duration
+}
+
+// Do calls quote until its results for paramsSlice are accepted or the policy gives up. Results are annotated with their
+// deviations. A nil policy calls quote once and accepts anything.
+func (p *RFQPolicy) Do(ctx context.Context, paramsSlice []RFQParams,
+	quote func() ([]*RFQResult, error)) ([]*RFQResult, error) {
+	if p == nil {
+		return quote()
+	}
+
+	var retries, requotes int
+	for attempt := 1; ; attempt++ {
+		results, err := quote()
+		if err == nil {
+			err = p.check(paramsSlice, results)
+		}
+		if err == nil {
+			return results, nil
+		}
+
+		rfqErr := NewRFQError(RFQReasonUnknown, err)
+		if handlerErr := (*RFQError)(nil); errors.As(err, &handlerErr) {
+			*rfqErr = *handlerErr
+		}
+		if rfqErr.PoolID == "" && len(paramsSlice) == 1 {
+			rfqErr.PoolID = paramsSlice[0].PoolID
+		}
+		rfqErr.Attempts = attempt
+
+		switch rfqErr.Reason {
+		case RFQReasonUnavailable:
+			if retries++; retries > p.MaxRetries {
+				return nil, rfqErr
+			}
+		case RFQReasonExpired, RFQReasonDeviation:
+			if requotes++; requotes > p.MaxRequotes {
+				return nil, rfqErr
+			}
+		default:
+			return nil, rfqErr
+		}
+
+		if p.RetryWait > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, rfqErr
+			case <-time.After(p.RetryWait):
+			}
+		} else if ctx.Err() != nil {
+			return nil, rfqErr
+		}
+	}
+}
+
+// check checks the validity and deviation of results, setting their DeviationBps.
+func (p *RFQPolicy) check(paramsSlice []RFQParams, results []*RFQResult) error {
+	minExpiry := time.Now().Add(p.MinValidity)
+	for i, result := range results {
+		if result == nil || i >= len(paramsSlice) {
+			continue
+		}
+		params := &paramsSlice[i]
+
+		if !result.ExpiresAt.IsZero() && result.ExpiresAt.Before(minExpiry) {
+			return &RFQError{Reason: RFQReasonExpired, PoolID: params.PoolID,
+				Err: fmt.Errorf("quote expires at %s", result.ExpiresAt)}
+		}
+
+		if result.NewAmountOut == nil || params.AmountOut == nil || params.AmountOut.Sign() <= 0 {
+			continue
+		}
+		deviation := new(big.Int).Sub(params.AmountOut, result.NewAmountOut)
+		deviation.Quo(deviation.Mul(deviation, bignumber.BasisPoint), params.AmountOut)
+		result.DeviationBps = deviation.Int64()
+		if maxDeviation := p.maxDeviationBps(params); maxDeviation > 0 && result.DeviationBps > maxDeviation {
+			return &RFQError{Reason: RFQReasonDeviation, PoolID: params.PoolID,
+				Err: fmt.Errorf("amount out %s deviates %d bps from %s", result.NewAmountOut, result.DeviationBps,
+					params.AmountOut)}
+		}
+	}
+	return nil
+}
+
+// maxDeviationBps is the tighter of MaxDeviationBps and the slippage of params.
+func (p *RFQPolicy) maxDeviationBps(params *RFQParams) int64 {
+	if p.MaxDeviationBps > 0 && (params.Slippage <= 0 || p.MaxDeviationBps < params.Slippage) {
+		return p.MaxDeviationBps
+	}
+	return params.Slippage
+}
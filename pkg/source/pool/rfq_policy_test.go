@@ -0,0 +1,162 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type emptyPoolManager struct{}
+
+func (emptyPoolManager) GetStateByPoolAddresses(context.Context, []string, []string,
+	ManagerExtraData) (*FindRouteState, error) {
+	return nil, nil
+}
+
+// quoter returns its results in turn, one per call.
+type quoter struct {
+	RFQHandler
+	results []*RFQResult
+	errs    []error
+	calls   int
+}
+
+func (q *quoter) RFQ(_ context.Context, _ RFQParams) (*RFQResult, error) {
+	i := q.calls
+	q.calls++
+	return q.results[i], q.errs[i]
+}
+
+func TestRFQWithPoolState_Policy(t *testing.T) {
+	t.Parallel()
+	params := RFQParams{PoolID: "pool", AmountOut: big.NewInt(10000), Slippage: 50}
+	live, expired := time.Now().Add(time.Minute), time.Now().Add(time.Second)
+	unavailable := NewRFQError(RFQReasonUnavailable, errors.New("503"))
+	policy := &RFQPolicy{MinValidity: 5 * time.Second, MaxDeviationBps: 100, MaxRetries: 1, MaxRequotes: 1}
+
+	tests := []struct {
+		name      string
+		policy    *RFQPolicy
+		results   []*RFQResult
+		errs      []error
+		wantOut   int64
+		wantDev   int64
+		wantCalls int
+		wantErr   RFQReason
+	}{
+		{
+			name:      "no policy accepts anything",
+			results:   []*RFQResult{{NewAmountOut: big.NewInt(1), ExpiresAt: expired}},
+			errs:      []error{nil},
+			wantOut:   1,
+			wantCalls: 1,
+		},
+		{
+			name:      "within slippage",
+			policy:    policy,
+			results:   []*RFQResult{{NewAmountOut: big.NewInt(9960), ExpiresAt: live}},
+			errs:      []error{nil},
+			wantOut:   9960,
+			wantDev:   40,
+			wantCalls: 1,
+		},
+		{
+			name:      "improvement",
+			policy:    policy,
+			results:   []*RFQResult{{NewAmountOut: big.NewInt(10100)}},
+			errs:      []error{nil},
+			wantOut:   10100,
+			wantDev:   -100,
+			wantCalls: 1,
+		},
+		{
+			name:   "re-quote deviation",
+			policy: policy,
+			results: []*RFQResult{
+				{NewAmountOut: big.NewInt(9900), ExpiresAt: live},
+				{NewAmountOut: big.NewInt(9990), ExpiresAt: live},
+			},
+			errs:      []error{nil, nil},
+			wantOut:   9990,
+			wantDev:   10,
+			wantCalls: 2,
+		},
+		{
+			name:   "re-quote expiry then reject",
+			policy: policy,
+			results: []*RFQResult{
+				{NewAmountOut: big.NewInt(10000), ExpiresAt: expired},
+				{NewAmountOut: big.NewInt(10000), ExpiresAt: expired},
+			},
+			errs:      []error{nil, nil},
+			wantCalls: 2,
+			wantErr:   RFQReasonExpired,
+		},
+		{
+			name:      "retry unavailable",
+			policy:    policy,
+			results:   []*RFQResult{nil, {NewAmountOut: big.NewInt(10000)}},
+			errs:      []error{unavailable, nil},
+			wantOut:   10000,
+			wantCalls: 2,
+		},
+		{
+			name:      "no retry of timed out quotes",
+			policy:    policy,
+			results:   []*RFQResult{nil, {NewAmountOut: big.NewInt(10000)}},
+			errs:      []error{NewRFQError(RFQReasonTransport, context.DeadlineExceeded), nil},
+			wantCalls: 1,
+			wantErr:   RFQReasonTransport,
+		},
+		{
+			name:      "reject deviation without re-quotes",
+			policy:    &RFQPolicy{},
+			results:   []*RFQResult{{NewAmountOut: big.NewInt(9900)}},
+			errs:      []error{nil},
+			wantCalls: 1,
+			wantErr:   RFQReasonDeviation,
+		},
+		{
+			name:      "reject unknown errors",
+			policy:    policy,
+			results:   []*RFQResult{nil, nil},
+			errs:      []error{errors.New("boom"), nil},
+			wantCalls: 1,
+			wantErr:   RFQReasonUnknown,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			q := &quoter{results: tc.results, errs: tc.errs}
+			h := &RFQWithPoolState{IPoolRFQ: q, IPoolManager: emptyPoolManager{}, Policy: tc.policy}
+
+			result, err := h.RFQ(context.Background(), params)
+			assert.Equal(t, tc.wantCalls, q.calls)
+			if tc.wantErr != "" {
+				var rfqErr *RFQError
+				require.ErrorAs(t, err, &rfqErr)
+				assert.Equal(t, tc.wantErr, rfqErr.Reason)
+				assert.Equal(t, "pool", rfqErr.PoolID)
+				assert.Equal(t, tc.wantCalls, rfqErr.Attempts)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantOut, result.NewAmountOut.Int64())
+			assert.Equal(t, tc.wantDev, result.DeviationBps)
+		})
+	}
+}
+
+func TestRFQReasonOf(t *testing.T) {
+	t.Parallel()
+	err := NewRFQError(RFQReasonRejected, errors.New("no liquidity"))
+	assert.Equal(t, RFQReasonRejected, RFQReasonOf(err))
+	assert.Equal(t, "rfq rejected: no liquidity", err.Error())
+	assert.Equal(t, RFQReasonUnknown, RFQReasonOf(errors.New("boom")))
+}
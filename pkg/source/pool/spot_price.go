@@ -0,0 +1,135 @@
+package pool
+
+import (
+	"context"
+	"math"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrSpotPriceUnsupported is returned by IPoolSpotPricer implementations that cannot price the current state in
+	// closed form (e.g. a hook or an empty active range). SpotPrice then falls back to NumericSpotPrice.
+	ErrSpotPriceUnsupported = errors.New("spot price is not supported")
+	ErrSpotPriceUnavailable = errors.New("spot price is unavailable")
+)
+
+const (
+	// spotPriceMinAmountOut is the amount out a probe swap must reach for its quote to have enough significant digits.
+	spotPriceMinAmountOut = 1e6
+	// spotPriceMaxProbes bounds the CalcAmountOut calls of NumericSpotPrice.
+	spotPriceMaxProbes = 12
+)
+
+// IPoolSpotPricer is an optional interface of pool simulators that compute their marginal price analytically.
+type IPoolSpotPricer interface {
+	// GetSpotPrice returns the marginal price of tokenOut per tokenIn at the current state, that is the limit of
+	// amountOut/amountIn as amountIn goes to 0, in wei of tokenOut per wei of tokenIn and net of swap fees.
+	GetSpotPrice(tokenIn, tokenOut string) (float64, error)
+}
+
+// SpotPrice returns the marginal price of tokenOut per tokenIn of poolSim, analytically if it implements
+// IPoolSpotPricer, numerically otherwise. A price that is not positive and finite, e.g. of a drained pool, is
+// reported as ErrSpotPriceUnavailable, so that it can safely be divided by.
+func SpotPrice(ctx context.Context, poolSim IPoolSimulator, tokenIn, tokenOut string) (float64, error) {
+	price, err := spotPrice(ctx, poolSim, tokenIn, tokenOut)
+	if err != nil {
+		return 0, err
+	} else if !(price > 0) || math.IsInf(price, 1) {
+		return 0, ErrSpotPriceUnavailable
+	}
+	return price, nil
+}
+
+func spotPrice(ctx context.Context, poolSim IPoolSimulator, tokenIn, tokenOut string) (float64, error) {
+	if pricer, ok := poolSim.(IPoolSpotPricer); ok {
+		price, err := pricer.GetSpotPrice(tokenIn, tokenOut)
+		if !errors.Is(err, ErrSpotPriceUnsupported) {
+			return price, err
+		}
+	}
+	return NumericSpotPrice(ctx, poolSim, tokenIn, tokenOut)
+}
+
+// NumericSpotPrice estimates the marginal price of tokenOut per tokenIn from CalcAmountOut: it quotes the smallest
+// amounts in h and 2h yielding enough precision, then extrapolates amountOut/amountIn to 0 with a Richardson step
+// 2·out(h)/h - out(2h)/2h, which cancels the first order price impact. poolSim is not modified.
+func NumericSpotPrice(ctx context.Context, poolSim IPoolSimulator, tokenIn, tokenOut string) (float64, error) {
+	var best float64
+	for _, amountIn := range spotPriceProbes(poolSim, tokenIn) {
+		out := quoteSpotPriceProbe(ctx, poolSim, tokenIn, tokenOut, amountIn)
+		if out == nil {
+			continue
+		}
+		h, _ := amountIn.Float64()
+		outH, _ := out.Float64()
+		best = outH / h
+		if outH < spotPriceMinAmountOut {
+			continue
+		}
+		out2 := quoteSpotPriceProbe(ctx, poolSim, tokenIn, tokenOut, new(big.Int).Lsh(amountIn, 1))
+		if out2 == nil {
+			break
+		}
+		out2H, _ := out2.Float64()
+		// a kink between h and 2h (e.g. a crossed tick) makes the extrapolation meaningless
+		if price := 2*outH/h - out2H/(2*h); price >= best && price <= 2*best {
+			return price, nil
+		}
+		return best, nil
+	}
+	if best > 0 {
+		return best, nil
+	}
+	return 0, ErrSpotPriceUnavailable
+}
+
+// PriceImpact returns the relative shortfall of the execution price of swapping tokenAmountIn for tokenOut on poolSim
+// from its spot price: 1 - (amountOut/amountIn) / spotPrice. Rounding may make it slightly negative for tiny swaps.
+func PriceImpact(ctx context.Context, poolSim IPoolSimulator, tokenAmountIn TokenAmount,
+	tokenOut string) (float64, error) {
+	spotPrice, err := SpotPrice(ctx, poolSim, tokenAmountIn.Token, tokenOut)
+	if err != nil {
+		return 0, err
+	}
+	res, err := CalcAmountOut(ctx, poolSim, tokenAmountIn, tokenOut, nil)
+	if err != nil {
+		return 0, err
+	} else if !res.IsValid() {
+		return 0, ErrSpotPriceUnavailable
+	}
+	amountIn, _ := tokenAmountIn.Amount.Float64()
+	amountOut, _ := res.TokenAmountOut.Amount.Float64()
+	return 1 - amountOut/amountIn/spotPrice, nil
+}
+
+func quoteSpotPriceProbe(ctx context.Context, poolSim IPoolSimulator, tokenIn, tokenOut string,
+	amountIn *big.Int) *big.Int {
+	res, err := CalcAmountOut(ctx, poolSim, TokenAmount{Token: tokenIn, Amount: amountIn}, tokenOut, nil)
+	if err != nil || !res.IsValid() ||
+		res.RemainingTokenAmountIn != nil && res.RemainingTokenAmountIn.Amount.Sign() > 0 {
+		return nil // failed or only partially filled
+	}
+	return res.TokenAmountOut.Amount
+}
+
+// spotPriceProbes returns increasing amounts of tokenIn to quote: from a millionth to a tenth of its reserve if known,
+// or powers of 1000 otherwise.
+func spotPriceProbes(poolSim IPoolSimulator, tokenIn string) []*big.Int {
+	probes := make([]*big.Int, 0, spotPriceMaxProbes)
+	if idx := poolSim.GetTokenIndex(tokenIn); idx >= 0 && idx < len(poolSim.GetReserves()) {
+		if reserve := poolSim.GetReserves()[idx]; reserve != nil && reserve.Cmp(big.NewInt(1e6)) >= 0 {
+			for exp := 6; exp >= 1; exp-- {
+				probes = append(probes, new(big.Int).Div(reserve, big.NewInt(int64(math.Pow10(exp)))))
+			}
+			return probes
+		}
+	}
+	amount := big.NewInt(1e3)
+	for range spotPriceMaxProbes {
+		probes = append(probes, amount)
+		amount = new(big.Int).Mul(amount, big.NewInt(1e3))
+	}
+	return probes
+}
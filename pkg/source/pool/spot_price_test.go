@@ -0,0 +1,88 @@
+package pool_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+type unsupportedSpotPricer struct {
+	IPoolSimulator
+}
+
+func (unsupportedSpotPricer) GetSpotPrice(_, _ string) (float64, error) {
+	return 0, ErrSpotPriceUnsupported
+}
+
+// drainedSpotPricer prices like a pool without liquidity on the tokenOut side.
+type drainedSpotPricer struct {
+	IPoolSimulator
+}
+
+func (drainedSpotPricer) GetSpotPrice(_, _ string) (float64, error) {
+	return 0, nil
+}
+
+func TestNumericSpotPrice(t *testing.T) {
+	t.Parallel()
+	poolSim := newExactOutTestPool(t)
+	tokens, reserves := poolSim.GetTokens(), poolSim.GetReserves()
+	for i, j := range []int{1, 0} {
+		r0, _ := reserves[i].Float64()
+		r1, _ := reserves[j].Float64()
+		want := r1 / r0 * 0.9975
+
+		got, err := NumericSpotPrice(ctx, poolSim, tokens[i], tokens[j])
+		require.NoError(t, err)
+		assert.InEpsilon(t, want, got, 1e-9)
+
+		got, err = SpotPrice(ctx, unsupportedSpotPricer{poolSim}, tokens[i], tokens[j])
+		require.NoError(t, err)
+		assert.InEpsilon(t, want, got, 1e-9)
+	}
+
+	_, err := NumericSpotPrice(ctx, poolSim, tokens[0], "0x0000000000000000000000000000000000000000")
+	assert.ErrorIs(t, err, ErrSpotPriceUnavailable)
+}
+
+func TestPriceImpact(t *testing.T) {
+	t.Parallel()
+	poolSim := newExactOutTestPool(t)
+	tokens := poolSim.GetTokens()
+	var prev float64
+	for _, amountIn := range []string{"1000000000000000000", "1000000000000000000000", "1000000000000000000000000"} {
+		impact, err := PriceImpact(ctx, poolSim, TokenAmount{Token: tokens[0], Amount: bignumber.NewBig(amountIn)},
+			tokens[1])
+		require.NoError(t, err)
+		assert.Greater(t, impact, prev)
+		assert.Less(t, impact, 1.)
+		prev = impact
+	}
+
+	// a uniswap v2 swap of x% of the reserve in has a price impact of x/(1+x)
+	reserveIn, _ := poolSim.GetReserves()[0].Float64()
+	impact, err := PriceImpact(ctx, poolSim, TokenAmount{Token: tokens[0],
+		Amount: new(big.Int).Div(poolSim.GetReserves()[0], big.NewInt(100))}, tokens[1])
+	require.NoError(t, err)
+	x := reserveIn / 100 * 0.9975 / reserveIn
+	assert.InEpsilon(t, x/(1+x), impact, 1e-6)
+}
+
+func TestPriceImpact_ZeroSpotPrice(t *testing.T) {
+	t.Parallel()
+	poolSim := drainedSpotPricer{newExactOutTestPool(t)}
+	tokens := poolSim.GetTokens()
+
+	_, err := SpotPrice(ctx, poolSim, tokens[0], tokens[1])
+	assert.ErrorIs(t, err, ErrSpotPriceUnavailable)
+	_, err = PriceImpact(ctx, poolSim, TokenAmount{Token: tokens[0], Amount: bignumber.NewBig("1000000000000000000")},
+		tokens[1])
+	assert.ErrorIs(t, err, ErrSpotPriceUnavailable)
+	_, err = DepthCurve(ctx, poolSim, tokens[0], tokens[1], DepthCurveOptions{})
+	assert.ErrorIs(t, err, ErrSpotPriceUnavailable)
+}
@@ -7,14 +7,8 @@ import (
 )
 
 var (
-	chainlinkABI       abi.ABI
-	fastPriceFeedV1ABI abi.ABI
-	fastPriceFeedV2ABI abi.ABI
-	pancakePairABI     abi.ABI
-	priceFeedABI       abi.ABI
-	vaultABI           abi.ABI
-	vaultPriceFeedABI  abi.ABI
-	erc20ABI           abi.ABI
+	priceFeedABI      abi.ABI
+	vaultPriceFeedABI abi.ABI
 )
 
 func init() {
@@ -22,14 +16,8 @@ func init() {
 		ABI  *abi.ABI
 		data []byte
 	}{
-		{&chainlinkABI, chainlinkFlagsJson},
-		{&fastPriceFeedV1ABI, fastPriceFeedV1Json},
-		{&fastPriceFeedV2ABI, fastPriceFeedV2Json},
-		{&pancakePairABI, pancakePairJson},
 		{&priceFeedABI, priceFeedJson},
-		{&vaultABI, vaultJson},
 		{&vaultPriceFeedABI, vaultPriceFeedJson},
-		{&erc20ABI, erc20Json},
 	}
 
 	for _, b := range builder {
@@ -1,16 +1,24 @@
 package quickperps
 
-import "math/big"
+const (
+	DexTypeQuickperps = "quickperps"
 
-const DexTypeQuickperps = "quickperps"
+	// usdqForkName is the name of USDG in the vault of QuickPerps
+	usdqForkName = "usdq"
 
-const flagArbitrumSeqOffline = "0xa438451d6458044c3c8cd2f6f31c91ac882a6d91"
+	priceFeedMethodRead = "read"
 
-var (
-	DefaultGas             = Gas{Swap: 286524}
-	BasisPointsDivisor     = big.NewInt(10000)
-	PricePrecision         = new(big.Int).Exp(big.NewInt(10), big.NewInt(30), nil)
-	USDQDecimals           = big.NewInt(18)
-	OneUSD                 = PricePrecision
-	FlagArbitrumSeqOffline = "0xa438451d6458044c3c8cd2f6f31c91ac882a6d91"
+	vaultPriceFeedMethodFavorPrimaryPrice          = "favorPrimaryPrice"
+	vaultPriceFeedMethodIsSecondaryPriceEnabled    = "isSecondaryPriceEnabled"
+	vaultPriceFeedMethodMaxStrictPriceDeviation    = "maxStrictPriceDeviation"
+	vaultPriceFeedMethodSecondaryPriceFeed         = "secondaryPriceFeed"
+	vaultPriceFeedMethodSpreadThresholdBasisPoints = "spreadThresholdBasisPoints"
+	vaultPriceFeedMethodExpireTimeForPriceFeed     = "expireTimeForPriceFeed"
+
+	vaultPriceFeedMethodPriceFeedProxies      = "priceFeedProxies"
+	vaultPriceFeedMethodPriceDecimals         = "priceDecimals"
+	vaultPriceFeedMethodSpreadBasisPoints     = "spreadBasisPoints"
+	vaultPriceFeedMethodAdjustmentBasisPoints = "adjustmentBasisPoints"
+	vaultPriceFeedMethodStrictStableTokens    = "strictStableTokens"
+	vaultPriceFeedMethodIsAdjustmentAdditive  = "isAdjustmentAdditive"
 )
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	gmxv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-v1"
 	poolPkg "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/testutil"
@@ -81,12 +82,12 @@ func TestPool_CalcAmountOut(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			var extra Extra
+			var extra gmxv1.Extra
 			err := json.Unmarshal([]byte(tc.entityPool.Extra), &extra)
 			assert.Nil(t, err)
 
-			extra.Vault.PriceFeed.PriceFeedProxies["0x4f9a0e7fd2bf6067db6994cf12e4495df938e6e9"].Timestamp = uint32(time.Now().Unix())
-			extra.Vault.PriceFeed.PriceFeedProxies["0xc5015b9d9161dca7e18e32f6f25c4ad850731fd4"].Timestamp = uint32(time.Now().Unix())
+			extra.Vault.PriceFeed.PriceFeeds["0x4f9a0e7fd2bf6067db6994cf12e4495df938e6e9"].Timestamp = uint32(time.Now().Unix())
+			extra.Vault.PriceFeed.PriceFeeds["0xc5015b9d9161dca7e18e32f6f25c4ad850731fd4"].Timestamp = uint32(time.Now().Unix())
 
 			extraBytes, err := json.Marshal(&extra)
 			assert.Nil(t, err)
@@ -7,8 +7,11 @@ import (
 	"github.com/KyberNetwork/ethrpc"
 	"github.com/KyberNetwork/logger"
 	"github.com/ethereum/go-ethereum/accounts/abi"
+
+	gmxv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-v1"
 )
 
+// PriceFeedReader reads the price feed proxies of QuickPerps, which answer read() with a price and its timestamp.
 type PriceFeedReader struct {
 	abi          abi.ABI
 	ethrpcClient *ethrpc.Client
@@ -26,8 +29,8 @@ func NewPriceFeedReader(ethrpcClient *ethrpc.Client) *PriceFeedReader {
 	}
 }
 
-func (r *PriceFeedReader) Read(ctx context.Context, address string) (*PriceFeed, error) {
-	priceFeed := &PriceFeed{}
+func (r *PriceFeedReader) Read(ctx context.Context, address string, _ int) (*gmxv1.PriceFeed, error) {
+	priceFeed := gmxv1.NewPriceFeed()
 
 	if err := r.read(ctx, address, priceFeed); err != nil {
 		r.log.Errorf("error when get latest round data: %s", err)
@@ -37,14 +40,14 @@ func (r *PriceFeedReader) Read(ctx context.Context, address string) (*PriceFeed,
 	return priceFeed, nil
 }
 
-func (r *PriceFeedReader) read(ctx context.Context, address string, priceFeed *PriceFeed) error {
+func (r *PriceFeedReader) read(ctx context.Context, address string, priceFeed *gmxv1.PriceFeed) error {
 	type State struct {
 		Value     *big.Int `json:"value"`
 		Timestamp uint32   `json:"timestamp"`
 	}
 	priceFeedState := State{}
 
-	rpcRequest := r.ethrpcClient.NewRequest().SetContext(ctx)
+	rpcRequest := gmxv1.NewRequest(r.ethrpcClient, ctx)
 
 	rpcRequest.AddCall(&ethrpc.Call{
 		ABI:    r.abi,
@@ -57,7 +60,7 @@ func (r *PriceFeedReader) read(ctx context.Context, address string, priceFeed *P
 		return err
 	}
 
-	priceFeed.Price = priceFeedState.Value
+	priceFeed.Answer = priceFeedState.Value
 	priceFeed.Timestamp = priceFeedState.Timestamp
 
 	return nil
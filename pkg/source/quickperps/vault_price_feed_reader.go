@@ -8,8 +8,11 @@ import (
 	"github.com/KyberNetwork/logger"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+
+	gmxv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-v1"
 )
 
+// VaultPriceFeedReader reads the VaultPriceFeed of QuickPerps, which prices tokens with price feed proxies.
 type VaultPriceFeedReader struct {
 	abi          abi.ABI
 	ethrpcClient *ethrpc.Client
@@ -31,8 +34,9 @@ func (r *VaultPriceFeedReader) Read(
 	ctx context.Context,
 	address string,
 	tokens []string,
-) (*VaultPriceFeed, error) {
-	vaultPriceFeed := NewVaultPriceFeed()
+) (*gmxv1.VaultPriceFeed, error) {
+	vaultPriceFeed := gmxv1.NewVaultPriceFeed()
+	vaultPriceFeed.PriceFeedType = gmxv1.PriceFeedTypeProxy
 
 	if err := r.readData(ctx, address, vaultPriceFeed); err != nil {
 		r.log.Errorf("error when read data: %s", err)
@@ -48,28 +52,19 @@ func (r *VaultPriceFeedReader) Read(
 }
 
 // readData reads data which required no parameters, included:
-// - BNB
-// - BNBBUSD
-// - BTC
-// - BTCBNB
-// - ChainlinkFlags
-// - ETH
-// - ETHBNB
 // - FavorPrimaryPrice
-// - IsAmmEnabled
 // - IsSecondaryPriceEnabled
 // - MaxStrictPriceDeviation
-// - PriceSampleSpace
 // - SecondaryPriceFeed
 // - SpreadThresholdBasisPoints
-// - UseV2Pricing
+// - ExpireTimeForPriceFeed
 func (r *VaultPriceFeedReader) readData(
 	ctx context.Context,
 	address string,
-	vaultPriceFeed *VaultPriceFeed,
+	vaultPriceFeed *gmxv1.VaultPriceFeed,
 ) error {
-	callParamsFactory := CallParamsFactory(r.abi, address)
-	rpcRequest := r.ethrpcClient.NewRequest().SetContext(ctx)
+	callParamsFactory := gmxv1.CallParamsFactory(r.abi, address)
+	rpcRequest := gmxv1.NewRequest(r.ethrpcClient, ctx)
 
 	rpcRequest.AddCall(callParamsFactory(vaultPriceFeedMethodFavorPrimaryPrice, nil), []any{&vaultPriceFeed.FavorPrimaryPrice})
 	rpcRequest.AddCall(callParamsFactory(vaultPriceFeedMethodIsSecondaryPriceEnabled, nil), []any{&vaultPriceFeed.IsSecondaryPriceEnabled})
@@ -96,7 +91,7 @@ func (r *VaultPriceFeedReader) readData(
 func (r *VaultPriceFeedReader) readTokenData(
 	ctx context.Context,
 	address string,
-	vaultPriceFeed *VaultPriceFeed,
+	vaultPriceFeed *gmxv1.VaultPriceFeed,
 	tokens []string,
 ) error {
 	tokensLen := len(tokens)
@@ -108,8 +103,8 @@ func (r *VaultPriceFeedReader) readTokenData(
 	strictStableTokens := make([]bool, tokensLen)
 	isAdjustmentAdditive := make([]bool, tokensLen)
 
-	callParamsFactory := CallParamsFactory(r.abi, address)
-	rpcRequest := r.ethrpcClient.NewRequest().SetContext(ctx)
+	callParamsFactory := gmxv1.CallParamsFactory(r.abi, address)
+	rpcRequest := gmxv1.NewRequest(r.ethrpcClient, ctx)
 
 	for i, token := range tokens {
 		tokenAddress := common.HexToAddress(token)
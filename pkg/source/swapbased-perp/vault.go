@@ -1,144 +0,0 @@
-package swapbasedperp
-
-import (
-	"math/big"
-
-	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
-	"github.com/ethereum/go-ethereum/common"
-)
-
-type Vault struct {
-	HasDynamicFees           bool     `json:"hasDynamicFees"`
-	IncludeAmmPrice          bool     `json:"includeAmmPrice"`
-	IsSwapEnabled            bool     `json:"isSwapEnabled"`
-	StableSwapFeeBasisPoints *big.Int `json:"stableSwapFeeBasisPoints"`
-	StableTaxBasisPoints     *big.Int `json:"stableTaxBasisPoints"`
-	SwapFeeBasisPoints       *big.Int `json:"swapFeeBasisPoints"`
-	TaxBasisPoints           *big.Int `json:"taxBasisPoints"`
-	TotalTokenWeights        *big.Int `json:"totalTokenWeights"`
-
-	WhitelistedTokens []string            `json:"whitelistedTokens"`
-	PoolAmounts       map[string]*big.Int `json:"poolAmounts"`
-	BufferAmounts     map[string]*big.Int `json:"bufferAmounts"`
-	ReservedAmounts   map[string]*big.Int `json:"reservedAmounts"`
-	TokenDecimals     map[string]*big.Int `json:"tokenDecimals"`
-	StableTokens      map[string]bool     `json:"stableTokens"`
-	USDBAmounts       map[string]*big.Int `json:"usdbAmounts"`
-	MaxUSDBAmounts    map[string]*big.Int `json:"maxUsdbAmounts"`
-	TokenWeights      map[string]*big.Int `json:"tokenWeights"`
-
-	PriceFeedAddress common.Address  `json:"-"`
-	PriceFeed        *VaultPriceFeed `json:"priceFeed"`
-
-	USDBAddress common.Address `json:"-"`
-	USDB        *USDB          `json:"usdb"`
-
-	WhitelistedTokensCount *big.Int `json:"-"`
-
-	UseSwapPricing bool // not used, always false for now
-}
-
-func NewVault() *Vault {
-	return &Vault{
-		PoolAmounts:     make(map[string]*big.Int),
-		BufferAmounts:   make(map[string]*big.Int),
-		ReservedAmounts: make(map[string]*big.Int),
-		TokenDecimals:   make(map[string]*big.Int),
-		StableTokens:    make(map[string]bool),
-		USDBAmounts:     make(map[string]*big.Int),
-		MaxUSDBAmounts:  make(map[string]*big.Int),
-		TokenWeights:    make(map[string]*big.Int),
-	}
-}
-
-const (
-	vaultMethodHasDynamicFees           = "hasDynamicFees"
-	vaultMethodIncludeAmmPrice          = "includeAmmPrice"
-	vaultMethodIsSwapEnabled            = "isSwapEnabled"
-	vaultMethodPriceFeed                = "priceFeed"
-	vaultMethodStableSwapFeeBasisPoints = "stableSwapFeeBasisPoints"
-	vaultMethodStableTaxBasisPoints     = "stableTaxBasisPoints"
-	vaultMethodSwapFeeBasisPoints       = "swapFeeBasisPoints"
-	vaultMethodTaxBasisPoints           = "taxBasisPoints"
-	vaultMethodTotalTokenWeights        = "totalTokenWeights"
-	vaultMethodUSDB                     = "usdb"
-	vaultMethodWhitelistedTokenCount    = "whitelistedTokenCount"
-
-	vaultMethodAllWhitelistedTokens = "allWhitelistedTokens"
-
-	vaultMethodPoolAmounts     = "poolAmounts"
-	vaultMethodBufferAmounts   = "bufferAmounts"
-	vaultMethodReservedAmounts = "reservedAmounts"
-	vaultMethodTokenDecimals   = "tokenDecimals"
-	vaultMethodStableTokens    = "stableTokens"
-	vaultMethodUSDBAmounts     = "usdbAmounts"
-	vaultMethodMaxUSDBAmounts  = "maxUsdbAmounts"
-	vaultMethodTokenWeights    = "tokenWeights"
-)
-
-func (v *Vault) GetMinPrice(token string) (*big.Int, error) {
-	return v.PriceFeed.GetPrice(token, false, v.IncludeAmmPrice, v.UseSwapPricing)
-}
-
-func (v *Vault) GetMaxPrice(token string) (*big.Int, error) {
-	return v.PriceFeed.GetPrice(token, true, v.IncludeAmmPrice, v.UseSwapPricing)
-}
-
-func (v *Vault) GetTargetUSDBAmount(token string) *big.Int {
-	supply := v.USDB.TotalSupply
-
-	if supply.Cmp(bignumber.ZeroBI) == 0 {
-		return bignumber.ZeroBI
-	}
-
-	weight := v.TokenWeights[token]
-
-	return new(big.Int).Div(new(big.Int).Mul(weight, supply), v.TotalTokenWeights)
-}
-
-func (v *Vault) AdjustForDecimals(amount *big.Int, tokenDiv string, tokenMul string) *big.Int {
-	var decimalsDiv *big.Int
-	if tokenDiv == v.USDB.Address {
-		decimalsDiv = USDBDecimals
-	} else {
-		decimalsDiv = v.TokenDecimals[tokenDiv]
-	}
-
-	var decimalsMul *big.Int
-	if tokenMul == v.USDB.Address {
-		decimalsMul = USDBDecimals
-	} else {
-		decimalsMul = v.TokenDecimals[tokenMul]
-	}
-
-	return new(big.Int).Div(
-		new(big.Int).Mul(
-			amount,
-			new(big.Int).Exp(big.NewInt(10), decimalsMul, nil),
-		),
-		new(big.Int).Exp(big.NewInt(10), decimalsDiv, nil),
-	)
-}
-
-func (v *Vault) IncreaseUSDBAmount(token string, amount *big.Int) {
-	v.USDBAmounts[token] = new(big.Int).Add(v.USDBAmounts[token], amount)
-}
-
-func (v *Vault) DecreaseUSDBAmount(token string, amount *big.Int) {
-	currentUSDBAmount := v.USDBAmounts[token]
-
-	if currentUSDBAmount.Cmp(amount) < 0 {
-		v.USDBAmounts[token] = bignumber.ZeroBI
-		return
-	}
-
-	v.USDBAmounts[token] = new(big.Int).Sub(v.USDBAmounts[token], amount)
-}
-
-func (v *Vault) IncreasePoolAmount(token string, amount *big.Int) {
-	v.PoolAmounts[token] = new(big.Int).Add(v.PoolAmounts[token], amount)
-}
-
-func (v *Vault) DecreasePoolAmount(token string, amount *big.Int) {
-	v.PoolAmounts[token] = new(big.Int).Sub(v.PoolAmounts[token], amount)
-}
@@ -7,13 +7,7 @@ import (
 )
 
 var (
-	fastPriceFeedV1ABI abi.ABI
-	fastPriceFeedV2ABI abi.ABI
-	pancakePairABI     abi.ABI
-	priceFeedABI       abi.ABI
-	vaultABI           abi.ABI
-	vaultPriceFeedABI  abi.ABI
-	erc20ABI           abi.ABI
+	priceFeedABI abi.ABI
 )
 
 func init() {
@@ -21,13 +15,7 @@ func init() {
 		ABI  *abi.ABI
 		data []byte
 	}{
-		{&fastPriceFeedV1ABI, fastPriceFeedV1Json},
-		{&fastPriceFeedV2ABI, fastPriceFeedV2Json},
-		{&pancakePairABI, pancakePairJson},
 		{&priceFeedABI, priceFeedJson},
-		{&vaultABI, vaultJson},
-		{&vaultPriceFeedABI, vaultPriceFeedJson},
-		{&erc20ABI, erc20Json},
 	}
 
 	for _, b := range builder {
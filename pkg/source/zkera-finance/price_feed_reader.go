@@ -3,14 +3,17 @@ package zkerafinance
 import (
 	"context"
 	"math/big"
+	"strconv"
 
 	"github.com/KyberNetwork/ethrpc"
 	"github.com/KyberNetwork/logger"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 
+	gmxv1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx-v1"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 )
 
+// PriceFeedReader reads the price feeds of zkEra Finance, which answer latestAnswer(maximise).
 type PriceFeedReader struct {
 	abi          abi.ABI
 	ethrpcClient *ethrpc.Client
@@ -28,12 +31,12 @@ func NewPriceFeedReader(ethrpcClient *ethrpc.Client) *PriceFeedReader {
 	}
 }
 
-func (r *PriceFeedReader) Read(ctx context.Context, address string) (*PriceFeed, error) {
-	priceFeed := NewPriceFeed()
+func (r *PriceFeedReader) Read(ctx context.Context, address string, _ int) (*gmxv1.PriceFeed, error) {
+	priceFeed := gmxv1.NewPriceFeed()
 
 	var v0, v1 *big.Int
 
-	rpcRequest := newRequest(r.ethrpcClient, ctx)
+	rpcRequest := gmxv1.NewRequest(r.ethrpcClient, ctx)
 
 	rpcRequest.AddCall(&ethrpc.Call{
 		ABI:    r.abi,
@@ -50,7 +53,7 @@ func (r *PriceFeedReader) Read(ctx context.Context, address string) (*PriceFeed,
 	}, []any{&v1})
 
 	if _, err := rpcRequest.TryAggregate(); err != nil {
-		logger.Errorf("error when call rpcRequest.Aggregate: %s | %s", err.Error(), address)
+		r.log.Errorf("error when call rpcRequest.Aggregate: %s | %s", err.Error(), address)
 		return nil, err
 	}
 
@@ -61,8 +64,8 @@ func (r *PriceFeedReader) Read(ctx context.Context, address string) (*PriceFeed,
 		v1 = bignumber.ZeroBI
 	}
 
-	priceFeed.LatestAnswers[maximizeTrue] = v0
-	priceFeed.LatestAnswers[maximizeFalse] = v1
+	priceFeed.Answers[strconv.FormatBool(true)] = v0
+	priceFeed.Answers[strconv.FormatBool(false)] = v1
 
 	return priceFeed, nil
 }
@@ -0,0 +1,121 @@
+package swaplimit
+
+import (
+	"maps"
+	"math/big"
+	"sync"
+	"sync/atomic"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+// IMakerPool is a pool of one of the makers sharing an exchange, e.g. a PMM whose pools are <maker>_<token0>_<token1>.
+type IMakerPool interface {
+	pool.IPoolSimulator
+	GetMaker() string
+}
+
+// MakerKey returns the key of the balance of token of maker in a MakerInventory.
+func MakerKey(maker, token string) string {
+	return maker + ":" + token
+}
+
+// MakerInventory is an Inventory of several makers sharing an exchange, keyed by MakerKey, so that swaps of one maker
+// never spend the balances of another. Legs of a route can be reserved together by Reserve, and rolled back.
+type MakerInventory struct {
+	Inventory
+}
+
+// NewMakerInventory creates a new MakerInventory from the balances of each maker by token.
+func NewMakerInventory(exchange string, balances map[string]map[string]*big.Int) *MakerInventory {
+	balance := make(map[string]*big.Int)
+	for maker, tokenBalances := range balances {
+		for token, amount := range tokenBalances {
+			balance[MakerKey(maker, token)] = amount
+		}
+	}
+	return &MakerInventory{
+		Inventory: Inventory{
+			exchange: exchange,
+			lock:     &sync.RWMutex{},
+			balance:  balance,
+		},
+	}
+}
+
+// NewMakerInventoryFromPools creates a new MakerInventory from the limits of pools. Pools of the same maker report the
+// same balances, so the largest report of each is kept.
+func NewMakerInventoryFromPools(exchange string, pools []IMakerPool) *MakerInventory {
+	balances := make(map[string]map[string]*big.Int)
+	for _, p := range pools {
+		maker := p.GetMaker()
+		if balances[maker] == nil {
+			balances[maker] = make(map[string]*big.Int)
+		}
+		for token, amount := range p.CalculateLimit() {
+			if current := balances[maker][token]; amount != nil && (current == nil || amount.Cmp(current) > 0) {
+				balances[maker][token] = amount
+			}
+		}
+	}
+	return NewMakerInventory(exchange, balances)
+}
+
+// Clone clones MakerInventory. Only guarantees that UpdateLimit and Reserve of the original do not affect the clone.
+func (i *MakerInventory) Clone() pool.SwapLimit {
+	return &MakerInventory{
+		Inventory: Inventory{
+			exchange: i.exchange,
+			lock:     &sync.RWMutex{},
+			balance:  maps.Clone(i.balance),
+		},
+	}
+}
+
+// Leg is the inventory change of a swap, as in UpdateLimit.
+type Leg struct {
+	DecreaseKey, IncreaseKey     string
+	DecreaseDelta, IncreaseDelta *big.Int
+}
+
+// Reservation is a set of legs applied together to a MakerInventory, until released.
+type Reservation struct {
+	inventory *MakerInventory
+	legs      []Leg
+	released  atomic.Bool
+}
+
+// Reserve applies legs in order, all or none: if a leg exceeds its balance, the legs before it are rolled back. Legs
+// spending the same balance are checked against what the previous ones left.
+func (i *MakerInventory) Reserve(legs ...Leg) (*Reservation, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	for j, leg := range legs {
+		if _, _, err := i.updateLimit(leg.DecreaseKey, leg.IncreaseKey, leg.DecreaseDelta,
+			leg.IncreaseDelta); err != nil {
+			i.revert(legs[:j])
+			return nil, err
+		}
+	}
+	return &Reservation{inventory: i, legs: legs}, nil
+}
+
+// Release rolls back the legs of the reservation. Releasing more than once has no effect.
+func (r *Reservation) Release() {
+	if r == nil || !r.released.CompareAndSwap(false, true) {
+		return
+	}
+	r.inventory.lock.Lock()
+	defer r.inventory.lock.Unlock()
+	r.inventory.revert(r.legs)
+}
+
+// revert undoes legs in reverse order. Creates new *big.Int as updateLimit does.
+func (i *MakerInventory) revert(legs []Leg) {
+	for j := len(legs) - 1; j >= 0; j-- {
+		leg := legs[j]
+		i.balance[leg.IncreaseKey] = new(big.Int).Sub(i.balance[leg.IncreaseKey], leg.IncreaseDelta)
+		i.balance[leg.DecreaseKey] = new(big.Int).Add(i.balance[leg.DecreaseKey], leg.DecreaseDelta)
+	}
+}
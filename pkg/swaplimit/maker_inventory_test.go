@@ -0,0 +1,52 @@
+package swaplimit
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+func TestMakerInventory_Reserve(t *testing.T) {
+	t.Parallel()
+	a, b := MakerKey("maker-a", "usdc"), MakerKey("maker-b", "usdc")
+	aIn := MakerKey("maker-a", "usdt")
+	inventory := NewMakerInventory("pmm-1", map[string]map[string]*big.Int{
+		"maker-a": {"usdc": big.NewInt(100)},
+		"maker-b": {"usdc": big.NewInt(50)},
+	})
+	limit := func(key string) int64 { return inventory.GetLimit(key).Int64() }
+
+	// two legs spending the same maker balance are checked together, and roll back on failure
+	_, err := inventory.Reserve(
+		Leg{DecreaseKey: a, IncreaseKey: aIn, DecreaseDelta: big.NewInt(60), IncreaseDelta: big.NewInt(61)},
+		Leg{DecreaseKey: a, IncreaseKey: aIn, DecreaseDelta: big.NewInt(60), IncreaseDelta: big.NewInt(61)},
+	)
+	assert.ErrorIs(t, err, pool.ErrNotEnoughInventory)
+	assert.Equal(t, int64(100), limit(a))
+	assert.Equal(t, int64(0), limit(aIn))
+
+	cloned := inventory.Clone()
+	reservation, err := inventory.Reserve(
+		Leg{DecreaseKey: a, IncreaseKey: aIn, DecreaseDelta: big.NewInt(60), IncreaseDelta: big.NewInt(61)},
+		Leg{DecreaseKey: b, IncreaseKey: aIn, DecreaseDelta: big.NewInt(50), IncreaseDelta: big.NewInt(51)},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, int64(40), limit(a))
+	assert.Equal(t, int64(0), limit(b))
+	assert.Equal(t, int64(112), limit(aIn))
+	assert.Equal(t, int64(100), cloned.GetLimit(a).Int64())
+
+	_, err = inventory.Reserve(Leg{DecreaseKey: MakerKey("maker-c", "usdc"), IncreaseKey: aIn,
+		DecreaseDelta: big.NewInt(1), IncreaseDelta: big.NewInt(1)})
+	assert.ErrorIs(t, err, pool.ErrTokenNotAvailable)
+
+	reservation.Release()
+	reservation.Release()
+	assert.Equal(t, int64(100), limit(a))
+	assert.Equal(t, int64(50), limit(b))
+	assert.Equal(t, int64(0), limit(aIn))
+}
@@ -0,0 +1,175 @@
+package transfertax
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/evmstate"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+const (
+	// DefaultTTL is how long detected taxes are cached by default.
+	DefaultTTL = time.Hour
+	// DefaultMaxEntries is the default number of detected taxes cached.
+	DefaultMaxEntries = 100000
+)
+
+// ErrUndetectable is returned for tokens whose balances cannot be located in storage, such as reflection tokens.
+var ErrUndetectable = errors.New("transfer tax is undetectable")
+
+type Config struct {
+	ChainID valueobject.ChainID `json:"chainID"`
+	// TTL is how long detected taxes are cached, DefaultTTL if 0.
+	TTL time.Duration `json:"ttl"`
+	// MaxEntries is the number of detected taxes cached, DefaultMaxEntries if 0. Past it, expired entries are evicted,
+	// then the oldest one.
+	MaxEntries int `json:"maxEntries"`
+}
+
+// Detector detects the transfer tax of tokens by simulating transfers to and from a pool locally, over a snapshot of
+// the token state collected with eth_getProof, and caches the taxes per token and pool. Tokens often tax transfers
+// only to and from the pairs they know of, so the tax of a token is only valid for the pool it is detected with.
+type Detector struct {
+	config       Config
+	ethrpcClient *ethrpc.Client
+
+	mu    sync.Mutex
+	cache map[cacheKey]cached
+}
+
+type cacheKey struct {
+	token, pool common.Address
+}
+
+type cached struct {
+	tax        Tax
+	err        error
+	detectedAt time.Time
+}
+
+func NewDetector(config Config, ethrpcClient *ethrpc.Client) *Detector {
+	if config.TTL <= 0 {
+		config.TTL = DefaultTTL
+	}
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = DefaultMaxEntries
+	}
+	return &Detector{
+		config:       config,
+		ethrpcClient: ethrpcClient,
+		cache:        make(map[cacheKey]cached),
+	}
+}
+
+// NewConfiguredDetector returns the Detector of config for trackers, on chainID unless config sets its own, or nil if
+// config is nil and detection is disabled.
+func NewConfiguredDetector(config *Config, chainID valueobject.ChainID, ethrpcClient *ethrpc.Client) *Detector {
+	if config == nil {
+		return nil
+	}
+	cfg := *config
+	if cfg.ChainID == 0 {
+		cfg.ChainID = chainID
+	}
+	return NewDetector(cfg, ethrpcClient)
+}
+
+// Detect returns the tax of token as seen by pool, from cache if detected within the TTL. Undetectable taxes are
+// cached as well, with ErrUndetectable.
+func (d *Detector) Detect(ctx context.Context, token, pool string) (Tax, error) {
+	key := cacheKey{token: common.HexToAddress(token), pool: common.HexToAddress(pool)}
+	d.mu.Lock()
+	entry, ok := d.cache[key]
+	d.mu.Unlock()
+	if ok && time.Since(entry.detectedAt) < d.config.TTL {
+		return entry.tax, entry.err
+	}
+
+	tax, err := d.detect(ctx, key)
+	if err != nil && !errors.Is(err, ErrUndetectable) {
+		return Tax{}, err
+	}
+	d.mu.Lock()
+	if _, ok := d.cache[key]; !ok && len(d.cache) >= d.config.MaxEntries {
+		d.evict()
+	}
+	d.cache[key] = cached{tax: tax, err: err, detectedAt: time.Now()}
+	d.mu.Unlock()
+	return tax, err
+}
+
+// evict makes room for an entry, dropping the expired entries, or the oldest one if none is. d.mu must be held.
+func (d *Detector) evict() {
+	var oldestKey cacheKey
+	var oldest time.Time
+	for key, entry := range d.cache {
+		if time.Since(entry.detectedAt) >= d.config.TTL {
+			delete(d.cache, key)
+		} else if oldest.IsZero() || entry.detectedAt.Before(oldest) {
+			oldestKey, oldest = key, entry.detectedAt
+		}
+	}
+	if len(d.cache) >= d.config.MaxEntries {
+		delete(d.cache, oldestKey)
+	}
+}
+
+// DetectPool returns the taxes of the tokens of p, leaving out untaxed and undetectable ones.
+func (d *Detector) DetectPool(ctx context.Context, p entity.Pool) (Taxes, error) {
+	taxes := make(Taxes)
+	for _, token := range p.Tokens {
+		tax, err := d.Detect(ctx, token.Address, p.Address)
+		if errors.Is(err, ErrUndetectable) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to detect the tax of %s: %w", token.Address, err)
+		}
+		if !tax.IsZero() {
+			taxes[strings.ToLower(token.Address)] = tax
+		}
+	}
+	return taxes, nil
+}
+
+// Track detects the taxes of the tokens of p and persists them in its Extra with SetPoolTaxes, for the simulators
+// built by RegisterFactory to apply. It returns p as is if d is nil, so that trackers can call it whether or not
+// detection is configured.
+func (d *Detector) Track(ctx context.Context, p entity.Pool) (entity.Pool, error) {
+	if d == nil {
+		return p, nil
+	}
+	taxes, err := d.DetectPool(ctx, p)
+	if err != nil {
+		return p, err
+	}
+	if err = SetPoolTaxes(&p, taxes); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+func (d *Detector) detect(ctx context.Context, key cacheKey) (Tax, error) {
+	sim := &simulation{chainID: d.config.ChainID, token: key.token, pool: key.pool}
+	var tax Tax
+	var simErr error
+	seed := evmstate.Keys{}
+	seed.Add(key.token)
+	_, err := evmstate.Collect(ctx, d.ethrpcClient, nil, nil, seed, func(state *evmstate.State) evmstate.Keys {
+		var missing evmstate.Keys
+		tax, missing, simErr = sim.run(state)
+		return missing
+	})
+	if err != nil {
+		return Tax{}, err
+	}
+	return tax, simErr
+}
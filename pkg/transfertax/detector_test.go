@@ -0,0 +1,135 @@
+package transfertax_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/transfertax"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/evmstate"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/testutil"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+var (
+	testToken = common.HexToAddress("0x00000000000000000000000000000000000070c0")
+	testPair  = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	testPool  = common.HexToAddress("0x00000000000000000000000000000000000000a2")
+
+	// testTaxedTokenCode is an ERC20 keeping the balance of an account in the slot of its address, which taxes
+	// transfers to the pair in slot 1 by 5% and transfers from it by 3%.
+	testTaxedTokenCode = common.FromHex("0x60003560e01c806370a0823114610020578063a9059cbb1461002d57600080fd5b600435546000" +
+		"5260206000f35b335460243580821061008457808203335560006001546004351461005957600154331461006257610067565b5061" +
+		"01f4610067565b5061012c5b810261271090048103600435540160043555600160005260206000f35b600080fd")
+)
+
+// newTestState returns a chain where the pair holds 1e12 of the token and the pool 1.
+func newTestState() *evmstate.State {
+	return &evmstate.State{BlockNumber: 1, Accounts: map[common.Address]*evmstate.Account{
+		testToken: {Nonce: 1, Code: testTaxedTokenCode, Storage: map[common.Hash]common.Hash{
+			{31: 1}:                         common.BytesToHash(testPair[:]),
+			common.BytesToHash(testPair[:]): common.BigToHash(big.NewInt(1e12)),
+			common.BytesToHash(testPool[:]): {31: 1},
+		}},
+	}}
+}
+
+func TestDetector_Detect(t *testing.T) {
+	t.Parallel()
+	node, client := testutil.NewEVMStateNode(t, newTestState())
+	detector := transfertax.NewDetector(transfertax.Config{ChainID: valueobject.ChainIDEthereum}, client)
+
+	tax, err := detector.Detect(context.Background(), testToken.Hex(), testPair.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, transfertax.Tax{BuyBps: 300, SellBps: 500}, tax)
+
+	// the token does not tax transfers to and from other pools, even short of the amount transferred
+	tax, err = detector.Detect(context.Background(), testToken.Hex(), testPool.Hex())
+	require.NoError(t, err)
+	assert.True(t, tax.IsZero())
+
+	// cached
+	calls := node.Calls["eth_getProof"]
+	taxes, err := detector.DetectPool(context.Background(), entity.Pool{
+		Address: testPair.Hex(),
+		Tokens:  []*entity.PoolToken{{Address: testToken.Hex()}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, transfertax.Taxes{"0x00000000000000000000000000000000000070c0": {BuyBps: 300, SellBps: 500}},
+		taxes)
+	assert.Equal(t, calls, node.Calls["eth_getProof"])
+}
+
+func TestDetector_Detect_OutOfGas(t *testing.T) {
+	t.Parallel()
+	// an ERC20 keeping the balance of an account in the slot of its address, whose transfers loop until out of gas
+	loopingTokenCode := common.FromHex("0x60003560e01c806370a08231146014575b6010565b600435546000526020" +
+		"6000f3")
+	state := &evmstate.State{BlockNumber: 1, Accounts: map[common.Address]*evmstate.Account{
+		testToken: {Nonce: 1, Code: loopingTokenCode, Storage: map[common.Hash]common.Hash{
+			common.BytesToHash(testPair[:]): common.BigToHash(big.NewInt(1e12)),
+		}},
+	}}
+	_, client := testutil.NewEVMStateNode(t, state)
+	detector := transfertax.NewDetector(transfertax.Config{ChainID: valueobject.ChainIDEthereum}, client)
+
+	// running out of gas tells nothing of the token, unlike a revert
+	tax, err := detector.Detect(context.Background(), testToken.Hex(), testPair.Hex())
+	require.ErrorIs(t, err, vm.ErrOutOfGas)
+	assert.False(t, tax.BuyReverted || tax.SellReverted)
+}
+
+func TestDetector_Detect_MaxEntries(t *testing.T) {
+	t.Parallel()
+	node, client := testutil.NewEVMStateNode(t, newTestState())
+	detector := transfertax.NewDetector(transfertax.Config{ChainID: valueobject.ChainIDEthereum, MaxEntries: 1},
+		client)
+
+	_, err := detector.Detect(context.Background(), testToken.Hex(), testPair.Hex())
+	require.NoError(t, err)
+	calls := node.Calls["eth_getProof"]
+	_, err = detector.Detect(context.Background(), testToken.Hex(), testPair.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, calls, node.Calls["eth_getProof"])
+
+	// detecting with the pool evicts the pair
+	_, err = detector.Detect(context.Background(), testToken.Hex(), testPool.Hex())
+	require.NoError(t, err)
+	calls = node.Calls["eth_getProof"]
+	tax, err := detector.Detect(context.Background(), testToken.Hex(), testPair.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, transfertax.Tax{BuyBps: 300, SellBps: 500}, tax)
+	assert.Greater(t, node.Calls["eth_getProof"], calls)
+}
+
+func TestDetector_Track(t *testing.T) {
+	t.Parallel()
+	_, client := testutil.NewEVMStateNode(t, newTestState())
+	p := entity.Pool{
+		Address: testPair.Hex(),
+		Tokens:  []*entity.PoolToken{{Address: testToken.Hex()}},
+		Extra:   `{"fee":30}`,
+	}
+
+	// trackers without a config leave pools as they are
+	tracked, err := transfertax.NewConfiguredDetector(nil, valueobject.ChainIDEthereum, client).Track(
+		context.Background(), p)
+	require.NoError(t, err)
+	assert.Equal(t, p, tracked)
+
+	tracked, err = transfertax.NewConfiguredDetector(&transfertax.Config{}, valueobject.ChainIDEthereum, client).Track(
+		context.Background(), p)
+	require.NoError(t, err)
+	taxes, err := transfertax.PoolTaxes(tracked)
+	require.NoError(t, err)
+	assert.Equal(t, transfertax.Taxes{"0x00000000000000000000000000000000000070c0": {BuyBps: 300, SellBps: 500}},
+		taxes)
+	assert.JSONEq(t, `{"fee":30,"transferTaxes":{"0x00000000000000000000000000000000000070c0":{"buyBps":300,`+
+		`"sellBps":500}}}`, tracked.Extra)
+}
@@ -0,0 +1,157 @@
+package transfertax
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/vm"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/evmstate"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+var (
+	selectorBalanceOf = common.FromHex("0x70a08231")
+	selectorTransfer  = common.FromHex("0xa9059cbb")
+
+	// trader is the account transfers are simulated from and to, an address no token treats specially.
+	trader = common.HexToAddress("0x00000000000000000000000000000000007a7e11")
+
+	basisPoints = big.NewInt(10000)
+)
+
+// minTransfer is the smallest amount transferred, large enough for taxes to round to the basis point.
+const minTransfer = 1_000_000
+
+// transferDivisor sizes the amount transferred to the balance of the pool over it, a trade the pool could take.
+const transferDivisor = 1000
+
+// simulation simulates transfers of a token to and from a pool over a State.
+type simulation struct {
+	chainID valueobject.ChainID
+	token   common.Address
+	pool    common.Address
+
+	missing evmstate.Keys
+}
+
+// run returns the tax of the token, or the state missing to tell, or ErrUndetectable.
+func (s *simulation) run(state *evmstate.State) (Tax, evmstate.Keys, error) {
+	s.missing = evmstate.Keys{}
+	poolBalance, poolSlot, err := s.balanceOf(state, s.pool)
+	if poolBalance == nil {
+		return Tax{}, s.missing, err
+	}
+	_, traderSlot, err := s.balanceOf(state, trader)
+	if len(s.missing) > 0 || err != nil {
+		return Tax{}, s.missing, err
+	}
+	amount := new(big.Int).Div(poolBalance, big.NewInt(transferDivisor))
+	if amount.Cmp(big.NewInt(minTransfer)) < 0 {
+		amount.SetInt64(minTransfer)
+	}
+
+	var tax Tax
+	// sell: the trader, funded with amount, transfers it to the pool
+	sellBps, reverted, err := s.transfer(state, trader, s.pool, amount, &traderSlot)
+	tax.SellBps, tax.SellReverted = sellBps, reverted
+	if err != nil {
+		return Tax{}, s.missing, err
+	}
+	// buy: the pool, funded with amount if short of it, transfers it to the trader
+	var poolFunding *common.Hash
+	if poolBalance.Cmp(amount) < 0 {
+		poolFunding = &poolSlot
+	}
+	buyBps, reverted, err := s.transfer(state, s.pool, trader, amount, poolFunding)
+	tax.BuyBps, tax.BuyReverted = buyBps, reverted
+	if err != nil || len(s.missing) > 0 {
+		return Tax{}, s.missing, err
+	}
+	return tax, nil, nil
+}
+
+// balanceOf returns the balance of account and its storage slot, the last slot of the token balanceOf reads.
+func (s *simulation) balanceOf(state *evmstate.State, account common.Address) (*big.Int, common.Hash, error) {
+	res, err := s.call(state, account, selectorBalanceOf, common.BytesToHash(account[:]))
+	if res == nil {
+		return nil, common.Hash{}, err
+	}
+	for i := len(res.Reads) - 1; i >= 0; i-- {
+		if res.Reads[i].Address == s.token {
+			return new(big.Int).SetBytes(res.Ret), res.Reads[i].Slot, nil
+		}
+	}
+	return nil, common.Hash{}, ErrUndetectable
+}
+
+// transfer transfers amount from from to to, over a copy of state where from is funded with amount at fundingSlot
+// unless it is nil, and returns the share of amount to did not receive in basis points, or whether it reverted. Calls
+// failing otherwise, e.g. out of gas, tell nothing of the token and are returned as errors.
+func (s *simulation) transfer(state *evmstate.State, from, to common.Address, amount *big.Int,
+	fundingSlot *common.Hash) (uint64, bool, error) {
+	state = state.Clone()
+	if fundingSlot != nil {
+		token := *state.Accounts[s.token]
+		token.Storage = make(map[common.Hash]common.Hash, len(token.Storage)+1)
+		for slot, value := range state.Accounts[s.token].Storage {
+			token.Storage[slot] = value
+		}
+		token.Storage[*fundingSlot] = common.BigToHash(amount)
+		state.Accounts[s.token] = &token
+		if funded, _, err := s.balanceOf(state, from); funded == nil {
+			return 0, false, err
+		} else if funded.Cmp(amount) != 0 {
+			return 0, false, ErrUndetectable // the balance is not kept as is in one slot, like with reflection tokens
+		}
+	}
+
+	before, _, err := s.balanceOf(state, to)
+	if before == nil {
+		return 0, false, err
+	}
+	res, err := s.call(state, from, selectorTransfer, common.BytesToHash(to[:]),
+		common.BytesToHash(math.U256Bytes(new(big.Int).Set(amount))))
+	if errors.Is(err, vm.ErrExecutionReverted) {
+		return 0, true, nil
+	} else if err != nil {
+		return 0, false, err
+	} else if res == nil {
+		return 0, false, nil
+	}
+	state.Apply(res.Diff)
+	after, _, err := s.balanceOf(state, to)
+	if after == nil {
+		return 0, false, err
+	}
+
+	received := after.Sub(after, before)
+	if received.Cmp(amount) >= 0 {
+		return 0, false, nil
+	}
+	taxed := received.Sub(amount, received)
+	return taxed.Mul(taxed, basisPoints).Div(taxed, amount).Uint64(), false, nil
+}
+
+// call calls the token from from, returning nil without error when the state is missing something.
+func (s *simulation) call(state *evmstate.State, from common.Address, selector []byte,
+	args ...common.Hash) (*evmstate.Result, error) {
+	input := append([]byte(nil), selector...)
+	for _, arg := range args {
+		input = append(input, arg[:]...)
+	}
+	res, missing, err := state.Call(&evmstate.Msg{
+		ChainID:    s.chainID,
+		From:       from,
+		To:         s.token,
+		Input:      input,
+		TraceReads: true,
+	})
+	if len(missing) > 0 {
+		s.missing.Merge(missing)
+		return nil, nil
+	}
+	return res, err
+}
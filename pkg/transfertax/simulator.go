@@ -0,0 +1,98 @@
+package transfertax
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+// ErrTransferReverts is returned when quoting a swap whose token transfers revert.
+var ErrTransferReverts = errors.New("token transfer reverts")
+
+// CalcAmountOut quotes params with calcAmountOut, the quote of a pool knowing nothing of taxes, applying the taxes
+// around it: the pool gets the amount in net of the sell tax of the token in, and the recipient the amount out net of
+// the buy tax of the token out. Pool simulators call it from their own CalcAmountOut.
+func (t Taxes) CalcAmountOut(params pool.CalcAmountOutParams,
+	calcAmountOut func(pool.CalcAmountOutParams) (*pool.CalcAmountOutResult, error)) (*pool.CalcAmountOutResult, error) {
+	if len(t) == 0 {
+		return calcAmountOut(params)
+	}
+	tokenIn, tokenOut := params.TokenAmountIn.Token, params.TokenOut
+	if t.Get(tokenIn).SellReverted || t.Get(tokenOut).BuyReverted {
+		return nil, ErrTransferReverts
+	}
+
+	amountIn := params.TokenAmountIn.Amount
+	params.TokenAmountIn.Amount = t.ApplySell(tokenIn, amountIn)
+	res, err := calcAmountOut(params)
+	if err != nil || res.TokenAmountOut == nil {
+		return res, err
+	}
+	amountOut := *res.TokenAmountOut
+	amountOut.Amount = t.ApplyBuy(tokenOut, amountOut.Amount)
+	res.TokenAmountOut = &amountOut
+
+	// the pool swapped only part of what it got: only that part, grossed up, is to be sent
+	if remaining := res.RemainingTokenAmountIn; remaining != nil && remaining.Amount != nil &&
+		remaining.Amount.Sign() > 0 && amountIn != params.TokenAmountIn.Amount {
+		swapped := new(big.Int).Sub(params.TokenAmountIn.Amount, remaining.Amount)
+		left := new(big.Int).Sub(amountIn, t.GrossUpSell(tokenIn, swapped))
+		if left.Sign() < 0 {
+			left.SetInt64(0)
+		}
+		res.RemainingTokenAmountIn = &pool.TokenAmount{Token: remaining.Token, Amount: left,
+			AmountUsd: remaining.AmountUsd}
+	}
+	return res, nil
+}
+
+// CalcAmountIn quotes params with calcAmountIn, the quote of a pool knowing nothing of taxes, for the recipient to
+// receive params.TokenAmountOut net of taxes: the pool sends the amount out grossed up by the buy tax, and receives the
+// amount in net of the sell tax.
+func (t Taxes) CalcAmountIn(params pool.CalcAmountInParams,
+	calcAmountIn func(pool.CalcAmountInParams) (*pool.CalcAmountInResult, error)) (*pool.CalcAmountInResult, error) {
+	if len(t) == 0 {
+		return calcAmountIn(params)
+	}
+	tokenIn, tokenOut := params.TokenIn, params.TokenAmountOut.Token
+	if t.Get(tokenIn).SellReverted || t.Get(tokenOut).BuyReverted {
+		return nil, ErrTransferReverts
+	}
+
+	params.TokenAmountOut.Amount = t.GrossUpBuy(tokenOut, params.TokenAmountOut.Amount)
+	res, err := calcAmountIn(params)
+	if err != nil || res.TokenAmountIn == nil {
+		return res, err
+	}
+	amountIn := *res.TokenAmountIn
+	amountIn.Amount = t.GrossUpSell(tokenIn, amountIn.Amount)
+	res.TokenAmountIn = &amountIn
+	return res, nil
+}
+
+// PoolAmounts returns params with the amounts the pool actually received and sent for the amounts quoted by
+// CalcAmountOut or CalcAmountIn, for pool simulators to update their balances with.
+func (t Taxes) PoolAmounts(params pool.UpdateBalanceParams) pool.UpdateBalanceParams {
+	if len(t) == 0 {
+		return params
+	}
+	params.TokenAmountIn.Amount = t.ApplySell(params.TokenAmountIn.Token, params.TokenAmountIn.Amount)
+	params.TokenAmountOut.Amount = t.GrossUpBuy(params.TokenAmountOut.Token, params.TokenAmountOut.Amount)
+	return params
+}
+
+// NetShare returns the share of a marginal amount of tokenIn swapped for tokenOut left after the taxes, by which spot
+// prices are scaled.
+func (t Taxes) NetShare(tokenIn, tokenOut string) float64 {
+	if len(t) == 0 {
+		return 1
+	}
+	taxIn, taxOut := t.Get(tokenIn), t.Get(tokenOut)
+	if taxIn.SellReverted || taxOut.BuyReverted {
+		return 0
+	}
+	return (1 - float64(taxIn.SellBps)/basisPointsFloat) * (1 - float64(taxOut.BuyBps)/basisPointsFloat)
+}
+
+const basisPointsFloat = 10000
@@ -0,0 +1,93 @@
+package transfertax_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/transfertax"
+)
+
+// doubling swaps any amount in for twice as much out, swapping at most 2000.
+func doubling(params pool.CalcAmountOutParams) (*pool.CalcAmountOutResult, error) {
+	amountIn, remaining := params.TokenAmountIn.Amount, big.NewInt(0)
+	if amountIn.Cmp(big.NewInt(2000)) > 0 {
+		remaining.Sub(amountIn, big.NewInt(2000))
+		amountIn = big.NewInt(2000)
+	}
+	return &pool.CalcAmountOutResult{
+		TokenAmountOut:         &pool.TokenAmount{Token: params.TokenOut, Amount: new(big.Int).Lsh(amountIn, 1)},
+		RemainingTokenAmountIn: &pool.TokenAmount{Token: params.TokenAmountIn.Token, Amount: remaining},
+	}, nil
+}
+
+// halving takes twice the amount out in.
+func halving(params pool.CalcAmountInParams) (*pool.CalcAmountInResult, error) {
+	return &pool.CalcAmountInResult{TokenAmountIn: &pool.TokenAmount{
+		Token:  params.TokenIn,
+		Amount: new(big.Int).Lsh(params.TokenAmountOut.Amount, 1),
+	}}, nil
+}
+
+func TestTaxes_CalcAmountOut(t *testing.T) {
+	t.Parallel()
+	taxes := transfertax.Taxes{"a": {SellBps: 1000}, "b": {BuyBps: 500}}
+
+	res, err := taxes.CalcAmountOut(pool.CalcAmountOutParams{
+		TokenAmountIn: pool.TokenAmount{Token: "a", Amount: big.NewInt(1000)},
+		TokenOut:      "b",
+	}, doubling)
+	require.NoError(t, err)
+	// 1000 in, 900 after tax, 1800 out, 1710 after tax
+	assert.Equal(t, big.NewInt(1710), res.TokenAmountOut.Amount)
+	assert.Zero(t, res.RemainingTokenAmountIn.Amount.Sign())
+
+	res, err = taxes.CalcAmountOut(pool.CalcAmountOutParams{
+		TokenAmountIn: pool.TokenAmount{Token: "a", Amount: big.NewInt(3000)},
+		TokenOut:      "b",
+	}, doubling)
+	require.NoError(t, err)
+	// 3000 in, 2700 after tax of which 2000 swapped, sent as 2223 before tax
+	assert.Equal(t, big.NewInt(3800), res.TokenAmountOut.Amount)
+	assert.Equal(t, big.NewInt(3000-2223), res.RemainingTokenAmountIn.Amount)
+
+	_, err = transfertax.Taxes{"b": {BuyReverted: true}}.CalcAmountOut(pool.CalcAmountOutParams{
+		TokenAmountIn: pool.TokenAmount{Token: "a", Amount: big.NewInt(1000)},
+		TokenOut:      "b",
+	}, doubling)
+	assert.ErrorIs(t, err, transfertax.ErrTransferReverts)
+}
+
+func TestTaxes_CalcAmountIn(t *testing.T) {
+	t.Parallel()
+	taxes := transfertax.Taxes{"a": {SellBps: 1000}, "b": {BuyBps: 500}}
+
+	res, err := taxes.CalcAmountIn(pool.CalcAmountInParams{
+		TokenAmountOut: pool.TokenAmount{Token: "b", Amount: big.NewInt(1710)},
+		TokenIn:        "a",
+	}, halving)
+	require.NoError(t, err)
+	// 1710 out, 1800 before tax, 3600 in, 4000 before tax
+	assert.Equal(t, big.NewInt(4000), res.TokenAmountIn.Amount)
+}
+
+func TestTaxes_PoolAmounts(t *testing.T) {
+	t.Parallel()
+	params := transfertax.Taxes{"a": {SellBps: 1000}, "b": {BuyBps: 500}}.PoolAmounts(pool.UpdateBalanceParams{
+		TokenAmountIn:  pool.TokenAmount{Token: "a", Amount: big.NewInt(1000)},
+		TokenAmountOut: pool.TokenAmount{Token: "b", Amount: big.NewInt(1710)},
+	})
+	assert.Equal(t, big.NewInt(900), params.TokenAmountIn.Amount)
+	assert.Equal(t, big.NewInt(1800), params.TokenAmountOut.Amount)
+}
+
+func TestTaxes_NetShare(t *testing.T) {
+	t.Parallel()
+	taxes := transfertax.Taxes{"a": {SellBps: 1000}, "b": {BuyBps: 500, BuyReverted: true}}
+	assert.InDelta(t, 0.9, taxes.NetShare("a", "c"), 1e-12)
+	assert.Zero(t, taxes.NetShare("a", "b"))
+	assert.Equal(t, 1.0, transfertax.Taxes(nil).NetShare("a", "b"))
+}
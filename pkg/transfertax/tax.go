@@ -0,0 +1,120 @@
+// Package transfertax detects the transfer tax of tokens, for any pool, by simulating transfers to and from the pool
+// locally over a snapshot of the token state, and applies it in the quotes of pool simulators.
+package transfertax
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	tokentax "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v2/token-tax"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
+)
+
+// Tax is the transfer tax of a token as seen by a pool, in basis points of the amount transferred. Buying transfers
+// out of the pool, selling into it.
+type Tax struct {
+	BuyBps  uint64 `json:"buyBps,omitempty"`
+	SellBps uint64 `json:"sellBps,omitempty"`
+	// BuyReverted and SellReverted report transfers that revert, e.g. of honeypots.
+	BuyReverted  bool `json:"buyReverted,omitempty"`
+	SellReverted bool `json:"sellReverted,omitempty"`
+}
+
+// IsZero reports whether transfers are untaxed both ways.
+func (t Tax) IsZero() bool {
+	return t == Tax{}
+}
+
+// Taxes are the taxes of the tokens of a pool by lowercase address. Untaxed tokens may be left out.
+type Taxes map[string]Tax
+
+// Get returns the tax of token.
+func (t Taxes) Get(token string) Tax {
+	return t[strings.ToLower(token)]
+}
+
+// ApplySell returns what the pool receives of amountIn of tokenIn sent to it.
+func (t Taxes) ApplySell(tokenIn string, amountIn *big.Int) *big.Int {
+	return t.apply(tokenIn, amountIn, t.handler(tokenIn).ApplySellTax)
+}
+
+// ApplyBuy returns what the recipient receives of amountOut of tokenOut sent by the pool.
+func (t Taxes) ApplyBuy(tokenOut string, amountOut *big.Int) *big.Int {
+	return t.apply(tokenOut, amountOut, t.handler(tokenOut).ApplyBuyTax)
+}
+
+// GrossUpSell returns the amount of tokenIn to send for the pool to receive amountIn.
+func (t Taxes) GrossUpSell(tokenIn string, amountIn *big.Int) *big.Int {
+	return t.apply(tokenIn, amountIn, t.handler(tokenIn).GrossUpSellTax)
+}
+
+// GrossUpBuy returns the amount of tokenOut the pool sends for the recipient to receive amountOut.
+func (t Taxes) GrossUpBuy(tokenOut string, amountOut *big.Int) *big.Int {
+	return t.apply(tokenOut, amountOut, t.handler(tokenOut).GrossUpBuyTax)
+}
+
+func (t Taxes) apply(token string, amount *big.Int, apply func(string, *uint256.Int) *uint256.Int) *big.Int {
+	if amount == nil || t.Get(token).IsZero() {
+		return amount
+	}
+	return apply(strings.ToLower(token), big256.FromBig(amount)).ToBig()
+}
+
+func (t Taxes) handler(token string) tokentax.Handler {
+	tax := t.Get(token)
+	return tokentax.Handler{
+		TokenAddress: strings.ToLower(token),
+		BuyTaxBps:    uint256.NewInt(tax.BuyBps),
+		SellTaxBps:   uint256.NewInt(tax.SellBps),
+	}
+}
+
+// extraKey is the key of the taxes in the Extra of a pool.
+const extraKey = "transferTaxes"
+
+// PoolTaxes returns the taxes persisted in the Extra of p by SetPoolTaxes, nil if none.
+func PoolTaxes(p entity.Pool) (Taxes, error) {
+	if p.Extra == "" {
+		return nil, nil
+	}
+	var extra struct {
+		Taxes Taxes `json:"transferTaxes"`
+	}
+	if err := json.Unmarshal([]byte(p.Extra), &extra); err != nil {
+		return nil, err
+	}
+	return extra.Taxes, nil
+}
+
+// SetPoolTaxes persists taxes in the Extra of p, next to the fields of the pool type, which must be a JSON object.
+// Empty taxes are removed.
+func SetPoolTaxes(p *entity.Pool, taxes Taxes) error {
+	extra := make(map[string]json.RawMessage)
+	if p.Extra != "" {
+		if err := json.Unmarshal([]byte(p.Extra), &extra); err != nil {
+			return err
+		}
+	}
+	if len(taxes) == 0 {
+		if _, ok := extra[extraKey]; !ok {
+			return nil
+		}
+		delete(extra, extraKey)
+	} else {
+		taxesBytes, err := json.Marshal(taxes)
+		if err != nil {
+			return err
+		}
+		extra[extraKey] = taxesBytes
+	}
+	extraBytes, err := json.Marshal(extra)
+	if err != nil {
+		return err
+	}
+	p.Extra = string(extraBytes)
+	return nil
+}
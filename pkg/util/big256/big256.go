@@ -147,6 +147,17 @@ func MustFromBigs[S ~[]*big.Int](bigs S) []*uint256.Int {
 	})
 }
 
+// FromBigs converts bigs into dst, which must be at least as long, and reports whether any of them is negative or
+// does not fit in 256 bits.
+func FromBigs[S ~[]*big.Int](dst []uint256.Int, bigs S) (overflow bool) {
+	for i, b := range bigs {
+		if b.Sign() < 0 || dst[i].SetFromBig(b) {
+			overflow = true
+		}
+	}
+	return overflow
+}
+
 func MustFromInt64(x int64) *uint256.Int {
 	return uint256.MustFromBig(new(big.Int).SetInt64(x))
 }
@@ -1,6 +1,7 @@
 package big256
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/holiman/uint256"
@@ -38,3 +39,12 @@ func TestNewI(t *testing.T) {
 		})
 	}
 }
+
+func TestFromBigs(t *testing.T) {
+	dst := make([]uint256.Int, 2)
+	assert.False(t, FromBigs(dst, []*big.Int{big.NewInt(1), big.NewInt(2)}))
+	assert.Equal(t, []uint256.Int{*uint256.NewInt(1), *uint256.NewInt(2)}, dst)
+
+	assert.True(t, FromBigs(dst, []*big.Int{big.NewInt(-1), big.NewInt(2)}))
+	assert.True(t, FromBigs(dst, []*big.Int{new(big.Int).Lsh(big.NewInt(1), 256)}))
+}
@@ -0,0 +1,232 @@
+// Package clob is the order book core shared by the central limit order book sources: price levels, a walk-the-book
+// fill engine with taker fees and lot sizes for exact in and exact out swaps, consuming fills from a book, and a walk of
+// orders priced by their own rules, such as signed limit orders or the ticks of an on-chain book.
+package clob
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/holiman/uint256"
+	"github.com/samber/lo"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
+)
+
+var (
+	ErrEmptyBook             = errors.New("empty book")
+	ErrInsufficientLiquidity = errors.New("insufficient liquidity")
+)
+
+// Level is a price level of a book, or a single order: filled in full, it pays Out of the token out for In of the
+// token in. With Lots set, it is filled by whole lots, each paying Out/Lots for In/Lots, e.g. the shares of an on-chain
+// book or its size in tick size units. Otherwise, it is filled to the wei of the amount given.
+type Level struct {
+	In   *uint256.Int `json:"i"`
+	Out  *uint256.Int `json:"o"`
+	Lots *uint256.Int `json:"l,omitempty"`
+}
+
+// Fee is a taker fee of Rate/Precision.
+type Fee struct {
+	Rate      *uint256.Int
+	Precision *uint256.Int
+	// OnInput charges the fee on top of the amount in, otherwise it is taken from the amount out.
+	OnInput bool
+}
+
+// Book is a side of an order book: the levels selling the token out for the token in, best price first.
+type Book struct {
+	Levels []Level
+	Fee    Fee
+}
+
+// Fill is the result of walking a book.
+type Fill struct {
+	// AmountIn is the amount the taker pays, fee on input included.
+	AmountIn *uint256.Int
+	// AmountOut is the amount the taker receives, fee on output excluded.
+	AmountOut *uint256.Int
+	// Fee is in the token in if Fee.OnInput, in the token out otherwise.
+	Fee *uint256.Int
+	// RemainingIn is the amount in of an exact in fill left unfilled, by exhausting the book or less than a lot.
+	RemainingIn *uint256.Int
+	// Walked is the number of levels walked, for gas estimation.
+	Walked int
+	// Filled is the number of levels filled in full, Levels[:Filled].
+	Filled int
+	// Partial is the part filled of Levels[Filled], nil if none.
+	Partial *Level
+}
+
+// FillExactIn walks the book with amountIn, filling as much of it as the book allows.
+func (b *Book) FillExactIn(amountIn *uint256.Int) (*Fill, error) {
+	if len(b.Levels) == 0 {
+		return nil, ErrEmptyBook
+	}
+
+	remaining := amountIn.Clone()
+	if b.Fee.OnInput && b.Fee.charged() {
+		var denominator uint256.Int
+		big256.MulDivDown(remaining, amountIn, b.Fee.Precision, denominator.Add(b.Fee.Precision, b.Fee.Rate))
+	}
+
+	fill := &Fill{AmountIn: new(uint256.Int), AmountOut: new(uint256.Int)}
+	for i, level := range b.Levels {
+		if remaining.IsZero() {
+			break
+		} else if level.empty() {
+			fill.Filled = i + 1
+			continue
+		}
+		fill.Walked++
+		if !remaining.Lt(level.In) {
+			fill.add(&level)
+			fill.Filled = i + 1
+			remaining.Sub(remaining, level.In)
+			continue
+		}
+		if partial := level.fillIn(remaining); !partial.Out.IsZero() {
+			fill.add(partial)
+			fill.Partial = partial
+		}
+		break
+	}
+
+	fill.Fee = b.Fee.amount(lo.Ternary(b.Fee.OnInput, fill.AmountIn, fill.AmountOut))
+	if b.Fee.OnInput {
+		fill.AmountIn.Add(fill.AmountIn, fill.Fee)
+	} else {
+		fill.AmountOut.Sub(fill.AmountOut, fill.Fee)
+	}
+	fill.RemainingIn = new(uint256.Int).Sub(amountIn, fill.AmountIn)
+	return fill, nil
+}
+
+// FillExactOut walks the book for amountOut, failing with ErrInsufficientLiquidity if the book cannot pay it.
+func (b *Book) FillExactOut(amountOut *uint256.Int) (*Fill, error) {
+	if len(b.Levels) == 0 {
+		return nil, ErrEmptyBook
+	}
+
+	remaining := amountOut.Clone()
+	if !b.Fee.OnInput && b.Fee.charged() {
+		remaining = b.Fee.grossOut(amountOut)
+	}
+
+	fill := &Fill{AmountIn: new(uint256.Int), AmountOut: new(uint256.Int), RemainingIn: new(uint256.Int)}
+	for i, level := range b.Levels {
+		if remaining.IsZero() {
+			break
+		} else if level.empty() {
+			fill.Filled = i + 1
+			continue
+		}
+		fill.Walked++
+		if !remaining.Lt(level.Out) {
+			fill.add(&level)
+			fill.Filled = i + 1
+			remaining.Sub(remaining, level.Out)
+			continue
+		}
+		partial := level.fillOut(remaining)
+		fill.add(partial)
+		fill.Partial = partial
+		remaining.Clear()
+	}
+	if !remaining.IsZero() {
+		return nil, ErrInsufficientLiquidity
+	}
+
+	fill.Fee = b.Fee.amount(lo.Ternary(b.Fee.OnInput, fill.AmountIn, fill.AmountOut))
+	if b.Fee.OnInput {
+		fill.AmountIn.Add(fill.AmountIn, fill.Fee)
+	} else {
+		fill.AmountOut.Sub(fill.AmountOut, fill.Fee)
+	}
+	return fill, nil
+}
+
+// Consume removes the liquidity taken by fill, a fill of the book, from the book. Levels are copied before being
+// changed, so books sharing them, e.g. clones of a pool simulator, are left untouched.
+func (b *Book) Consume(fill *Fill) {
+	levels := b.Levels[min(fill.Filled, len(b.Levels)):]
+	if fill.Partial != nil && len(levels) > 0 {
+		levels = slices.Clone(levels)
+		levels[0] = levels[0].sub(fill.Partial)
+	}
+	b.Levels = levels
+}
+
+func (l *Level) empty() bool {
+	return l.In == nil || l.Out == nil || l.In.IsZero() || l.Out.IsZero() || l.Lots != nil && l.Lots.IsZero()
+}
+
+// fillIn fills the level with amountIn, less than l.In.
+func (l *Level) fillIn(amountIn *uint256.Int) *Level {
+	if l.Lots == nil {
+		var out uint256.Int
+		return &Level{In: amountIn.Clone(), Out: big256.MulDivDown(&out, amountIn, l.Out, l.In)}
+	}
+	var lots uint256.Int
+	return l.lots(big256.MulDivDown(&lots, amountIn, l.Lots, l.In))
+}
+
+// fillOut fills the level for amountOut, less than l.Out.
+func (l *Level) fillOut(amountOut *uint256.Int) *Level {
+	if l.Lots == nil {
+		var in uint256.Int
+		return &Level{In: big256.MulDivUp(&in, amountOut, l.In, l.Out), Out: amountOut.Clone()}
+	}
+	var lots uint256.Int
+	return l.lots(big256.MulDivUp(&lots, amountOut, l.Lots, l.Out))
+}
+
+// lots returns the part of the level of the given number of lots, rounding in favor of the level.
+func (l *Level) lots(lots *uint256.Int) *Level {
+	var in, out uint256.Int
+	return &Level{
+		In:   big256.MulDivUp(&in, lots, l.In, l.Lots),
+		Out:  big256.MulDivDown(&out, lots, l.Out, l.Lots),
+		Lots: lots,
+	}
+}
+
+func (l *Level) sub(part *Level) Level {
+	left := Level{
+		In:  new(uint256.Int).Sub(l.In, part.In),
+		Out: new(uint256.Int).Sub(l.Out, part.Out),
+	}
+	if l.Lots != nil && part.Lots != nil {
+		left.Lots = new(uint256.Int).Sub(l.Lots, part.Lots)
+	}
+	return left
+}
+
+func (f *Fill) add(level *Level) {
+	f.AmountIn.Add(f.AmountIn, level.In)
+	f.AmountOut.Add(f.AmountOut, level.Out)
+}
+
+func (f *Fee) charged() bool {
+	return f.Rate != nil && !f.Rate.IsZero() && f.Precision != nil
+}
+
+// amount returns the fee on amount, rounded up.
+func (f *Fee) amount(amount *uint256.Int) *uint256.Int {
+	if !f.charged() {
+		return new(uint256.Int)
+	}
+	var fee uint256.Int
+	return big256.MulDivUp(&fee, amount, f.Rate, f.Precision)
+}
+
+// grossOut returns the least amount out, before the fee on output, leaving amountOut after the fee.
+func (f *Fee) grossOut(amountOut *uint256.Int) *uint256.Int {
+	var gross, denominator uint256.Int
+	big256.MulDivUp(&gross, amountOut, f.Precision, denominator.Sub(f.Precision, f.Rate))
+	for new(uint256.Int).Sub(&gross, f.amount(&gross)).Lt(amountOut) {
+		gross.AddUint64(&gross, 1)
+	}
+	return &gross
+}
@@ -0,0 +1,124 @@
+package clob
+
+import (
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/big256"
+)
+
+func level(in, out uint64, lots ...uint64) Level {
+	l := Level{In: uint256.NewInt(in), Out: uint256.NewInt(out)}
+	if len(lots) > 0 {
+		l.Lots = uint256.NewInt(lots[0])
+	}
+	return l
+}
+
+func TestBook_FillExactIn(t *testing.T) {
+	t.Parallel()
+	book := Book{Levels: []Level{level(0, 0), level(100, 200), level(100, 150)}}
+
+	fill, err := book.FillExactIn(uint256.NewInt(150))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(150), fill.AmountIn.Uint64())
+	assert.Equal(t, uint64(275), fill.AmountOut.Uint64())
+	assert.Equal(t, uint64(0), fill.RemainingIn.Uint64())
+	assert.Equal(t, 2, fill.Walked)
+	assert.Equal(t, 2, fill.Filled)
+	assert.Equal(t, level(50, 75), *fill.Partial)
+
+	fill, err = book.FillExactIn(uint256.NewInt(250))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(350), fill.AmountOut.Uint64())
+	assert.Equal(t, uint64(50), fill.RemainingIn.Uint64(), "the book is exhausted")
+	assert.Nil(t, fill.Partial)
+
+	_, err = (&Book{}).FillExactIn(uint256.NewInt(1))
+	assert.ErrorIs(t, err, ErrEmptyBook)
+}
+
+func TestBook_FillExactIn_Lots(t *testing.T) {
+	t.Parallel()
+	// 10 lots of 30 in for 7 out
+	book := Book{Levels: []Level{level(300, 70, 10)}}
+
+	fill, err := book.FillExactIn(uint256.NewInt(100))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(90), fill.AmountIn.Uint64(), "3 whole lots")
+	assert.Equal(t, uint64(21), fill.AmountOut.Uint64())
+	assert.Equal(t, uint64(10), fill.RemainingIn.Uint64())
+	assert.Equal(t, uint64(3), fill.Partial.Lots.Uint64())
+
+	fill, err = book.FillExactIn(uint256.NewInt(29))
+	require.NoError(t, err)
+	assert.True(t, fill.AmountOut.IsZero(), "less than a lot")
+	assert.Equal(t, 1, fill.Walked)
+	assert.Nil(t, fill.Partial)
+}
+
+func TestBook_FillExactIn_Fee(t *testing.T) {
+	t.Parallel()
+	fee := Fee{Rate: uint256.NewInt(100), Precision: big256.UBasisPoint}
+	levels := []Level{level(1000, 2000)}
+
+	onOutput := Book{Levels: levels, Fee: fee}
+	fill, err := onOutput.FillExactIn(uint256.NewInt(500))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(500), fill.AmountIn.Uint64())
+	assert.Equal(t, uint64(10), fill.Fee.Uint64())
+	assert.Equal(t, uint64(990), fill.AmountOut.Uint64())
+
+	fee.OnInput = true
+	onInput := Book{Levels: levels, Fee: fee}
+	fill, err = onInput.FillExactIn(uint256.NewInt(505))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(505), fill.AmountIn.Uint64())
+	assert.Equal(t, uint64(5), fill.Fee.Uint64())
+	assert.Equal(t, uint64(1000), fill.AmountOut.Uint64())
+	assert.True(t, fill.RemainingIn.IsZero())
+}
+
+func TestBook_FillExactOut(t *testing.T) {
+	t.Parallel()
+	book := Book{
+		Levels: []Level{level(100, 200), level(300, 70, 10)},
+		Fee:    Fee{Rate: uint256.NewInt(100), Precision: big256.UBasisPoint},
+	}
+
+	fill, err := book.FillExactOut(uint256.NewInt(210))
+	require.NoError(t, err)
+	// 213 gross out: all of the first level then 2 lots of 7 of the second
+	assert.Equal(t, uint64(160), fill.AmountIn.Uint64())
+	assert.Equal(t, uint64(3), fill.Fee.Uint64())
+	assert.Equal(t, uint64(211), fill.AmountOut.Uint64())
+	assert.Equal(t, 1, fill.Filled)
+	assert.Equal(t, uint64(2), fill.Partial.Lots.Uint64())
+
+	exactIn, err := book.FillExactIn(fill.AmountIn)
+	require.NoError(t, err)
+	assert.Equal(t, fill.AmountOut, exactIn.AmountOut, "exact out fills round trip")
+
+	_, err = book.FillExactOut(uint256.NewInt(270))
+	assert.ErrorIs(t, err, ErrInsufficientLiquidity)
+}
+
+func TestBook_Consume(t *testing.T) {
+	t.Parallel()
+	book := Book{Levels: []Level{level(100, 200), level(300, 70, 10), level(10, 10)}}
+	clone := book
+
+	fill, err := book.FillExactIn(uint256.NewInt(190))
+	require.NoError(t, err)
+	book.Consume(fill)
+	assert.Equal(t, []Level{level(210, 49, 7), level(10, 10)}, book.Levels)
+	assert.Equal(t, level(300, 70, 10), clone.Levels[1], "clones are left untouched")
+
+	fill, err = book.FillExactIn(uint256.NewInt(220))
+	require.NoError(t, err)
+	book.Consume(fill)
+	assert.Empty(t, book.Levels)
+}
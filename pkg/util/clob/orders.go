@@ -0,0 +1,118 @@
+package clob
+
+import (
+	"errors"
+
+	"github.com/holiman/uint256"
+)
+
+// ErrSkipOrder is returned by Orders.FillIn to move on to the next order, e.g. an expired order or one too small to
+// fill.
+var ErrSkipOrder = errors.New("skip order")
+
+// Orders is a book side of orders priced by their own rules rather than pro rata to a level: signed limit orders with
+// their own fees, amount getters and maker balances, or the ticks of an on-chain book rounding the way its contract
+// does. Orders are walked best first, each once per walk, so implementations may keep state across a walk, such as
+// the balance left to each maker.
+type Orders interface {
+	Len() int
+	// FillIn fills order i with at most amountIn, returning the part filled, with its amount in and out after fees.
+	// last stops the walk after this part, e.g. when the order is filled in part. An empty part with last stops the
+	// walk without being filled.
+	FillIn(i int, amountIn *uint256.Int) (part Level, last bool, err error)
+}
+
+// ExactOutOrders are Orders that can also be filled for an amount out.
+type ExactOutOrders interface {
+	Orders
+	// FillOut fills order i for at most amountOut, the way FillIn does.
+	FillOut(i int, amountOut *uint256.Int) (part Level, last bool, err error)
+}
+
+// OrderPart is the part of an order filled by a walk.
+type OrderPart struct {
+	Level
+	// Index is the index of the order in Orders.
+	Index int
+	// Last is whether the walk stopped at this part.
+	Last bool
+}
+
+// OrderFill is the result of walking Orders.
+type OrderFill struct {
+	AmountIn  *uint256.Int
+	AmountOut *uint256.Int
+	// RemainingIn is the amount in left unfilled by an exact in walk.
+	RemainingIn *uint256.Int
+	// RemainingOut is the amount out left unfilled by an exact out walk.
+	RemainingOut *uint256.Int
+	// Walked is the number of orders walked, skipped ones included.
+	Walked int
+	// Parts are the parts filled, in walking order.
+	Parts []OrderPart
+}
+
+// FillOrdersExactIn walks orders with amountIn until it is spent, an order stops the walk or orders run out. An order
+// rounding in its favor may take a few wei more than is left of amountIn, which ends the walk.
+func FillOrdersExactIn(orders Orders, amountIn *uint256.Int) (*OrderFill, error) {
+	fill, remaining, err := walkOrders(orders, amountIn, orders.FillIn, func(part *Level) *uint256.Int { return part.In })
+	if err != nil {
+		return nil, err
+	}
+	fill.RemainingIn = remaining
+	return fill, nil
+}
+
+// FillOrdersExactOut walks orders for amountOut the way FillOrdersExactIn walks them with an amount in.
+func FillOrdersExactOut(orders ExactOutOrders, amountOut *uint256.Int) (*OrderFill, error) {
+	fill, remaining, err := walkOrders(orders, amountOut, orders.FillOut, func(part *Level) *uint256.Int { return part.Out })
+	if err != nil {
+		return nil, err
+	}
+	fill.RemainingOut = remaining
+	return fill, nil
+}
+
+func walkOrders(orders Orders, amount *uint256.Int, fillFn func(int, *uint256.Int) (Level, bool, error),
+	filled func(*Level) *uint256.Int) (*OrderFill, *uint256.Int, error) {
+	if orders.Len() == 0 {
+		return nil, nil, ErrEmptyBook
+	}
+
+	remaining := amount.Clone()
+	fill := &OrderFill{AmountIn: new(uint256.Int), AmountOut: new(uint256.Int)}
+	for i := 0; i < orders.Len() && !remaining.IsZero(); i++ {
+		fill.Walked++
+		part, last, err := fillFn(i, remaining)
+		if errors.Is(err, ErrSkipOrder) {
+			continue
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		if !part.empty() {
+			fill.AmountIn.Add(fill.AmountIn, part.In)
+			fill.AmountOut.Add(fill.AmountOut, part.Out)
+			fill.Parts = append(fill.Parts, OrderPart{Level: part, Index: i, Last: last})
+			if amount := filled(&part); !amount.Gt(remaining) {
+				remaining.Sub(remaining, amount)
+			} else {
+				remaining.Clear()
+				break
+			}
+		}
+		if last {
+			break
+		}
+	}
+
+	return fill, remaining, nil
+}
+
+// Last returns the part the walk stopped at, nil if it did not stop at a filled part.
+func (f *OrderFill) Last() *OrderPart {
+	if len(f.Parts) == 0 || !f.Parts[len(f.Parts)-1].Last {
+		return nil
+	}
+	return &f.Parts[len(f.Parts)-1]
+}
@@ -0,0 +1,98 @@
+package clob
+
+import (
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testOrders fills each order pro rata, skipping expired ones and stopping at a partial fill.
+type testOrders struct {
+	levels  []Level
+	expired map[int]bool
+}
+
+func (o *testOrders) Len() int { return len(o.levels) }
+
+func (o *testOrders) FillIn(i int, amountIn *uint256.Int) (Level, bool, error) {
+	if o.expired[i] {
+		return Level{}, false, ErrSkipOrder
+	}
+	if level := o.levels[i]; amountIn.Lt(level.In) {
+		return *level.fillIn(amountIn), true, nil
+	}
+	return o.levels[i], false, nil
+}
+
+func TestFillOrdersExactIn(t *testing.T) {
+	t.Parallel()
+	orders := &testOrders{
+		levels:  []Level{level(100, 200), level(100, 190), level(100, 150), level(100, 100)},
+		expired: map[int]bool{1: true},
+	}
+
+	fill, err := FillOrdersExactIn(orders, uint256.NewInt(150))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(150), fill.AmountIn.Uint64())
+	assert.Equal(t, uint64(275), fill.AmountOut.Uint64())
+	assert.True(t, fill.RemainingIn.IsZero())
+	assert.Equal(t, 3, fill.Walked, "skipped orders are walked")
+	assert.Equal(t, []OrderPart{{Level: level(100, 200)}, {Level: level(50, 75), Index: 2, Last: true}}, fill.Parts)
+	assert.Equal(t, &fill.Parts[1], fill.Last())
+
+	fill, err = FillOrdersExactIn(orders, uint256.NewInt(500))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(450), fill.AmountOut.Uint64())
+	assert.Equal(t, uint64(200), fill.RemainingIn.Uint64(), "the orders run out")
+	assert.Nil(t, fill.Last())
+
+	_, err = FillOrdersExactIn(&testOrders{}, uint256.NewInt(1))
+	assert.ErrorIs(t, err, ErrEmptyBook)
+}
+
+// roundingOrders take one more wei than they are given, the way an on-chain book rounds in its favor.
+type roundingOrders struct{ testOrders }
+
+func (o *roundingOrders) FillIn(i int, amountIn *uint256.Int) (Level, bool, error) {
+	return Level{In: new(uint256.Int).AddUint64(amountIn, 1), Out: uint256.NewInt(1)}, false, nil
+}
+
+func TestFillOrdersExactIn_RoundingUp(t *testing.T) {
+	t.Parallel()
+	fill, err := FillOrdersExactIn(&roundingOrders{testOrders{levels: make([]Level, 3)}}, uint256.NewInt(10))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(11), fill.AmountIn.Uint64())
+	assert.True(t, fill.RemainingIn.IsZero())
+	assert.Equal(t, 1, fill.Walked)
+}
+
+func (o *testOrders) FillOut(i int, amountOut *uint256.Int) (Level, bool, error) {
+	if o.expired[i] {
+		return Level{}, false, ErrSkipOrder
+	}
+	if level := o.levels[i]; amountOut.Lt(level.Out) {
+		return *level.fillOut(amountOut), true, nil
+	}
+	return o.levels[i], false, nil
+}
+
+func TestFillOrdersExactOut(t *testing.T) {
+	t.Parallel()
+	orders := &testOrders{
+		levels:  []Level{level(100, 200), level(100, 190), level(100, 150)},
+		expired: map[int]bool{1: true},
+	}
+
+	fill, err := FillOrdersExactOut(orders, uint256.NewInt(275))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(150), fill.AmountIn.Uint64())
+	assert.True(t, fill.RemainingOut.IsZero())
+	assert.Equal(t, 3, fill.Walked)
+	assert.Equal(t, &OrderPart{Level: level(50, 75), Index: 2, Last: true}, fill.Last())
+
+	fill, err = FillOrdersExactOut(orders, uint256.NewInt(400))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(50), fill.RemainingOut.Uint64(), "the orders run out")
+}
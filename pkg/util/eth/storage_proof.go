@@ -0,0 +1,268 @@
+package eth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/holiman/uint256"
+)
+
+// errCodeMethodNotFound is the JSON-RPC error code of an unsupported method.
+const errCodeMethodNotFound = -32601
+
+var ErrInvalidProof = errors.New("storage proof does not match the state root")
+
+// StorageQuery is the storage slots to read of an account.
+type StorageQuery struct {
+	Address common.Address
+	Slots   []common.Hash
+}
+
+// StorageSnapshot is storage read as of one block.
+type StorageSnapshot struct {
+	BlockNumber uint64
+	BlockTime   uint64
+	StateRoot   common.Hash
+	// Verified reports whether the values were proven against StateRoot with eth_getProof, rather than read with
+	// eth_getStorageAt from a node without it.
+	Verified bool
+
+	values map[common.Address]map[common.Hash]common.Hash
+}
+
+// Get returns the value of slot of addr, zero if it was not read.
+func (s *StorageSnapshot) Get(addr common.Address, slot common.Hash) common.Hash {
+	return s.values[addr][slot]
+}
+
+// AccountProof is the result of eth_getProof, see EIP-1186.
+type AccountProof struct {
+	AccountProof []hexutil.Bytes `json:"accountProof"`
+	Balance      *hexutil.Big    `json:"balance"`
+	CodeHash     common.Hash     `json:"codeHash"`
+	Nonce        hexutil.Uint64  `json:"nonce"`
+	StorageHash  common.Hash     `json:"storageHash"`
+	StorageProof []StorageProof  `json:"storageProof"`
+}
+
+type StorageProof struct {
+	Value *hexutil.Big    `json:"value"`
+	Proof []hexutil.Bytes `json:"proof"`
+}
+
+// ReadStorage reads the queried slots as of blockNumber, the latest block if nil, in one batch pinned to that block.
+// The values are proven against the state root of the block with eth_getProof, unless the node lacks eth_getProof, in
+// which case they are read unverified with eth_getStorageAt. The header of a known block is fetched in the batch of the
+// proofs, while the latest block is pinned with a header fetched beforehand.
+func ReadStorage(ctx context.Context, client *rpc.Client, blockNumber *big.Int, queries []StorageQuery,
+	retry BatchRetry) (*StorageSnapshot, error) {
+	var header *types.Header
+	if blockNumber == nil {
+		var err error
+		if header, err = FetchHeader(ctx, client, nil); err != nil {
+			return nil, err
+		}
+		blockNumber = header.Number
+	}
+	return readStorage(ctx, client, blockNumber, header, queries, retry)
+}
+
+// ReadStorageAt is ReadStorage as of the block of a header the caller already holds, which is not fetched again.
+func ReadStorageAt(ctx context.Context, client *rpc.Client, header *types.Header, queries []StorageQuery,
+	retry BatchRetry) (*StorageSnapshot, error) {
+	return readStorage(ctx, client, header.Number, header, queries, retry)
+}
+
+// FetchHeader returns the header of blockNumber, the latest block if nil.
+func FetchHeader(ctx context.Context, client *rpc.Client, blockNumber *big.Int) (*types.Header, error) {
+	blockArg := "latest"
+	if blockNumber != nil {
+		blockArg = hexutil.EncodeBig(blockNumber)
+	}
+	var header *types.Header
+	if err := client.CallContext(ctx, &header, "eth_getBlockByNumber", blockArg, false); err != nil {
+		return nil, fmt.Errorf("failed to fetch block header: %w", err)
+	} else if header == nil {
+		return nil, fmt.Errorf("block %s not found", blockArg)
+	}
+	return header, nil
+}
+
+func readStorage(ctx context.Context, client *rpc.Client, blockNumber *big.Int, header *types.Header,
+	queries []StorageQuery, retry BatchRetry) (*StorageSnapshot, error) {
+	snapshot := &StorageSnapshot{
+		values: make(map[common.Address]map[common.Hash]common.Hash, len(queries)),
+	}
+	blockArg := hexutil.EncodeBig(blockNumber)
+	err := snapshot.prove(ctx, client, blockArg, header, queries, retry)
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == errCodeMethodNotFound {
+		err = snapshot.read(ctx, client, blockArg, queries, retry)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// prove reads the queries with eth_getProof, verifying the proofs against the state root of header, which is fetched
+// in the same batch if nil.
+func (s *StorageSnapshot) prove(ctx context.Context, client *rpc.Client, blockArg string, header *types.Header,
+	queries []StorageQuery, retry BatchRetry) error {
+	proofs := make([]AccountProof, len(queries))
+	batch := make([]rpc.BatchElem, len(queries), len(queries)+1)
+	for i, query := range queries {
+		slots := query.Slots
+		if slots == nil {
+			slots = []common.Hash{}
+		}
+		batch[i] = rpc.BatchElem{
+			Method: "eth_getProof",
+			Args:   []any{query.Address, slots, blockArg},
+			Result: &proofs[i],
+		}
+	}
+	if header == nil {
+		batch = append(batch, rpc.BatchElem{
+			Method: "eth_getBlockByNumber",
+			Args:   []any{blockArg, false},
+			Result: &header,
+		})
+	}
+	if err := BatchCallWithRetry(ctx, client, batch, retry); err != nil {
+		return err
+	}
+	if len(batch) > len(queries) {
+		if err := batch[len(queries)].Error; err != nil {
+			return fmt.Errorf("failed to fetch block header: %w", err)
+		} else if header == nil {
+			return fmt.Errorf("block %s not found", blockArg)
+		}
+	}
+	s.BlockNumber, s.BlockTime, s.StateRoot = header.Number.Uint64(), header.Time, header.Root
+	for _, elem := range batch[:len(queries)] {
+		if elem.Error != nil {
+			return elem.Error
+		}
+	}
+
+	for i, query := range queries {
+		if err := proofs[i].Verify(s.StateRoot, query.Address, query.Slots); err != nil {
+			return fmt.Errorf("account %s: %w", query.Address, err)
+		}
+		values := s.account(query.Address)
+		for j, slot := range query.Slots {
+			values[slot] = common.BigToHash(proofs[i].StorageProof[j].Value.ToInt())
+		}
+	}
+	s.Verified = true
+	return nil
+}
+
+// read reads the queries with eth_getStorageAt.
+func (s *StorageSnapshot) read(ctx context.Context, client *rpc.Client, blockArg string, queries []StorageQuery,
+	retry BatchRetry) error {
+	type read struct {
+		addr   common.Address
+		slot   common.Hash
+		result common.Hash
+	}
+	var reads []*read
+	var batch []rpc.BatchElem
+	for _, query := range queries {
+		for _, slot := range query.Slots {
+			r := &read{addr: query.Address, slot: slot}
+			reads = append(reads, r)
+			batch = append(batch, rpc.BatchElem{
+				Method: "eth_getStorageAt",
+				Args:   []any{query.Address, slot, blockArg},
+				Result: &r.result,
+			})
+		}
+	}
+	if err := BatchCallWithRetry(ctx, client, batch, retry); err != nil {
+		return err
+	}
+	for _, elem := range batch {
+		if elem.Error != nil {
+			return elem.Error
+		}
+	}
+
+	for _, r := range reads {
+		s.account(r.addr)[r.slot] = r.result
+	}
+	s.Verified = false
+	return nil
+}
+
+func (s *StorageSnapshot) account(addr common.Address) map[common.Hash]common.Hash {
+	values, ok := s.values[addr]
+	if !ok {
+		values = make(map[common.Hash]common.Hash)
+		s.values[addr] = values
+	}
+	return values
+}
+
+// Verify verifies the proof of the account addr and of its slots against stateRoot.
+func (p *AccountProof) Verify(stateRoot common.Hash, addr common.Address, slots []common.Hash) error {
+	if len(p.StorageProof) != len(slots) {
+		return fmt.Errorf("%w: %d storage proofs for %d slots", ErrInvalidProof, len(p.StorageProof), len(slots))
+	}
+
+	encoded, err := trie.VerifyProof(stateRoot, crypto.Keccak256(addr[:]), proofDB(p.AccountProof))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+	account := types.NewEmptyStateAccount()
+	if len(encoded) > 0 {
+		if err = rlp.DecodeBytes(encoded, account); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidProof, err)
+		}
+	}
+	balance, _ := uint256.FromBig(p.Balance.ToInt())
+	if account.Nonce != uint64(p.Nonce) || !account.Balance.Eq(balance) || account.Root != p.StorageHash ||
+		!bytes.Equal(account.CodeHash, p.CodeHash[:]) && len(encoded) > 0 {
+		return fmt.Errorf("%w: account fields differ", ErrInvalidProof)
+	}
+
+	for i, slot := range slots {
+		claimed := p.StorageProof[i].Value.ToInt()
+		var value []byte
+		if account.Root != types.EmptyRootHash {
+			encoded, err = trie.VerifyProof(account.Root, crypto.Keccak256(slot[:]), proofDB(p.StorageProof[i].Proof))
+			if err != nil {
+				return fmt.Errorf("%w: slot %s: %v", ErrInvalidProof, slot, err)
+			} else if len(encoded) > 0 {
+				if _, value, _, err = rlp.Split(encoded); err != nil {
+					return fmt.Errorf("%w: slot %s: %v", ErrInvalidProof, slot, err)
+				}
+			}
+		}
+		if new(big.Int).SetBytes(value).Cmp(claimed) != 0 {
+			return fmt.Errorf("%w: slot %s value differs", ErrInvalidProof, slot)
+		}
+	}
+	return nil
+}
+
+// proofDB returns the nodes of a Merkle proof keyed by hash, for trie.VerifyProof.
+func proofDB(nodes []hexutil.Bytes) *memorydb.Database {
+	db := memorydb.New()
+	for _, node := range nodes {
+		_ = db.Put(crypto.Keccak256(node), node)
+	}
+	return db
+}
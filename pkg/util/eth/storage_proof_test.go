@@ -0,0 +1,133 @@
+package eth_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/eth"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/evmstate"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/testutil"
+)
+
+var (
+	testPair  = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	testOther = common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	testEmpty = common.HexToAddress("0x00000000000000000000000000000000000000a3")
+)
+
+func newTestStorage() *evmstate.State {
+	return &evmstate.State{
+		BlockNumber: 100,
+		Time:        1700000000,
+		Accounts: map[common.Address]*evmstate.Account{
+			testPair: {Nonce: 1, Code: []byte{0x00}, Storage: map[common.Hash]common.Hash{
+				{31: 8}:  common.HexToHash("0x12345678000000000000000000010000000000000000000000000000000a"),
+				{31: 12}: {31: 1},
+			}},
+			testOther: {Balance: uint256.NewInt(5)},
+		},
+	}
+}
+
+func testQueries() []eth.StorageQuery {
+	return []eth.StorageQuery{
+		{Address: testPair, Slots: []common.Hash{{31: 8}, {31: 9}}},
+		{Address: testOther, Slots: []common.Hash{{31: 8}}},
+		{Address: testEmpty, Slots: []common.Hash{{31: 1}}},
+	}
+}
+
+func TestReadStorage(t *testing.T) {
+	t.Parallel()
+	node, client := testutil.NewEVMStateNode(t, newTestStorage())
+	snapshot, err := eth.ReadStorage(context.Background(), client.GetETHClient().Client(), big.NewInt(100),
+		testQueries(), eth.DefaultBatchRetry)
+	require.NoError(t, err)
+	assert.True(t, snapshot.Verified)
+	assert.EqualValues(t, 100, snapshot.BlockNumber)
+	assert.EqualValues(t, 1700000000, snapshot.BlockTime)
+	assert.NotEqual(t, common.Hash{}, snapshot.StateRoot)
+	assert.Equal(t, node.State.Accounts[testPair].Storage[common.Hash{31: 8}],
+		snapshot.Get(testPair, common.Hash{31: 8}))
+	assert.Equal(t, common.Hash{}, snapshot.Get(testPair, common.Hash{31: 9}))
+	assert.Equal(t, common.Hash{}, snapshot.Get(testOther, common.Hash{31: 8}))
+	assert.Equal(t, common.Hash{}, snapshot.Get(testEmpty, common.Hash{31: 1}))
+	assert.Equal(t, map[string]int{"eth_getBlockByNumber": 1, "eth_getProof": 3}, node.Calls)
+	// the header of a known block is fetched along with the proofs
+	assert.Equal(t, 1, node.Requests)
+}
+
+func TestReadStorage_Latest(t *testing.T) {
+	t.Parallel()
+	node, client := testutil.NewEVMStateNode(t, newTestStorage())
+	snapshot, err := eth.ReadStorage(context.Background(), client.GetETHClient().Client(), nil, testQueries(),
+		eth.DefaultBatchRetry)
+	require.NoError(t, err)
+	assert.True(t, snapshot.Verified)
+	assert.EqualValues(t, 100, snapshot.BlockNumber)
+	assert.Equal(t, 2, node.Requests)
+
+	// a header held by the caller is not fetched again
+	header, err := eth.FetchHeader(context.Background(), client.GetETHClient().Client(), nil)
+	require.NoError(t, err)
+	node.Calls, node.Requests = map[string]int{}, 0
+	snapshot, err = eth.ReadStorageAt(context.Background(), client.GetETHClient().Client(), header, testQueries(),
+		eth.DefaultBatchRetry)
+	require.NoError(t, err)
+	assert.Equal(t, header.Root, snapshot.StateRoot)
+	assert.Equal(t, map[string]int{"eth_getProof": 3}, node.Calls)
+	assert.Equal(t, 1, node.Requests)
+}
+
+func TestReadStorage_NoProofs(t *testing.T) {
+	t.Parallel()
+	node, client := testutil.NewEVMStateNode(t, newTestStorage())
+	node.NoProofs = true
+	snapshot, err := eth.ReadStorage(context.Background(), client.GetETHClient().Client(), nil, testQueries(),
+		eth.DefaultBatchRetry)
+	require.NoError(t, err)
+	assert.False(t, snapshot.Verified)
+	assert.Equal(t, node.State.Accounts[testPair].Storage[common.Hash{31: 8}],
+		snapshot.Get(testPair, common.Hash{31: 8}))
+	assert.Equal(t, 4, node.Calls["eth_getStorageAt"])
+}
+
+func TestAccountProof_Verify(t *testing.T) {
+	t.Parallel()
+	node, client := testutil.NewEVMStateNode(t, newTestStorage())
+	rpcClient := client.GetETHClient().Client()
+	header, err := node.GetBlockByNumber(context.Background(), "latest", false)
+	require.NoError(t, err)
+	root := header.Root
+	var proofs []eth.AccountProof
+	for _, query := range testQueries() {
+		var proof eth.AccountProof
+		require.NoError(t, rpcClient.Call(&proof, "eth_getProof", query.Address, query.Slots, "latest"))
+		require.NoError(t, proof.Verify(root, query.Address, query.Slots))
+		proofs = append(proofs, proof)
+	}
+
+	// a forged value
+	forged := proofs[0]
+	forged.StorageProof = append([]eth.StorageProof(nil), forged.StorageProof...)
+	forged.StorageProof[0].Value = (*hexutil.Big)(big.NewInt(1))
+	assert.ErrorIs(t, forged.Verify(root, testPair, testQueries()[0].Slots), eth.ErrInvalidProof)
+
+	// a forged value of an account without storage
+	forged = proofs[1]
+	forged.StorageProof = []eth.StorageProof{{Value: (*hexutil.Big)(big.NewInt(1))}}
+	assert.ErrorIs(t, forged.Verify(root, testOther, testQueries()[1].Slots), eth.ErrInvalidProof)
+
+	// a proof of another account
+	assert.ErrorIs(t, proofs[1].Verify(root, testPair, testQueries()[1].Slots), eth.ErrInvalidProof)
+
+	// another state root
+	assert.ErrorIs(t, proofs[0].Verify(common.Hash{1}, testPair, testQueries()[0].Slots), eth.ErrInvalidProof)
+}
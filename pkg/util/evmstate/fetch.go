@@ -0,0 +1,154 @@
+package evmstate
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math/big"
+	"slices"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/holiman/uint256"
+
+	utileth "github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/eth"
+)
+
+// MaxCollectRounds bounds the fetch rounds of Collect.
+const MaxCollectRounds = 16
+
+// Collect builds the State that probe needs, as of blockNumber (nil for latest). Starting from seed, it fetches the
+// missing accounts and storage slots with eth_getProof and eth_getCode, then runs probe, which returns what its calls
+// found missing, round after round until nothing is.
+func Collect(ctx context.Context, client *ethrpc.Client, blockNumber *big.Int,
+	overrides map[common.Address]gethclient.OverrideAccount, seed Keys, probe func(*State) Keys) (*State, error) {
+	header, err := client.GetETHClient().HeaderByNumber(ctx, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch block header: %w", err)
+	}
+
+	state := &State{
+		BlockNumber: header.Number.Uint64(),
+		Time:        header.Time,
+		Accounts:    make(map[common.Address]*Account),
+	}
+	missing := seed
+	for range MaxCollectRounds {
+		if err = Fetch(ctx, client.GetETHClient().Client(), state, missing, header.Number, overrides); err != nil {
+			return nil, fmt.Errorf("failed to fetch state: %w", err)
+		}
+		if missing = probe(state); len(missing) == 0 {
+			return state, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrIncomplete, missing)
+}
+
+type storageProof struct {
+	Value *hexutil.Big `json:"value"`
+}
+
+type accountProof struct {
+	Balance      *hexutil.Big   `json:"balance"`
+	Nonce        hexutil.Uint64 `json:"nonce"`
+	StorageProof []storageProof `json:"storageProof"`
+}
+
+// Fetch fetches the keys into state in one batch, then applies the overrides of the fetched accounts and slots.
+func Fetch(ctx context.Context, client *rpc.Client, state *State, keys Keys, blockNumber *big.Int,
+	overrides map[common.Address]gethclient.OverrideAccount) error {
+	type request struct {
+		addr  common.Address
+		slots []common.Hash
+		proof accountProof
+		code  hexutil.Bytes
+	}
+	blockArg := hexutil.EncodeBig(blockNumber)
+	requests := make([]*request, 0, len(keys))
+	batch := make([]rpc.BatchElem, 0, 2*len(keys))
+	for addr, slots := range keys {
+		req := &request{addr: addr, slots: slices.AppendSeq(make([]common.Hash, 0, len(slots)), maps.Keys(slots))}
+		requests = append(requests, req)
+		batch = append(batch, rpc.BatchElem{
+			Method: "eth_getProof",
+			Args:   []any{addr, req.slots, blockArg},
+			Result: &req.proof,
+		})
+		if _, ok := state.Accounts[addr]; !ok {
+			batch = append(batch, rpc.BatchElem{
+				Method: "eth_getCode",
+				Args:   []any{addr, blockArg},
+				Result: &req.code,
+			})
+		}
+	}
+	if err := utileth.BatchCallWithRetry(ctx, client, batch, utileth.DefaultBatchRetry); err != nil {
+		return err
+	}
+	for _, elem := range batch {
+		if elem.Error != nil {
+			return elem.Error
+		}
+	}
+
+	if state.Accounts == nil {
+		state.Accounts = make(map[common.Address]*Account, len(requests))
+	}
+	for _, req := range requests {
+		if len(req.proof.StorageProof) != len(req.slots) {
+			return fmt.Errorf("eth_getProof of %s returned %d slots instead of %d", req.addr,
+				len(req.proof.StorageProof), len(req.slots))
+		}
+		account, known := state.Accounts[req.addr]
+		if !known {
+			account = &Account{Nonce: uint64(req.proof.Nonce)}
+			if len(req.code) > 0 {
+				account.Code = req.code
+			}
+			if req.proof.Balance != nil && req.proof.Balance.ToInt().Sign() > 0 {
+				account.Balance, _ = uint256.FromBig(req.proof.Balance.ToInt())
+			}
+			state.Accounts[req.addr] = account
+		}
+		if account.Storage == nil && len(req.slots) > 0 {
+			account.Storage = make(map[common.Hash]common.Hash, len(req.slots))
+		}
+		for i, slot := range req.slots {
+			var value common.Hash
+			if v := req.proof.StorageProof[i].Value; v != nil {
+				value = common.BigToHash(v.ToInt())
+			}
+			account.Storage[slot] = value
+		}
+		if override, ok := overrides[req.addr]; ok {
+			applyOverride(account, req.slots, override, !known)
+		}
+	}
+	return nil
+}
+
+// applyOverride applies an eth_call state override to the slots just fetched of an account, and to the account itself
+// if it was just fetched too.
+func applyOverride(account *Account, slots []common.Hash, override gethclient.OverrideAccount, newAccount bool) {
+	if newAccount {
+		if override.Nonce != 0 {
+			account.Nonce = override.Nonce
+		}
+		if override.Code != nil {
+			account.Code = override.Code
+		}
+		if override.Balance != nil {
+			account.Balance, _ = uint256.FromBig(override.Balance)
+		}
+	}
+	for _, slot := range slots {
+		if override.State != nil {
+			account.Storage[slot] = override.State[slot]
+		} else if value, ok := override.StateDiff[slot]; ok {
+			account.Storage[slot] = value
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package evmstate_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/evmstate"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/testutil"
+)
+
+func TestCollect(t *testing.T) {
+	t.Parallel()
+	node, client := testutil.NewEVMStateNode(t, newTestState())
+	node.State.Accounts[testCounter].Storage[common.Hash{31: 9}] = common.Hash{31: 9} // never read
+
+	seed := evmstate.Keys{}
+	seed.Add(testCounter)
+	state, err := evmstate.Collect(context.Background(), client, nil, nil, seed,
+		func(state *evmstate.State) evmstate.Keys {
+			_, missing, _ := state.Call(testMsg())
+			return missing
+		})
+	require.NoError(t, err)
+	assert.Equal(t, newTestState(), state)
+	// a round fetching the account, then one fetching the slot read
+	assert.Equal(t, map[string]int{"eth_getBlockByNumber": 1, "eth_getProof": 2, "eth_getCode": 1}, node.Calls)
+}
+
+func TestCollect_Overrides(t *testing.T) {
+	t.Parallel()
+	_, client := testutil.NewEVMStateNode(t, newTestState())
+	overrides := map[common.Address]gethclient.OverrideAccount{
+		testCounter: {StateDiff: map[common.Hash]common.Hash{{}: {31: 1}}},
+	}
+
+	seed := evmstate.Keys{}
+	seed.Add(testCounter, common.Hash{})
+	state, err := evmstate.Collect(context.Background(), client, nil, overrides, seed,
+		func(*evmstate.State) evmstate.Keys { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, common.Hash{31: 1}, state.Accounts[testCounter].Storage[common.Hash{}])
+
+	// probes that never run through
+	_, err = evmstate.Collect(context.Background(), client, nil, nil, seed, func(*evmstate.State) evmstate.Keys {
+		return evmstate.Keys{testCaller: {}}
+	})
+	assert.ErrorIs(t, err, evmstate.ErrIncomplete)
+}
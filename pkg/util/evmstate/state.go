@@ -0,0 +1,335 @@
+// Package evmstate runs contract calls in an embedded go-ethereum EVM against a snapshot of the few accounts and
+// storage slots they read, so that simulators can execute on-chain code instead of porting it.
+//
+// A State only holds the state its calls were seen reading: Collect builds it by running probe calls and fetching
+// what they read, round after round. A call reading anything else fails with ErrStateNotCached rather than reading
+// zero.
+package evmstate
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+// DefaultGas is the gas limit of calls that do not set one.
+const DefaultGas = 5_000_000
+
+var (
+	ErrStateNotCached = errors.New("call reads state outside the snapshot")
+	ErrIncomplete     = errors.New("state still incomplete after max rounds")
+)
+
+// State is a snapshot of accounts, as of a block.
+type State struct {
+	BlockNumber uint64                      `json:"b"`
+	Time        uint64                      `json:"t"`
+	Accounts    map[common.Address]*Account `json:"a"`
+}
+
+// Account is an account of a State. Storage only holds the slots read.
+type Account struct {
+	Nonce   uint64                      `json:"n,omitempty"`
+	Balance *uint256.Int                `json:"b,omitempty"`
+	Code    []byte                      `json:"c,omitempty"`
+	Storage map[common.Hash]common.Hash `json:"s,omitempty"`
+}
+
+// Diff is the state a call wrote, for Apply to apply to the snapshot.
+type Diff struct {
+	Storage  map[common.Address]map[common.Hash]common.Hash
+	Balances map[common.Address]*uint256.Int
+}
+
+// Keys are accounts and storage slots. An account with no slots stands for the account itself.
+type Keys map[common.Address]map[common.Hash]struct{}
+
+// Add adds the account addr and its slots.
+func (k Keys) Add(addr common.Address, slots ...common.Hash) {
+	accountSlots, ok := k[addr]
+	if !ok {
+		accountSlots = make(map[common.Hash]struct{}, len(slots))
+		k[addr] = accountSlots
+	}
+	for _, slot := range slots {
+		accountSlots[slot] = struct{}{}
+	}
+}
+
+// Merge adds the accounts and slots of other.
+func (k Keys) Merge(other Keys) {
+	for addr, slots := range other {
+		k.Add(addr, slices.Collect(maps.Keys(slots))...)
+	}
+}
+
+// String describes one of the keys, for error messages.
+func (k Keys) String() string {
+	for addr, slots := range k {
+		for slot := range slots {
+			return fmt.Sprintf("%s slot %s", addr, slot)
+		}
+		return addr.String()
+	}
+	return ""
+}
+
+// StorageKey is a storage slot of an account.
+type StorageKey struct {
+	Address common.Address
+	Slot    common.Hash
+}
+
+// Msg is a call to run against a State.
+type Msg struct {
+	ChainID valueobject.ChainID
+	From    common.Address
+	To      common.Address
+	Input   []byte
+	Value   *uint256.Int
+	// Gas is the gas limit, DefaultGas if 0.
+	Gas uint64
+	// Storage overrides storage slots of the snapshot for this call only. Overridden slots count as cached.
+	Storage map[common.Address]map[common.Hash]common.Hash
+	// Transient sets transient storage before the call, e.g. to unlock a contract the callee calls back.
+	Transient map[common.Address]map[common.Hash]common.Hash
+	// TraceReads records the storage reads of the call into Result.Reads.
+	TraceReads bool
+}
+
+// Result is the outcome of a call.
+type Result struct {
+	Ret     []byte
+	GasUsed int64
+	Diff    *Diff
+	// Reads are the storage reads of the call in order, if Msg.TraceReads.
+	Reads []StorageKey
+}
+
+// Call runs msg in the block after the snapshot's, at the snapshot's time, so that its result only depends on the
+// snapshot. Besides the result, it returns the state the call read but the snapshot lacks, in which case there is no
+// result. A reverted call returns an error wrapping vm.ErrExecutionReverted.
+func (s *State) Call(msg *Msg) (*Result, Keys, error) {
+	statedb, err := s.stateDB()
+	if err != nil {
+		return nil, nil, err
+	}
+	for addr, slots := range msg.Storage {
+		for slot, value := range slots {
+			statedb.SetState(addr, slot, value)
+		}
+	}
+
+	chainConfig := *params.AllDevChainProtocolChanges
+	chainConfig.ChainID = new(big.Int).SetUint64(uint64(msg.ChainID))
+	gasLimit := msg.Gas
+	if gasLimit == 0 {
+		gasLimit = DefaultGas
+	}
+	blockCtx := vm.BlockContext{
+		CanTransfer: canTransfer,
+		Transfer:    transfer,
+		GetHash:     func(uint64) common.Hash { return common.Hash{} },
+		GasLimit:    gasLimit,
+		BlockNumber: new(big.Int).SetUint64(s.BlockNumber + 1),
+		Time:        s.Time,
+		Difficulty:  new(big.Int),
+		BaseFee:     new(big.Int),
+		BlobBaseFee: new(big.Int),
+		Random:      &common.Hash{},
+	}
+	rules := chainConfig.Rules(blockCtx.BlockNumber, true, blockCtx.Time)
+	precompiles := vm.ActivePrecompiles(rules)
+
+	var reads []StorageKey
+	missing, written := Keys{}, Keys{}
+	checkAccount := func(addr common.Address) {
+		if _, ok := s.Accounts[addr]; !ok && !slices.Contains(precompiles, addr) {
+			missing.Add(addr)
+		}
+	}
+	isCached := func(addr common.Address, slot common.Hash) bool {
+		if _, ok := written[addr][slot]; ok {
+			return true
+		} else if _, ok = msg.Storage[addr][slot]; ok {
+			return true
+		} else if account, ok := s.Accounts[addr]; ok {
+			_, ok = account.Storage[slot]
+			return ok
+		}
+		return false
+	}
+	tracer := &tracing.Hooks{
+		OnEnter: func(depth int, _ byte, _, to common.Address, _ []byte, _ uint64, _ *big.Int) {
+			if depth > 0 {
+				checkAccount(to)
+			}
+		},
+		OnOpcode: func(_ uint64, op byte, _, _ uint64, scope tracing.OpContext, _ []byte, _ int, _ error) {
+			stack := scope.StackData()
+			if len(stack) == 0 {
+				return
+			}
+			top := stack[len(stack)-1]
+			switch vm.OpCode(op) {
+			case vm.SLOAD:
+				addr, slot := scope.Address(), common.Hash(top.Bytes32())
+				if msg.TraceReads {
+					reads = append(reads, StorageKey{Address: addr, Slot: slot})
+				}
+				if !isCached(addr, slot) {
+					missing.Add(addr, slot)
+				}
+			case vm.SSTORE:
+				written.Add(scope.Address(), top.Bytes32())
+			case vm.BALANCE, vm.EXTCODESIZE, vm.EXTCODECOPY, vm.EXTCODEHASH:
+				checkAccount(top.Bytes20())
+			}
+		},
+	}
+
+	evm := vm.NewEVM(blockCtx, statedb, &chainConfig, vm.Config{Tracer: tracer, NoBaseFee: true})
+	evm.SetTxContext(vm.TxContext{Origin: msg.From, GasPrice: new(uint256.Int)})
+	statedb.Prepare(rules, msg.From, blockCtx.Coinbase, &msg.To, precompiles, nil)
+	for addr, slots := range msg.Transient {
+		for slot, value := range slots {
+			statedb.SetTransientState(addr, slot, value)
+		}
+	}
+
+	value := msg.Value
+	if value == nil {
+		value = new(uint256.Int)
+	}
+	gas := vm.NewGasBudget(gasLimit, 0)
+	ret, left, err := evm.Call(msg.From, msg.To, msg.Input, gas, value)
+	if len(missing) > 0 {
+		return nil, missing, nil
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("call to %s failed: %w", msg.To, err)
+	}
+	return &Result{
+		Ret:     ret,
+		GasUsed: int64(left.Used(gas)),
+		Diff:    s.diff(statedb, written),
+		Reads:   reads,
+	}, nil, nil
+}
+
+// stateDB returns an in-memory StateDB holding the snapshot.
+func (s *State) stateDB() (*state.StateDB, error) {
+	db := rawdb.NewMemoryDatabase()
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabase(triedb.NewDatabase(db, nil), state.NewCodeDB(db)))
+	if err != nil {
+		return nil, err
+	}
+	for addr, account := range s.Accounts {
+		statedb.CreateAccount(addr)
+		statedb.SetNonce(addr, account.Nonce, tracing.NonceChangeUnspecified)
+		if account.Balance != nil {
+			statedb.SetBalance(addr, account.Balance, tracing.BalanceChangeUnspecified)
+		}
+		if len(account.Code) > 0 {
+			statedb.SetCode(addr, account.Code, tracing.CodeChangeUnspecified)
+		}
+		for slot, value := range account.Storage {
+			statedb.SetState(addr, slot, value)
+		}
+	}
+	return statedb, nil
+}
+
+// diff returns the storage and balances of statedb that differ from the snapshot. written are the slots that may
+// have been written to; a reverted write leaves the slot unchanged.
+func (s *State) diff(statedb *state.StateDB, written Keys) *Diff {
+	diff := &Diff{}
+	for addr, slots := range written {
+		account := s.Accounts[addr]
+		for slot := range slots {
+			value := statedb.GetState(addr, slot)
+			if account != nil {
+				if prev, ok := account.Storage[slot]; ok && prev == value {
+					continue
+				}
+			}
+			if diff.Storage == nil {
+				diff.Storage = make(map[common.Address]map[common.Hash]common.Hash)
+			}
+			if diff.Storage[addr] == nil {
+				diff.Storage[addr] = make(map[common.Hash]common.Hash)
+			}
+			diff.Storage[addr][slot] = value
+		}
+	}
+	for addr, account := range s.Accounts {
+		balance := statedb.GetBalance(addr)
+		if account.Balance == nil && balance.IsZero() || account.Balance != nil && account.Balance.Eq(balance) {
+			continue
+		}
+		if diff.Balances == nil {
+			diff.Balances = make(map[common.Address]*uint256.Int)
+		}
+		diff.Balances[addr] = balance.Clone()
+	}
+	return diff
+}
+
+// Apply applies the writes of a call, copying the accounts it changes so that clones of the snapshot are left
+// untouched.
+func (s *State) Apply(diff *Diff) {
+	if diff == nil {
+		return
+	}
+	touch := func(addr common.Address) *Account {
+		var account Account
+		if prev := s.Accounts[addr]; prev != nil {
+			account = *prev
+		}
+		account.Storage = maps.Clone(account.Storage)
+		if s.Accounts == nil {
+			s.Accounts = make(map[common.Address]*Account)
+		}
+		s.Accounts[addr] = &account
+		return &account
+	}
+	for addr, slots := range diff.Storage {
+		account := touch(addr)
+		if account.Storage == nil {
+			account.Storage = make(map[common.Hash]common.Hash, len(slots))
+		}
+		maps.Copy(account.Storage, slots)
+	}
+	for addr, balance := range diff.Balances {
+		touch(addr).Balance = balance
+	}
+}
+
+// Clone returns a copy of the snapshot sharing its accounts, which Apply copies on write.
+func (s *State) Clone() *State {
+	cloned := *s
+	cloned.Accounts = maps.Clone(s.Accounts)
+	return &cloned
+}
+
+func canTransfer(db vm.StateDB, addr common.Address, amount *uint256.Int) bool {
+	return db.GetBalance(addr).Cmp(amount) >= 0
+}
+
+func transfer(db vm.StateDB, sender, recipient common.Address, amount *uint256.Int, _ *params.Rules) {
+	db.SubBalance(sender, amount, tracing.BalanceChangeTransfer)
+	db.AddBalance(recipient, amount, tracing.BalanceChangeTransfer)
+}
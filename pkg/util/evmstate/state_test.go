@@ -0,0 +1,126 @@
+package evmstate_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/evmstate"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+var (
+	testCounter = common.HexToAddress("0x00000000000000000000000000000000000000c0")
+	testCaller  = common.HexToAddress("0x00000000000000000000000000000000000000ca")
+
+	// testCounterCode increments slot 0 and returns it:
+	//
+	//	sstore(0, add(sload(0), 1))
+	//	mstore(0, sload(0))
+	//	return(0, 0x20)
+	testCounterCode = common.FromHex("0x60005460010160005560005460005260206000f3")
+)
+
+func newTestState() *evmstate.State {
+	return &evmstate.State{
+		BlockNumber: 100,
+		Time:        1700000000,
+		Accounts: map[common.Address]*evmstate.Account{
+			testCounter: {Code: testCounterCode, Storage: map[common.Hash]common.Hash{{}: {31: 7}}},
+		},
+	}
+}
+
+func testMsg() *evmstate.Msg {
+	return &evmstate.Msg{ChainID: valueobject.ChainIDEthereum, From: testCaller, To: testCounter, TraceReads: true}
+}
+
+func TestState_Call(t *testing.T) {
+	t.Parallel()
+	state := newTestState()
+	res, missing, err := state.Call(testMsg())
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+	assert.Equal(t, common.Hash{31: 8}.Bytes(), res.Ret)
+	assert.Positive(t, res.GasUsed)
+	assert.Equal(t, []evmstate.StorageKey{{testCounter, common.Hash{}}, {testCounter, common.Hash{}}}, res.Reads)
+	assert.Equal(t, &evmstate.Diff{Storage: map[common.Address]map[common.Hash]common.Hash{testCounter: {{}: {31: 8}}}},
+		res.Diff)
+
+	// the diff applies to the state, not to its clones
+	cloned := state.Clone()
+	cloned.Apply(res.Diff)
+	res, _, err = cloned.Call(testMsg())
+	require.NoError(t, err)
+	assert.Equal(t, common.Hash{31: 9}.Bytes(), res.Ret)
+	res, _, err = state.Call(testMsg())
+	require.NoError(t, err)
+	assert.Equal(t, common.Hash{31: 8}.Bytes(), res.Ret)
+
+	// storage overrides hold for the call only
+	msg := testMsg()
+	msg.Storage = map[common.Address]map[common.Hash]common.Hash{testCounter: {{}: {31: 1}}}
+	res, _, err = state.Call(msg)
+	require.NoError(t, err)
+	assert.Equal(t, common.Hash{31: 2}.Bytes(), res.Ret)
+	assert.Equal(t, common.Hash{31: 7}, state.Accounts[testCounter].Storage[common.Hash{}])
+}
+
+func TestState_Call_Missing(t *testing.T) {
+	t.Parallel()
+	state := newTestState()
+	delete(state.Accounts[testCounter].Storage, common.Hash{})
+	res, missing, err := state.Call(testMsg())
+	require.NoError(t, err)
+	assert.Nil(t, res)
+	assert.Equal(t, evmstate.Keys{testCounter: {{}: {}}}, missing)
+
+	// a storage override counts as cached
+	msg := testMsg()
+	msg.Storage = map[common.Address]map[common.Hash]common.Hash{testCounter: {{}: {}}}
+	_, missing, err = state.Call(msg)
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+
+	msg = testMsg()
+	msg.Gas = 100
+	_, _, err = newTestState().Call(msg)
+	assert.ErrorIs(t, err, vm.ErrOutOfGas)
+}
+
+func TestState_Call_Value(t *testing.T) {
+	t.Parallel()
+	state := newTestState()
+	state.Accounts[testCaller] = &evmstate.Account{Balance: uint256.NewInt(10)}
+	msg := testMsg()
+	msg.Value = uint256.NewInt(4)
+	res, _, err := state.Call(msg)
+	require.NoError(t, err)
+	assert.Equal(t, map[common.Address]*uint256.Int{testCaller: uint256.NewInt(6), testCounter: uint256.NewInt(4)},
+		res.Diff.Balances)
+
+	state.Apply(res.Diff)
+	assert.Equal(t, uint256.NewInt(6), state.Accounts[testCaller].Balance)
+	assert.Equal(t, uint256.NewInt(4), state.Accounts[testCounter].Balance)
+}
+
+func TestState_Call_Time(t *testing.T) {
+	t.Parallel()
+	// testClock returns the block timestamp:
+	//
+	//	mstore(0, timestamp())
+	//	return(0, 0x20)
+	testClock := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	state := newTestState()
+	state.Accounts[testClock] = &evmstate.Account{Code: common.FromHex("0x4260005260206000f3")}
+	msg := testMsg()
+	msg.To = testClock
+	res, _, err := state.Call(msg)
+	require.NoError(t, err)
+	assert.Equal(t, common.BigToHash(new(big.Int).SetUint64(state.Time)).Bytes(), res.Ret, "the snapshot's time")
+}
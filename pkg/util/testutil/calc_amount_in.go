@@ -125,3 +125,49 @@ func TestCalcAmountIn[TB interface {
 		}
 	}
 }
+
+// TestCalcAmountInMatchesInversion checks a native CalcAmountIn implementation against pool.ExactOutAdapter, which
+// inverts CalcAmountOut by search. amountOuts are sampled from the outputs of power-of-ten amountIns, and the native
+// amountIn must be within epsilon (relative) of the inverted one.
+func TestCalcAmountInMatchesInversion[TB interface {
+	testing.TB
+	Run(string, func(TB)) bool
+}](tb TB, poolSim interface {
+	pool.IPoolSimulator
+	pool.IPoolExactOutSimulator
+}, epsilon float64) {
+	tb.Helper()
+	adapter := pool.NewExactOutAdapter(poolSim, pool.InversionConfig{})
+	tokens := poolSim.GetTokens()
+	for idxIn, tokenIn := range tokens {
+		for _, tokenOut := range poolSim.CanSwapFrom(tokenIn) {
+			idxOut := poolSim.GetTokenIndex(tokenOut)
+			for exp := 3; exp <= 24; exp += 3 {
+				resOut, err := pool.CalcAmountOut(ctx, poolSim,
+					pool.TokenAmount{Token: tokenIn, Amount: bignumber.TenPowInt(exp)}, tokenOut, nil)
+				if err != nil {
+					continue
+				}
+				amountOut := resOut.TokenAmountOut.Amount
+				tb.Run(fmt.Sprintf("? token%d -> %s token%d", idxIn, amountOut, idxOut), func(tb TB) {
+					tb.Helper()
+					params := pool.CalcAmountInParams{
+						TokenAmountOut: pool.TokenAmount{Token: tokenOut, Amount: amountOut},
+						TokenIn:        tokenIn,
+					}
+					native, err := poolSim.CalcAmountIn(params)
+					require.NoError(tb, err)
+					inverted, stats, err := adapter.CalcAmountInWithStats(ctx, params)
+					require.NoError(tb, err)
+
+					nativeF, _ := native.TokenAmountIn.Amount.Float64()
+					invertedF, _ := inverted.TokenAmountIn.Amount.Float64()
+					tb.Logf("native: %s, inverted: %s in %d iterations", native.TokenAmountIn.Amount,
+						inverted.TokenAmountIn.Amount, stats.Iterations)
+					assert.InEpsilonf(tb, invertedF, nativeF, epsilon, "expected ~%s, got %s",
+						inverted.TokenAmountIn.Amount, native.TokenAmountIn.Amount)
+				})
+			}
+		}
+	}
+}
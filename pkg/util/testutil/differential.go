@@ -0,0 +1,256 @@
+package testutil
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+// DifferentialVariant is one simulator implementation taking part in a differential test.
+type DifferentialVariant struct {
+	Name string
+	// PoolType is the pool type the simulator is instantiated with through pool.Factory.
+	PoolType string
+	// Adapt maps a generated pool state onto the variant's own entity.Pool encoding (extra, static extra, swap fee...).
+	// When nil, only the pool type is changed.
+	Adapt func(entity.Pool) (entity.Pool, error)
+}
+
+// DifferentialConfig configures a differential test between simulators expected to agree.
+type DifferentialConfig struct {
+	Variants []DifferentialVariant
+	// Generate returns a random valid pool state, which is then adapted to every variant.
+	Generate func(r *rand.Rand) entity.Pool
+	// AmountIn returns a random amount of token index tokenIn to swap. Defaults to a log-uniform amount between 1 and
+	// twice the reserve of tokenIn.
+	AmountIn func(r *rand.Rand, p entity.Pool, tokenIn int) *big.Int
+	// Shrink returns simpler variants of a pool state to minimize counterexamples, e.g. with fewer ticks. Reserves are
+	// always shrunk.
+	Shrink func(entity.Pool) []entity.Pool
+	// ToleranceBps is the relative difference allowed between amounts out. Defaults to exact agreement.
+	ToleranceBps int64
+	ChainID      valueobject.ChainID
+	Runs         int    // defaults to 256
+	Seed         uint64 // runs are reproducible from (Seed, run)
+}
+
+// DifferentialCase is a swap to quote on every variant.
+type DifferentialCase struct {
+	Pool              entity.Pool
+	TokenIn, TokenOut int
+	AmountIn          *big.Int
+}
+
+// DifferentialOutcome is the quote of a variant for a DifferentialCase.
+type DifferentialOutcome struct {
+	Variant   string
+	AmountOut *big.Int
+	Err       error
+}
+
+// Counterexample is a minimized DifferentialCase on which the variants disagree.
+type Counterexample struct {
+	Seed     uint64
+	Run      int
+	Case     DifferentialCase
+	Outcomes []DifferentialOutcome
+}
+
+func (c *Counterexample) String() string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "seed %d run %d: swap %s token%d -> token%d\n", c.Seed, c.Run, c.Case.AmountIn,
+		c.Case.TokenIn, c.Case.TokenOut)
+	_, _ = fmt.Fprintf(&sb, "  reserves: %v\n  extra: %s\n  staticExtra: %s\n  swapFee: %v\n", c.Case.Pool.Reserves,
+		c.Case.Pool.Extra, c.Case.Pool.StaticExtra, c.Case.Pool.SwapFee)
+	for _, o := range c.Outcomes {
+		if o.Err != nil {
+			_, _ = fmt.Fprintf(&sb, "  %s: error %v\n", o.Variant, o.Err)
+		} else {
+			_, _ = fmt.Fprintf(&sb, "  %s: %s\n", o.Variant, o.AmountOut)
+		}
+	}
+	return sb.String()
+}
+
+// TestDifferential fails tb with a minimized counterexample if the variants of cfg disagree on any generated swap.
+func TestDifferential(tb testing.TB, cfg DifferentialConfig) {
+	tb.Helper()
+	if c := FindCounterexample(cfg); c != nil {
+		tb.Fatalf("simulators disagree:\n%s", c)
+	}
+}
+
+// FindCounterexample quotes random swaps on random pool states with every variant of cfg and returns the first
+// disagreement found, minimized, or nil if they all agree. Quotes agree if they all fail (a zero amount out counts as a
+// failure) or if all amounts out are within cfg.ToleranceBps of each other.
+func FindCounterexample(cfg DifferentialConfig) *Counterexample {
+	runs := cfg.Runs
+	if runs <= 0 {
+		runs = 256
+	}
+	if cfg.AmountIn == nil {
+		cfg.AmountIn = defaultDifferentialAmountIn
+	}
+
+	for run := range runs {
+		r := rand.New(rand.NewPCG(cfg.Seed, uint64(run)))
+		p := cfg.Generate(r)
+		if len(p.Tokens) < 2 {
+			continue
+		}
+		tokenIn := r.IntN(len(p.Tokens))
+		tokenOut := (tokenIn + 1 + r.IntN(len(p.Tokens)-1)) % len(p.Tokens)
+		c := DifferentialCase{Pool: p, TokenIn: tokenIn, TokenOut: tokenOut, AmountIn: cfg.AmountIn(r, p, tokenIn)}
+
+		if outcomes, ok := cfg.agree(c); !ok {
+			c, outcomes = cfg.minimize(c, outcomes)
+			return &Counterexample{Seed: cfg.Seed, Run: run, Case: c, Outcomes: outcomes}
+		}
+	}
+	return nil
+}
+
+func (cfg *DifferentialConfig) quote(c DifferentialCase, v DifferentialVariant) DifferentialOutcome {
+	outcome := DifferentialOutcome{Variant: v.Name}
+	p := c.Pool
+	p.Type = v.PoolType
+	if v.Adapt != nil {
+		if p, outcome.Err = v.Adapt(p); outcome.Err != nil {
+			return outcome
+		}
+	}
+	factory := pool.Factory(v.PoolType)
+	if factory == nil {
+		outcome.Err = fmt.Errorf("no factory registered for pool type %s", v.PoolType)
+		return outcome
+	}
+	poolSim, err := factory(pool.FactoryParams{EntityPool: p, ChainID: cfg.ChainID})
+	if err != nil {
+		outcome.Err = err
+		return outcome
+	}
+	res, err := pool.CalcAmountOut(ctx, poolSim,
+		pool.TokenAmount{Token: p.Tokens[c.TokenIn].Address, Amount: new(big.Int).Set(c.AmountIn)},
+		p.Tokens[c.TokenOut].Address, nil)
+	if err != nil {
+		outcome.Err = err
+	} else if res.TokenAmountOut == nil || res.TokenAmountOut.Amount == nil || res.TokenAmountOut.Amount.Sign() <= 0 {
+		outcome.Err = fmt.Errorf("zero amount out")
+	} else {
+		outcome.AmountOut = res.TokenAmountOut.Amount
+	}
+	return outcome
+}
+
+func (cfg *DifferentialConfig) agree(c DifferentialCase) ([]DifferentialOutcome, bool) {
+	outcomes := make([]DifferentialOutcome, len(cfg.Variants))
+	var lo, hi *big.Int
+	failed := 0
+	for i, v := range cfg.Variants {
+		outcomes[i] = cfg.quote(c, v)
+		if outcomes[i].Err != nil {
+			failed++
+			continue
+		}
+		if lo == nil || outcomes[i].AmountOut.Cmp(lo) < 0 {
+			lo = outcomes[i].AmountOut
+		}
+		if hi == nil || outcomes[i].AmountOut.Cmp(hi) > 0 {
+			hi = outcomes[i].AmountOut
+		}
+	}
+	if failed == len(outcomes) {
+		return outcomes, true
+	} else if failed > 0 {
+		return outcomes, false
+	}
+	// (hi - lo) * 10000 <= hi * ToleranceBps
+	diff := new(big.Int).Sub(hi, lo)
+	diff.Mul(diff, bignumber.BasisPoint)
+	return outcomes, diff.Cmp(new(big.Int).Mul(hi, big.NewInt(cfg.ToleranceBps))) <= 0
+}
+
+// minimize greedily replaces c with simpler cases that still make the variants disagree, until none does.
+func (cfg *DifferentialConfig) minimize(c DifferentialCase, outcomes []DifferentialOutcome) (DifferentialCase,
+	[]DifferentialOutcome) {
+	for steps := 0; steps < 1000; steps++ {
+		shrunk := false
+		for _, candidate := range cfg.shrinkCase(c) {
+			if o, ok := cfg.agree(candidate); !ok {
+				c, outcomes, shrunk = candidate, o, true
+				break
+			}
+		}
+		if !shrunk {
+			break
+		}
+	}
+	return c, outcomes
+}
+
+func (cfg *DifferentialConfig) shrinkCase(c DifferentialCase) []DifferentialCase {
+	var candidates []DifferentialCase
+	for _, amountIn := range shrinkInt(c.AmountIn) {
+		candidate := c
+		candidate.AmountIn = amountIn
+		candidates = append(candidates, candidate)
+	}
+	for i, reserve := range c.Pool.Reserves {
+		r, ok := new(big.Int).SetString(reserve, 10)
+		if !ok {
+			continue
+		}
+		for _, shrunk := range shrinkInt(r) {
+			candidate := c
+			candidate.Pool.Reserves = append(entity.PoolReserves(nil), c.Pool.Reserves...)
+			candidate.Pool.Reserves[i] = shrunk.String()
+			candidates = append(candidates, candidate)
+		}
+	}
+	if cfg.Shrink != nil {
+		for _, p := range cfg.Shrink(c.Pool) {
+			candidate := c
+			candidate.Pool = p
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates
+}
+
+// shrinkInt returns positive integers smaller than or rounder than x, simplest first: x rounded down to fewer
+// significant digits, then halved.
+func shrinkInt(x *big.Int) []*big.Int {
+	if x.Cmp(bignumber.One) <= 0 {
+		return nil
+	}
+	var candidates []*big.Int
+	digits := x.String()
+	for sig := 1; sig < len(digits); sig++ {
+		if strings.Trim(digits[sig:], "0") == "" {
+			break // already round at this precision
+		}
+		rounded, _ := new(big.Int).SetString(digits[:sig]+strings.Repeat("0", len(digits)-sig), 10)
+		candidates = append(candidates, rounded)
+	}
+	return append(candidates, new(big.Int).Rsh(x, 1))
+}
+
+func defaultDifferentialAmountIn(r *rand.Rand, p entity.Pool, tokenIn int) *big.Int {
+	maxAmount := 1e18
+	if tokenIn < len(p.Reserves) {
+		if reserve, ok := new(big.Float).SetString(p.Reserves[tokenIn]); ok {
+			maxAmount, _ = reserve.Float64()
+			maxAmount *= 2
+		}
+	}
+	amount, _ := big.NewFloat(math.Pow(max(maxAmount, 2), r.Float64())).Int(nil)
+	return amount
+}
@@ -0,0 +1,230 @@
+package testutil_test
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/KyberNetwork/int256"
+	"github.com/goccy/go-json"
+	"github.com/holiman/uint256"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	solidlyv2 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/solidly-v2"
+	uniswapv3 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/uniswap/v3"
+	velodromev1 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/velodrome-v1"
+	velodromev2 "github.com/KyberNetwork/kyberswap-dex-lib/pkg/liquidity-source/velodrome-v2"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/equalizer"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/velocimeter"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/testutil"
+)
+
+// solidlyState is the shared state of Solidly-curve pools, encoded as a velodrome-v1 pool.
+type solidlyState struct {
+	velodromev1.PoolStaticExtra
+	velodromev1.PoolExtra
+}
+
+func generateSolidlyPool(stable bool) func(r *rand.Rand) entity.Pool {
+	return func(r *rand.Rand) entity.Pool {
+		decimals := []uint8{6, 8, 18}
+		tokens := make([]*entity.PoolToken, 2)
+		reserves := make(entity.PoolReserves, 2)
+		for i := range tokens {
+			dec := decimals[r.IntN(len(decimals))]
+			tokens[i] = &entity.PoolToken{Address: fmt.Sprintf("0x%040d", i+1), Decimals: dec, Swappable: true}
+			reserve, _ := new(big.Float).Mul(big.NewFloat(1+r.Float64()*9),
+				new(big.Float).SetInt(bignumber.TenPowInt(dec+uint8(2+r.IntN(8))))).Int(nil)
+			reserves[i] = reserve.String()
+		}
+		fees := []uint64{1, 2, 4, 5, 20, 30, 100}
+		return entity.Pool{
+			Address:     "0x0000000000000000000000000000000000000abc",
+			Exchange:    "solidly",
+			Tokens:      tokens,
+			Reserves:    reserves,
+			Extra:       string(lo.Must(json.Marshal(velodromev1.PoolExtra{Fee: fees[r.IntN(len(fees))]}))),
+			StaticExtra: string(lo.Must(json.Marshal(velodromev1.PoolStaticExtra{FeePrecision: 10000, Stable: stable}))),
+		}
+	}
+}
+
+func parseSolidlyState(p entity.Pool) (solidlyState, error) {
+	var s solidlyState
+	if err := json.Unmarshal([]byte(p.Extra), &s.PoolExtra); err != nil {
+		return s, err
+	}
+	return s, json.Unmarshal([]byte(p.StaticExtra), &s.PoolStaticExtra)
+}
+
+// adaptVelodromeV2 encodes token decimals and fee precision in the static extra, as velodrome-v2 and solidly-v2 do.
+func adaptVelodromeV2(p entity.Pool) (entity.Pool, error) {
+	s, err := parseSolidlyState(p)
+	if err != nil {
+		return p, err
+	}
+	staticExtra, err := json.Marshal(velodromev2.PoolStaticExtra{
+		FeePrecision: s.FeePrecision,
+		Decimal0:     uint256.MustFromBig(bignumber.TenPowInt(p.Tokens[0].Decimals)),
+		Decimal1:     uint256.MustFromBig(bignumber.TenPowInt(p.Tokens[1].Decimals)),
+		Stable:       s.Stable,
+	})
+	if err != nil {
+		return p, err
+	}
+	p.StaticExtra = string(staticExtra)
+	return p, nil
+}
+
+// adaptSwapFee encodes the fee as the swap fee fraction, as equalizer and velocimeter do.
+func adaptSwapFee(p entity.Pool) (entity.Pool, error) {
+	s, err := parseSolidlyState(p)
+	if err != nil {
+		return p, err
+	}
+	p.SwapFee = float64(s.Fee) / float64(s.FeePrecision)
+	p.StaticExtra = `{"stable":` + strconv.FormatBool(s.Stable) + `}`
+	return p, nil
+}
+
+var (
+	velodromeV1Variant = testutil.DifferentialVariant{Name: "velodrome-v1", PoolType: velodromev1.DexType}
+	velodromeV2Variant = testutil.DifferentialVariant{Name: "velodrome-v2", PoolType: velodromev2.DexType,
+		Adapt: adaptVelodromeV2}
+	solidlyV2Variant = testutil.DifferentialVariant{Name: "solidly-v2", PoolType: solidlyv2.DexType,
+		Adapt: adaptVelodromeV2}
+	equalizerVariant = testutil.DifferentialVariant{Name: "equalizer", PoolType: equalizer.DexTypeEqualizer,
+		Adapt: adaptSwapFee}
+	velocimeterVariant = testutil.DifferentialVariant{Name: "velocimeter", PoolType: velocimeter.DexTypeVelocimeter,
+		Adapt: adaptSwapFee}
+)
+
+func TestDifferential_Solidly(t *testing.T) {
+	t.Parallel()
+	t.Run("volatile", func(t *testing.T) {
+		t.Parallel()
+		testutil.TestDifferential(t, testutil.DifferentialConfig{
+			Variants: []testutil.DifferentialVariant{velodromeV1Variant, velodromeV2Variant, solidlyV2Variant,
+				equalizerVariant, velocimeterVariant},
+			Generate: generateSolidlyPool(false),
+		})
+	})
+	t.Run("stable", func(t *testing.T) {
+		t.Parallel()
+		testutil.TestDifferential(t, testutil.DifferentialConfig{
+			Variants: []testutil.DifferentialVariant{velodromeV1Variant, velodromeV2Variant, solidlyV2Variant,
+				equalizerVariant, velocimeterVariant},
+			Generate: generateSolidlyPool(true),
+		})
+	})
+}
+
+func generateUniswapV3Pool(r *rand.Rand) entity.Pool {
+	feeTiers := []struct{ fee, tickSpacing int }{{100, 1}, {500, 10}, {3000, 60}, {10000, 200}}
+	tier := feeTiers[r.IntN(len(feeTiers))]
+	type position struct {
+		lower, upper int
+		liquidity    *big.Int
+	}
+	positions := make([]position, 1+r.IntN(4))
+	for i := range positions {
+		lower := (r.IntN(400) - 200) * tier.tickSpacing
+		liquidity, _ := new(big.Float).Mul(big.NewFloat(1+r.Float64()*9),
+			new(big.Float).SetInt(bignumber.TenPowInt(uint8(15+r.IntN(9))))).Int(nil)
+		positions[i] = position{lower, lower + (1+r.IntN(200))*tier.tickSpacing, liquidity}
+	}
+	minTick := slices.MinFunc(positions, func(a, b position) int { return a.lower - b.lower }).lower
+	maxTick := slices.MaxFunc(positions, func(a, b position) int { return a.upper - b.upper }).upper
+	tick := minTick + r.IntN(maxTick-minTick)
+
+	liquidity := new(big.Int)
+	net, gross := map[int]*big.Int{}, map[int]*big.Int{}
+	for _, pos := range positions {
+		if pos.lower <= tick && tick < pos.upper {
+			liquidity.Add(liquidity, pos.liquidity)
+		}
+		for idx, sign := range map[int]int64{pos.lower: 1, pos.upper: -1} {
+			if net[idx] == nil {
+				net[idx], gross[idx] = new(big.Int), new(big.Int)
+			}
+			net[idx].Add(net[idx], new(big.Int).Mul(pos.liquidity, big.NewInt(sign)))
+			gross[idx].Add(gross[idx], pos.liquidity)
+		}
+	}
+	var ticks []uniswapv3.TickU256
+	for _, idx := range lo.Keys(net) {
+		ticks = append(ticks, uniswapv3.TickU256{Index: idx, LiquidityGross: uint256.MustFromBig(gross[idx]),
+			LiquidityNet: int256.MustFromBig(net[idx])})
+	}
+	slices.SortFunc(ticks, func(a, b uniswapv3.TickU256) int { return a.Index - b.Index })
+
+	var sqrtPriceX96 uint256.Int
+	_ = uniswapv3.GetSqrtRatioAtTick(tick, &sqrtPriceX96)
+	extra := lo.Must(json.Marshal(uniswapv3.ExtraTickU256{
+		Liquidity:    uint256.MustFromBig(liquidity),
+		SqrtPriceX96: &sqrtPriceX96,
+		TickSpacing:  uint64(tier.tickSpacing),
+		Tick:         &tick,
+		Ticks:        ticks,
+	}))
+	return entity.Pool{
+		Address:  "0x0000000000000000000000000000000000000def",
+		Exchange: "uniswapv3",
+		SwapFee:  float64(tier.fee),
+		Tokens: []*entity.PoolToken{
+			{Address: "0x0000000000000000000000000000000000000001", Decimals: 18, Swappable: true},
+			{Address: "0x0000000000000000000000000000000000000002", Decimals: 18, Swappable: true},
+		},
+		Reserves: entity.PoolReserves{"1000000000000000000000000000", "1000000000000000000000000000"},
+		Extra:    string(extra),
+	}
+}
+
+func TestDifferential_UniswapV3Forks(t *testing.T) {
+	t.Parallel()
+	testutil.TestDifferential(t, testutil.DifferentialConfig{
+		Variants: lo.Map([]string{uniswapv3.DexTypeUniswapV3, uniswapv3.DexTypePancakeV3, uniswapv3.DexTypeRamsesV2,
+			uniswapv3.DexTypeSolidlyV3, uniswapv3.DexTypeSlipstream, uniswapv3.DexTypeNuriV2},
+			func(poolType string, _ int) testutil.DifferentialVariant {
+				return testutil.DifferentialVariant{Name: poolType, PoolType: poolType}
+			}),
+		Generate: generateUniswapV3Pool,
+		AmountIn: func(r *rand.Rand, _ entity.Pool, _ int) *big.Int {
+			amountIn, _ := big.NewFloat(r.Float64()).Mul(big.NewFloat(r.Float64()), big.NewFloat(1e24)).Int(nil)
+			return amountIn.Add(amountIn, bignumber.One)
+		},
+	})
+}
+
+func TestFindCounterexample(t *testing.T) {
+	t.Parallel()
+	// a 1bps fee difference on volatile pools is found and minimized to round numbers
+	c := testutil.FindCounterexample(testutil.DifferentialConfig{
+		Variants: []testutil.DifferentialVariant{velodromeV1Variant, {
+			Name:     "velodrome-v1 +1bps",
+			PoolType: velodromev1.DexType,
+			Adapt: func(p entity.Pool) (entity.Pool, error) {
+				s, err := parseSolidlyState(p)
+				if err != nil {
+					return p, err
+				}
+				p.Extra = string(lo.Must(json.Marshal(velodromev1.PoolExtra{Fee: s.Fee + 1})))
+				return p, nil
+			},
+		}},
+		Generate: generateSolidlyPool(false),
+	})
+	require.NotNil(t, c)
+	require.Len(t, c.Outcomes, 2)
+	assert.NotEqual(t, c.Outcomes[0].AmountOut, c.Outcomes[1].AmountOut)
+	for _, amount := range append([]string{c.Case.AmountIn.String()}, c.Case.Pool.Reserves...) {
+		assert.Regexp(t, `^[1-9]0*$`, amount, c.String())
+	}
+}
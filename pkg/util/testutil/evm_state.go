@@ -0,0 +1,163 @@
+package testutil
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/evmstate"
+)
+
+// EVMStateNode serves the eth_ methods evmstate.Collect and eth.ReadStorage use from a State, with Merkle proofs of
+// it, counting the calls by method and the HTTP requests, i.e. round trips.
+type EVMStateNode struct {
+	State    *evmstate.State
+	Calls    map[string]int
+	Requests int
+	// NoProofs makes eth_getProof fail as unsupported, like on nodes without it.
+	NoProofs bool
+}
+
+// NewEVMStateNode serves state over JSON-RPC for the duration of the test, returning the node and a client of it.
+func NewEVMStateNode(t testing.TB, state *evmstate.State) (*EVMStateNode, *ethrpc.Client) {
+	t.Helper()
+	node := &EVMStateNode{State: state, Calls: map[string]int{}}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", node))
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		node.Requests++
+		server.ServeHTTP(w, r)
+	}))
+	t.Cleanup(httpServer.Close)
+	return node, ethrpc.New(httpServer.URL)
+}
+
+func (n *EVMStateNode) GetBlockByNumber(_ context.Context, _ string, _ bool) (*types.Header, error) {
+	n.Calls["eth_getBlockByNumber"]++
+	stateTrie, _ := n.tries()
+	return &types.Header{Number: new(big.Int).SetUint64(n.State.BlockNumber), Time: n.State.Time,
+		Difficulty: new(big.Int), Root: stateTrie.Hash()}, nil
+}
+
+func (n *EVMStateNode) GetProof(addr common.Address, slots []common.Hash, _ string) (map[string]any, error) {
+	n.Calls["eth_getProof"]++
+	if n.NoProofs {
+		return nil, errMethodNotFound{}
+	}
+	stateTrie, storageTries := n.tries()
+	account := n.account(addr)
+	storageTrie := storageTries[addr]
+	storageProof := make([]map[string]any, len(slots))
+	for i, slot := range slots {
+		var proof []hexutil.Bytes
+		if storageTrie != nil {
+			proof = prove(storageTrie, slot[:])
+		}
+		storageProof[i] = map[string]any{"key": slot, "value": (*hexutil.Big)(account.Storage[slot].Big()),
+			"proof": proof}
+	}
+	storageHash := types.EmptyRootHash
+	if storageTrie != nil {
+		storageHash = storageTrie.Hash()
+	}
+	balance := new(uint256.Int)
+	if account.Balance != nil {
+		balance = account.Balance
+	}
+	return map[string]any{
+		"accountProof": prove(stateTrie, addr[:]),
+		"balance":      (*hexutil.Big)(balance.ToBig()),
+		"codeHash":     crypto.Keccak256Hash(account.Code),
+		"nonce":        hexutil.Uint64(account.Nonce),
+		"storageHash":  storageHash,
+		"storageProof": storageProof,
+	}, nil
+}
+
+func (n *EVMStateNode) GetStorageAt(addr common.Address, slot common.Hash, _ string) common.Hash {
+	n.Calls["eth_getStorageAt"]++
+	return n.account(addr).Storage[slot]
+}
+
+func (n *EVMStateNode) GetCode(addr common.Address, _ string) hexutil.Bytes {
+	n.Calls["eth_getCode"]++
+	return n.account(addr).Code
+}
+
+func (n *EVMStateNode) account(addr common.Address) *evmstate.Account {
+	if account := n.State.Accounts[addr]; account != nil {
+		return account
+	}
+	return &evmstate.Account{}
+}
+
+// tries builds the state trie of State and the storage tries of its accounts with storage.
+func (n *EVMStateNode) tries() (*trie.Trie, map[common.Address]*trie.Trie) {
+	db := triedb.NewDatabase(rawdb.NewMemoryDatabase(), nil)
+	stateTrie := trie.NewEmpty(db)
+	storageTries := make(map[common.Address]*trie.Trie)
+	for addr, account := range n.State.Accounts {
+		stateAccount := types.NewEmptyStateAccount()
+		stateAccount.Nonce = account.Nonce
+		if account.Balance != nil {
+			stateAccount.Balance = account.Balance
+		}
+		stateAccount.CodeHash = crypto.Keccak256(account.Code)
+		for slot, value := range account.Storage {
+			if value == (common.Hash{}) {
+				continue
+			}
+			storageTrie := storageTries[addr]
+			if storageTrie == nil {
+				storageTrie = trie.NewEmpty(db)
+				storageTries[addr] = storageTrie
+			}
+			encoded, _ := rlp.EncodeToBytes(common.TrimLeftZeroes(value[:]))
+			_ = storageTrie.Update(crypto.Keccak256(slot[:]), encoded)
+		}
+		if storageTrie := storageTries[addr]; storageTrie != nil {
+			stateAccount.Root = storageTrie.Hash()
+		}
+		encoded, _ := rlp.EncodeToBytes(stateAccount)
+		_ = stateTrie.Update(crypto.Keccak256(addr[:]), encoded)
+	}
+	return stateTrie, storageTries
+}
+
+// prove returns the proof of the hashed key in t, root first.
+func prove(t *trie.Trie, key []byte) []hexutil.Bytes {
+	var proof proofList
+	_ = t.Prove(crypto.Keccak256(key), &proof)
+	return proof
+}
+
+type proofList []hexutil.Bytes
+
+func (l *proofList) Put(_ []byte, value []byte) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func (l *proofList) Delete([]byte) error { return nil }
+
+type errMethodNotFound struct{}
+
+func (errMethodNotFound) Error() string {
+	return "the method eth_getProof does not exist/is not available"
+}
+func (errMethodNotFound) ErrorCode() int { return -32601 }
@@ -0,0 +1,384 @@
+package testutil
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/swaplimit"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+// Invariant is a general property every pool simulator should hold.
+type Invariant string
+
+const (
+	// InvariantNoPanic: no simulator method panics.
+	InvariantNoPanic Invariant = "no-panic"
+	// InvariantMonotone: amountOut does not decrease when amountIn increases.
+	InvariantMonotone Invariant = "monotone"
+	// InvariantNoRoundTripArb: swapping the output of a swap back, after applying the swap, does not return more than
+	// the original amountIn.
+	InvariantNoRoundTripArb Invariant = "no-round-trip-arb"
+	// InvariantCloneIsolation: UpdateBalance on a CloneState result does not affect the original and vice versa.
+	InvariantCloneIsolation Invariant = "clone-isolation"
+	// InvariantWithinReserves: amountOut never exceeds the reserve of tokenOut.
+	InvariantWithinReserves Invariant = "within-reserves"
+)
+
+// Invariants lists every Invariant.
+var Invariants = []Invariant{InvariantNoPanic, InvariantMonotone, InvariantNoRoundTripArb, InvariantCloneIsolation,
+	InvariantWithinReserves}
+
+// InvariantViolation is a broken invariant of a pool simulator.
+type InvariantViolation struct {
+	PoolType  string
+	Invariant Invariant
+	TokenIn   string
+	TokenOut  string
+	AmountIn  *big.Int
+	Detail    string
+}
+
+func (v InvariantViolation) String() string {
+	return fmt.Sprintf("pool type %s: invariant %s broken swapping %v %s -> %s: %s", v.PoolType, v.Invariant,
+		v.AmountIn, v.TokenIn, v.TokenOut, v.Detail)
+}
+
+// InvariantOptions configures CheckInvariants.
+type InvariantOptions struct {
+	// Skip lists invariants not to check.
+	Skip []Invariant
+	// AmountsIn returns the increasing amounts of tokenIn to swap. Defaults to fractions of the tokenIn reserve between
+	// 1e-6 and 1e-1, or powers of 10 from 1e3 to 1e24 if the pool has no reserve for tokenIn.
+	AmountsIn func(poolSim pool.IPoolSimulator, tokenIn string) []*big.Int
+	// RoundTripTolerance is the amount by which a round trip may exceed the original amountIn, to absorb rounding.
+	RoundTripTolerance *big.Int
+}
+
+// TestInvariants reports every invariant poolSim breaks as an error of tb, with its first violation.
+func TestInvariants(tb testing.TB, poolSim pool.IPoolSimulator, opts InvariantOptions) {
+	tb.Helper()
+	violations := CheckInvariants(poolSim, opts)
+	for _, invariant := range Invariants {
+		broken := slices.DeleteFunc(slices.Clone(violations), func(v InvariantViolation) bool {
+			return v.Invariant != invariant
+		})
+		if len(broken) > 1 {
+			tb.Errorf("%s (and %d more)", broken[0], len(broken)-1)
+		} else if len(broken) == 1 {
+			tb.Error(broken[0])
+		}
+	}
+}
+
+// CheckInvariants checks the Invariants on every swappable token pair of poolSim. poolSim itself is not modified
+// unless its CloneState is unimplemented.
+func CheckInvariants(poolSim pool.IPoolSimulator, opts InvariantOptions) []InvariantViolation {
+	c := invariantChecker{poolSim: poolSim, opts: opts}
+	if c.opts.AmountsIn == nil {
+		c.opts.AmountsIn = defaultInvariantAmountsIn
+	}
+	if c.opts.RoundTripTolerance == nil {
+		c.opts.RoundTripTolerance = bignumber.ZeroBI
+	}
+
+	var tokens []string
+	c.call("", "", nil, "GetTokens", func() { tokens = poolSim.GetTokens() })
+	for _, tokenIn := range tokens {
+		var tokenOuts []string
+		c.call(tokenIn, "", nil, "CanSwapFrom", func() { tokenOuts = poolSim.CanSwapFrom(tokenIn) })
+		for _, tokenOut := range tokenOuts {
+			c.checkPair(tokenIn, tokenOut)
+		}
+	}
+	return c.violations
+}
+
+type invariantChecker struct {
+	poolSim    pool.IPoolSimulator
+	opts       InvariantOptions
+	violations []InvariantViolation
+}
+
+func (c *invariantChecker) enabled(invariant Invariant) bool {
+	return !slices.Contains(c.opts.Skip, invariant)
+}
+
+func (c *invariantChecker) violate(invariant Invariant, tokenIn, tokenOut string, amountIn *big.Int, format string,
+	args ...any) {
+	if !c.enabled(invariant) {
+		return
+	}
+	c.violations = append(c.violations, InvariantViolation{
+		PoolType:  c.poolSim.GetType(),
+		Invariant: invariant,
+		TokenIn:   tokenIn,
+		TokenOut:  tokenOut,
+		AmountIn:  amountIn,
+		Detail:    fmt.Sprintf(format, args...),
+	})
+}
+
+// call runs f, recording a no-panic violation if it panics.
+func (c *invariantChecker) call(tokenIn, tokenOut string, amountIn *big.Int, method string, f func()) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.violate(InvariantNoPanic, tokenIn, tokenOut, amountIn, "%s panicked: %v\n%s", method, r, debug.Stack())
+			ok = false
+		}
+	}()
+	f()
+	return true
+}
+
+// quote returns the amount out of a swap on poolSim, or nil if it fails or panics.
+func (c *invariantChecker) quote(poolSim pool.IPoolSimulator, tokenIn, tokenOut string,
+	amountIn *big.Int) *pool.CalcAmountOutResult {
+	var res *pool.CalcAmountOutResult
+	c.call(tokenIn, tokenOut, amountIn, "CalcAmountOut", func() {
+		var err error
+		res, err = poolSim.CalcAmountOut(pool.CalcAmountOutParams{
+			TokenAmountIn: pool.TokenAmount{Token: tokenIn, Amount: new(big.Int).Set(amountIn)},
+			TokenOut:      tokenOut,
+			Limit:         swapLimit(poolSim),
+		})
+		if err != nil || res == nil || res.TokenAmountOut == nil || res.TokenAmountOut.Amount == nil {
+			res = nil
+		}
+	})
+	return res
+}
+
+func (c *invariantChecker) apply(poolSim pool.IPoolSimulator, tokenIn string, amountIn *big.Int,
+	res *pool.CalcAmountOutResult) bool {
+	fee := pool.TokenAmount{Token: tokenIn, Amount: bignumber.ZeroBI}
+	if res.Fee != nil {
+		fee = *res.Fee
+	}
+	return c.call(tokenIn, res.TokenAmountOut.Token, amountIn, "UpdateBalance", func() {
+		poolSim.UpdateBalance(pool.UpdateBalanceParams{
+			TokenAmountIn:  pool.TokenAmount{Token: tokenIn, Amount: new(big.Int).Set(amountIn)},
+			TokenAmountOut: *res.TokenAmountOut,
+			Fee:            fee,
+			SwapInfo:       res.SwapInfo,
+			SwapLimit:      swapLimit(poolSim),
+		})
+	})
+}
+
+func (c *invariantChecker) clone(tokenIn, tokenOut string, amountIn *big.Int) pool.IPoolSimulator {
+	var cloned pool.IPoolSimulator
+	c.call(tokenIn, tokenOut, amountIn, "CloneState", func() { cloned = c.poolSim.CloneState() })
+	return cloned
+}
+
+func (c *invariantChecker) checkPair(tokenIn, tokenOut string) {
+	var amountsIn []*big.Int
+	c.call(tokenIn, tokenOut, nil, "AmountsIn", func() {
+		amountsIn = c.opts.AmountsIn(c.poolSim, tokenIn)
+	})
+
+	var reserveOut *big.Int
+	if idx := c.poolSim.GetTokenIndex(tokenOut); idx >= 0 && idx < len(c.poolSim.GetReserves()) {
+		reserveOut = c.poolSim.GetReserves()[idx]
+	}
+
+	var prevIn, prevOut *big.Int
+	for _, amountIn := range amountsIn {
+		res := c.quote(c.poolSim, tokenIn, tokenOut, amountIn)
+		if res == nil {
+			continue
+		}
+		amountOut := res.TokenAmountOut.Amount
+
+		if prevOut != nil && amountOut.Cmp(prevOut) < 0 {
+			c.violate(InvariantMonotone, tokenIn, tokenOut, amountIn, "amountOut %s < %s for smaller amountIn %s",
+				amountOut, prevOut, prevIn)
+		}
+		prevIn, prevOut = amountIn, amountOut
+
+		if reserveOut != nil && reserveOut.Sign() > 0 && amountOut.Cmp(reserveOut) > 0 {
+			c.violate(InvariantWithinReserves, tokenIn, tokenOut, amountIn, "amountOut %s > reserve %s", amountOut,
+				reserveOut)
+		}
+
+		if c.enabled(InvariantCloneIsolation) {
+			c.checkCloneIsolation(tokenIn, tokenOut, amountIn, res)
+		}
+		if c.enabled(InvariantNoRoundTripArb) {
+			c.checkRoundTrip(tokenIn, tokenOut, amountIn, res)
+		}
+	}
+}
+
+func (c *invariantChecker) checkCloneIsolation(tokenIn, tokenOut string, amountIn *big.Int,
+	res *pool.CalcAmountOutResult) {
+	cloned := c.clone(tokenIn, tokenOut, amountIn)
+	if cloned == nil {
+		return
+	}
+	if !c.apply(cloned, tokenIn, amountIn, res) {
+		return
+	}
+	if after := c.quote(c.poolSim, tokenIn, tokenOut, amountIn); after == nil ||
+		after.TokenAmountOut.Amount.Cmp(res.TokenAmountOut.Amount) != 0 {
+		c.violate(InvariantCloneIsolation, tokenIn, tokenOut, amountIn,
+			"UpdateBalance on the clone changed the original's amountOut from %s to %v", res.TokenAmountOut.Amount,
+			amountOutOf(after))
+	}
+
+	// the original must not affect a clone either: update a second clone and check the first is unchanged
+	cloned = c.clone(tokenIn, tokenOut, amountIn)
+	other := c.clone(tokenIn, tokenOut, amountIn)
+	if cloned == nil || other == nil || !c.apply(other, tokenIn, amountIn, res) {
+		return
+	}
+	if after := c.quote(cloned, tokenIn, tokenOut, amountIn); after == nil ||
+		after.TokenAmountOut.Amount.Cmp(res.TokenAmountOut.Amount) != 0 {
+		c.violate(InvariantCloneIsolation, tokenIn, tokenOut, amountIn,
+			"UpdateBalance on a clone changed another clone's amountOut from %s to %v", res.TokenAmountOut.Amount,
+			amountOutOf(after))
+	}
+}
+
+func (c *invariantChecker) checkRoundTrip(tokenIn, tokenOut string, amountIn *big.Int,
+	res *pool.CalcAmountOutResult) {
+	var canSwapBack bool
+	c.call(tokenOut, tokenIn, nil, "CanSwapFrom", func() {
+		canSwapBack = slices.Contains(c.poolSim.CanSwapFrom(tokenOut), tokenIn)
+	})
+	if !canSwapBack {
+		return
+	}
+
+	state := c.clone(tokenIn, tokenOut, amountIn)
+	if state == nil {
+		return // the swap cannot be applied without modifying the pool
+	}
+	if !c.apply(state, tokenIn, amountIn, res) {
+		return
+	}
+	back := c.quote(state, tokenOut, tokenIn, res.TokenAmountOut.Amount)
+	if back == nil {
+		return
+	}
+	if limit := new(big.Int).Add(amountIn, c.opts.RoundTripTolerance); back.TokenAmountOut.Amount.Cmp(limit) > 0 {
+		c.violate(InvariantNoRoundTripArb, tokenIn, tokenOut, amountIn,
+			"swapping %s back returns %s", res.TokenAmountOut.Amount, back.TokenAmountOut.Amount)
+	}
+}
+
+func amountOutOf(res *pool.CalcAmountOutResult) any {
+	if res == nil {
+		return "error"
+	}
+	return res.TokenAmountOut.Amount
+}
+
+// swapLimit returns a fresh inventory for pools that declare one.
+func swapLimit(poolSim pool.IPoolSimulator) pool.SwapLimit {
+	limits := poolSim.CalculateLimit()
+	if limits == nil {
+		return nil
+	}
+	return swaplimit.NewInventory(poolSim.GetExchange(), limits)
+}
+
+func defaultInvariantAmountsIn(poolSim pool.IPoolSimulator, tokenIn string) []*big.Int {
+	var reserve *big.Int
+	if idx := poolSim.GetTokenIndex(tokenIn); idx >= 0 && idx < len(poolSim.GetReserves()) {
+		reserve = poolSim.GetReserves()[idx]
+	}
+	var amounts []*big.Int
+	if reserve != nil && reserve.Cmp(big.NewInt(1e6)) >= 0 {
+		for _, div := range []int64{1e6, 1e5, 1e4, 1e3, 1e2, 10} {
+			amounts = append(amounts, new(big.Int).Div(reserve, big.NewInt(div)))
+		}
+		return amounts
+	}
+	for exp := 3; exp <= 24; exp += 3 {
+		amounts = append(amounts, bignumber.TenPowInt(exp))
+	}
+	return amounts
+}
+
+// PoolFixture is a pool to check the invariants on.
+type PoolFixture struct {
+	entity.Pool
+	// ChainID is the chain of the pool, if it is not the default one of TestAllPoolTypes.
+	ChainID valueobject.ChainID `json:"chainId,omitempty"`
+	// BasePool is the base pool of a meta pool, built with the factory of its own type.
+	BasePool *entity.Pool `json:"basePool,omitempty"`
+}
+
+// LoadPoolFixtures reads the PoolFixture JSON files of dir, keyed by pool type. Each file holds one pool.
+func LoadPoolFixtures(dir string) (map[string]PoolFixture, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	fixtures := make(map[string]PoolFixture, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var p PoolFixture
+		if err = json.Unmarshal(data, &p); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if p.Type == "" {
+			p.Type = strings.TrimSuffix(filepath.Base(file), ".json")
+		}
+		fixtures[p.Type] = p
+	}
+	return fixtures, nil
+}
+
+// TestAllPoolTypes runs TestInvariants as a subtest for every registered pool type, on a simulator built through
+// pool.Factory from its fixture, on chainID unless the fixture has its own. Pool types without a fixture are skipped;
+// skip lists known violations per pool type.
+func TestAllPoolTypes(t *testing.T, fixtures map[string]PoolFixture, chainID valueobject.ChainID,
+	skip map[string][]Invariant) {
+	t.Helper()
+	for _, poolType := range pool.PoolTypes() {
+		t.Run(poolType, func(t *testing.T) {
+			t.Parallel()
+			fixture, ok := fixtures[poolType]
+			if !ok {
+				t.Skip("no fixture")
+			}
+			poolChainID := chainID
+			if fixture.ChainID != 0 {
+				poolChainID = fixture.ChainID
+			}
+			params := pool.FactoryParams{EntityPool: fixture.Pool, ChainID: poolChainID}
+			if fixture.BasePool != nil {
+				basePool, err := pool.Factory(fixture.BasePool.Type)(pool.FactoryParams{
+					EntityPool: *fixture.BasePool,
+					ChainID:    poolChainID,
+				})
+				if err != nil {
+					t.Fatalf("pool type %s: base pool does not build: %v", poolType, err)
+				}
+				params.BasePoolMap = map[string]pool.IPoolSimulator{basePool.GetAddress(): basePool}
+			}
+			poolSim, err := pool.Factory(poolType)(params)
+			if err != nil {
+				t.Fatalf("pool type %s: fixture does not build: %v", poolType, err)
+			}
+			TestInvariants(t, poolSim, InvariantOptions{Skip: skip[poolType]})
+		})
+	}
+}
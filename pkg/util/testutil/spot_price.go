@@ -0,0 +1,40 @@
+package testutil
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+// TestSpotPrice checks that the analytic spot price of poolSim agrees with pool.NumericSpotPrice within toleranceBps on
+// every swappable token pair. Pairs the simulator cannot price analytically or at all are skipped; at least one pair must
+// be priced.
+func TestSpotPrice(tb testing.TB, poolSim pool.IPoolSimulator, toleranceBps float64) {
+	tb.Helper()
+	pricer, ok := poolSim.(pool.IPoolSpotPricer)
+	require.True(tb, ok, "%T does not implement IPoolSpotPricer", poolSim)
+
+	checked := 0
+	for _, tokenIn := range poolSim.GetTokens() {
+		for _, tokenOut := range poolSim.CanSwapFrom(tokenIn) {
+			analytic, err := pricer.GetSpotPrice(tokenIn, tokenOut)
+			if errors.Is(err, pool.ErrSpotPriceUnsupported) {
+				continue
+			}
+			require.NoError(tb, err, "%s -> %s", tokenIn, tokenOut)
+			numeric, err := pool.NumericSpotPrice(ctx, poolSim, tokenIn, tokenOut)
+			if err != nil {
+				continue
+			}
+			assert.LessOrEqual(tb, math.Abs(analytic/numeric-1)*1e4, toleranceBps,
+				"%s -> %s: analytic %v, numeric %v", tokenIn, tokenOut, analytic, numeric)
+			checked++
+		}
+	}
+	assert.Positive(tb, checked, "no token pair priced")
+}
@@ -26,6 +26,8 @@ const (
 	ExchangeBakerySwap                  = "bakeryswap"
 	ExchangeBalancerV1                  = "balancer-v1"
 	ExchangeBalancerV2ComposableStable  = "balancer-v2-composable-stable"
+	ExchangeBalancerV2FX                = "balancer-v2-fx"
+	ExchangeBalancerV2Linear            = "balancer-v2-linear"
 	ExchangeBalancerV2Stable            = "balancer-v2-stable"
 	ExchangeBalancerV2Weighted          = "balancer-v2-weighted"
 	ExchangeBalancerV3ECLP              = "balancer-v3-eclp"
@@ -112,6 +114,7 @@ const (
 	ExchangeEtherVista                  = "ether-vista"
 	ExchangeEulerSwap                   = "euler-swap"
 	ExchangeEulerSwapV2                 = "euler-swap-v2"
+	ExchangeEVMQuoter                   = "evm-quoter"
 	ExchangeFakePool                    = "fake-pool"
 	ExchangeFeltir                      = "feltir"
 	ExchangeFermi                       = "fermi"
@@ -127,6 +130,7 @@ const (
 	ExchangeFrxUSD                      = "frxusd"
 	ExchangeGenericArm                  = "generic-arm"
 	ExchangeGigaV2                      = "giga-v2"
+	ExchangeGMXV2                       = "gmx-v2"
 	ExchangeGOHM                        = "gohm"
 	ExchangeGravity                     = "gravity"
 	ExchangeGsm4626                     = "gsm-4626"
@@ -219,6 +223,7 @@ const (
 	ExchangePantherSwap                 = "pantherswap"
 	ExchangeParallelParallelizer        = "parallel-parallelizer"
 	ExchangeParityProp                  = "parity-prop"
+	ExchangePendleV2                    = "pendle-v2"
 	ExchangePharaoh2                    = "pharaoh-2"
 	ExchangePlatypus                    = "platypus"
 	ExchangePmm1                        = "pmm-1"
@@ -282,6 +287,7 @@ const (
 	ExchangeUniPool                     = "unipool"
 	ExchangeUniSwap                     = "uniswap"
 	ExchangeUniswapLO                   = "uniswap-lo"
+	ExchangeUniswapX                    = "uniswapx"
 	ExchangeUniSwapV1                   = "uniswap-v1"
 	ExchangeUniSwapV3                   = "uniswapv3"
 	ExchangeUniswapV4                   = "uniswap-v4"
@@ -367,6 +373,7 @@ var RFQSourceSet = map[Exchange]struct{}{
 	ExchangePmm14:      {},
 	ExchangeSwaapV2:    {},
 	ExchangeUniswapLO:  {},
+	ExchangeUniswapX:   {},
 }
 
 // pmmSourcePrefix matches the "pmm-N" generic maker slot family, so a newly onboarded pmm-N